@@ -0,0 +1,35 @@
+package httpapi
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/moondolphin/crypto-api/domain"
+)
+
+type CacheStatsHandler struct {
+	Quotes domain.CacheStatsReporter
+	Coins  domain.CacheStatsReporter
+}
+
+// @Summary Estadísticas del cache de lecturas
+// @Description Devuelve hits/misses/evictions/size de los caches de últimas cotizaciones y coins. Requiere token de admin.
+// @Tags Cache
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]domain.CacheStats
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/cache/stats [get]
+func (h CacheStatsHandler) Handle(c *gin.Context) {
+	if h.Quotes == nil || h.Coins == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "cache_disabled"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"latest_quotes": h.Quotes.Stats(),
+		"coins":         h.Coins.Stats(),
+	})
+}
@@ -0,0 +1,33 @@
+package httpapi
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/moondolphin/crypto-api/domain"
+)
+
+type JWKSHandler struct {
+	Keys domain.JWKSProvider
+}
+
+type JWKSResponse struct {
+	Keys []domain.PublicJWK `json:"keys"`
+}
+
+// @Summary Claves públicas de firma (JWKS)
+// @Description Publica las claves RS256/EdDSA vigentes (activa y retiradas) para verificar access tokens por kid. Con HS256 la lista viene vacía.
+// @Tags Auth
+// @Produce json
+// @Success 200 {object} httpapi.JWKSResponse
+// @Router /.well-known/jwks.json [get]
+func (h JWKSHandler) Handle(c *gin.Context) {
+	keys := h.Keys.PublicJWKs()
+	if keys == nil {
+		keys = []domain.PublicJWK{}
+	}
+
+	// las claves cambian sólo al rotar; los verificadores pueden cachear un rato
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, JWKSResponse{Keys: keys})
+}
@@ -1,25 +1,63 @@
 package httpapi
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/moondolphin/crypto-api/app"
+	"github.com/moondolphin/crypto-api/domain"
 )
 
 type AuthContext struct {
 	UserID int64
 	Email  string
+	Role   string
+
+	// datos del access token, para poder revocarlo (no se exponen en /me)
+	TokenID   string    `json:"-"`
+	IssuedAt  time.Time `json:"-"`
+	ExpiresAt time.Time `json:"-"`
+
+	// autenticado con X-API-Key: sólo puede lo que permiten sus scopes
+	APIKeyID int64    `json:"-"`
+	Scopes   []string `json:"-"`
+}
+
+// ViaAPIKey indica si el request se autenticó con X-API-Key en lugar de un JWT.
+func (a AuthContext) ViaAPIKey() bool {
+	return a.APIKeyID != 0
 }
 
-func AuthRequired(jwtSecret string) gin.HandlerFunc {
-	secret := []byte(jwtSecret)
+// AuthConfig reúne lo que necesitan AuthRequired/AuthOptional.
+type AuthConfig struct {
+	JWTSecret   string                         // HS256 sin kid; se ignora si hay KeyFunc
+	KeyFunc     jwt.Keyfunc                    // opcional: elige la clave por kid (RS256/EdDSA)
+	Issuer      string                         // opcional: exige ese iss en el token
+	Revocations domain.TokenRevocationStore    // opcional: denylist de jti
+	APIKeys     *app.AuthenticateAPIKeyUseCase // opcional: habilita X-API-Key
+}
+
+const apiKeyHeader = "X-API-Key"
+
+var errInvalidToken = errors.New("invalid_token")
+
+// AuthRequired exige un Bearer válido o, si está habilitado, una X-API-Key válida.
+func AuthRequired(cfg AuthConfig) gin.HandlerFunc {
+	keyFunc := cfg.keyFunc()
+	opts := cfg.parserOptions()
 
 	return func(c *gin.Context) {
 		h := c.GetHeader("Authorization")
 		if h == "" || !strings.HasPrefix(strings.ToLower(h), "bearer ") {
+			if cfg.APIKeys != nil && c.GetHeader(apiKeyHeader) != "" {
+				authenticateAPIKey(c, cfg.APIKeys)
+				return
+			}
 			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "missing_token"})
 			return
 		}
@@ -30,48 +68,17 @@ func AuthRequired(jwtSecret string) gin.HandlerFunc {
 			return
 		}
 
-		tok, err := jwt.Parse(tokenStr, func(t *jwt.Token) (any, error) {
-			// solo HS256
-			if t.Method != jwt.SigningMethodHS256 {
-				return nil, jwt.ErrTokenSignatureInvalid
-			}
-			return secret, nil
-		})
-		if err != nil || tok == nil || !tok.Valid {
+		auth, err := parseAccessToken(tokenStr, keyFunc, opts...)
+		if err != nil {
 			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid_token"})
 			return
 		}
 
-		claims, ok := tok.Claims.(jwt.MapClaims)
-		if !ok {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid_token"})
+		if !checkNotRevoked(c, cfg.Revocations, auth) {
 			return
 		}
 
-		// sub puede venir como float64 (json) o string
-		var uid int64
-		switch v := claims["sub"].(type) {
-		case float64:
-			uid = int64(v)
-		case string:
-			n, err := strconv.ParseInt(v, 10, 64)
-			if err != nil {
-				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid_token"})
-				return
-			}
-			uid = n
-		default:
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid_token"})
-			return
-		}
-
-		email, _ := claims["email"].(string)
-		if uid <= 0 || email == "" {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid_token"})
-			return
-		}
-
-		c.Set("auth", AuthContext{UserID: uid, Email: email})
+		c.Set("auth", auth)
 		c.Next()
 	}
 }
@@ -85,14 +92,67 @@ func MustAuth(c *gin.Context) (AuthContext, bool) {
 	return a, ok
 }
 
-func AuthOptional(jwtSecret string) gin.HandlerFunc {
-	secret := []byte(jwtSecret)
+// RequireRole se monta después de AuthRequired y corta con 403 si el rol del token
+// no está entre los permitidos.
+func RequireRole(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		auth, ok := MustAuth(c)
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+
+		for _, r := range roles {
+			if auth.Role == r {
+				c.Next()
+				return
+			}
+		}
+
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+	}
+}
+
+// RequireVerifiedEmail se monta después de AuthRequired y corta con 403 si la
+// cuenta no verificó su email y la política restringe feature. policy nil = sin restricción.
+func RequireVerifiedEmail(policy *app.EmailVerificationPolicy, feature string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if policy == nil {
+			c.Next()
+			return
+		}
+
+		auth, ok := MustAuth(c)
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+
+		switch err := policy.Check(c.Request.Context(), auth.UserID, feature); err {
+		case nil:
+			c.Next()
+		case app.ErrEmailNotVerified:
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		default:
+			c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{"error": "internal_error"})
+		}
+	}
+}
+
+// AuthOptional deja pasar requests anónimos, pero si viene un Bearer tiene que ser válido.
+func AuthOptional(cfg AuthConfig) gin.HandlerFunc {
+	keyFunc := cfg.keyFunc()
+	opts := cfg.parserOptions()
 
 	return func(c *gin.Context) {
 		h := c.GetHeader("Authorization")
 
-		// Si NO hay header o no es Bearer -> seguimos sin auth
+		// Si NO hay header o no es Bearer -> probamos X-API-Key o seguimos sin auth
 		if h == "" || !strings.HasPrefix(strings.ToLower(h), "bearer ") {
+			if cfg.APIKeys != nil && c.GetHeader(apiKeyHeader) != "" {
+				authenticateAPIKey(c, cfg.APIKeys)
+				return
+			}
 			c.Next()
 			return
 		}
@@ -104,49 +164,174 @@ func AuthOptional(jwtSecret string) gin.HandlerFunc {
 			return
 		}
 
-		tok, err := jwt.Parse(tokenStr, func(t *jwt.Token) (any, error) {
-			// solo HS256
-			if t.Method != jwt.SigningMethodHS256 {
-				return nil, jwt.ErrTokenSignatureInvalid
-			}
-			return secret, nil
-		})
-		if err != nil || tok == nil || !tok.Valid {
+		auth, err := parseAccessToken(tokenStr, keyFunc, opts...)
+		if err != nil {
 			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid_token"})
 			return
 		}
 
-		claims, ok := tok.Claims.(jwt.MapClaims)
-		if !ok {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid_token"})
+		if !checkNotRevoked(c, cfg.Revocations, auth) {
+			return
+		}
+
+		// Token válido -> seteamos auth y seguimos
+		c.Set("auth", auth)
+		c.Next()
+	}
+}
+
+// RequireScope se monta después de AuthRequired/AuthOptional. Los JWT de usuario
+// pasan siempre; una API key tiene que tener el scope. Sin auth también pasa
+// (para rutas públicas con AuthOptional).
+func RequireScope(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		auth, ok := MustAuth(c)
+		if !ok || !auth.ViaAPIKey() {
+			c.Next()
 			return
 		}
 
-		// sub puede venir como float64 (json) o string
-		var uid int64
-		switch v := claims["sub"].(type) {
-		case float64:
-			uid = int64(v)
-		case string:
-			n, err := strconv.ParseInt(v, 10, 64)
-			if err != nil {
-				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid_token"})
+		for _, s := range auth.Scopes {
+			if s == scope {
+				c.Next()
 				return
 			}
-			uid = n
-		default:
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid_token"})
+		}
+
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "insufficient_scope"})
+	}
+}
+
+// RejectAPIKeys corta con 403 los requests autenticados con X-API-Key:
+// administración y gestión de credenciales sólo con sesión de usuario.
+func RejectAPIKeys() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if auth, ok := MustAuth(c); ok && auth.ViaAPIKey() {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "api_key_not_allowed"})
 			return
 		}
+		c.Next()
+	}
+}
 
-		email, _ := claims["email"].(string)
-		if uid <= 0 || email == "" {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid_token"})
+// authenticateAPIKey valida X-API-Key y continúa la cadena o corta con 401.
+func authenticateAPIKey(c *gin.Context, uc *app.AuthenticateAPIKeyUseCase) {
+	res, err := uc.Execute(c.Request.Context(), c.GetHeader(apiKeyHeader))
+	if err != nil {
+		if err == app.ErrInvalidAPIKey {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
 			return
 		}
+		c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{"error": "internal_error"})
+		return
+	}
 
-		// Token válido -> seteamos auth y seguimos
-		c.Set("auth", AuthContext{UserID: uid, Email: email})
-		c.Next()
+	role := res.User.Role
+	if role == "" {
+		role = domain.RoleUser
+	}
+
+	c.Set("auth", AuthContext{
+		UserID:   res.User.ID,
+		Email:    res.User.Email,
+		Role:     role,
+		APIKeyID: res.Key.ID,
+		Scopes:   res.Key.Scopes,
+	})
+	c.Next()
+}
+
+// checkNotRevoked corta el request si el token está en la denylist.
+// Devuelve false si ya respondió.
+func checkNotRevoked(c *gin.Context, revocations domain.TokenRevocationStore, auth AuthContext) bool {
+	if revocations == nil {
+		return true
+	}
+
+	revoked, err := revocations.IsRevoked(c.Request.Context(), auth.TokenID, auth.UserID, auth.IssuedAt)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{"error": "internal_error"})
+		return false
+	}
+	if revoked {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "token_revoked"})
+		return false
+	}
+	return true
+}
+
+// keyFunc devuelve la Keyfunc configurada o, por compatibilidad, una que sólo
+// acepta HS256 con JWTSecret.
+func (cfg AuthConfig) keyFunc() jwt.Keyfunc {
+	if cfg.KeyFunc != nil {
+		return cfg.KeyFunc
+	}
+
+	secret := []byte(cfg.JWTSecret)
+	return func(t *jwt.Token) (any, error) {
+		// solo HS256
+		if t.Method != jwt.SigningMethodHS256 {
+			return nil, jwt.ErrTokenSignatureInvalid
+		}
+		return secret, nil
+	}
+}
+
+// parserOptions: con Issuer, un token firmado con las mismas claves pero emitido
+// por otro servicio (otro iss) no se acepta.
+func (cfg AuthConfig) parserOptions() []jwt.ParserOption {
+	if cfg.Issuer == "" {
+		return nil
+	}
+	return []jwt.ParserOption{jwt.WithIssuer(cfg.Issuer)}
+}
+
+func parseAccessToken(tokenStr string, keyFunc jwt.Keyfunc, opts ...jwt.ParserOption) (AuthContext, error) {
+	tok, err := jwt.Parse(tokenStr, keyFunc, opts...)
+	if err != nil || tok == nil || !tok.Valid {
+		return AuthContext{}, errInvalidToken
+	}
+
+	claims, ok := tok.Claims.(jwt.MapClaims)
+	if !ok {
+		return AuthContext{}, errInvalidToken
+	}
+
+	// sub puede venir como float64 (json) o string
+	var uid int64
+	switch v := claims["sub"].(type) {
+	case float64:
+		uid = int64(v)
+	case string:
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return AuthContext{}, errInvalidToken
+		}
+		uid = n
+	default:
+		return AuthContext{}, errInvalidToken
+	}
+
+	email, _ := claims["email"].(string)
+	if uid <= 0 || email == "" {
+		return AuthContext{}, errInvalidToken
+	}
+
+	// tokens previos a los roles no traen "role": se tratan como user
+	role, _ := claims["role"].(string)
+	if role == "" {
+		role = domain.RoleUser
+	}
+
+	// tokens previos a la denylist no traen jti: sólo los alcanza el corte por usuario
+	jti, _ := claims["jti"].(string)
+
+	auth := AuthContext{UserID: uid, Email: email, Role: role, TokenID: jti}
+	if iat, err := claims.GetIssuedAt(); err == nil && iat != nil {
+		auth.IssuedAt = iat.Time.UTC()
+	}
+	if exp, err := claims.GetExpirationTime(); err == nil && exp != nil {
+		auth.ExpiresAt = exp.Time.UTC()
 	}
+	return auth, nil
 }
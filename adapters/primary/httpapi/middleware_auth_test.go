@@ -0,0 +1,56 @@
+package httpapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/require"
+)
+
+func signHS256(t *testing.T, secret string, claims jwt.MapClaims) string {
+	t.Helper()
+	s, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secret))
+	require.NoError(t, err)
+	return s
+}
+
+func TestAuthRequired_RejectsTokensFromAnotherIssuer(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/me", AuthRequired(AuthConfig{JWTSecret: "secret", Issuer: "crypto-api"}), func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+
+	claims := func(iss string) jwt.MapClaims {
+		c := jwt.MapClaims{"sub": 1, "email": "a@b.com", "exp": time.Now().Add(time.Hour).Unix()}
+		if iss != "" {
+			c["iss"] = iss
+		}
+		return c
+	}
+
+	cases := map[string]struct {
+		token string
+		want  int
+	}{
+		"own issuer":     {token: signHS256(t, "secret", claims("crypto-api")), want: http.StatusNoContent},
+		"foreign issuer": {token: signHS256(t, "secret", claims("other-service")), want: http.StatusUnauthorized},
+		"missing issuer": {token: signHS256(t, "secret", claims("")), want: http.StatusUnauthorized},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/me", nil)
+			req.Header.Set("Authorization", "Bearer "+tc.token)
+			w := httptest.NewRecorder()
+
+			r.ServeHTTP(w, req)
+
+			require.Equal(t, tc.want, w.Code)
+		})
+	}
+}
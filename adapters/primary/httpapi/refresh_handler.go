@@ -12,12 +12,13 @@ type RefreshHandler struct {
 }
 
 // @Summary Refresh quotes (Manual, with cooldown)
-// @Description Refresh manual: consulta providers y persiste en BD. Requiere JWT. Enforce cooldown (ej 20 min).
+// @Description Refresh manual: consulta providers y persiste en BD. Requiere JWT de admin. Enforce cooldown (ej 20 min).
 // @Tags Job
 // @Produce json
 // @Security BearerAuth
 // @Success 200 {object} app.ManualRefreshWithCooldownOutput
 // @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
 // @Failure 429 {object} map[string]any
 // @Failure 503 {object} map[string]string
 // @Router /api/v1/job/refresh [post]
@@ -23,8 +23,10 @@ type GetCurrentPriceHandler struct {
 // @Param provider query string false "Proveedor (binance, coingecko) - opcional"
 // @Success 200 {object} domain.PriceQuote
 // @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
 // @Failure 404 {object} map[string]string
 // @Failure 503 {object} map[string]string
+// @Security ApiKeyAuth
 // @Router /api/v1/crypto/price [get]
 func (h GetCurrentPriceHandler) Handle(c *gin.Context) {
 	in := app.GetLastPriceInput{
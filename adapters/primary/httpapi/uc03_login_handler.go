@@ -1,7 +1,9 @@
 package httpapi
 
 import (
+	"math"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/moondolphin/crypto-api/app"
@@ -12,7 +14,7 @@ type LoginHandler struct {
 }
 
 // @Summary Login
-// @Description Autentica usuario y devuelve token JWT
+// @Description Autentica usuario y devuelve token JWT. Si el usuario tiene 2FA activo devuelve en cambio {two_factor_required, challenge_token, expires_at}: el challenge se canjea en /api/v1/auth/login/2fa. Tras varios intentos fallidos la cuenta o la IP quedan bloqueadas temporalmente (429 con Retry-After).
 // @Tags Auth
 // @Accept json
 // @Produce json
@@ -20,6 +22,7 @@ type LoginHandler struct {
 // @Success 200 {object} app.LoginOutput
 // @Failure 400 {object} map[string]string
 // @Failure 401 {object} map[string]string
+// @Failure 429 {object} map[string]string
 // @Router /api/v1/auth/login [post]
 func (h LoginHandler) Handle(c *gin.Context) {
 	var in app.LoginInput
@@ -28,6 +31,8 @@ func (h LoginHandler) Handle(c *gin.Context) {
 		return
 	}
 
+	in.ClientIP = c.ClientIP()
+
 	out, err := h.UC.Execute(c.Request.Context(), in)
 	if err != nil {
 		switch err {
@@ -35,11 +40,19 @@ func (h LoginHandler) Handle(c *gin.Context) {
 			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		case app.ErrInvalidCredentials:
 			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		case app.ErrTooManyLoginAttempts:
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(out.RetryAfter.Seconds()))))
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
 		default:
 			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "internal_error"})
 		}
 		return
 	}
 
+	if out.Challenge != nil {
+		c.JSON(http.StatusOK, out.Challenge)
+		return
+	}
+
 	c.JSON(http.StatusOK, out)
 }
@@ -25,7 +25,9 @@ type GetQuoteFiltersHandler struct {
 // @Param to   query string false "Hasta. Formatos: 'YYYY-MM-DD' o 'YYYY-MM-DDTHH:MM:SSZ'"
 // @Success 200 {object} app.GetQuoteFiltersOutput
 // @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
 // @Failure 503 {object} map[string]string
+// @Security ApiKeyAuth
 // @Router /api/v1/quotes/filters [get]
 func (h GetQuoteFiltersHandler) Handle(c *gin.Context) {
 	in := app.SearchQuotesInput{
@@ -29,7 +29,9 @@ type SearchQuotesHandler struct {
 // @Param page_size query int false "Tamaño (1..100)"
 // @Success 200 {object} app.SearchQuotesOutput
 // @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
 // @Failure 503 {object} map[string]string
+// @Security ApiKeyAuth
 // @Router /api/v1/quotes [get]
 func (h SearchQuotesHandler) Handle(c *gin.Context) {
 	in := app.SearchQuotesInput{
@@ -12,7 +12,7 @@ type CreateCoinHandler struct {
 }
 
 // @Summary Alta de moneda de interés
-// @Description Crea o actualiza una moneda (upsert). Requiere token.
+// @Description Crea o actualiza una moneda (upsert). Requiere token de admin.
 // @Tags Coins
 // @Accept json
 // @Produce json
@@ -21,6 +21,7 @@ type CreateCoinHandler struct {
 // @Success 200 {object} app.CreateCoinOutput
 // @Failure 400 {object} map[string]string
 // @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
 // @Failure 503 {object} map[string]string
 // @Router /api/v1/coins [post]
 func (h CreateCoinHandler) Handle(c *gin.Context) {
@@ -13,7 +13,7 @@ type UpdateCoinHandler struct {
 }
 
 // @Summary Actualizar moneda de interés
-// @Description Permite habilitar/deshabilitar una moneda o actualizar IDs de proveedores. Requiere token.
+// @Description Permite habilitar/deshabilitar una moneda o actualizar IDs de proveedores. Requiere token de admin.
 // @Tags Coins
 // @Accept json
 // @Produce json
@@ -23,6 +23,7 @@ type UpdateCoinHandler struct {
 // @Success 200 {object} domain.Coin
 // @Failure 400 {object} map[string]string
 // @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
 // @Failure 404 {object} map[string]string
 // @Failure 503 {object} map[string]string
 // @Router /api/v1/coins/{symbol} [put]
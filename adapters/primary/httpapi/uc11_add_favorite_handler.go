@@ -19,10 +19,12 @@ type AddFavoriteHandler struct {
 // @Accept json
 // @Produce json
 // @Security BearerAuth
+// @Security ApiKeyAuth
 // @Param symbol path string true "Symbol (BTC, ETH, ...)"
 // @Success 200 {object} map[string]any
 // @Failure 400 {object} map[string]string
 // @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
 // @Failure 404 {object} map[string]string
 // @Failure 503 {object} map[string]string
 // @Router /api/v1/users/me/favorites/{symbol} [post]
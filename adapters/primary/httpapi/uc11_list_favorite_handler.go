@@ -2,24 +2,29 @@ package httpapi
 
 import (
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/moondolphin/crypto-api/app"
-	"github.com/moondolphin/crypto-api/domain"
 )
 
 type ListFavoritesHandler struct {
-	FavRepo domain.FavoritesRepository
+	UC app.ListFavoritesUseCase
 }
 
 // @Summary Listar monedas favoritas del usuario
-// @Description Devuelve las coins favoritas del usuario autenticado.
+// @Description Devuelve las coins favoritas del usuario autenticado: las de su watchlist por defecto, por símbolo.
+// @Description Con include_quotes=true agrega la última cotización de cada provider/moneda configurado y la variación en 24h.
 // @Tags Favorites
 // @Accept json
 // @Produce json
 // @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param include_quotes query bool false "Incluir últimas cotizaciones y variación 24h"
 // @Success 200 {array} app.FavoriteCoinOutput
+// @Failure 400 {object} map[string]string
 // @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
 // @Failure 503 {object} map[string]string
 // @Router /api/v1/users/me/favorites [get]
 func (h ListFavoritesHandler) Handle(c *gin.Context) {
@@ -29,22 +34,21 @@ func (h ListFavoritesHandler) Handle(c *gin.Context) {
 		return
 	}
 
-	coins, err := h.FavRepo.ListFavoriteCoinIDsByUser(c.Request.Context(), auth.UserID)
+	var in app.ListFavoritesInput
+	if raw := c.Query("include_quotes"); raw != "" {
+		v, err := strconv.ParseBool(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "bad_request"})
+			return
+		}
+		in.IncludeQuotes = v
+	}
+
+	out, err := h.UC.Execute(c.Request.Context(), auth.UserID, in)
 	if err != nil {
 		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "internal_error"})
 		return
 	}
 
-	out := make([]app.FavoriteCoinOutput, 0, len(coins))
-	for _, coin := range coins {
-		out = append(out, app.FavoriteCoinOutput{
-			ID:            coin.ID,
-			Symbol:        coin.Symbol,
-			Enabled:       coin.Enabled,
-			CoinGeckoID:   coin.CoinGeckoID,
-			BinanceSymbol: coin.BinanceSymbol,
-		})
-	}
-
 	c.JSON(http.StatusOK, out)
 }
@@ -0,0 +1,45 @@
+package httpapi
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/moondolphin/crypto-api/app"
+)
+
+type RefreshTokenHandler struct {
+	UC app.RefreshTokenUseCase
+}
+
+// @Summary Refresh token
+// @Description Canjea un refresh token por un access token nuevo y rota el refresh token. Reusar un token ya rotado revoca la sesión completa.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param body body app.RefreshTokenInput true "Refresh payload"
+// @Success 200 {object} app.LoginOutput
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Router /api/v1/auth/refresh [post]
+func (h RefreshTokenHandler) Handle(c *gin.Context) {
+	var in app.RefreshTokenInput
+	if err := c.ShouldBindJSON(&in); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "bad_request"})
+		return
+	}
+
+	out, err := h.UC.Execute(c.Request.Context(), in)
+	if err != nil {
+		switch err {
+		case app.ErrBadRequest:
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case app.ErrInvalidRefreshToken, app.ErrRefreshTokenReused:
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "internal_error"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, out)
+}
@@ -0,0 +1,48 @@
+package httpapi
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/moondolphin/crypto-api/app"
+)
+
+type LogoutHandler struct {
+	UC app.LogoutUseCase
+}
+
+// @Summary Logout
+// @Description Revoca el refresh token y todos los emitidos por rotación desde el mismo login. Si viene un Bearer válido, también lo revoca.
+// @Tags Auth
+// @Accept json
+// @Security BearerAuth
+// @Param body body app.LogoutInput true "Logout payload"
+// @Success 204
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Router /api/v1/auth/logout [post]
+func (h LogoutHandler) Handle(c *gin.Context) {
+	var in app.LogoutInput
+	if err := c.ShouldBindJSON(&in); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "bad_request"})
+		return
+	}
+
+	// montado con AuthOptional: si vino access token, se revoca también
+	if auth, ok := MustAuth(c); ok {
+		in.AccessTokenID = auth.TokenID
+		in.AccessExpiresAt = auth.ExpiresAt
+	}
+
+	if err := h.UC.Execute(c.Request.Context(), in); err != nil {
+		switch err {
+		case app.ErrBadRequest:
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "internal_error"})
+		}
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,48 @@
+package httpapi
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/moondolphin/crypto-api/app"
+)
+
+type RevokeUserTokensHandler struct {
+	UC app.RevokeUserTokensUseCase
+}
+
+// @Summary Revocar tokens de un usuario
+// @Description Invalida todos los access tokens emitidos hasta ahora, los refresh tokens y las API keys del usuario. Requiere JWT de admin.
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Success 204
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Router /api/v1/admin/users/{id}/revoke-tokens [post]
+func (h RevokeUserTokensHandler) Handle(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "bad_request"})
+		return
+	}
+
+	if err := h.UC.Execute(c.Request.Context(), id); err != nil {
+		switch err {
+		case app.ErrBadRequest:
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case app.ErrUserNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "internal_error"})
+		}
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,52 @@
+package httpapi
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/moondolphin/crypto-api/app"
+)
+
+type CreateAPIKeyHandler struct {
+	UC app.CreateAPIKeyUseCase
+}
+
+// @Summary Crear API key
+// @Description Crea una API key del usuario autenticado para clientes máquina a máquina. La key en claro sólo se devuelve en esta respuesta; se usa en el header X-API-Key.
+// @Tags APIKeys
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param body body app.CreateAPIKeyInput true "name, scopes (quotes:read, favorites:read, favorites:write, alerts:read, alerts:write, portfolios:read, portfolios:write) y expires_at opcional"
+// @Success 201 {object} app.CreatedAPIKeyOutput
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Router /api/v1/users/me/api-keys [post]
+func (h CreateAPIKeyHandler) Handle(c *gin.Context) {
+	auth, ok := MustAuth(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	var in app.CreateAPIKeyInput
+	if err := c.ShouldBindJSON(&in); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "bad_request"})
+		return
+	}
+
+	out, err := h.UC.Execute(c.Request.Context(), auth.UserID, in)
+	if err != nil {
+		switch err {
+		case app.ErrInvalidAPIKeyName, app.ErrInvalidScopes, app.ErrInvalidExpiry:
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "internal_error"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, out)
+}
@@ -0,0 +1,38 @@
+package httpapi
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/moondolphin/crypto-api/app"
+)
+
+type ListAPIKeysHandler struct {
+	UC app.ListAPIKeysUseCase
+}
+
+// @Summary Listar API keys
+// @Description Devuelve las API keys activas del usuario autenticado (sin la key en claro).
+// @Tags APIKeys
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} app.APIKeyOutput
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Router /api/v1/users/me/api-keys [get]
+func (h ListAPIKeysHandler) Handle(c *gin.Context) {
+	auth, ok := MustAuth(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	out, err := h.UC.Execute(c.Request.Context(), auth.UserID)
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "internal_error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, out)
+}
@@ -0,0 +1,54 @@
+package httpapi
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/moondolphin/crypto-api/app"
+)
+
+type RevokeAPIKeyHandler struct {
+	UC app.RevokeAPIKeyUseCase
+}
+
+// @Summary Revocar API key
+// @Description Revoca una API key del usuario autenticado.
+// @Tags APIKeys
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "API key ID"
+// @Success 204
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Router /api/v1/users/me/api-keys/{id} [delete]
+func (h RevokeAPIKeyHandler) Handle(c *gin.Context) {
+	auth, ok := MustAuth(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "bad_request"})
+		return
+	}
+
+	if err := h.UC.Execute(c.Request.Context(), auth.UserID, id); err != nil {
+		switch err {
+		case app.ErrBadRequest:
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case app.ErrAPIKeyNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "internal_error"})
+		}
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
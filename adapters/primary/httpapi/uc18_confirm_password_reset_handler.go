@@ -0,0 +1,40 @@
+package httpapi
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/moondolphin/crypto-api/app"
+)
+
+type ConfirmPasswordResetHandler struct {
+	UC app.ConfirmPasswordResetUseCase
+}
+
+// @Summary Confirmar reset de contraseña
+// @Description Canjea el token recibido por mail por una contraseña nueva. El token sirve una sola vez; se cierran todas las sesiones abiertas y se revocan las API keys.
+// @Tags Auth
+// @Accept json
+// @Param body body app.ConfirmPasswordResetInput true "Token y contraseña nueva"
+// @Success 204
+// @Failure 400 {object} map[string]string
+// @Router /api/v1/auth/password/reset [post]
+func (h ConfirmPasswordResetHandler) Handle(c *gin.Context) {
+	var in app.ConfirmPasswordResetInput
+	if err := c.ShouldBindJSON(&in); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "bad_request"})
+		return
+	}
+
+	if err := h.UC.Execute(c.Request.Context(), in); err != nil {
+		switch err {
+		case app.ErrBadRequest, app.ErrInvalidPassword, app.ErrInvalidResetToken:
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "internal_error"})
+		}
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
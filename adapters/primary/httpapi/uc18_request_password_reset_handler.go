@@ -0,0 +1,49 @@
+package httpapi
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/moondolphin/crypto-api/app"
+)
+
+type RequestPasswordResetHandler struct {
+	UC app.RequestPasswordResetUseCase
+}
+
+// @Summary Pedir reset de contraseña
+// @Description Envía por mail un link de un solo uso para elegir una contraseña nueva. Responde 202 exista o no el email, para no revelar cuentas registradas. Limitado por email y por IP: al superar el límite responde 429 con Retry-After.
+// @Tags Auth
+// @Accept json
+// @Param body body app.RequestPasswordResetInput true "Email de la cuenta"
+// @Success 202
+// @Failure 400 {object} map[string]string
+// @Failure 429 {object} map[string]string
+// @Router /api/v1/auth/password/forgot [post]
+func (h RequestPasswordResetHandler) Handle(c *gin.Context) {
+	var in app.RequestPasswordResetInput
+	if err := c.ShouldBindJSON(&in); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "bad_request"})
+		return
+	}
+
+	in.ClientIP = c.ClientIP()
+
+	retryAfter, err := h.UC.Execute(c.Request.Context(), in)
+	if err != nil {
+		switch err {
+		case app.ErrInvalidEmail:
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case app.ErrTooManyResetRequests:
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "internal_error"})
+		}
+		return
+	}
+
+	c.Status(http.StatusAccepted)
+}
@@ -0,0 +1,49 @@
+package httpapi
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/moondolphin/crypto-api/app"
+)
+
+type ResendVerificationHandler struct {
+	UC app.ResendEmailVerificationUseCase
+}
+
+// @Summary Reenviar mail de verificación
+// @Description Manda un link de verificación nuevo (invalida los anteriores). Limitado a uno por período: si es muy pronto responde 429 con Retry-After.
+// @Tags Auth
+// @Security BearerAuth
+// @Success 202
+// @Failure 401 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 429 {object} map[string]string
+// @Router /api/v1/auth/verify-email/resend [post]
+func (h ResendVerificationHandler) Handle(c *gin.Context) {
+	auth, ok := MustAuth(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	retryAfter, err := h.UC.Execute(c.Request.Context(), auth.UserID)
+	if err != nil {
+		switch err {
+		case app.ErrUserNotFound:
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		case app.ErrEmailAlreadyVerified:
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		case app.ErrVerificationResendTooSoon:
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "internal_error"})
+		}
+		return
+	}
+
+	c.Status(http.StatusAccepted)
+}
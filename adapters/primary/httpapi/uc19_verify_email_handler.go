@@ -0,0 +1,44 @@
+package httpapi
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/moondolphin/crypto-api/app"
+)
+
+type VerifyEmailHandler struct {
+	UC app.VerifyEmailUseCase
+}
+
+// @Summary Verificar email
+// @Description Confirma la dirección de email con el token recibido por mail. Acepta GET con ?token= (link del mail) o POST con JSON.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param token query string false "Token de verificación (GET)"
+// @Param body body app.VerifyEmailInput false "Token de verificación (POST)"
+// @Success 200 {object} map[string]bool
+// @Failure 400 {object} map[string]string
+// @Router /api/v1/auth/verify-email [get]
+// @Router /api/v1/auth/verify-email [post]
+func (h VerifyEmailHandler) Handle(c *gin.Context) {
+	// GET bindea la query; POST, el body según Content-Type
+	var in app.VerifyEmailInput
+	if err := c.ShouldBind(&in); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "bad_request"})
+		return
+	}
+
+	if err := h.UC.Execute(c.Request.Context(), in); err != nil {
+		switch err {
+		case app.ErrBadRequest, app.ErrInvalidVerificationToken:
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "internal_error"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"email_verified": true})
+}
@@ -0,0 +1,48 @@
+package httpapi
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/moondolphin/crypto-api/app"
+)
+
+type UnlockUserLoginHandler struct {
+	UC app.UnlockUserLoginUseCase
+}
+
+// @Summary Desbloquear login de un usuario
+// @Description Borra los intentos fallidos y el bloqueo temporal de login de la cuenta. No afecta los bloqueos por IP. Requiere JWT de admin.
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Success 204
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Router /api/v1/admin/users/{id}/unlock [post]
+func (h UnlockUserLoginHandler) Handle(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "bad_request"})
+		return
+	}
+
+	if err := h.UC.Execute(c.Request.Context(), id); err != nil {
+		switch err {
+		case app.ErrBadRequest:
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case app.ErrUserNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "internal_error"})
+		}
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
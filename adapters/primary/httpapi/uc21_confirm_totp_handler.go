@@ -0,0 +1,54 @@
+package httpapi
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/moondolphin/crypto-api/app"
+)
+
+type ConfirmTOTPHandler struct {
+	UC app.ConfirmTOTPUseCase
+}
+
+// @Summary Confirmar enrolamiento TOTP
+// @Description Activa el 2FA pendiente con un código de la app autenticadora. Devuelve los códigos de recuperación: sólo se muestran esta vez.
+// @Tags TwoFactor
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param body body app.TwoFactorCodeInput true "code"
+// @Success 200 {object} app.RecoveryCodesOutput
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Router /api/v1/users/me/2fa/confirm [post]
+func (h ConfirmTOTPHandler) Handle(c *gin.Context) {
+	auth, ok := MustAuth(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	var in app.TwoFactorCodeInput
+	if err := c.ShouldBindJSON(&in); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "bad_request"})
+		return
+	}
+
+	out, err := h.UC.Execute(c.Request.Context(), auth.UserID, in)
+	if err != nil {
+		switch err {
+		case app.ErrBadRequest, app.ErrInvalidTwoFactorCode:
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case app.ErrTwoFactorNotEnabled, app.ErrTwoFactorAlreadyEnabled:
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "internal_error"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, out)
+}
@@ -0,0 +1,60 @@
+package httpapi
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/moondolphin/crypto-api/app"
+)
+
+type DisableTOTPHandler struct {
+	UC app.DisableTOTPUseCase
+}
+
+// @Summary Desactivar 2FA
+// @Description Desactiva el 2FA y borra los códigos de recuperación. Pide la contraseña y un código TOTP o de recuperación.
+// @Tags TwoFactor
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param body body app.DisableTwoFactorInput true "password y code"
+// @Success 204
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Router /api/v1/users/me/2fa/disable [post]
+func (h DisableTOTPHandler) Handle(c *gin.Context) {
+	auth, ok := MustAuth(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	var in app.DisableTwoFactorInput
+	if err := c.ShouldBindJSON(&in); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "bad_request"})
+		return
+	}
+
+	if err := h.UC.Execute(c.Request.Context(), auth.UserID, in); err != nil {
+		switch err {
+		case app.ErrBadRequest, app.ErrInvalidTwoFactorCode:
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case app.ErrInvalidCredentials:
+			// 403 y no 401: la sesión es válida, lo incorrecto es la contraseña
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		case app.ErrUserNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case app.ErrTwoFactorNotEnabled:
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "internal_error"})
+		}
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
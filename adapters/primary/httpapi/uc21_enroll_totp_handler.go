@@ -0,0 +1,46 @@
+package httpapi
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/moondolphin/crypto-api/app"
+)
+
+type EnrollTOTPHandler struct {
+	UC app.EnrollTOTPUseCase
+}
+
+// @Summary Iniciar enrolamiento TOTP
+// @Description Genera un secreto TOTP y su URI otpauth:// (para mostrar como QR). El 2FA no queda activo hasta confirmarlo con un código en /api/v1/users/me/2fa/confirm.
+// @Tags TwoFactor
+// @Produce json
+// @Security BearerAuth
+// @Success 201 {object} app.TOTPEnrollmentOutput
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Router /api/v1/users/me/2fa/enroll [post]
+func (h EnrollTOTPHandler) Handle(c *gin.Context) {
+	auth, ok := MustAuth(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	out, err := h.UC.Execute(c.Request.Context(), auth.UserID)
+	if err != nil {
+		switch err {
+		case app.ErrUserNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case app.ErrTwoFactorAlreadyEnabled:
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "internal_error"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, out)
+}
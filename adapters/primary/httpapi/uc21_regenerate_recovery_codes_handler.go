@@ -0,0 +1,54 @@
+package httpapi
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/moondolphin/crypto-api/app"
+)
+
+type RegenerateRecoveryCodesHandler struct {
+	UC app.RegenerateRecoveryCodesUseCase
+}
+
+// @Summary Regenerar códigos de recuperación
+// @Description Invalida los códigos de recuperación actuales y emite otros. Pide un código TOTP vigente.
+// @Tags TwoFactor
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param body body app.TwoFactorCodeInput true "code"
+// @Success 200 {object} app.RecoveryCodesOutput
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Router /api/v1/users/me/2fa/recovery-codes [post]
+func (h RegenerateRecoveryCodesHandler) Handle(c *gin.Context) {
+	auth, ok := MustAuth(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	var in app.TwoFactorCodeInput
+	if err := c.ShouldBindJSON(&in); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "bad_request"})
+		return
+	}
+
+	out, err := h.UC.Execute(c.Request.Context(), auth.UserID, in)
+	if err != nil {
+		switch err {
+		case app.ErrBadRequest, app.ErrInvalidTwoFactorCode:
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case app.ErrTwoFactorNotEnabled:
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "internal_error"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, out)
+}
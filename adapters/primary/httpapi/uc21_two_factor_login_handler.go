@@ -0,0 +1,54 @@
+package httpapi
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/moondolphin/crypto-api/app"
+)
+
+type TwoFactorLoginHandler struct {
+	UC app.TwoFactorLoginUseCase
+}
+
+// @Summary Login con segundo factor
+// @Description Canjea el challenge_token devuelto por /api/v1/auth/login y un código TOTP (o de recuperación) por los tokens de sesión. El challenge sirve para un solo intento: con un código incorrecto hay que volver a /api/v1/auth/login. Los códigos incorrectos cuentan como intentos fallidos de login.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param body body app.TwoFactorLoginInput true "challenge_token y code"
+// @Success 200 {object} app.LoginOutput
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 429 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Router /api/v1/auth/login/2fa [post]
+func (h TwoFactorLoginHandler) Handle(c *gin.Context) {
+	var in app.TwoFactorLoginInput
+	if err := c.ShouldBindJSON(&in); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "bad_request"})
+		return
+	}
+
+	in.ClientIP = c.ClientIP()
+
+	out, err := h.UC.Execute(c.Request.Context(), in)
+	if err != nil {
+		switch err {
+		case app.ErrBadRequest:
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case app.ErrInvalidLoginChallenge, app.ErrInvalidTwoFactorCode:
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		case app.ErrTooManyLoginAttempts:
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(out.RetryAfter.Seconds()))))
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "internal_error"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, out)
+}
@@ -0,0 +1,37 @@
+package httpapi
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/moondolphin/crypto-api/app"
+)
+
+type TwoFactorStatusHandler struct {
+	UC app.GetTwoFactorStatusUseCase
+}
+
+// @Summary Estado del 2FA
+// @Description Indica si el usuario autenticado tiene 2FA activo (o pendiente de confirmar) y cuántos códigos de recuperación le quedan.
+// @Tags TwoFactor
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} app.TwoFactorStatusOutput
+// @Failure 401 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Router /api/v1/users/me/2fa [get]
+func (h TwoFactorStatusHandler) Handle(c *gin.Context) {
+	auth, ok := MustAuth(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	out, err := h.UC.Execute(c.Request.Context(), auth.UserID)
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "internal_error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, out)
+}
@@ -0,0 +1,57 @@
+package httpapi
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/moondolphin/crypto-api/app"
+)
+
+type ChangePasswordHandler struct {
+	UC app.ChangePasswordUseCase
+}
+
+// @Summary Cambiar contraseña
+// @Description Cambia la contraseña verificando la actual. Cierra todas las sesiones abiertas, incluida la actual, y revoca las API keys.
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param body body app.ChangePasswordInput true "current_password y new_password"
+// @Success 204
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Router /api/v1/users/me/password [post]
+func (h ChangePasswordHandler) Handle(c *gin.Context) {
+	auth, ok := MustAuth(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	var in app.ChangePasswordInput
+	if err := c.ShouldBindJSON(&in); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "bad_request"})
+		return
+	}
+
+	if err := h.UC.Execute(c.Request.Context(), auth.UserID, in); err != nil {
+		switch err {
+		case app.ErrBadRequest, app.ErrInvalidPassword:
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case app.ErrInvalidCredentials:
+			// 403 y no 401: la sesión es válida, lo incorrecto es la contraseña
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		case app.ErrUserNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "internal_error"})
+		}
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,58 @@
+package httpapi
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/moondolphin/crypto-api/app"
+)
+
+type DeleteAccountHandler struct {
+	UC app.DeleteAccountUseCase
+}
+
+// @Summary Borrar cuenta
+// @Description Borra la cuenta del usuario autenticado junto con sus favoritos, API keys, sesiones y 2FA. Pide la contraseña; una cuenta con login OIDC (que no conoce su contraseña) puede omitirla si el access token se emitió hace menos de 5 minutos (si no, 403 recent_login_required: volver a entrar y reintentar).
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param body body app.DeleteAccountInput true "password"
+// @Success 204
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Router /api/v1/users/me [delete]
+func (h DeleteAccountHandler) Handle(c *gin.Context) {
+	auth, ok := MustAuth(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	var in app.DeleteAccountInput
+	if err := c.ShouldBindJSON(&in); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "bad_request"})
+		return
+	}
+
+	in.SessionIssuedAt = auth.IssuedAt
+
+	if err := h.UC.Execute(c.Request.Context(), auth.UserID, in); err != nil {
+		switch err {
+		case app.ErrBadRequest:
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case app.ErrInvalidCredentials, app.ErrRecentLoginRequired:
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		case app.ErrUserNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "internal_error"})
+		}
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
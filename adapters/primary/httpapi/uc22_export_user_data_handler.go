@@ -0,0 +1,44 @@
+package httpapi
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/moondolphin/crypto-api/app"
+)
+
+type ExportUserDataHandler struct {
+	UC app.ExportUserDataUseCase
+}
+
+// @Summary Exportar datos del usuario
+// @Description Descarga en JSON todo lo que se guarda del usuario autenticado: perfil, favoritos, API keys, sesiones activas y estado del 2FA. No incluye hashes ni secretos.
+// @Tags Users
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} app.UserDataExport
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Router /api/v1/users/me/export [get]
+func (h ExportUserDataHandler) Handle(c *gin.Context) {
+	auth, ok := MustAuth(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	out, err := h.UC.Execute(c.Request.Context(), auth.UserID)
+	if err != nil {
+		switch err {
+		case app.ErrUserNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "internal_error"})
+		}
+		return
+	}
+
+	c.Header("Content-Disposition", `attachment; filename="user-data.json"`)
+	c.JSON(http.StatusOK, out)
+}
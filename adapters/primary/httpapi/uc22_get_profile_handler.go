@@ -0,0 +1,43 @@
+package httpapi
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/moondolphin/crypto-api/app"
+)
+
+type GetProfileHandler struct {
+	UC app.GetProfileUseCase
+}
+
+// @Summary Perfil del usuario
+// @Description Devuelve el perfil del usuario autenticado leído de la base (no de los claims del JWT).
+// @Tags Users
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} app.UserOutput
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Router /api/v1/users/me [get]
+func (h GetProfileHandler) Handle(c *gin.Context) {
+	auth, ok := MustAuth(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	out, err := h.UC.Execute(c.Request.Context(), auth.UserID)
+	if err != nil {
+		switch err {
+		case app.ErrUserNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "internal_error"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, out)
+}
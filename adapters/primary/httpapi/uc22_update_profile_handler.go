@@ -0,0 +1,60 @@
+package httpapi
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/moondolphin/crypto-api/app"
+)
+
+type UpdateProfileHandler struct {
+	UC app.UpdateProfileUseCase
+}
+
+// @Summary Editar perfil
+// @Description Cambia el nombre y/o el email. Cambiar el email pide current_password y, si la verificación está activa, el email nuevo queda sin verificar.
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param body body app.UpdateProfileInput true "name, email, current_password"
+// @Success 200 {object} app.UserOutput
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Router /api/v1/users/me [patch]
+func (h UpdateProfileHandler) Handle(c *gin.Context) {
+	auth, ok := MustAuth(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	var in app.UpdateProfileInput
+	if err := c.ShouldBindJSON(&in); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "bad_request"})
+		return
+	}
+
+	out, err := h.UC.Execute(c.Request.Context(), auth.UserID, in)
+	if err != nil {
+		switch err {
+		case app.ErrBadRequest, app.ErrInvalidName, app.ErrInvalidEmail, app.ErrCurrentPasswordRequired:
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case app.ErrInvalidCredentials:
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		case app.ErrUserNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case app.ErrEmailAlreadyRegistered:
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "internal_error"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, out)
+}
@@ -0,0 +1,57 @@
+package httpapi
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/moondolphin/crypto-api/app"
+)
+
+type OIDCCallbackHandler struct {
+	UC app.OIDCCallbackUseCase
+}
+
+// @Summary Callback de OIDC
+// @Description Recibe el code del proveedor, valida el ID token y devuelve la sesión como /api/v1/auth/login (o el challenge si el usuario tiene 2FA). La primera vez vincula la identidad al usuario local con el mismo email, si ya lo verificó (409 oidc_account_unverified si no), o crea la cuenta.
+// @Tags Auth
+// @Produce json
+// @Param code query string true "authorization code"
+// @Param state query string true "state devuelto por el proveedor"
+// @Success 200 {object} app.LoginOutput
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Router /api/v1/auth/oidc/callback [get]
+func (h OIDCCallbackHandler) Handle(c *gin.Context) {
+	var in app.OIDCCallbackInput
+	if err := c.ShouldBindQuery(&in); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "bad_request"})
+		return
+	}
+
+	out, err := h.UC.Execute(c.Request.Context(), in)
+	if err != nil {
+		switch err {
+		case app.ErrBadRequest:
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case app.ErrInvalidOIDCState, app.ErrOIDCLoginFailed:
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		case app.ErrOIDCEmailNotVerified, app.ErrOIDCAccountNotFound:
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		case app.ErrOIDCAccountUnverified:
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "internal_error"})
+		}
+		return
+	}
+
+	if out.Challenge != nil {
+		c.JSON(http.StatusOK, out.Challenge)
+		return
+	}
+
+	c.JSON(http.StatusOK, out)
+}
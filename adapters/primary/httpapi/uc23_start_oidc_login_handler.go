@@ -0,0 +1,28 @@
+package httpapi
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/moondolphin/crypto-api/app"
+)
+
+type StartOIDCLoginHandler struct {
+	UC app.StartOIDCLoginUseCase
+}
+
+// @Summary Login con OIDC
+// @Description Redirige al proveedor de identidad (authorization code + PKCE). El proveedor vuelve a /api/v1/auth/oidc/callback.
+// @Tags Auth
+// @Success 302
+// @Failure 503 {object} map[string]string
+// @Router /api/v1/auth/oidc/login [get]
+func (h StartOIDCLoginHandler) Handle(c *gin.Context) {
+	out, err := h.UC.Execute(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "internal_error"})
+		return
+	}
+
+	c.Redirect(http.StatusFound, out.AuthorizationURL)
+}
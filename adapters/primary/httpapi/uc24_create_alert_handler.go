@@ -0,0 +1,60 @@
+package httpapi
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/moondolphin/crypto-api/app"
+)
+
+type CreateAlertHandler struct {
+	UC app.CreateAlertUseCase
+}
+
+// @Summary Crear alerta de precio
+// @Description Crea una regla de alerta del usuario autenticado. kind above/below compara el precio con threshold; pct_change compara la variación % en window_seconds (threshold negativo = caída). Con rearm=false la regla se desactiva al dispararse; con rearm=true vuelve a armarse cuando la condición deja de cumplirse.
+// @Tags Alerts
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param body body app.CreateAlertInput true "symbol, kind, threshold, window_seconds (pct_change), rearm; currency y provider opcionales"
+// @Success 201 {object} app.AlertOutput
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Router /api/v1/users/me/alerts [post]
+func (h CreateAlertHandler) Handle(c *gin.Context) {
+	auth, ok := MustAuth(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	var in app.CreateAlertInput
+	if err := c.ShouldBindJSON(&in); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "bad_request"})
+		return
+	}
+
+	out, err := h.UC.Execute(c.Request.Context(), auth.UserID, in)
+	if err != nil {
+		switch err {
+		case app.ErrBadRequest, app.ErrInvalidAlertKind, app.ErrInvalidAlertThreshold, app.ErrInvalidAlertWindow,
+			app.ErrProviderNotSupported:
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case app.ErrCoinNotEnabled:
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case app.ErrAlertLimitReached:
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "internal_error"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, out)
+}
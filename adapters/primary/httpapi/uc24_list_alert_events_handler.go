@@ -0,0 +1,72 @@
+package httpapi
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/moondolphin/crypto-api/app"
+)
+
+type ListAlertEventsHandler struct {
+	UC app.ListAlertEventsUseCase
+}
+
+// @Summary Listar alertas disparadas
+// @Description Devuelve los disparos de las alertas del usuario autenticado (o de una sola alerta), del más reciente al más viejo.
+// @Tags Alerts
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param id path int false "Alert ID (sólo en /alerts/{id}/events)"
+// @Param limit query int false "Máximo de eventos (default 50, máx 200)"
+// @Success 200 {array} app.AlertEventOutput
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Router /api/v1/users/me/alerts/events [get]
+// @Router /api/v1/users/me/alerts/{id}/events [get]
+func (h ListAlertEventsHandler) Handle(c *gin.Context) {
+	auth, ok := MustAuth(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	var alertID int64
+	if raw := c.Param("id"); raw != "" {
+		id, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || id <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "bad_request"})
+			return
+		}
+		alertID = id
+	}
+
+	limit := 0
+	if raw := c.Query("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "bad_request"})
+			return
+		}
+		limit = n
+	}
+
+	out, err := h.UC.Execute(c.Request.Context(), auth.UserID, alertID, limit)
+	if err != nil {
+		switch err {
+		case app.ErrBadRequest:
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case app.ErrAlertNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "internal_error"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, out)
+}
@@ -0,0 +1,39 @@
+package httpapi
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/moondolphin/crypto-api/app"
+)
+
+type ListAlertsHandler struct {
+	UC app.ListAlertsUseCase
+}
+
+// @Summary Listar alertas de precio
+// @Description Devuelve las reglas de alerta del usuario autenticado, activas o no.
+// @Tags Alerts
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Success 200 {array} app.AlertOutput
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Router /api/v1/users/me/alerts [get]
+func (h ListAlertsHandler) Handle(c *gin.Context) {
+	auth, ok := MustAuth(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	out, err := h.UC.Execute(c.Request.Context(), auth.UserID)
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "internal_error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, out)
+}
@@ -0,0 +1,64 @@
+package httpapi
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/moondolphin/crypto-api/app"
+)
+
+type UpdateAlertHandler struct {
+	UC app.UpdateAlertUseCase
+}
+
+// @Summary Modificar alerta de precio
+// @Description Cambia threshold, window_seconds, rearm o active de una regla del usuario autenticado. Cambiar la condición o reactivar la regla la vuelve a armar.
+// @Tags Alerts
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param id path int true "Alert ID"
+// @Param body body app.UpdateAlertInput true "campos a modificar"
+// @Success 200 {object} app.AlertOutput
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Router /api/v1/users/me/alerts/{id} [patch]
+func (h UpdateAlertHandler) Handle(c *gin.Context) {
+	auth, ok := MustAuth(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "bad_request"})
+		return
+	}
+
+	var in app.UpdateAlertInput
+	if err := c.ShouldBindJSON(&in); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "bad_request"})
+		return
+	}
+
+	out, err := h.UC.Execute(c.Request.Context(), auth.UserID, id, in)
+	if err != nil {
+		switch err {
+		case app.ErrBadRequest, app.ErrInvalidAlertThreshold, app.ErrInvalidAlertWindow:
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case app.ErrAlertNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "internal_error"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, out)
+}
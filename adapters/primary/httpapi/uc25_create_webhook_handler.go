@@ -0,0 +1,55 @@
+package httpapi
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/moondolphin/crypto-api/app"
+)
+
+type CreateWebhookHandler struct {
+	UC app.CreateWebhookUseCase
+}
+
+// @Summary Crear webhook
+// @Description Suscribe una URL https a eventos (quotes.stored, alert.triggered). Cada entrega es un POST JSON firmado en X-Webhook-Signature: t=<unix>,v1=<hex HMAC-SHA256(secret, "<t>.<body>")>. El secret sólo se devuelve en esta respuesta.
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param body body app.CreateWebhookInput true "url y events"
+// @Success 201 {object} app.CreatedWebhookOutput
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Router /api/v1/users/me/webhooks [post]
+func (h CreateWebhookHandler) Handle(c *gin.Context) {
+	auth, ok := MustAuth(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	var in app.CreateWebhookInput
+	if err := c.ShouldBindJSON(&in); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "bad_request"})
+		return
+	}
+
+	out, err := h.UC.Execute(c.Request.Context(), auth.UserID, in)
+	if err != nil {
+		switch err {
+		case app.ErrInvalidWebhookURL, app.ErrInvalidWebhookEvents:
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case app.ErrWebhookLimitReached:
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "internal_error"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, out)
+}
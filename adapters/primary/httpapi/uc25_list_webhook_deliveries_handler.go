@@ -0,0 +1,66 @@
+package httpapi
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/moondolphin/crypto-api/app"
+)
+
+type ListWebhookDeliveriesHandler struct {
+	UC app.ListWebhookDeliveriesUseCase
+}
+
+// @Summary Listar entregas de un webhook
+// @Description Devuelve las entregas de una suscripción, de la más nueva a la más vieja, con el resultado del último intento. Las entregas exitosas se purgan después de WEBHOOK_DELIVERED_RETENTION_HOURS.
+// @Tags Webhooks
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Webhook ID"
+// @Param status query string false "pending | delivered | dead"
+// @Param limit query int false "Máximo de entregas (default 50, máx 200)"
+// @Success 200 {array} app.WebhookDeliveryOutput
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Router /api/v1/users/me/webhooks/{id}/deliveries [get]
+func (h ListWebhookDeliveriesHandler) Handle(c *gin.Context) {
+	auth, ok := MustAuth(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "bad_request"})
+		return
+	}
+
+	limit := 0
+	if raw := c.Query("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "bad_request"})
+			return
+		}
+		limit = n
+	}
+
+	out, err := h.UC.Execute(c.Request.Context(), auth.UserID, id, c.Query("status"), limit)
+	if err != nil {
+		switch err {
+		case app.ErrBadRequest:
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case app.ErrWebhookNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "internal_error"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, out)
+}
@@ -0,0 +1,37 @@
+package httpapi
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/moondolphin/crypto-api/app"
+)
+
+type ListWebhooksHandler struct {
+	UC app.ListWebhooksUseCase
+}
+
+// @Summary Listar webhooks
+// @Description Devuelve las suscripciones del usuario autenticado (sin el secret).
+// @Tags Webhooks
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} app.WebhookOutput
+// @Failure 401 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Router /api/v1/users/me/webhooks [get]
+func (h ListWebhooksHandler) Handle(c *gin.Context) {
+	auth, ok := MustAuth(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	out, err := h.UC.Execute(c.Request.Context(), auth.UserID)
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "internal_error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, out)
+}
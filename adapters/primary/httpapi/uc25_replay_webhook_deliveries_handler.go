@@ -0,0 +1,69 @@
+package httpapi
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/moondolphin/crypto-api/app"
+)
+
+type ReplayWebhookDeliveriesHandler struct {
+	UC app.ReplayWebhookDeliveriesUseCase
+}
+
+// @Summary Reintentar entregas fallidas
+// @Description Vuelve a encolar entregas en estado dead (agotaron los reintentos) con el mismo payload y el contador de intentos en cero. Sin deliveryId reencola todas las de la suscripción.
+// @Tags Webhooks
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Webhook ID"
+// @Param deliveryId path int false "Delivery ID (sólo en /deliveries/{deliveryId}/replay)"
+// @Success 200 {object} app.ReplayWebhookDeliveriesOutput
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Router /api/v1/users/me/webhooks/{id}/deliveries/replay [post]
+// @Router /api/v1/users/me/webhooks/{id}/deliveries/{deliveryId}/replay [post]
+func (h ReplayWebhookDeliveriesHandler) Handle(c *gin.Context) {
+	auth, ok := MustAuth(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "bad_request"})
+		return
+	}
+
+	var deliveryID int64
+	if raw := c.Param("deliveryId"); raw != "" {
+		n, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || n <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "bad_request"})
+			return
+		}
+		deliveryID = n
+	}
+
+	out, err := h.UC.Execute(c.Request.Context(), auth.UserID, id, deliveryID)
+	if err != nil {
+		switch err {
+		case app.ErrBadRequest:
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case app.ErrWebhookNotFound, app.ErrWebhookDeliveryNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case app.ErrWebhookDeliveryNotFailed:
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "internal_error"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, out)
+}
@@ -0,0 +1,56 @@
+package httpapi
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/moondolphin/crypto-api/app"
+)
+
+type CreatePortfolioHandler struct {
+	UC app.CreatePortfolioUseCase
+}
+
+// @Summary Crear portfolio
+// @Description Crea un portfolio vacío del usuario autenticado. El nombre es único por usuario (sin distinguir mayúsculas); base_currency (default USD) es la moneda en que se valúa si no se pide otra.
+// @Tags Portfolios
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param body body app.CreatePortfolioInput true "name y base_currency opcional"
+// @Success 201 {object} app.PortfolioOutput
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Router /api/v1/users/me/portfolios [post]
+func (h CreatePortfolioHandler) Handle(c *gin.Context) {
+	auth, ok := MustAuth(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	var in app.CreatePortfolioInput
+	if err := c.ShouldBindJSON(&in); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "bad_request"})
+		return
+	}
+
+	out, err := h.UC.Execute(c.Request.Context(), auth.UserID, in)
+	if err != nil {
+		switch err {
+		case app.ErrInvalidPortfolioName, app.ErrInvalidPortfolioCurrency:
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case app.ErrPortfolioNameTaken, app.ErrPortfolioLimitReached:
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "internal_error"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, out)
+}
@@ -0,0 +1,57 @@
+package httpapi
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/moondolphin/crypto-api/app"
+)
+
+type PortfolioValuationHandler struct {
+	UC app.ValuePortfolioUseCase
+}
+
+// @Summary Valuar portfolio
+// @Description Valúa las tenencias con la última cotización guardada de cada coin en la moneda pedida: valor por activo, total, porcentaje de distribución y antigüedad de cada precio (stale=true si supera el umbral). Los activos sin cotización en esa moneda se listan con price_missing=true y no suman al total.
+// @Tags Portfolios
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param id path int true "Portfolio ID"
+// @Param currency query string false "Moneda de valuación (default: base_currency del portfolio)"
+// @Success 200 {object} app.PortfolioValuationOutput
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Router /api/v1/users/me/portfolios/{id}/valuation [get]
+func (h PortfolioValuationHandler) Handle(c *gin.Context) {
+	auth, ok := MustAuth(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "bad_request"})
+		return
+	}
+
+	out, err := h.UC.Execute(c.Request.Context(), auth.UserID, id, c.Query("currency"))
+	if err != nil {
+		switch err {
+		case app.ErrBadRequest, app.ErrInvalidPortfolioCurrency:
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case app.ErrPortfolioNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "internal_error"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, out)
+}
@@ -0,0 +1,65 @@
+package httpapi
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/moondolphin/crypto-api/app"
+)
+
+type SetHoldingHandler struct {
+	UC app.SetHoldingUseCase
+}
+
+// @Summary Fijar tenencia
+// @Description Fija la cantidad que el portfolio tiene de una coin habilitada: crea la tenencia o reemplaza la cantidad. quantity es un decimal positivo de hasta 18 decimales (mejor como string para no perder precisión).
+// @Tags Portfolios
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param id path int true "Portfolio ID"
+// @Param symbol path string true "Símbolo de la coin, ej: BTC"
+// @Param body body app.SetHoldingInput true "quantity"
+// @Success 200 {object} app.HoldingOutput
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Router /api/v1/users/me/portfolios/{id}/holdings/{symbol} [put]
+func (h SetHoldingHandler) Handle(c *gin.Context) {
+	auth, ok := MustAuth(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "bad_request"})
+		return
+	}
+
+	var in app.SetHoldingInput
+	if err := c.ShouldBindJSON(&in); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "bad_request"})
+		return
+	}
+
+	out, err := h.UC.Execute(c.Request.Context(), auth.UserID, id, c.Param("symbol"), in)
+	if err != nil {
+		switch err {
+		case app.ErrBadRequest, app.ErrInvalidQuantity:
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case app.ErrPortfolioNotFound, app.ErrCoinNotEnabled:
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "internal_error"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, out)
+}
@@ -0,0 +1,78 @@
+package httpapi
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/moondolphin/crypto-api/app"
+)
+
+type ListTransactionsHandler struct {
+	UC app.ListTransactionsUseCase
+}
+
+// @Summary Listar movimientos
+// @Description Lista los movimientos del portfolio en orden cronológico, opcionalmente de una sola coin y/o en un rango de fechas.
+// @Tags Portfolios
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param id path int true "Portfolio ID"
+// @Param symbol query string false "Símbolo de la coin, ej: BTC"
+// @Param from query string false "Desde (RFC3339 o YYYY-MM-DD, incluido)"
+// @Param to query string false "Hasta (RFC3339 o YYYY-MM-DD)"
+// @Success 200 {array} app.TransactionOutput
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Router /api/v1/users/me/portfolios/{id}/transactions [get]
+func (h ListTransactionsHandler) Handle(c *gin.Context) {
+	auth, ok := MustAuth(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "bad_request"})
+		return
+	}
+
+	in := app.ListTransactionsInput{Symbol: c.Query("symbol")}
+	if s := strings.TrimSpace(c.Query("from")); s != "" {
+		t, err := parseTimeFlexible(s, false)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "bad_request"})
+			return
+		}
+		in.From = &t
+	}
+	if s := strings.TrimSpace(c.Query("to")); s != "" {
+		t, err := parseTimeFlexible(s, true)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "bad_request"})
+			return
+		}
+		in.To = &t
+	}
+
+	out, err := h.UC.Execute(c.Request.Context(), auth.UserID, id, in)
+	if err != nil {
+		switch err {
+		case app.ErrBadRequest:
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case app.ErrPortfolioNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "internal_error"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, out)
+}
@@ -0,0 +1,61 @@
+package httpapi
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/moondolphin/crypto-api/app"
+)
+
+type PortfolioPnLHandler struct {
+	UC app.PortfolioPnLUseCase
+}
+
+// @Summary Ganancias del portfolio
+// @Description Calcula, a partir de los movimientos, la ganancia realizada (ventas menos costo, neto de comisiones) y la no realizada de lo que sigue abierto, valuado con la última cotización guardada. method=fifo asigna el costo de los lotes más viejos y lista los lotes abiertos; method=average usa el costo promedio ponderado. Todos los movimientos tienen que estar en la moneda pedida (409 transaction_currency_mismatch si no).
+// @Tags Portfolios
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param id path int true "Portfolio ID"
+// @Param method query string false "fifo (default) | average"
+// @Param currency query string false "Moneda (default: base_currency del portfolio)"
+// @Success 200 {object} app.PortfolioPnLOutput
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Router /api/v1/users/me/portfolios/{id}/pnl [get]
+func (h PortfolioPnLHandler) Handle(c *gin.Context) {
+	auth, ok := MustAuth(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "bad_request"})
+		return
+	}
+
+	out, err := h.UC.Execute(c.Request.Context(), auth.UserID, id, c.Query("method"), c.Query("currency"))
+	if err != nil {
+		switch err {
+		case app.ErrBadRequest, app.ErrInvalidCostBasisMethod, app.ErrInvalidPortfolioCurrency:
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case app.ErrPortfolioNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case app.ErrTransactionCurrencyMixed:
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "internal_error"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, out)
+}
@@ -0,0 +1,69 @@
+package httpapi
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/moondolphin/crypto-api/app"
+)
+
+type RecordTransactionHandler struct {
+	UC app.RecordTransactionUseCase
+}
+
+// @Summary Registrar movimiento
+// @Description Registra una compra (buy), venta (sell) o transferencia (transfer_in / transfer_out) en el portfolio. quantity, price y fee son decimales de hasta 18 decimales (mejor como string). price es obligatorio en buy/sell; en transfer_in es el costo asignado a las unidades (sin precio cuenta como 0 y el reporte impositivo usa el cierre del día; un 0 explícito se respeta) y en transfer_out no se usa. currency default: base_currency del portfolio, y si viene tiene que coincidir (transaction_currency_mismatch); executed_at default: ahora. Una salida que deje sin cubrir alguna venta o transferencia (en su fecha o después) se rechaza con insufficient_quantity.
+// @Tags Portfolios
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param id path int true "Portfolio ID"
+// @Param body body app.RecordTransactionInput true "Movimiento"
+// @Success 201 {object} app.TransactionOutput
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Router /api/v1/users/me/portfolios/{id}/transactions [post]
+func (h RecordTransactionHandler) Handle(c *gin.Context) {
+	auth, ok := MustAuth(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "bad_request"})
+		return
+	}
+
+	var in app.RecordTransactionInput
+	if err := c.ShouldBindJSON(&in); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "bad_request"})
+		return
+	}
+
+	out, err := h.UC.Execute(c.Request.Context(), auth.UserID, id, in)
+	if err != nil {
+		switch err {
+		case app.ErrBadRequest, app.ErrInvalidTransactionType, app.ErrInvalidQuantity, app.ErrInvalidPrice,
+			app.ErrInvalidFee, app.ErrInvalidExecutedAt, app.ErrInvalidTransactionNote, app.ErrInvalidPortfolioCurrency,
+			app.ErrTransactionCurrencyMixed:
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case app.ErrPortfolioNotFound, app.ErrCoinNotEnabled:
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case app.ErrInsufficientQuantity:
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "internal_error"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, out)
+}
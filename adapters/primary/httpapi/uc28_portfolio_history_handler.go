@@ -0,0 +1,79 @@
+package httpapi
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/moondolphin/crypto-api/app"
+)
+
+type PortfolioHistoryHandler struct {
+	UC app.PortfolioHistoryUseCase
+}
+
+// @Summary Historial de valor del portfolio
+// @Description Reconstruye el valor del portfolio al cierre de cada día (UTC) con las cotizaciones guardadas. La cantidad de cada coin sale de sus movimientos; las coins sin movimientos usan su tenencia actual en todo el rango. Si un día no hubo cotización se usa el último cierre de los días anteriores (hasta un límite); si no hay, la coin se lista en missing y no suma. Default: los últimos 30 días.
+// @Tags Portfolios
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param id path int true "Portfolio ID"
+// @Param from query string false "Primer día (YYYY-MM-DD)"
+// @Param to query string false "Último día (YYYY-MM-DD, default: hoy)"
+// @Param currency query string false "Moneda (default: base_currency del portfolio)"
+// @Success 200 {object} app.PortfolioHistoryOutput
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Router /api/v1/users/me/portfolios/{id}/history [get]
+func (h PortfolioHistoryHandler) Handle(c *gin.Context) {
+	auth, ok := MustAuth(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "bad_request"})
+		return
+	}
+
+	in := app.PortfolioHistoryInput{Currency: c.Query("currency")}
+	if s := strings.TrimSpace(c.Query("from")); s != "" {
+		day, err := time.Parse("2006-01-02", s)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "bad_request"})
+			return
+		}
+		in.From = &day
+	}
+	if s := strings.TrimSpace(c.Query("to")); s != "" {
+		day, err := time.Parse("2006-01-02", s)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "bad_request"})
+			return
+		}
+		in.To = &day
+	}
+
+	out, err := h.UC.Execute(c.Request.Context(), auth.UserID, id, in)
+	if err != nil {
+		switch err {
+		case app.ErrBadRequest, app.ErrInvalidPortfolioCurrency, app.ErrInvalidHistoryRange, app.ErrHistoryRangeTooLarge:
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case app.ErrPortfolioNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "internal_error"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, out)
+}
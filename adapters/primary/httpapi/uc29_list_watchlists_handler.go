@@ -0,0 +1,39 @@
+package httpapi
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/moondolphin/crypto-api/app"
+)
+
+type ListWatchlistsHandler struct {
+	UC app.ListWatchlistsUseCase
+}
+
+// @Summary Listar watchlists
+// @Description Devuelve las watchlists del usuario autenticado (sin entradas). La primera es la por defecto, la misma que muestra /users/me/favorites; se crea si todavía no existe.
+// @Tags Watchlists
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Success 200 {array} app.WatchlistOutput
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Router /api/v1/users/me/watchlists [get]
+func (h ListWatchlistsHandler) Handle(c *gin.Context) {
+	auth, ok := MustAuth(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	out, err := h.UC.Execute(c.Request.Context(), auth.UserID)
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "internal_error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, out)
+}
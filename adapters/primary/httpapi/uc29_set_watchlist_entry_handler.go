@@ -0,0 +1,67 @@
+package httpapi
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/moondolphin/crypto-api/app"
+)
+
+type SetWatchlistEntryHandler struct {
+	UC app.SetWatchlistEntryUseCase
+}
+
+// @Summary Agregar coin a watchlist
+// @Description Agrega la coin al final de la watchlist o, si ya estaba, le cambia la nota. El body es opcional: sin note una coin existente conserva la suya y "" la borra (hasta 500 caracteres).
+// @Tags Watchlists
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param id path int true "Watchlist ID"
+// @Param symbol path string true "Símbolo de la coin, ej: BTC"
+// @Param body body app.SetWatchlistEntryInput false "note"
+// @Success 200 {object} app.WatchlistEntryOutput
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Router /api/v1/users/me/watchlists/{id}/entries/{symbol} [put]
+func (h SetWatchlistEntryHandler) Handle(c *gin.Context) {
+	auth, ok := MustAuth(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "bad_request"})
+		return
+	}
+
+	var in app.SetWatchlistEntryInput
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&in); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "bad_request"})
+			return
+		}
+	}
+
+	out, err := h.UC.Execute(c.Request.Context(), auth.UserID, id, c.Param("symbol"), in)
+	if err != nil {
+		switch err {
+		case app.ErrBadRequest, app.ErrInvalidWatchlistNote:
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case app.ErrWatchlistNotFound, app.ErrCoinNotFound, app.ErrWatchlistEntryNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "internal_error"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, out)
+}
@@ -0,0 +1,76 @@
+package httpapi
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/moondolphin/crypto-api/app"
+)
+
+type BacktestHandler struct {
+	UC app.BacktestUseCase
+}
+
+// @Summary Backtest de compras periódicas o única
+// @Description Simula comprar amount del símbolo en cada fecha (dca: daily, weekly o monthly) o una sola vez al inicio (lump_sum) con los cierres diarios (UTC) guardados. Si una fecha no tiene cotización se compra el primer día siguiente que tenga, antes de la próxima compra; si no hay se lista en skipped. Devuelve invertido, valor final, retorno, drawdown máximo (sobre valor/invertido) y las compras. Default: los últimos 365 días.
+// @Tags Quotes
+// @Produce json
+// @Param symbol query string true "Símbolo (BTC, ETH...)"
+// @Param provider query string false "Proveedor (default: cualquiera)"
+// @Param currency query string false "Moneda (default: USD)"
+// @Param strategy query string true "dca o lump_sum"
+// @Param interval query string false "Frecuencia de dca: daily, weekly (default) o monthly"
+// @Param amount query string true "Monto por compra (dca) o total (lump_sum)"
+// @Param from query string false "Primer día (YYYY-MM-DD)"
+// @Param to query string false "Último día (YYYY-MM-DD, default: hoy)"
+// @Success 200 {object} app.BacktestOutput
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /api/v1/quotes/backtest [get]
+func (h BacktestHandler) Handle(c *gin.Context) {
+	in := app.BacktestInput{
+		Symbol:   c.Query("symbol"),
+		Provider: c.Query("provider"),
+		Currency: c.Query("currency"),
+		Strategy: c.Query("strategy"),
+		Interval: c.Query("interval"),
+		Amount:   c.Query("amount"),
+	}
+	if s := strings.TrimSpace(c.Query("from")); s != "" {
+		day, err := time.Parse("2006-01-02", s)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "bad_request"})
+			return
+		}
+		in.From = &day
+	}
+	if s := strings.TrimSpace(c.Query("to")); s != "" {
+		day, err := time.Parse("2006-01-02", s)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "bad_request"})
+			return
+		}
+		in.To = &day
+	}
+
+	out, err := h.UC.Execute(c.Request.Context(), in)
+	if err != nil {
+		switch err {
+		case app.ErrBadRequest, app.ErrInvalidBacktestStrategy, app.ErrInvalidBacktestInterval,
+			app.ErrInvalidBacktestAmount, app.ErrInvalidBacktestRange, app.ErrBacktestRangeTooLarge:
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case app.ErrQuoteNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "internal_error"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, out)
+}
@@ -0,0 +1,94 @@
+package httpapi
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/moondolphin/crypto-api/app"
+)
+
+type TaxReportHandler struct {
+	UC app.TaxReportUseCase
+}
+
+// @Summary Reporte impositivo anual del portfolio
+// @Description Lista las ventas del año (UTC) con fecha de compra, proceeds (netos de comisión), costo y ganancia. Con method=fifo hay una fila por lote consumido, con plazo (long si se tuvo más de un año) y lo que le quedó abierto si el lote se consumió en parte; con average una fila por venta contra el costo promedio. Un transfer_in cargado sin precio (no con price 0, que se respeta) toma como costo el cierre guardado del día de la entrada (cost_basis_source=fair_market_value, o missing si no hay cotización). Las transferencias de salida no se listan. format=csv devuelve el mismo detalle como archivo CSV.
+// @Tags Portfolios
+// @Produce json
+// @Produce text/csv
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param id path int true "Portfolio ID"
+// @Param year query int false "Año (default: el actual)"
+// @Param method query string false "fifo (default) | average"
+// @Param currency query string false "Moneda (default: base_currency del portfolio)"
+// @Param format query string false "json (default) | csv"
+// @Success 200 {object} app.TaxReportOutput
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Router /api/v1/users/me/portfolios/{id}/tax-report [get]
+func (h TaxReportHandler) Handle(c *gin.Context) {
+	auth, ok := MustAuth(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "bad_request"})
+		return
+	}
+
+	format := strings.ToLower(strings.TrimSpace(c.Query("format")))
+	if format != "" && format != "json" && format != "csv" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "bad_request"})
+		return
+	}
+
+	in := app.TaxReportInput{Method: c.Query("method"), Currency: c.Query("currency")}
+	if raw := strings.TrimSpace(c.Query("year")); raw != "" {
+		year, err := strconv.Atoi(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": app.ErrInvalidTaxYear.Error()})
+			return
+		}
+		in.Year = year
+	}
+
+	out, err := h.UC.Execute(c.Request.Context(), auth.UserID, id, in)
+	if err != nil {
+		switch err {
+		case app.ErrBadRequest, app.ErrInvalidTaxYear, app.ErrInvalidCostBasisMethod, app.ErrInvalidPortfolioCurrency:
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case app.ErrPortfolioNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case app.ErrTransactionCurrencyMixed:
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "internal_error"})
+		}
+		return
+	}
+
+	if format == "csv" {
+		var buf bytes.Buffer
+		if err := out.WriteCSV(&buf); err != nil {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "internal_error"})
+			return
+		}
+		c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="tax-report-%d-%d.csv"`, out.PortfolioID, out.Year))
+		c.Data(http.StatusOK, "text/csv; charset=utf-8", buf.Bytes())
+		return
+	}
+
+	c.JSON(http.StatusOK, out)
+}
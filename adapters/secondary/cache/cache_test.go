@@ -0,0 +1,140 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/moondolphin/crypto-api/adapters/secondary/persistence/memory"
+	"github.com/moondolphin/crypto-api/domain"
+	"github.com/moondolphin/crypto-api/test/contract"
+)
+
+func TestTTLCache_ExpiresEntries(t *testing.T) {
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+	c := newTTLCache[string, int](time.Minute, 10, func() time.Time { return now })
+
+	c.set("a", 1)
+	v, ok := c.get("a")
+	require.True(t, ok)
+	require.Equal(t, 1, v)
+
+	now = now.Add(time.Minute)
+	_, ok = c.get("a")
+	require.False(t, ok)
+
+	st := c.stats()
+	require.Equal(t, uint64(1), st.Hits)
+	require.Equal(t, uint64(1), st.Misses)
+	require.Equal(t, 0, st.Size)
+}
+
+func TestTTLCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	c := newTTLCache[string, int](time.Hour, 2, nil)
+
+	c.set("a", 1)
+	c.set("b", 2)
+	_, _ = c.get("a") // "b" pasa a ser el menos usado
+	c.set("c", 3)
+
+	_, ok := c.get("b")
+	require.False(t, ok)
+	_, ok = c.get("a")
+	require.True(t, ok)
+	_, ok = c.get("c")
+	require.True(t, ok)
+
+	st := c.stats()
+	require.Equal(t, uint64(1), st.Evictions)
+	require.Equal(t, 2, st.Size)
+}
+
+// countingQuotes cuenta las llamadas que llegan al repo decorado.
+type countingQuotes struct {
+	domain.QuoteRepository
+	calls int
+}
+
+func (r *countingQuotes) GetLatest(ctx context.Context, symbol, provider, currency string) (*domain.PriceQuote, error) {
+	r.calls++
+	return r.QuoteRepository.GetLatest(ctx, symbol, provider, currency)
+}
+
+func TestCachedQuoteRepository_ServesFromCacheUntilInvalidated(t *testing.T) {
+	ctx := context.Background()
+	inner := &countingQuotes{QuoteRepository: memory.NewMemoryQuoteRepository()}
+	repo := NewCachedQuoteRepository(inner, time.Hour, 10)
+
+	base := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+	_, err := repo.Insert(ctx, domain.Quote{CoinID: 1, Symbol: "BTC", Provider: "binance", Currency: "USDT", Price: "100", QuotedAt: base})
+	require.NoError(t, err)
+
+	q, err := repo.GetLatest(ctx, "BTC", "binance", "USDT")
+	require.NoError(t, err)
+	require.Equal(t, "100", q.Price)
+
+	// el caller no puede alterar lo cacheado
+	q.Price = "tampered"
+
+	_, err = repo.Insert(ctx, domain.Quote{CoinID: 1, Symbol: "BTC", Provider: "binance", Currency: "USDT", Price: "200", QuotedAt: base.Add(time.Hour)})
+	require.NoError(t, err)
+
+	q, err = repo.GetLatest(ctx, "BTC", "binance", "USDT")
+	require.NoError(t, err)
+	require.Equal(t, "100", q.Price)
+	require.Equal(t, 1, inner.calls)
+
+	repo.InvalidateSymbol("BTC")
+
+	q, err = repo.GetLatest(ctx, "BTC", "binance", "USDT")
+	require.NoError(t, err)
+	require.Equal(t, "200", q.Price)
+	require.Equal(t, 2, inner.calls)
+
+	st := repo.Stats()
+	require.Equal(t, uint64(1), st.Hits)
+	require.Equal(t, uint64(2), st.Misses)
+}
+
+func TestCachedQuoteRepository_CachesMisses(t *testing.T) {
+	ctx := context.Background()
+	inner := &countingQuotes{QuoteRepository: memory.NewMemoryQuoteRepository()}
+	repo := NewCachedQuoteRepository(inner, time.Hour, 10)
+
+	for i := 0; i < 3; i++ {
+		q, err := repo.GetLatest(ctx, "ETH", "", "")
+		require.NoError(t, err)
+		require.Nil(t, q)
+	}
+	require.Equal(t, 1, inner.calls)
+}
+
+func TestCachedCoinRepository_UpsertInvalidatesSymbol(t *testing.T) {
+	ctx := context.Background()
+	repo := NewCachedCoinRepository(memory.NewMemoryCoinRepository(), time.Hour, 10)
+
+	_, err := repo.Upsert(ctx, domain.Coin{Symbol: "BTC", Enabled: true, CoinGeckoID: "bitcoin"})
+	require.NoError(t, err)
+
+	c, err := repo.GetEnabledBySymbol(ctx, "BTC")
+	require.NoError(t, err)
+	require.NotNil(t, c)
+
+	_, err = repo.Upsert(ctx, domain.Coin{Symbol: "BTC", Enabled: false})
+	require.NoError(t, err)
+
+	c, err = repo.GetEnabledBySymbol(ctx, "BTC")
+	require.NoError(t, err)
+	require.Nil(t, c)
+}
+
+func TestCachedRepositories_Contract(t *testing.T) {
+	contract.RunRepositoryContract(t, func(t *testing.T) contract.Repositories {
+		repos := memory.NewRepositories()
+		repos.Coins = NewCachedCoinRepository(repos.Coins, time.Hour, 100)
+		repos.Quotes = NewCachedQuoteRepository(repos.Quotes, time.Hour, 100)
+		return repos
+	})
+}
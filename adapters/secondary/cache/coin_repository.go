@@ -0,0 +1,71 @@
+package cache
+
+import (
+	"context"
+	"time"
+
+	"github.com/moondolphin/crypto-api/domain"
+)
+
+type coinKey struct {
+	symbol      string
+	enabledOnly bool
+}
+
+// CachedCoinRepository decora un CoinRepository cacheando las búsquedas por símbolo.
+// Upsert invalida el símbolo afectado; ListEnabled no se cachea.
+type CachedCoinRepository struct {
+	domain.CoinRepository
+	bySymbol *ttlCache[coinKey, *domain.Coin]
+}
+
+func NewCachedCoinRepository(inner domain.CoinRepository, ttl time.Duration, maxEntries int) *CachedCoinRepository {
+	return &CachedCoinRepository{
+		CoinRepository: inner,
+		bySymbol:       newTTLCache[coinKey, *domain.Coin](ttl, maxEntries, nil),
+	}
+}
+
+func (r *CachedCoinRepository) GetEnabledBySymbol(ctx context.Context, symbol string) (*domain.Coin, error) {
+	return r.lookup(ctx, coinKey{symbol: symbol, enabledOnly: true}, r.CoinRepository.GetEnabledBySymbol)
+}
+
+func (r *CachedCoinRepository) GetBySymbol(ctx context.Context, symbol string) (*domain.Coin, error) {
+	return r.lookup(ctx, coinKey{symbol: symbol}, r.CoinRepository.GetBySymbol)
+}
+
+func (r *CachedCoinRepository) Upsert(ctx context.Context, c domain.Coin) (*domain.Coin, error) {
+	out, err := r.CoinRepository.Upsert(ctx, c)
+	r.bySymbol.deleteFunc(func(k coinKey) bool { return k.symbol == c.Symbol })
+	return out, err
+}
+
+func (r *CachedCoinRepository) Stats() domain.CacheStats {
+	return r.bySymbol.stats()
+}
+
+func (r *CachedCoinRepository) lookup(
+	ctx context.Context,
+	key coinKey,
+	load func(ctx context.Context, symbol string) (*domain.Coin, error),
+) (*domain.Coin, error) {
+	if c, ok := r.bySymbol.get(key); ok {
+		return copyCoin(c), nil
+	}
+
+	c, err := load(ctx, key.symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	r.bySymbol.set(key, copyCoin(c))
+	return c, nil
+}
+
+func copyCoin(c *domain.Coin) *domain.Coin {
+	if c == nil {
+		return nil
+	}
+	out := *c
+	return &out
+}
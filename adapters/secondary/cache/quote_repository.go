@@ -0,0 +1,61 @@
+package cache
+
+import (
+	"context"
+	"time"
+
+	"github.com/moondolphin/crypto-api/domain"
+)
+
+type latestKey struct {
+	symbol   string
+	provider string
+	currency string
+}
+
+// CachedQuoteRepository decora un QuoteRepository cacheando GetLatest (incluido el
+// "no hay cotización"). No invalida en Insert: lo hace explícitamente RefreshQuotesUseCase
+// vía InvalidateSymbol; otros escritores quedan acotados por el TTL.
+type CachedQuoteRepository struct {
+	domain.QuoteRepository
+	latest *ttlCache[latestKey, *domain.PriceQuote]
+}
+
+func NewCachedQuoteRepository(inner domain.QuoteRepository, ttl time.Duration, maxEntries int) *CachedQuoteRepository {
+	return &CachedQuoteRepository{
+		QuoteRepository: inner,
+		latest:          newTTLCache[latestKey, *domain.PriceQuote](ttl, maxEntries, nil),
+	}
+}
+
+func (r *CachedQuoteRepository) GetLatest(ctx context.Context, symbol, provider, currency string) (*domain.PriceQuote, error) {
+	key := latestKey{symbol: symbol, provider: provider, currency: currency}
+	if q, ok := r.latest.get(key); ok {
+		return copyQuote(q), nil
+	}
+
+	q, err := r.QuoteRepository.GetLatest(ctx, symbol, provider, currency)
+	if err != nil {
+		return nil, err
+	}
+
+	r.latest.set(key, copyQuote(q))
+	return q, nil
+}
+
+func (r *CachedQuoteRepository) InvalidateSymbol(symbol string) {
+	r.latest.deleteFunc(func(k latestKey) bool { return k.symbol == symbol })
+}
+
+func (r *CachedQuoteRepository) Stats() domain.CacheStats {
+	return r.latest.stats()
+}
+
+// copyQuote evita que el llamador modifique el valor cacheado.
+func copyQuote(q *domain.PriceQuote) *domain.PriceQuote {
+	if q == nil {
+		return nil
+	}
+	c := *q
+	return &c
+}
@@ -0,0 +1,59 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/moondolphin/crypto-api/domain"
+)
+
+// FileMailer deja cada mail como un .eml en Dir (outbox local): se abren con
+// cualquier cliente de mail y sirven para probar los flujos sin SMTP.
+type FileMailer struct {
+	Dir  string
+	From string
+	Now  func() time.Time
+
+	seq atomic.Uint64
+}
+
+func NewFileMailer(dir, from string) *FileMailer {
+	return &FileMailer{Dir: dir, From: from}
+}
+
+func (m *FileMailer) Send(ctx context.Context, msg domain.EmailMessage) error {
+	now := m.Now
+	if now == nil {
+		now = time.Now
+	}
+	t := now().UTC()
+
+	body, err := buildMessage(m.From, msg, t)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(m.Dir, 0o755); err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("%s-%04d-%s.eml", t.Format("20060102T150405"), m.seq.Add(1)%10000, safeName(msg.To))
+	return os.WriteFile(filepath.Join(m.Dir, name), body, 0o600)
+}
+
+// safeName deja sólo caracteres seguros para un nombre de archivo.
+func safeName(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '.', r == '-', r == '_', r == '@':
+			return r
+		default:
+			return '_'
+		}
+	}, s)
+}
@@ -0,0 +1,25 @@
+package mail
+
+import (
+	"context"
+	"log"
+
+	"github.com/moondolphin/crypto-api/domain"
+)
+
+// LogMailer no envía nada: escribe el mail en el log. Para desarrollo local.
+type LogMailer struct {
+	Logger *log.Logger
+}
+
+func NewLogMailer(logger *log.Logger) *LogMailer {
+	if logger == nil {
+		logger = log.Default()
+	}
+	return &LogMailer{Logger: logger}
+}
+
+func (m *LogMailer) Send(ctx context.Context, msg domain.EmailMessage) error {
+	m.Logger.Printf("mail to=%s subject=%q\n%s", msg.To, msg.Subject, msg.Body)
+	return nil
+}
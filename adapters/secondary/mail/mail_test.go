@@ -0,0 +1,44 @@
+package mail
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/moondolphin/crypto-api/domain"
+)
+
+func TestBuildMessage_RejectsHeaderInjection(t *testing.T) {
+	_, err := buildMessage("no-reply@x.com", domain.EmailMessage{To: "a@b.com\r\nBcc: evil@x.com", Subject: "s"}, time.Now())
+	require.ErrorIs(t, err, ErrInvalidHeader)
+
+	_, err = buildMessage("no-reply@x.com", domain.EmailMessage{To: "a@b.com", Subject: "s\nBcc: evil@x.com"}, time.Now())
+	require.ErrorIs(t, err, ErrInvalidHeader)
+}
+
+func TestFileMailer_WritesEMLToOutbox(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "outbox")
+	m := NewFileMailer(dir, "no-reply@x.com")
+	m.Now = func() time.Time { return time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC) }
+
+	err := m.Send(context.Background(), domain.EmailMessage{To: "a@b.com", Subject: "Reset", Body: "línea 1\nlínea 2"})
+	require.NoError(t, err)
+
+	files, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	require.True(t, strings.HasSuffix(files[0].Name(), "-a@b.com.eml"))
+
+	raw, err := os.ReadFile(filepath.Join(dir, files[0].Name()))
+	require.NoError(t, err)
+	s := string(raw)
+	require.Contains(t, s, "From: no-reply@x.com\r\n")
+	require.Contains(t, s, "To: a@b.com\r\n")
+	require.Contains(t, s, "Subject: Reset\r\n")
+	require.Contains(t, s, "\r\n\r\nlínea 1\r\nlínea 2")
+}
@@ -0,0 +1,269 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/require"
+
+	"github.com/moondolphin/crypto-api/test/oidctest"
+)
+
+const redirectURL = "http://localhost:8080/api/v1/auth/oidc/callback"
+
+func newTestProvider(issuer *oidctest.Issuer) *Provider {
+	return NewProvider(Config{
+		IssuerURL:    issuer.URL(),
+		ClientID:     issuer.ClientID,
+		ClientSecret: issuer.ClientSecret,
+		RedirectURL:  redirectURL,
+	})
+}
+
+// login recorre el flujo completo: URL de autorización -> /authorize -> canje del code.
+func login(t *testing.T, p *Provider, issuer *oidctest.Issuer, verifier, nonce string) string {
+	t.Helper()
+	ctx := context.Background()
+
+	authURL, err := p.AuthCodeURL(ctx, "state-1", nonce, verifier)
+	require.NoError(t, err)
+
+	code, state, err := issuer.Authorize(authURL)
+	require.NoError(t, err)
+	require.Equal(t, "state-1", state)
+	return code
+}
+
+func TestProvider_AuthCodeURL_IncludesPKCEAndNonce(t *testing.T) {
+	issuer := oidctest.NewIssuer("client-1", "secret")
+	defer issuer.Close()
+
+	authURL, err := newTestProvider(issuer).AuthCodeURL(context.Background(), "st", "nn", "verifier-123")
+	require.NoError(t, err)
+
+	u, err := url.Parse(authURL)
+	require.NoError(t, err)
+	q := u.Query()
+	require.Equal(t, issuer.URL()+"/authorize", u.Scheme+"://"+u.Host+u.Path)
+	require.Equal(t, "code", q.Get("response_type"))
+	require.Equal(t, "client-1", q.Get("client_id"))
+	require.Equal(t, redirectURL, q.Get("redirect_uri"))
+	require.Equal(t, "openid email profile", q.Get("scope"))
+	require.Equal(t, "st", q.Get("state"))
+	require.Equal(t, "nn", q.Get("nonce"))
+	require.Equal(t, "S256", q.Get("code_challenge_method"))
+	require.Equal(t, codeChallengeS256("verifier-123"), q.Get("code_challenge"))
+}
+
+func TestCodeChallengeS256_RFC7636Vector(t *testing.T) {
+	// RFC 7636, apéndice B
+	require.Equal(t, "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM", codeChallengeS256("dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"))
+}
+
+func TestProvider_Exchange_ConfidentialClient(t *testing.T) {
+	issuer := oidctest.NewIssuer("client-1", "s3cr:et")
+	defer issuer.Close()
+	issuer.SetUser(oidctest.User{Subject: "abc", Email: "Ann@Example.com", EmailVerified: true, Name: " Ann "})
+
+	p := newTestProvider(issuer)
+	code := login(t, p, issuer, "verifier-123", "nonce-1")
+
+	claims, err := p.Exchange(context.Background(), code, "verifier-123", "nonce-1")
+	require.NoError(t, err)
+	require.Equal(t, issuer.URL(), claims.Issuer)
+	require.Equal(t, "abc", claims.Subject)
+	require.Equal(t, "ann@example.com", claims.Email)
+	require.True(t, claims.EmailVerified)
+	require.Equal(t, "Ann", claims.Name)
+}
+
+func TestProvider_Exchange_PublicClient(t *testing.T) {
+	issuer := oidctest.NewIssuer("spa", "")
+	defer issuer.Close()
+
+	p := newTestProvider(issuer)
+	code := login(t, p, issuer, "verifier-123", "nonce-1")
+
+	claims, err := p.Exchange(context.Background(), code, "verifier-123", "nonce-1")
+	require.NoError(t, err)
+	require.Equal(t, "user-1", claims.Subject)
+}
+
+func TestProvider_Exchange_WrongVerifier(t *testing.T) {
+	issuer := oidctest.NewIssuer("client-1", "secret")
+	defer issuer.Close()
+
+	p := newTestProvider(issuer)
+	code := login(t, p, issuer, "verifier-123", "nonce-1")
+
+	_, err := p.Exchange(context.Background(), code, "other-verifier", "nonce-1")
+	require.ErrorIs(t, err, ErrTokenEndpoint)
+}
+
+func TestProvider_Exchange_CodeIsSingleUse(t *testing.T) {
+	issuer := oidctest.NewIssuer("client-1", "secret")
+	defer issuer.Close()
+
+	p := newTestProvider(issuer)
+	code := login(t, p, issuer, "verifier-123", "nonce-1")
+
+	_, err := p.Exchange(context.Background(), code, "verifier-123", "nonce-1")
+	require.NoError(t, err)
+	_, err = p.Exchange(context.Background(), code, "verifier-123", "nonce-1")
+	require.ErrorIs(t, err, ErrTokenEndpoint)
+}
+
+func TestProvider_Exchange_RejectsInvalidIDTokens(t *testing.T) {
+	cases := map[string]struct {
+		nonce  string
+		mutate func(jwt.MapClaims)
+	}{
+		"nonce mismatch": {nonce: "other"},
+		"wrong audience": {mutate: func(c jwt.MapClaims) { c["aud"] = "someone-else" }},
+		"wrong issuer":   {mutate: func(c jwt.MapClaims) { c["iss"] = "https://evil.example.com" }},
+		"expired":        {mutate: func(c jwt.MapClaims) { c["exp"] = time.Now().Add(-time.Minute).Unix() }},
+		"missing exp":    {mutate: func(c jwt.MapClaims) { delete(c, "exp") }},
+		"issued in the future": {mutate: func(c jwt.MapClaims) {
+			c["iat"] = time.Now().Add(time.Hour).Unix()
+		}},
+		"missing sub": {mutate: func(c jwt.MapClaims) { delete(c, "sub") }},
+		"multiple audiences without azp": {mutate: func(c jwt.MapClaims) {
+			c["aud"] = []string{"client-1", "other"}
+		}},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			issuer := oidctest.NewIssuer("client-1", "secret")
+			defer issuer.Close()
+			issuer.Mutate = tc.mutate
+
+			p := newTestProvider(issuer)
+			code := login(t, p, issuer, "verifier-123", "nonce-1")
+
+			nonce := "nonce-1"
+			if tc.nonce != "" {
+				nonce = tc.nonce
+			}
+			_, err := p.Exchange(context.Background(), code, "verifier-123", nonce)
+			require.ErrorIs(t, err, ErrInvalidIDToken)
+		})
+	}
+}
+
+func TestProvider_Exchange_AcceptsMultipleAudiencesWithAZP(t *testing.T) {
+	issuer := oidctest.NewIssuer("client-1", "secret")
+	defer issuer.Close()
+	issuer.Mutate = func(c jwt.MapClaims) {
+		c["aud"] = []string{"client-1", "other"}
+		c["azp"] = "client-1"
+	}
+
+	p := newTestProvider(issuer)
+	code := login(t, p, issuer, "verifier-123", "nonce-1")
+
+	_, err := p.Exchange(context.Background(), code, "verifier-123", "nonce-1")
+	require.NoError(t, err)
+}
+
+func TestProvider_Exchange_RefetchesKeysAfterRotation(t *testing.T) {
+	issuer := oidctest.NewIssuer("client-1", "secret")
+	defer issuer.Close()
+
+	now := time.Now()
+	issuer.Now = func() time.Time { return now }
+	p := newTestProvider(issuer)
+	p.cfg.Now = func() time.Time { return now }
+
+	code := login(t, p, issuer, "verifier-123", "nonce-1")
+	_, err := p.Exchange(context.Background(), code, "verifier-123", "nonce-1")
+	require.NoError(t, err)
+
+	issuer.RotateKey()
+
+	// recién pedido: el kid nuevo se rechaza sin volver a pedir el JWKS
+	code = login(t, p, issuer, "verifier-456", "nonce-2")
+	_, err = p.Exchange(context.Background(), code, "verifier-456", "nonce-2")
+	require.ErrorIs(t, err, ErrInvalidIDToken)
+	require.Equal(t, 1, issuer.JWKSFetches())
+
+	now = now.Add(time.Minute)
+	code = login(t, p, issuer, "verifier-789", "nonce-3")
+	_, err = p.Exchange(context.Background(), code, "verifier-789", "nonce-3")
+	require.NoError(t, err)
+	require.Equal(t, 2, issuer.JWKSFetches())
+}
+
+func TestProvider_VerifyIDToken_UnknownKidsDoNotRefetchWithinInterval(t *testing.T) {
+	issuer := oidctest.NewIssuer("client-1", "secret")
+	defer issuer.Close()
+
+	p := newTestProvider(issuer)
+	doc, err := p.discover(context.Background())
+	require.NoError(t, err)
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	claims := jwt.MapClaims{
+		"iss":   issuer.URL(),
+		"sub":   "user-1",
+		"aud":   "client-1",
+		"iat":   time.Now().Unix(),
+		"exp":   time.Now().Add(time.Minute).Unix(),
+		"nonce": "n",
+	}
+	for _, kid := range []string{"random-1", "random-2", "random-3"} {
+		tok := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+		tok.Header["kid"] = kid
+		raw, err := tok.SignedString(key)
+		require.NoError(t, err)
+
+		_, err = p.verifyIDToken(context.Background(), doc, raw, "n")
+		require.ErrorIs(t, err, ErrInvalidIDToken)
+	}
+
+	require.Equal(t, 1, issuer.JWKSFetches())
+}
+
+func TestProvider_VerifyIDToken_RejectsSymmetricAlg(t *testing.T) {
+	issuer := oidctest.NewIssuer("client-1", "secret")
+	defer issuer.Close()
+
+	p := newTestProvider(issuer)
+	doc, err := p.discover(context.Background())
+	require.NoError(t, err)
+
+	// firmado con el client secret: válido para HS256, pero no aceptamos algoritmos simétricos
+	raw, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"iss":   issuer.URL(),
+		"sub":   "user-1",
+		"aud":   "client-1",
+		"iat":   time.Now().Unix(),
+		"exp":   time.Now().Add(time.Minute).Unix(),
+		"nonce": "n",
+	}).SignedString([]byte("secret"))
+	require.NoError(t, err)
+
+	_, err = p.verifyIDToken(context.Background(), doc, raw, "n")
+	require.ErrorIs(t, err, ErrInvalidIDToken)
+}
+
+func TestProvider_Discovery_RejectsIssuerMismatch(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"issuer":"https://evil.example.com","authorization_endpoint":"x","token_endpoint":"x","jwks_uri":"x"}`))
+	}))
+	defer srv.Close()
+
+	p := NewProvider(Config{IssuerURL: srv.URL, ClientID: "c", RedirectURL: redirectURL})
+
+	_, err := p.AuthCodeURL(context.Background(), "s", "n", "v")
+	require.ErrorIs(t, err, ErrDiscovery)
+}
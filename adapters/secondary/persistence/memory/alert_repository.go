@@ -0,0 +1,210 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/moondolphin/crypto-api/domain"
+)
+
+type MemoryAlertRuleRepository struct {
+	mu     sync.RWMutex
+	nextID int64
+	byID   map[int64]domain.AlertRule
+}
+
+func NewMemoryAlertRuleRepository() *MemoryAlertRuleRepository {
+	return &MemoryAlertRuleRepository{byID: make(map[int64]domain.AlertRule)}
+}
+
+func copyAlertRule(r domain.AlertRule) domain.AlertRule {
+	r.LastTriggeredAt = copyTime(r.LastTriggeredAt)
+	return r
+}
+
+func (r *MemoryAlertRuleRepository) Create(ctx context.Context, a domain.AlertRule) (domain.AlertRule, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.nextID++
+	a.ID = r.nextID
+	a.CreatedAt = a.CreatedAt.UTC()
+	a.UpdatedAt = a.UpdatedAt.UTC()
+	a.LastTriggeredAt = nil
+	r.byID[a.ID] = a
+	return a, nil
+}
+
+func (r *MemoryAlertRuleRepository) FindByUser(ctx context.Context, userID, id int64) (*domain.AlertRule, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	a, ok := r.byID[id]
+	if !ok || a.UserID != userID {
+		return nil, nil
+	}
+	a = copyAlertRule(a)
+	return &a, nil
+}
+
+func (r *MemoryAlertRuleRepository) filter(keep func(domain.AlertRule) bool) []domain.AlertRule {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var out []domain.AlertRule
+	for _, a := range r.byID {
+		if keep(a) {
+			out = append(out, copyAlertRule(a))
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out
+}
+
+func (r *MemoryAlertRuleRepository) ListByUser(ctx context.Context, userID int64) ([]domain.AlertRule, error) {
+	return r.filter(func(a domain.AlertRule) bool { return a.UserID == userID }), nil
+}
+
+func (r *MemoryAlertRuleRepository) CountByUser(ctx context.Context, userID int64) (int, error) {
+	return len(r.filter(func(a domain.AlertRule) bool { return a.UserID == userID })), nil
+}
+
+func (r *MemoryAlertRuleRepository) ListActive(ctx context.Context) ([]domain.AlertRule, error) {
+	return r.filter(func(a domain.AlertRule) bool { return a.Active }), nil
+}
+
+func (r *MemoryAlertRuleRepository) Update(ctx context.Context, a domain.AlertRule) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	cur, ok := r.byID[a.ID]
+	if !ok || cur.UserID != a.UserID {
+		return false, nil
+	}
+
+	cur.Threshold = a.Threshold
+	cur.Window = a.Window
+	cur.Rearm = a.Rearm
+	cur.Active = a.Active
+	cur.Armed = a.Armed
+	cur.UpdatedAt = a.UpdatedAt.UTC()
+	r.byID[a.ID] = cur
+	return true, nil
+}
+
+func (r *MemoryAlertRuleRepository) Delete(ctx context.Context, userID, id int64) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	a, ok := r.byID[id]
+	if !ok || a.UserID != userID {
+		return false, nil
+	}
+	delete(r.byID, id)
+	return true, nil
+}
+
+func (r *MemoryAlertRuleRepository) MarkTriggered(ctx context.Context, id int64, at time.Time, deactivate bool) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	a, ok := r.byID[id]
+	if !ok || !a.Active || !a.Armed {
+		return false, nil
+	}
+
+	t := at.UTC()
+	a.Armed = false
+	a.Active = a.Active && !deactivate
+	a.LastTriggeredAt = &t
+	r.byID[id] = a
+	return true, nil
+}
+
+func (r *MemoryAlertRuleRepository) Rearm(ctx context.Context, id int64, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	a, ok := r.byID[id]
+	if !ok || !a.Active || a.Armed {
+		return nil
+	}
+	a.Armed = true
+	a.UpdatedAt = at.UTC()
+	r.byID[id] = a
+	return nil
+}
+
+type MemoryAlertEventRepository struct {
+	mu     sync.RWMutex
+	nextID int64
+	events []domain.AlertEvent
+}
+
+func NewMemoryAlertEventRepository() *MemoryAlertEventRepository {
+	return &MemoryAlertEventRepository{}
+}
+
+func (r *MemoryAlertEventRepository) Create(ctx context.Context, e domain.AlertEvent) (domain.AlertEvent, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.nextID++
+	e.ID = r.nextID
+	e.QuotedAt = e.QuotedAt.UTC()
+	e.TriggeredAt = e.TriggeredAt.UTC()
+	if e.ChangePercent != nil {
+		v := *e.ChangePercent
+		e.ChangePercent = &v
+	}
+	r.events = append(r.events, e)
+	return e, nil
+}
+
+func (r *MemoryAlertEventRepository) ListByUser(ctx context.Context, userID, ruleID int64, limit int) ([]domain.AlertEvent, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var out []domain.AlertEvent
+	for _, e := range r.events {
+		if e.UserID == userID && (ruleID <= 0 || e.RuleID == ruleID) {
+			out = append(out, e)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if !out[i].TriggeredAt.Equal(out[j].TriggeredAt) {
+			return out[i].TriggeredAt.After(out[j].TriggeredAt)
+		}
+		return out[i].ID > out[j].ID
+	})
+	if limit > 0 && len(out) > limit {
+		out = out[:limit]
+	}
+	return out, nil
+}
+
+func (r *MemoryAlertRuleRepository) deleteByUser(userID int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for k, v := range r.byID {
+		if v.UserID == userID {
+			delete(r.byID, k)
+		}
+	}
+}
+
+func (r *MemoryAlertEventRepository) deleteByUser(userID int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	kept := r.events[:0]
+	for _, e := range r.events {
+		if e.UserID != userID {
+			kept = append(kept, e)
+		}
+	}
+	r.events = kept
+}
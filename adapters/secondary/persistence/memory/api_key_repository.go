@@ -0,0 +1,139 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/moondolphin/crypto-api/domain"
+)
+
+// ErrDuplicateKeyHash emula el UNIQUE (key_hash) de api_keys.
+var ErrDuplicateKeyHash = errors.New("duplicate_key_hash")
+
+type MemoryAPIKeyRepository struct {
+	mu     sync.RWMutex
+	nextID int64
+	byID   map[int64]domain.APIKey
+}
+
+func NewMemoryAPIKeyRepository() *MemoryAPIKeyRepository {
+	return &MemoryAPIKeyRepository{byID: make(map[int64]domain.APIKey)}
+}
+
+func (r *MemoryAPIKeyRepository) Create(ctx context.Context, k domain.APIKey) (domain.APIKey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, cur := range r.byID {
+		if cur.KeyHash == k.KeyHash {
+			return domain.APIKey{}, ErrDuplicateKeyHash
+		}
+	}
+
+	r.nextID++
+	k.ID = r.nextID
+	k.CreatedAt = k.CreatedAt.UTC()
+	k.Scopes = append([]string{}, k.Scopes...)
+	k.ExpiresAt = copyTime(k.ExpiresAt)
+	k.LastUsedAt = nil
+	k.RevokedAt = nil
+	r.byID[k.ID] = k
+	return k, nil
+}
+
+func (r *MemoryAPIKeyRepository) ListByUser(ctx context.Context, userID int64) ([]domain.APIKey, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	out := make([]domain.APIKey, 0)
+	for _, k := range r.byID {
+		if k.UserID == userID && k.RevokedAt == nil {
+			out = append(out, cloneAPIKey(k))
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out, nil
+}
+
+func (r *MemoryAPIKeyRepository) FindByHash(ctx context.Context, keyHash string) (*domain.APIKey, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, k := range r.byID {
+		if k.KeyHash == keyHash {
+			c := cloneAPIKey(k)
+			return &c, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *MemoryAPIKeyRepository) Revoke(ctx context.Context, userID, id int64, at time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	k, ok := r.byID[id]
+	if !ok || k.UserID != userID || k.RevokedAt != nil {
+		return false, nil
+	}
+	k.RevokedAt = copyTime(&at)
+	r.byID[id] = k
+	return true, nil
+}
+
+func (r *MemoryAPIKeyRepository) RevokeByUser(ctx context.Context, userID int64, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for id, k := range r.byID {
+		if k.UserID != userID || k.RevokedAt != nil {
+			continue
+		}
+		k.RevokedAt = copyTime(&at)
+		r.byID[id] = k
+	}
+	return nil
+}
+
+func (r *MemoryAPIKeyRepository) TouchLastUsed(ctx context.Context, id int64, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	k, ok := r.byID[id]
+	if !ok {
+		return nil
+	}
+	k.LastUsedAt = copyTime(&at)
+	r.byID[id] = k
+	return nil
+}
+
+func cloneAPIKey(k domain.APIKey) domain.APIKey {
+	k.Scopes = append([]string{}, k.Scopes...)
+	k.ExpiresAt = copyTime(k.ExpiresAt)
+	k.LastUsedAt = copyTime(k.LastUsedAt)
+	k.RevokedAt = copyTime(k.RevokedAt)
+	return k
+}
+
+func copyTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	v := t.UTC()
+	return &v
+}
+
+func (r *MemoryAPIKeyRepository) deleteByUser(userID int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for k, v := range r.byID {
+		if v.UserID == userID {
+			delete(r.byID, k)
+		}
+	}
+}
@@ -0,0 +1,26 @@
+package memory
+
+// En memoria no hay ON DELETE CASCADE: cada repo padre borra las filas que
+// cuelgan de él en los repos que se le registran con CascadeTo, como lo hacen
+// las foreign keys de la versión SQL.
+
+// userOwned lo implementan los repos con filas que cuelgan de users.
+type userOwned interface {
+	deleteByUser(userID int64)
+}
+
+// parentOwned lo implementan los repos con filas que cuelgan de otro repo
+// (portfolio, watchlist, suscripción de webhook). parentIDs son los ids
+// borrados del padre.
+type parentOwned interface {
+	deleteByParent(parentIDs map[int64]bool)
+}
+
+func cascadeToChildren(children []parentOwned, parentIDs map[int64]bool) {
+	if len(parentIDs) == 0 {
+		return
+	}
+	for _, c := range children {
+		c.deleteByParent(parentIDs)
+	}
+}
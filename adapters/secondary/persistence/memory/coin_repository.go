@@ -0,0 +1,94 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/moondolphin/crypto-api/domain"
+)
+
+// MemoryCoinRepository es la versión en memoria (thread-safe) de CoinRepository.
+type MemoryCoinRepository struct {
+	mu     sync.RWMutex
+	nextID int64
+	bySym  map[string]domain.Coin
+}
+
+func NewMemoryCoinRepository() *MemoryCoinRepository {
+	return &MemoryCoinRepository{bySym: make(map[string]domain.Coin)}
+}
+
+func (r *MemoryCoinRepository) GetEnabledBySymbol(ctx context.Context, symbol string) (*domain.Coin, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	c, ok := r.bySym[symbol]
+	if !ok || !c.Enabled {
+		return nil, nil
+	}
+	return &c, nil
+}
+
+func (r *MemoryCoinRepository) ListEnabled(ctx context.Context) ([]domain.Coin, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	out := make([]domain.Coin, 0, len(r.bySym))
+	for _, c := range r.bySym {
+		if c.Enabled {
+			out = append(out, c)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Symbol < out[j].Symbol })
+	return out, nil
+}
+
+func (r *MemoryCoinRepository) GetBySymbol(ctx context.Context, symbol string) (*domain.Coin, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	c, ok := r.bySym[symbol]
+	if !ok {
+		return nil, nil
+	}
+	return &c, nil
+}
+
+// Upsert: mismo merge que MySQL, los IDs de proveedor vacíos no pisan los existentes.
+func (r *MemoryCoinRepository) Upsert(ctx context.Context, c domain.Coin) (*domain.Coin, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, ok := r.bySym[c.Symbol]
+	if !ok {
+		r.nextID++
+		c.ID = r.nextID
+		r.bySym[c.Symbol] = c
+		return &c, nil
+	}
+
+	existing.Enabled = c.Enabled
+	if c.CoinGeckoID != "" {
+		existing.CoinGeckoID = c.CoinGeckoID
+	}
+	if c.BinanceSymbol != "" {
+		existing.BinanceSymbol = c.BinanceSymbol
+	}
+	r.bySym[c.Symbol] = existing
+	return &existing, nil
+}
+
+// getByID lo usan los repos de favoritos, tenencias y movimientos para
+// resolver el "JOIN" con coins.
+func (r *MemoryCoinRepository) getByID(id int64) (domain.Coin, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, c := range r.bySym {
+		if c.ID == id {
+			return c, true
+		}
+	}
+	return domain.Coin{}, false
+}
@@ -0,0 +1,114 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/moondolphin/crypto-api/domain"
+)
+
+type MemoryEmailVerificationRepository struct {
+	mu     sync.RWMutex
+	nextID int64
+	byHash map[string]domain.EmailVerificationToken
+}
+
+func NewMemoryEmailVerificationRepository() *MemoryEmailVerificationRepository {
+	return &MemoryEmailVerificationRepository{byHash: make(map[string]domain.EmailVerificationToken)}
+}
+
+func (r *MemoryEmailVerificationRepository) Create(ctx context.Context, t domain.EmailVerificationToken) (domain.EmailVerificationToken, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.byHash[t.TokenHash]; ok {
+		return domain.EmailVerificationToken{}, ErrDuplicateTokenHash
+	}
+
+	r.nextID++
+	t.ID = r.nextID
+	t.ExpiresAt = t.ExpiresAt.UTC()
+	t.CreatedAt = t.CreatedAt.UTC()
+	t.UsedAt = nil
+	r.byHash[t.TokenHash] = t
+	return t, nil
+}
+
+func (r *MemoryEmailVerificationRepository) FindByHash(ctx context.Context, tokenHash string) (*domain.EmailVerificationToken, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	t, ok := r.byHash[tokenHash]
+	if !ok {
+		return nil, nil
+	}
+	if t.UsedAt != nil {
+		at := *t.UsedAt
+		t.UsedAt = &at
+	}
+	return &t, nil
+}
+
+func (r *MemoryEmailVerificationRepository) LatestByUser(ctx context.Context, userID int64) (*domain.EmailVerificationToken, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var latest *domain.EmailVerificationToken
+	for _, t := range r.byHash {
+		if t.UserID != userID {
+			continue
+		}
+		if latest == nil || t.CreatedAt.After(latest.CreatedAt) || (t.CreatedAt.Equal(latest.CreatedAt) && t.ID > latest.ID) {
+			t := t
+			t.UsedAt = copyTime(t.UsedAt)
+			latest = &t
+		}
+	}
+	return latest, nil
+}
+
+func (r *MemoryEmailVerificationRepository) MarkUsed(ctx context.Context, id int64, at time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for h, t := range r.byHash {
+		if t.ID != id {
+			continue
+		}
+		if t.UsedAt != nil {
+			return false, nil
+		}
+		usedAt := at.UTC()
+		t.UsedAt = &usedAt
+		r.byHash[h] = t
+		return true, nil
+	}
+	return false, nil
+}
+
+func (r *MemoryEmailVerificationRepository) InvalidateByUser(ctx context.Context, userID int64, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for h, t := range r.byHash {
+		if t.UserID != userID || t.UsedAt != nil {
+			continue
+		}
+		usedAt := at.UTC()
+		t.UsedAt = &usedAt
+		r.byHash[h] = t
+	}
+	return nil
+}
+
+func (r *MemoryEmailVerificationRepository) deleteByUser(userID int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for k, v := range r.byHash {
+		if v.UserID == userID {
+			delete(r.byHash, k)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/moondolphin/crypto-api/domain"
+)
+
+// MemoryFavoritesRepository trabaja sobre la watchlist por defecto del
+// usuario; la crea en la primera alta.
+type MemoryFavoritesRepository struct {
+	coins      *MemoryCoinRepository
+	watchlists *MemoryWatchlistRepository
+	entries    *MemoryWatchlistEntryRepository
+}
+
+// NewMemoryFavoritesRepository recibe el repo de coins para devolver los datos
+// actuales de cada coin (equivalente al JOIN de la versión SQL).
+func NewMemoryFavoritesRepository(coins *MemoryCoinRepository, watchlists *MemoryWatchlistRepository, entries *MemoryWatchlistEntryRepository) *MemoryFavoritesRepository {
+	return &MemoryFavoritesRepository{
+		coins:      coins,
+		watchlists: watchlists,
+		entries:    entries,
+	}
+}
+
+// Idempotente: si ya existe (user_id, coin_id)
+func (r *MemoryFavoritesRepository) AddFavoriteCoinToUser(ctx context.Context, userID, coinID int64) error {
+	now := time.Now().UTC()
+	w, err := r.watchlists.EnsureDefault(ctx, domain.Watchlist{
+		UserID:    userID,
+		Name:      domain.DefaultWatchlistName,
+		CreatedAt: now,
+		UpdatedAt: now,
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = r.entries.Add(ctx, domain.WatchlistEntry{WatchlistID: w.ID, CoinID: coinID, CreatedAt: now, UpdatedAt: now})
+	return err
+}
+
+// Idempotente: si no existe
+func (r *MemoryFavoritesRepository) RemoveFavoriteCoinFromUser(ctx context.Context, userID, coinID int64) error {
+	w, ok := r.defaultOf(userID)
+	if !ok {
+		return nil
+	}
+	_, err := r.entries.Remove(ctx, w.ID, coinID)
+	return err
+}
+
+func (r *MemoryFavoritesRepository) ListFavoriteCoinIDsByUser(ctx context.Context, userID int64) ([]domain.Coin, error) {
+	w, ok := r.defaultOf(userID)
+	if !ok {
+		return []domain.Coin{}, nil
+	}
+	entries, err := r.entries.ListByWatchlist(ctx, w.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]domain.Coin, 0, len(entries))
+	for _, e := range entries {
+		if c, ok := r.coins.getByID(e.CoinID); ok {
+			out = append(out, c)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Symbol < out[j].Symbol })
+	return out, nil
+}
+
+func (r *MemoryFavoritesRepository) defaultOf(userID int64) (domain.Watchlist, bool) {
+	r.watchlists.mu.RLock()
+	defer r.watchlists.mu.RUnlock()
+
+	return r.watchlists.defaultOf(userID)
+}
@@ -0,0 +1,90 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/moondolphin/crypto-api/domain"
+)
+
+type MemoryLoginAttemptStore struct {
+	mu    sync.Mutex
+	byKey map[string]domain.LoginAttempt
+}
+
+func NewMemoryLoginAttemptStore() *MemoryLoginAttemptStore {
+	return &MemoryLoginAttemptStore{byKey: make(map[string]domain.LoginAttempt)}
+}
+
+func (s *MemoryLoginAttemptStore) Get(ctx context.Context, key string) (*domain.LoginAttempt, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	a, ok := s.byKey[key]
+	if !ok {
+		return nil, nil
+	}
+	a.LockedUntil = copyTime(a.LockedUntil)
+	return &a, nil
+}
+
+func (s *MemoryLoginAttemptStore) RecordFailure(ctx context.Context, key string, at, resetBefore time.Time) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	a, ok := s.byKey[key]
+	if !ok {
+		a = domain.LoginAttempt{Key: key}
+	}
+	if ok {
+		last := a.LastFailureAt
+		if a.LockedUntil != nil && a.LockedUntil.After(last) {
+			last = *a.LockedUntil
+		}
+		if last.Before(resetBefore) {
+			a.Failures = 0
+		}
+	}
+
+	a.Failures++
+	a.LastFailureAt = at.UTC()
+	s.byKey[key] = a
+	return a.Failures, nil
+}
+
+func (s *MemoryLoginAttemptStore) Lock(ctx context.Context, key string, until time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	a, ok := s.byKey[key]
+	if !ok {
+		return nil
+	}
+	u := until.UTC()
+	a.LockedUntil = &u
+	s.byKey[key] = a
+	return nil
+}
+
+func (s *MemoryLoginAttemptStore) Reset(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.byKey, key)
+	return nil
+}
+
+func (s *MemoryLoginAttemptStore) DeleteStale(ctx context.Context, before time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var n int64
+	for k, a := range s.byKey {
+		if a.LastFailureAt.Before(before) && (a.LockedUntil == nil || a.LockedUntil.Before(before)) {
+			delete(s.byKey, k)
+			n++
+		}
+	}
+	return n, nil
+}
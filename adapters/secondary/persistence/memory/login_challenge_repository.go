@@ -0,0 +1,92 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/moondolphin/crypto-api/domain"
+)
+
+type MemoryLoginChallengeRepository struct {
+	mu     sync.RWMutex
+	nextID int64
+	byHash map[string]domain.LoginChallenge
+}
+
+func NewMemoryLoginChallengeRepository() *MemoryLoginChallengeRepository {
+	return &MemoryLoginChallengeRepository{byHash: make(map[string]domain.LoginChallenge)}
+}
+
+func (r *MemoryLoginChallengeRepository) Create(ctx context.Context, c domain.LoginChallenge) (domain.LoginChallenge, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.byHash[c.TokenHash]; ok {
+		return domain.LoginChallenge{}, ErrDuplicateTokenHash
+	}
+
+	r.nextID++
+	c.ID = r.nextID
+	c.ExpiresAt = c.ExpiresAt.UTC()
+	c.CreatedAt = c.CreatedAt.UTC()
+	c.UsedAt = nil
+	r.byHash[c.TokenHash] = c
+	return c, nil
+}
+
+func (r *MemoryLoginChallengeRepository) FindByHash(ctx context.Context, tokenHash string) (*domain.LoginChallenge, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	c, ok := r.byHash[tokenHash]
+	if !ok {
+		return nil, nil
+	}
+	c.UsedAt = copyTime(c.UsedAt)
+	return &c, nil
+}
+
+func (r *MemoryLoginChallengeRepository) MarkUsed(ctx context.Context, id int64, at time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for h, c := range r.byHash {
+		if c.ID != id {
+			continue
+		}
+		if c.UsedAt != nil {
+			return false, nil
+		}
+		t := at.UTC()
+		c.UsedAt = &t
+		r.byHash[h] = c
+		return true, nil
+	}
+	return false, nil
+}
+
+func (r *MemoryLoginChallengeRepository) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var n int64
+	for h, c := range r.byHash {
+		if c.ExpiresAt.Before(now) {
+			delete(r.byHash, h)
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (r *MemoryLoginChallengeRepository) deleteByUser(userID int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for k, v := range r.byHash {
+		if v.UserID == userID {
+			delete(r.byHash, k)
+		}
+	}
+}
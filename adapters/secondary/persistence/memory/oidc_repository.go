@@ -0,0 +1,152 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/moondolphin/crypto-api/domain"
+)
+
+// ErrDuplicateIdentity emula el UNIQUE (issuer, subject) de user_identities.
+var ErrDuplicateIdentity = errors.New("duplicate_identity")
+
+type MemoryUserIdentityRepository struct {
+	mu     sync.RWMutex
+	nextID int64
+	byKey  map[string]domain.UserIdentity
+}
+
+func NewMemoryUserIdentityRepository() *MemoryUserIdentityRepository {
+	return &MemoryUserIdentityRepository{byKey: make(map[string]domain.UserIdentity)}
+}
+
+func identityKey(issuer, subject string) string {
+	return issuer + "\x00" + subject
+}
+
+func (r *MemoryUserIdentityRepository) FindBySubject(ctx context.Context, issuer, subject string) (*domain.UserIdentity, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	i, ok := r.byKey[identityKey(issuer, subject)]
+	if !ok {
+		return nil, nil
+	}
+	return &i, nil
+}
+
+func (r *MemoryUserIdentityRepository) Create(ctx context.Context, i domain.UserIdentity) (domain.UserIdentity, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := identityKey(i.Issuer, i.Subject)
+	if _, ok := r.byKey[key]; ok {
+		return domain.UserIdentity{}, ErrDuplicateIdentity
+	}
+
+	r.nextID++
+	i.ID = r.nextID
+	i.CreatedAt = i.CreatedAt.UTC()
+	r.byKey[key] = i
+	return i, nil
+}
+
+func (r *MemoryUserIdentityRepository) ListByUser(ctx context.Context, userID int64) ([]domain.UserIdentity, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var out []domain.UserIdentity
+	for _, i := range r.byKey {
+		if i.UserID == userID {
+			out = append(out, i)
+		}
+	}
+	sort.Slice(out, func(a, b int) bool { return out[a].ID < out[b].ID })
+	return out, nil
+}
+
+type MemoryOIDCStateRepository struct {
+	mu     sync.RWMutex
+	nextID int64
+	byHash map[string]domain.OIDCLoginState
+}
+
+func NewMemoryOIDCStateRepository() *MemoryOIDCStateRepository {
+	return &MemoryOIDCStateRepository{byHash: make(map[string]domain.OIDCLoginState)}
+}
+
+func (r *MemoryOIDCStateRepository) Create(ctx context.Context, s domain.OIDCLoginState) (domain.OIDCLoginState, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.byHash[s.StateHash]; ok {
+		return domain.OIDCLoginState{}, ErrDuplicateTokenHash
+	}
+
+	r.nextID++
+	s.ID = r.nextID
+	s.ExpiresAt = s.ExpiresAt.UTC()
+	s.CreatedAt = s.CreatedAt.UTC()
+	s.UsedAt = nil
+	r.byHash[s.StateHash] = s
+	return s, nil
+}
+
+func (r *MemoryOIDCStateRepository) FindByHash(ctx context.Context, stateHash string) (*domain.OIDCLoginState, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	s, ok := r.byHash[stateHash]
+	if !ok {
+		return nil, nil
+	}
+	s.UsedAt = copyTime(s.UsedAt)
+	return &s, nil
+}
+
+func (r *MemoryOIDCStateRepository) MarkUsed(ctx context.Context, id int64, at time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for h, s := range r.byHash {
+		if s.ID != id {
+			continue
+		}
+		if s.UsedAt != nil {
+			return false, nil
+		}
+		t := at.UTC()
+		s.UsedAt = &t
+		r.byHash[h] = s
+		return true, nil
+	}
+	return false, nil
+}
+
+func (r *MemoryOIDCStateRepository) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var n int64
+	for h, s := range r.byHash {
+		if s.ExpiresAt.Before(now) {
+			delete(r.byHash, h)
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (r *MemoryUserIdentityRepository) deleteByUser(userID int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for k, v := range r.byKey {
+		if v.UserID == userID {
+			delete(r.byKey, k)
+		}
+	}
+}
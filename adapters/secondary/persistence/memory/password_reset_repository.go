@@ -0,0 +1,96 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/moondolphin/crypto-api/domain"
+)
+
+type MemoryPasswordResetRepository struct {
+	mu     sync.RWMutex
+	nextID int64
+	byHash map[string]domain.PasswordResetToken
+}
+
+func NewMemoryPasswordResetRepository() *MemoryPasswordResetRepository {
+	return &MemoryPasswordResetRepository{byHash: make(map[string]domain.PasswordResetToken)}
+}
+
+func (r *MemoryPasswordResetRepository) Create(ctx context.Context, t domain.PasswordResetToken) (domain.PasswordResetToken, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.byHash[t.TokenHash]; ok {
+		return domain.PasswordResetToken{}, ErrDuplicateTokenHash
+	}
+
+	r.nextID++
+	t.ID = r.nextID
+	t.ExpiresAt = t.ExpiresAt.UTC()
+	t.CreatedAt = t.CreatedAt.UTC()
+	t.UsedAt = nil
+	r.byHash[t.TokenHash] = t
+	return t, nil
+}
+
+func (r *MemoryPasswordResetRepository) FindByHash(ctx context.Context, tokenHash string) (*domain.PasswordResetToken, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	t, ok := r.byHash[tokenHash]
+	if !ok {
+		return nil, nil
+	}
+	if t.UsedAt != nil {
+		at := *t.UsedAt
+		t.UsedAt = &at
+	}
+	return &t, nil
+}
+
+func (r *MemoryPasswordResetRepository) MarkUsed(ctx context.Context, id int64, at time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for h, t := range r.byHash {
+		if t.ID != id {
+			continue
+		}
+		if t.UsedAt != nil {
+			return false, nil
+		}
+		usedAt := at.UTC()
+		t.UsedAt = &usedAt
+		r.byHash[h] = t
+		return true, nil
+	}
+	return false, nil
+}
+
+func (r *MemoryPasswordResetRepository) InvalidateByUser(ctx context.Context, userID int64, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for h, t := range r.byHash {
+		if t.UserID != userID || t.UsedAt != nil {
+			continue
+		}
+		usedAt := at.UTC()
+		t.UsedAt = &usedAt
+		r.byHash[h] = t
+	}
+	return nil
+}
+
+func (r *MemoryPasswordResetRepository) deleteByUser(userID int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for k, v := range r.byHash {
+		if v.UserID == userID {
+			delete(r.byHash, k)
+		}
+	}
+}
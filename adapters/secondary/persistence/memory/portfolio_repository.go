@@ -0,0 +1,212 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/moondolphin/crypto-api/domain"
+)
+
+type MemoryPortfolioRepository struct {
+	mu       sync.RWMutex
+	nextID   int64
+	byID     map[int64]domain.Portfolio
+	children []parentOwned
+}
+
+func NewMemoryPortfolioRepository() *MemoryPortfolioRepository {
+	return &MemoryPortfolioRepository{byID: make(map[int64]domain.Portfolio)}
+}
+
+func (r *MemoryPortfolioRepository) Create(ctx context.Context, p domain.Portfolio) (domain.Portfolio, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.nextID++
+	p.ID = r.nextID
+	p.CreatedAt = p.CreatedAt.UTC()
+	p.UpdatedAt = p.UpdatedAt.UTC()
+	r.byID[p.ID] = p
+	return p, nil
+}
+
+func (r *MemoryPortfolioRepository) FindByUser(ctx context.Context, userID, id int64) (*domain.Portfolio, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	p, ok := r.byID[id]
+	if !ok || p.UserID != userID {
+		return nil, nil
+	}
+	return &p, nil
+}
+
+func (r *MemoryPortfolioRepository) ListByUser(ctx context.Context, userID int64) ([]domain.Portfolio, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var out []domain.Portfolio
+	for _, p := range r.byID {
+		if p.UserID == userID {
+			out = append(out, p)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out, nil
+}
+
+func (r *MemoryPortfolioRepository) CountByUser(ctx context.Context, userID int64) (int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	n := 0
+	for _, p := range r.byID {
+		if p.UserID == userID {
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (r *MemoryPortfolioRepository) Update(ctx context.Context, p domain.Portfolio) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	cur, ok := r.byID[p.ID]
+	if !ok || cur.UserID != p.UserID {
+		return false, nil
+	}
+	cur.Name = p.Name
+	cur.BaseCurrency = p.BaseCurrency
+	cur.UpdatedAt = p.UpdatedAt.UTC()
+	r.byID[p.ID] = cur
+	return true, nil
+}
+
+func (r *MemoryPortfolioRepository) Delete(ctx context.Context, userID, id int64) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	p, ok := r.byID[id]
+	if !ok || p.UserID != userID {
+		return false, nil
+	}
+	delete(r.byID, id)
+	cascadeToChildren(r.children, map[int64]bool{id: true})
+	return true, nil
+}
+
+// CascadeTo registra los repos cuyas filas borra junto con cada portfolio.
+func (r *MemoryPortfolioRepository) CascadeTo(children ...parentOwned) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.children = append(r.children, children...)
+}
+
+func (r *MemoryPortfolioRepository) deleteByUser(userID int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	ids := make(map[int64]bool)
+	for id, v := range r.byID {
+		if v.UserID == userID {
+			delete(r.byID, id)
+			ids[id] = true
+		}
+	}
+	cascadeToChildren(r.children, ids)
+}
+
+type holdingKey struct {
+	portfolioID int64
+	coinID      int64
+}
+
+type MemoryHoldingRepository struct {
+	mu     sync.RWMutex
+	coins  *MemoryCoinRepository
+	nextID int64
+	byKey  map[holdingKey]domain.Holding
+}
+
+// NewMemoryHoldingRepository recibe el repo de coins para completar el símbolo
+// (equivalente al JOIN de la versión SQL).
+func NewMemoryHoldingRepository(coins *MemoryCoinRepository) *MemoryHoldingRepository {
+	return &MemoryHoldingRepository{
+		coins: coins,
+		byKey: make(map[holdingKey]domain.Holding),
+	}
+}
+
+func (r *MemoryHoldingRepository) withSymbol(h domain.Holding) domain.Holding {
+	if c, ok := r.coins.getByID(h.CoinID); ok {
+		h.Symbol = c.Symbol
+	}
+	return h
+}
+
+func (r *MemoryHoldingRepository) Upsert(ctx context.Context, h domain.Holding) (domain.Holding, error) {
+	r.mu.Lock()
+	k := holdingKey{portfolioID: h.PortfolioID, coinID: h.CoinID}
+	cur, ok := r.byKey[k]
+	if ok {
+		cur.Quantity = h.Quantity
+		cur.UpdatedAt = h.UpdatedAt.UTC()
+	} else {
+		r.nextID++
+		cur = domain.Holding{
+			ID:          r.nextID,
+			PortfolioID: h.PortfolioID,
+			CoinID:      h.CoinID,
+			Quantity:    h.Quantity,
+			CreatedAt:   h.CreatedAt.UTC(),
+			UpdatedAt:   h.UpdatedAt.UTC(),
+		}
+	}
+	r.byKey[k] = cur
+	r.mu.Unlock()
+
+	return r.withSymbol(cur), nil
+}
+
+func (r *MemoryHoldingRepository) ListByPortfolio(ctx context.Context, portfolioID int64) ([]domain.Holding, error) {
+	r.mu.RLock()
+	var out []domain.Holding
+	for k, h := range r.byKey {
+		if k.portfolioID == portfolioID {
+			out = append(out, h)
+		}
+	}
+	r.mu.RUnlock()
+
+	for i := range out {
+		out[i] = r.withSymbol(out[i])
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Symbol < out[j].Symbol })
+	return out, nil
+}
+
+func (r *MemoryHoldingRepository) Delete(ctx context.Context, portfolioID, coinID int64) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	k := holdingKey{portfolioID: portfolioID, coinID: coinID}
+	if _, ok := r.byKey[k]; !ok {
+		return false, nil
+	}
+	delete(r.byKey, k)
+	return true, nil
+}
+
+func (r *MemoryHoldingRepository) deleteByParent(portfolioIDs map[int64]bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for k := range r.byKey {
+		if portfolioIDs[k.portfolioID] {
+			delete(r.byKey, k)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/moondolphin/crypto-api/domain"
+)
+
+type snapshotKey struct {
+	portfolioID int64
+	day         time.Time
+	currency    string
+}
+
+type MemoryPortfolioSnapshotRepository struct {
+	mu    sync.RWMutex
+	byKey map[snapshotKey]domain.PortfolioSnapshot
+}
+
+func NewMemoryPortfolioSnapshotRepository() *MemoryPortfolioSnapshotRepository {
+	return &MemoryPortfolioSnapshotRepository{byKey: make(map[snapshotKey]domain.PortfolioSnapshot)}
+}
+
+func (r *MemoryPortfolioSnapshotRepository) Upsert(ctx context.Context, s domain.PortfolioSnapshot) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	s.Day = s.Day.UTC()
+	s.ComputedAt = s.ComputedAt.UTC()
+	r.byKey[snapshotKey{portfolioID: s.PortfolioID, day: s.Day, currency: s.Currency}] = s
+	return nil
+}
+
+func (r *MemoryPortfolioSnapshotRepository) ListRange(ctx context.Context, portfolioID int64, currency string, from, to time.Time) ([]domain.PortfolioSnapshot, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var out []domain.PortfolioSnapshot
+	for k, s := range r.byKey {
+		if k.portfolioID == portfolioID && k.currency == currency && !k.day.Before(from) && !k.day.After(to) {
+			out = append(out, s)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Day.Before(out[j].Day) })
+	return out, nil
+}
+
+func (r *MemoryPortfolioSnapshotRepository) DeleteFrom(ctx context.Context, portfolioID int64, from time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for k := range r.byKey {
+		if k.portfolioID == portfolioID && !k.day.Before(from) {
+			delete(r.byKey, k)
+		}
+	}
+	return nil
+}
+
+func (r *MemoryPortfolioSnapshotRepository) deleteByParent(portfolioIDs map[int64]bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for k := range r.byKey {
+		if portfolioIDs[k.portfolioID] {
+			delete(r.byKey, k)
+		}
+	}
+}
@@ -0,0 +1,305 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/moondolphin/crypto-api/domain"
+)
+
+type MemoryQuoteRepository struct {
+	mu     sync.RWMutex
+	nextID int64
+	quotes []domain.Quote
+	Now    func() time.Time
+}
+
+func NewMemoryQuoteRepository() *MemoryQuoteRepository {
+	return &MemoryQuoteRepository{}
+}
+
+func (r *MemoryQuoteRepository) Insert(ctx context.Context, q domain.Quote) (bool, error) {
+	now := r.Now
+	if now == nil {
+		now = time.Now
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// misma unicidad que uk_quotes_coin_provider_currency_time
+	for _, e := range r.quotes {
+		if e.CoinID == q.CoinID && e.Provider == q.Provider && e.Currency == q.Currency && e.QuotedAt.Equal(q.QuotedAt) {
+			return false, nil
+		}
+	}
+
+	r.nextID++
+	q.ID = r.nextID
+	q.QuotedAt = q.QuotedAt.UTC()
+	q.CreatedAt = now().UTC()
+	r.quotes = append(r.quotes, q)
+	return true, nil
+}
+
+func (r *MemoryQuoteRepository) GetLatest(ctx context.Context, symbol, provider, currency string) (*domain.PriceQuote, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var latest *domain.Quote
+	for i := range r.quotes {
+		q := &r.quotes[i]
+		if q.Symbol != symbol {
+			continue
+		}
+		if provider != "" && q.Provider != provider {
+			continue
+		}
+		if currency != "" && q.Currency != currency {
+			continue
+		}
+		if latest == nil || q.QuotedAt.After(latest.QuotedAt) {
+			latest = q
+		}
+	}
+	if latest == nil {
+		return nil, nil
+	}
+
+	return &domain.PriceQuote{
+		Symbol:    latest.Symbol,
+		Currency:  latest.Currency,
+		Price:     latest.Price,
+		Provider:  latest.Provider,
+		Timestamp: latest.QuotedAt.Format(time.RFC3339Nano),
+	}, nil
+}
+
+// matches aplica el mismo tamiz que buildQuoteWhere en los adapters SQL.
+func matches(q domain.Quote, f domain.QuoteFilter) bool {
+	if f.Symbol != "" && q.Symbol != f.Symbol {
+		return false
+	}
+	if f.Provider != "" && q.Provider != f.Provider {
+		return false
+	}
+	if f.Currency != "" && q.Currency != f.Currency {
+		return false
+	}
+	if f.From != nil && q.QuotedAt.Before(*f.From) {
+		return false
+	}
+	if f.To != nil && q.QuotedAt.After(*f.To) {
+		return false
+	}
+	if f.MinPrice != nil || f.MaxPrice != nil {
+		p, err := strconv.ParseFloat(q.Price, 64)
+		if err != nil {
+			return false
+		}
+		if f.MinPrice != nil && p < *f.MinPrice {
+			return false
+		}
+		if f.MaxPrice != nil && p > *f.MaxPrice {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *MemoryQuoteRepository) filtered(f domain.QuoteFilter) []domain.Quote {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	out := make([]domain.Quote, 0, len(r.quotes))
+	for _, q := range r.quotes {
+		if matches(q, f) {
+			out = append(out, q)
+		}
+	}
+	return out
+}
+
+func (r *MemoryQuoteRepository) ListFilter(ctx context.Context, f domain.QuoteFilter) ([]domain.Quote, int, error) {
+	// defaults defensivos
+	page := f.Page
+	if page <= 0 {
+		page = 1
+	}
+	pageSize := f.PageSize
+	if pageSize <= 0 {
+		pageSize = 50
+	}
+	if pageSize > 100 {
+		pageSize = 100
+	}
+	offset := (page - 1) * pageSize
+
+	all := r.filtered(f)
+	sort.SliceStable(all, func(i, j int) bool { return all[i].QuotedAt.After(all[j].QuotedAt) })
+
+	total := len(all)
+	if offset >= total {
+		return []domain.Quote{}, total, nil
+	}
+	end := offset + pageSize
+	if end > total {
+		end = total
+	}
+	return all[offset:end], total, nil
+}
+
+func (r *MemoryQuoteRepository) ListAvailableFilters(ctx context.Context, f domain.QuoteFilter) (domain.QuoteFilters, error) {
+	all := r.filtered(f)
+
+	distinct := func(get func(domain.Quote) string) []string {
+		seen := make(map[string]struct{}, 16)
+		out := make([]string, 0, 16)
+		for _, q := range all {
+			v := get(q)
+			if v == "" {
+				continue
+			}
+			if _, ok := seen[v]; ok {
+				continue
+			}
+			seen[v] = struct{}{}
+			out = append(out, v)
+		}
+		sort.Strings(out)
+		return out
+	}
+
+	out := domain.QuoteFilters{
+		Symbols:    distinct(func(q domain.Quote) string { return q.Symbol }),
+		Providers:  distinct(func(q domain.Quote) string { return q.Provider }),
+		Currencies: distinct(func(q domain.Quote) string { return q.Currency }),
+	}
+
+	var minP, maxP float64
+	for i, q := range all {
+		p, _ := strconv.ParseFloat(q.Price, 64)
+		if i == 0 || p < minP {
+			minP = p
+			out.MinPrice = q.Price
+		}
+		if i == 0 || p > maxP {
+			maxP = p
+			out.MaxPrice = q.Price
+		}
+
+		t := q.QuotedAt
+		if out.From == nil || t.Before(*out.From) {
+			out.From = &t
+		}
+		if out.To == nil || t.After(*out.To) {
+			out.To = &t
+		}
+	}
+
+	return out, nil
+}
+
+func (r *MemoryQuoteRepository) ListDailyCloses(ctx context.Context, f domain.DailyCloseFilter) ([]domain.DailyClose, error) {
+	symbols := make(map[string]bool, len(f.Symbols))
+	for _, s := range f.Symbols {
+		symbols[s] = true
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	type key struct {
+		symbol string
+		day    time.Time
+	}
+	last := make(map[key]domain.Quote)
+	for _, q := range r.quotes {
+		if !symbols[q.Symbol] || q.Currency != f.Currency {
+			continue
+		}
+		if f.Provider != "" && q.Provider != f.Provider {
+			continue
+		}
+		if q.QuotedAt.Before(f.From) || !q.QuotedAt.Before(f.To) {
+			continue
+		}
+
+		k := key{symbol: q.Symbol, day: q.QuotedAt.Truncate(24 * time.Hour)}
+		cur, ok := last[k]
+		if !ok || q.QuotedAt.After(cur.QuotedAt) || (q.QuotedAt.Equal(cur.QuotedAt) && q.Provider < cur.Provider) {
+			last[k] = q
+		}
+	}
+
+	out := make([]domain.DailyClose, 0, len(last))
+	for k, q := range last {
+		out = append(out, domain.DailyClose{Symbol: k.symbol, Day: k.day, Provider: q.Provider, Price: q.Price, QuotedAt: q.QuotedAt})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Symbol != out[j].Symbol {
+			return out[i].Symbol < out[j].Symbol
+		}
+		return out[i].Day.Before(out[j].Day)
+	})
+	return out, nil
+}
+
+func (r *MemoryQuoteRepository) ListTickers(ctx context.Context, f domain.QuoteTickerFilter) ([]domain.QuoteTicker, error) {
+	symbols := make(map[string]bool, len(f.Symbols))
+	for _, s := range f.Symbols {
+		symbols[s] = true
+	}
+	pairs := make(map[domain.ProviderCurrency]bool, len(f.Pairs))
+	for _, p := range f.Pairs {
+		pairs[p] = true
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	type key struct {
+		symbol string
+		pair   domain.ProviderCurrency
+	}
+	last := make(map[key]domain.Quote)
+	prev := make(map[key]domain.Quote)
+	for _, q := range r.quotes {
+		k := key{symbol: q.Symbol, pair: domain.ProviderCurrency{Provider: q.Provider, Currency: q.Currency}}
+		if !symbols[k.symbol] || (len(pairs) > 0 && !pairs[k.pair]) {
+			continue
+		}
+		if cur, ok := last[k]; !ok || q.QuotedAt.After(cur.QuotedAt) {
+			last[k] = q
+		}
+		if q.QuotedAt.After(f.PrevAt) {
+			continue
+		}
+		if cur, ok := prev[k]; !ok || q.QuotedAt.After(cur.QuotedAt) {
+			prev[k] = q
+		}
+	}
+
+	out := make([]domain.QuoteTicker, 0, len(last))
+	for k, q := range last {
+		t := domain.QuoteTicker{Symbol: k.symbol, Provider: k.pair.Provider, Currency: k.pair.Currency, Price: q.Price, QuotedAt: q.QuotedAt}
+		if p, ok := prev[k]; ok {
+			t.PrevPrice = p.Price
+			t.PrevQuotedAt = p.QuotedAt
+		}
+		out = append(out, t)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Symbol != out[j].Symbol {
+			return out[i].Symbol < out[j].Symbol
+		}
+		if out[i].Provider != out[j].Provider {
+			return out[i].Provider < out[j].Provider
+		}
+		return out[i].Currency < out[j].Currency
+	})
+	return out, nil
+}
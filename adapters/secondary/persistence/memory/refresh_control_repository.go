@@ -0,0 +1,34 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type MemoryRefreshControlRepository struct {
+	mu   sync.RWMutex
+	last time.Time
+	ok   bool
+}
+
+func NewMemoryRefreshControlRepository() *MemoryRefreshControlRepository {
+	return &MemoryRefreshControlRepository{}
+}
+
+func (r *MemoryRefreshControlRepository) GetLastManualRefresh(ctx context.Context) (time.Time, bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.last, r.ok, nil
+}
+
+// Se guarda con precisión de segundos, igual que el RFC3339 persistido en SQL.
+func (r *MemoryRefreshControlRepository) SetLastManualRefresh(ctx context.Context, t time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.last = t.UTC().Truncate(time.Second)
+	r.ok = true
+	return nil
+}
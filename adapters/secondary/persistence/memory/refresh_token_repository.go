@@ -0,0 +1,130 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/moondolphin/crypto-api/domain"
+)
+
+// ErrDuplicateTokenHash emula el UNIQUE (token_hash) de refresh_tokens.
+var ErrDuplicateTokenHash = errors.New("duplicate_token_hash")
+
+type MemoryRefreshTokenRepository struct {
+	mu     sync.RWMutex
+	nextID int64
+	byHash map[string]domain.RefreshToken
+}
+
+func NewMemoryRefreshTokenRepository() *MemoryRefreshTokenRepository {
+	return &MemoryRefreshTokenRepository{byHash: make(map[string]domain.RefreshToken)}
+}
+
+func (r *MemoryRefreshTokenRepository) Create(ctx context.Context, t domain.RefreshToken) (domain.RefreshToken, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.byHash[t.TokenHash]; ok {
+		return domain.RefreshToken{}, ErrDuplicateTokenHash
+	}
+
+	r.nextID++
+	t.ID = r.nextID
+	t.ExpiresAt = t.ExpiresAt.UTC()
+	t.CreatedAt = t.CreatedAt.UTC()
+	t.RevokedAt = nil
+	r.byHash[t.TokenHash] = t
+	return t, nil
+}
+
+func (r *MemoryRefreshTokenRepository) FindByHash(ctx context.Context, tokenHash string) (*domain.RefreshToken, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	t, ok := r.byHash[tokenHash]
+	if !ok {
+		return nil, nil
+	}
+	if t.RevokedAt != nil {
+		at := *t.RevokedAt
+		t.RevokedAt = &at
+	}
+	return &t, nil
+}
+
+func (r *MemoryRefreshTokenRepository) Revoke(ctx context.Context, id int64, at time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for h, t := range r.byHash {
+		if t.ID != id {
+			continue
+		}
+		if t.RevokedAt != nil {
+			return false, nil
+		}
+		revokedAt := at.UTC()
+		t.RevokedAt = &revokedAt
+		r.byHash[h] = t
+		return true, nil
+	}
+	return false, nil
+}
+
+func (r *MemoryRefreshTokenRepository) RevokeFamily(ctx context.Context, familyID string, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for h, t := range r.byHash {
+		if t.FamilyID != familyID || t.RevokedAt != nil {
+			continue
+		}
+		revokedAt := at.UTC()
+		t.RevokedAt = &revokedAt
+		r.byHash[h] = t
+	}
+	return nil
+}
+
+func (r *MemoryRefreshTokenRepository) RevokeByUser(ctx context.Context, userID int64, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for h, t := range r.byHash {
+		if t.UserID != userID || t.RevokedAt != nil {
+			continue
+		}
+		revokedAt := at.UTC()
+		t.RevokedAt = &revokedAt
+		r.byHash[h] = t
+	}
+	return nil
+}
+
+func (r *MemoryRefreshTokenRepository) ListActiveByUser(ctx context.Context, userID int64, now time.Time) ([]domain.RefreshToken, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var out []domain.RefreshToken
+	for _, t := range r.byHash {
+		if t.UserID == userID && !t.IsRevoked() && !t.IsExpired(now) {
+			out = append(out, t)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out, nil
+}
+
+func (r *MemoryRefreshTokenRepository) deleteByUser(userID int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for k, v := range r.byHash {
+		if v.UserID == userID {
+			delete(r.byHash, k)
+		}
+	}
+}
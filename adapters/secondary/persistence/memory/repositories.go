@@ -0,0 +1,63 @@
+package memory
+
+import "github.com/moondolphin/crypto-api/domain"
+
+// NewRepositories arma todos los puertos en memoria sobre el mismo storage, con
+// los borrados en cascada registrados como las foreign keys de la versión SQL.
+func NewRepositories() domain.Repositories {
+	coins := NewMemoryCoinRepository()
+	users := NewMemoryUserRepository()
+	refreshTokens := NewMemoryRefreshTokenRepository()
+	apiKeys := NewMemoryAPIKeyRepository()
+	resets := NewMemoryPasswordResetRepository()
+	verifications := NewMemoryEmailVerificationRepository()
+	totp := NewMemoryTOTPRepository()
+	recovery := NewMemoryRecoveryCodeRepository()
+	challenges := NewMemoryLoginChallengeRepository()
+	identities := NewMemoryUserIdentityRepository()
+	alertRules := NewMemoryAlertRuleRepository()
+	alertEvents := NewMemoryAlertEventRepository()
+	webhooks := NewMemoryWebhookSubscriptionRepository()
+	deliveries := NewMemoryWebhookDeliveryRepository()
+	portfolios := NewMemoryPortfolioRepository()
+	holdings := NewMemoryHoldingRepository(coins)
+	transactions := NewMemoryTransactionRepository(coins)
+	snapshots := NewMemoryPortfolioSnapshotRepository()
+	watchlists := NewMemoryWatchlistRepository()
+	entries := NewMemoryWatchlistEntryRepository(coins)
+
+	users.CascadeTo(refreshTokens, apiKeys, resets, verifications, totp, recovery, challenges,
+		identities, alertRules, alertEvents, webhooks, portfolios, watchlists)
+	webhooks.CascadeTo(deliveries)
+	portfolios.CascadeTo(holdings, transactions, snapshots)
+	watchlists.CascadeTo(entries)
+
+	return domain.Repositories{
+		Coins:            coins,
+		Quotes:           NewMemoryQuoteRepository(),
+		Users:            users,
+		Favorites:        NewMemoryFavoritesRepository(coins, watchlists, entries),
+		RefreshControl:   NewMemoryRefreshControlRepository(),
+		RefreshTokens:    refreshTokens,
+		Revocations:      NewMemoryTokenRevocationStore(),
+		APIKeys:          apiKeys,
+		PasswordResets:   resets,
+		Verifications:    verifications,
+		LoginAttempts:    NewMemoryLoginAttemptStore(),
+		TOTP:             totp,
+		RecoveryCodes:    recovery,
+		Challenges:       challenges,
+		Identities:       identities,
+		OIDCStates:       NewMemoryOIDCStateRepository(),
+		AlertRules:       alertRules,
+		AlertEvents:      alertEvents,
+		Webhooks:         webhooks,
+		Deliveries:       deliveries,
+		Portfolios:       portfolios,
+		Holdings:         holdings,
+		Transactions:     transactions,
+		Snapshots:        snapshots,
+		Watchlists:       watchlists,
+		WatchlistEntries: entries,
+	}
+}
@@ -0,0 +1,14 @@
+package memory_test
+
+import (
+	"testing"
+
+	"github.com/moondolphin/crypto-api/adapters/secondary/persistence/memory"
+	"github.com/moondolphin/crypto-api/test/contract"
+)
+
+func TestMemoryRepositories_Contract(t *testing.T) {
+	contract.RunRepositoryContract(t, func(t *testing.T) contract.Repositories {
+		return memory.NewRepositories()
+	})
+}
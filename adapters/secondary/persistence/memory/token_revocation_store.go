@@ -0,0 +1,88 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type userCutoff struct {
+	revokedBefore time.Time
+	expiresAt     time.Time
+}
+
+type MemoryTokenRevocationStore struct {
+	mu    sync.RWMutex
+	jtis  map[string]time.Time // jti -> expiresAt
+	users map[int64]userCutoff
+}
+
+func NewMemoryTokenRevocationStore() *MemoryTokenRevocationStore {
+	return &MemoryTokenRevocationStore{
+		jtis:  make(map[string]time.Time),
+		users: make(map[int64]userCutoff),
+	}
+}
+
+func (s *MemoryTokenRevocationStore) RevokeJTI(ctx context.Context, jti string, expiresAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if cur, ok := s.jtis[jti]; !ok || expiresAt.After(cur) {
+		s.jtis[jti] = expiresAt.UTC()
+	}
+	return nil
+}
+
+func (s *MemoryTokenRevocationStore) RevokeUser(ctx context.Context, userID int64, issuedBefore, expiresAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	next := userCutoff{
+		revokedBefore: issuedBefore.UTC().Truncate(time.Second),
+		expiresAt:     expiresAt.UTC(),
+	}
+	if cur, ok := s.users[userID]; ok {
+		if cur.revokedBefore.After(next.revokedBefore) {
+			next.revokedBefore = cur.revokedBefore
+		}
+		if cur.expiresAt.After(next.expiresAt) {
+			next.expiresAt = cur.expiresAt
+		}
+	}
+	s.users[userID] = next
+	return nil
+}
+
+func (s *MemoryTokenRevocationStore) IsRevoked(ctx context.Context, jti string, userID int64, issuedAt time.Time) (bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if _, ok := s.jtis[jti]; ok {
+		return true, nil
+	}
+	if cut, ok := s.users[userID]; ok && !cut.revokedBefore.Before(issuedAt.UTC().Truncate(time.Second)) {
+		return true, nil
+	}
+	return false, nil
+}
+
+func (s *MemoryTokenRevocationStore) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var n int64
+	for jti, exp := range s.jtis {
+		if !exp.After(now) {
+			delete(s.jtis, jti)
+			n++
+		}
+	}
+	for uid, cut := range s.users {
+		if !cut.expiresAt.After(now) {
+			delete(s.users, uid)
+			n++
+		}
+	}
+	return n, nil
+}
@@ -0,0 +1,128 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/moondolphin/crypto-api/domain"
+)
+
+type MemoryTransactionRepository struct {
+	mu     sync.RWMutex
+	coins  *MemoryCoinRepository
+	nextID int64
+	byID   map[int64]domain.Transaction
+}
+
+// NewMemoryTransactionRepository recibe el repo de coins para completar el
+// símbolo (equivalente al JOIN de la versión SQL).
+func NewMemoryTransactionRepository(coins *MemoryCoinRepository) *MemoryTransactionRepository {
+	return &MemoryTransactionRepository{
+		coins: coins,
+		byID:  make(map[int64]domain.Transaction),
+	}
+}
+
+func (r *MemoryTransactionRepository) withSymbol(t domain.Transaction) domain.Transaction {
+	if c, ok := r.coins.getByID(t.CoinID); ok {
+		t.Symbol = c.Symbol
+	}
+	return t
+}
+
+func (r *MemoryTransactionRepository) Create(ctx context.Context, t domain.Transaction) (domain.Transaction, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.create(t), nil
+}
+
+func (r *MemoryTransactionRepository) CreateChecked(ctx context.Context, t domain.Transaction, check func(existing []domain.Transaction) error) (domain.Transaction, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := check(r.list(t.PortfolioID, domain.TransactionFilter{CoinID: t.CoinID})); err != nil {
+		return domain.Transaction{}, err
+	}
+	return r.create(t), nil
+}
+
+// create y list asumen r.mu tomado.
+func (r *MemoryTransactionRepository) create(t domain.Transaction) domain.Transaction {
+	r.nextID++
+	t.ID = r.nextID
+	t.ExecutedAt = t.ExecutedAt.UTC()
+	t.CreatedAt = t.CreatedAt.UTC()
+	r.byID[t.ID] = t
+	return t
+}
+
+func (r *MemoryTransactionRepository) FindByPortfolio(ctx context.Context, portfolioID, id int64) (*domain.Transaction, error) {
+	r.mu.RLock()
+	t, ok := r.byID[id]
+	r.mu.RUnlock()
+
+	if !ok || t.PortfolioID != portfolioID {
+		return nil, nil
+	}
+	t = r.withSymbol(t)
+	return &t, nil
+}
+
+func (r *MemoryTransactionRepository) ListByPortfolio(ctx context.Context, portfolioID int64, f domain.TransactionFilter) ([]domain.Transaction, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.list(portfolioID, f), nil
+}
+
+func (r *MemoryTransactionRepository) list(portfolioID int64, f domain.TransactionFilter) []domain.Transaction {
+	var out []domain.Transaction
+	for _, t := range r.byID {
+		if t.PortfolioID != portfolioID {
+			continue
+		}
+		if f.CoinID > 0 && t.CoinID != f.CoinID {
+			continue
+		}
+		if f.From != nil && t.ExecutedAt.Before(*f.From) {
+			continue
+		}
+		if f.To != nil && !t.ExecutedAt.Before(*f.To) {
+			continue
+		}
+		out = append(out, r.withSymbol(t))
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		if !out[i].ExecutedAt.Equal(out[j].ExecutedAt) {
+			return out[i].ExecutedAt.Before(out[j].ExecutedAt)
+		}
+		return out[i].ID < out[j].ID
+	})
+	return out
+}
+
+func (r *MemoryTransactionRepository) Delete(ctx context.Context, portfolioID, id int64) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	t, ok := r.byID[id]
+	if !ok || t.PortfolioID != portfolioID {
+		return false, nil
+	}
+	delete(r.byID, id)
+	return true, nil
+}
+
+func (r *MemoryTransactionRepository) deleteByParent(portfolioIDs map[int64]bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for id, t := range r.byID {
+		if portfolioIDs[t.PortfolioID] {
+			delete(r.byID, id)
+		}
+	}
+}
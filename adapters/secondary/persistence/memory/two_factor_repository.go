@@ -0,0 +1,137 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/moondolphin/crypto-api/domain"
+)
+
+type MemoryTOTPRepository struct {
+	mu     sync.RWMutex
+	byUser map[int64]domain.TOTPEnrollment
+}
+
+func NewMemoryTOTPRepository() *MemoryTOTPRepository {
+	return &MemoryTOTPRepository{byUser: make(map[int64]domain.TOTPEnrollment)}
+}
+
+func (r *MemoryTOTPRepository) Get(ctx context.Context, userID int64) (*domain.TOTPEnrollment, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	e, ok := r.byUser[userID]
+	if !ok {
+		return nil, nil
+	}
+	e.ConfirmedAt = copyTime(e.ConfirmedAt)
+	return &e, nil
+}
+
+func (r *MemoryTOTPRepository) Save(ctx context.Context, e domain.TOTPEnrollment) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	e.CreatedAt = e.CreatedAt.UTC()
+	e.ConfirmedAt = nil
+	e.LastUsedStep = 0
+	r.byUser[e.UserID] = e
+	return nil
+}
+
+func (r *MemoryTOTPRepository) Confirm(ctx context.Context, userID int64, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	e, ok := r.byUser[userID]
+	if !ok {
+		return nil
+	}
+	t := at.UTC()
+	e.ConfirmedAt = &t
+	r.byUser[userID] = e
+	return nil
+}
+
+func (r *MemoryTOTPRepository) MarkStepUsed(ctx context.Context, userID int64, step int64) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	e, ok := r.byUser[userID]
+	if !ok || e.LastUsedStep >= step {
+		return false, nil
+	}
+	e.LastUsedStep = step
+	r.byUser[userID] = e
+	return true, nil
+}
+
+func (r *MemoryTOTPRepository) Delete(ctx context.Context, userID int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.byUser, userID)
+	return nil
+}
+
+type MemoryRecoveryCodeRepository struct {
+	mu sync.RWMutex
+	// user_id -> code_hash -> usado
+	byUser map[int64]map[string]bool
+}
+
+func NewMemoryRecoveryCodeRepository() *MemoryRecoveryCodeRepository {
+	return &MemoryRecoveryCodeRepository{byUser: make(map[int64]map[string]bool)}
+}
+
+func (r *MemoryRecoveryCodeRepository) Replace(ctx context.Context, userID int64, hashes []string, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	codes := make(map[string]bool, len(hashes))
+	for _, h := range hashes {
+		codes[h] = false
+	}
+	r.byUser[userID] = codes
+	return nil
+}
+
+func (r *MemoryRecoveryCodeRepository) Use(ctx context.Context, userID int64, hash string, at time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	used, ok := r.byUser[userID][hash]
+	if !ok || used {
+		return false, nil
+	}
+	r.byUser[userID][hash] = true
+	return true, nil
+}
+
+func (r *MemoryRecoveryCodeRepository) CountUnused(ctx context.Context, userID int64) (int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	n := 0
+	for _, used := range r.byUser[userID] {
+		if !used {
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (r *MemoryTOTPRepository) deleteByUser(userID int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.byUser, userID)
+}
+
+func (r *MemoryRecoveryCodeRepository) deleteByUser(userID int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.byUser, userID)
+}
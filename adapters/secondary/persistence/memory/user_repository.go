@@ -0,0 +1,175 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/moondolphin/crypto-api/domain"
+)
+
+// ErrDuplicateEmail emula la violación del UNIQUE (email) de la tabla users.
+var ErrDuplicateEmail = errors.New("duplicate_email")
+
+type MemoryUserRepository struct {
+	mu      sync.RWMutex
+	nextID  int64
+	byEmail map[string]domain.User
+	cascade []userOwned
+}
+
+func NewMemoryUserRepository() *MemoryUserRepository {
+	return &MemoryUserRepository{byEmail: make(map[string]domain.User)}
+}
+
+func (r *MemoryUserRepository) ExistsByEmail(ctx context.Context, email string) (bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	_, ok := r.byEmail[email]
+	return ok, nil
+}
+
+func (r *MemoryUserRepository) Create(ctx context.Context, u domain.User) (domain.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.byEmail[u.Email]; ok {
+		return domain.User{}, ErrDuplicateEmail
+	}
+
+	if u.Role == "" {
+		u.Role = domain.RoleUser
+	}
+
+	r.nextID++
+	u.ID = r.nextID
+	u.EmailVerifiedAt = copyTime(u.EmailVerifiedAt)
+	r.byEmail[u.Email] = u
+	return u, nil
+}
+
+func (r *MemoryUserRepository) FindByEmail(ctx context.Context, email string) (*domain.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	u, ok := r.byEmail[email]
+	if !ok {
+		return nil, nil
+	}
+	u.EmailVerifiedAt = copyTime(u.EmailVerifiedAt)
+	return &u, nil
+}
+
+func (r *MemoryUserRepository) FindByID(ctx context.Context, id int64) (*domain.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, u := range r.byEmail {
+		if u.ID == id {
+			u.EmailVerifiedAt = copyTime(u.EmailVerifiedAt)
+			return &u, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *MemoryUserRepository) UpdateRole(ctx context.Context, userID int64, role string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for email, u := range r.byEmail {
+		if u.ID == userID {
+			u.Role = role
+			r.byEmail[email] = u
+			return nil
+		}
+	}
+	return nil
+}
+
+func (r *MemoryUserRepository) UpdatePassword(ctx context.Context, userID int64, passwordHash string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for email, u := range r.byEmail {
+		if u.ID == userID {
+			u.PasswordHash = passwordHash
+			r.byEmail[email] = u
+			return nil
+		}
+	}
+	return nil
+}
+
+func (r *MemoryUserRepository) MarkEmailVerified(ctx context.Context, userID int64, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for email, u := range r.byEmail {
+		if u.ID == userID {
+			if u.EmailVerifiedAt == nil {
+				verifiedAt := at.UTC()
+				u.EmailVerifiedAt = &verifiedAt
+				r.byEmail[email] = u
+			}
+			return nil
+		}
+	}
+	return nil
+}
+
+func (r *MemoryUserRepository) UpdateProfile(ctx context.Context, u domain.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for email, cur := range r.byEmail {
+		if cur.ID != u.ID {
+			continue
+		}
+		if u.Email != email {
+			if _, taken := r.byEmail[u.Email]; taken {
+				return ErrDuplicateEmail
+			}
+			delete(r.byEmail, email)
+		}
+		cur.Name = u.Name
+		cur.Email = u.Email
+		cur.EmailVerifiedAt = copyTime(u.EmailVerifiedAt)
+		r.byEmail[cur.Email] = cur
+		return nil
+	}
+	return nil
+}
+
+// CascadeTo registra los repos cuyas filas del usuario borra Delete (tokens,
+// API keys, 2FA, identidades, alertas, webhooks, portfolios, watchlists...).
+func (r *MemoryUserRepository) CascadeTo(repos ...userOwned) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.cascade = append(r.cascade, repos...)
+}
+
+func (r *MemoryUserRepository) Delete(ctx context.Context, userID int64) error {
+	r.mu.Lock()
+	found := false
+	for email, u := range r.byEmail {
+		if u.ID == userID {
+			delete(r.byEmail, email)
+			found = true
+			break
+		}
+	}
+	cascade := r.cascade
+	r.mu.Unlock()
+
+	if !found {
+		return nil
+	}
+	for _, repo := range cascade {
+		repo.deleteByUser(userID)
+	}
+	return nil
+}
@@ -0,0 +1,272 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/moondolphin/crypto-api/domain"
+)
+
+type MemoryWatchlistRepository struct {
+	mu       sync.RWMutex
+	nextID   int64
+	byID     map[int64]domain.Watchlist
+	children []parentOwned
+}
+
+func NewMemoryWatchlistRepository() *MemoryWatchlistRepository {
+	return &MemoryWatchlistRepository{byID: make(map[int64]domain.Watchlist)}
+}
+
+func (r *MemoryWatchlistRepository) Create(ctx context.Context, w domain.Watchlist) (domain.Watchlist, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.insert(w, false), nil
+}
+
+// insert asigna id y guarda; requiere el lock tomado.
+func (r *MemoryWatchlistRepository) insert(w domain.Watchlist, isDefault bool) domain.Watchlist {
+	r.nextID++
+	w.ID = r.nextID
+	w.IsDefault = isDefault
+	w.CreatedAt = w.CreatedAt.UTC()
+	w.UpdatedAt = w.UpdatedAt.UTC()
+	r.byID[w.ID] = w
+	return w
+}
+
+func (r *MemoryWatchlistRepository) EnsureDefault(ctx context.Context, w domain.Watchlist) (domain.Watchlist, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if cur, ok := r.defaultOf(w.UserID); ok {
+		return cur, nil
+	}
+	return r.insert(w, true), nil
+}
+
+// defaultOf busca la lista por defecto del usuario; requiere el lock tomado.
+func (r *MemoryWatchlistRepository) defaultOf(userID int64) (domain.Watchlist, bool) {
+	for _, w := range r.byID {
+		if w.UserID == userID && w.IsDefault {
+			return w, true
+		}
+	}
+	return domain.Watchlist{}, false
+}
+
+func (r *MemoryWatchlistRepository) FindByUser(ctx context.Context, userID, id int64) (*domain.Watchlist, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	w, ok := r.byID[id]
+	if !ok || w.UserID != userID {
+		return nil, nil
+	}
+	return &w, nil
+}
+
+func (r *MemoryWatchlistRepository) ListByUser(ctx context.Context, userID int64) ([]domain.Watchlist, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var out []domain.Watchlist
+	for _, w := range r.byID {
+		if w.UserID == userID {
+			out = append(out, w)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].IsDefault != out[j].IsDefault {
+			return out[i].IsDefault
+		}
+		return out[i].ID < out[j].ID
+	})
+	return out, nil
+}
+
+func (r *MemoryWatchlistRepository) CountByUser(ctx context.Context, userID int64) (int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	n := 0
+	for _, w := range r.byID {
+		if w.UserID == userID {
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (r *MemoryWatchlistRepository) Update(ctx context.Context, w domain.Watchlist) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	cur, ok := r.byID[w.ID]
+	if !ok || cur.UserID != w.UserID {
+		return false, nil
+	}
+	cur.Name = w.Name
+	cur.UpdatedAt = w.UpdatedAt.UTC()
+	r.byID[w.ID] = cur
+	return true, nil
+}
+
+func (r *MemoryWatchlistRepository) Delete(ctx context.Context, userID, id int64) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	w, ok := r.byID[id]
+	if !ok || w.UserID != userID || w.IsDefault {
+		return false, nil
+	}
+	delete(r.byID, id)
+	cascadeToChildren(r.children, map[int64]bool{id: true})
+	return true, nil
+}
+
+// CascadeTo registra los repos cuyas filas borra junto con cada watchlist.
+func (r *MemoryWatchlistRepository) CascadeTo(children ...parentOwned) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.children = append(r.children, children...)
+}
+
+func (r *MemoryWatchlistRepository) deleteByUser(userID int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	ids := make(map[int64]bool)
+	for id, v := range r.byID {
+		if v.UserID == userID {
+			delete(r.byID, id)
+			ids[id] = true
+		}
+	}
+	cascadeToChildren(r.children, ids)
+}
+
+type watchlistEntryKey struct {
+	watchlistID int64
+	coinID      int64
+}
+
+type MemoryWatchlistEntryRepository struct {
+	mu      sync.RWMutex
+	coins   *MemoryCoinRepository
+	entries map[watchlistEntryKey]domain.WatchlistEntry
+}
+
+// NewMemoryWatchlistEntryRepository recibe el repo de coins para completar el
+// símbolo (equivalente al JOIN de la versión SQL).
+func NewMemoryWatchlistEntryRepository(coins *MemoryCoinRepository) *MemoryWatchlistEntryRepository {
+	return &MemoryWatchlistEntryRepository{
+		coins:   coins,
+		entries: make(map[watchlistEntryKey]domain.WatchlistEntry),
+	}
+}
+
+func (r *MemoryWatchlistEntryRepository) Add(ctx context.Context, e domain.WatchlistEntry) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	k := watchlistEntryKey{watchlistID: e.WatchlistID, coinID: e.CoinID}
+	if _, ok := r.entries[k]; ok {
+		return false, nil
+	}
+
+	e.Position = 0
+	for key, cur := range r.entries {
+		if key.watchlistID == e.WatchlistID && cur.Position >= e.Position {
+			e.Position = cur.Position + 1
+		}
+	}
+	e.CreatedAt = e.CreatedAt.UTC()
+	e.UpdatedAt = e.UpdatedAt.UTC()
+	r.entries[k] = e
+	return true, nil
+}
+
+func (r *MemoryWatchlistEntryRepository) UpdateNote(ctx context.Context, watchlistID, coinID int64, note string, at time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	k := watchlistEntryKey{watchlistID: watchlistID, coinID: coinID}
+	e, ok := r.entries[k]
+	if !ok {
+		return false, nil
+	}
+	e.Note = note
+	e.UpdatedAt = at.UTC()
+	r.entries[k] = e
+	return true, nil
+}
+
+func (r *MemoryWatchlistEntryRepository) Remove(ctx context.Context, watchlistID, coinID int64) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	k := watchlistEntryKey{watchlistID: watchlistID, coinID: coinID}
+	if _, ok := r.entries[k]; !ok {
+		return false, nil
+	}
+	delete(r.entries, k)
+	return true, nil
+}
+
+func (r *MemoryWatchlistEntryRepository) ListByWatchlist(ctx context.Context, watchlistID int64) ([]domain.WatchlistEntry, error) {
+	r.mu.RLock()
+	var list []domain.WatchlistEntry
+	for k, e := range r.entries {
+		if k.watchlistID == watchlistID {
+			list = append(list, e)
+		}
+	}
+	r.mu.RUnlock()
+
+	out := make([]domain.WatchlistEntry, 0, len(list))
+	for _, e := range list {
+		c, ok := r.coins.getByID(e.CoinID)
+		if !ok {
+			continue
+		}
+		e.Symbol = c.Symbol
+		out = append(out, e)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Position != out[j].Position {
+			return out[i].Position < out[j].Position
+		}
+		return out[i].Symbol < out[j].Symbol
+	})
+	return out, nil
+}
+
+func (r *MemoryWatchlistEntryRepository) Reorder(ctx context.Context, watchlistID int64, coinIDs []int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, coinID := range coinIDs {
+		k := watchlistEntryKey{watchlistID: watchlistID, coinID: coinID}
+		if e, ok := r.entries[k]; ok {
+			e.Position = i
+			r.entries[k] = e
+		}
+	}
+	return nil
+}
+
+func (r *MemoryWatchlistEntryRepository) deleteByParent(watchlistIDs map[int64]bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for k := range r.entries {
+		if watchlistIDs[k.watchlistID] {
+			delete(r.entries, k)
+		}
+	}
+}
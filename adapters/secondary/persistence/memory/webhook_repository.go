@@ -0,0 +1,286 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/moondolphin/crypto-api/domain"
+)
+
+type MemoryWebhookSubscriptionRepository struct {
+	mu       sync.RWMutex
+	nextID   int64
+	byID     map[int64]domain.WebhookSubscription
+	children []parentOwned
+}
+
+func NewMemoryWebhookSubscriptionRepository() *MemoryWebhookSubscriptionRepository {
+	return &MemoryWebhookSubscriptionRepository{byID: make(map[int64]domain.WebhookSubscription)}
+}
+
+func copyWebhookSubscription(s domain.WebhookSubscription) domain.WebhookSubscription {
+	s.Events = append([]string{}, s.Events...)
+	return s
+}
+
+func (r *MemoryWebhookSubscriptionRepository) Create(ctx context.Context, s domain.WebhookSubscription) (domain.WebhookSubscription, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.nextID++
+	s.ID = r.nextID
+	s.CreatedAt = s.CreatedAt.UTC()
+	s = copyWebhookSubscription(s)
+	r.byID[s.ID] = s
+	return copyWebhookSubscription(s), nil
+}
+
+func (r *MemoryWebhookSubscriptionRepository) FindByUser(ctx context.Context, userID, id int64) (*domain.WebhookSubscription, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	s, ok := r.byID[id]
+	if !ok || s.UserID != userID {
+		return nil, nil
+	}
+	s = copyWebhookSubscription(s)
+	return &s, nil
+}
+
+func (r *MemoryWebhookSubscriptionRepository) FindByID(ctx context.Context, id int64) (*domain.WebhookSubscription, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	s, ok := r.byID[id]
+	if !ok {
+		return nil, nil
+	}
+	s = copyWebhookSubscription(s)
+	return &s, nil
+}
+
+func (r *MemoryWebhookSubscriptionRepository) filter(keep func(domain.WebhookSubscription) bool) []domain.WebhookSubscription {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var out []domain.WebhookSubscription
+	for _, s := range r.byID {
+		if keep(s) {
+			out = append(out, copyWebhookSubscription(s))
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out
+}
+
+func (r *MemoryWebhookSubscriptionRepository) ListByUser(ctx context.Context, userID int64) ([]domain.WebhookSubscription, error) {
+	return r.filter(func(s domain.WebhookSubscription) bool { return s.UserID == userID }), nil
+}
+
+func (r *MemoryWebhookSubscriptionRepository) CountByUser(ctx context.Context, userID int64) (int, error) {
+	return len(r.filter(func(s domain.WebhookSubscription) bool { return s.UserID == userID })), nil
+}
+
+func (r *MemoryWebhookSubscriptionRepository) ListByEvent(ctx context.Context, event string, userID int64) ([]domain.WebhookSubscription, error) {
+	return r.filter(func(s domain.WebhookSubscription) bool {
+		return s.HasEvent(event) && (userID <= 0 || s.UserID == userID)
+	}), nil
+}
+
+func (r *MemoryWebhookSubscriptionRepository) Delete(ctx context.Context, userID, id int64) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	s, ok := r.byID[id]
+	if !ok || s.UserID != userID {
+		return false, nil
+	}
+	delete(r.byID, id)
+	cascadeToChildren(r.children, map[int64]bool{id: true})
+	return true, nil
+}
+
+// CascadeTo registra los repos cuyas filas borra junto con cada suscripción.
+func (r *MemoryWebhookSubscriptionRepository) CascadeTo(children ...parentOwned) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.children = append(r.children, children...)
+}
+
+func (r *MemoryWebhookSubscriptionRepository) deleteByUser(userID int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	ids := make(map[int64]bool)
+	for id, v := range r.byID {
+		if v.UserID == userID {
+			delete(r.byID, id)
+			ids[id] = true
+		}
+	}
+	cascadeToChildren(r.children, ids)
+}
+
+type MemoryWebhookDeliveryRepository struct {
+	mu     sync.RWMutex
+	nextID int64
+	byID   map[int64]domain.WebhookDelivery
+}
+
+func NewMemoryWebhookDeliveryRepository() *MemoryWebhookDeliveryRepository {
+	return &MemoryWebhookDeliveryRepository{byID: make(map[int64]domain.WebhookDelivery)}
+}
+
+func copyWebhookDelivery(d domain.WebhookDelivery) domain.WebhookDelivery {
+	d.LastAttemptAt = copyTime(d.LastAttemptAt)
+	d.DeliveredAt = copyTime(d.DeliveredAt)
+	return d
+}
+
+func (r *MemoryWebhookDeliveryRepository) Enqueue(ctx context.Context, d domain.WebhookDelivery) (domain.WebhookDelivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.nextID++
+	d.ID = r.nextID
+	d.NextAttemptAt = d.NextAttemptAt.UTC()
+	d.CreatedAt = d.CreatedAt.UTC()
+	d.LastAttemptAt = nil
+	d.DeliveredAt = nil
+	r.byID[d.ID] = d
+	return d, nil
+}
+
+func (r *MemoryWebhookDeliveryRepository) ListDue(ctx context.Context, now time.Time, limit int) ([]domain.WebhookDelivery, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var out []domain.WebhookDelivery
+	for _, d := range r.byID {
+		if d.Status == domain.WebhookDeliveryPending && !d.NextAttemptAt.After(now) {
+			out = append(out, copyWebhookDelivery(d))
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if !out[i].NextAttemptAt.Equal(out[j].NextAttemptAt) {
+			return out[i].NextAttemptAt.Before(out[j].NextAttemptAt)
+		}
+		return out[i].ID < out[j].ID
+	})
+	if limit > 0 && len(out) > limit {
+		out = out[:limit]
+	}
+	return out, nil
+}
+
+func (r *MemoryWebhookDeliveryRepository) Claim(ctx context.Context, id int64, attempts int, leaseUntil time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	d, ok := r.byID[id]
+	if !ok || d.Status != domain.WebhookDeliveryPending || d.Attempts != attempts {
+		return false, nil
+	}
+	d.Attempts++
+	d.NextAttemptAt = leaseUntil.UTC()
+	r.byID[id] = d
+	return true, nil
+}
+
+func (r *MemoryWebhookDeliveryRepository) RecordAttempt(ctx context.Context, d domain.WebhookDelivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	cur, ok := r.byID[d.ID]
+	if !ok {
+		return nil
+	}
+	cur.Status = d.Status
+	cur.NextAttemptAt = d.NextAttemptAt.UTC()
+	cur.LastAttemptAt = copyTime(d.LastAttemptAt)
+	cur.LastStatusCode = d.LastStatusCode
+	cur.LastError = d.LastError
+	cur.DeliveredAt = copyTime(d.DeliveredAt)
+	r.byID[d.ID] = cur
+	return nil
+}
+
+func (r *MemoryWebhookDeliveryRepository) FindBySubscription(ctx context.Context, subscriptionID, id int64) (*domain.WebhookDelivery, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	d, ok := r.byID[id]
+	if !ok || d.SubscriptionID != subscriptionID {
+		return nil, nil
+	}
+	d = copyWebhookDelivery(d)
+	return &d, nil
+}
+
+func (r *MemoryWebhookDeliveryRepository) ListBySubscription(ctx context.Context, subscriptionID int64, status string, limit int) ([]domain.WebhookDelivery, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var out []domain.WebhookDelivery
+	for _, d := range r.byID {
+		if d.SubscriptionID == subscriptionID && (status == "" || d.Status == status) {
+			out = append(out, copyWebhookDelivery(d))
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if !out[i].CreatedAt.Equal(out[j].CreatedAt) {
+			return out[i].CreatedAt.After(out[j].CreatedAt)
+		}
+		return out[i].ID > out[j].ID
+	})
+	if limit > 0 && len(out) > limit {
+		out = out[:limit]
+	}
+	return out, nil
+}
+
+func (r *MemoryWebhookDeliveryRepository) Replay(ctx context.Context, subscriptionID, id int64, now time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var n int64
+	for _, d := range r.byID {
+		if d.SubscriptionID != subscriptionID || d.Status != domain.WebhookDeliveryDead || (id > 0 && d.ID != id) {
+			continue
+		}
+		d.Status = domain.WebhookDeliveryPending
+		d.Attempts = 0
+		d.NextAttemptAt = now.UTC()
+		r.byID[d.ID] = d
+		n++
+	}
+	return n, nil
+}
+
+func (r *MemoryWebhookDeliveryRepository) DeleteDeliveredBefore(ctx context.Context, before time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var n int64
+	for id, d := range r.byID {
+		if d.Status == domain.WebhookDeliveryDelivered && d.DeliveredAt != nil && d.DeliveredAt.Before(before) {
+			delete(r.byID, id)
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (r *MemoryWebhookDeliveryRepository) deleteByParent(subscriptionIDs map[int64]bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for id, d := range r.byID {
+		if subscriptionIDs[d.SubscriptionID] {
+			delete(r.byID, id)
+		}
+	}
+}
@@ -0,0 +1,239 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/moondolphin/crypto-api/domain"
+)
+
+type MySQLAlertRuleRepository struct {
+	DB *sql.DB
+}
+
+func NewMySQLAlertRuleRepository(db *sql.DB) *MySQLAlertRuleRepository {
+	return &MySQLAlertRuleRepository{DB: db}
+}
+
+const alertRuleColumns = `id, user_id, coin_id, symbol, currency, provider, kind, threshold, window_seconds,
+		rearm, active, armed, last_triggered_at, created_at, updated_at`
+
+func scanAlertRule(s rowScanner) (domain.AlertRule, error) {
+	var (
+		r             domain.AlertRule
+		windowSeconds int64
+		lastTriggered sql.NullTime
+	)
+	err := s.Scan(&r.ID, &r.UserID, &r.CoinID, &r.Symbol, &r.Currency, &r.Provider, &r.Kind, &r.Threshold, &windowSeconds,
+		&r.Rearm, &r.Active, &r.Armed, &lastTriggered, &r.CreatedAt, &r.UpdatedAt)
+	if err != nil {
+		return domain.AlertRule{}, err
+	}
+
+	r.Window = time.Duration(windowSeconds) * time.Second
+	r.LastTriggeredAt = timePtr(lastTriggered)
+	return r, nil
+}
+
+func (r *MySQLAlertRuleRepository) list(ctx context.Context, q string, args ...any) ([]domain.AlertRule, error) {
+	rows, err := r.DB.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []domain.AlertRule
+	for rows.Next() {
+		a, err := scanAlertRule(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, a)
+	}
+	return out, rows.Err()
+}
+
+func (r *MySQLAlertRuleRepository) Create(ctx context.Context, a domain.AlertRule) (domain.AlertRule, error) {
+	const q = `
+		INSERT INTO alert_rules (user_id, coin_id, symbol, currency, provider, kind, threshold, window_seconds,
+			rearm, active, armed, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+	res, err := r.DB.ExecContext(ctx, q, a.UserID, a.CoinID, a.Symbol, a.Currency, a.Provider, a.Kind, a.Threshold,
+		int64(a.Window/time.Second), a.Rearm, a.Active, a.Armed, a.CreatedAt.UTC(), a.UpdatedAt.UTC())
+	if err != nil {
+		return domain.AlertRule{}, err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return domain.AlertRule{}, err
+	}
+
+	a.ID = id
+	a.LastTriggeredAt = nil
+	return a, nil
+}
+
+func (r *MySQLAlertRuleRepository) FindByUser(ctx context.Context, userID, id int64) (*domain.AlertRule, error) {
+	q := `SELECT ` + alertRuleColumns + ` FROM alert_rules WHERE id = ? AND user_id = ? LIMIT 1`
+
+	a, err := scanAlertRule(r.DB.QueryRowContext(ctx, q, id, userID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+func (r *MySQLAlertRuleRepository) ListByUser(ctx context.Context, userID int64) ([]domain.AlertRule, error) {
+	return r.list(ctx, `SELECT `+alertRuleColumns+` FROM alert_rules WHERE user_id = ? ORDER BY id ASC`, userID)
+}
+
+func (r *MySQLAlertRuleRepository) CountByUser(ctx context.Context, userID int64) (int, error) {
+	var n int
+	err := r.DB.QueryRowContext(ctx, `SELECT COUNT(*) FROM alert_rules WHERE user_id = ?`, userID).Scan(&n)
+	return n, err
+}
+
+func (r *MySQLAlertRuleRepository) ListActive(ctx context.Context) ([]domain.AlertRule, error) {
+	return r.list(ctx, `SELECT `+alertRuleColumns+` FROM alert_rules WHERE active = ? ORDER BY id ASC`, true)
+}
+
+func (r *MySQLAlertRuleRepository) Update(ctx context.Context, a domain.AlertRule) (bool, error) {
+	const q = `
+		UPDATE alert_rules
+		SET threshold = ?, window_seconds = ?, rearm = ?, active = ?, armed = ?, updated_at = ?
+		WHERE id = ? AND user_id = ?
+	`
+	res, err := r.DB.ExecContext(ctx, q, a.Threshold, int64(a.Window/time.Second), a.Rearm, a.Active, a.Armed,
+		a.UpdatedAt.UTC(), a.ID, a.UserID)
+	if err != nil {
+		return false, err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
+func (r *MySQLAlertRuleRepository) Delete(ctx context.Context, userID, id int64) (bool, error) {
+	res, err := r.DB.ExecContext(ctx, `DELETE FROM alert_rules WHERE id = ? AND user_id = ?`, id, userID)
+	if err != nil {
+		return false, err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
+func (r *MySQLAlertRuleRepository) MarkTriggered(ctx context.Context, id int64, at time.Time, deactivate bool) (bool, error) {
+	const q = `
+		UPDATE alert_rules
+		SET armed = ?, active = active AND ?, last_triggered_at = ?
+		WHERE id = ? AND active = ? AND armed = ?
+	`
+	res, err := r.DB.ExecContext(ctx, q, false, !deactivate, at.UTC(), id, true, true)
+	if err != nil {
+		return false, err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
+func (r *MySQLAlertRuleRepository) Rearm(ctx context.Context, id int64, at time.Time) error {
+	const q = `UPDATE alert_rules SET armed = ?, updated_at = ? WHERE id = ? AND active = ? AND armed = ?`
+	_, err := r.DB.ExecContext(ctx, q, true, at.UTC(), id, true, false)
+	return err
+}
+
+type MySQLAlertEventRepository struct {
+	DB *sql.DB
+}
+
+func NewMySQLAlertEventRepository(db *sql.DB) *MySQLAlertEventRepository {
+	return &MySQLAlertEventRepository{DB: db}
+}
+
+func (r *MySQLAlertEventRepository) Create(ctx context.Context, e domain.AlertEvent) (domain.AlertEvent, error) {
+	const q = `
+		INSERT INTO alert_events (rule_id, user_id, symbol, provider, currency, kind, threshold, price, quoted_at,
+			reference_price, change_percent, triggered_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+	var ref sql.NullString
+	if e.ReferencePrice != "" {
+		ref = sql.NullString{String: e.ReferencePrice, Valid: true}
+	}
+	var change sql.NullFloat64
+	if e.ChangePercent != nil {
+		change = sql.NullFloat64{Float64: *e.ChangePercent, Valid: true}
+	}
+
+	res, err := r.DB.ExecContext(ctx, q, e.RuleID, e.UserID, e.Symbol, e.Provider, e.Currency, e.Kind, e.Threshold,
+		e.Price, e.QuotedAt.UTC(), ref, change, e.TriggeredAt.UTC())
+	if err != nil {
+		return domain.AlertEvent{}, err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return domain.AlertEvent{}, err
+	}
+
+	e.ID = id
+	return e, nil
+}
+
+func (r *MySQLAlertEventRepository) ListByUser(ctx context.Context, userID, ruleID int64, limit int) ([]domain.AlertEvent, error) {
+	q := `
+		SELECT id, rule_id, user_id, symbol, provider, currency, kind, threshold, price, quoted_at,
+			reference_price, change_percent, triggered_at
+		FROM alert_events
+		WHERE user_id = ?`
+	args := []any{userID}
+	if ruleID > 0 {
+		q += ` AND rule_id = ?`
+		args = append(args, ruleID)
+	}
+	q += ` ORDER BY triggered_at DESC, id DESC LIMIT ?`
+	args = append(args, limit)
+
+	rows, err := r.DB.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []domain.AlertEvent
+	for rows.Next() {
+		var (
+			e      domain.AlertEvent
+			ref    sql.NullString
+			change sql.NullFloat64
+		)
+		if err := rows.Scan(&e.ID, &e.RuleID, &e.UserID, &e.Symbol, &e.Provider, &e.Currency, &e.Kind, &e.Threshold,
+			&e.Price, &e.QuotedAt, &ref, &change, &e.TriggeredAt); err != nil {
+			return nil, err
+		}
+		e.ReferencePrice = ref.String
+		if change.Valid {
+			v := change.Float64
+			e.ChangePercent = &v
+		}
+		out = append(out, e)
+	}
+	return out, rows.Err()
+}
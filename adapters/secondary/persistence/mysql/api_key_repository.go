@@ -0,0 +1,146 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/moondolphin/crypto-api/domain"
+)
+
+type MySQLAPIKeyRepository struct {
+	DB *sql.DB
+}
+
+func NewMySQLAPIKeyRepository(db *sql.DB) *MySQLAPIKeyRepository {
+	return &MySQLAPIKeyRepository{DB: db}
+}
+
+const apiKeyColumns = `id, user_id, name, prefix, key_hash, scopes, expires_at, created_at, last_used_at, revoked_at`
+
+func (r *MySQLAPIKeyRepository) Create(ctx context.Context, k domain.APIKey) (domain.APIKey, error) {
+	const q = `
+		INSERT INTO api_keys (user_id, name, prefix, key_hash, scopes, expires_at, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`
+	res, err := r.DB.ExecContext(ctx, q,
+		k.UserID, k.Name, k.Prefix, k.KeyHash, strings.Join(k.Scopes, ","), nullTime(k.ExpiresAt), k.CreatedAt.UTC(),
+	)
+	if err != nil {
+		return domain.APIKey{}, err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return domain.APIKey{}, err
+	}
+
+	k.ID = id
+	return k, nil
+}
+
+func (r *MySQLAPIKeyRepository) ListByUser(ctx context.Context, userID int64) ([]domain.APIKey, error) {
+	q := `SELECT ` + apiKeyColumns + ` FROM api_keys WHERE user_id = ? AND revoked_at IS NULL ORDER BY id`
+
+	rows, err := r.DB.QueryContext(ctx, q, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]domain.APIKey, 0)
+	for rows.Next() {
+		k, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, k)
+	}
+	return out, rows.Err()
+}
+
+func (r *MySQLAPIKeyRepository) FindByHash(ctx context.Context, keyHash string) (*domain.APIKey, error) {
+	q := `SELECT ` + apiKeyColumns + ` FROM api_keys WHERE key_hash = ? LIMIT 1`
+
+	k, err := scanAPIKey(r.DB.QueryRowContext(ctx, q, keyHash))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &k, nil
+}
+
+func (r *MySQLAPIKeyRepository) Revoke(ctx context.Context, userID, id int64, at time.Time) (bool, error) {
+	const q = `UPDATE api_keys SET revoked_at = ? WHERE id = ? AND user_id = ? AND revoked_at IS NULL`
+	res, err := r.DB.ExecContext(ctx, q, at.UTC(), id, userID)
+	if err != nil {
+		return false, err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
+func (r *MySQLAPIKeyRepository) RevokeByUser(ctx context.Context, userID int64, at time.Time) error {
+	const q = `UPDATE api_keys SET revoked_at = ? WHERE user_id = ? AND revoked_at IS NULL`
+	_, err := r.DB.ExecContext(ctx, q, at.UTC(), userID)
+	return err
+}
+
+func (r *MySQLAPIKeyRepository) TouchLastUsed(ctx context.Context, id int64, at time.Time) error {
+	const q = `UPDATE api_keys SET last_used_at = ? WHERE id = ?`
+	_, err := r.DB.ExecContext(ctx, q, at.UTC(), id)
+	return err
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanAPIKey(row rowScanner) (domain.APIKey, error) {
+	var (
+		k                                domain.APIKey
+		scopes                           string
+		expiresAt, lastUsedAt, revokedAt sql.NullTime
+	)
+	err := row.Scan(&k.ID, &k.UserID, &k.Name, &k.Prefix, &k.KeyHash, &scopes,
+		&expiresAt, &k.CreatedAt, &lastUsedAt, &revokedAt)
+	if err != nil {
+		return domain.APIKey{}, err
+	}
+
+	k.CreatedAt = k.CreatedAt.UTC()
+	k.Scopes = splitScopes(scopes)
+	k.ExpiresAt = timePtr(expiresAt)
+	k.LastUsedAt = timePtr(lastUsedAt)
+	k.RevokedAt = timePtr(revokedAt)
+	return k, nil
+}
+
+func splitScopes(s string) []string {
+	if s == "" {
+		return []string{}
+	}
+	return strings.Split(s, ",")
+}
+
+func nullTime(t *time.Time) any {
+	if t == nil {
+		return nil
+	}
+	return t.UTC()
+}
+
+func timePtr(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	v := t.Time.UTC()
+	return &v
+}
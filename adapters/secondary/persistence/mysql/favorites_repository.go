@@ -3,10 +3,13 @@ package mysql
 import (
 	"context"
 	"database/sql"
+	"time"
 
 	"github.com/moondolphin/crypto-api/domain"
 )
 
+// MySQLFavoritesRepository trabaja sobre la watchlist por defecto del
+// usuario; la crea en la primera alta.
 type MySQLFavoritesRepository struct {
 	DB *sql.DB
 }
@@ -17,30 +20,43 @@ func NewMySQLFavoritesRepository(db *sql.DB) *MySQLFavoritesRepository {
 
 // Idempotente: si ya existe (user_id, coin_id)
 func (r *MySQLFavoritesRepository) AddFavoriteCoinToUser(ctx context.Context, userID, coinID int64) error {
-	const q = `
-		INSERT IGNORE INTO user_favorites (user_id, coin_id)
-		VALUES (?, ?)
-	`
-	_, err := r.DB.ExecContext(ctx, q, userID, coinID)
+	now := time.Now().UTC()
+	w, err := NewMySQLWatchlistRepository(r.DB).EnsureDefault(ctx, domain.Watchlist{
+		UserID:    userID,
+		Name:      domain.DefaultWatchlistName,
+		CreatedAt: now,
+		UpdatedAt: now,
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = NewMySQLWatchlistEntryRepository(r.DB).Add(ctx, domain.WatchlistEntry{
+		WatchlistID: w.ID,
+		CoinID:      coinID,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	})
 	return err
 }
 
 // Idempotente: si no existe
 func (r *MySQLFavoritesRepository) RemoveFavoriteCoinFromUser(ctx context.Context, userID, coinID int64) error {
 	const q = `
-		DELETE FROM user_favorites
-		WHERE user_id = ? AND coin_id = ?
+		DELETE FROM watchlist_entries
+		WHERE coin_id = ? AND watchlist_id IN (SELECT id FROM watchlists WHERE default_user_id = ?)
 	`
-	_, err := r.DB.ExecContext(ctx, q, userID, coinID)
+	_, err := r.DB.ExecContext(ctx, q, coinID, userID)
 	return err
 }
 
 func (r *MySQLFavoritesRepository) ListFavoriteCoinIDsByUser(ctx context.Context, userID int64) ([]domain.Coin, error) {
 	const q = `
 		SELECT c.id, c.symbol, c.enabled, c.coingecko_id, c.binance_symbol
-		FROM user_favorites uf
-		JOIN coins c ON c.id = uf.coin_id
-		WHERE uf.user_id = ?
+		FROM watchlists w
+		JOIN watchlist_entries e ON e.watchlist_id = w.id
+		JOIN coins c ON c.id = e.coin_id
+		WHERE w.default_user_id = ?
 		ORDER BY c.symbol ASC
 	`
 
@@ -0,0 +1,84 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/moondolphin/crypto-api/domain"
+)
+
+type MySQLLoginAttemptStore struct {
+	DB *sql.DB
+}
+
+func NewMySQLLoginAttemptStore(db *sql.DB) *MySQLLoginAttemptStore {
+	return &MySQLLoginAttemptStore{DB: db}
+}
+
+func (s *MySQLLoginAttemptStore) Get(ctx context.Context, key string) (*domain.LoginAttempt, error) {
+	const q = `
+		SELECT attempt_key, failures, last_failure_at, locked_until
+		FROM login_attempts
+		WHERE attempt_key = ?
+	`
+	var (
+		a           domain.LoginAttempt
+		lockedUntil sql.NullTime
+	)
+	err := s.DB.QueryRowContext(ctx, q, key).Scan(&a.Key, &a.Failures, &a.LastFailureAt, &lockedUntil)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	a.LockedUntil = timePtr(lockedUntil)
+	return &a, nil
+}
+
+func (s *MySQLLoginAttemptStore) RecordFailure(ctx context.Context, key string, at, resetBefore time.Time) (int, error) {
+	// el upsert incrementa en una sola sentencia: dos fallos concurrentes no se pisan.
+	// MySQL evalúa los SET en orden: failures todavía ve el last_failure_at anterior.
+	const upsert = `
+		INSERT INTO login_attempts (attempt_key, failures, last_failure_at)
+		VALUES (?, 1, ?)
+		ON DUPLICATE KEY UPDATE
+			failures = IF(GREATEST(last_failure_at, COALESCE(locked_until, last_failure_at)) < ?, 1, failures + 1),
+			last_failure_at = VALUES(last_failure_at)
+	`
+	if _, err := s.DB.ExecContext(ctx, upsert, key, at.UTC(), resetBefore.UTC()); err != nil {
+		return 0, err
+	}
+
+	// MySQL 5.7 no tiene RETURNING
+	const q = `SELECT failures FROM login_attempts WHERE attempt_key = ?`
+	var failures int
+	err := s.DB.QueryRowContext(ctx, q, key).Scan(&failures)
+	return failures, err
+}
+
+func (s *MySQLLoginAttemptStore) Lock(ctx context.Context, key string, until time.Time) error {
+	const q = `UPDATE login_attempts SET locked_until = ? WHERE attempt_key = ?`
+	_, err := s.DB.ExecContext(ctx, q, until.UTC(), key)
+	return err
+}
+
+func (s *MySQLLoginAttemptStore) Reset(ctx context.Context, key string) error {
+	const q = `DELETE FROM login_attempts WHERE attempt_key = ?`
+	_, err := s.DB.ExecContext(ctx, q, key)
+	return err
+}
+
+func (s *MySQLLoginAttemptStore) DeleteStale(ctx context.Context, before time.Time) (int64, error) {
+	const q = `
+		DELETE FROM login_attempts
+		WHERE last_failure_at < ? AND (locked_until IS NULL OR locked_until < ?)
+	`
+	res, err := s.DB.ExecContext(ctx, q, before.UTC(), before.UTC())
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
@@ -0,0 +1,83 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/moondolphin/crypto-api/domain"
+)
+
+type MySQLLoginChallengeRepository struct {
+	DB *sql.DB
+}
+
+func NewMySQLLoginChallengeRepository(db *sql.DB) *MySQLLoginChallengeRepository {
+	return &MySQLLoginChallengeRepository{DB: db}
+}
+
+func (r *MySQLLoginChallengeRepository) Create(ctx context.Context, c domain.LoginChallenge) (domain.LoginChallenge, error) {
+	const q = `
+		INSERT INTO login_challenges (user_id, token_hash, expires_at, created_at)
+		VALUES (?, ?, ?, ?)
+	`
+	res, err := r.DB.ExecContext(ctx, q, c.UserID, c.TokenHash, c.ExpiresAt.UTC(), c.CreatedAt.UTC())
+	if err != nil {
+		return domain.LoginChallenge{}, err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return domain.LoginChallenge{}, err
+	}
+
+	c.ID = id
+	return c, nil
+}
+
+func (r *MySQLLoginChallengeRepository) FindByHash(ctx context.Context, tokenHash string) (*domain.LoginChallenge, error) {
+	const q = `
+		SELECT id, user_id, token_hash, expires_at, created_at, used_at
+		FROM login_challenges
+		WHERE token_hash = ?
+		LIMIT 1
+	`
+	var (
+		c      domain.LoginChallenge
+		usedAt sql.NullTime
+	)
+	err := r.DB.QueryRowContext(ctx, q, tokenHash).
+		Scan(&c.ID, &c.UserID, &c.TokenHash, &c.ExpiresAt, &c.CreatedAt, &usedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	c.UsedAt = timePtr(usedAt)
+	return &c, nil
+}
+
+func (r *MySQLLoginChallengeRepository) MarkUsed(ctx context.Context, id int64, at time.Time) (bool, error) {
+	const q = `UPDATE login_challenges SET used_at = ? WHERE id = ? AND used_at IS NULL`
+	res, err := r.DB.ExecContext(ctx, q, at.UTC(), id)
+	if err != nil {
+		return false, err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
+func (r *MySQLLoginChallengeRepository) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	const q = `DELETE FROM login_challenges WHERE expires_at < ?`
+	res, err := r.DB.ExecContext(ctx, q, now.UTC())
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
@@ -0,0 +1,153 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/moondolphin/crypto-api/domain"
+)
+
+type MySQLUserIdentityRepository struct {
+	DB *sql.DB
+}
+
+func NewMySQLUserIdentityRepository(db *sql.DB) *MySQLUserIdentityRepository {
+	return &MySQLUserIdentityRepository{DB: db}
+}
+
+func (r *MySQLUserIdentityRepository) FindBySubject(ctx context.Context, issuer, subject string) (*domain.UserIdentity, error) {
+	const q = `
+		SELECT id, user_id, issuer, subject, email, created_at
+		FROM user_identities
+		WHERE issuer = ? AND subject = ?
+		LIMIT 1
+	`
+	var i domain.UserIdentity
+	err := r.DB.QueryRowContext(ctx, q, issuer, subject).
+		Scan(&i.ID, &i.UserID, &i.Issuer, &i.Subject, &i.Email, &i.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &i, nil
+}
+
+func (r *MySQLUserIdentityRepository) Create(ctx context.Context, i domain.UserIdentity) (domain.UserIdentity, error) {
+	const q = `
+		INSERT INTO user_identities (user_id, issuer, subject, email, created_at)
+		VALUES (?, ?, ?, ?, ?)
+	`
+	res, err := r.DB.ExecContext(ctx, q, i.UserID, i.Issuer, i.Subject, i.Email, i.CreatedAt.UTC())
+	if err != nil {
+		return domain.UserIdentity{}, err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return domain.UserIdentity{}, err
+	}
+
+	i.ID = id
+	return i, nil
+}
+
+func (r *MySQLUserIdentityRepository) ListByUser(ctx context.Context, userID int64) ([]domain.UserIdentity, error) {
+	const q = `
+		SELECT id, user_id, issuer, subject, email, created_at
+		FROM user_identities
+		WHERE user_id = ?
+		ORDER BY id ASC
+	`
+	rows, err := r.DB.QueryContext(ctx, q, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []domain.UserIdentity
+	for rows.Next() {
+		var i domain.UserIdentity
+		if err := rows.Scan(&i.ID, &i.UserID, &i.Issuer, &i.Subject, &i.Email, &i.CreatedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, i)
+	}
+	return out, rows.Err()
+}
+
+type MySQLOIDCStateRepository struct {
+	DB *sql.DB
+}
+
+func NewMySQLOIDCStateRepository(db *sql.DB) *MySQLOIDCStateRepository {
+	return &MySQLOIDCStateRepository{DB: db}
+}
+
+func (r *MySQLOIDCStateRepository) Create(ctx context.Context, s domain.OIDCLoginState) (domain.OIDCLoginState, error) {
+	const q = `
+		INSERT INTO oidc_login_states (state_hash, nonce, code_verifier, expires_at, created_at)
+		VALUES (?, ?, ?, ?, ?)
+	`
+	res, err := r.DB.ExecContext(ctx, q, s.StateHash, s.Nonce, s.CodeVerifier, s.ExpiresAt.UTC(), s.CreatedAt.UTC())
+	if err != nil {
+		return domain.OIDCLoginState{}, err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return domain.OIDCLoginState{}, err
+	}
+
+	s.ID = id
+	return s, nil
+}
+
+func (r *MySQLOIDCStateRepository) FindByHash(ctx context.Context, stateHash string) (*domain.OIDCLoginState, error) {
+	const q = `
+		SELECT id, state_hash, nonce, code_verifier, expires_at, created_at, used_at
+		FROM oidc_login_states
+		WHERE state_hash = ?
+		LIMIT 1
+	`
+	var (
+		s      domain.OIDCLoginState
+		usedAt sql.NullTime
+	)
+	err := r.DB.QueryRowContext(ctx, q, stateHash).
+		Scan(&s.ID, &s.StateHash, &s.Nonce, &s.CodeVerifier, &s.ExpiresAt, &s.CreatedAt, &usedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	s.UsedAt = timePtr(usedAt)
+	return &s, nil
+}
+
+func (r *MySQLOIDCStateRepository) MarkUsed(ctx context.Context, id int64, at time.Time) (bool, error) {
+	const q = `UPDATE oidc_login_states SET used_at = ? WHERE id = ? AND used_at IS NULL`
+	res, err := r.DB.ExecContext(ctx, q, at.UTC(), id)
+	if err != nil {
+		return false, err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
+func (r *MySQLOIDCStateRepository) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	const q = `DELETE FROM oidc_login_states WHERE expires_at < ?`
+	res, err := r.DB.ExecContext(ctx, q, now.UTC())
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
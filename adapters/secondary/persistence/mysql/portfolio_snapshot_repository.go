@@ -0,0 +1,59 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/moondolphin/crypto-api/domain"
+)
+
+type MySQLPortfolioSnapshotRepository struct {
+	DB *sql.DB
+}
+
+func NewMySQLPortfolioSnapshotRepository(db *sql.DB) *MySQLPortfolioSnapshotRepository {
+	return &MySQLPortfolioSnapshotRepository{DB: db}
+}
+
+func (r *MySQLPortfolioSnapshotRepository) Upsert(ctx context.Context, s domain.PortfolioSnapshot) error {
+	const q = `
+		INSERT INTO portfolio_snapshots (portfolio_id, day, currency, value, computed_at)
+		VALUES (?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE
+			value = VALUES(value),
+			computed_at = VALUES(computed_at)
+	`
+	_, err := r.DB.ExecContext(ctx, q, s.PortfolioID, s.Day.UTC(), s.Currency, s.Value, s.ComputedAt.UTC())
+	return err
+}
+
+func (r *MySQLPortfolioSnapshotRepository) ListRange(ctx context.Context, portfolioID int64, currency string, from, to time.Time) ([]domain.PortfolioSnapshot, error) {
+	const q = `
+		SELECT portfolio_id, day, currency, value, computed_at
+		FROM portfolio_snapshots
+		WHERE portfolio_id = ? AND currency = ? AND day >= ? AND day <= ?
+		ORDER BY day ASC
+	`
+	rows, err := r.DB.QueryContext(ctx, q, portfolioID, currency, from.UTC(), to.UTC())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []domain.PortfolioSnapshot
+	for rows.Next() {
+		var s domain.PortfolioSnapshot
+		if err := rows.Scan(&s.PortfolioID, &s.Day, &s.Currency, &s.Value, &s.ComputedAt); err != nil {
+			return nil, err
+		}
+		s.Day = s.Day.UTC()
+		out = append(out, s)
+	}
+	return out, rows.Err()
+}
+
+func (r *MySQLPortfolioSnapshotRepository) DeleteFrom(ctx context.Context, portfolioID int64, from time.Time) error {
+	_, err := r.DB.ExecContext(ctx, `DELETE FROM portfolio_snapshots WHERE portfolio_id = ? AND day >= ?`, portfolioID, from.UTC())
+	return err
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/moondolphin/crypto-api/domain"
@@ -17,13 +18,23 @@ func NewMySQLQuoteRepository(db *sql.DB) *MySQLQuoteRepository {
 	return &MySQLQuoteRepository{DB: db}
 }
 
-func (r *MySQLQuoteRepository) Insert(ctx context.Context, q domain.Quote) error {
+// Insert usa la clave única uk_quotes_coin_provider_currency_time: el no-op
+// "id = id" deja RowsAffected en 0 cuando la cotización ya existía.
+func (r *MySQLQuoteRepository) Insert(ctx context.Context, q domain.Quote) (bool, error) {
 	const stmt = `
 		INSERT INTO quotes (coin_id, symbol, provider, currency, price, quoted_at)
 		VALUES (?, ?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE id = id
 	`
-	_, err := r.DB.ExecContext(ctx, stmt, q.CoinID, q.Symbol, q.Provider, q.Currency, q.Price, q.QuotedAt)
-	return err
+	res, err := r.DB.ExecContext(ctx, stmt, q.CoinID, q.Symbol, q.Provider, q.Currency, q.Price, q.QuotedAt)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
 }
 
 func (r *MySQLQuoteRepository) GetLatest(ctx context.Context, symbol, provider, currency string) (*domain.PriceQuote, error) {
@@ -257,3 +268,133 @@ func (r *MySQLQuoteRepository) ListAvailableFilters(ctx context.Context, f domai
 }
 
 var _ = time.RFC3339
+
+// ListDailyCloses agrupa por DATE(quoted_at): los tiempos se guardan en UTC,
+// así que el día es el de UTC. MySQL 5.7 no tiene funciones de ventana: se
+// hace JOIN contra el MAX(quoted_at) de cada día.
+func (r *MySQLQuoteRepository) ListDailyCloses(ctx context.Context, f domain.DailyCloseFilter) ([]domain.DailyClose, error) {
+	if len(f.Symbols) == 0 {
+		return []domain.DailyClose{}, nil
+	}
+
+	where := " WHERE currency = ? AND quoted_at >= ? AND quoted_at < ?"
+	args := []any{f.Currency, f.From, f.To}
+	where += " AND symbol IN (?" + strings.Repeat(", ?", len(f.Symbols)-1) + ")"
+	for _, s := range f.Symbols {
+		args = append(args, s)
+	}
+	if f.Provider != "" {
+		where += " AND provider = ?"
+		args = append(args, f.Provider)
+	}
+
+	q := `
+SELECT q.symbol, q.provider, q.price, q.quoted_at
+FROM quotes q
+JOIN (
+	SELECT symbol, MAX(quoted_at) AS last_at
+	FROM quotes` + where + `
+	GROUP BY symbol, DATE(quoted_at)
+) d ON d.symbol = q.symbol AND d.last_at = q.quoted_at
+WHERE q.currency = ?`
+	args = append(args, f.Currency)
+	if f.Provider != "" {
+		q += " AND q.provider = ?"
+		args = append(args, f.Provider)
+	}
+	q += " ORDER BY q.symbol ASC, q.quoted_at ASC, q.provider ASC"
+
+	rows, err := r.DB.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]domain.DailyClose, 0, 64)
+	for rows.Next() {
+		var c domain.DailyClose
+		if err := rows.Scan(&c.Symbol, &c.Provider, &c.Price, &c.QuotedAt); err != nil {
+			return nil, err
+		}
+		c.QuotedAt = c.QuotedAt.UTC()
+		c.Day = c.QuotedAt.Truncate(24 * time.Hour)
+
+		// empate entre providers en el mismo instante: queda el primero
+		if n := len(out); n > 0 && out[n-1].Symbol == c.Symbol && out[n-1].Day.Equal(c.Day) {
+			continue
+		}
+		out = append(out, c)
+	}
+	return out, rows.Err()
+}
+
+// tickerWhere filtra por símbolos y pares provider/moneda; se usa en las dos
+// subconsultas de ListTickers, así que los args se repiten.
+func tickerWhere(f domain.QuoteTickerFilter) (string, []any) {
+	where := " WHERE symbol IN (?" + strings.Repeat(", ?", len(f.Symbols)-1) + ")"
+	args := make([]any, 0, len(f.Symbols)+2*len(f.Pairs))
+	for _, s := range f.Symbols {
+		args = append(args, s)
+	}
+	if len(f.Pairs) > 0 {
+		where += " AND ((provider = ? AND currency = ?)" + strings.Repeat(" OR (provider = ? AND currency = ?)", len(f.Pairs)-1) + ")"
+		for _, p := range f.Pairs {
+			args = append(args, p.Provider, p.Currency)
+		}
+	}
+	return where, args
+}
+
+// ListTickers junta el MAX(quoted_at) de cada grupo con el MAX hasta PrevAt
+// (LEFT JOIN: puede no haber referencia) en una sola consulta.
+func (r *MySQLQuoteRepository) ListTickers(ctx context.Context, f domain.QuoteTickerFilter) ([]domain.QuoteTicker, error) {
+	if len(f.Symbols) == 0 {
+		return []domain.QuoteTicker{}, nil
+	}
+
+	where, whereArgs := tickerWhere(f)
+
+	q := `
+SELECT q.symbol, q.provider, q.currency, q.price, q.quoted_at, p.price, p.quoted_at
+FROM quotes q
+JOIN (
+	SELECT symbol, provider, currency, MAX(quoted_at) AS last_at
+	FROM quotes` + where + `
+	GROUP BY symbol, provider, currency
+) l ON l.symbol = q.symbol AND l.provider = q.provider AND l.currency = q.currency AND l.last_at = q.quoted_at
+LEFT JOIN (
+	SELECT symbol, provider, currency, MAX(quoted_at) AS prev_at
+	FROM quotes` + where + ` AND quoted_at <= ?
+	GROUP BY symbol, provider, currency
+) pa ON pa.symbol = q.symbol AND pa.provider = q.provider AND pa.currency = q.currency
+LEFT JOIN quotes p ON p.symbol = pa.symbol AND p.provider = pa.provider AND p.currency = pa.currency AND p.quoted_at = pa.prev_at
+ORDER BY q.symbol ASC, q.provider ASC, q.currency ASC`
+
+	args := make([]any, 0, 2*len(whereArgs)+1)
+	args = append(args, whereArgs...)
+	args = append(args, whereArgs...)
+	args = append(args, f.PrevAt)
+
+	rows, err := r.DB.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]domain.QuoteTicker, 0, len(f.Symbols))
+	for rows.Next() {
+		var t domain.QuoteTicker
+		var prevPrice sql.NullString
+		var prevAt sql.NullTime
+		if err := rows.Scan(&t.Symbol, &t.Provider, &t.Currency, &t.Price, &t.QuotedAt, &prevPrice, &prevAt); err != nil {
+			return nil, err
+		}
+		t.QuotedAt = t.QuotedAt.UTC()
+		if prevPrice.Valid && prevAt.Valid {
+			t.PrevPrice = prevPrice.String
+			t.PrevQuotedAt = prevAt.Time.UTC()
+		}
+		out = append(out, t)
+	}
+	return out, rows.Err()
+}
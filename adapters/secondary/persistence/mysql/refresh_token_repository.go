@@ -0,0 +1,118 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/moondolphin/crypto-api/domain"
+)
+
+type MySQLRefreshTokenRepository struct {
+	DB *sql.DB
+}
+
+func NewMySQLRefreshTokenRepository(db *sql.DB) *MySQLRefreshTokenRepository {
+	return &MySQLRefreshTokenRepository{DB: db}
+}
+
+func (r *MySQLRefreshTokenRepository) Create(ctx context.Context, t domain.RefreshToken) (domain.RefreshToken, error) {
+	const q = `
+		INSERT INTO refresh_tokens (user_id, family_id, token_hash, expires_at, created_at)
+		VALUES (?, ?, ?, ?, ?)
+	`
+	res, err := r.DB.ExecContext(ctx, q, t.UserID, t.FamilyID, t.TokenHash, t.ExpiresAt.UTC(), t.CreatedAt.UTC())
+	if err != nil {
+		return domain.RefreshToken{}, err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return domain.RefreshToken{}, err
+	}
+
+	t.ID = id
+	return t, nil
+}
+
+func (r *MySQLRefreshTokenRepository) FindByHash(ctx context.Context, tokenHash string) (*domain.RefreshToken, error) {
+	const q = `
+		SELECT id, user_id, family_id, token_hash, expires_at, created_at, revoked_at
+		FROM refresh_tokens
+		WHERE token_hash = ?
+		LIMIT 1
+	`
+	var (
+		t         domain.RefreshToken
+		revokedAt sql.NullTime
+	)
+	err := r.DB.QueryRowContext(ctx, q, tokenHash).
+		Scan(&t.ID, &t.UserID, &t.FamilyID, &t.TokenHash, &t.ExpiresAt, &t.CreatedAt, &revokedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if revokedAt.Valid {
+		at := revokedAt.Time.UTC()
+		t.RevokedAt = &at
+	}
+	return &t, nil
+}
+
+func (r *MySQLRefreshTokenRepository) Revoke(ctx context.Context, id int64, at time.Time) (bool, error) {
+	// el filtro revoked_at IS NULL hace la rotación atómica: sólo un request gana
+	const q = `UPDATE refresh_tokens SET revoked_at = ? WHERE id = ? AND revoked_at IS NULL`
+	res, err := r.DB.ExecContext(ctx, q, at.UTC(), id)
+	if err != nil {
+		return false, err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
+func (r *MySQLRefreshTokenRepository) RevokeFamily(ctx context.Context, familyID string, at time.Time) error {
+	const q = `UPDATE refresh_tokens SET revoked_at = ? WHERE family_id = ? AND revoked_at IS NULL`
+	_, err := r.DB.ExecContext(ctx, q, at.UTC(), familyID)
+	return err
+}
+
+func (r *MySQLRefreshTokenRepository) RevokeByUser(ctx context.Context, userID int64, at time.Time) error {
+	const q = `UPDATE refresh_tokens SET revoked_at = ? WHERE user_id = ? AND revoked_at IS NULL`
+	_, err := r.DB.ExecContext(ctx, q, at.UTC(), userID)
+	return err
+}
+
+func (r *MySQLRefreshTokenRepository) ListActiveByUser(ctx context.Context, userID int64, now time.Time) ([]domain.RefreshToken, error) {
+	const q = `
+		SELECT id, user_id, family_id, token_hash, expires_at, created_at, revoked_at
+		FROM refresh_tokens
+		WHERE user_id = ? AND revoked_at IS NULL AND expires_at > ?
+		ORDER BY id ASC
+	`
+	rows, err := r.DB.QueryContext(ctx, q, userID, now.UTC())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []domain.RefreshToken
+	for rows.Next() {
+		var (
+			t         domain.RefreshToken
+			revokedAt sql.NullTime
+		)
+		if err := rows.Scan(&t.ID, &t.UserID, &t.FamilyID, &t.TokenHash, &t.ExpiresAt, &t.CreatedAt, &revokedAt); err != nil {
+			return nil, err
+		}
+		t.RevokedAt = timePtr(revokedAt)
+		out = append(out, t)
+	}
+	return out, rows.Err()
+}
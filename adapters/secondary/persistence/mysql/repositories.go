@@ -0,0 +1,39 @@
+package mysql
+
+import (
+	"database/sql"
+
+	"github.com/moondolphin/crypto-api/domain"
+)
+
+// NewRepositories arma todos los puertos de persistencia sobre la misma base.
+func NewRepositories(db *sql.DB) domain.Repositories {
+	return domain.Repositories{
+		Users:            NewMySQLUserRepository(db),
+		Coins:            NewMySQLCoinRepository(db),
+		Quotes:           NewMySQLQuoteRepository(db),
+		Favorites:        NewMySQLFavoritesRepository(db),
+		RefreshControl:   NewMySQLRefreshControlRepository(db),
+		RefreshTokens:    NewMySQLRefreshTokenRepository(db),
+		Revocations:      NewMySQLTokenRevocationStore(db),
+		APIKeys:          NewMySQLAPIKeyRepository(db),
+		PasswordResets:   NewMySQLPasswordResetRepository(db),
+		Verifications:    NewMySQLEmailVerificationRepository(db),
+		LoginAttempts:    NewMySQLLoginAttemptStore(db),
+		TOTP:             NewMySQLTOTPRepository(db),
+		RecoveryCodes:    NewMySQLRecoveryCodeRepository(db),
+		Challenges:       NewMySQLLoginChallengeRepository(db),
+		Identities:       NewMySQLUserIdentityRepository(db),
+		OIDCStates:       NewMySQLOIDCStateRepository(db),
+		AlertRules:       NewMySQLAlertRuleRepository(db),
+		AlertEvents:      NewMySQLAlertEventRepository(db),
+		Webhooks:         NewMySQLWebhookSubscriptionRepository(db),
+		Deliveries:       NewMySQLWebhookDeliveryRepository(db),
+		Portfolios:       NewMySQLPortfolioRepository(db),
+		Holdings:         NewMySQLHoldingRepository(db),
+		Transactions:     NewMySQLTransactionRepository(db),
+		Snapshots:        NewMySQLPortfolioSnapshotRepository(db),
+		Watchlists:       NewMySQLWatchlistRepository(db),
+		WatchlistEntries: NewMySQLWatchlistEntryRepository(db),
+	}
+}
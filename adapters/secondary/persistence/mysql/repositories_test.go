@@ -0,0 +1,63 @@
+package mysql_test
+
+import (
+	"database/sql"
+	"os"
+	"testing"
+
+	_ "github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/require"
+
+	"github.com/moondolphin/crypto-api/adapters/secondary/persistence/mysql"
+	"github.com/moondolphin/crypto-api/test/contract"
+)
+
+// MYSQL_TEST_DSN debe apuntar a una base descartable con el schema de resources/:
+// la suite borra quotes, users, favoritos, refresh_control, refresh_tokens, revocaciones, api_keys, tokens de reset/verificación y las coins "ZZ*".
+func TestMySQLRepositories_Contract(t *testing.T) {
+	dsn := os.Getenv("MYSQL_TEST_DSN")
+	if dsn == "" {
+		t.Skip("MYSQL_TEST_DSN not set")
+	}
+
+	db, err := sql.Open("mysql", dsn)
+	require.NoError(t, err)
+	require.NoError(t, db.Ping())
+	t.Cleanup(func() { _ = db.Close() })
+
+	contract.RunRepositoryContract(t, func(t *testing.T) contract.Repositories {
+		for _, stmt := range []string{
+			"DELETE FROM refresh_tokens",
+			"DELETE FROM api_keys",
+			"DELETE FROM password_reset_tokens",
+			"DELETE FROM email_verification_tokens",
+			"DELETE FROM user_totp",
+			"DELETE FROM totp_recovery_codes",
+			"DELETE FROM watchlist_entries",
+			"DELETE FROM watchlists",
+			"DELETE FROM portfolio_snapshots",
+			"DELETE FROM portfolio_transactions",
+			"DELETE FROM portfolio_holdings",
+			"DELETE FROM portfolios",
+			"DELETE FROM webhook_deliveries",
+			"DELETE FROM webhook_subscriptions",
+			"DELETE FROM alert_events",
+			"DELETE FROM alert_rules",
+			"DELETE FROM login_challenges",
+			"DELETE FROM user_identities",
+			"DELETE FROM oidc_login_states",
+			"DELETE FROM quotes",
+			"DELETE FROM users",
+			"DELETE FROM refresh_control",
+			"DELETE FROM revoked_tokens",
+			"DELETE FROM revoked_user_tokens",
+			"DELETE FROM login_attempts",
+			"DELETE FROM coins WHERE symbol LIKE 'ZZ%'",
+		} {
+			_, err := db.Exec(stmt)
+			require.NoError(t, err)
+		}
+
+		return mysql.NewRepositories(db)
+	})
+}
@@ -0,0 +1,229 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/moondolphin/crypto-api/domain"
+)
+
+type MySQLWatchlistRepository struct {
+	DB *sql.DB
+}
+
+func NewMySQLWatchlistRepository(db *sql.DB) *MySQLWatchlistRepository {
+	return &MySQLWatchlistRepository{DB: db}
+}
+
+const watchlistColumns = `id, user_id, name, default_user_id IS NOT NULL, created_at, updated_at`
+
+func scanWatchlist(s rowScanner) (domain.Watchlist, error) {
+	var w domain.Watchlist
+	err := s.Scan(&w.ID, &w.UserID, &w.Name, &w.IsDefault, &w.CreatedAt, &w.UpdatedAt)
+	return w, err
+}
+
+func (r *MySQLWatchlistRepository) Create(ctx context.Context, w domain.Watchlist) (domain.Watchlist, error) {
+	const q = `INSERT INTO watchlists (user_id, name, created_at, updated_at) VALUES (?, ?, ?, ?)`
+
+	res, err := r.DB.ExecContext(ctx, q, w.UserID, w.Name, w.CreatedAt.UTC(), w.UpdatedAt.UTC())
+	if err != nil {
+		return domain.Watchlist{}, err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return domain.Watchlist{}, err
+	}
+
+	w.ID = id
+	w.IsDefault = false
+	return w, nil
+}
+
+func (r *MySQLWatchlistRepository) EnsureDefault(ctx context.Context, w domain.Watchlist) (domain.Watchlist, error) {
+	q := `SELECT ` + watchlistColumns + ` FROM watchlists WHERE default_user_id = ?`
+
+	cur, err := scanWatchlist(r.DB.QueryRowContext(ctx, q, w.UserID))
+	if err != sql.ErrNoRows {
+		return cur, err
+	}
+
+	// si otra alta gana la carrera, el UNIQUE de default_user_id descarta esta
+	const ins = `
+		INSERT IGNORE INTO watchlists (user_id, name, default_user_id, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?)
+	`
+	if _, err := r.DB.ExecContext(ctx, ins, w.UserID, w.Name, w.UserID, w.CreatedAt.UTC(), w.UpdatedAt.UTC()); err != nil {
+		return domain.Watchlist{}, err
+	}
+
+	return scanWatchlist(r.DB.QueryRowContext(ctx, q, w.UserID))
+}
+
+func (r *MySQLWatchlistRepository) FindByUser(ctx context.Context, userID, id int64) (*domain.Watchlist, error) {
+	q := `SELECT ` + watchlistColumns + ` FROM watchlists WHERE id = ? AND user_id = ? LIMIT 1`
+
+	w, err := scanWatchlist(r.DB.QueryRowContext(ctx, q, id, userID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &w, nil
+}
+
+func (r *MySQLWatchlistRepository) ListByUser(ctx context.Context, userID int64) ([]domain.Watchlist, error) {
+	q := `SELECT ` + watchlistColumns + ` FROM watchlists WHERE user_id = ? ORDER BY default_user_id IS NULL, id ASC`
+
+	rows, err := r.DB.QueryContext(ctx, q, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []domain.Watchlist
+	for rows.Next() {
+		w, err := scanWatchlist(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, w)
+	}
+	return out, rows.Err()
+}
+
+func (r *MySQLWatchlistRepository) CountByUser(ctx context.Context, userID int64) (int, error) {
+	var n int
+	err := r.DB.QueryRowContext(ctx, `SELECT COUNT(*) FROM watchlists WHERE user_id = ?`, userID).Scan(&n)
+	return n, err
+}
+
+func (r *MySQLWatchlistRepository) Update(ctx context.Context, w domain.Watchlist) (bool, error) {
+	const q = `UPDATE watchlists SET name = ?, updated_at = ? WHERE id = ? AND user_id = ?`
+
+	res, err := r.DB.ExecContext(ctx, q, w.Name, w.UpdatedAt.UTC(), w.ID, w.UserID)
+	if err != nil {
+		return false, err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
+func (r *MySQLWatchlistRepository) Delete(ctx context.Context, userID, id int64) (bool, error) {
+	const q = `DELETE FROM watchlists WHERE id = ? AND user_id = ? AND default_user_id IS NULL`
+
+	res, err := r.DB.ExecContext(ctx, q, id, userID)
+	if err != nil {
+		return false, err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
+type MySQLWatchlistEntryRepository struct {
+	DB *sql.DB
+}
+
+func NewMySQLWatchlistEntryRepository(db *sql.DB) *MySQLWatchlistEntryRepository {
+	return &MySQLWatchlistEntryRepository{DB: db}
+}
+
+func (r *MySQLWatchlistEntryRepository) Add(ctx context.Context, e domain.WatchlistEntry) (bool, error) {
+	// la posición se calcula en el mismo INSERT: queda al final aunque haya
+	// altas concurrentes (a lo sumo empatan, y el orden cae al símbolo)
+	const q = `
+		INSERT IGNORE INTO watchlist_entries (watchlist_id, coin_id, sort_order, note, created_at, updated_at)
+		SELECT ?, ?, COALESCE(MAX(sort_order) + 1, 0), ?, ?, ?
+		FROM watchlist_entries WHERE watchlist_id = ?
+	`
+	res, err := r.DB.ExecContext(ctx, q, e.WatchlistID, e.CoinID, e.Note, e.CreatedAt.UTC(), e.UpdatedAt.UTC(), e.WatchlistID)
+	if err != nil {
+		return false, err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
+func (r *MySQLWatchlistEntryRepository) UpdateNote(ctx context.Context, watchlistID, coinID int64, note string, at time.Time) (bool, error) {
+	const q = `UPDATE watchlist_entries SET note = ?, updated_at = ? WHERE watchlist_id = ? AND coin_id = ?`
+
+	res, err := r.DB.ExecContext(ctx, q, note, at.UTC(), watchlistID, coinID)
+	if err != nil {
+		return false, err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
+func (r *MySQLWatchlistEntryRepository) Remove(ctx context.Context, watchlistID, coinID int64) (bool, error) {
+	res, err := r.DB.ExecContext(ctx, `DELETE FROM watchlist_entries WHERE watchlist_id = ? AND coin_id = ?`, watchlistID, coinID)
+	if err != nil {
+		return false, err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
+func (r *MySQLWatchlistEntryRepository) ListByWatchlist(ctx context.Context, watchlistID int64) ([]domain.WatchlistEntry, error) {
+	const q = `
+		SELECT e.watchlist_id, e.coin_id, c.symbol, e.sort_order, e.note, e.created_at, e.updated_at
+		FROM watchlist_entries e
+		JOIN coins c ON c.id = e.coin_id
+		WHERE e.watchlist_id = ?
+		ORDER BY e.sort_order ASC, c.symbol ASC
+	`
+	rows, err := r.DB.QueryContext(ctx, q, watchlistID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []domain.WatchlistEntry
+	for rows.Next() {
+		var e domain.WatchlistEntry
+		if err := rows.Scan(&e.WatchlistID, &e.CoinID, &e.Symbol, &e.Position, &e.Note, &e.CreatedAt, &e.UpdatedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, e)
+	}
+	return out, rows.Err()
+}
+
+func (r *MySQLWatchlistEntryRepository) Reorder(ctx context.Context, watchlistID int64, coinIDs []int64) error {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	const q = `UPDATE watchlist_entries SET sort_order = ? WHERE watchlist_id = ? AND coin_id = ?`
+	for i, coinID := range coinIDs {
+		if _, err := tx.ExecContext(ctx, q, i, watchlistID, coinID); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,234 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/moondolphin/crypto-api/domain"
+)
+
+type PostgresAlertRuleRepository struct {
+	DB *sql.DB
+}
+
+func NewPostgresAlertRuleRepository(db *sql.DB) *PostgresAlertRuleRepository {
+	return &PostgresAlertRuleRepository{DB: db}
+}
+
+const alertRuleColumns = `id, user_id, coin_id, symbol, currency, provider, kind, threshold, window_seconds,
+		rearm, active, armed, last_triggered_at, created_at, updated_at`
+
+func scanAlertRule(s rowScanner) (domain.AlertRule, error) {
+	var (
+		r             domain.AlertRule
+		windowSeconds int64
+		lastTriggered sql.NullTime
+	)
+	err := s.Scan(&r.ID, &r.UserID, &r.CoinID, &r.Symbol, &r.Currency, &r.Provider, &r.Kind, &r.Threshold, &windowSeconds,
+		&r.Rearm, &r.Active, &r.Armed, &lastTriggered, &r.CreatedAt, &r.UpdatedAt)
+	if err != nil {
+		return domain.AlertRule{}, err
+	}
+
+	r.Window = time.Duration(windowSeconds) * time.Second
+	r.LastTriggeredAt = timePtr(lastTriggered)
+	r.CreatedAt = r.CreatedAt.UTC()
+	r.UpdatedAt = r.UpdatedAt.UTC()
+	return r, nil
+}
+
+func (r *PostgresAlertRuleRepository) list(ctx context.Context, q string, args ...any) ([]domain.AlertRule, error) {
+	rows, err := r.DB.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []domain.AlertRule
+	for rows.Next() {
+		a, err := scanAlertRule(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, a)
+	}
+	return out, rows.Err()
+}
+
+func (r *PostgresAlertRuleRepository) Create(ctx context.Context, a domain.AlertRule) (domain.AlertRule, error) {
+	const q = `
+		INSERT INTO alert_rules (user_id, coin_id, symbol, currency, provider, kind, threshold, window_seconds,
+			rearm, active, armed, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+		RETURNING id
+	`
+	err := r.DB.QueryRowContext(ctx, q, a.UserID, a.CoinID, a.Symbol, a.Currency, a.Provider, a.Kind, a.Threshold,
+		int64(a.Window/time.Second), a.Rearm, a.Active, a.Armed, a.CreatedAt.UTC(), a.UpdatedAt.UTC()).Scan(&a.ID)
+	if err != nil {
+		return domain.AlertRule{}, err
+	}
+
+	a.LastTriggeredAt = nil
+	return a, nil
+}
+
+func (r *PostgresAlertRuleRepository) FindByUser(ctx context.Context, userID, id int64) (*domain.AlertRule, error) {
+	q := `SELECT ` + alertRuleColumns + ` FROM alert_rules WHERE id = $1 AND user_id = $2 LIMIT 1`
+
+	a, err := scanAlertRule(r.DB.QueryRowContext(ctx, q, id, userID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+func (r *PostgresAlertRuleRepository) ListByUser(ctx context.Context, userID int64) ([]domain.AlertRule, error) {
+	return r.list(ctx, `SELECT `+alertRuleColumns+` FROM alert_rules WHERE user_id = $1 ORDER BY id ASC`, userID)
+}
+
+func (r *PostgresAlertRuleRepository) CountByUser(ctx context.Context, userID int64) (int, error) {
+	var n int
+	err := r.DB.QueryRowContext(ctx, `SELECT COUNT(*) FROM alert_rules WHERE user_id = $1`, userID).Scan(&n)
+	return n, err
+}
+
+func (r *PostgresAlertRuleRepository) ListActive(ctx context.Context) ([]domain.AlertRule, error) {
+	return r.list(ctx, `SELECT `+alertRuleColumns+` FROM alert_rules WHERE active = $1 ORDER BY id ASC`, true)
+}
+
+func (r *PostgresAlertRuleRepository) Update(ctx context.Context, a domain.AlertRule) (bool, error) {
+	const q = `
+		UPDATE alert_rules
+		SET threshold = $1, window_seconds = $2, rearm = $3, active = $4, armed = $5, updated_at = $6
+		WHERE id = $7 AND user_id = $8
+	`
+	res, err := r.DB.ExecContext(ctx, q, a.Threshold, int64(a.Window/time.Second), a.Rearm, a.Active, a.Armed,
+		a.UpdatedAt.UTC(), a.ID, a.UserID)
+	if err != nil {
+		return false, err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
+func (r *PostgresAlertRuleRepository) Delete(ctx context.Context, userID, id int64) (bool, error) {
+	res, err := r.DB.ExecContext(ctx, `DELETE FROM alert_rules WHERE id = $1 AND user_id = $2`, id, userID)
+	if err != nil {
+		return false, err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
+func (r *PostgresAlertRuleRepository) MarkTriggered(ctx context.Context, id int64, at time.Time, deactivate bool) (bool, error) {
+	const q = `
+		UPDATE alert_rules
+		SET armed = $1, active = active AND $2, last_triggered_at = $3
+		WHERE id = $4 AND active = $5 AND armed = $6
+	`
+	res, err := r.DB.ExecContext(ctx, q, false, !deactivate, at.UTC(), id, true, true)
+	if err != nil {
+		return false, err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
+func (r *PostgresAlertRuleRepository) Rearm(ctx context.Context, id int64, at time.Time) error {
+	const q = `UPDATE alert_rules SET armed = $1, updated_at = $2 WHERE id = $3 AND active = $4 AND armed = $5`
+	_, err := r.DB.ExecContext(ctx, q, true, at.UTC(), id, true, false)
+	return err
+}
+
+type PostgresAlertEventRepository struct {
+	DB *sql.DB
+}
+
+func NewPostgresAlertEventRepository(db *sql.DB) *PostgresAlertEventRepository {
+	return &PostgresAlertEventRepository{DB: db}
+}
+
+func (r *PostgresAlertEventRepository) Create(ctx context.Context, e domain.AlertEvent) (domain.AlertEvent, error) {
+	const q = `
+		INSERT INTO alert_events (rule_id, user_id, symbol, provider, currency, kind, threshold, price, quoted_at,
+			reference_price, change_percent, triggered_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		RETURNING id
+	`
+	var ref sql.NullString
+	if e.ReferencePrice != "" {
+		ref = sql.NullString{String: e.ReferencePrice, Valid: true}
+	}
+	var change sql.NullFloat64
+	if e.ChangePercent != nil {
+		change = sql.NullFloat64{Float64: *e.ChangePercent, Valid: true}
+	}
+
+	err := r.DB.QueryRowContext(ctx, q, e.RuleID, e.UserID, e.Symbol, e.Provider, e.Currency, e.Kind, e.Threshold,
+		e.Price, e.QuotedAt.UTC(), ref, change, e.TriggeredAt.UTC()).Scan(&e.ID)
+	if err != nil {
+		return domain.AlertEvent{}, err
+	}
+
+	return e, nil
+}
+
+func (r *PostgresAlertEventRepository) ListByUser(ctx context.Context, userID, ruleID int64, limit int) ([]domain.AlertEvent, error) {
+	q := `
+		SELECT id, rule_id, user_id, symbol, provider, currency, kind, threshold, price, quoted_at,
+			reference_price, change_percent, triggered_at
+		FROM alert_events
+		WHERE user_id = $1`
+	args := []any{userID}
+	if ruleID > 0 {
+		args = append(args, ruleID)
+		q += fmt.Sprintf(` AND rule_id = $%d`, len(args))
+	}
+	args = append(args, limit)
+	q += fmt.Sprintf(` ORDER BY triggered_at DESC, id DESC LIMIT $%d`, len(args))
+
+	rows, err := r.DB.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []domain.AlertEvent
+	for rows.Next() {
+		var (
+			e      domain.AlertEvent
+			ref    sql.NullString
+			change sql.NullFloat64
+		)
+		if err := rows.Scan(&e.ID, &e.RuleID, &e.UserID, &e.Symbol, &e.Provider, &e.Currency, &e.Kind, &e.Threshold,
+			&e.Price, &e.QuotedAt, &ref, &change, &e.TriggeredAt); err != nil {
+			return nil, err
+		}
+		e.QuotedAt = e.QuotedAt.UTC()
+		e.TriggeredAt = e.TriggeredAt.UTC()
+		e.ReferencePrice = ref.String
+		if change.Valid {
+			v := change.Float64
+			e.ChangePercent = &v
+		}
+		out = append(out, e)
+	}
+	return out, rows.Err()
+}
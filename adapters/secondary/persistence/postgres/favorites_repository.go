@@ -0,0 +1,81 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/moondolphin/crypto-api/domain"
+)
+
+// PostgresFavoritesRepository trabaja sobre la watchlist por defecto del
+// usuario; la crea en la primera alta.
+type PostgresFavoritesRepository struct {
+	DB *sql.DB
+}
+
+func NewPostgresFavoritesRepository(db *sql.DB) *PostgresFavoritesRepository {
+	return &PostgresFavoritesRepository{DB: db}
+}
+
+// Idempotente: si ya existe (user_id, coin_id)
+func (r *PostgresFavoritesRepository) AddFavoriteCoinToUser(ctx context.Context, userID, coinID int64) error {
+	now := time.Now().UTC()
+	w, err := NewPostgresWatchlistRepository(r.DB).EnsureDefault(ctx, domain.Watchlist{
+		UserID:    userID,
+		Name:      domain.DefaultWatchlistName,
+		CreatedAt: now,
+		UpdatedAt: now,
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = NewPostgresWatchlistEntryRepository(r.DB).Add(ctx, domain.WatchlistEntry{
+		WatchlistID: w.ID,
+		CoinID:      coinID,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	})
+	return err
+}
+
+// Idempotente: si no existe
+func (r *PostgresFavoritesRepository) RemoveFavoriteCoinFromUser(ctx context.Context, userID, coinID int64) error {
+	const q = `
+		DELETE FROM watchlist_entries
+		WHERE coin_id = $1 AND watchlist_id IN (SELECT id FROM watchlists WHERE default_user_id = $2)
+	`
+	_, err := r.DB.ExecContext(ctx, q, coinID, userID)
+	return err
+}
+
+func (r *PostgresFavoritesRepository) ListFavoriteCoinIDsByUser(ctx context.Context, userID int64) ([]domain.Coin, error) {
+	const q = `
+		SELECT c.id, c.symbol, c.enabled, c.coingecko_id, c.binance_symbol
+		FROM watchlists w
+		JOIN watchlist_entries e ON e.watchlist_id = w.id
+		JOIN coins c ON c.id = e.coin_id
+		WHERE w.default_user_id = $1
+		ORDER BY c.symbol ASC
+	`
+
+	rows, err := r.DB.QueryContext(ctx, q, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]domain.Coin, 0, 16)
+	for rows.Next() {
+		var c domain.Coin
+		if err := rows.Scan(&c.ID, &c.Symbol, &c.Enabled, &c.CoinGeckoID, &c.BinanceSymbol); err != nil {
+			return nil, err
+		}
+		out = append(out, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
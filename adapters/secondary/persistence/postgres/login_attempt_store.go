@@ -0,0 +1,79 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/moondolphin/crypto-api/domain"
+)
+
+type PostgresLoginAttemptStore struct {
+	DB *sql.DB
+}
+
+func NewPostgresLoginAttemptStore(db *sql.DB) *PostgresLoginAttemptStore {
+	return &PostgresLoginAttemptStore{DB: db}
+}
+
+func (s *PostgresLoginAttemptStore) Get(ctx context.Context, key string) (*domain.LoginAttempt, error) {
+	const q = `
+		SELECT attempt_key, failures, last_failure_at, locked_until
+		FROM login_attempts
+		WHERE attempt_key = $1
+	`
+	var (
+		a           domain.LoginAttempt
+		lockedUntil sql.NullTime
+	)
+	err := s.DB.QueryRowContext(ctx, q, key).Scan(&a.Key, &a.Failures, &a.LastFailureAt, &lockedUntil)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	a.LastFailureAt = a.LastFailureAt.UTC()
+	a.LockedUntil = timePtr(lockedUntil)
+	return &a, nil
+}
+
+func (s *PostgresLoginAttemptStore) RecordFailure(ctx context.Context, key string, at, resetBefore time.Time) (int, error) {
+	// el upsert incrementa en una sola sentencia: dos fallos concurrentes no se pisan
+	const q = `
+		INSERT INTO login_attempts (attempt_key, failures, last_failure_at)
+		VALUES ($1, 1, $2)
+		ON CONFLICT (attempt_key) DO UPDATE SET
+			failures = CASE WHEN GREATEST(login_attempts.last_failure_at, login_attempts.locked_until) < $3 THEN 1 ELSE login_attempts.failures + 1 END,
+			last_failure_at = excluded.last_failure_at
+		RETURNING failures
+	`
+	var failures int
+	err := s.DB.QueryRowContext(ctx, q, key, at.UTC(), resetBefore.UTC()).Scan(&failures)
+	return failures, err
+}
+
+func (s *PostgresLoginAttemptStore) Lock(ctx context.Context, key string, until time.Time) error {
+	const q = `UPDATE login_attempts SET locked_until = $1 WHERE attempt_key = $2`
+	_, err := s.DB.ExecContext(ctx, q, until.UTC(), key)
+	return err
+}
+
+func (s *PostgresLoginAttemptStore) Reset(ctx context.Context, key string) error {
+	const q = `DELETE FROM login_attempts WHERE attempt_key = $1`
+	_, err := s.DB.ExecContext(ctx, q, key)
+	return err
+}
+
+func (s *PostgresLoginAttemptStore) DeleteStale(ctx context.Context, before time.Time) (int64, error) {
+	const q = `
+		DELETE FROM login_attempts
+		WHERE last_failure_at < $1 AND (locked_until IS NULL OR locked_until < $1)
+	`
+	res, err := s.DB.ExecContext(ctx, q, before.UTC())
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
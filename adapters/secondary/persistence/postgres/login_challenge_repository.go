@@ -0,0 +1,78 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/moondolphin/crypto-api/domain"
+)
+
+type PostgresLoginChallengeRepository struct {
+	DB *sql.DB
+}
+
+func NewPostgresLoginChallengeRepository(db *sql.DB) *PostgresLoginChallengeRepository {
+	return &PostgresLoginChallengeRepository{DB: db}
+}
+
+func (r *PostgresLoginChallengeRepository) Create(ctx context.Context, c domain.LoginChallenge) (domain.LoginChallenge, error) {
+	const q = `
+		INSERT INTO login_challenges (user_id, token_hash, expires_at, created_at)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id
+	`
+	if err := r.DB.QueryRowContext(ctx, q, c.UserID, c.TokenHash, c.ExpiresAt.UTC(), c.CreatedAt.UTC()).Scan(&c.ID); err != nil {
+		return domain.LoginChallenge{}, err
+	}
+	return c, nil
+}
+
+func (r *PostgresLoginChallengeRepository) FindByHash(ctx context.Context, tokenHash string) (*domain.LoginChallenge, error) {
+	const q = `
+		SELECT id, user_id, token_hash, expires_at, created_at, used_at
+		FROM login_challenges
+		WHERE token_hash = $1
+		LIMIT 1
+	`
+	var (
+		c      domain.LoginChallenge
+		usedAt sql.NullTime
+	)
+	err := r.DB.QueryRowContext(ctx, q, tokenHash).
+		Scan(&c.ID, &c.UserID, &c.TokenHash, &c.ExpiresAt, &c.CreatedAt, &usedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	c.ExpiresAt = c.ExpiresAt.UTC()
+	c.CreatedAt = c.CreatedAt.UTC()
+	c.UsedAt = timePtr(usedAt)
+	return &c, nil
+}
+
+func (r *PostgresLoginChallengeRepository) MarkUsed(ctx context.Context, id int64, at time.Time) (bool, error) {
+	const q = `UPDATE login_challenges SET used_at = $1 WHERE id = $2 AND used_at IS NULL`
+	res, err := r.DB.ExecContext(ctx, q, at.UTC(), id)
+	if err != nil {
+		return false, err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
+func (r *PostgresLoginChallengeRepository) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	const q = `DELETE FROM login_challenges WHERE expires_at < $1`
+	res, err := r.DB.ExecContext(ctx, q, now.UTC())
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
@@ -0,0 +1,97 @@
+CREATE TABLE IF NOT EXISTS coins (
+  id BIGSERIAL PRIMARY KEY,
+  symbol VARCHAR(16) NOT NULL UNIQUE,
+  enabled BOOLEAN NOT NULL DEFAULT TRUE,
+  coingecko_id VARCHAR(64) NOT NULL DEFAULT '',
+  binance_symbol VARCHAR(32) NOT NULL DEFAULT '',
+  created_at TIMESTAMPTZ DEFAULT now()
+);
+
+INSERT INTO coins (symbol, enabled, coingecko_id, binance_symbol) VALUES
+('BTC', TRUE, 'bitcoin', 'BTCUSDT'),
+('ETH', TRUE, 'ethereum', 'ETHUSDT'),
+('BNB', TRUE, 'binancecoin', 'BNBUSDT'),
+('SOL', TRUE, 'solana', 'SOLUSDT'),
+('XRP', TRUE, 'ripple', 'XRPUSDT'),
+('ADA', TRUE, 'cardano', 'ADAUSDT'),
+('DOGE', TRUE, 'dogecoin', 'DOGEUSDT'),
+('AVAX', TRUE, 'avalanche-2', 'AVAXUSDT'),
+('TRX', TRUE, 'tron', 'TRXUSDT'),
+('DOT', TRUE, 'polkadot', 'DOTUSDT'),
+('MATIC', TRUE, 'matic-network', 'MATICUSDT'),
+('LINK', TRUE, 'chainlink', 'LINKUSDT'),
+('LTC', TRUE, 'litecoin', 'LTCUSDT'),
+('BCH', TRUE, 'bitcoin-cash', 'BCHUSDT'),
+('ATOM', TRUE, 'cosmos', 'ATOMUSDT'),
+('ETC', TRUE, 'ethereum-classic', 'ETCUSDT'),
+('FIL', TRUE, 'filecoin', 'FILUSDT'),
+('ICP', TRUE, 'internet-computer', 'ICPUSDT'),
+('APT', TRUE, 'aptos', 'APTUSDT'),
+('ARB', TRUE, 'arbitrum', 'ARBUSDT'),
+('OP', TRUE, 'optimism', 'OPUSDT'),
+('NEAR', TRUE, 'near', 'NEARUSDT'),
+('ALGO', TRUE, 'algorand', 'ALGOUSDT'),
+('VET', TRUE, 'vechain', 'VETUSDT'),
+('HBAR', TRUE, 'hedera-hashgraph', 'HBARUSDT'),
+('SAND', TRUE, 'the-sandbox', 'SANDUSDT'),
+('MANA', TRUE, 'decentraland', 'MANAUSDT'),
+('EGLD', TRUE, 'elrond-erd-2', 'EGLDUSDT'),
+('AAVE', TRUE, 'aave', 'AAVEUSDT'),
+('AXS', TRUE, 'axie-infinity', 'AXSUSDT'),
+('XTZ', TRUE, 'tezos', 'XTZUSDT'),
+('THETA', TRUE, 'theta-token', 'THETAUSDT'),
+('EOS', TRUE, 'eos', 'EOSUSDT'),
+('KLAY', TRUE, 'klay-token', 'KLAYUSDT'),
+('FLOW', TRUE, 'flow', 'FLOWUSDT'),
+('GALA', TRUE, 'gala', 'GALAUSDT'),
+('CHZ', TRUE, 'chiliz', 'CHZUSDT'),
+('ENJ', TRUE, 'enjincoin', 'ENJUSDT'),
+('DYDX', TRUE, 'dydx', 'DYDXUSDT'),
+('CRV', TRUE, 'curve-dao-token', 'CRVUSDT'),
+('SNX', TRUE, 'synthetix-network-token', 'SNXUSDT'),
+('COMP', TRUE, 'compound-governance-token', 'COMPUSDT'),
+('KSM', TRUE, 'kusama', 'KSMUSDT'),
+('ZIL', TRUE, 'zilliqa', 'ZILUSDT'),
+('1INCH', TRUE, '1inch', '1INCHUSDT'),
+('BAT', TRUE, 'basic-attention-token', 'BATUSDT'),
+('ANKR', TRUE, 'ankr', 'ANKRUSDT'),
+('CELO', TRUE, 'celo', 'CELOUSDT')
+ON CONFLICT (symbol) DO NOTHING;
+
+CREATE TABLE IF NOT EXISTS quotes (
+  id BIGSERIAL PRIMARY KEY,
+  coin_id BIGINT NOT NULL REFERENCES coins(id),
+  symbol VARCHAR(20) NOT NULL,
+  provider VARCHAR(50) NOT NULL,
+  currency VARCHAR(10) NOT NULL,
+  price NUMERIC(30,10) NOT NULL,
+  quoted_at TIMESTAMPTZ NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_quotes_coin_time ON quotes (coin_id, quoted_at);
+CREATE INDEX IF NOT EXISTS idx_quotes_provider ON quotes (provider);
+
+CREATE TABLE IF NOT EXISTS users (
+  id BIGSERIAL PRIMARY KEY,
+  email VARCHAR(255) NOT NULL,
+  name VARCHAR(120) NOT NULL,
+  password_hash VARCHAR(255) NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  CONSTRAINT uk_users_email UNIQUE (email)
+);
+
+CREATE TABLE IF NOT EXISTS user_favorites (
+  user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  coin_id BIGINT NOT NULL REFERENCES coins(id) ON DELETE CASCADE,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  PRIMARY KEY (user_id, coin_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_fav_coin ON user_favorites (coin_id);
+
+CREATE TABLE IF NOT EXISTS refresh_control (
+  key VARCHAR(64) NOT NULL PRIMARY KEY,
+  value VARCHAR(255) NOT NULL,
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
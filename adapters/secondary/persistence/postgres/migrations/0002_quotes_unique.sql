@@ -0,0 +1,11 @@
+-- elimina cotizaciones duplicadas (se conserva la de menor id) y agrega la unicidad
+DELETE FROM quotes a
+USING quotes b
+WHERE a.coin_id = b.coin_id
+  AND a.provider = b.provider
+  AND a.currency = b.currency
+  AND a.quoted_at = b.quoted_at
+  AND a.id > b.id;
+
+CREATE UNIQUE INDEX IF NOT EXISTS uk_quotes_coin_provider_currency_time
+  ON quotes (coin_id, provider, currency, quoted_at);
@@ -0,0 +1,38 @@
+CREATE TABLE IF NOT EXISTS alert_rules (
+  id BIGSERIAL PRIMARY KEY,
+  user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  coin_id BIGINT NOT NULL REFERENCES coins(id) ON DELETE CASCADE,
+  symbol VARCHAR(20) NOT NULL,
+  currency VARCHAR(10) NOT NULL DEFAULT '',
+  provider VARCHAR(50) NOT NULL DEFAULT '',
+  kind VARCHAR(20) NOT NULL,
+  threshold DOUBLE PRECISION NOT NULL,
+  window_seconds INTEGER NOT NULL DEFAULT 0,
+  rearm BOOLEAN NOT NULL DEFAULT FALSE,
+  active BOOLEAN NOT NULL DEFAULT TRUE,
+  armed BOOLEAN NOT NULL DEFAULT TRUE,
+  last_triggered_at TIMESTAMPTZ NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_alert_rules_user ON alert_rules (user_id);
+CREATE INDEX IF NOT EXISTS idx_alert_rules_active ON alert_rules (active);
+
+CREATE TABLE IF NOT EXISTS alert_events (
+  id BIGSERIAL PRIMARY KEY,
+  rule_id BIGINT NOT NULL REFERENCES alert_rules(id) ON DELETE CASCADE,
+  user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  symbol VARCHAR(20) NOT NULL,
+  provider VARCHAR(50) NOT NULL,
+  currency VARCHAR(10) NOT NULL,
+  kind VARCHAR(20) NOT NULL,
+  threshold DOUBLE PRECISION NOT NULL,
+  price NUMERIC(30,10) NOT NULL,
+  quoted_at TIMESTAMPTZ NOT NULL,
+  reference_price NUMERIC(30,10) NULL,
+  change_percent DOUBLE PRECISION NULL,
+  triggered_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_alert_events_user ON alert_events (user_id, triggered_at);
@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS portfolios (
+  id BIGSERIAL PRIMARY KEY,
+  user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  name VARCHAR(100) NOT NULL,
+  base_currency VARCHAR(10) NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_portfolios_user ON portfolios (user_id);
+
+CREATE TABLE IF NOT EXISTS portfolio_holdings (
+  id BIGSERIAL PRIMARY KEY,
+  portfolio_id BIGINT NOT NULL REFERENCES portfolios(id) ON DELETE CASCADE,
+  coin_id BIGINT NOT NULL REFERENCES coins(id) ON DELETE CASCADE,
+  quantity NUMERIC(38,18) NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  UNIQUE (portfolio_id, coin_id)
+);
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS portfolio_transactions (
+  id BIGSERIAL PRIMARY KEY,
+  portfolio_id BIGINT NOT NULL REFERENCES portfolios(id) ON DELETE CASCADE,
+  coin_id BIGINT NOT NULL REFERENCES coins(id) ON DELETE CASCADE,
+  type VARCHAR(20) NOT NULL,
+  quantity NUMERIC(38,18) NOT NULL,
+  price NUMERIC(38,18) NOT NULL,
+  fee NUMERIC(38,18) NOT NULL,
+  currency VARCHAR(10) NOT NULL,
+  executed_at TIMESTAMPTZ NOT NULL,
+  note VARCHAR(255) NOT NULL DEFAULT '',
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_portfolio_transactions_time ON portfolio_transactions (portfolio_id, executed_at);
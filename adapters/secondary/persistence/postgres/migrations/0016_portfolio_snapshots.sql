@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS portfolio_snapshots (
+  portfolio_id BIGINT NOT NULL REFERENCES portfolios(id) ON DELETE CASCADE,
+  day DATE NOT NULL,
+  currency VARCHAR(10) NOT NULL,
+  value NUMERIC(38,18) NOT NULL,
+  computed_at TIMESTAMPTZ NOT NULL,
+  PRIMARY KEY (portfolio_id, day, currency)
+);
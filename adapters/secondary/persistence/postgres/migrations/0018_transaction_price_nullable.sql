@@ -0,0 +1,5 @@
+ALTER TABLE portfolio_transactions ALTER COLUMN price DROP NOT NULL;
+
+-- los transfer_in con 0 se cargaron sin precio (antes no había otra forma de
+-- guardarlo): quedan en NULL, como los trataba el reporte impositivo
+UPDATE portfolio_transactions SET price = NULL WHERE type = 'transfer_in' AND price = 0;
@@ -0,0 +1,144 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/moondolphin/crypto-api/domain"
+)
+
+type PostgresUserIdentityRepository struct {
+	DB *sql.DB
+}
+
+func NewPostgresUserIdentityRepository(db *sql.DB) *PostgresUserIdentityRepository {
+	return &PostgresUserIdentityRepository{DB: db}
+}
+
+func (r *PostgresUserIdentityRepository) FindBySubject(ctx context.Context, issuer, subject string) (*domain.UserIdentity, error) {
+	const q = `
+		SELECT id, user_id, issuer, subject, email, created_at
+		FROM user_identities
+		WHERE issuer = $1 AND subject = $2
+		LIMIT 1
+	`
+	var i domain.UserIdentity
+	err := r.DB.QueryRowContext(ctx, q, issuer, subject).
+		Scan(&i.ID, &i.UserID, &i.Issuer, &i.Subject, &i.Email, &i.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	i.CreatedAt = i.CreatedAt.UTC()
+	return &i, nil
+}
+
+func (r *PostgresUserIdentityRepository) Create(ctx context.Context, i domain.UserIdentity) (domain.UserIdentity, error) {
+	const q = `
+		INSERT INTO user_identities (user_id, issuer, subject, email, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id
+	`
+	if err := r.DB.QueryRowContext(ctx, q, i.UserID, i.Issuer, i.Subject, i.Email, i.CreatedAt.UTC()).Scan(&i.ID); err != nil {
+		return domain.UserIdentity{}, err
+	}
+	return i, nil
+}
+
+func (r *PostgresUserIdentityRepository) ListByUser(ctx context.Context, userID int64) ([]domain.UserIdentity, error) {
+	const q = `
+		SELECT id, user_id, issuer, subject, email, created_at
+		FROM user_identities
+		WHERE user_id = $1
+		ORDER BY id ASC
+	`
+	rows, err := r.DB.QueryContext(ctx, q, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []domain.UserIdentity
+	for rows.Next() {
+		var i domain.UserIdentity
+		if err := rows.Scan(&i.ID, &i.UserID, &i.Issuer, &i.Subject, &i.Email, &i.CreatedAt); err != nil {
+			return nil, err
+		}
+		i.CreatedAt = i.CreatedAt.UTC()
+		out = append(out, i)
+	}
+	return out, rows.Err()
+}
+
+type PostgresOIDCStateRepository struct {
+	DB *sql.DB
+}
+
+func NewPostgresOIDCStateRepository(db *sql.DB) *PostgresOIDCStateRepository {
+	return &PostgresOIDCStateRepository{DB: db}
+}
+
+func (r *PostgresOIDCStateRepository) Create(ctx context.Context, s domain.OIDCLoginState) (domain.OIDCLoginState, error) {
+	const q = `
+		INSERT INTO oidc_login_states (state_hash, nonce, code_verifier, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id
+	`
+	if err := r.DB.QueryRowContext(ctx, q, s.StateHash, s.Nonce, s.CodeVerifier, s.ExpiresAt.UTC(), s.CreatedAt.UTC()).Scan(&s.ID); err != nil {
+		return domain.OIDCLoginState{}, err
+	}
+	return s, nil
+}
+
+func (r *PostgresOIDCStateRepository) FindByHash(ctx context.Context, stateHash string) (*domain.OIDCLoginState, error) {
+	const q = `
+		SELECT id, state_hash, nonce, code_verifier, expires_at, created_at, used_at
+		FROM oidc_login_states
+		WHERE state_hash = $1
+		LIMIT 1
+	`
+	var (
+		s      domain.OIDCLoginState
+		usedAt sql.NullTime
+	)
+	err := r.DB.QueryRowContext(ctx, q, stateHash).
+		Scan(&s.ID, &s.StateHash, &s.Nonce, &s.CodeVerifier, &s.ExpiresAt, &s.CreatedAt, &usedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	s.ExpiresAt = s.ExpiresAt.UTC()
+	s.CreatedAt = s.CreatedAt.UTC()
+	s.UsedAt = timePtr(usedAt)
+	return &s, nil
+}
+
+func (r *PostgresOIDCStateRepository) MarkUsed(ctx context.Context, id int64, at time.Time) (bool, error) {
+	const q = `UPDATE oidc_login_states SET used_at = $1 WHERE id = $2 AND used_at IS NULL`
+	res, err := r.DB.ExecContext(ctx, q, at.UTC(), id)
+	if err != nil {
+		return false, err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
+func (r *PostgresOIDCStateRepository) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	const q = `DELETE FROM oidc_login_states WHERE expires_at < $1`
+	res, err := r.DB.ExecContext(ctx, q, now.UTC())
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
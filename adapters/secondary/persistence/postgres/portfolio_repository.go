@@ -0,0 +1,179 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/moondolphin/crypto-api/domain"
+)
+
+type PostgresPortfolioRepository struct {
+	DB *sql.DB
+}
+
+func NewPostgresPortfolioRepository(db *sql.DB) *PostgresPortfolioRepository {
+	return &PostgresPortfolioRepository{DB: db}
+}
+
+const portfolioColumns = `id, user_id, name, base_currency, created_at, updated_at`
+
+func scanPortfolio(s rowScanner) (domain.Portfolio, error) {
+	var p domain.Portfolio
+	if err := s.Scan(&p.ID, &p.UserID, &p.Name, &p.BaseCurrency, &p.CreatedAt, &p.UpdatedAt); err != nil {
+		return domain.Portfolio{}, err
+	}
+	p.CreatedAt = p.CreatedAt.UTC()
+	p.UpdatedAt = p.UpdatedAt.UTC()
+	return p, nil
+}
+
+func (r *PostgresPortfolioRepository) Create(ctx context.Context, p domain.Portfolio) (domain.Portfolio, error) {
+	const q = `
+		INSERT INTO portfolios (user_id, name, base_currency, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id
+	`
+	err := r.DB.QueryRowContext(ctx, q, p.UserID, p.Name, p.BaseCurrency, p.CreatedAt.UTC(), p.UpdatedAt.UTC()).Scan(&p.ID)
+	if err != nil {
+		return domain.Portfolio{}, err
+	}
+	return p, nil
+}
+
+func (r *PostgresPortfolioRepository) FindByUser(ctx context.Context, userID, id int64) (*domain.Portfolio, error) {
+	q := `SELECT ` + portfolioColumns + ` FROM portfolios WHERE id = $1 AND user_id = $2 LIMIT 1`
+
+	p, err := scanPortfolio(r.DB.QueryRowContext(ctx, q, id, userID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+func (r *PostgresPortfolioRepository) ListByUser(ctx context.Context, userID int64) ([]domain.Portfolio, error) {
+	q := `SELECT ` + portfolioColumns + ` FROM portfolios WHERE user_id = $1 ORDER BY id ASC`
+
+	rows, err := r.DB.QueryContext(ctx, q, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []domain.Portfolio
+	for rows.Next() {
+		p, err := scanPortfolio(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, p)
+	}
+	return out, rows.Err()
+}
+
+func (r *PostgresPortfolioRepository) CountByUser(ctx context.Context, userID int64) (int, error) {
+	var n int
+	err := r.DB.QueryRowContext(ctx, `SELECT COUNT(*) FROM portfolios WHERE user_id = $1`, userID).Scan(&n)
+	return n, err
+}
+
+func (r *PostgresPortfolioRepository) Update(ctx context.Context, p domain.Portfolio) (bool, error) {
+	const q = `UPDATE portfolios SET name = $1, base_currency = $2, updated_at = $3 WHERE id = $4 AND user_id = $5`
+
+	res, err := r.DB.ExecContext(ctx, q, p.Name, p.BaseCurrency, p.UpdatedAt.UTC(), p.ID, p.UserID)
+	if err != nil {
+		return false, err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
+func (r *PostgresPortfolioRepository) Delete(ctx context.Context, userID, id int64) (bool, error) {
+	res, err := r.DB.ExecContext(ctx, `DELETE FROM portfolios WHERE id = $1 AND user_id = $2`, id, userID)
+	if err != nil {
+		return false, err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
+type PostgresHoldingRepository struct {
+	DB *sql.DB
+}
+
+func NewPostgresHoldingRepository(db *sql.DB) *PostgresHoldingRepository {
+	return &PostgresHoldingRepository{DB: db}
+}
+
+// quantity se lee como texto para no pasar por float
+const holdingSelect = `
+	SELECT h.id, h.portfolio_id, h.coin_id, c.symbol, h.quantity::text, h.created_at, h.updated_at
+	FROM portfolio_holdings h
+	JOIN coins c ON c.id = h.coin_id`
+
+func scanHolding(s rowScanner) (domain.Holding, error) {
+	var h domain.Holding
+	if err := s.Scan(&h.ID, &h.PortfolioID, &h.CoinID, &h.Symbol, &h.Quantity, &h.CreatedAt, &h.UpdatedAt); err != nil {
+		return domain.Holding{}, err
+	}
+	h.CreatedAt = h.CreatedAt.UTC()
+	h.UpdatedAt = h.UpdatedAt.UTC()
+	return h, nil
+}
+
+func (r *PostgresHoldingRepository) Upsert(ctx context.Context, h domain.Holding) (domain.Holding, error) {
+	const q = `
+		INSERT INTO portfolio_holdings (portfolio_id, coin_id, quantity, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (portfolio_id, coin_id) DO UPDATE SET
+			quantity = excluded.quantity,
+			updated_at = excluded.updated_at
+	`
+	if _, err := r.DB.ExecContext(ctx, q, h.PortfolioID, h.CoinID, h.Quantity, h.CreatedAt.UTC(), h.UpdatedAt.UTC()); err != nil {
+		return domain.Holding{}, err
+	}
+
+	// se relee para devolver id, símbolo y created_at de la fila que quedó
+	return scanHolding(r.DB.QueryRowContext(ctx, holdingSelect+` WHERE h.portfolio_id = $1 AND h.coin_id = $2`, h.PortfolioID, h.CoinID))
+}
+
+func (r *PostgresHoldingRepository) ListByPortfolio(ctx context.Context, portfolioID int64) ([]domain.Holding, error) {
+	rows, err := r.DB.QueryContext(ctx, holdingSelect+` WHERE h.portfolio_id = $1 ORDER BY c.symbol ASC`, portfolioID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []domain.Holding
+	for rows.Next() {
+		h, err := scanHolding(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, h)
+	}
+	return out, rows.Err()
+}
+
+func (r *PostgresHoldingRepository) Delete(ctx context.Context, portfolioID, coinID int64) (bool, error) {
+	res, err := r.DB.ExecContext(ctx, `DELETE FROM portfolio_holdings WHERE portfolio_id = $1 AND coin_id = $2`, portfolioID, coinID)
+	if err != nil {
+		return false, err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
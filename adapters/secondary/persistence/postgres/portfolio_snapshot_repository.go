@@ -0,0 +1,60 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/moondolphin/crypto-api/domain"
+)
+
+type PostgresPortfolioSnapshotRepository struct {
+	DB *sql.DB
+}
+
+func NewPostgresPortfolioSnapshotRepository(db *sql.DB) *PostgresPortfolioSnapshotRepository {
+	return &PostgresPortfolioSnapshotRepository{DB: db}
+}
+
+func (r *PostgresPortfolioSnapshotRepository) Upsert(ctx context.Context, s domain.PortfolioSnapshot) error {
+	const q = `
+		INSERT INTO portfolio_snapshots (portfolio_id, day, currency, value, computed_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (portfolio_id, day, currency) DO UPDATE SET
+			value = EXCLUDED.value,
+			computed_at = EXCLUDED.computed_at
+	`
+	_, err := r.DB.ExecContext(ctx, q, s.PortfolioID, s.Day.UTC(), s.Currency, s.Value, s.ComputedAt)
+	return err
+}
+
+func (r *PostgresPortfolioSnapshotRepository) ListRange(ctx context.Context, portfolioID int64, currency string, from, to time.Time) ([]domain.PortfolioSnapshot, error) {
+	const q = `
+		SELECT portfolio_id, day, currency, value::text, computed_at
+		FROM portfolio_snapshots
+		WHERE portfolio_id = $1 AND currency = $2 AND day >= $3 AND day <= $4
+		ORDER BY day ASC
+	`
+	rows, err := r.DB.QueryContext(ctx, q, portfolioID, currency, from.UTC(), to.UTC())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []domain.PortfolioSnapshot
+	for rows.Next() {
+		var s domain.PortfolioSnapshot
+		if err := rows.Scan(&s.PortfolioID, &s.Day, &s.Currency, &s.Value, &s.ComputedAt); err != nil {
+			return nil, err
+		}
+		s.Day = s.Day.UTC()
+		s.ComputedAt = s.ComputedAt.UTC()
+		out = append(out, s)
+	}
+	return out, rows.Err()
+}
+
+func (r *PostgresPortfolioSnapshotRepository) DeleteFrom(ctx context.Context, portfolioID int64, from time.Time) error {
+	_, err := r.DB.ExecContext(ctx, `DELETE FROM portfolio_snapshots WHERE portfolio_id = $1 AND day >= $2`, portfolioID, from.UTC())
+	return err
+}
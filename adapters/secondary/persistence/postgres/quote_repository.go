@@ -0,0 +1,385 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/moondolphin/crypto-api/domain"
+)
+
+type PostgresQuoteRepository struct {
+	DB *sql.DB
+}
+
+func NewPostgresQuoteRepository(db *sql.DB) *PostgresQuoteRepository {
+	return &PostgresQuoteRepository{DB: db}
+}
+
+func (r *PostgresQuoteRepository) Insert(ctx context.Context, q domain.Quote) (bool, error) {
+	const stmt = `
+		INSERT INTO quotes (coin_id, symbol, provider, currency, price, quoted_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (coin_id, provider, currency, quoted_at) DO NOTHING
+	`
+	res, err := r.DB.ExecContext(ctx, stmt, q.CoinID, q.Symbol, q.Provider, q.Currency, q.Price, q.QuotedAt)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+func (r *PostgresQuoteRepository) GetLatest(ctx context.Context, symbol, provider, currency string) (*domain.PriceQuote, error) {
+	const base = `
+SELECT symbol, provider, currency, price::text, quoted_at
+FROM quotes
+WHERE symbol = $1
+`
+
+	q := base
+	args := []any{symbol}
+
+	if provider != "" {
+		args = append(args, provider)
+		q += " AND provider = $" + strconv.Itoa(len(args))
+	}
+	if currency != "" {
+		args = append(args, currency)
+		q += " AND currency = $" + strconv.Itoa(len(args))
+	}
+
+	q += " ORDER BY quoted_at DESC LIMIT 1"
+
+	var out domain.PriceQuote
+	var quotedAt time.Time
+	if err := r.DB.QueryRowContext(ctx, q, args...).Scan(
+		&out.Symbol,
+		&out.Provider,
+		&out.Currency,
+		&out.Price,
+		&quotedAt,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	// pgx devuelve timestamptz en hora local: normalizamos a UTC como MySQL
+	out.Timestamp = quotedAt.UTC().Format(time.RFC3339Nano)
+	return &out, nil
+}
+
+// buildQuoteWhere arma el WHERE dinámico con placeholders numerados ($n).
+// Devuelve los args para que el llamador pueda seguir numerando (LIMIT/OFFSET).
+func buildQuoteWhere(f domain.QuoteFilter) (string, []any) {
+	where := " WHERE 1=1"
+	args := make([]any, 0, 12)
+
+	add := func(cond string, v any) {
+		args = append(args, v)
+		where += fmt.Sprintf(" AND "+cond, len(args))
+	}
+
+	if f.Symbol != "" {
+		add("symbol = $%d", f.Symbol)
+	}
+	if f.Provider != "" {
+		add("provider = $%d", f.Provider)
+	}
+	if f.Currency != "" {
+		add("currency = $%d", f.Currency)
+	}
+	if f.From != nil {
+		add("quoted_at >= $%d", *f.From)
+	}
+	if f.To != nil {
+		add("quoted_at <= $%d", *f.To)
+	}
+	if f.MinPrice != nil {
+		add("price >= $%d", *f.MinPrice)
+	}
+	if f.MaxPrice != nil {
+		add("price <= $%d", *f.MaxPrice)
+	}
+
+	return where, args
+}
+
+func (r *PostgresQuoteRepository) ListFilter(ctx context.Context, f domain.QuoteFilter) ([]domain.Quote, int, error) {
+	// defaults defensivos
+	page := f.Page
+	if page <= 0 {
+		page = 1
+	}
+	pageSize := f.PageSize
+	if pageSize <= 0 {
+		pageSize = 50
+	}
+	if pageSize > 100 {
+		pageSize = 100
+	}
+	offset := (page - 1) * pageSize
+
+	where, args := buildQuoteWhere(f)
+
+	// 1) COUNT total (para summary)
+	countSQL := "SELECT COUNT(*) FROM quotes" + where
+	var total int
+	if err := r.DB.QueryRowContext(ctx, countSQL, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	// 2) SELECT paginado
+	listSQL := fmt.Sprintf(`
+SELECT id, coin_id, symbol, provider, currency, price::text, quoted_at, created_at
+FROM quotes
+%s
+ORDER BY quoted_at DESC
+LIMIT $%d OFFSET $%d
+`, where, len(args)+1, len(args)+2)
+
+	listArgs := make([]any, 0, len(args)+2)
+	listArgs = append(listArgs, args...)
+	listArgs = append(listArgs, pageSize, offset)
+
+	rows, err := r.DB.QueryContext(ctx, listSQL, listArgs...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	out := make([]domain.Quote, 0, pageSize)
+
+	for rows.Next() {
+		var q domain.Quote
+		if err := rows.Scan(
+			&q.ID,
+			&q.CoinID,
+			&q.Symbol,
+			&q.Provider,
+			&q.Currency,
+			&q.Price,
+			&q.QuotedAt,
+			&q.CreatedAt,
+		); err != nil {
+			return nil, 0, err
+		}
+
+		q.QuotedAt = q.QuotedAt.UTC()
+		q.CreatedAt = q.CreatedAt.UTC()
+		out = append(out, q)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return out, total, nil
+}
+
+// ListAvailableFilters devuelve "faceted filters" aplicando el mismo tamiz que ListFilter.
+func (r *PostgresQuoteRepository) ListAvailableFilters(ctx context.Context, f domain.QuoteFilter) (domain.QuoteFilters, error) {
+	where, args := buildQuoteWhere(f)
+
+	distinctList := func(col string) ([]string, error) {
+		q := fmt.Sprintf(`SELECT DISTINCT %s FROM quotes %s ORDER BY %s ASC`, col, where, col)
+		rows, err := r.DB.QueryContext(ctx, q, args...)
+		if err != nil {
+			return nil, err
+		}
+		defer rows.Close()
+
+		out := make([]string, 0, 16)
+		for rows.Next() {
+			var v sql.NullString
+			if err := rows.Scan(&v); err != nil {
+				return nil, err
+			}
+			if v.Valid && v.String != "" {
+				out = append(out, v.String)
+			}
+		}
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+		return out, nil
+	}
+
+	symbols, err := distinctList("symbol")
+	if err != nil {
+		return domain.QuoteFilters{}, err
+	}
+	providers, err := distinctList("provider")
+	if err != nil {
+		return domain.QuoteFilters{}, err
+	}
+	currencies, err := distinctList("currency")
+	if err != nil {
+		return domain.QuoteFilters{}, err
+	}
+
+	var minPrice, maxPrice sql.NullString
+	var minTime, maxTime sql.NullTime
+	rangeQ := `SELECT MIN(price)::text, MAX(price)::text, MIN(quoted_at), MAX(quoted_at) FROM quotes` + where
+	if err := r.DB.QueryRowContext(ctx, rangeQ, args...).Scan(&minPrice, &maxPrice, &minTime, &maxTime); err != nil {
+		return domain.QuoteFilters{}, err
+	}
+
+	out := domain.QuoteFilters{
+		Symbols:    symbols,
+		Providers:  providers,
+		Currencies: currencies,
+	}
+
+	if minPrice.Valid {
+		out.MinPrice = minPrice.String
+	}
+	if maxPrice.Valid {
+		out.MaxPrice = maxPrice.String
+	}
+
+	if minTime.Valid {
+		t := minTime.Time.UTC()
+		out.From = &t
+	}
+	if maxTime.Valid {
+		t := maxTime.Time.UTC()
+		out.To = &t
+	}
+
+	return out, nil
+}
+
+// ListDailyCloses agrupa por el día UTC de quoted_at (TIMESTAMPTZ). Usa la
+// misma forma que MySQL (JOIN contra el MAX por día) en vez de DISTINCT ON.
+func (r *PostgresQuoteRepository) ListDailyCloses(ctx context.Context, f domain.DailyCloseFilter) ([]domain.DailyClose, error) {
+	if len(f.Symbols) == 0 {
+		return []domain.DailyClose{}, nil
+	}
+
+	args := []any{f.Currency, f.From, f.To}
+	where := " WHERE currency = $1 AND quoted_at >= $2 AND quoted_at < $3"
+
+	in := make([]string, 0, len(f.Symbols))
+	for _, s := range f.Symbols {
+		args = append(args, s)
+		in = append(in, "$"+strconv.Itoa(len(args)))
+	}
+	where += " AND symbol IN (" + strings.Join(in, ", ") + ")"
+
+	providerCond := ""
+	if f.Provider != "" {
+		args = append(args, f.Provider)
+		where += " AND provider = $" + strconv.Itoa(len(args))
+		providerCond = " AND q.provider = $" + strconv.Itoa(len(args))
+	}
+
+	q := `
+SELECT q.symbol, q.provider, q.price::text, q.quoted_at
+FROM quotes q
+JOIN (
+	SELECT symbol, MAX(quoted_at) AS last_at
+	FROM quotes` + where + `
+	GROUP BY symbol, (quoted_at AT TIME ZONE 'UTC')::date
+) d ON d.symbol = q.symbol AND d.last_at = q.quoted_at
+WHERE q.currency = $1` + providerCond + `
+ORDER BY q.symbol ASC, q.quoted_at ASC, q.provider ASC`
+
+	rows, err := r.DB.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]domain.DailyClose, 0, 64)
+	for rows.Next() {
+		var c domain.DailyClose
+		if err := rows.Scan(&c.Symbol, &c.Provider, &c.Price, &c.QuotedAt); err != nil {
+			return nil, err
+		}
+		c.QuotedAt = c.QuotedAt.UTC()
+		c.Day = c.QuotedAt.Truncate(24 * time.Hour)
+
+		// empate entre providers en el mismo instante: queda el primero
+		if n := len(out); n > 0 && out[n-1].Symbol == c.Symbol && out[n-1].Day.Equal(c.Day) {
+			continue
+		}
+		out = append(out, c)
+	}
+	return out, rows.Err()
+}
+
+// ListTickers junta el MAX(quoted_at) de cada grupo con el MAX hasta PrevAt
+// (LEFT JOIN: puede no haber referencia) en una sola consulta. Las dos
+// subconsultas comparten los mismos parámetros.
+func (r *PostgresQuoteRepository) ListTickers(ctx context.Context, f domain.QuoteTickerFilter) ([]domain.QuoteTicker, error) {
+	if len(f.Symbols) == 0 {
+		return []domain.QuoteTicker{}, nil
+	}
+
+	args := make([]any, 0, len(f.Symbols)+2*len(f.Pairs)+1)
+	in := make([]string, 0, len(f.Symbols))
+	for _, s := range f.Symbols {
+		args = append(args, s)
+		in = append(in, "$"+strconv.Itoa(len(args)))
+	}
+	where := " WHERE symbol IN (" + strings.Join(in, ", ") + ")"
+
+	if len(f.Pairs) > 0 {
+		pairs := make([]string, 0, len(f.Pairs))
+		for _, p := range f.Pairs {
+			args = append(args, p.Provider, p.Currency)
+			pairs = append(pairs, "(provider = $"+strconv.Itoa(len(args)-1)+" AND currency = $"+strconv.Itoa(len(args))+")")
+		}
+		where += " AND (" + strings.Join(pairs, " OR ") + ")"
+	}
+
+	args = append(args, f.PrevAt)
+	prevCond := " AND quoted_at <= $" + strconv.Itoa(len(args))
+
+	q := `
+SELECT q.symbol, q.provider, q.currency, q.price::text, q.quoted_at, p.price::text, p.quoted_at
+FROM quotes q
+JOIN (
+	SELECT symbol, provider, currency, MAX(quoted_at) AS last_at
+	FROM quotes` + where + `
+	GROUP BY symbol, provider, currency
+) l ON l.symbol = q.symbol AND l.provider = q.provider AND l.currency = q.currency AND l.last_at = q.quoted_at
+LEFT JOIN (
+	SELECT symbol, provider, currency, MAX(quoted_at) AS prev_at
+	FROM quotes` + where + prevCond + `
+	GROUP BY symbol, provider, currency
+) pa ON pa.symbol = q.symbol AND pa.provider = q.provider AND pa.currency = q.currency
+LEFT JOIN quotes p ON p.symbol = pa.symbol AND p.provider = pa.provider AND p.currency = pa.currency AND p.quoted_at = pa.prev_at
+ORDER BY q.symbol ASC, q.provider ASC, q.currency ASC`
+
+	rows, err := r.DB.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]domain.QuoteTicker, 0, len(f.Symbols))
+	for rows.Next() {
+		var t domain.QuoteTicker
+		var prevPrice sql.NullString
+		var prevAt sql.NullTime
+		if err := rows.Scan(&t.Symbol, &t.Provider, &t.Currency, &t.Price, &t.QuotedAt, &prevPrice, &prevAt); err != nil {
+			return nil, err
+		}
+		t.QuotedAt = t.QuotedAt.UTC()
+		if prevPrice.Valid && prevAt.Valid {
+			t.PrevPrice = prevPrice.String
+			t.PrevQuotedAt = prevAt.Time.UTC()
+		}
+		out = append(out, t)
+	}
+	return out, rows.Err()
+}
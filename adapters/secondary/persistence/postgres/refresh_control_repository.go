@@ -0,0 +1,50 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+type PostgresRefreshControlRepository struct {
+	DB *sql.DB
+}
+
+func NewPostgresRefreshControlRepository(db *sql.DB) *PostgresRefreshControlRepository {
+	return &PostgresRefreshControlRepository{DB: db}
+}
+
+const keyLastManualRefresh = "last_manual_refresh_rfc3339"
+
+func (r *PostgresRefreshControlRepository) GetLastManualRefresh(ctx context.Context) (time.Time, bool, error) {
+	const q = `SELECT value FROM refresh_control WHERE key = $1 LIMIT 1`
+
+	var v string
+	err := r.DB.QueryRowContext(ctx, q, keyLastManualRefresh).Scan(&v)
+	if err == sql.ErrNoRows {
+		return time.Time{}, false, nil
+	}
+	if err != nil {
+		return time.Time{}, false, err
+	}
+
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		// si quedó basura, lo tratamos como "no hay dato"
+		return time.Time{}, false, nil
+	}
+
+	return t.UTC(), true, nil
+}
+
+func (r *PostgresRefreshControlRepository) SetLastManualRefresh(ctx context.Context, t time.Time) error {
+	const stmt = `
+		INSERT INTO refresh_control (key, value, updated_at)
+		VALUES ($1, $2, now())
+		ON CONFLICT (key) DO UPDATE SET
+			value = excluded.value,
+			updated_at = excluded.updated_at
+	`
+	_, err := r.DB.ExecContext(ctx, stmt, keyLastManualRefresh, t.UTC().Format(time.RFC3339))
+	return err
+}
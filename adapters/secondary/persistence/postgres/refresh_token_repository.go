@@ -0,0 +1,115 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/moondolphin/crypto-api/domain"
+)
+
+type PostgresRefreshTokenRepository struct {
+	DB *sql.DB
+}
+
+func NewPostgresRefreshTokenRepository(db *sql.DB) *PostgresRefreshTokenRepository {
+	return &PostgresRefreshTokenRepository{DB: db}
+}
+
+func (r *PostgresRefreshTokenRepository) Create(ctx context.Context, t domain.RefreshToken) (domain.RefreshToken, error) {
+	const q = `
+		INSERT INTO refresh_tokens (user_id, family_id, token_hash, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id
+	`
+	if err := r.DB.QueryRowContext(ctx, q, t.UserID, t.FamilyID, t.TokenHash, t.ExpiresAt.UTC(), t.CreatedAt.UTC()).Scan(&t.ID); err != nil {
+		return domain.RefreshToken{}, err
+	}
+	return t, nil
+}
+
+func (r *PostgresRefreshTokenRepository) FindByHash(ctx context.Context, tokenHash string) (*domain.RefreshToken, error) {
+	const q = `
+		SELECT id, user_id, family_id, token_hash, expires_at, created_at, revoked_at
+		FROM refresh_tokens
+		WHERE token_hash = $1
+		LIMIT 1
+	`
+	var (
+		t         domain.RefreshToken
+		revokedAt sql.NullTime
+	)
+	err := r.DB.QueryRowContext(ctx, q, tokenHash).
+		Scan(&t.ID, &t.UserID, &t.FamilyID, &t.TokenHash, &t.ExpiresAt, &t.CreatedAt, &revokedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	t.ExpiresAt = t.ExpiresAt.UTC()
+	t.CreatedAt = t.CreatedAt.UTC()
+	if revokedAt.Valid {
+		at := revokedAt.Time.UTC()
+		t.RevokedAt = &at
+	}
+	return &t, nil
+}
+
+func (r *PostgresRefreshTokenRepository) Revoke(ctx context.Context, id int64, at time.Time) (bool, error) {
+	// el filtro revoked_at IS NULL hace la rotación atómica: sólo un request gana
+	const q = `UPDATE refresh_tokens SET revoked_at = $1 WHERE id = $2 AND revoked_at IS NULL`
+	res, err := r.DB.ExecContext(ctx, q, at.UTC(), id)
+	if err != nil {
+		return false, err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
+func (r *PostgresRefreshTokenRepository) RevokeFamily(ctx context.Context, familyID string, at time.Time) error {
+	const q = `UPDATE refresh_tokens SET revoked_at = $1 WHERE family_id = $2 AND revoked_at IS NULL`
+	_, err := r.DB.ExecContext(ctx, q, at.UTC(), familyID)
+	return err
+}
+
+func (r *PostgresRefreshTokenRepository) RevokeByUser(ctx context.Context, userID int64, at time.Time) error {
+	const q = `UPDATE refresh_tokens SET revoked_at = $1 WHERE user_id = $2 AND revoked_at IS NULL`
+	_, err := r.DB.ExecContext(ctx, q, at.UTC(), userID)
+	return err
+}
+
+func (r *PostgresRefreshTokenRepository) ListActiveByUser(ctx context.Context, userID int64, now time.Time) ([]domain.RefreshToken, error) {
+	const q = `
+		SELECT id, user_id, family_id, token_hash, expires_at, created_at, revoked_at
+		FROM refresh_tokens
+		WHERE user_id = $1 AND revoked_at IS NULL AND expires_at > $2
+		ORDER BY id ASC
+	`
+	rows, err := r.DB.QueryContext(ctx, q, userID, now.UTC())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []domain.RefreshToken
+	for rows.Next() {
+		var (
+			t         domain.RefreshToken
+			revokedAt sql.NullTime
+		)
+		if err := rows.Scan(&t.ID, &t.UserID, &t.FamilyID, &t.TokenHash, &t.ExpiresAt, &t.CreatedAt, &revokedAt); err != nil {
+			return nil, err
+		}
+		t.ExpiresAt = t.ExpiresAt.UTC()
+		t.CreatedAt = t.CreatedAt.UTC()
+		t.RevokedAt = timePtr(revokedAt)
+		out = append(out, t)
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,39 @@
+package postgres
+
+import (
+	"database/sql"
+
+	"github.com/moondolphin/crypto-api/domain"
+)
+
+// NewRepositories arma todos los puertos de persistencia sobre la misma base.
+func NewRepositories(db *sql.DB) domain.Repositories {
+	return domain.Repositories{
+		Users:            NewPostgresUserRepository(db),
+		Coins:            NewPostgresCoinRepository(db),
+		Quotes:           NewPostgresQuoteRepository(db),
+		Favorites:        NewPostgresFavoritesRepository(db),
+		RefreshControl:   NewPostgresRefreshControlRepository(db),
+		RefreshTokens:    NewPostgresRefreshTokenRepository(db),
+		Revocations:      NewPostgresTokenRevocationStore(db),
+		APIKeys:          NewPostgresAPIKeyRepository(db),
+		PasswordResets:   NewPostgresPasswordResetRepository(db),
+		Verifications:    NewPostgresEmailVerificationRepository(db),
+		LoginAttempts:    NewPostgresLoginAttemptStore(db),
+		TOTP:             NewPostgresTOTPRepository(db),
+		RecoveryCodes:    NewPostgresRecoveryCodeRepository(db),
+		Challenges:       NewPostgresLoginChallengeRepository(db),
+		Identities:       NewPostgresUserIdentityRepository(db),
+		OIDCStates:       NewPostgresOIDCStateRepository(db),
+		AlertRules:       NewPostgresAlertRuleRepository(db),
+		AlertEvents:      NewPostgresAlertEventRepository(db),
+		Webhooks:         NewPostgresWebhookSubscriptionRepository(db),
+		Deliveries:       NewPostgresWebhookDeliveryRepository(db),
+		Portfolios:       NewPostgresPortfolioRepository(db),
+		Holdings:         NewPostgresHoldingRepository(db),
+		Transactions:     NewPostgresTransactionRepository(db),
+		Snapshots:        NewPostgresPortfolioSnapshotRepository(db),
+		Watchlists:       NewPostgresWatchlistRepository(db),
+		WatchlistEntries: NewPostgresWatchlistEntryRepository(db),
+	}
+}
@@ -0,0 +1,61 @@
+package postgres_test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/moondolphin/crypto-api/adapters/secondary/persistence/postgres"
+	"github.com/moondolphin/crypto-api/test/contract"
+)
+
+// POSTGRES_TEST_DSN debe apuntar a una base descartable: se aplican las migraciones
+// y la suite borra quotes, users, favoritos, refresh_control, refresh_tokens, revocaciones, api_keys, tokens de reset/verificación y las coins "ZZ*".
+func TestPostgresRepositories_Contract(t *testing.T) {
+	dsn := os.Getenv("POSTGRES_TEST_DSN")
+	if dsn == "" {
+		t.Skip("POSTGRES_TEST_DSN not set")
+	}
+
+	db, err := postgres.Open(context.Background(), dsn)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	contract.RunRepositoryContract(t, func(t *testing.T) contract.Repositories {
+		for _, stmt := range []string{
+			"DELETE FROM refresh_tokens",
+			"DELETE FROM api_keys",
+			"DELETE FROM password_reset_tokens",
+			"DELETE FROM email_verification_tokens",
+			"DELETE FROM user_totp",
+			"DELETE FROM totp_recovery_codes",
+			"DELETE FROM watchlist_entries",
+			"DELETE FROM watchlists",
+			"DELETE FROM portfolio_snapshots",
+			"DELETE FROM portfolio_transactions",
+			"DELETE FROM portfolio_holdings",
+			"DELETE FROM portfolios",
+			"DELETE FROM webhook_deliveries",
+			"DELETE FROM webhook_subscriptions",
+			"DELETE FROM alert_events",
+			"DELETE FROM alert_rules",
+			"DELETE FROM login_challenges",
+			"DELETE FROM user_identities",
+			"DELETE FROM oidc_login_states",
+			"DELETE FROM quotes",
+			"DELETE FROM users",
+			"DELETE FROM refresh_control",
+			"DELETE FROM revoked_tokens",
+			"DELETE FROM revoked_user_tokens",
+			"DELETE FROM login_attempts",
+			"DELETE FROM coins WHERE symbol LIKE 'ZZ%'",
+		} {
+			_, err := db.Exec(stmt)
+			require.NoError(t, err)
+		}
+
+		return postgres.NewRepositories(db)
+	})
+}
@@ -0,0 +1,68 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+type PostgresTokenRevocationStore struct {
+	DB *sql.DB
+}
+
+func NewPostgresTokenRevocationStore(db *sql.DB) *PostgresTokenRevocationStore {
+	return &PostgresTokenRevocationStore{DB: db}
+}
+
+func (s *PostgresTokenRevocationStore) RevokeJTI(ctx context.Context, jti string, expiresAt time.Time) error {
+	const q = `
+		INSERT INTO revoked_tokens (jti, expires_at)
+		VALUES ($1, $2)
+		ON CONFLICT (jti) DO UPDATE SET expires_at = GREATEST(revoked_tokens.expires_at, EXCLUDED.expires_at)
+	`
+	_, err := s.DB.ExecContext(ctx, q, jti, expiresAt.UTC())
+	return err
+}
+
+func (s *PostgresTokenRevocationStore) RevokeUser(ctx context.Context, userID int64, issuedBefore, expiresAt time.Time) error {
+	// si ya había un corte, nos quedamos con el más reciente
+	const q = `
+		INSERT INTO revoked_user_tokens (user_id, revoked_before, expires_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (user_id) DO UPDATE SET
+			revoked_before = GREATEST(revoked_user_tokens.revoked_before, EXCLUDED.revoked_before),
+			expires_at = GREATEST(revoked_user_tokens.expires_at, EXCLUDED.expires_at)
+	`
+	_, err := s.DB.ExecContext(ctx, q, userID, issuedBefore.UTC().Truncate(time.Second), expiresAt.UTC())
+	return err
+}
+
+func (s *PostgresTokenRevocationStore) IsRevoked(ctx context.Context, jti string, userID int64, issuedAt time.Time) (bool, error) {
+	const q = `
+		SELECT
+			EXISTS (SELECT 1 FROM revoked_tokens WHERE jti = $1)
+			OR EXISTS (SELECT 1 FROM revoked_user_tokens WHERE user_id = $2 AND revoked_before >= $3)
+	`
+	var revoked bool
+	err := s.DB.QueryRowContext(ctx, q, jti, userID, issuedAt.UTC().Truncate(time.Second)).Scan(&revoked)
+	return revoked, err
+}
+
+func (s *PostgresTokenRevocationStore) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	var total int64
+	for _, q := range []string{
+		`DELETE FROM revoked_tokens WHERE expires_at <= $1`,
+		`DELETE FROM revoked_user_tokens WHERE expires_at <= $1`,
+	} {
+		res, err := s.DB.ExecContext(ctx, q, now.UTC())
+		if err != nil {
+			return total, err
+		}
+		n, err := res.RowsAffected()
+		if err != nil {
+			return total, err
+		}
+		total += n
+	}
+	return total, nil
+}
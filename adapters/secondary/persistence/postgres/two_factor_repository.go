@@ -0,0 +1,134 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/moondolphin/crypto-api/domain"
+)
+
+type PostgresTOTPRepository struct {
+	DB *sql.DB
+}
+
+func NewPostgresTOTPRepository(db *sql.DB) *PostgresTOTPRepository {
+	return &PostgresTOTPRepository{DB: db}
+}
+
+func (r *PostgresTOTPRepository) Get(ctx context.Context, userID int64) (*domain.TOTPEnrollment, error) {
+	const q = `
+		SELECT user_id, secret, created_at, confirmed_at, last_used_step
+		FROM user_totp
+		WHERE user_id = $1
+	`
+	var (
+		e           domain.TOTPEnrollment
+		confirmedAt sql.NullTime
+	)
+	err := r.DB.QueryRowContext(ctx, q, userID).
+		Scan(&e.UserID, &e.Secret, &e.CreatedAt, &confirmedAt, &e.LastUsedStep)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	e.CreatedAt = e.CreatedAt.UTC()
+	e.ConfirmedAt = timePtr(confirmedAt)
+	return &e, nil
+}
+
+func (r *PostgresTOTPRepository) Save(ctx context.Context, e domain.TOTPEnrollment) error {
+	const q = `
+		INSERT INTO user_totp (user_id, secret, created_at, confirmed_at, last_used_step)
+		VALUES ($1, $2, $3, NULL, 0)
+		ON CONFLICT (user_id) DO UPDATE SET
+			secret = excluded.secret,
+			created_at = excluded.created_at,
+			confirmed_at = NULL,
+			last_used_step = 0
+	`
+	_, err := r.DB.ExecContext(ctx, q, e.UserID, e.Secret, e.CreatedAt.UTC())
+	return err
+}
+
+func (r *PostgresTOTPRepository) Confirm(ctx context.Context, userID int64, at time.Time) error {
+	const q = `UPDATE user_totp SET confirmed_at = $1 WHERE user_id = $2`
+	_, err := r.DB.ExecContext(ctx, q, at.UTC(), userID)
+	return err
+}
+
+func (r *PostgresTOTPRepository) MarkStepUsed(ctx context.Context, userID int64, step int64) (bool, error) {
+	// la condición sobre last_used_step hace atómico el chequeo de replay
+	const q = `UPDATE user_totp SET last_used_step = $1 WHERE user_id = $2 AND last_used_step < $3`
+	res, err := r.DB.ExecContext(ctx, q, step, userID, step)
+	if err != nil {
+		return false, err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
+func (r *PostgresTOTPRepository) Delete(ctx context.Context, userID int64) error {
+	const q = `DELETE FROM user_totp WHERE user_id = $1`
+	_, err := r.DB.ExecContext(ctx, q, userID)
+	return err
+}
+
+type PostgresRecoveryCodeRepository struct {
+	DB *sql.DB
+}
+
+func NewPostgresRecoveryCodeRepository(db *sql.DB) *PostgresRecoveryCodeRepository {
+	return &PostgresRecoveryCodeRepository{DB: db}
+}
+
+func (r *PostgresRecoveryCodeRepository) Replace(ctx context.Context, userID int64, hashes []string, at time.Time) error {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM totp_recovery_codes WHERE user_id = $1`, userID); err != nil {
+		return err
+	}
+
+	const ins = `INSERT INTO totp_recovery_codes (user_id, code_hash, created_at) VALUES ($1, $2, $3)`
+	for _, h := range hashes {
+		if _, err := tx.ExecContext(ctx, ins, userID, h, at.UTC()); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (r *PostgresRecoveryCodeRepository) Use(ctx context.Context, userID int64, hash string, at time.Time) (bool, error) {
+	const q = `
+		UPDATE totp_recovery_codes SET used_at = $1
+		WHERE user_id = $2 AND code_hash = $3 AND used_at IS NULL
+	`
+	res, err := r.DB.ExecContext(ctx, q, at.UTC(), userID, hash)
+	if err != nil {
+		return false, err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
+func (r *PostgresRecoveryCodeRepository) CountUnused(ctx context.Context, userID int64) (int, error) {
+	const q = `SELECT COUNT(*) FROM totp_recovery_codes WHERE user_id = $1 AND used_at IS NULL`
+	var n int
+	err := r.DB.QueryRowContext(ctx, q, userID).Scan(&n)
+	return n, err
+}
@@ -0,0 +1,108 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/moondolphin/crypto-api/domain"
+)
+
+type PostgresUserRepository struct {
+	DB *sql.DB
+}
+
+func NewPostgresUserRepository(db *sql.DB) *PostgresUserRepository {
+	return &PostgresUserRepository{DB: db}
+}
+
+func (r *PostgresUserRepository) ExistsByEmail(ctx context.Context, email string) (bool, error) {
+	const q = `SELECT 1 FROM users WHERE email = $1 LIMIT 1`
+	var one int
+	err := r.DB.QueryRowContext(ctx, q, email).Scan(&one)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (r *PostgresUserRepository) Create(ctx context.Context, u domain.User) (domain.User, error) {
+	if u.Role == "" {
+		u.Role = domain.RoleUser
+	}
+
+	const q = `
+		INSERT INTO users (email, name, password_hash, role, created_at, email_verified_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id
+	`
+	// pgx no soporta LastInsertId: usamos RETURNING
+	if err := r.DB.QueryRowContext(ctx, q, u.Email, u.Name, u.PasswordHash, u.Role, u.CreatedAt, nullTime(u.EmailVerifiedAt)).Scan(&u.ID); err != nil {
+		return domain.User{}, err
+	}
+
+	return u, nil
+}
+
+func (r *PostgresUserRepository) FindByEmail(ctx context.Context, email string) (*domain.User, error) {
+	const q = `SELECT ` + userColumns + ` FROM users WHERE email = $1 LIMIT 1`
+	return scanUser(r.DB.QueryRowContext(ctx, q, email))
+}
+
+func (r *PostgresUserRepository) FindByID(ctx context.Context, id int64) (*domain.User, error) {
+	const q = `SELECT ` + userColumns + ` FROM users WHERE id = $1 LIMIT 1`
+	return scanUser(r.DB.QueryRowContext(ctx, q, id))
+}
+
+func (r *PostgresUserRepository) UpdateRole(ctx context.Context, userID int64, role string) error {
+	const q = `UPDATE users SET role = $1 WHERE id = $2`
+	_, err := r.DB.ExecContext(ctx, q, role, userID)
+	return err
+}
+
+func (r *PostgresUserRepository) UpdatePassword(ctx context.Context, userID int64, passwordHash string) error {
+	const q = `UPDATE users SET password_hash = $1 WHERE id = $2`
+	_, err := r.DB.ExecContext(ctx, q, passwordHash, userID)
+	return err
+}
+
+func (r *PostgresUserRepository) MarkEmailVerified(ctx context.Context, userID int64, at time.Time) error {
+	// no pisa la fecha original si ya estaba verificado
+	const q = `UPDATE users SET email_verified_at = $1 WHERE id = $2 AND email_verified_at IS NULL`
+	_, err := r.DB.ExecContext(ctx, q, at.UTC(), userID)
+	return err
+}
+
+const userColumns = `id, email, name, password_hash, role, created_at, email_verified_at`
+
+func (r *PostgresUserRepository) UpdateProfile(ctx context.Context, u domain.User) error {
+	const q = `UPDATE users SET name = $1, email = $2, email_verified_at = $3 WHERE id = $4`
+	_, err := r.DB.ExecContext(ctx, q, u.Name, u.Email, nullTime(u.EmailVerifiedAt), u.ID)
+	return err
+}
+
+func (r *PostgresUserRepository) Delete(ctx context.Context, userID int64) error {
+	const q = `DELETE FROM users WHERE id = $1`
+	_, err := r.DB.ExecContext(ctx, q, userID)
+	return err
+}
+
+func scanUser(row *sql.Row) (*domain.User, error) {
+	var (
+		u          domain.User
+		verifiedAt sql.NullTime
+	)
+	err := row.Scan(&u.ID, &u.Email, &u.Name, &u.PasswordHash, &u.Role, &u.CreatedAt, &verifiedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	u.CreatedAt = u.CreatedAt.UTC()
+	u.EmailVerifiedAt = timePtr(verifiedAt)
+	return &u, nil
+}
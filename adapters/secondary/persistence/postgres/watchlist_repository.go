@@ -0,0 +1,233 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/moondolphin/crypto-api/domain"
+)
+
+type PostgresWatchlistRepository struct {
+	DB *sql.DB
+}
+
+func NewPostgresWatchlistRepository(db *sql.DB) *PostgresWatchlistRepository {
+	return &PostgresWatchlistRepository{DB: db}
+}
+
+const watchlistColumns = `id, user_id, name, default_user_id IS NOT NULL, created_at, updated_at`
+
+func scanWatchlist(s rowScanner) (domain.Watchlist, error) {
+	var w domain.Watchlist
+	if err := s.Scan(&w.ID, &w.UserID, &w.Name, &w.IsDefault, &w.CreatedAt, &w.UpdatedAt); err != nil {
+		return domain.Watchlist{}, err
+	}
+	w.CreatedAt = w.CreatedAt.UTC()
+	w.UpdatedAt = w.UpdatedAt.UTC()
+	return w, nil
+}
+
+func (r *PostgresWatchlistRepository) Create(ctx context.Context, w domain.Watchlist) (domain.Watchlist, error) {
+	const q = `
+		INSERT INTO watchlists (user_id, name, created_at, updated_at)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id
+	`
+	if err := r.DB.QueryRowContext(ctx, q, w.UserID, w.Name, w.CreatedAt.UTC(), w.UpdatedAt.UTC()).Scan(&w.ID); err != nil {
+		return domain.Watchlist{}, err
+	}
+
+	w.IsDefault = false
+	return w, nil
+}
+
+func (r *PostgresWatchlistRepository) EnsureDefault(ctx context.Context, w domain.Watchlist) (domain.Watchlist, error) {
+	q := `SELECT ` + watchlistColumns + ` FROM watchlists WHERE default_user_id = $1`
+
+	cur, err := scanWatchlist(r.DB.QueryRowContext(ctx, q, w.UserID))
+	if err != sql.ErrNoRows {
+		return cur, err
+	}
+
+	// si otra alta gana la carrera, el UNIQUE de default_user_id descarta esta
+	const ins = `
+		INSERT INTO watchlists (user_id, name, default_user_id, created_at, updated_at)
+		VALUES ($1, $2, $1, $3, $4)
+		ON CONFLICT (default_user_id) DO NOTHING
+	`
+	if _, err := r.DB.ExecContext(ctx, ins, w.UserID, w.Name, w.CreatedAt.UTC(), w.UpdatedAt.UTC()); err != nil {
+		return domain.Watchlist{}, err
+	}
+
+	return scanWatchlist(r.DB.QueryRowContext(ctx, q, w.UserID))
+}
+
+func (r *PostgresWatchlistRepository) FindByUser(ctx context.Context, userID, id int64) (*domain.Watchlist, error) {
+	q := `SELECT ` + watchlistColumns + ` FROM watchlists WHERE id = $1 AND user_id = $2 LIMIT 1`
+
+	w, err := scanWatchlist(r.DB.QueryRowContext(ctx, q, id, userID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &w, nil
+}
+
+func (r *PostgresWatchlistRepository) ListByUser(ctx context.Context, userID int64) ([]domain.Watchlist, error) {
+	q := `SELECT ` + watchlistColumns + ` FROM watchlists WHERE user_id = $1 ORDER BY default_user_id IS NULL, id ASC`
+
+	rows, err := r.DB.QueryContext(ctx, q, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []domain.Watchlist
+	for rows.Next() {
+		w, err := scanWatchlist(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, w)
+	}
+	return out, rows.Err()
+}
+
+func (r *PostgresWatchlistRepository) CountByUser(ctx context.Context, userID int64) (int, error) {
+	var n int
+	err := r.DB.QueryRowContext(ctx, `SELECT COUNT(*) FROM watchlists WHERE user_id = $1`, userID).Scan(&n)
+	return n, err
+}
+
+func (r *PostgresWatchlistRepository) Update(ctx context.Context, w domain.Watchlist) (bool, error) {
+	const q = `UPDATE watchlists SET name = $1, updated_at = $2 WHERE id = $3 AND user_id = $4`
+
+	res, err := r.DB.ExecContext(ctx, q, w.Name, w.UpdatedAt.UTC(), w.ID, w.UserID)
+	if err != nil {
+		return false, err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
+func (r *PostgresWatchlistRepository) Delete(ctx context.Context, userID, id int64) (bool, error) {
+	const q = `DELETE FROM watchlists WHERE id = $1 AND user_id = $2 AND default_user_id IS NULL`
+
+	res, err := r.DB.ExecContext(ctx, q, id, userID)
+	if err != nil {
+		return false, err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
+type PostgresWatchlistEntryRepository struct {
+	DB *sql.DB
+}
+
+func NewPostgresWatchlistEntryRepository(db *sql.DB) *PostgresWatchlistEntryRepository {
+	return &PostgresWatchlistEntryRepository{DB: db}
+}
+
+func (r *PostgresWatchlistEntryRepository) Add(ctx context.Context, e domain.WatchlistEntry) (bool, error) {
+	// la posición se calcula en el mismo INSERT: queda al final aunque haya
+	// altas concurrentes (a lo sumo empatan, y el orden cae al símbolo)
+	const q = `
+		INSERT INTO watchlist_entries (watchlist_id, coin_id, sort_order, note, created_at, updated_at)
+		SELECT $1, $2, COALESCE(MAX(sort_order) + 1, 0), $3, $4, $5
+		FROM watchlist_entries WHERE watchlist_id = $1
+		ON CONFLICT (watchlist_id, coin_id) DO NOTHING
+	`
+	res, err := r.DB.ExecContext(ctx, q, e.WatchlistID, e.CoinID, e.Note, e.CreatedAt.UTC(), e.UpdatedAt.UTC())
+	if err != nil {
+		return false, err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
+func (r *PostgresWatchlistEntryRepository) UpdateNote(ctx context.Context, watchlistID, coinID int64, note string, at time.Time) (bool, error) {
+	const q = `UPDATE watchlist_entries SET note = $1, updated_at = $2 WHERE watchlist_id = $3 AND coin_id = $4`
+
+	res, err := r.DB.ExecContext(ctx, q, note, at.UTC(), watchlistID, coinID)
+	if err != nil {
+		return false, err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
+func (r *PostgresWatchlistEntryRepository) Remove(ctx context.Context, watchlistID, coinID int64) (bool, error) {
+	res, err := r.DB.ExecContext(ctx, `DELETE FROM watchlist_entries WHERE watchlist_id = $1 AND coin_id = $2`, watchlistID, coinID)
+	if err != nil {
+		return false, err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
+func (r *PostgresWatchlistEntryRepository) ListByWatchlist(ctx context.Context, watchlistID int64) ([]domain.WatchlistEntry, error) {
+	const q = `
+		SELECT e.watchlist_id, e.coin_id, c.symbol, e.sort_order, e.note, e.created_at, e.updated_at
+		FROM watchlist_entries e
+		JOIN coins c ON c.id = e.coin_id
+		WHERE e.watchlist_id = $1
+		ORDER BY e.sort_order ASC, c.symbol ASC
+	`
+	rows, err := r.DB.QueryContext(ctx, q, watchlistID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []domain.WatchlistEntry
+	for rows.Next() {
+		var e domain.WatchlistEntry
+		if err := rows.Scan(&e.WatchlistID, &e.CoinID, &e.Symbol, &e.Position, &e.Note, &e.CreatedAt, &e.UpdatedAt); err != nil {
+			return nil, err
+		}
+		e.CreatedAt = e.CreatedAt.UTC()
+		e.UpdatedAt = e.UpdatedAt.UTC()
+		out = append(out, e)
+	}
+	return out, rows.Err()
+}
+
+func (r *PostgresWatchlistEntryRepository) Reorder(ctx context.Context, watchlistID int64, coinIDs []int64) error {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	const q = `UPDATE watchlist_entries SET sort_order = $1 WHERE watchlist_id = $2 AND coin_id = $3`
+	for i, coinID := range coinIDs {
+		if _, err := tx.ExecContext(ctx, q, i, watchlistID, coinID); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
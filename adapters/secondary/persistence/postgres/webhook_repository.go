@@ -0,0 +1,281 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/moondolphin/crypto-api/domain"
+)
+
+type PostgresWebhookSubscriptionRepository struct {
+	DB *sql.DB
+}
+
+func NewPostgresWebhookSubscriptionRepository(db *sql.DB) *PostgresWebhookSubscriptionRepository {
+	return &PostgresWebhookSubscriptionRepository{DB: db}
+}
+
+const webhookSubscriptionColumns = `id, user_id, url, events, secret, created_at`
+
+func scanWebhookSubscription(s rowScanner) (domain.WebhookSubscription, error) {
+	var (
+		w      domain.WebhookSubscription
+		events string
+	)
+	if err := s.Scan(&w.ID, &w.UserID, &w.URL, &events, &w.Secret, &w.CreatedAt); err != nil {
+		return domain.WebhookSubscription{}, err
+	}
+	w.Events = splitScopes(events)
+	w.CreatedAt = w.CreatedAt.UTC()
+	return w, nil
+}
+
+func (r *PostgresWebhookSubscriptionRepository) list(ctx context.Context, q string, args ...any) ([]domain.WebhookSubscription, error) {
+	rows, err := r.DB.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []domain.WebhookSubscription
+	for rows.Next() {
+		w, err := scanWebhookSubscription(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, w)
+	}
+	return out, rows.Err()
+}
+
+func (r *PostgresWebhookSubscriptionRepository) Create(ctx context.Context, s domain.WebhookSubscription) (domain.WebhookSubscription, error) {
+	const q = `
+		INSERT INTO webhook_subscriptions (user_id, url, events, secret, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id
+	`
+	err := r.DB.QueryRowContext(ctx, q, s.UserID, s.URL, strings.Join(s.Events, ","), s.Secret, s.CreatedAt.UTC()).Scan(&s.ID)
+	if err != nil {
+		return domain.WebhookSubscription{}, err
+	}
+	return s, nil
+}
+
+func (r *PostgresWebhookSubscriptionRepository) FindByUser(ctx context.Context, userID, id int64) (*domain.WebhookSubscription, error) {
+	q := `SELECT ` + webhookSubscriptionColumns + ` FROM webhook_subscriptions WHERE id = $1 AND user_id = $2 LIMIT 1`
+
+	w, err := scanWebhookSubscription(r.DB.QueryRowContext(ctx, q, id, userID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &w, nil
+}
+
+func (r *PostgresWebhookSubscriptionRepository) FindByID(ctx context.Context, id int64) (*domain.WebhookSubscription, error) {
+	q := `SELECT ` + webhookSubscriptionColumns + ` FROM webhook_subscriptions WHERE id = $1 LIMIT 1`
+
+	w, err := scanWebhookSubscription(r.DB.QueryRowContext(ctx, q, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &w, nil
+}
+
+func (r *PostgresWebhookSubscriptionRepository) ListByUser(ctx context.Context, userID int64) ([]domain.WebhookSubscription, error) {
+	return r.list(ctx, `SELECT `+webhookSubscriptionColumns+` FROM webhook_subscriptions WHERE user_id = $1 ORDER BY id ASC`, userID)
+}
+
+func (r *PostgresWebhookSubscriptionRepository) CountByUser(ctx context.Context, userID int64) (int, error) {
+	var n int
+	err := r.DB.QueryRowContext(ctx, `SELECT COUNT(*) FROM webhook_subscriptions WHERE user_id = $1`, userID).Scan(&n)
+	return n, err
+}
+
+func (r *PostgresWebhookSubscriptionRepository) ListByEvent(ctx context.Context, event string, userID int64) ([]domain.WebhookSubscription, error) {
+	// events es una lista separada por comas: se busca ",event," para no matchear prefijos
+	q := `SELECT ` + webhookSubscriptionColumns + ` FROM webhook_subscriptions WHERE (',' || events || ',') LIKE $1`
+	args := []any{"%," + event + ",%"}
+	if userID > 0 {
+		args = append(args, userID)
+		q += fmt.Sprintf(` AND user_id = $%d`, len(args))
+	}
+	q += ` ORDER BY id ASC`
+	return r.list(ctx, q, args...)
+}
+
+func (r *PostgresWebhookSubscriptionRepository) Delete(ctx context.Context, userID, id int64) (bool, error) {
+	res, err := r.DB.ExecContext(ctx, `DELETE FROM webhook_subscriptions WHERE id = $1 AND user_id = $2`, id, userID)
+	if err != nil {
+		return false, err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
+type PostgresWebhookDeliveryRepository struct {
+	DB *sql.DB
+}
+
+func NewPostgresWebhookDeliveryRepository(db *sql.DB) *PostgresWebhookDeliveryRepository {
+	return &PostgresWebhookDeliveryRepository{DB: db}
+}
+
+const webhookDeliveryColumns = `id, subscription_id, event_id, event_type, payload, status, attempts, next_attempt_at,
+		last_attempt_at, last_status_code, last_error, delivered_at, created_at`
+
+func scanWebhookDelivery(s rowScanner) (domain.WebhookDelivery, error) {
+	var (
+		d             domain.WebhookDelivery
+		lastAttemptAt sql.NullTime
+		deliveredAt   sql.NullTime
+	)
+	err := s.Scan(&d.ID, &d.SubscriptionID, &d.EventID, &d.EventType, &d.Payload, &d.Status, &d.Attempts, &d.NextAttemptAt,
+		&lastAttemptAt, &d.LastStatusCode, &d.LastError, &deliveredAt, &d.CreatedAt)
+	if err != nil {
+		return domain.WebhookDelivery{}, err
+	}
+
+	d.NextAttemptAt = d.NextAttemptAt.UTC()
+	d.CreatedAt = d.CreatedAt.UTC()
+	d.LastAttemptAt = timePtr(lastAttemptAt)
+	d.DeliveredAt = timePtr(deliveredAt)
+	return d, nil
+}
+
+func (r *PostgresWebhookDeliveryRepository) list(ctx context.Context, q string, args ...any) ([]domain.WebhookDelivery, error) {
+	rows, err := r.DB.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []domain.WebhookDelivery
+	for rows.Next() {
+		d, err := scanWebhookDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, d)
+	}
+	return out, rows.Err()
+}
+
+func (r *PostgresWebhookDeliveryRepository) Enqueue(ctx context.Context, d domain.WebhookDelivery) (domain.WebhookDelivery, error) {
+	const q = `
+		INSERT INTO webhook_deliveries (subscription_id, event_id, event_type, payload, status, attempts, next_attempt_at,
+			last_status_code, last_error, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING id
+	`
+	err := r.DB.QueryRowContext(ctx, q, d.SubscriptionID, d.EventID, d.EventType, d.Payload, d.Status, d.Attempts,
+		d.NextAttemptAt.UTC(), d.LastStatusCode, d.LastError, d.CreatedAt.UTC()).Scan(&d.ID)
+	if err != nil {
+		return domain.WebhookDelivery{}, err
+	}
+
+	d.LastAttemptAt = nil
+	d.DeliveredAt = nil
+	return d, nil
+}
+
+func (r *PostgresWebhookDeliveryRepository) ListDue(ctx context.Context, now time.Time, limit int) ([]domain.WebhookDelivery, error) {
+	q := `SELECT ` + webhookDeliveryColumns + ` FROM webhook_deliveries
+		WHERE status = $1 AND next_attempt_at <= $2
+		ORDER BY next_attempt_at ASC, id ASC LIMIT $3`
+	return r.list(ctx, q, domain.WebhookDeliveryPending, now.UTC(), limit)
+}
+
+func (r *PostgresWebhookDeliveryRepository) Claim(ctx context.Context, id int64, attempts int, leaseUntil time.Time) (bool, error) {
+	const q = `
+		UPDATE webhook_deliveries
+		SET attempts = attempts + 1, next_attempt_at = $1
+		WHERE id = $2 AND status = $3 AND attempts = $4
+	`
+	res, err := r.DB.ExecContext(ctx, q, leaseUntil.UTC(), id, domain.WebhookDeliveryPending, attempts)
+	if err != nil {
+		return false, err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
+func (r *PostgresWebhookDeliveryRepository) RecordAttempt(ctx context.Context, d domain.WebhookDelivery) error {
+	const q = `
+		UPDATE webhook_deliveries
+		SET status = $1, next_attempt_at = $2, last_attempt_at = $3, last_status_code = $4, last_error = $5, delivered_at = $6
+		WHERE id = $7
+	`
+	_, err := r.DB.ExecContext(ctx, q, d.Status, d.NextAttemptAt.UTC(), nullTime(d.LastAttemptAt), d.LastStatusCode,
+		d.LastError, nullTime(d.DeliveredAt), d.ID)
+	return err
+}
+
+func (r *PostgresWebhookDeliveryRepository) FindBySubscription(ctx context.Context, subscriptionID, id int64) (*domain.WebhookDelivery, error) {
+	q := `SELECT ` + webhookDeliveryColumns + ` FROM webhook_deliveries WHERE id = $1 AND subscription_id = $2 LIMIT 1`
+
+	d, err := scanWebhookDelivery(r.DB.QueryRowContext(ctx, q, id, subscriptionID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+func (r *PostgresWebhookDeliveryRepository) ListBySubscription(ctx context.Context, subscriptionID int64, status string, limit int) ([]domain.WebhookDelivery, error) {
+	q := `SELECT ` + webhookDeliveryColumns + ` FROM webhook_deliveries WHERE subscription_id = $1`
+	args := []any{subscriptionID}
+	if status != "" {
+		args = append(args, status)
+		q += fmt.Sprintf(` AND status = $%d`, len(args))
+	}
+	args = append(args, limit)
+	q += fmt.Sprintf(` ORDER BY created_at DESC, id DESC LIMIT $%d`, len(args))
+	return r.list(ctx, q, args...)
+}
+
+func (r *PostgresWebhookDeliveryRepository) Replay(ctx context.Context, subscriptionID, id int64, now time.Time) (int64, error) {
+	q := `
+		UPDATE webhook_deliveries
+		SET status = $1, attempts = 0, next_attempt_at = $2
+		WHERE subscription_id = $3 AND status = $4`
+	args := []any{domain.WebhookDeliveryPending, now.UTC(), subscriptionID, domain.WebhookDeliveryDead}
+	if id > 0 {
+		args = append(args, id)
+		q += fmt.Sprintf(` AND id = $%d`, len(args))
+	}
+
+	res, err := r.DB.ExecContext(ctx, q, args...)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+func (r *PostgresWebhookDeliveryRepository) DeleteDeliveredBefore(ctx context.Context, before time.Time) (int64, error) {
+	const q = `DELETE FROM webhook_deliveries WHERE status = $1 AND delivered_at < $2`
+
+	res, err := r.DB.ExecContext(ctx, q, domain.WebhookDeliveryDelivered, before.UTC())
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
@@ -0,0 +1,94 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/moondolphin/crypto-api/domain"
+)
+
+type SQLiteEmailVerificationRepository struct {
+	DB *sql.DB
+}
+
+func NewSQLiteEmailVerificationRepository(db *sql.DB) *SQLiteEmailVerificationRepository {
+	return &SQLiteEmailVerificationRepository{DB: db}
+}
+
+func (r *SQLiteEmailVerificationRepository) Create(ctx context.Context, t domain.EmailVerificationToken) (domain.EmailVerificationToken, error) {
+	const q = `
+		INSERT INTO email_verification_tokens (user_id, token_hash, expires_at, created_at)
+		VALUES (?, ?, ?, ?)
+	`
+	res, err := r.DB.ExecContext(ctx, q, t.UserID, t.TokenHash, t.ExpiresAt.UTC(), t.CreatedAt.UTC())
+	if err != nil {
+		return domain.EmailVerificationToken{}, err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return domain.EmailVerificationToken{}, err
+	}
+
+	t.ID = id
+	return t, nil
+}
+
+func (r *SQLiteEmailVerificationRepository) FindByHash(ctx context.Context, tokenHash string) (*domain.EmailVerificationToken, error) {
+	const q = `
+		SELECT id, user_id, token_hash, expires_at, created_at, used_at
+		FROM email_verification_tokens
+		WHERE token_hash = ?
+		LIMIT 1
+	`
+	return scanEmailVerificationToken(r.DB.QueryRowContext(ctx, q, tokenHash))
+}
+
+func (r *SQLiteEmailVerificationRepository) LatestByUser(ctx context.Context, userID int64) (*domain.EmailVerificationToken, error) {
+	const q = `
+		SELECT id, user_id, token_hash, expires_at, created_at, used_at
+		FROM email_verification_tokens
+		WHERE user_id = ?
+		ORDER BY created_at DESC, id DESC
+		LIMIT 1
+	`
+	return scanEmailVerificationToken(r.DB.QueryRowContext(ctx, q, userID))
+}
+
+func (r *SQLiteEmailVerificationRepository) MarkUsed(ctx context.Context, id int64, at time.Time) (bool, error) {
+	// used_at IS NULL garantiza el único uso aunque lleguen dos verificaciones juntas
+	const q = `UPDATE email_verification_tokens SET used_at = ? WHERE id = ? AND used_at IS NULL`
+	res, err := r.DB.ExecContext(ctx, q, at.UTC(), id)
+	if err != nil {
+		return false, err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
+func (r *SQLiteEmailVerificationRepository) InvalidateByUser(ctx context.Context, userID int64, at time.Time) error {
+	const q = `UPDATE email_verification_tokens SET used_at = ? WHERE user_id = ? AND used_at IS NULL`
+	_, err := r.DB.ExecContext(ctx, q, at.UTC(), userID)
+	return err
+}
+
+func scanEmailVerificationToken(row *sql.Row) (*domain.EmailVerificationToken, error) {
+	var (
+		t      domain.EmailVerificationToken
+		usedAt sql.NullTime
+	)
+	err := row.Scan(&t.ID, &t.UserID, &t.TokenHash, &t.ExpiresAt, &t.CreatedAt, &usedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	t.UsedAt = timePtr(usedAt)
+	return &t, nil
+}
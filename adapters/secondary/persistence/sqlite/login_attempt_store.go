@@ -0,0 +1,78 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/moondolphin/crypto-api/domain"
+)
+
+type SQLiteLoginAttemptStore struct {
+	DB *sql.DB
+}
+
+func NewSQLiteLoginAttemptStore(db *sql.DB) *SQLiteLoginAttemptStore {
+	return &SQLiteLoginAttemptStore{DB: db}
+}
+
+func (s *SQLiteLoginAttemptStore) Get(ctx context.Context, key string) (*domain.LoginAttempt, error) {
+	const q = `
+		SELECT attempt_key, failures, last_failure_at, locked_until
+		FROM login_attempts
+		WHERE attempt_key = ?
+	`
+	var (
+		a           domain.LoginAttempt
+		lockedUntil sql.NullTime
+	)
+	err := s.DB.QueryRowContext(ctx, q, key).Scan(&a.Key, &a.Failures, &a.LastFailureAt, &lockedUntil)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	a.LockedUntil = timePtr(lockedUntil)
+	return &a, nil
+}
+
+func (s *SQLiteLoginAttemptStore) RecordFailure(ctx context.Context, key string, at, resetBefore time.Time) (int, error) {
+	// el upsert incrementa en una sola sentencia: dos fallos concurrentes no se pisan
+	const q = `
+		INSERT INTO login_attempts (attempt_key, failures, last_failure_at)
+		VALUES (?, 1, ?)
+		ON CONFLICT (attempt_key) DO UPDATE SET
+			failures = CASE WHEN MAX(login_attempts.last_failure_at, COALESCE(login_attempts.locked_until, login_attempts.last_failure_at)) < ? THEN 1 ELSE login_attempts.failures + 1 END,
+			last_failure_at = excluded.last_failure_at
+		RETURNING failures
+	`
+	var failures int
+	err := s.DB.QueryRowContext(ctx, q, key, at.UTC(), resetBefore.UTC()).Scan(&failures)
+	return failures, err
+}
+
+func (s *SQLiteLoginAttemptStore) Lock(ctx context.Context, key string, until time.Time) error {
+	const q = `UPDATE login_attempts SET locked_until = ? WHERE attempt_key = ?`
+	_, err := s.DB.ExecContext(ctx, q, until.UTC(), key)
+	return err
+}
+
+func (s *SQLiteLoginAttemptStore) Reset(ctx context.Context, key string) error {
+	const q = `DELETE FROM login_attempts WHERE attempt_key = ?`
+	_, err := s.DB.ExecContext(ctx, q, key)
+	return err
+}
+
+func (s *SQLiteLoginAttemptStore) DeleteStale(ctx context.Context, before time.Time) (int64, error) {
+	const q = `
+		DELETE FROM login_attempts
+		WHERE last_failure_at < ? AND (locked_until IS NULL OR locked_until < ?)
+	`
+	res, err := s.DB.ExecContext(ctx, q, before.UTC(), before.UTC())
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
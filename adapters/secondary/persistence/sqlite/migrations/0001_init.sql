@@ -0,0 +1,95 @@
+CREATE TABLE IF NOT EXISTS coins (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  symbol TEXT NOT NULL UNIQUE,
+  enabled BOOLEAN NOT NULL DEFAULT 1,
+  coingecko_id TEXT NOT NULL DEFAULT '',
+  binance_symbol TEXT NOT NULL DEFAULT '',
+  created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT OR IGNORE INTO coins (symbol, enabled, coingecko_id, binance_symbol) VALUES
+('BTC', 1, 'bitcoin', 'BTCUSDT'),
+('ETH', 1, 'ethereum', 'ETHUSDT'),
+('BNB', 1, 'binancecoin', 'BNBUSDT'),
+('SOL', 1, 'solana', 'SOLUSDT'),
+('XRP', 1, 'ripple', 'XRPUSDT'),
+('ADA', 1, 'cardano', 'ADAUSDT'),
+('DOGE', 1, 'dogecoin', 'DOGEUSDT'),
+('AVAX', 1, 'avalanche-2', 'AVAXUSDT'),
+('TRX', 1, 'tron', 'TRXUSDT'),
+('DOT', 1, 'polkadot', 'DOTUSDT'),
+('MATIC', 1, 'matic-network', 'MATICUSDT'),
+('LINK', 1, 'chainlink', 'LINKUSDT'),
+('LTC', 1, 'litecoin', 'LTCUSDT'),
+('BCH', 1, 'bitcoin-cash', 'BCHUSDT'),
+('ATOM', 1, 'cosmos', 'ATOMUSDT'),
+('ETC', 1, 'ethereum-classic', 'ETCUSDT'),
+('FIL', 1, 'filecoin', 'FILUSDT'),
+('ICP', 1, 'internet-computer', 'ICPUSDT'),
+('APT', 1, 'aptos', 'APTUSDT'),
+('ARB', 1, 'arbitrum', 'ARBUSDT'),
+('OP', 1, 'optimism', 'OPUSDT'),
+('NEAR', 1, 'near', 'NEARUSDT'),
+('ALGO', 1, 'algorand', 'ALGOUSDT'),
+('VET', 1, 'vechain', 'VETUSDT'),
+('HBAR', 1, 'hedera-hashgraph', 'HBARUSDT'),
+('SAND', 1, 'the-sandbox', 'SANDUSDT'),
+('MANA', 1, 'decentraland', 'MANAUSDT'),
+('EGLD', 1, 'elrond-erd-2', 'EGLDUSDT'),
+('AAVE', 1, 'aave', 'AAVEUSDT'),
+('AXS', 1, 'axie-infinity', 'AXSUSDT'),
+('XTZ', 1, 'tezos', 'XTZUSDT'),
+('THETA', 1, 'theta-token', 'THETAUSDT'),
+('EOS', 1, 'eos', 'EOSUSDT'),
+('KLAY', 1, 'klay-token', 'KLAYUSDT'),
+('FLOW', 1, 'flow', 'FLOWUSDT'),
+('GALA', 1, 'gala', 'GALAUSDT'),
+('CHZ', 1, 'chiliz', 'CHZUSDT'),
+('ENJ', 1, 'enjincoin', 'ENJUSDT'),
+('DYDX', 1, 'dydx', 'DYDXUSDT'),
+('CRV', 1, 'curve-dao-token', 'CRVUSDT'),
+('SNX', 1, 'synthetix-network-token', 'SNXUSDT'),
+('COMP', 1, 'compound-governance-token', 'COMPUSDT'),
+('KSM', 1, 'kusama', 'KSMUSDT'),
+('ZIL', 1, 'zilliqa', 'ZILUSDT'),
+('1INCH', 1, '1inch', '1INCHUSDT'),
+('BAT', 1, 'basic-attention-token', 'BATUSDT'),
+('ANKR', 1, 'ankr', 'ANKRUSDT'),
+('CELO', 1, 'celo', 'CELOUSDT');
+
+CREATE TABLE IF NOT EXISTS quotes (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  coin_id INTEGER NOT NULL REFERENCES coins(id),
+  symbol TEXT NOT NULL,
+  provider TEXT NOT NULL,
+  currency TEXT NOT NULL,
+  price TEXT NOT NULL,
+  quoted_at DATETIME NOT NULL,
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_quotes_coin_time ON quotes (coin_id, quoted_at);
+CREATE INDEX IF NOT EXISTS idx_quotes_provider ON quotes (provider);
+
+CREATE TABLE IF NOT EXISTS users (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  email TEXT NOT NULL UNIQUE,
+  name TEXT NOT NULL,
+  password_hash TEXT NOT NULL,
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS user_favorites (
+  user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  coin_id INTEGER NOT NULL REFERENCES coins(id) ON DELETE CASCADE,
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (user_id, coin_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_fav_coin ON user_favorites (coin_id);
+
+CREATE TABLE IF NOT EXISTS refresh_control (
+  "key" TEXT NOT NULL PRIMARY KEY,
+  "value" TEXT NOT NULL,
+  updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
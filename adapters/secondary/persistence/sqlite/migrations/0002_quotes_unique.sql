@@ -0,0 +1,9 @@
+-- elimina cotizaciones duplicadas (se conserva la de menor id) y agrega la unicidad
+DELETE FROM quotes
+WHERE id NOT IN (
+  SELECT MIN(id) FROM quotes
+  GROUP BY coin_id, provider, currency, quoted_at
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS uk_quotes_coin_provider_currency_time
+  ON quotes (coin_id, provider, currency, quoted_at);
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS revoked_tokens (
+  jti TEXT NOT NULL PRIMARY KEY,
+  expires_at DATETIME NOT NULL,
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_revoked_tokens_expires ON revoked_tokens (expires_at);
+
+CREATE TABLE IF NOT EXISTS revoked_user_tokens (
+  user_id INTEGER NOT NULL PRIMARY KEY,
+  revoked_before DATETIME NOT NULL,
+  expires_at DATETIME NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_revoked_user_tokens_expires ON revoked_user_tokens (expires_at);
@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS login_attempts (
+  attempt_key TEXT PRIMARY KEY,
+  failures INTEGER NOT NULL,
+  last_failure_at DATETIME NOT NULL,
+  locked_until DATETIME NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_login_attempts_last_failure ON login_attempts (last_failure_at);
@@ -0,0 +1,38 @@
+CREATE TABLE IF NOT EXISTS alert_rules (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  coin_id INTEGER NOT NULL REFERENCES coins(id) ON DELETE CASCADE,
+  symbol TEXT NOT NULL,
+  currency TEXT NOT NULL DEFAULT '',
+  provider TEXT NOT NULL DEFAULT '',
+  kind TEXT NOT NULL,
+  threshold REAL NOT NULL,
+  window_seconds INTEGER NOT NULL DEFAULT 0,
+  rearm INTEGER NOT NULL DEFAULT 0,
+  active INTEGER NOT NULL DEFAULT 1,
+  armed INTEGER NOT NULL DEFAULT 1,
+  last_triggered_at DATETIME NULL,
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_alert_rules_user ON alert_rules (user_id);
+CREATE INDEX IF NOT EXISTS idx_alert_rules_active ON alert_rules (active);
+
+CREATE TABLE IF NOT EXISTS alert_events (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  rule_id INTEGER NOT NULL REFERENCES alert_rules(id) ON DELETE CASCADE,
+  user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  symbol TEXT NOT NULL,
+  provider TEXT NOT NULL,
+  currency TEXT NOT NULL,
+  kind TEXT NOT NULL,
+  threshold REAL NOT NULL,
+  price TEXT NOT NULL,
+  quoted_at DATETIME NOT NULL,
+  reference_price TEXT NULL,
+  change_percent REAL NULL,
+  triggered_at DATETIME NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_alert_events_user ON alert_events (user_id, triggered_at);
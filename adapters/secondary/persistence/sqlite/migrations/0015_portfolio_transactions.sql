@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS portfolio_transactions (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  portfolio_id INTEGER NOT NULL REFERENCES portfolios(id) ON DELETE CASCADE,
+  coin_id INTEGER NOT NULL REFERENCES coins(id) ON DELETE CASCADE,
+  type TEXT NOT NULL,
+  quantity TEXT NOT NULL,
+  price TEXT NOT NULL,
+  fee TEXT NOT NULL,
+  currency TEXT NOT NULL,
+  executed_at DATETIME NOT NULL,
+  note TEXT NOT NULL DEFAULT '',
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_portfolio_transactions_time ON portfolio_transactions (portfolio_id, executed_at);
@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS portfolio_snapshots (
+  portfolio_id INTEGER NOT NULL REFERENCES portfolios(id) ON DELETE CASCADE,
+  day DATE NOT NULL,
+  currency TEXT NOT NULL,
+  value TEXT NOT NULL,
+  computed_at DATETIME NOT NULL,
+  PRIMARY KEY (portfolio_id, day, currency)
+);
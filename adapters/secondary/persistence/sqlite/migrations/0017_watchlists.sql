@@ -0,0 +1,40 @@
+CREATE TABLE IF NOT EXISTS watchlists (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  name TEXT NOT NULL,
+  -- user_id en la lista por defecto, NULL en las demás: una sola por usuario
+  default_user_id INTEGER UNIQUE REFERENCES users(id) ON DELETE CASCADE,
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_watchlists_user ON watchlists (user_id);
+
+CREATE TABLE IF NOT EXISTS watchlist_entries (
+  watchlist_id INTEGER NOT NULL REFERENCES watchlists(id) ON DELETE CASCADE,
+  coin_id INTEGER NOT NULL REFERENCES coins(id) ON DELETE CASCADE,
+  sort_order INTEGER NOT NULL,
+  note TEXT NOT NULL DEFAULT '',
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (watchlist_id, coin_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_watchlist_entries_coin ON watchlist_entries (coin_id);
+
+-- las favoritas pasan a la lista por defecto, ordenadas por símbolo
+INSERT INTO watchlists (user_id, name, default_user_id, created_at, updated_at)
+SELECT user_id, 'Favorites', user_id, MIN(created_at), MIN(created_at)
+FROM user_favorites
+GROUP BY user_id;
+
+INSERT INTO watchlist_entries (watchlist_id, coin_id, sort_order, note, created_at, updated_at)
+SELECT w.id, uf.coin_id,
+  (SELECT COUNT(*) FROM user_favorites uf2 JOIN coins c2 ON c2.id = uf2.coin_id
+   WHERE uf2.user_id = uf.user_id AND c2.symbol < c.symbol),
+  '', uf.created_at, uf.created_at
+FROM user_favorites uf
+JOIN coins c ON c.id = uf.coin_id
+JOIN watchlists w ON w.default_user_id = uf.user_id;
+
+DROP TABLE user_favorites;
@@ -0,0 +1,29 @@
+-- SQLite no cambia NOT NULL con ALTER: se rearma la tabla. Un transfer_in
+-- cargado sin precio pasa a guardar NULL (distinto de un costo 0); los que ya
+-- tenían "0" se guardaron así por no traer precio y quedan en NULL, como los
+-- trataba el reporte impositivo.
+CREATE TABLE portfolio_transactions_new (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  portfolio_id INTEGER NOT NULL REFERENCES portfolios(id) ON DELETE CASCADE,
+  coin_id INTEGER NOT NULL REFERENCES coins(id) ON DELETE CASCADE,
+  type TEXT NOT NULL,
+  quantity TEXT NOT NULL,
+  price TEXT,
+  fee TEXT NOT NULL,
+  currency TEXT NOT NULL,
+  executed_at DATETIME NOT NULL,
+  note TEXT NOT NULL DEFAULT '',
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT INTO portfolio_transactions_new
+  (id, portfolio_id, coin_id, type, quantity, price, fee, currency, executed_at, note, created_at)
+SELECT id, portfolio_id, coin_id, type, quantity,
+  CASE WHEN type = 'transfer_in' AND CAST(price AS REAL) = 0 THEN NULL ELSE price END,
+  fee, currency, executed_at, note, created_at
+FROM portfolio_transactions;
+
+DROP TABLE portfolio_transactions;
+ALTER TABLE portfolio_transactions_new RENAME TO portfolio_transactions;
+
+CREATE INDEX IF NOT EXISTS idx_portfolio_transactions_time ON portfolio_transactions (portfolio_id, executed_at);
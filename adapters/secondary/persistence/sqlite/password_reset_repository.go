@@ -0,0 +1,84 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/moondolphin/crypto-api/domain"
+)
+
+type SQLitePasswordResetRepository struct {
+	DB *sql.DB
+}
+
+func NewSQLitePasswordResetRepository(db *sql.DB) *SQLitePasswordResetRepository {
+	return &SQLitePasswordResetRepository{DB: db}
+}
+
+func (r *SQLitePasswordResetRepository) Create(ctx context.Context, t domain.PasswordResetToken) (domain.PasswordResetToken, error) {
+	const q = `
+		INSERT INTO password_reset_tokens (user_id, token_hash, expires_at, created_at)
+		VALUES (?, ?, ?, ?)
+	`
+	res, err := r.DB.ExecContext(ctx, q, t.UserID, t.TokenHash, t.ExpiresAt.UTC(), t.CreatedAt.UTC())
+	if err != nil {
+		return domain.PasswordResetToken{}, err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return domain.PasswordResetToken{}, err
+	}
+
+	t.ID = id
+	return t, nil
+}
+
+func (r *SQLitePasswordResetRepository) FindByHash(ctx context.Context, tokenHash string) (*domain.PasswordResetToken, error) {
+	const q = `
+		SELECT id, user_id, token_hash, expires_at, created_at, used_at
+		FROM password_reset_tokens
+		WHERE token_hash = ?
+		LIMIT 1
+	`
+	var (
+		t      domain.PasswordResetToken
+		usedAt sql.NullTime
+	)
+	err := r.DB.QueryRowContext(ctx, q, tokenHash).
+		Scan(&t.ID, &t.UserID, &t.TokenHash, &t.ExpiresAt, &t.CreatedAt, &usedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if usedAt.Valid {
+		at := usedAt.Time.UTC()
+		t.UsedAt = &at
+	}
+	return &t, nil
+}
+
+func (r *SQLitePasswordResetRepository) MarkUsed(ctx context.Context, id int64, at time.Time) (bool, error) {
+	// used_at IS NULL garantiza el único uso aunque lleguen dos confirmaciones juntas
+	const q = `UPDATE password_reset_tokens SET used_at = ? WHERE id = ? AND used_at IS NULL`
+	res, err := r.DB.ExecContext(ctx, q, at.UTC(), id)
+	if err != nil {
+		return false, err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
+func (r *SQLitePasswordResetRepository) InvalidateByUser(ctx context.Context, userID int64, at time.Time) error {
+	const q = `UPDATE password_reset_tokens SET used_at = ? WHERE user_id = ? AND used_at IS NULL`
+	_, err := r.DB.ExecContext(ctx, q, at.UTC(), userID)
+	return err
+}
@@ -0,0 +1,174 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/moondolphin/crypto-api/domain"
+)
+
+type SQLitePortfolioRepository struct {
+	DB *sql.DB
+}
+
+func NewSQLitePortfolioRepository(db *sql.DB) *SQLitePortfolioRepository {
+	return &SQLitePortfolioRepository{DB: db}
+}
+
+const portfolioColumns = `id, user_id, name, base_currency, created_at, updated_at`
+
+func scanPortfolio(s rowScanner) (domain.Portfolio, error) {
+	var p domain.Portfolio
+	err := s.Scan(&p.ID, &p.UserID, &p.Name, &p.BaseCurrency, &p.CreatedAt, &p.UpdatedAt)
+	return p, err
+}
+
+func (r *SQLitePortfolioRepository) Create(ctx context.Context, p domain.Portfolio) (domain.Portfolio, error) {
+	const q = `INSERT INTO portfolios (user_id, name, base_currency, created_at, updated_at) VALUES (?, ?, ?, ?, ?)`
+
+	res, err := r.DB.ExecContext(ctx, q, p.UserID, p.Name, p.BaseCurrency, p.CreatedAt.UTC(), p.UpdatedAt.UTC())
+	if err != nil {
+		return domain.Portfolio{}, err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return domain.Portfolio{}, err
+	}
+
+	p.ID = id
+	return p, nil
+}
+
+func (r *SQLitePortfolioRepository) FindByUser(ctx context.Context, userID, id int64) (*domain.Portfolio, error) {
+	q := `SELECT ` + portfolioColumns + ` FROM portfolios WHERE id = ? AND user_id = ? LIMIT 1`
+
+	p, err := scanPortfolio(r.DB.QueryRowContext(ctx, q, id, userID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+func (r *SQLitePortfolioRepository) ListByUser(ctx context.Context, userID int64) ([]domain.Portfolio, error) {
+	q := `SELECT ` + portfolioColumns + ` FROM portfolios WHERE user_id = ? ORDER BY id ASC`
+
+	rows, err := r.DB.QueryContext(ctx, q, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []domain.Portfolio
+	for rows.Next() {
+		p, err := scanPortfolio(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, p)
+	}
+	return out, rows.Err()
+}
+
+func (r *SQLitePortfolioRepository) CountByUser(ctx context.Context, userID int64) (int, error) {
+	var n int
+	err := r.DB.QueryRowContext(ctx, `SELECT COUNT(*) FROM portfolios WHERE user_id = ?`, userID).Scan(&n)
+	return n, err
+}
+
+func (r *SQLitePortfolioRepository) Update(ctx context.Context, p domain.Portfolio) (bool, error) {
+	const q = `UPDATE portfolios SET name = ?, base_currency = ?, updated_at = ? WHERE id = ? AND user_id = ?`
+
+	res, err := r.DB.ExecContext(ctx, q, p.Name, p.BaseCurrency, p.UpdatedAt.UTC(), p.ID, p.UserID)
+	if err != nil {
+		return false, err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
+func (r *SQLitePortfolioRepository) Delete(ctx context.Context, userID, id int64) (bool, error) {
+	res, err := r.DB.ExecContext(ctx, `DELETE FROM portfolios WHERE id = ? AND user_id = ?`, id, userID)
+	if err != nil {
+		return false, err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
+type SQLiteHoldingRepository struct {
+	DB *sql.DB
+}
+
+func NewSQLiteHoldingRepository(db *sql.DB) *SQLiteHoldingRepository {
+	return &SQLiteHoldingRepository{DB: db}
+}
+
+const holdingSelect = `
+	SELECT h.id, h.portfolio_id, h.coin_id, c.symbol, h.quantity, h.created_at, h.updated_at
+	FROM portfolio_holdings h
+	JOIN coins c ON c.id = h.coin_id`
+
+func scanHolding(s rowScanner) (domain.Holding, error) {
+	var h domain.Holding
+	err := s.Scan(&h.ID, &h.PortfolioID, &h.CoinID, &h.Symbol, &h.Quantity, &h.CreatedAt, &h.UpdatedAt)
+	return h, err
+}
+
+func (r *SQLiteHoldingRepository) Upsert(ctx context.Context, h domain.Holding) (domain.Holding, error) {
+	const q = `
+		INSERT INTO portfolio_holdings (portfolio_id, coin_id, quantity, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT (portfolio_id, coin_id) DO UPDATE SET
+			quantity = excluded.quantity,
+			updated_at = excluded.updated_at
+	`
+	if _, err := r.DB.ExecContext(ctx, q, h.PortfolioID, h.CoinID, h.Quantity, h.CreatedAt.UTC(), h.UpdatedAt.UTC()); err != nil {
+		return domain.Holding{}, err
+	}
+
+	// se relee para devolver id, símbolo y created_at de la fila que quedó
+	return scanHolding(r.DB.QueryRowContext(ctx, holdingSelect+` WHERE h.portfolio_id = ? AND h.coin_id = ?`, h.PortfolioID, h.CoinID))
+}
+
+func (r *SQLiteHoldingRepository) ListByPortfolio(ctx context.Context, portfolioID int64) ([]domain.Holding, error) {
+	rows, err := r.DB.QueryContext(ctx, holdingSelect+` WHERE h.portfolio_id = ? ORDER BY c.symbol ASC`, portfolioID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []domain.Holding
+	for rows.Next() {
+		h, err := scanHolding(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, h)
+	}
+	return out, rows.Err()
+}
+
+func (r *SQLiteHoldingRepository) Delete(ctx context.Context, portfolioID, coinID int64) (bool, error) {
+	res, err := r.DB.ExecContext(ctx, `DELETE FROM portfolio_holdings WHERE portfolio_id = ? AND coin_id = ?`, portfolioID, coinID)
+	if err != nil {
+		return false, err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
@@ -0,0 +1,397 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/moondolphin/crypto-api/domain"
+)
+
+// SQLiteQuoteRepository guarda price como TEXT (equivalente al DECIMAL de MySQL
+// escaneado como string) y lo castea a REAL solo para comparar/ordenar.
+type SQLiteQuoteRepository struct {
+	DB *sql.DB
+}
+
+func NewSQLiteQuoteRepository(db *sql.DB) *SQLiteQuoteRepository {
+	return &SQLiteQuoteRepository{DB: db}
+}
+
+func (r *SQLiteQuoteRepository) Insert(ctx context.Context, q domain.Quote) (bool, error) {
+	const stmt = `
+		INSERT INTO quotes (coin_id, symbol, provider, currency, price, quoted_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT (coin_id, provider, currency, quoted_at) DO NOTHING
+	`
+	res, err := r.DB.ExecContext(ctx, stmt, q.CoinID, q.Symbol, q.Provider, q.Currency, q.Price, q.QuotedAt.UTC())
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+func (r *SQLiteQuoteRepository) GetLatest(ctx context.Context, symbol, provider, currency string) (*domain.PriceQuote, error) {
+	const base = `
+SELECT symbol, provider, currency, price, quoted_at
+FROM quotes
+WHERE symbol = ?
+`
+
+	q := base
+	args := []any{symbol}
+
+	if provider != "" {
+		q += " AND provider = ?"
+		args = append(args, provider)
+	}
+	if currency != "" {
+		q += " AND currency = ?"
+		args = append(args, currency)
+	}
+
+	q += " ORDER BY quoted_at DESC LIMIT 1"
+
+	var out domain.PriceQuote
+	if err := r.DB.QueryRowContext(ctx, q, args...).Scan(
+		&out.Symbol,
+		&out.Provider,
+		&out.Currency,
+		&out.Price,
+		&out.Timestamp,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &out, nil
+}
+
+// buildQuoteWhere arma el WHERE dinámico y sus args (mismo tamiz que MySQL).
+// Los tiempos se bindean en UTC para que la comparación textual de SQLite sea consistente.
+func buildQuoteWhere(f domain.QuoteFilter) (string, []any) {
+	where := " WHERE 1=1"
+	args := make([]any, 0, 12)
+
+	if f.Symbol != "" {
+		where += " AND symbol = ?"
+		args = append(args, f.Symbol)
+	}
+	if f.Provider != "" {
+		where += " AND provider = ?"
+		args = append(args, f.Provider)
+	}
+	if f.Currency != "" {
+		where += " AND currency = ?"
+		args = append(args, f.Currency)
+	}
+	if f.From != nil {
+		where += " AND quoted_at >= ?"
+		args = append(args, f.From.UTC())
+	}
+	if f.To != nil {
+		where += " AND quoted_at <= ?"
+		args = append(args, f.To.UTC())
+	}
+	if f.MinPrice != nil {
+		where += " AND CAST(price AS REAL) >= ?"
+		args = append(args, *f.MinPrice)
+	}
+	if f.MaxPrice != nil {
+		where += " AND CAST(price AS REAL) <= ?"
+		args = append(args, *f.MaxPrice)
+	}
+
+	return where, args
+}
+
+func (r *SQLiteQuoteRepository) ListFilter(ctx context.Context, f domain.QuoteFilter) ([]domain.Quote, int, error) {
+	// defaults defensivos
+	page := f.Page
+	if page <= 0 {
+		page = 1
+	}
+	pageSize := f.PageSize
+	if pageSize <= 0 {
+		pageSize = 50
+	}
+	if pageSize > 100 {
+		pageSize = 100
+	}
+	offset := (page - 1) * pageSize
+
+	where, args := buildQuoteWhere(f)
+
+	countSQL := "SELECT COUNT(*) FROM quotes" + where
+	var total int
+	if err := r.DB.QueryRowContext(ctx, countSQL, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	listSQL := `
+SELECT id, coin_id, symbol, provider, currency, price, quoted_at, created_at
+FROM quotes
+` + where + `
+ORDER BY quoted_at DESC
+LIMIT ? OFFSET ?
+`
+
+	listArgs := make([]any, 0, len(args)+2)
+	listArgs = append(listArgs, args...)
+	listArgs = append(listArgs, pageSize, offset)
+
+	rows, err := r.DB.QueryContext(ctx, listSQL, listArgs...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	out := make([]domain.Quote, 0, pageSize)
+
+	for rows.Next() {
+		var q domain.Quote
+		if err := rows.Scan(
+			&q.ID,
+			&q.CoinID,
+			&q.Symbol,
+			&q.Provider,
+			&q.Currency,
+			&q.Price,
+			&q.QuotedAt,
+			&q.CreatedAt,
+		); err != nil {
+			return nil, 0, err
+		}
+		out = append(out, q)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return out, total, nil
+}
+
+// ListAvailableFilters devuelve "faceted filters" con el mismo tamiz que ListFilter.
+// Los extremos se obtienen con ORDER BY ... LIMIT 1 para conservar el texto original
+// del precio y el tipo DATETIME de quoted_at (MIN/MAX en SQLite pierden ambos).
+func (r *SQLiteQuoteRepository) ListAvailableFilters(ctx context.Context, f domain.QuoteFilter) (domain.QuoteFilters, error) {
+	where, args := buildQuoteWhere(f)
+
+	distinctList := func(col string) ([]string, error) {
+		q := fmt.Sprintf(`SELECT DISTINCT %s FROM quotes %s ORDER BY %s ASC`, col, where, col)
+		rows, err := r.DB.QueryContext(ctx, q, args...)
+		if err != nil {
+			return nil, err
+		}
+		defer rows.Close()
+
+		out := make([]string, 0, 16)
+		for rows.Next() {
+			var v sql.NullString
+			if err := rows.Scan(&v); err != nil {
+				return nil, err
+			}
+			if v.Valid && v.String != "" {
+				out = append(out, v.String)
+			}
+		}
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+		return out, nil
+	}
+
+	symbols, err := distinctList("symbol")
+	if err != nil {
+		return domain.QuoteFilters{}, err
+	}
+	providers, err := distinctList("provider")
+	if err != nil {
+		return domain.QuoteFilters{}, err
+	}
+	currencies, err := distinctList("currency")
+	if err != nil {
+		return domain.QuoteFilters{}, err
+	}
+
+	edge := func(col, orderBy string, dest any) (bool, error) {
+		q := fmt.Sprintf(`SELECT %s FROM quotes %s ORDER BY %s LIMIT 1`, col, where, orderBy)
+		err := r.DB.QueryRowContext(ctx, q, args...).Scan(dest)
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+		return err == nil, err
+	}
+
+	out := domain.QuoteFilters{
+		Symbols:    symbols,
+		Providers:  providers,
+		Currencies: currencies,
+	}
+
+	var minPrice, maxPrice string
+	if ok, err := edge("price", "CAST(price AS REAL) ASC", &minPrice); err != nil {
+		return domain.QuoteFilters{}, err
+	} else if ok {
+		out.MinPrice = minPrice
+	}
+	if ok, err := edge("price", "CAST(price AS REAL) DESC", &maxPrice); err != nil {
+		return domain.QuoteFilters{}, err
+	} else if ok {
+		out.MaxPrice = maxPrice
+	}
+
+	var minTime, maxTime time.Time
+	if ok, err := edge("quoted_at", "quoted_at ASC", &minTime); err != nil {
+		return domain.QuoteFilters{}, err
+	} else if ok {
+		t := minTime.UTC()
+		out.From = &t
+	}
+	if ok, err := edge("quoted_at", "quoted_at DESC", &maxTime); err != nil {
+		return domain.QuoteFilters{}, err
+	} else if ok {
+		t := maxTime.UTC()
+		out.To = &t
+	}
+
+	return out, nil
+}
+
+// ListDailyCloses agrupa por date(quoted_at): los tiempos se guardan en UTC, así
+// que el día es el de UTC. El JOIN contra el MAX por día reemplaza a una
+// función de ventana para usar la misma consulta que MySQL 5.7.
+func (r *SQLiteQuoteRepository) ListDailyCloses(ctx context.Context, f domain.DailyCloseFilter) ([]domain.DailyClose, error) {
+	if len(f.Symbols) == 0 {
+		return []domain.DailyClose{}, nil
+	}
+
+	where := " WHERE currency = ? AND quoted_at >= ? AND quoted_at < ?"
+	args := []any{f.Currency, f.From.UTC(), f.To.UTC()}
+	where += " AND symbol IN (?" + strings.Repeat(", ?", len(f.Symbols)-1) + ")"
+	for _, s := range f.Symbols {
+		args = append(args, s)
+	}
+	if f.Provider != "" {
+		where += " AND provider = ?"
+		args = append(args, f.Provider)
+	}
+
+	q := `
+SELECT q.symbol, q.provider, q.price, q.quoted_at
+FROM quotes q
+JOIN (
+	SELECT symbol, MAX(quoted_at) AS last_at
+	FROM quotes` + where + `
+	GROUP BY symbol, date(quoted_at)
+) d ON d.symbol = q.symbol AND d.last_at = q.quoted_at
+WHERE q.currency = ?`
+	args = append(args, f.Currency)
+	if f.Provider != "" {
+		q += " AND q.provider = ?"
+		args = append(args, f.Provider)
+	}
+	q += " ORDER BY q.symbol ASC, q.quoted_at ASC, q.provider ASC"
+
+	rows, err := r.DB.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]domain.DailyClose, 0, 64)
+	for rows.Next() {
+		var c domain.DailyClose
+		if err := rows.Scan(&c.Symbol, &c.Provider, &c.Price, &c.QuotedAt); err != nil {
+			return nil, err
+		}
+		c.QuotedAt = c.QuotedAt.UTC()
+		c.Day = c.QuotedAt.Truncate(24 * time.Hour)
+
+		// empate entre providers en el mismo instante: queda el primero
+		if n := len(out); n > 0 && out[n-1].Symbol == c.Symbol && out[n-1].Day.Equal(c.Day) {
+			continue
+		}
+		out = append(out, c)
+	}
+	return out, rows.Err()
+}
+
+// tickerWhere filtra por símbolos y pares provider/moneda; se usa en las dos
+// subconsultas de ListTickers, así que los args se repiten.
+func tickerWhere(f domain.QuoteTickerFilter) (string, []any) {
+	where := " WHERE symbol IN (?" + strings.Repeat(", ?", len(f.Symbols)-1) + ")"
+	args := make([]any, 0, len(f.Symbols)+2*len(f.Pairs))
+	for _, s := range f.Symbols {
+		args = append(args, s)
+	}
+	if len(f.Pairs) > 0 {
+		where += " AND ((provider = ? AND currency = ?)" + strings.Repeat(" OR (provider = ? AND currency = ?)", len(f.Pairs)-1) + ")"
+		for _, p := range f.Pairs {
+			args = append(args, p.Provider, p.Currency)
+		}
+	}
+	return where, args
+}
+
+// ListTickers junta el MAX(quoted_at) de cada grupo con el MAX hasta PrevAt
+// (LEFT JOIN: puede no haber referencia) en una sola consulta.
+func (r *SQLiteQuoteRepository) ListTickers(ctx context.Context, f domain.QuoteTickerFilter) ([]domain.QuoteTicker, error) {
+	if len(f.Symbols) == 0 {
+		return []domain.QuoteTicker{}, nil
+	}
+
+	where, whereArgs := tickerWhere(f)
+
+	q := `
+SELECT q.symbol, q.provider, q.currency, q.price, q.quoted_at, p.price, p.quoted_at
+FROM quotes q
+JOIN (
+	SELECT symbol, provider, currency, MAX(quoted_at) AS last_at
+	FROM quotes` + where + `
+	GROUP BY symbol, provider, currency
+) l ON l.symbol = q.symbol AND l.provider = q.provider AND l.currency = q.currency AND l.last_at = q.quoted_at
+LEFT JOIN (
+	SELECT symbol, provider, currency, MAX(quoted_at) AS prev_at
+	FROM quotes` + where + ` AND quoted_at <= ?
+	GROUP BY symbol, provider, currency
+) pa ON pa.symbol = q.symbol AND pa.provider = q.provider AND pa.currency = q.currency
+LEFT JOIN quotes p ON p.symbol = pa.symbol AND p.provider = pa.provider AND p.currency = pa.currency AND p.quoted_at = pa.prev_at
+ORDER BY q.symbol ASC, q.provider ASC, q.currency ASC`
+
+	args := make([]any, 0, 2*len(whereArgs)+1)
+	args = append(args, whereArgs...)
+	args = append(args, whereArgs...)
+	args = append(args, f.PrevAt.UTC())
+
+	rows, err := r.DB.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]domain.QuoteTicker, 0, len(f.Symbols))
+	for rows.Next() {
+		var t domain.QuoteTicker
+		var prevPrice sql.NullString
+		var prevAt sql.NullTime
+		if err := rows.Scan(&t.Symbol, &t.Provider, &t.Currency, &t.Price, &t.QuotedAt, &prevPrice, &prevAt); err != nil {
+			return nil, err
+		}
+		t.QuotedAt = t.QuotedAt.UTC()
+		if prevPrice.Valid && prevAt.Valid {
+			t.PrevPrice = prevPrice.String
+			t.PrevQuotedAt = prevAt.Time.UTC()
+		}
+		out = append(out, t)
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,39 @@
+package sqlite
+
+import (
+	"database/sql"
+
+	"github.com/moondolphin/crypto-api/domain"
+)
+
+// NewRepositories arma todos los puertos de persistencia sobre la misma base.
+func NewRepositories(db *sql.DB) domain.Repositories {
+	return domain.Repositories{
+		Users:            NewSQLiteUserRepository(db),
+		Coins:            NewSQLiteCoinRepository(db),
+		Quotes:           NewSQLiteQuoteRepository(db),
+		Favorites:        NewSQLiteFavoritesRepository(db),
+		RefreshControl:   NewSQLiteRefreshControlRepository(db),
+		RefreshTokens:    NewSQLiteRefreshTokenRepository(db),
+		Revocations:      NewSQLiteTokenRevocationStore(db),
+		APIKeys:          NewSQLiteAPIKeyRepository(db),
+		PasswordResets:   NewSQLitePasswordResetRepository(db),
+		Verifications:    NewSQLiteEmailVerificationRepository(db),
+		LoginAttempts:    NewSQLiteLoginAttemptStore(db),
+		TOTP:             NewSQLiteTOTPRepository(db),
+		RecoveryCodes:    NewSQLiteRecoveryCodeRepository(db),
+		Challenges:       NewSQLiteLoginChallengeRepository(db),
+		Identities:       NewSQLiteUserIdentityRepository(db),
+		OIDCStates:       NewSQLiteOIDCStateRepository(db),
+		AlertRules:       NewSQLiteAlertRuleRepository(db),
+		AlertEvents:      NewSQLiteAlertEventRepository(db),
+		Webhooks:         NewSQLiteWebhookSubscriptionRepository(db),
+		Deliveries:       NewSQLiteWebhookDeliveryRepository(db),
+		Portfolios:       NewSQLitePortfolioRepository(db),
+		Holdings:         NewSQLiteHoldingRepository(db),
+		Transactions:     NewSQLiteTransactionRepository(db),
+		Snapshots:        NewSQLitePortfolioSnapshotRepository(db),
+		Watchlists:       NewSQLiteWatchlistRepository(db),
+		WatchlistEntries: NewSQLiteWatchlistEntryRepository(db),
+	}
+}
@@ -0,0 +1,21 @@
+package sqlite_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/moondolphin/crypto-api/adapters/secondary/persistence/sqlite"
+	"github.com/moondolphin/crypto-api/test/contract"
+)
+
+func TestSQLiteRepositories_Contract(t *testing.T) {
+	contract.RunRepositoryContract(t, func(t *testing.T) contract.Repositories {
+		db, err := sqlite.Open(context.Background(), ":memory:")
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = db.Close() })
+
+		return sqlite.NewRepositories(db)
+	})
+}
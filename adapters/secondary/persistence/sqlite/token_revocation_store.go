@@ -0,0 +1,68 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+type SQLiteTokenRevocationStore struct {
+	DB *sql.DB
+}
+
+func NewSQLiteTokenRevocationStore(db *sql.DB) *SQLiteTokenRevocationStore {
+	return &SQLiteTokenRevocationStore{DB: db}
+}
+
+func (s *SQLiteTokenRevocationStore) RevokeJTI(ctx context.Context, jti string, expiresAt time.Time) error {
+	const q = `
+		INSERT INTO revoked_tokens (jti, expires_at)
+		VALUES (?, ?)
+		ON CONFLICT (jti) DO UPDATE SET expires_at = MAX(expires_at, excluded.expires_at)
+	`
+	_, err := s.DB.ExecContext(ctx, q, jti, expiresAt.UTC())
+	return err
+}
+
+func (s *SQLiteTokenRevocationStore) RevokeUser(ctx context.Context, userID int64, issuedBefore, expiresAt time.Time) error {
+	// si ya había un corte, nos quedamos con el más reciente
+	const q = `
+		INSERT INTO revoked_user_tokens (user_id, revoked_before, expires_at)
+		VALUES (?, ?, ?)
+		ON CONFLICT (user_id) DO UPDATE SET
+			revoked_before = MAX(revoked_before, excluded.revoked_before),
+			expires_at = MAX(expires_at, excluded.expires_at)
+	`
+	_, err := s.DB.ExecContext(ctx, q, userID, issuedBefore.UTC().Truncate(time.Second), expiresAt.UTC())
+	return err
+}
+
+func (s *SQLiteTokenRevocationStore) IsRevoked(ctx context.Context, jti string, userID int64, issuedAt time.Time) (bool, error) {
+	const q = `
+		SELECT
+			EXISTS (SELECT 1 FROM revoked_tokens WHERE jti = ?)
+			OR EXISTS (SELECT 1 FROM revoked_user_tokens WHERE user_id = ? AND revoked_before >= ?)
+	`
+	var revoked bool
+	err := s.DB.QueryRowContext(ctx, q, jti, userID, issuedAt.UTC().Truncate(time.Second)).Scan(&revoked)
+	return revoked, err
+}
+
+func (s *SQLiteTokenRevocationStore) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	var total int64
+	for _, q := range []string{
+		`DELETE FROM revoked_tokens WHERE expires_at <= ?`,
+		`DELETE FROM revoked_user_tokens WHERE expires_at <= ?`,
+	} {
+		res, err := s.DB.ExecContext(ctx, q, now.UTC())
+		if err != nil {
+			return total, err
+		}
+		n, err := res.RowsAffected()
+		if err != nil {
+			return total, err
+		}
+		total += n
+	}
+	return total, nil
+}
@@ -0,0 +1,161 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/moondolphin/crypto-api/domain"
+)
+
+type SQLiteTransactionRepository struct {
+	DB *sql.DB
+}
+
+func NewSQLiteTransactionRepository(db *sql.DB) *SQLiteTransactionRepository {
+	return &SQLiteTransactionRepository{DB: db}
+}
+
+const transactionSelect = `
+	SELECT t.id, t.portfolio_id, t.coin_id, c.symbol, t.type, t.quantity, t.price, t.fee,
+		t.currency, t.executed_at, t.note, t.created_at
+	FROM portfolio_transactions t
+	JOIN coins c ON c.id = t.coin_id`
+
+func scanTransaction(s rowScanner) (domain.Transaction, error) {
+	var (
+		t     domain.Transaction
+		price sql.NullString
+	)
+	err := s.Scan(&t.ID, &t.PortfolioID, &t.CoinID, &t.Symbol, &t.Type, &t.Quantity, &price, &t.Fee,
+		&t.Currency, &t.ExecutedAt, &t.Note, &t.CreatedAt)
+	t.Price = price.String
+	return t, err
+}
+
+// transactionPrice guarda NULL el precio de un transfer_in cargado sin precio.
+func transactionPrice(t domain.Transaction) sql.NullString {
+	return sql.NullString{String: t.Price, Valid: t.Price != ""}
+}
+
+// transactionQuerier es *sql.DB o *sql.Tx: CreateChecked reusa el alta y el
+// listado dentro de su transacción.
+type transactionQuerier interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+func createTransaction(ctx context.Context, db transactionQuerier, t domain.Transaction) (domain.Transaction, error) {
+	const q = `
+		INSERT INTO portfolio_transactions
+			(portfolio_id, coin_id, type, quantity, price, fee, currency, executed_at, note, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+	res, err := db.ExecContext(ctx, q, t.PortfolioID, t.CoinID, t.Type, t.Quantity, transactionPrice(t), t.Fee,
+		t.Currency, t.ExecutedAt.UTC(), t.Note, t.CreatedAt.UTC())
+	if err != nil {
+		return domain.Transaction{}, err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return domain.Transaction{}, err
+	}
+
+	t.ID = id
+	return t, nil
+}
+
+func listTransactions(ctx context.Context, db transactionQuerier, portfolioID int64, f domain.TransactionFilter) ([]domain.Transaction, error) {
+	q := transactionSelect + ` WHERE t.portfolio_id = ?`
+	args := []any{portfolioID}
+
+	if f.CoinID > 0 {
+		q += ` AND t.coin_id = ?`
+		args = append(args, f.CoinID)
+	}
+	if f.From != nil {
+		q += ` AND t.executed_at >= ?`
+		args = append(args, f.From.UTC())
+	}
+	if f.To != nil {
+		q += ` AND t.executed_at < ?`
+		args = append(args, f.To.UTC())
+	}
+	q += ` ORDER BY t.executed_at ASC, t.id ASC`
+
+	rows, err := db.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []domain.Transaction
+	for rows.Next() {
+		t, err := scanTransaction(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, t)
+	}
+	return out, rows.Err()
+}
+
+func (r *SQLiteTransactionRepository) Create(ctx context.Context, t domain.Transaction) (domain.Transaction, error) {
+	return createTransaction(ctx, r.DB, t)
+}
+
+func (r *SQLiteTransactionRepository) FindByPortfolio(ctx context.Context, portfolioID, id int64) (*domain.Transaction, error) {
+	t, err := scanTransaction(r.DB.QueryRowContext(ctx, transactionSelect+` WHERE t.id = ? AND t.portfolio_id = ? LIMIT 1`, id, portfolioID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (r *SQLiteTransactionRepository) ListByPortfolio(ctx context.Context, portfolioID int64, f domain.TransactionFilter) ([]domain.Transaction, error) {
+	return listTransactions(ctx, r.DB, portfolioID, f)
+}
+
+func (r *SQLiteTransactionRepository) CreateChecked(ctx context.Context, t domain.Transaction, check func(existing []domain.Transaction) error) (domain.Transaction, error) {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return domain.Transaction{}, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	// SQLite tiene una sola conexión abierta: mientras dure la transacción no
+	// escribe nadie más, no hace falta bloquear el portfolio
+	existing, err := listTransactions(ctx, tx, t.PortfolioID, domain.TransactionFilter{CoinID: t.CoinID})
+	if err != nil {
+		return domain.Transaction{}, err
+	}
+	if err := check(existing); err != nil {
+		return domain.Transaction{}, err
+	}
+
+	created, err := createTransaction(ctx, tx, t)
+	if err != nil {
+		return domain.Transaction{}, err
+	}
+	if err := tx.Commit(); err != nil {
+		return domain.Transaction{}, err
+	}
+	return created, nil
+}
+
+func (r *SQLiteTransactionRepository) Delete(ctx context.Context, portfolioID, id int64) (bool, error) {
+	res, err := r.DB.ExecContext(ctx, `DELETE FROM portfolio_transactions WHERE id = ? AND portfolio_id = ?`, id, portfolioID)
+	if err != nil {
+		return false, err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
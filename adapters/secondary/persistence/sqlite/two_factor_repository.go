@@ -0,0 +1,133 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/moondolphin/crypto-api/domain"
+)
+
+type SQLiteTOTPRepository struct {
+	DB *sql.DB
+}
+
+func NewSQLiteTOTPRepository(db *sql.DB) *SQLiteTOTPRepository {
+	return &SQLiteTOTPRepository{DB: db}
+}
+
+func (r *SQLiteTOTPRepository) Get(ctx context.Context, userID int64) (*domain.TOTPEnrollment, error) {
+	const q = `
+		SELECT user_id, secret, created_at, confirmed_at, last_used_step
+		FROM user_totp
+		WHERE user_id = ?
+	`
+	var (
+		e           domain.TOTPEnrollment
+		confirmedAt sql.NullTime
+	)
+	err := r.DB.QueryRowContext(ctx, q, userID).
+		Scan(&e.UserID, &e.Secret, &e.CreatedAt, &confirmedAt, &e.LastUsedStep)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	e.ConfirmedAt = timePtr(confirmedAt)
+	return &e, nil
+}
+
+func (r *SQLiteTOTPRepository) Save(ctx context.Context, e domain.TOTPEnrollment) error {
+	const q = `
+		INSERT INTO user_totp (user_id, secret, created_at, confirmed_at, last_used_step)
+		VALUES (?, ?, ?, NULL, 0)
+		ON CONFLICT (user_id) DO UPDATE SET
+			secret = excluded.secret,
+			created_at = excluded.created_at,
+			confirmed_at = NULL,
+			last_used_step = 0
+	`
+	_, err := r.DB.ExecContext(ctx, q, e.UserID, e.Secret, e.CreatedAt.UTC())
+	return err
+}
+
+func (r *SQLiteTOTPRepository) Confirm(ctx context.Context, userID int64, at time.Time) error {
+	const q = `UPDATE user_totp SET confirmed_at = ? WHERE user_id = ?`
+	_, err := r.DB.ExecContext(ctx, q, at.UTC(), userID)
+	return err
+}
+
+func (r *SQLiteTOTPRepository) MarkStepUsed(ctx context.Context, userID int64, step int64) (bool, error) {
+	// la condición sobre last_used_step hace atómico el chequeo de replay
+	const q = `UPDATE user_totp SET last_used_step = ? WHERE user_id = ? AND last_used_step < ?`
+	res, err := r.DB.ExecContext(ctx, q, step, userID, step)
+	if err != nil {
+		return false, err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
+func (r *SQLiteTOTPRepository) Delete(ctx context.Context, userID int64) error {
+	const q = `DELETE FROM user_totp WHERE user_id = ?`
+	_, err := r.DB.ExecContext(ctx, q, userID)
+	return err
+}
+
+type SQLiteRecoveryCodeRepository struct {
+	DB *sql.DB
+}
+
+func NewSQLiteRecoveryCodeRepository(db *sql.DB) *SQLiteRecoveryCodeRepository {
+	return &SQLiteRecoveryCodeRepository{DB: db}
+}
+
+func (r *SQLiteRecoveryCodeRepository) Replace(ctx context.Context, userID int64, hashes []string, at time.Time) error {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM totp_recovery_codes WHERE user_id = ?`, userID); err != nil {
+		return err
+	}
+
+	const ins = `INSERT INTO totp_recovery_codes (user_id, code_hash, created_at) VALUES (?, ?, ?)`
+	for _, h := range hashes {
+		if _, err := tx.ExecContext(ctx, ins, userID, h, at.UTC()); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (r *SQLiteRecoveryCodeRepository) Use(ctx context.Context, userID int64, hash string, at time.Time) (bool, error) {
+	const q = `
+		UPDATE totp_recovery_codes SET used_at = ?
+		WHERE user_id = ? AND code_hash = ? AND used_at IS NULL
+	`
+	res, err := r.DB.ExecContext(ctx, q, at.UTC(), userID, hash)
+	if err != nil {
+		return false, err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
+func (r *SQLiteRecoveryCodeRepository) CountUnused(ctx context.Context, userID int64) (int, error) {
+	const q = `SELECT COUNT(*) FROM totp_recovery_codes WHERE user_id = ? AND used_at IS NULL`
+	var n int
+	err := r.DB.QueryRowContext(ctx, q, userID).Scan(&n)
+	return n, err
+}
@@ -0,0 +1,112 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/moondolphin/crypto-api/domain"
+)
+
+type SQLiteUserRepository struct {
+	DB *sql.DB
+}
+
+func NewSQLiteUserRepository(db *sql.DB) *SQLiteUserRepository {
+	return &SQLiteUserRepository{DB: db}
+}
+
+func (r *SQLiteUserRepository) ExistsByEmail(ctx context.Context, email string) (bool, error) {
+	const q = `SELECT 1 FROM users WHERE email = ? LIMIT 1`
+	var one int
+	err := r.DB.QueryRowContext(ctx, q, email).Scan(&one)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (r *SQLiteUserRepository) Create(ctx context.Context, u domain.User) (domain.User, error) {
+	if u.Role == "" {
+		u.Role = domain.RoleUser
+	}
+
+	const q = `
+		INSERT INTO users (email, name, password_hash, role, created_at, email_verified_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`
+	res, err := r.DB.ExecContext(ctx, q, u.Email, u.Name, u.PasswordHash, u.Role, u.CreatedAt.UTC(), nullTime(u.EmailVerifiedAt))
+	if err != nil {
+		return domain.User{}, err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return domain.User{}, err
+	}
+
+	u.ID = id
+	return u, nil
+}
+
+func (r *SQLiteUserRepository) FindByEmail(ctx context.Context, email string) (*domain.User, error) {
+	const q = `SELECT ` + userColumns + ` FROM users WHERE email = ? LIMIT 1`
+	return scanUser(r.DB.QueryRowContext(ctx, q, email))
+}
+
+func (r *SQLiteUserRepository) FindByID(ctx context.Context, id int64) (*domain.User, error) {
+	const q = `SELECT ` + userColumns + ` FROM users WHERE id = ? LIMIT 1`
+	return scanUser(r.DB.QueryRowContext(ctx, q, id))
+}
+
+func (r *SQLiteUserRepository) UpdateRole(ctx context.Context, userID int64, role string) error {
+	const q = `UPDATE users SET role = ? WHERE id = ?`
+	_, err := r.DB.ExecContext(ctx, q, role, userID)
+	return err
+}
+
+func (r *SQLiteUserRepository) UpdatePassword(ctx context.Context, userID int64, passwordHash string) error {
+	const q = `UPDATE users SET password_hash = ? WHERE id = ?`
+	_, err := r.DB.ExecContext(ctx, q, passwordHash, userID)
+	return err
+}
+
+func (r *SQLiteUserRepository) MarkEmailVerified(ctx context.Context, userID int64, at time.Time) error {
+	// no pisa la fecha original si ya estaba verificado
+	const q = `UPDATE users SET email_verified_at = ? WHERE id = ? AND email_verified_at IS NULL`
+	_, err := r.DB.ExecContext(ctx, q, at.UTC(), userID)
+	return err
+}
+
+const userColumns = `id, email, name, password_hash, role, created_at, email_verified_at`
+
+func (r *SQLiteUserRepository) UpdateProfile(ctx context.Context, u domain.User) error {
+	const q = `UPDATE users SET name = ?, email = ?, email_verified_at = ? WHERE id = ?`
+	_, err := r.DB.ExecContext(ctx, q, u.Name, u.Email, nullTime(u.EmailVerifiedAt), u.ID)
+	return err
+}
+
+func (r *SQLiteUserRepository) Delete(ctx context.Context, userID int64) error {
+	const q = `DELETE FROM users WHERE id = ?`
+	_, err := r.DB.ExecContext(ctx, q, userID)
+	return err
+}
+
+func scanUser(row *sql.Row) (*domain.User, error) {
+	var (
+		u          domain.User
+		verifiedAt sql.NullTime
+	)
+	err := row.Scan(&u.ID, &u.Email, &u.Name, &u.PasswordHash, &u.Role, &u.CreatedAt, &verifiedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	u.EmailVerifiedAt = timePtr(verifiedAt)
+	return &u, nil
+}
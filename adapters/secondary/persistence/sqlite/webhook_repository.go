@@ -0,0 +1,286 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/moondolphin/crypto-api/domain"
+)
+
+type SQLiteWebhookSubscriptionRepository struct {
+	DB *sql.DB
+}
+
+func NewSQLiteWebhookSubscriptionRepository(db *sql.DB) *SQLiteWebhookSubscriptionRepository {
+	return &SQLiteWebhookSubscriptionRepository{DB: db}
+}
+
+const webhookSubscriptionColumns = `id, user_id, url, events, secret, created_at`
+
+func scanWebhookSubscription(s rowScanner) (domain.WebhookSubscription, error) {
+	var (
+		w      domain.WebhookSubscription
+		events string
+	)
+	if err := s.Scan(&w.ID, &w.UserID, &w.URL, &events, &w.Secret, &w.CreatedAt); err != nil {
+		return domain.WebhookSubscription{}, err
+	}
+	w.Events = splitScopes(events)
+	return w, nil
+}
+
+func (r *SQLiteWebhookSubscriptionRepository) list(ctx context.Context, q string, args ...any) ([]domain.WebhookSubscription, error) {
+	rows, err := r.DB.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []domain.WebhookSubscription
+	for rows.Next() {
+		w, err := scanWebhookSubscription(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, w)
+	}
+	return out, rows.Err()
+}
+
+func (r *SQLiteWebhookSubscriptionRepository) Create(ctx context.Context, s domain.WebhookSubscription) (domain.WebhookSubscription, error) {
+	const q = `INSERT INTO webhook_subscriptions (user_id, url, events, secret, created_at) VALUES (?, ?, ?, ?, ?)`
+
+	res, err := r.DB.ExecContext(ctx, q, s.UserID, s.URL, strings.Join(s.Events, ","), s.Secret, s.CreatedAt.UTC())
+	if err != nil {
+		return domain.WebhookSubscription{}, err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return domain.WebhookSubscription{}, err
+	}
+
+	s.ID = id
+	return s, nil
+}
+
+func (r *SQLiteWebhookSubscriptionRepository) FindByUser(ctx context.Context, userID, id int64) (*domain.WebhookSubscription, error) {
+	q := `SELECT ` + webhookSubscriptionColumns + ` FROM webhook_subscriptions WHERE id = ? AND user_id = ? LIMIT 1`
+
+	w, err := scanWebhookSubscription(r.DB.QueryRowContext(ctx, q, id, userID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &w, nil
+}
+
+func (r *SQLiteWebhookSubscriptionRepository) FindByID(ctx context.Context, id int64) (*domain.WebhookSubscription, error) {
+	q := `SELECT ` + webhookSubscriptionColumns + ` FROM webhook_subscriptions WHERE id = ? LIMIT 1`
+
+	w, err := scanWebhookSubscription(r.DB.QueryRowContext(ctx, q, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &w, nil
+}
+
+func (r *SQLiteWebhookSubscriptionRepository) ListByUser(ctx context.Context, userID int64) ([]domain.WebhookSubscription, error) {
+	return r.list(ctx, `SELECT `+webhookSubscriptionColumns+` FROM webhook_subscriptions WHERE user_id = ? ORDER BY id ASC`, userID)
+}
+
+func (r *SQLiteWebhookSubscriptionRepository) CountByUser(ctx context.Context, userID int64) (int, error) {
+	var n int
+	err := r.DB.QueryRowContext(ctx, `SELECT COUNT(*) FROM webhook_subscriptions WHERE user_id = ?`, userID).Scan(&n)
+	return n, err
+}
+
+func (r *SQLiteWebhookSubscriptionRepository) ListByEvent(ctx context.Context, event string, userID int64) ([]domain.WebhookSubscription, error) {
+	// events es una lista separada por comas: se busca ",event," para no matchear prefijos
+	q := `SELECT ` + webhookSubscriptionColumns + ` FROM webhook_subscriptions WHERE (',' || events || ',') LIKE ?`
+	args := []any{"%," + event + ",%"}
+	if userID > 0 {
+		q += ` AND user_id = ?`
+		args = append(args, userID)
+	}
+	q += ` ORDER BY id ASC`
+	return r.list(ctx, q, args...)
+}
+
+func (r *SQLiteWebhookSubscriptionRepository) Delete(ctx context.Context, userID, id int64) (bool, error) {
+	res, err := r.DB.ExecContext(ctx, `DELETE FROM webhook_subscriptions WHERE id = ? AND user_id = ?`, id, userID)
+	if err != nil {
+		return false, err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
+type SQLiteWebhookDeliveryRepository struct {
+	DB *sql.DB
+}
+
+func NewSQLiteWebhookDeliveryRepository(db *sql.DB) *SQLiteWebhookDeliveryRepository {
+	return &SQLiteWebhookDeliveryRepository{DB: db}
+}
+
+const webhookDeliveryColumns = `id, subscription_id, event_id, event_type, payload, status, attempts, next_attempt_at,
+		last_attempt_at, last_status_code, last_error, delivered_at, created_at`
+
+func scanWebhookDelivery(s rowScanner) (domain.WebhookDelivery, error) {
+	var (
+		d             domain.WebhookDelivery
+		lastAttemptAt sql.NullTime
+		deliveredAt   sql.NullTime
+	)
+	err := s.Scan(&d.ID, &d.SubscriptionID, &d.EventID, &d.EventType, &d.Payload, &d.Status, &d.Attempts, &d.NextAttemptAt,
+		&lastAttemptAt, &d.LastStatusCode, &d.LastError, &deliveredAt, &d.CreatedAt)
+	if err != nil {
+		return domain.WebhookDelivery{}, err
+	}
+
+	d.LastAttemptAt = timePtr(lastAttemptAt)
+	d.DeliveredAt = timePtr(deliveredAt)
+	return d, nil
+}
+
+func (r *SQLiteWebhookDeliveryRepository) list(ctx context.Context, q string, args ...any) ([]domain.WebhookDelivery, error) {
+	rows, err := r.DB.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []domain.WebhookDelivery
+	for rows.Next() {
+		d, err := scanWebhookDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, d)
+	}
+	return out, rows.Err()
+}
+
+func (r *SQLiteWebhookDeliveryRepository) Enqueue(ctx context.Context, d domain.WebhookDelivery) (domain.WebhookDelivery, error) {
+	const q = `
+		INSERT INTO webhook_deliveries (subscription_id, event_id, event_type, payload, status, attempts, next_attempt_at,
+			last_status_code, last_error, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+	res, err := r.DB.ExecContext(ctx, q, d.SubscriptionID, d.EventID, d.EventType, d.Payload, d.Status, d.Attempts,
+		d.NextAttemptAt.UTC(), d.LastStatusCode, d.LastError, d.CreatedAt.UTC())
+	if err != nil {
+		return domain.WebhookDelivery{}, err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return domain.WebhookDelivery{}, err
+	}
+
+	d.ID = id
+	d.LastAttemptAt = nil
+	d.DeliveredAt = nil
+	return d, nil
+}
+
+func (r *SQLiteWebhookDeliveryRepository) ListDue(ctx context.Context, now time.Time, limit int) ([]domain.WebhookDelivery, error) {
+	q := `SELECT ` + webhookDeliveryColumns + ` FROM webhook_deliveries
+		WHERE status = ? AND next_attempt_at <= ?
+		ORDER BY next_attempt_at ASC, id ASC LIMIT ?`
+	return r.list(ctx, q, domain.WebhookDeliveryPending, now.UTC(), limit)
+}
+
+func (r *SQLiteWebhookDeliveryRepository) Claim(ctx context.Context, id int64, attempts int, leaseUntil time.Time) (bool, error) {
+	const q = `
+		UPDATE webhook_deliveries
+		SET attempts = attempts + 1, next_attempt_at = ?
+		WHERE id = ? AND status = ? AND attempts = ?
+	`
+	res, err := r.DB.ExecContext(ctx, q, leaseUntil.UTC(), id, domain.WebhookDeliveryPending, attempts)
+	if err != nil {
+		return false, err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
+func (r *SQLiteWebhookDeliveryRepository) RecordAttempt(ctx context.Context, d domain.WebhookDelivery) error {
+	const q = `
+		UPDATE webhook_deliveries
+		SET status = ?, next_attempt_at = ?, last_attempt_at = ?, last_status_code = ?, last_error = ?, delivered_at = ?
+		WHERE id = ?
+	`
+	_, err := r.DB.ExecContext(ctx, q, d.Status, d.NextAttemptAt.UTC(), nullTime(d.LastAttemptAt), d.LastStatusCode,
+		d.LastError, nullTime(d.DeliveredAt), d.ID)
+	return err
+}
+
+func (r *SQLiteWebhookDeliveryRepository) FindBySubscription(ctx context.Context, subscriptionID, id int64) (*domain.WebhookDelivery, error) {
+	q := `SELECT ` + webhookDeliveryColumns + ` FROM webhook_deliveries WHERE id = ? AND subscription_id = ? LIMIT 1`
+
+	d, err := scanWebhookDelivery(r.DB.QueryRowContext(ctx, q, id, subscriptionID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+func (r *SQLiteWebhookDeliveryRepository) ListBySubscription(ctx context.Context, subscriptionID int64, status string, limit int) ([]domain.WebhookDelivery, error) {
+	q := `SELECT ` + webhookDeliveryColumns + ` FROM webhook_deliveries WHERE subscription_id = ?`
+	args := []any{subscriptionID}
+	if status != "" {
+		q += ` AND status = ?`
+		args = append(args, status)
+	}
+	q += ` ORDER BY created_at DESC, id DESC LIMIT ?`
+	args = append(args, limit)
+	return r.list(ctx, q, args...)
+}
+
+func (r *SQLiteWebhookDeliveryRepository) Replay(ctx context.Context, subscriptionID, id int64, now time.Time) (int64, error) {
+	q := `
+		UPDATE webhook_deliveries
+		SET status = ?, attempts = 0, next_attempt_at = ?
+		WHERE subscription_id = ? AND status = ?`
+	args := []any{domain.WebhookDeliveryPending, now.UTC(), subscriptionID, domain.WebhookDeliveryDead}
+	if id > 0 {
+		q += ` AND id = ?`
+		args = append(args, id)
+	}
+
+	res, err := r.DB.ExecContext(ctx, q, args...)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+func (r *SQLiteWebhookDeliveryRepository) DeleteDeliveredBefore(ctx context.Context, before time.Time) (int64, error) {
+	const q = `DELETE FROM webhook_deliveries WHERE status = ? AND delivered_at < ?`
+
+	res, err := r.DB.ExecContext(ctx, q, domain.WebhookDeliveryDelivered, before.UTC())
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
@@ -0,0 +1,203 @@
+package security
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/moondolphin/crypto-api/domain"
+)
+
+var (
+	ErrNoSigningKey = errors.New("jwt: no signing key")
+	ErrUnknownKID   = errors.New("jwt: unknown kid")
+)
+
+// SigningKey es una clave de JWT identificada por kid. Private nil = sólo verificación
+// (clave retirada que sigue validando tokens emitidos antes de la rotación).
+type SigningKey struct {
+	KID     string
+	Method  jwt.SigningMethod
+	Private any // *rsa.PrivateKey | ed25519.PrivateKey | []byte (HS256)
+	Public  any // *rsa.PublicKey | ed25519.PublicKey | []byte (HS256)
+}
+
+// HS256Key arma la clave simétrica a partir del secreto compartido.
+func HS256Key(kid, secret string) SigningKey {
+	b := []byte(secret)
+	return SigningKey{KID: kid, Method: jwt.SigningMethodHS256, Private: b, Public: b}
+}
+
+// KeySet agrupa las claves vigentes: una activa para firmar y todas para verificar.
+// Rotar = agregar la clave nueva, marcarla activa y dejar la anterior como
+// sólo verificación hasta que venzan los tokens que firmó.
+type KeySet struct {
+	active string
+	keys   map[string]SigningKey
+}
+
+func NewKeySet(activeKID string, keys ...SigningKey) (*KeySet, error) {
+	s := &KeySet{active: activeKID, keys: make(map[string]SigningKey, len(keys))}
+	for _, k := range keys {
+		if _, dup := s.keys[k.KID]; dup {
+			return nil, fmt.Errorf("jwt: duplicate kid %q", k.KID)
+		}
+		s.keys[k.KID] = k
+	}
+
+	k, ok := s.keys[activeKID]
+	if !ok || k.Private == nil {
+		return nil, ErrNoSigningKey
+	}
+	return s, nil
+}
+
+// NewHS256KeySet es el modo legacy: un único secreto compartido, sin kid.
+func NewHS256KeySet(secret string) *KeySet {
+	s, _ := NewKeySet("", HS256Key("", secret))
+	return s
+}
+
+// AddVerifyOnly suma una clave que sólo sirve para verificar (p. ej. el JWT_SECRET
+// anterior durante la migración a firma asimétrica). No pisa claves existentes.
+func (s *KeySet) AddVerifyOnly(k SigningKey) {
+	if _, ok := s.keys[k.KID]; ok {
+		return
+	}
+	k.Private = nil
+	s.keys[k.KID] = k
+}
+
+func (s *KeySet) Active() SigningKey {
+	return s.keys[s.active]
+}
+
+// Keyfunc elige la clave por el header kid y exige que el alg del token coincida
+// con el de la clave (evita confusiones tipo RS256 -> HS256).
+func (s *KeySet) Keyfunc(t *jwt.Token) (any, error) {
+	kid, _ := t.Header["kid"].(string)
+
+	k, ok := s.keys[kid]
+	if !ok {
+		return nil, ErrUnknownKID
+	}
+	if t.Method == nil || t.Method.Alg() != k.Method.Alg() {
+		return nil, jwt.ErrTokenSignatureInvalid
+	}
+	return k.Public, nil
+}
+
+// PublicJWKs devuelve las claves asimétricas (activas y retiradas) ordenadas por kid.
+func (s *KeySet) PublicJWKs() []domain.PublicJWK {
+	out := make([]domain.PublicJWK, 0, len(s.keys))
+	for _, k := range s.keys {
+		switch pub := k.Public.(type) {
+		case *rsa.PublicKey:
+			out = append(out, domain.PublicJWK{
+				Kty: "RSA",
+				Kid: k.KID,
+				Use: "sig",
+				Alg: k.Method.Alg(),
+				N:   b64(pub.N.Bytes()),
+				E:   b64(big.NewInt(int64(pub.E)).Bytes()),
+			})
+		case ed25519.PublicKey:
+			out = append(out, domain.PublicJWK{
+				Kty: "OKP",
+				Kid: k.KID,
+				Use: "sig",
+				Alg: k.Method.Alg(),
+				Crv: "Ed25519",
+				X:   b64(pub),
+			})
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Kid < out[j].Kid })
+	return out
+}
+
+func b64(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// LoadKeySet lee las claves PEM de dir. El kid es el nombre del archivo:
+//
+//	<kid>.pem      clave privada RSA (RS256) o Ed25519 (EdDSA): firma y verifica
+//	<kid>.pub.pem  clave pública: sólo verifica (clave retirada)
+//
+// Si activeKID está vacío se firma con la clave privada de kid mayor (p. ej. "2026-02" > "2026-01").
+func LoadKeySet(dir, activeKID string) (*KeySet, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		keys   []SigningKey
+		newest string
+	)
+	for _, p := range paths {
+		body, err := os.ReadFile(p)
+		if err != nil {
+			return nil, err
+		}
+
+		name := filepath.Base(p)
+		var k SigningKey
+		if strings.HasSuffix(name, ".pub.pem") {
+			k, err = parsePublicPEM(strings.TrimSuffix(name, ".pub.pem"), body)
+		} else {
+			k, err = parsePrivatePEM(strings.TrimSuffix(name, ".pem"), body)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("jwt key %s: %w", name, err)
+		}
+		keys = append(keys, k)
+
+		if k.Private != nil && k.KID > newest {
+			newest = k.KID
+		}
+	}
+
+	if activeKID == "" {
+		activeKID = newest
+	}
+	return NewKeySet(activeKID, keys...)
+}
+
+func parsePrivatePEM(kid string, body []byte) (SigningKey, error) {
+	if rk, err := jwt.ParseRSAPrivateKeyFromPEM(body); err == nil {
+		return SigningKey{KID: kid, Method: jwt.SigningMethodRS256, Private: rk, Public: &rk.PublicKey}, nil
+	}
+	if ek, err := jwt.ParseEdPrivateKeyFromPEM(body); err == nil {
+		priv, ok := ek.(ed25519.PrivateKey)
+		if !ok {
+			return SigningKey{}, errors.New("unsupported private key")
+		}
+		return SigningKey{KID: kid, Method: jwt.SigningMethodEdDSA, Private: priv, Public: priv.Public()}, nil
+	}
+	return SigningKey{}, errors.New("unsupported private key (want RSA or Ed25519 PEM)")
+}
+
+func parsePublicPEM(kid string, body []byte) (SigningKey, error) {
+	if rk, err := jwt.ParseRSAPublicKeyFromPEM(body); err == nil {
+		return SigningKey{KID: kid, Method: jwt.SigningMethodRS256, Public: rk}, nil
+	}
+	if ek, err := jwt.ParseEdPublicKeyFromPEM(body); err == nil {
+		pub, ok := ek.(ed25519.PublicKey)
+		if !ok {
+			return SigningKey{}, errors.New("unsupported public key")
+		}
+		return SigningKey{KID: kid, Method: jwt.SigningMethodEdDSA, Public: pub}, nil
+	}
+	return SigningKey{}, errors.New("unsupported public key (want RSA or Ed25519 PEM)")
+}
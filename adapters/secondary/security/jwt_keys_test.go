@@ -0,0 +1,162 @@
+package security
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/require"
+)
+
+func writePEM(t *testing.T, dir, name, typ string, der []byte) {
+	t.Helper()
+	b := pem.EncodeToMemory(&pem.Block{Type: typ, Bytes: der})
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name), b, 0o600))
+}
+
+func writeRSAKey(t *testing.T, dir, kid string) *rsa.PrivateKey {
+	t.Helper()
+	k, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(k)
+	require.NoError(t, err)
+	writePEM(t, dir, kid+".pem", "PRIVATE KEY", der)
+	return k
+}
+
+func writeEdKey(t *testing.T, dir, kid string) ed25519.PrivateKey {
+	t.Helper()
+	_, k, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(k)
+	require.NoError(t, err)
+	writePEM(t, dir, kid+".pem", "PRIVATE KEY", der)
+	return k
+}
+
+func parse(t *testing.T, keys *KeySet, tok string) (*jwt.Token, error) {
+	t.Helper()
+	return jwt.Parse(tok, keys.Keyfunc)
+}
+
+func TestLoadKeySet_SignsWithHighestKIDAndVerifiesAll(t *testing.T) {
+	dir := t.TempDir()
+	writeRSAKey(t, dir, "2026-01")
+	writeEdKey(t, dir, "2026-02")
+
+	keys, err := LoadKeySet(dir, "")
+	require.NoError(t, err)
+	require.Equal(t, "2026-02", keys.Active().KID)
+
+	svc := NewJWTServiceWithKeys(keys, time.Hour, "test")
+	tok, err := svc.Generate(7, "a@b.com", "user")
+	require.NoError(t, err)
+
+	parsed, err := parse(t, keys, tok)
+	require.NoError(t, err)
+	require.Equal(t, "2026-02", parsed.Header["kid"])
+	require.Equal(t, "EdDSA", parsed.Method.Alg())
+
+	// forzando la clave anterior también verifica con el mismo set
+	old, err := LoadKeySet(dir, "2026-01")
+	require.NoError(t, err)
+	oldTok, err := NewJWTServiceWithKeys(old, time.Hour, "test").Generate(7, "a@b.com", "user")
+	require.NoError(t, err)
+
+	parsed, err = parse(t, keys, oldTok)
+	require.NoError(t, err)
+	require.Equal(t, "RS256", parsed.Method.Alg())
+}
+
+func TestLoadKeySet_RetiredPublicKeyOnlyVerifies(t *testing.T) {
+	dir := t.TempDir()
+	k := writeRSAKey(t, dir, "2026-01")
+
+	oldKeys, err := LoadKeySet(dir, "")
+	require.NoError(t, err)
+	oldTok, err := NewJWTServiceWithKeys(oldKeys, time.Hour, "test").Generate(7, "a@b.com", "user")
+	require.NoError(t, err)
+
+	// rotación: la privada vieja se reemplaza por su pública y entra una nueva
+	require.NoError(t, os.Remove(filepath.Join(dir, "2026-01.pem")))
+	der, err := x509.MarshalPKIXPublicKey(&k.PublicKey)
+	require.NoError(t, err)
+	writePEM(t, dir, "2026-01.pub.pem", "PUBLIC KEY", der)
+	writeRSAKey(t, dir, "2026-02")
+
+	keys, err := LoadKeySet(dir, "")
+	require.NoError(t, err)
+	require.Equal(t, "2026-02", keys.Active().KID)
+
+	_, err = parse(t, keys, oldTok)
+	require.NoError(t, err)
+
+	_, err = LoadKeySet(dir, "2026-01")
+	require.ErrorIs(t, err, ErrNoSigningKey)
+}
+
+func TestKeySet_RejectsUnknownKIDAndAlgMismatch(t *testing.T) {
+	dir := t.TempDir()
+	k := writeRSAKey(t, dir, "k1")
+
+	keys, err := LoadKeySet(dir, "")
+	require.NoError(t, err)
+	keys.AddVerifyOnly(HS256Key("", "legacy"))
+
+	// kid desconocido
+	tok := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{"sub": 1})
+	tok.Header["kid"] = "nope"
+	s, err := tok.SignedString(k)
+	require.NoError(t, err)
+	_, err = parse(t, keys, s)
+	require.Error(t, err)
+
+	// HS256 firmado con la clave pública RSA como secreto, bajo el kid RSA
+	pubDER, err := x509.MarshalPKIXPublicKey(&k.PublicKey)
+	require.NoError(t, err)
+	tok = jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"sub": 1})
+	tok.Header["kid"] = "k1"
+	s, err = tok.SignedString(pubDER)
+	require.NoError(t, err)
+	_, err = parse(t, keys, s)
+	require.Error(t, err)
+
+	// el secreto legacy sigue validando tokens sin kid
+	legacy, err := NewJWTService("legacy", time.Hour, "test").Generate(1, "a@b.com", "user")
+	require.NoError(t, err)
+	_, err = parse(t, keys, legacy)
+	require.NoError(t, err)
+}
+
+func TestKeySet_PublicJWKsOmitsSymmetricKeys(t *testing.T) {
+	dir := t.TempDir()
+	writeRSAKey(t, dir, "a")
+	writeEdKey(t, dir, "b")
+
+	keys, err := LoadKeySet(dir, "")
+	require.NoError(t, err)
+	keys.AddVerifyOnly(HS256Key("", "legacy"))
+
+	jwks := keys.PublicJWKs()
+	require.Len(t, jwks, 2)
+
+	require.Equal(t, "a", jwks[0].Kid)
+	require.Equal(t, "RSA", jwks[0].Kty)
+	require.Equal(t, "RS256", jwks[0].Alg)
+	require.Equal(t, "AQAB", jwks[0].E)
+	require.NotEmpty(t, jwks[0].N)
+
+	require.Equal(t, "b", jwks[1].Kid)
+	require.Equal(t, "OKP", jwks[1].Kty)
+	require.Equal(t, "Ed25519", jwks[1].Crv)
+	require.NotEmpty(t, jwks[1].X)
+
+	require.Empty(t, NewHS256KeySet("s").PublicJWKs())
+}
@@ -1,40 +1,69 @@
 package security
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 )
 
 type JWTService struct {
-	Secret []byte
+	Keys   *KeySet
 	TTL    time.Duration
 	Issuer string
 	Now    func() time.Time
 }
 
+// NewJWTService firma con HS256 y un secreto compartido (modo legacy, sin kid).
 func NewJWTService(secret string, ttl time.Duration, issuer string) JWTService {
+	return NewJWTServiceWithKeys(NewHS256KeySet(secret), ttl, issuer)
+}
+
+// NewJWTServiceWithKeys firma con la clave activa de keys (RS256, EdDSA o HS256).
+func NewJWTServiceWithKeys(keys *KeySet, ttl time.Duration, issuer string) JWTService {
 	return JWTService{
-		Secret: []byte(secret),
+		Keys:   keys,
 		TTL:    ttl,
 		Issuer: issuer,
 	}
 }
 
-func (s JWTService) Generate(userID int64, email string) (string, error) {
+func (s JWTService) Generate(userID int64, email, role string) (string, error) {
 	now := s.Now
 	if now == nil {
 		now = time.Now
 	}
 
+	// jti identifica al token para poder revocarlo antes de exp
+	jti, err := newJTI()
+	if err != nil {
+		return "", err
+	}
+
 	claims := jwt.MapClaims{
+		"jti":   jti,
 		"sub":   userID,
 		"email": email,
+		"role":  role,
 		"iss":   s.Issuer,
 		"iat":   now().Unix(),
 		"exp":   now().Add(s.TTL).Unix(),
 	}
 
-	t := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return t.SignedString(s.Secret)
+	key := s.Keys.Active()
+
+	t := jwt.NewWithClaims(key.Method, claims)
+	if key.KID != "" {
+		t.Header["kid"] = key.KID
+	}
+	return t.SignedString(key.Private)
+}
+
+func newJTI() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
 }
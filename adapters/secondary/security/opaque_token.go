@@ -0,0 +1,36 @@
+package security
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+)
+
+// OpaqueTokenService genera tokens aleatorios url-safe y los hashea con SHA-256:
+// al ser secretos de alta entropía no hace falta un hash lento como bcrypt.
+type OpaqueTokenService struct {
+	Bytes int
+}
+
+func NewOpaqueTokenService() OpaqueTokenService {
+	return OpaqueTokenService{Bytes: 32}
+}
+
+func (s OpaqueTokenService) Generate() (string, error) {
+	n := s.Bytes
+	if n <= 0 {
+		n = 32
+	}
+
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func (s OpaqueTokenService) Hash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,86 @@
+package security
+
+import (
+	"encoding/base32"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// vectores SHA1 del apéndice B de RFC 6238 (últimos 6 de los 8 dígitos)
+func TestTOTPService_Validate_RFC6238Vectors(t *testing.T) {
+	s := NewTOTPService()
+	s.Skew = 0
+	secret := base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte("12345678901234567890"))
+
+	cases := []struct {
+		unix int64
+		code string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+	}
+	for _, tc := range cases {
+		step, ok := s.Validate(secret, tc.code, time.Unix(tc.unix, 0))
+		require.True(t, ok, tc.unix)
+		require.Equal(t, tc.unix/30, step)
+	}
+}
+
+func TestTOTPService_Validate_SkewAndRejects(t *testing.T) {
+	s := NewTOTPService()
+	secret := base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte("12345678901234567890"))
+
+	// el código de t=59 (paso 1) sigue valiendo un paso después, no dos
+	_, ok := s.Validate(secret, "287082", time.Unix(89, 0))
+	require.True(t, ok)
+	_, ok = s.Validate(secret, "287082", time.Unix(120, 0))
+	require.False(t, ok)
+
+	_, ok = s.Validate(secret, "287 082", time.Unix(59, 0))
+	require.True(t, ok)
+	_, ok = s.Validate(secret, "28708", time.Unix(59, 0))
+	require.False(t, ok)
+	_, ok = s.Validate("not base32!", "287082", time.Unix(59, 0))
+	require.False(t, ok)
+}
+
+func TestTOTPService_GenerateSecretAndURI(t *testing.T) {
+	s := NewTOTPService()
+
+	secret, err := s.GenerateSecret()
+	require.NoError(t, err)
+	require.Len(t, secret, 32)
+
+	u, err := url.Parse(s.URI("Crypto API", "john@example.com", secret))
+	require.NoError(t, err)
+	require.Equal(t, "otpauth", u.Scheme)
+	require.Equal(t, "totp", u.Host)
+	require.Equal(t, "/Crypto API:john@example.com", u.Path)
+	require.Equal(t, secret, u.Query().Get("secret"))
+	require.Equal(t, "Crypto API", u.Query().Get("issuer"))
+	require.Equal(t, "6", u.Query().Get("digits"))
+	require.Equal(t, "30", u.Query().Get("period"))
+}
+
+func TestTOTPService_GenerateRecoveryCode(t *testing.T) {
+	s := NewTOTPService()
+
+	a, err := s.GenerateRecoveryCode()
+	require.NoError(t, err)
+	b, err := s.GenerateRecoveryCode()
+	require.NoError(t, err)
+
+	require.Len(t, a, 11)
+	require.Equal(t, byte('-'), a[5])
+	require.NotEqual(t, a, b)
+	for _, r := range strings.ReplaceAll(a, "-", "") {
+		require.Contains(t, recoveryAlphabet, string(r))
+	}
+}
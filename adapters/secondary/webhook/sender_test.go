@@ -0,0 +1,83 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPSender_PostsBodyAndHeaders(t *testing.T) {
+	var (
+		gotBody []byte
+		gotSig  string
+		gotType string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPost, r.Method)
+		gotBody, _ = io.ReadAll(r.Body)
+		gotSig = r.Header.Get("X-Webhook-Signature")
+		gotType = r.Header.Get("Content-Type")
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer srv.Close()
+
+	s := NewHTTPSender(2*time.Second, true)
+	code, err := s.Send(context.Background(), srv.URL, map[string]string{
+		"Content-Type":        "application/json",
+		"X-Webhook-Signature": "t=1,v1=abc",
+	}, []byte(`{"id":"evt_1"}`))
+
+	require.NoError(t, err)
+	require.Equal(t, http.StatusAccepted, code)
+	require.Equal(t, `{"id":"evt_1"}`, string(gotBody))
+	require.Equal(t, "t=1,v1=abc", gotSig)
+	require.Equal(t, "application/json", gotType)
+}
+
+func TestHTTPSender_RejectsPrivateAddresses(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Fatal("no debería llegar al receptor")
+	}))
+	defer srv.Close()
+
+	s := NewHTTPSender(2*time.Second, false)
+	code, err := s.Send(context.Background(), srv.URL, nil, []byte(`{}`))
+
+	require.ErrorIs(t, err, ErrPrivateAddress)
+	require.Zero(t, code)
+}
+
+func TestHTTPSender_DoesNotFollowRedirects(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/hook" {
+			http.Redirect(w, r, "/elsewhere", http.StatusFound)
+			return
+		}
+		t.Fatal("siguió el redirect")
+	}))
+	defer srv.Close()
+
+	s := NewHTTPSender(2*time.Second, true)
+	code, err := s.Send(context.Background(), srv.URL+"/hook", nil, []byte(`{}`))
+
+	require.NoError(t, err)
+	require.Equal(t, http.StatusFound, code)
+}
+
+func TestIsPrivateIP(t *testing.T) {
+	private := []string{"127.0.0.1", "10.1.2.3", "172.16.0.1", "192.168.1.1", "169.254.169.254", "100.64.0.1", "0.0.0.0", "::1", "fd00::1", "fe80::1"}
+	public := []string{"8.8.8.8", "1.1.1.1", "2606:4700:4700::1111"}
+
+	for _, s := range private {
+		require.True(t, isPrivateIP(net.ParseIP(s)), s)
+	}
+	for _, s := range public {
+		require.False(t, isPrivateIP(net.ParseIP(s)), s)
+	}
+}
@@ -3,6 +3,7 @@ package app
 import (
 	"context"
 	"errors"
+	"log"
 	"strings"
 	"time"
 
@@ -26,13 +27,20 @@ type UserOutput struct {
 	ID        int64     `json:"id"`
 	Email     string    `json:"email"`
 	Name      string    `json:"name"`
+	Role      string    `json:"role"`
 	CreatedAt time.Time `json:"created_at"`
+
+	EmailVerified bool `json:"email_verified"`
 }
 
 type RegisterUserUseCase struct {
 	UserRepo domain.UserRepository
 	Hasher   domain.PasswordHasher
 	Now      func() time.Time
+
+	// opcional: si está, la cuenta nace sin verificar y se manda el mail de
+	// verificación. Sin Verification las cuentas nacen verificadas.
+	Verification *SendEmailVerificationUseCase
 }
 
 func (uc RegisterUserUseCase) Execute(ctx context.Context, in RegisterInput) (UserOutput, error) {
@@ -72,18 +80,26 @@ func (uc RegisterUserUseCase) Execute(ctx context.Context, in RegisterInput) (Us
 		Email:        email,
 		Name:         name,
 		PasswordHash: hash,
+		Role:         domain.RoleUser,
 		CreatedAt:    now().UTC().Truncate(time.Second),
 	}
 
+	if uc.Verification == nil {
+		verifiedAt := u.CreatedAt
+		u.EmailVerifiedAt = &verifiedAt
+	}
+
 	created, err := uc.UserRepo.Create(ctx, u)
 	if err != nil {
 		return UserOutput{}, err
 	}
 
-	return UserOutput{
-		ID:        created.ID,
-		Email:     created.Email,
-		Name:      created.Name,
-		CreatedAt: created.CreatedAt,
-	}, nil
+	if uc.Verification != nil && !created.IsEmailVerified() {
+		// la cuenta ya existe: si el mail falla se puede pedir el reenvío
+		if err := uc.Verification.Send(ctx, created); err != nil {
+			log.Printf("Warning: failed to send verification email: %v", err)
+		}
+	}
+
+	return toUserOutput(created), nil
 }
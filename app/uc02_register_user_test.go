@@ -221,3 +221,176 @@ func TestUC02RegisterUser_Success_CreatesUserAndReturnsIt(t *testing.T) {
 	require.Equal(t, "John Doe", result.Name)
 	require.Equal(t, fixedTime, result.CreatedAt)
 }
+
+func TestUC02RegisterUser_BootstrapAdminEmail_RegistersUnverifiedUser(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	hasher := mocks.NewMockPasswordHasher(ctrl)
+	tokens := mocks.NewMockEmailVerificationRepository(ctrl)
+	opaque := mocks.NewMockOpaqueTokenService(ctrl)
+	mailer := mocks.NewMockMailer(ctrl)
+
+	userRepo.EXPECT().ExistsByEmail(gomock.Any(), "boss@example.com").Return(false, nil)
+	hasher.EXPECT().Hash("SecurePassword123").Return("hash", nil)
+	userRepo.EXPECT().
+		Create(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, u domain.User) (domain.User, error) {
+			// el admin sale de verificar el email, no de registrarlo
+			require.Equal(t, domain.RoleUser, u.Role)
+			require.Nil(t, u.EmailVerifiedAt)
+			u.ID = 1
+			return u, nil
+		})
+
+	tokens.EXPECT().InvalidateByUser(gomock.Any(), int64(1), gomock.Any()).Return(nil)
+	opaque.EXPECT().Generate().Return("raw", nil)
+	opaque.EXPECT().Hash("raw").Return("h")
+	tokens.EXPECT().Create(gomock.Any(), gomock.Any()).Return(domain.EmailVerificationToken{ID: 1}, nil)
+	mailer.EXPECT().Send(gomock.Any(), gomock.Any()).Return(nil)
+
+	uc := app.RegisterUserUseCase{
+		UserRepo:     userRepo,
+		Hasher:       hasher,
+		Verification: &app.SendEmailVerificationUseCase{Tokens: tokens, Opaque: opaque, Mailer: mailer},
+	}
+
+	// Act
+	result, err := uc.Execute(context.Background(), app.RegisterInput{
+		Email:    "boss@example.com",
+		Password: "SecurePassword123",
+		Name:     "Boss",
+	})
+
+	// Assert
+	require.NoError(t, err)
+	require.Equal(t, domain.RoleUser, result.Role)
+	require.False(t, result.EmailVerified)
+}
+
+func TestUC02RegisterUser_Success_RegularEmailGetsUserRole(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	hasher := mocks.NewMockPasswordHasher(ctrl)
+
+	userRepo.EXPECT().
+		ExistsByEmail(gomock.Any(), "john@example.com").
+		Return(false, nil)
+
+	hasher.EXPECT().
+		Hash("SecurePassword123").
+		Return("hash", nil)
+
+	userRepo.EXPECT().
+		Create(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, u domain.User) (domain.User, error) {
+			u.ID = 2
+			return u, nil
+		})
+
+	uc := app.RegisterUserUseCase{
+		UserRepo: userRepo,
+		Hasher:   hasher,
+	}
+
+	// Act
+	result, err := uc.Execute(context.Background(), app.RegisterInput{
+		Email:    "john@example.com",
+		Password: "SecurePassword123",
+		Name:     "John",
+	})
+
+	// Assert
+	require.NoError(t, err)
+	require.Equal(t, domain.RoleUser, result.Role)
+}
+
+func TestUC02RegisterUser_WithVerification_CreatesUnverifiedAndSendsMail(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	hasher := mocks.NewMockPasswordHasher(ctrl)
+	tokens := mocks.NewMockEmailVerificationRepository(ctrl)
+	opaque := mocks.NewMockOpaqueTokenService(ctrl)
+	mailer := mocks.NewMockMailer(ctrl)
+
+	userRepo.EXPECT().ExistsByEmail(gomock.Any(), "john@example.com").Return(false, nil)
+	hasher.EXPECT().Hash("SecurePassword123").Return("hash", nil)
+	userRepo.EXPECT().
+		Create(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, u domain.User) (domain.User, error) {
+			require.Nil(t, u.EmailVerifiedAt)
+			u.ID = 3
+			return u, nil
+		})
+
+	tokens.EXPECT().InvalidateByUser(gomock.Any(), int64(3), gomock.Any()).Return(nil)
+	opaque.EXPECT().Generate().Return("raw", nil)
+	opaque.EXPECT().Hash("raw").Return("h")
+	tokens.EXPECT().Create(gomock.Any(), gomock.Any()).Return(domain.EmailVerificationToken{ID: 1}, nil)
+	// un fallo del mail no rompe el registro
+	mailer.EXPECT().Send(gomock.Any(), gomock.Any()).Return(errors.New("smtp down"))
+
+	uc := app.RegisterUserUseCase{
+		UserRepo:     userRepo,
+		Hasher:       hasher,
+		Verification: &app.SendEmailVerificationUseCase{Tokens: tokens, Opaque: opaque, Mailer: mailer},
+	}
+
+	// Act
+	result, err := uc.Execute(context.Background(), app.RegisterInput{
+		Email:    "john@example.com",
+		Password: "SecurePassword123",
+		Name:     "John",
+	})
+
+	// Assert
+	require.NoError(t, err)
+	require.False(t, result.EmailVerified)
+}
+
+func TestUC02RegisterUser_WithoutVerification_CreatesVerified(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fixedTime := time.Date(2026, 1, 22, 10, 30, 0, 0, time.UTC)
+
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	hasher := mocks.NewMockPasswordHasher(ctrl)
+
+	userRepo.EXPECT().ExistsByEmail(gomock.Any(), "john@example.com").Return(false, nil)
+	hasher.EXPECT().Hash("SecurePassword123").Return("hash", nil)
+	userRepo.EXPECT().
+		Create(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, u domain.User) (domain.User, error) {
+			require.NotNil(t, u.EmailVerifiedAt)
+			require.Equal(t, fixedTime, *u.EmailVerifiedAt)
+			u.ID = 4
+			return u, nil
+		})
+
+	uc := app.RegisterUserUseCase{
+		UserRepo: userRepo,
+		Hasher:   hasher,
+		Now:      func() time.Time { return fixedTime },
+	}
+
+	// Act
+	result, err := uc.Execute(context.Background(), app.RegisterInput{
+		Email:    "john@example.com",
+		Password: "SecurePassword123",
+		Name:     "John",
+	})
+
+	// Assert
+	require.NoError(t, err)
+	require.True(t, result.EmailVerified)
+}
@@ -18,12 +18,23 @@ var (
 type LoginInput struct {
 	Email    string `json:"email"`
 	Password string `json:"password"`
+
+	// IP del cliente para el throttling por IP (lo completa el handler)
+	ClientIP string `json:"-"`
 }
 
 type LoginOutput struct {
-	AccessToken string    `json:"access_token"`
-	TokenType   string    `json:"token_type"`
-	ExpiresAt   time.Time `json:"expires_at"`
+	AccessToken      string     `json:"access_token"`
+	TokenType        string     `json:"token_type"`
+	ExpiresAt        time.Time  `json:"expires_at"`
+	RefreshToken     string     `json:"refresh_token,omitempty"`
+	RefreshExpiresAt *time.Time `json:"refresh_expires_at,omitempty"`
+
+	// con ErrTooManyLoginAttempts: cuánto falta para poder reintentar
+	RetryAfter time.Duration `json:"-"`
+
+	// usuario con 2FA: en lugar de tokens se devuelve el challenge a canjear
+	Challenge *TwoFactorChallengeOutput `json:"-"`
 }
 
 type LoginUseCase struct {
@@ -32,6 +43,17 @@ type LoginUseCase struct {
 	Tokens   domain.TokenService
 	Now      func() time.Time
 	TTL      time.Duration
+
+	// opcionales: si RefreshTokens es nil sólo se emite el access token
+	RefreshTokens domain.RefreshTokenRepository
+	Opaque        domain.OpaqueTokenService
+	RefreshTTL    time.Duration
+
+	// opcional: sin Throttle no hay límite de intentos fallidos
+	Throttle *LoginThrottle
+
+	// opcional: sin TwoFactor se ignora el 2FA de los usuarios
+	TwoFactor *TwoFactorLogin
 }
 
 func (uc LoginUseCase) Execute(ctx context.Context, in LoginInput) (LoginOutput, error) {
@@ -49,30 +71,92 @@ func (uc LoginUseCase) Execute(ctx context.Context, in LoginInput) (LoginOutput,
 		return LoginOutput{}, ErrInvalidPasswordL
 	}
 
+	now := uc.Now
+	if now == nil {
+		now = time.Now
+	}
+
+	// se chequea antes de comparar el hash: una cuenta bloqueada no revela si la contraseña era correcta
+	if uc.Throttle != nil {
+		retryAfter, err := uc.Throttle.Check(ctx, email, in.ClientIP, now().UTC())
+		if err != nil {
+			return LoginOutput{RetryAfter: retryAfter}, err
+		}
+	}
+
 	u, err := uc.UserRepo.FindByEmail(ctx, email)
 	if err != nil {
 		return LoginOutput{}, err
 	}
-	if u == nil {
+
+	ok := false
+	if u != nil {
+		ok, err = uc.Hasher.Compare(u.PasswordHash, password)
+		if err != nil {
+			return LoginOutput{}, err
+		}
+	}
+	if !ok {
+		// los emails inexistentes también cuentan, si no se podría enumerar cuentas
+		if uc.Throttle != nil {
+			if err := uc.Throttle.Failure(ctx, email, in.ClientIP, now().UTC()); err != nil {
+				return LoginOutput{}, err
+			}
+		}
 		return LoginOutput{}, ErrInvalidCredentials
 	}
 
-	ok, err := uc.Hasher.Compare(u.PasswordHash, password)
+	// con 2FA la contraseña sola no alcanza: se emite un challenge y el contador
+	// de fallos de la cuenta se resetea recién al validar el código
+	ch, err := uc.twoFactorChallenge(ctx, u.ID, now().UTC())
 	if err != nil {
 		return LoginOutput{}, err
 	}
-	if !ok {
-		return LoginOutput{}, ErrInvalidCredentials
+	if ch != nil {
+		return LoginOutput{Challenge: ch}, nil
+	}
+
+	if uc.Throttle != nil {
+		if err := uc.Throttle.Success(ctx, email); err != nil {
+			return LoginOutput{}, err
+		}
+	}
+
+	return uc.issueSession(ctx, *u, now().UTC())
+}
+
+// twoFactorChallenge emite un challenge si el usuario tiene 2FA confirmado; nil si no hace falta.
+func (uc LoginUseCase) twoFactorChallenge(ctx context.Context, userID int64, now time.Time) (*TwoFactorChallengeOutput, error) {
+	if uc.TwoFactor == nil {
+		return nil, nil
 	}
 
-	token, err := uc.Tokens.Generate(u.ID, u.Email)
+	e, err := uc.TwoFactor.TOTP.Get(ctx, userID)
 	if err != nil {
-		return LoginOutput{}, err
+		return nil, err
+	}
+	if e == nil || !e.IsConfirmed() {
+		return nil, nil
 	}
 
-	now := uc.Now
-	if now == nil {
-		now = time.Now
+	ch, err := uc.TwoFactor.challenge(ctx, uc.Opaque, userID, now)
+	if err != nil {
+		return nil, err
+	}
+	return &ch, nil
+}
+
+// issueSession emite el access token (y el refresh token si está configurado)
+// de un usuario ya autenticado.
+func (uc LoginUseCase) issueSession(ctx context.Context, u domain.User, now time.Time) (LoginOutput, error) {
+	role := u.Role
+	if role == "" {
+		role = domain.RoleUser
+	}
+
+	token, err := uc.Tokens.Generate(u.ID, u.Email, role)
+	if err != nil {
+		return LoginOutput{}, err
 	}
 
 	ttl := uc.TTL
@@ -80,9 +164,26 @@ func (uc LoginUseCase) Execute(ctx context.Context, in LoginInput) (LoginOutput,
 		ttl = 60 * time.Minute
 	}
 
-	return LoginOutput{
+	out := LoginOutput{
 		AccessToken: token,
 		TokenType:   "Bearer",
-		ExpiresAt:   now().UTC().Add(ttl),
-	}, nil
+		ExpiresAt:   now.Add(ttl),
+	}
+
+	if uc.RefreshTokens != nil {
+		// cada login abre una familia nueva de refresh tokens
+		family, err := uc.Opaque.Generate()
+		if err != nil {
+			return LoginOutput{}, err
+		}
+
+		rt, err := issueRefreshToken(ctx, uc.RefreshTokens, uc.Opaque, u.ID, family, now, uc.RefreshTTL)
+		if err != nil {
+			return LoginOutput{}, err
+		}
+		out.RefreshToken = rt.Token
+		out.RefreshExpiresAt = &rt.ExpiresAt
+	}
+
+	return out, nil
 }
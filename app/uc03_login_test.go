@@ -274,7 +274,7 @@ func TestUC03Login_TokenServiceError_WhenGenerateTokenFails(t *testing.T) {
 		Return(true, nil)
 
 	tokens.EXPECT().
-		Generate(int64(1), "john@example.com").
+		Generate(int64(1), "john@example.com", domain.RoleUser).
 		Return("", errors.New("token_error"))
 
 	uc := app.LoginUseCase{
@@ -322,7 +322,7 @@ func TestUC03Login_Success_ReturnsAccessToken(t *testing.T) {
 		Return(true, nil)
 
 	tokens.EXPECT().
-		Generate(int64(1), "john@example.com").
+		Generate(int64(1), "john@example.com", domain.RoleUser).
 		Return("eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9...", nil)
 
 	uc := app.LoginUseCase{
@@ -373,7 +373,7 @@ func TestUC03Login_Success_UsesDefaultTTLWhenNotSet(t *testing.T) {
 		Return(true, nil)
 
 	tokens.EXPECT().
-		Generate(int64(1), "john@example.com").
+		Generate(int64(1), "john@example.com", domain.RoleUser).
 		Return("token123", nil)
 
 	uc := app.LoginUseCase{
@@ -395,3 +395,252 @@ func TestUC03Login_Success_UsesDefaultTTLWhenNotSet(t *testing.T) {
 	expectedTTL := 60 * time.Minute
 	require.Equal(t, fixedTime.Add(expectedTTL), result.ExpiresAt)
 }
+
+func TestUC03Login_Success_EmbedsUserRoleInToken(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	hasher := mocks.NewMockPasswordHasher(ctrl)
+	tokens := mocks.NewMockTokenService(ctrl)
+
+	user := domain.User{
+		ID:           7,
+		Email:        "admin@example.com",
+		Name:         "Admin",
+		PasswordHash: "$2a$10$encrypted_hash",
+		Role:         domain.RoleAdmin,
+	}
+
+	userRepo.EXPECT().
+		FindByEmail(gomock.Any(), "admin@example.com").
+		Return(&user, nil)
+
+	hasher.EXPECT().
+		Compare("$2a$10$encrypted_hash", "SecurePassword123").
+		Return(true, nil)
+
+	tokens.EXPECT().
+		Generate(int64(7), "admin@example.com", domain.RoleAdmin).
+		Return("admin-token", nil)
+
+	uc := app.LoginUseCase{
+		UserRepo: userRepo,
+		Hasher:   hasher,
+		Tokens:   tokens,
+	}
+
+	// Act
+	result, err := uc.Execute(context.Background(), app.LoginInput{
+		Email:    "admin@example.com",
+		Password: "SecurePassword123",
+	})
+
+	// Assert
+	require.NoError(t, err)
+	require.Equal(t, "admin-token", result.AccessToken)
+}
+
+func TestUC03Login_Success_IssuesRefreshTokenInNewFamily(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	hasher := mocks.NewMockPasswordHasher(ctrl)
+	tokens := mocks.NewMockTokenService(ctrl)
+	refreshRepo := mocks.NewMockRefreshTokenRepository(ctrl)
+	opaque := mocks.NewMockOpaqueTokenService(ctrl)
+
+	fixedNow := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	userRepo.EXPECT().
+		FindByEmail(gomock.Any(), "john@example.com").
+		Return(&domain.User{ID: 1, Email: "john@example.com", PasswordHash: "h", Role: domain.RoleUser}, nil)
+	hasher.EXPECT().Compare("h", "SecurePassword123").Return(true, nil)
+	tokens.EXPECT().Generate(int64(1), "john@example.com", domain.RoleUser).Return("access", nil)
+
+	gomock.InOrder(
+		opaque.EXPECT().Generate().Return("family-1", nil),
+		opaque.EXPECT().Generate().Return("refresh-1", nil),
+	)
+	opaque.EXPECT().Hash("refresh-1").Return("hash-1")
+
+	refreshRepo.EXPECT().
+		Create(gomock.Any(), domain.RefreshToken{
+			UserID:    1,
+			FamilyID:  "family-1",
+			TokenHash: "hash-1",
+			ExpiresAt: fixedNow.Add(24 * time.Hour),
+			CreatedAt: fixedNow,
+		}).
+		Return(domain.RefreshToken{ID: 9}, nil)
+
+	uc := app.LoginUseCase{
+		UserRepo:      userRepo,
+		Hasher:        hasher,
+		Tokens:        tokens,
+		Now:           func() time.Time { return fixedNow },
+		TTL:           15 * time.Minute,
+		RefreshTokens: refreshRepo,
+		Opaque:        opaque,
+		RefreshTTL:    24 * time.Hour,
+	}
+
+	// Act
+	result, err := uc.Execute(context.Background(), app.LoginInput{
+		Email:    "john@example.com",
+		Password: "SecurePassword123",
+	})
+
+	// Assert
+	require.NoError(t, err)
+	require.Equal(t, "access", result.AccessToken)
+	require.Equal(t, "refresh-1", result.RefreshToken)
+	require.NotNil(t, result.RefreshExpiresAt)
+	require.Equal(t, fixedNow.Add(24*time.Hour), *result.RefreshExpiresAt)
+}
+
+func TestUC03Login_RepoError_WhenRefreshTokenCreateFails(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	hasher := mocks.NewMockPasswordHasher(ctrl)
+	tokens := mocks.NewMockTokenService(ctrl)
+	refreshRepo := mocks.NewMockRefreshTokenRepository(ctrl)
+	opaque := mocks.NewMockOpaqueTokenService(ctrl)
+
+	userRepo.EXPECT().
+		FindByEmail(gomock.Any(), "john@example.com").
+		Return(&domain.User{ID: 1, Email: "john@example.com", PasswordHash: "h"}, nil)
+	hasher.EXPECT().Compare("h", "SecurePassword123").Return(true, nil)
+	tokens.EXPECT().Generate(int64(1), "john@example.com", domain.RoleUser).Return("access", nil)
+	opaque.EXPECT().Generate().Return("x", nil).Times(2)
+	opaque.EXPECT().Hash("x").Return("hx")
+	refreshRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(domain.RefreshToken{}, errors.New("db_error"))
+
+	uc := app.LoginUseCase{
+		UserRepo:      userRepo,
+		Hasher:        hasher,
+		Tokens:        tokens,
+		RefreshTokens: refreshRepo,
+		Opaque:        opaque,
+	}
+
+	// Act
+	_, err := uc.Execute(context.Background(), app.LoginInput{
+		Email:    "john@example.com",
+		Password: "SecurePassword123",
+	})
+
+	// Assert
+	require.EqualError(t, err, "db_error")
+}
+
+func TestUC03Login_TooManyAttempts_WhenLocked(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	store := mocks.NewMockLoginAttemptStore(ctrl)
+
+	fixedTime := time.Date(2026, 1, 22, 10, 0, 0, 0, time.UTC)
+	until := fixedTime.Add(90 * time.Second)
+
+	store.EXPECT().Get(gomock.Any(), "account:john@example.com").Return(&domain.LoginAttempt{LockedUntil: &until}, nil)
+	store.EXPECT().Get(gomock.Any(), "ip:10.0.0.1").Return(nil, nil)
+
+	uc := app.LoginUseCase{
+		UserRepo: userRepo,
+		Hasher:   mocks.NewMockPasswordHasher(ctrl),
+		Tokens:   mocks.NewMockTokenService(ctrl),
+		Now:      func() time.Time { return fixedTime },
+		Throttle: &app.LoginThrottle{Store: store},
+	}
+
+	// Act (no se consulta el usuario ni se compara el hash)
+	out, err := uc.Execute(context.Background(), app.LoginInput{
+		Email:    "john@example.com",
+		Password: "SecurePassword123",
+		ClientIP: "10.0.0.1",
+	})
+
+	// Assert
+	require.ErrorIs(t, err, app.ErrTooManyLoginAttempts)
+	require.Equal(t, 90*time.Second, out.RetryAfter)
+}
+
+func TestUC03Login_RecordsFailure_WhenUserNotFound(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	store := mocks.NewMockLoginAttemptStore(ctrl)
+
+	fixedTime := time.Date(2026, 1, 22, 10, 0, 0, 0, time.UTC)
+
+	store.EXPECT().Get(gomock.Any(), gomock.Any()).Return(nil, nil).Times(2)
+	userRepo.EXPECT().FindByEmail(gomock.Any(), "ghost@example.com").Return(nil, nil)
+	store.EXPECT().RecordFailure(gomock.Any(), "account:ghost@example.com", fixedTime, gomock.Any()).Return(1, nil)
+	store.EXPECT().RecordFailure(gomock.Any(), "ip:10.0.0.1", fixedTime, gomock.Any()).Return(1, nil)
+
+	uc := app.LoginUseCase{
+		UserRepo: userRepo,
+		Hasher:   mocks.NewMockPasswordHasher(ctrl),
+		Tokens:   mocks.NewMockTokenService(ctrl),
+		Now:      func() time.Time { return fixedTime },
+		Throttle: &app.LoginThrottle{Store: store},
+	}
+
+	// Act
+	_, err := uc.Execute(context.Background(), app.LoginInput{
+		Email:    "ghost@example.com",
+		Password: "SecurePassword123",
+		ClientIP: "10.0.0.1",
+	})
+
+	// Assert
+	require.ErrorIs(t, err, app.ErrInvalidCredentials)
+}
+
+func TestUC03Login_Success_ResetsAccountFailures(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	hasher := mocks.NewMockPasswordHasher(ctrl)
+	tokens := mocks.NewMockTokenService(ctrl)
+	store := mocks.NewMockLoginAttemptStore(ctrl)
+
+	user := domain.User{ID: 1, Email: "john@example.com", PasswordHash: "hash"}
+
+	store.EXPECT().Get(gomock.Any(), gomock.Any()).Return(nil, nil).Times(2)
+	userRepo.EXPECT().FindByEmail(gomock.Any(), "john@example.com").Return(&user, nil)
+	hasher.EXPECT().Compare("hash", "SecurePassword123").Return(true, nil)
+	store.EXPECT().Reset(gomock.Any(), "account:john@example.com").Return(nil)
+	tokens.EXPECT().Generate(int64(1), "john@example.com", domain.RoleUser).Return("tok", nil)
+
+	uc := app.LoginUseCase{
+		UserRepo: userRepo,
+		Hasher:   hasher,
+		Tokens:   tokens,
+		Throttle: &app.LoginThrottle{Store: store},
+	}
+
+	// Act
+	out, err := uc.Execute(context.Background(), app.LoginInput{
+		Email:    "john@example.com",
+		Password: "SecurePassword123",
+		ClientIP: "10.0.0.1",
+	})
+
+	// Assert
+	require.NoError(t, err)
+	require.Equal(t, "tok", out.AccessToken)
+}
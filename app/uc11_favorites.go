@@ -1,9 +1,133 @@
 package app
 
+import (
+	"context"
+	"math/big"
+	"time"
+
+	"github.com/moondolphin/crypto-api/domain"
+)
+
+// ventana de la variación informada junto a cada cotización
+const favoriteChangeWindow = 24 * time.Hour
+
 type FavoriteCoinOutput struct {
 	ID            int64  `json:"id"`
 	Symbol        string `json:"symbol"`
 	Enabled       bool   `json:"enabled"`
 	CoinGeckoID   string `json:"coingecko_id"`
 	BinanceSymbol string `json:"binance_symbol"`
+
+	// sólo con IncludeQuotes; una por provider/moneda que tenga cotizaciones
+	Quotes []FavoriteQuoteOutput `json:"quotes,omitempty"`
+}
+
+type FavoriteQuoteOutput struct {
+	Provider string    `json:"provider"`
+	Currency string    `json:"currency"`
+	Price    string    `json:"price"`
+	QuotedAt time.Time `json:"quoted_at"`
+
+	// vacíos si no hay cotización de hace 24h (o es de hace más de 48h)
+	Change24h        string   `json:"change_24h,omitempty"`
+	ChangePercent24h *float64 `json:"change_percent_24h,omitempty"`
+}
+
+type ListFavoritesInput struct {
+	IncludeQuotes bool
+}
+
+// ListFavoritesUseCase lista las favoritas del usuario y, si se pide, la
+// última cotización de cada una en cada par configurado con su variación en
+// 24h. Las cotizaciones salen de una sola consulta (ListTickers) para todas
+// las favoritas.
+//
+// La variación compara contra la última cotización de la misma fuente hasta
+// 24h antes de ahora. Si esa referencia es de hace más de otra ventana, o es
+// la misma cotización (la fuente no se actualizó en 24h), no se informa.
+type ListFavoritesUseCase struct {
+	Favorites domain.FavoritesRepository
+	QuoteRepo domain.QuoteRepository
+
+	// pares provider/moneda a informar; vacío = todos los que tengan cotizaciones
+	Pairs []domain.ProviderCurrency
+	Now   func() time.Time
+}
+
+func (uc ListFavoritesUseCase) Execute(ctx context.Context, userID int64, in ListFavoritesInput) ([]FavoriteCoinOutput, error) {
+	coins, err := uc.Favorites.ListFavoriteCoinIDsByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]FavoriteCoinOutput, 0, len(coins))
+	for _, coin := range coins {
+		out = append(out, FavoriteCoinOutput{
+			ID:            coin.ID,
+			Symbol:        coin.Symbol,
+			Enabled:       coin.Enabled,
+			CoinGeckoID:   coin.CoinGeckoID,
+			BinanceSymbol: coin.BinanceSymbol,
+		})
+	}
+	if !in.IncludeQuotes || len(out) == 0 {
+		return out, nil
+	}
+
+	now := uc.Now
+	if now == nil {
+		now = time.Now
+	}
+	prevAt := now().UTC().Add(-favoriteChangeWindow)
+
+	symbols := make([]string, 0, len(out))
+	for _, f := range out {
+		symbols = append(symbols, f.Symbol)
+	}
+	tickers, err := uc.QuoteRepo.ListTickers(ctx, domain.QuoteTickerFilter{
+		Symbols: symbols,
+		Pairs:   uc.Pairs,
+		PrevAt:  prevAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	bySymbol := make(map[string][]FavoriteQuoteOutput, len(out))
+	for _, t := range tickers {
+		bySymbol[t.Symbol] = append(bySymbol[t.Symbol], favoriteQuote(t, prevAt))
+	}
+	for i := range out {
+		out[i].Quotes = bySymbol[out[i].Symbol]
+	}
+	return out, nil
+}
+
+func favoriteQuote(t domain.QuoteTicker, prevAt time.Time) FavoriteQuoteOutput {
+	q := FavoriteQuoteOutput{
+		Provider: t.Provider,
+		Currency: t.Currency,
+		Price:    t.Price,
+		QuotedAt: t.QuotedAt,
+	}
+
+	price, ok := parseDecimal(t.Price)
+	if !ok {
+		return q
+	}
+	q.Price = formatDecimal(price, valueScale)
+
+	if t.PrevPrice == "" || !t.PrevQuotedAt.Before(t.QuotedAt) || t.PrevQuotedAt.Before(prevAt.Add(-favoriteChangeWindow)) {
+		return q
+	}
+	prev, ok := parseDecimal(t.PrevPrice)
+	if !ok || prev.Sign() <= 0 {
+		return q
+	}
+
+	change := new(big.Rat).Sub(price, prev)
+	pct := allocationPercent(change, prev)
+	q.Change24h = formatDecimal(change, valueScale)
+	q.ChangePercent24h = &pct
+	return q
 }
@@ -0,0 +1,151 @@
+package app_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/moondolphin/crypto-api/app"
+	"github.com/moondolphin/crypto-api/domain"
+	"github.com/moondolphin/crypto-api/test/mocks"
+)
+
+func TestUC11ListFavorites_WithoutQuotes_DoesNotQueryQuotes(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	favs := mocks.NewMockFavoritesRepository(ctrl)
+	quotes := mocks.NewMockQuoteRepository(ctrl)
+
+	favs.EXPECT().ListFavoriteCoinIDsByUser(gomock.Any(), int64(7)).Return([]domain.Coin{
+		{ID: 1, Symbol: "BTC", Enabled: true, CoinGeckoID: "bitcoin", BinanceSymbol: "BTCUSDT"},
+	}, nil)
+
+	uc := app.ListFavoritesUseCase{Favorites: favs, QuoteRepo: quotes}
+
+	// Act
+	out, err := uc.Execute(context.Background(), 7, app.ListFavoritesInput{})
+
+	// Assert
+	require.NoError(t, err)
+	require.Equal(t, []app.FavoriteCoinOutput{
+		{ID: 1, Symbol: "BTC", Enabled: true, CoinGeckoID: "bitcoin", BinanceSymbol: "BTCUSDT"},
+	}, out)
+}
+
+func TestUC11ListFavorites_IncludeQuotes_OneQueryWithChange(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Date(2026, 3, 5, 12, 0, 0, 0, time.UTC)
+	prevAt := now.Add(-24 * time.Hour)
+	pairs := []domain.ProviderCurrency{
+		{Provider: "binance", Currency: "USDT"},
+		{Provider: "coingecko", Currency: "USD"},
+	}
+
+	favs := mocks.NewMockFavoritesRepository(ctrl)
+	quotes := mocks.NewMockQuoteRepository(ctrl)
+
+	favs.EXPECT().ListFavoriteCoinIDsByUser(gomock.Any(), int64(7)).Return([]domain.Coin{
+		{ID: 1, Symbol: "BTC"},
+		{ID: 2, Symbol: "ETH"},
+		{ID: 3, Symbol: "ADA"},
+	}, nil)
+	quotes.EXPECT().ListTickers(gomock.Any(), domain.QuoteTickerFilter{
+		Symbols: []string{"BTC", "ETH", "ADA"},
+		Pairs:   pairs,
+		PrevAt:  prevAt,
+	}).Return([]domain.QuoteTicker{
+		{Symbol: "BTC", Provider: "binance", Currency: "USDT", Price: "110.50000000", QuotedAt: now.Add(-time.Minute),
+			PrevPrice: "100", PrevQuotedAt: prevAt.Add(-time.Minute)},
+		{Symbol: "BTC", Provider: "coingecko", Currency: "USD", Price: "110", QuotedAt: now.Add(-time.Minute)},
+		// la fuente no se actualizó en 24h: la referencia es la misma cotización
+		{Symbol: "ETH", Provider: "binance", Currency: "USDT", Price: "50", QuotedAt: prevAt.Add(-time.Hour),
+			PrevPrice: "50", PrevQuotedAt: prevAt.Add(-time.Hour)},
+		// referencia de hace más de 48h
+		{Symbol: "ETH", Provider: "coingecko", Currency: "USD", Price: "40", QuotedAt: now,
+			PrevPrice: "80", PrevQuotedAt: prevAt.Add(-25 * time.Hour)},
+	}, nil)
+
+	uc := app.ListFavoritesUseCase{
+		Favorites: favs,
+		QuoteRepo: quotes,
+		Pairs:     pairs,
+		Now:       func() time.Time { return now },
+	}
+
+	// Act
+	out, err := uc.Execute(context.Background(), 7, app.ListFavoritesInput{IncludeQuotes: true})
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, out, 3)
+
+	require.Len(t, out[0].Quotes, 2)
+	btc := out[0].Quotes[0]
+	require.Equal(t, "binance", btc.Provider)
+	require.Equal(t, "110.5", btc.Price)
+	require.Equal(t, "10.5", btc.Change24h)
+	require.NotNil(t, btc.ChangePercent24h)
+	require.InDelta(t, 10.5, *btc.ChangePercent24h, 1e-9)
+	require.Empty(t, out[0].Quotes[1].Change24h)
+	require.Nil(t, out[0].Quotes[1].ChangePercent24h)
+
+	require.Len(t, out[1].Quotes, 2)
+	for _, q := range out[1].Quotes {
+		require.Empty(t, q.Change24h)
+		require.Nil(t, q.ChangePercent24h)
+	}
+
+	// sin cotizaciones: no hay entradas
+	require.Empty(t, out[2].Quotes)
+}
+
+func TestUC11ListFavorites_IncludeQuotes_NoFavorites_SkipsQuery(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	favs := mocks.NewMockFavoritesRepository(ctrl)
+	quotes := mocks.NewMockQuoteRepository(ctrl)
+
+	favs.EXPECT().ListFavoriteCoinIDsByUser(gomock.Any(), int64(7)).Return(nil, nil)
+
+	uc := app.ListFavoritesUseCase{Favorites: favs, QuoteRepo: quotes}
+
+	// Act
+	out, err := uc.Execute(context.Background(), 7, app.ListFavoritesInput{IncludeQuotes: true})
+
+	// Assert
+	require.NoError(t, err)
+	require.Empty(t, out)
+	require.NotNil(t, out)
+}
+
+func TestUC11ListFavorites_QuotesError_Propagates(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	favs := mocks.NewMockFavoritesRepository(ctrl)
+	quotes := mocks.NewMockQuoteRepository(ctrl)
+	boom := errors.New("db down")
+
+	favs.EXPECT().ListFavoriteCoinIDsByUser(gomock.Any(), int64(7)).Return([]domain.Coin{{ID: 1, Symbol: "BTC"}}, nil)
+	quotes.EXPECT().ListTickers(gomock.Any(), gomock.Any()).Return(nil, boom)
+
+	uc := app.ListFavoritesUseCase{Favorites: favs, QuoteRepo: quotes}
+
+	// Act
+	_, err := uc.Execute(context.Background(), 7, app.ListFavoritesInput{IncludeQuotes: true})
+
+	// Assert
+	require.ErrorIs(t, err, boom)
+}
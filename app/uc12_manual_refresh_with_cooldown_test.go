@@ -274,7 +274,7 @@ func TestUC12ManualRefresh_Success_ReturnsRefreshOutput(t *testing.T) {
 
 	quoteRepo.EXPECT().
 		Insert(gomock.Any(), gomock.Any()).
-		Return(nil)
+		Return(true, nil)
 
 	controlRepo.EXPECT().
 		GetLastManualRefresh(gomock.Any()).
@@ -0,0 +1,55 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/moondolphin/crypto-api/domain"
+)
+
+var (
+	ErrUserNotFound = errors.New("user_not_found")
+
+	ErrBootstrapAdminNeedsVerification = errors.New("bootstrap_admin_requires_email_verification")
+)
+
+// BootstrapAdminUseCase promueve a admin al usuario con el email configurado
+// (BOOTSTRAP_ADMIN_EMAIL). Se corre al iniciar; la cuenta tiene que existir y
+// tener el email verificado, así registrar ese email primero no alcanza para
+// quedarse con el admin. Si todavía no lo verificó, VerifyEmailUseCase la
+// promueve al verificarlo.
+//
+// Con la verificación de email apagada las cuentas nacen verificadas y nadie
+// prueba ser dueño del buzón: no se promueve a nadie
+// (ErrBootstrapAdminNeedsVerification).
+type BootstrapAdminUseCase struct {
+	UserRepo                 domain.UserRepository
+	EmailVerificationEnabled bool
+}
+
+func (uc BootstrapAdminUseCase) Execute(ctx context.Context, email string) error {
+	email = strings.ToLower(strings.TrimSpace(email))
+	if email == "" {
+		return ErrBadRequest
+	}
+	if !uc.EmailVerificationEnabled {
+		return ErrBootstrapAdminNeedsVerification
+	}
+
+	u, err := uc.UserRepo.FindByEmail(ctx, email)
+	if err != nil {
+		return err
+	}
+	if u == nil {
+		return ErrUserNotFound
+	}
+	if u.Role == domain.RoleAdmin {
+		return nil
+	}
+	if !u.IsEmailVerified() {
+		return ErrEmailNotVerified
+	}
+
+	return uc.UserRepo.UpdateRole(ctx, u.ID, domain.RoleAdmin)
+}
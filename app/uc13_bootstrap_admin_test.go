@@ -0,0 +1,148 @@
+package app_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/moondolphin/crypto-api/app"
+	"github.com/moondolphin/crypto-api/domain"
+	"github.com/moondolphin/crypto-api/test/mocks"
+)
+
+func TestUC13BootstrapAdmin_BadRequest_WhenEmailEmpty(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	uc := app.BootstrapAdminUseCase{UserRepo: mocks.NewMockUserRepository(ctrl)}
+
+	// Act
+	err := uc.Execute(context.Background(), "  ")
+
+	// Assert
+	require.ErrorIs(t, err, app.ErrBadRequest)
+}
+
+func TestUC13BootstrapAdmin_UserNotFound_WhenNotRegistered(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	userRepo.EXPECT().
+		FindByEmail(gomock.Any(), "boss@example.com").
+		Return(nil, nil)
+
+	uc := app.BootstrapAdminUseCase{UserRepo: userRepo, EmailVerificationEnabled: true}
+
+	// Act
+	err := uc.Execute(context.Background(), " Boss@Example.com ")
+
+	// Assert
+	require.ErrorIs(t, err, app.ErrUserNotFound)
+}
+
+var bootstrapVerifiedAt = time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+
+func TestUC13BootstrapAdmin_Success_PromotesUser(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	userRepo.EXPECT().
+		FindByEmail(gomock.Any(), "boss@example.com").
+		Return(&domain.User{ID: 3, Email: "boss@example.com", Role: domain.RoleUser, EmailVerifiedAt: &bootstrapVerifiedAt}, nil)
+	userRepo.EXPECT().
+		UpdateRole(gomock.Any(), int64(3), domain.RoleAdmin).
+		Return(nil)
+
+	uc := app.BootstrapAdminUseCase{UserRepo: userRepo, EmailVerificationEnabled: true}
+
+	// Act
+	err := uc.Execute(context.Background(), "boss@example.com")
+
+	// Assert
+	require.NoError(t, err)
+}
+
+func TestUC13BootstrapAdmin_Success_NoopWhenAlreadyAdmin(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	userRepo.EXPECT().
+		FindByEmail(gomock.Any(), "boss@example.com").
+		Return(&domain.User{ID: 3, Email: "boss@example.com", Role: domain.RoleAdmin}, nil)
+
+	uc := app.BootstrapAdminUseCase{UserRepo: userRepo, EmailVerificationEnabled: true}
+
+	// Act
+	err := uc.Execute(context.Background(), "boss@example.com")
+
+	// Assert
+	require.NoError(t, err)
+}
+
+func TestUC13BootstrapAdmin_RepoError_WhenUpdateFails(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	userRepo.EXPECT().
+		FindByEmail(gomock.Any(), "boss@example.com").
+		Return(&domain.User{ID: 3, Email: "boss@example.com", EmailVerifiedAt: &bootstrapVerifiedAt}, nil)
+	userRepo.EXPECT().
+		UpdateRole(gomock.Any(), int64(3), domain.RoleAdmin).
+		Return(errors.New("db_error"))
+
+	uc := app.BootstrapAdminUseCase{UserRepo: userRepo, EmailVerificationEnabled: true}
+
+	// Act
+	err := uc.Execute(context.Background(), "boss@example.com")
+
+	// Assert
+	require.EqualError(t, err, "db_error")
+}
+
+func TestUC13BootstrapAdmin_EmailNotVerified_StaysUser(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// quien registró primero el email del admin sin verificarlo no se lo queda
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	userRepo.EXPECT().
+		FindByEmail(gomock.Any(), "boss@example.com").
+		Return(&domain.User{ID: 3, Email: "boss@example.com", Role: domain.RoleUser}, nil)
+
+	uc := app.BootstrapAdminUseCase{UserRepo: userRepo, EmailVerificationEnabled: true}
+
+	// Act
+	err := uc.Execute(context.Background(), "boss@example.com")
+
+	// Assert
+	require.ErrorIs(t, err, app.ErrEmailNotVerified)
+}
+
+func TestUC13BootstrapAdmin_VerificationDisabled_RefusesToPromote(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// sin verificación la cuenta nace verificada: ni se busca
+	uc := app.BootstrapAdminUseCase{UserRepo: mocks.NewMockUserRepository(ctrl)}
+
+	// Act
+	err := uc.Execute(context.Background(), "boss@example.com")
+
+	// Assert
+	require.ErrorIs(t, err, app.ErrBootstrapAdminNeedsVerification)
+}
@@ -0,0 +1,153 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/moondolphin/crypto-api/domain"
+)
+
+var (
+	ErrInvalidRefreshToken = errors.New("invalid_refresh_token")
+	ErrRefreshTokenReused  = errors.New("refresh_token_reused")
+)
+
+const defaultRefreshTTL = 30 * 24 * time.Hour
+
+type RefreshTokenInput struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+// RefreshTokenUseCase canjea un refresh token por un access token nuevo y rota el
+// refresh token (el presentado queda revocado). Presentar un token ya revocado se
+// considera robo: se revoca toda la familia y el usuario debe volver a loguearse.
+type RefreshTokenUseCase struct {
+	UserRepo      domain.UserRepository
+	Tokens        domain.TokenService
+	RefreshTokens domain.RefreshTokenRepository
+	Opaque        domain.OpaqueTokenService
+	Now           func() time.Time
+	TTL           time.Duration
+	RefreshTTL    time.Duration
+}
+
+func (uc RefreshTokenUseCase) Execute(ctx context.Context, in RefreshTokenInput) (LoginOutput, error) {
+	raw := strings.TrimSpace(in.RefreshToken)
+	if raw == "" {
+		return LoginOutput{}, ErrBadRequest
+	}
+
+	now := uc.Now
+	if now == nil {
+		now = time.Now
+	}
+	t := now().UTC()
+
+	current, err := uc.RefreshTokens.FindByHash(ctx, uc.Opaque.Hash(raw))
+	if err != nil {
+		return LoginOutput{}, err
+	}
+	if current == nil {
+		return LoginOutput{}, ErrInvalidRefreshToken
+	}
+
+	if current.IsRevoked() {
+		if err := uc.RefreshTokens.RevokeFamily(ctx, current.FamilyID, t); err != nil {
+			return LoginOutput{}, err
+		}
+		return LoginOutput{}, ErrRefreshTokenReused
+	}
+	if current.IsExpired(t) {
+		return LoginOutput{}, ErrInvalidRefreshToken
+	}
+
+	revoked, err := uc.RefreshTokens.Revoke(ctx, current.ID, t)
+	if err != nil {
+		return LoginOutput{}, err
+	}
+	if !revoked {
+		// otro request rotó el mismo token en paralelo: mismo tratamiento que el reuso
+		if err := uc.RefreshTokens.RevokeFamily(ctx, current.FamilyID, t); err != nil {
+			return LoginOutput{}, err
+		}
+		return LoginOutput{}, ErrRefreshTokenReused
+	}
+
+	u, err := uc.UserRepo.FindByID(ctx, current.UserID)
+	if err != nil {
+		return LoginOutput{}, err
+	}
+	if u == nil {
+		return LoginOutput{}, ErrInvalidRefreshToken
+	}
+
+	role := u.Role
+	if role == "" {
+		role = domain.RoleUser
+	}
+
+	access, err := uc.Tokens.Generate(u.ID, u.Email, role)
+	if err != nil {
+		return LoginOutput{}, err
+	}
+
+	next, err := issueRefreshToken(ctx, uc.RefreshTokens, uc.Opaque, u.ID, current.FamilyID, t, uc.RefreshTTL)
+	if err != nil {
+		return LoginOutput{}, err
+	}
+
+	ttl := uc.TTL
+	if ttl <= 0 {
+		ttl = 60 * time.Minute
+	}
+
+	return LoginOutput{
+		AccessToken:      access,
+		TokenType:        "Bearer",
+		ExpiresAt:        t.Add(ttl),
+		RefreshToken:     next.Token,
+		RefreshExpiresAt: &next.ExpiresAt,
+	}, nil
+}
+
+type issuedRefreshToken struct {
+	Token     string
+	ExpiresAt time.Time
+}
+
+// issueRefreshToken genera un token opaco, persiste su hash dentro de family y
+// devuelve el valor en claro (única vez que se conoce).
+func issueRefreshToken(
+	ctx context.Context,
+	repo domain.RefreshTokenRepository,
+	opaque domain.OpaqueTokenService,
+	userID int64,
+	family string,
+	now time.Time,
+	ttl time.Duration,
+) (issuedRefreshToken, error) {
+	if ttl <= 0 {
+		ttl = defaultRefreshTTL
+	}
+
+	raw, err := opaque.Generate()
+	if err != nil {
+		return issuedRefreshToken{}, err
+	}
+
+	expiresAt := now.Add(ttl)
+	_, err = repo.Create(ctx, domain.RefreshToken{
+		UserID:    userID,
+		FamilyID:  family,
+		TokenHash: opaque.Hash(raw),
+		ExpiresAt: expiresAt,
+		CreatedAt: now,
+	})
+	if err != nil {
+		return issuedRefreshToken{}, err
+	}
+
+	return issuedRefreshToken{Token: raw, ExpiresAt: expiresAt}, nil
+}
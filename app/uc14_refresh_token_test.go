@@ -0,0 +1,306 @@
+package app_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/moondolphin/crypto-api/app"
+	"github.com/moondolphin/crypto-api/domain"
+	"github.com/moondolphin/crypto-api/test/mocks"
+)
+
+func TestUC14RefreshToken_BadRequest_WhenEmpty(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fixedNow := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	tokens := mocks.NewMockTokenService(ctrl)
+	refreshRepo := mocks.NewMockRefreshTokenRepository(ctrl)
+	opaque := mocks.NewMockOpaqueTokenService(ctrl)
+
+	uc := app.RefreshTokenUseCase{
+		UserRepo:      userRepo,
+		Tokens:        tokens,
+		RefreshTokens: refreshRepo,
+		Opaque:        opaque,
+		Now:           func() time.Time { return fixedNow },
+		TTL:           15 * time.Minute,
+		RefreshTTL:    24 * time.Hour,
+	}
+
+	// Act
+	_, err := uc.Execute(context.Background(), app.RefreshTokenInput{RefreshToken: "  "})
+
+	// Assert
+	require.ErrorIs(t, err, app.ErrBadRequest)
+}
+
+func TestUC14RefreshToken_Invalid_WhenUnknown(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fixedNow := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	tokens := mocks.NewMockTokenService(ctrl)
+	refreshRepo := mocks.NewMockRefreshTokenRepository(ctrl)
+	opaque := mocks.NewMockOpaqueTokenService(ctrl)
+
+	opaque.EXPECT().Hash("nope").Return("h-nope")
+	refreshRepo.EXPECT().FindByHash(gomock.Any(), "h-nope").Return(nil, nil)
+
+	uc := app.RefreshTokenUseCase{
+		UserRepo:      userRepo,
+		Tokens:        tokens,
+		RefreshTokens: refreshRepo,
+		Opaque:        opaque,
+		Now:           func() time.Time { return fixedNow },
+		TTL:           15 * time.Minute,
+		RefreshTTL:    24 * time.Hour,
+	}
+
+	// Act
+	_, err := uc.Execute(context.Background(), app.RefreshTokenInput{RefreshToken: "nope"})
+
+	// Assert
+	require.ErrorIs(t, err, app.ErrInvalidRefreshToken)
+}
+
+func TestUC14RefreshToken_Invalid_WhenExpired(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fixedNow := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	tokens := mocks.NewMockTokenService(ctrl)
+	refreshRepo := mocks.NewMockRefreshTokenRepository(ctrl)
+	opaque := mocks.NewMockOpaqueTokenService(ctrl)
+
+	opaque.EXPECT().Hash("old").Return("h-old")
+	refreshRepo.EXPECT().
+		FindByHash(gomock.Any(), "h-old").
+		Return(&domain.RefreshToken{ID: 1, UserID: 1, FamilyID: "fam", ExpiresAt: fixedNow}, nil)
+
+	uc := app.RefreshTokenUseCase{
+		UserRepo:      userRepo,
+		Tokens:        tokens,
+		RefreshTokens: refreshRepo,
+		Opaque:        opaque,
+		Now:           func() time.Time { return fixedNow },
+		TTL:           15 * time.Minute,
+		RefreshTTL:    24 * time.Hour,
+	}
+
+	// Act
+	_, err := uc.Execute(context.Background(), app.RefreshTokenInput{RefreshToken: "old"})
+
+	// Assert
+	require.ErrorIs(t, err, app.ErrInvalidRefreshToken)
+}
+
+func TestUC14RefreshToken_Reused_RevokesFamilyWhenTokenAlreadyRevoked(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fixedNow := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	tokens := mocks.NewMockTokenService(ctrl)
+	refreshRepo := mocks.NewMockRefreshTokenRepository(ctrl)
+	opaque := mocks.NewMockOpaqueTokenService(ctrl)
+
+	revokedAt := fixedNow.Add(-time.Minute)
+
+	opaque.EXPECT().Hash("stolen").Return("h-stolen")
+	refreshRepo.EXPECT().
+		FindByHash(gomock.Any(), "h-stolen").
+		Return(&domain.RefreshToken{ID: 1, UserID: 1, FamilyID: "fam", ExpiresAt: fixedNow.Add(time.Hour), RevokedAt: &revokedAt}, nil)
+	refreshRepo.EXPECT().RevokeFamily(gomock.Any(), "fam", fixedNow).Return(nil)
+
+	uc := app.RefreshTokenUseCase{
+		UserRepo:      userRepo,
+		Tokens:        tokens,
+		RefreshTokens: refreshRepo,
+		Opaque:        opaque,
+		Now:           func() time.Time { return fixedNow },
+		TTL:           15 * time.Minute,
+		RefreshTTL:    24 * time.Hour,
+	}
+
+	// Act
+	_, err := uc.Execute(context.Background(), app.RefreshTokenInput{RefreshToken: "stolen"})
+
+	// Assert
+	require.ErrorIs(t, err, app.ErrRefreshTokenReused)
+}
+
+func TestUC14RefreshToken_Reused_WhenConcurrentRotationWins(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fixedNow := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	tokens := mocks.NewMockTokenService(ctrl)
+	refreshRepo := mocks.NewMockRefreshTokenRepository(ctrl)
+	opaque := mocks.NewMockOpaqueTokenService(ctrl)
+
+	opaque.EXPECT().Hash("rt").Return("h-rt")
+	refreshRepo.EXPECT().
+		FindByHash(gomock.Any(), "h-rt").
+		Return(&domain.RefreshToken{ID: 1, UserID: 1, FamilyID: "fam", ExpiresAt: fixedNow.Add(time.Hour)}, nil)
+	refreshRepo.EXPECT().Revoke(gomock.Any(), int64(1), fixedNow).Return(false, nil)
+	refreshRepo.EXPECT().RevokeFamily(gomock.Any(), "fam", fixedNow).Return(nil)
+
+	uc := app.RefreshTokenUseCase{
+		UserRepo:      userRepo,
+		Tokens:        tokens,
+		RefreshTokens: refreshRepo,
+		Opaque:        opaque,
+		Now:           func() time.Time { return fixedNow },
+		TTL:           15 * time.Minute,
+		RefreshTTL:    24 * time.Hour,
+	}
+
+	// Act
+	_, err := uc.Execute(context.Background(), app.RefreshTokenInput{RefreshToken: "rt"})
+
+	// Assert
+	require.ErrorIs(t, err, app.ErrRefreshTokenReused)
+}
+
+func TestUC14RefreshToken_Invalid_WhenUserNoLongerExists(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fixedNow := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	tokens := mocks.NewMockTokenService(ctrl)
+	refreshRepo := mocks.NewMockRefreshTokenRepository(ctrl)
+	opaque := mocks.NewMockOpaqueTokenService(ctrl)
+
+	opaque.EXPECT().Hash("rt").Return("h-rt")
+	refreshRepo.EXPECT().
+		FindByHash(gomock.Any(), "h-rt").
+		Return(&domain.RefreshToken{ID: 1, UserID: 1, FamilyID: "fam", ExpiresAt: fixedNow.Add(time.Hour)}, nil)
+	refreshRepo.EXPECT().Revoke(gomock.Any(), int64(1), fixedNow).Return(true, nil)
+	userRepo.EXPECT().FindByID(gomock.Any(), int64(1)).Return(nil, nil)
+
+	uc := app.RefreshTokenUseCase{
+		UserRepo:      userRepo,
+		Tokens:        tokens,
+		RefreshTokens: refreshRepo,
+		Opaque:        opaque,
+		Now:           func() time.Time { return fixedNow },
+		TTL:           15 * time.Minute,
+		RefreshTTL:    24 * time.Hour,
+	}
+
+	// Act
+	_, err := uc.Execute(context.Background(), app.RefreshTokenInput{RefreshToken: "rt"})
+
+	// Assert
+	require.ErrorIs(t, err, app.ErrInvalidRefreshToken)
+}
+
+func TestUC14RefreshToken_Success_RotatesWithinSameFamily(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fixedNow := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	tokens := mocks.NewMockTokenService(ctrl)
+	refreshRepo := mocks.NewMockRefreshTokenRepository(ctrl)
+	opaque := mocks.NewMockOpaqueTokenService(ctrl)
+
+	opaque.EXPECT().Hash("rt-1").Return("h-1")
+	refreshRepo.EXPECT().
+		FindByHash(gomock.Any(), "h-1").
+		Return(&domain.RefreshToken{ID: 1, UserID: 7, FamilyID: "fam", ExpiresAt: fixedNow.Add(time.Hour)}, nil)
+	refreshRepo.EXPECT().Revoke(gomock.Any(), int64(1), fixedNow).Return(true, nil)
+	userRepo.EXPECT().
+		FindByID(gomock.Any(), int64(7)).
+		Return(&domain.User{ID: 7, Email: "admin@example.com", Role: domain.RoleAdmin}, nil)
+	tokens.EXPECT().Generate(int64(7), "admin@example.com", domain.RoleAdmin).Return("access-2", nil)
+	opaque.EXPECT().Generate().Return("rt-2", nil)
+	opaque.EXPECT().Hash("rt-2").Return("h-2")
+	refreshRepo.EXPECT().
+		Create(gomock.Any(), domain.RefreshToken{
+			UserID:    7,
+			FamilyID:  "fam",
+			TokenHash: "h-2",
+			ExpiresAt: fixedNow.Add(24 * time.Hour),
+			CreatedAt: fixedNow,
+		}).
+		Return(domain.RefreshToken{ID: 2}, nil)
+
+	uc := app.RefreshTokenUseCase{
+		UserRepo:      userRepo,
+		Tokens:        tokens,
+		RefreshTokens: refreshRepo,
+		Opaque:        opaque,
+		Now:           func() time.Time { return fixedNow },
+		TTL:           15 * time.Minute,
+		RefreshTTL:    24 * time.Hour,
+	}
+
+	// Act
+	out, err := uc.Execute(context.Background(), app.RefreshTokenInput{RefreshToken: " rt-1 "})
+
+	// Assert
+	require.NoError(t, err)
+	require.Equal(t, "access-2", out.AccessToken)
+	require.Equal(t, "Bearer", out.TokenType)
+	require.Equal(t, fixedNow.Add(15*time.Minute), out.ExpiresAt)
+	require.Equal(t, "rt-2", out.RefreshToken)
+	require.Equal(t, fixedNow.Add(24*time.Hour), *out.RefreshExpiresAt)
+}
+
+func TestUC14RefreshToken_RepoError_WhenFindFails(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fixedNow := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	tokens := mocks.NewMockTokenService(ctrl)
+	refreshRepo := mocks.NewMockRefreshTokenRepository(ctrl)
+	opaque := mocks.NewMockOpaqueTokenService(ctrl)
+
+	opaque.EXPECT().Hash("rt").Return("h-rt")
+	refreshRepo.EXPECT().FindByHash(gomock.Any(), "h-rt").Return(nil, errors.New("db_error"))
+
+	uc := app.RefreshTokenUseCase{
+		UserRepo:      userRepo,
+		Tokens:        tokens,
+		RefreshTokens: refreshRepo,
+		Opaque:        opaque,
+		Now:           func() time.Time { return fixedNow },
+		TTL:           15 * time.Minute,
+		RefreshTTL:    24 * time.Hour,
+	}
+
+	// Act
+	_, err := uc.Execute(context.Background(), app.RefreshTokenInput{RefreshToken: "rt"})
+
+	// Assert
+	require.EqualError(t, err, "db_error")
+}
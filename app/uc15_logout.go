@@ -0,0 +1,59 @@
+package app
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/moondolphin/crypto-api/domain"
+)
+
+type LogoutInput struct {
+	RefreshToken string `json:"refresh_token"`
+
+	// access token con el que vino el request (si vino): se agrega a la denylist
+	AccessTokenID   string    `json:"-"`
+	AccessExpiresAt time.Time `json:"-"`
+}
+
+// LogoutUseCase revoca la familia completa del refresh token presentado y, si hay
+// denylist, el access token actual. Es idempotente: un token desconocido o ya
+// revocado no es error.
+type LogoutUseCase struct {
+	RefreshTokens domain.RefreshTokenRepository
+	Opaque        domain.OpaqueTokenService
+	Revocations   domain.TokenRevocationStore // opcional
+	Now           func() time.Time
+}
+
+func (uc LogoutUseCase) Execute(ctx context.Context, in LogoutInput) error {
+	raw := strings.TrimSpace(in.RefreshToken)
+	if raw == "" && in.AccessTokenID == "" {
+		return ErrBadRequest
+	}
+
+	now := uc.Now
+	if now == nil {
+		now = time.Now
+	}
+
+	if uc.Revocations != nil && in.AccessTokenID != "" {
+		if err := uc.Revocations.RevokeJTI(ctx, in.AccessTokenID, in.AccessExpiresAt); err != nil {
+			return err
+		}
+	}
+
+	if raw == "" {
+		return nil
+	}
+
+	current, err := uc.RefreshTokens.FindByHash(ctx, uc.Opaque.Hash(raw))
+	if err != nil {
+		return err
+	}
+	if current == nil {
+		return nil
+	}
+
+	return uc.RefreshTokens.RevokeFamily(ctx, current.FamilyID, now().UTC())
+}
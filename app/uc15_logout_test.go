@@ -0,0 +1,149 @@
+package app_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/moondolphin/crypto-api/app"
+	"github.com/moondolphin/crypto-api/domain"
+	"github.com/moondolphin/crypto-api/test/mocks"
+)
+
+func TestUC15Logout_BadRequest_WhenEmpty(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	uc := app.LogoutUseCase{
+		RefreshTokens: mocks.NewMockRefreshTokenRepository(ctrl),
+		Opaque:        mocks.NewMockOpaqueTokenService(ctrl),
+	}
+
+	// Act
+	err := uc.Execute(context.Background(), app.LogoutInput{})
+
+	// Assert
+	require.ErrorIs(t, err, app.ErrBadRequest)
+}
+
+func TestUC15Logout_Success_NoopWhenUnknownToken(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockRefreshTokenRepository(ctrl)
+	opaque := mocks.NewMockOpaqueTokenService(ctrl)
+	opaque.EXPECT().Hash("rt").Return("h")
+	repo.EXPECT().FindByHash(gomock.Any(), "h").Return(nil, nil)
+
+	uc := app.LogoutUseCase{RefreshTokens: repo, Opaque: opaque}
+
+	// Act
+	err := uc.Execute(context.Background(), app.LogoutInput{RefreshToken: "rt"})
+
+	// Assert
+	require.NoError(t, err)
+}
+
+func TestUC15Logout_Success_RevokesWholeFamily(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fixedNow := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	repo := mocks.NewMockRefreshTokenRepository(ctrl)
+	opaque := mocks.NewMockOpaqueTokenService(ctrl)
+	opaque.EXPECT().Hash("rt").Return("h")
+	repo.EXPECT().
+		FindByHash(gomock.Any(), "h").
+		Return(&domain.RefreshToken{ID: 3, FamilyID: "fam"}, nil)
+	repo.EXPECT().RevokeFamily(gomock.Any(), "fam", fixedNow).Return(nil)
+
+	uc := app.LogoutUseCase{
+		RefreshTokens: repo,
+		Opaque:        opaque,
+		Now:           func() time.Time { return fixedNow },
+	}
+
+	// Act
+	err := uc.Execute(context.Background(), app.LogoutInput{RefreshToken: "rt"})
+
+	// Assert
+	require.NoError(t, err)
+}
+
+func TestUC15Logout_RepoError_WhenRevokeFails(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockRefreshTokenRepository(ctrl)
+	opaque := mocks.NewMockOpaqueTokenService(ctrl)
+	opaque.EXPECT().Hash("rt").Return("h")
+	repo.EXPECT().FindByHash(gomock.Any(), "h").Return(&domain.RefreshToken{ID: 3, FamilyID: "fam"}, nil)
+	repo.EXPECT().RevokeFamily(gomock.Any(), "fam", gomock.Any()).Return(errors.New("db_error"))
+
+	uc := app.LogoutUseCase{RefreshTokens: repo, Opaque: opaque}
+
+	// Act
+	err := uc.Execute(context.Background(), app.LogoutInput{RefreshToken: "rt"})
+
+	// Assert
+	require.EqualError(t, err, "db_error")
+}
+
+func TestUC15Logout_Success_DenylistsCurrentAccessToken(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	exp := time.Date(2026, 1, 10, 13, 0, 0, 0, time.UTC)
+
+	repo := mocks.NewMockRefreshTokenRepository(ctrl)
+	opaque := mocks.NewMockOpaqueTokenService(ctrl)
+	revocations := mocks.NewMockTokenRevocationStore(ctrl)
+
+	revocations.EXPECT().RevokeJTI(gomock.Any(), "jti-1", exp).Return(nil)
+	opaque.EXPECT().Hash("rt").Return("h")
+	repo.EXPECT().FindByHash(gomock.Any(), "h").Return(&domain.RefreshToken{ID: 3, FamilyID: "fam"}, nil)
+	repo.EXPECT().RevokeFamily(gomock.Any(), "fam", gomock.Any()).Return(nil)
+
+	uc := app.LogoutUseCase{RefreshTokens: repo, Opaque: opaque, Revocations: revocations}
+
+	// Act
+	err := uc.Execute(context.Background(), app.LogoutInput{
+		RefreshToken:    "rt",
+		AccessTokenID:   "jti-1",
+		AccessExpiresAt: exp,
+	})
+
+	// Assert
+	require.NoError(t, err)
+}
+
+func TestUC15Logout_Success_OnlyAccessTokenWithoutRefresh(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	revocations := mocks.NewMockTokenRevocationStore(ctrl)
+	revocations.EXPECT().RevokeJTI(gomock.Any(), "jti-1", gomock.Any()).Return(nil)
+
+	uc := app.LogoutUseCase{
+		RefreshTokens: mocks.NewMockRefreshTokenRepository(ctrl),
+		Opaque:        mocks.NewMockOpaqueTokenService(ctrl),
+		Revocations:   revocations,
+	}
+
+	// Act
+	err := uc.Execute(context.Background(), app.LogoutInput{AccessTokenID: "jti-1"})
+
+	// Assert
+	require.NoError(t, err)
+}
@@ -0,0 +1,61 @@
+package app
+
+import (
+	"context"
+	"time"
+
+	"github.com/moondolphin/crypto-api/domain"
+)
+
+// RevokeUserTokensUseCase invalida todas las sesiones de un usuario: los access
+// tokens emitidos hasta ahora (corte por usuario en la denylist), sus refresh
+// tokens y sus API keys.
+type RevokeUserTokensUseCase struct {
+	UserRepo      domain.UserRepository
+	Revocations   domain.TokenRevocationStore
+	RefreshTokens domain.RefreshTokenRepository // opcional
+	APIKeys       domain.APIKeyRepository       // opcional
+	Now           func() time.Time
+
+	// vida máxima de un access token: pasado ese plazo el corte ya no hace falta
+	TTL time.Duration
+}
+
+func (uc RevokeUserTokensUseCase) Execute(ctx context.Context, userID int64) error {
+	if userID <= 0 {
+		return ErrBadRequest
+	}
+
+	u, err := uc.UserRepo.FindByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if u == nil {
+		return ErrUserNotFound
+	}
+
+	now := uc.Now
+	if now == nil {
+		now = time.Now
+	}
+	t := now().UTC()
+
+	ttl := uc.TTL
+	if ttl <= 0 {
+		ttl = 60 * time.Minute
+	}
+
+	if err := uc.Revocations.RevokeUser(ctx, u.ID, t, t.Add(ttl)); err != nil {
+		return err
+	}
+
+	if uc.RefreshTokens != nil {
+		if err := uc.RefreshTokens.RevokeByUser(ctx, u.ID, t); err != nil {
+			return err
+		}
+	}
+	if uc.APIKeys != nil {
+		return uc.APIKeys.RevokeByUser(ctx, u.ID, t)
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package app_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/moondolphin/crypto-api/app"
+	"github.com/moondolphin/crypto-api/domain"
+	"github.com/moondolphin/crypto-api/test/mocks"
+)
+
+func TestUC16RevokeUserTokens_BadRequest_WhenInvalidID(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	uc := app.RevokeUserTokensUseCase{
+		UserRepo:    mocks.NewMockUserRepository(ctrl),
+		Revocations: mocks.NewMockTokenRevocationStore(ctrl),
+	}
+
+	// Act
+	err := uc.Execute(context.Background(), 0)
+
+	// Assert
+	require.ErrorIs(t, err, app.ErrBadRequest)
+}
+
+func TestUC16RevokeUserTokens_UserNotFound(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	userRepo.EXPECT().FindByID(gomock.Any(), int64(9)).Return(nil, nil)
+
+	uc := app.RevokeUserTokensUseCase{
+		UserRepo:    userRepo,
+		Revocations: mocks.NewMockTokenRevocationStore(ctrl),
+	}
+
+	// Act
+	err := uc.Execute(context.Background(), 9)
+
+	// Assert
+	require.ErrorIs(t, err, app.ErrUserNotFound)
+}
+
+func TestUC16RevokeUserTokens_Success_CutsAccessRefreshTokensAndAPIKeys(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fixedNow := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	revocations := mocks.NewMockTokenRevocationStore(ctrl)
+	refreshRepo := mocks.NewMockRefreshTokenRepository(ctrl)
+	apiKeys := mocks.NewMockAPIKeyRepository(ctrl)
+
+	userRepo.EXPECT().FindByID(gomock.Any(), int64(9)).Return(&domain.User{ID: 9}, nil)
+	revocations.EXPECT().RevokeUser(gomock.Any(), int64(9), fixedNow, fixedNow.Add(15*time.Minute)).Return(nil)
+	refreshRepo.EXPECT().RevokeByUser(gomock.Any(), int64(9), fixedNow).Return(nil)
+	apiKeys.EXPECT().RevokeByUser(gomock.Any(), int64(9), fixedNow).Return(nil)
+
+	uc := app.RevokeUserTokensUseCase{
+		UserRepo:      userRepo,
+		Revocations:   revocations,
+		RefreshTokens: refreshRepo,
+		APIKeys:       apiKeys,
+		Now:           func() time.Time { return fixedNow },
+		TTL:           15 * time.Minute,
+	}
+
+	// Act
+	err := uc.Execute(context.Background(), 9)
+
+	// Assert
+	require.NoError(t, err)
+}
+
+func TestUC16RevokeUserTokens_StoreError_WhenRevokeUserFails(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	revocations := mocks.NewMockTokenRevocationStore(ctrl)
+
+	userRepo.EXPECT().FindByID(gomock.Any(), int64(9)).Return(&domain.User{ID: 9}, nil)
+	revocations.EXPECT().RevokeUser(gomock.Any(), int64(9), gomock.Any(), gomock.Any()).Return(errors.New("db_error"))
+
+	uc := app.RevokeUserTokensUseCase{
+		UserRepo:      userRepo,
+		Revocations:   revocations,
+		RefreshTokens: mocks.NewMockRefreshTokenRepository(ctrl),
+	}
+
+	// Act
+	err := uc.Execute(context.Background(), 9)
+
+	// Assert
+	require.EqualError(t, err, "db_error")
+}
@@ -0,0 +1,238 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/moondolphin/crypto-api/domain"
+)
+
+var (
+	ErrInvalidAPIKeyName = errors.New("invalid_api_key_name")
+	ErrInvalidScopes     = errors.New("invalid_scopes")
+	ErrInvalidExpiry     = errors.New("invalid_expires_at")
+	ErrAPIKeyNotFound    = errors.New("api_key_not_found")
+	ErrInvalidAPIKey     = errors.New("invalid_api_key")
+)
+
+// apiKeyPrefix identifica las keys de esta API (útil para secret scanners).
+const apiKeyPrefix = "cak_"
+
+type CreateAPIKeyInput struct {
+	Name      string     `json:"name"`
+	Scopes    []string   `json:"scopes"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+type APIKeyOutput struct {
+	ID         int64      `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+}
+
+// CreatedAPIKeyOutput es la única respuesta que incluye la key en claro.
+type CreatedAPIKeyOutput struct {
+	APIKeyOutput
+	Key string `json:"key"`
+}
+
+func toAPIKeyOutput(k domain.APIKey) APIKeyOutput {
+	return APIKeyOutput{
+		ID:         k.ID,
+		Name:       k.Name,
+		Prefix:     k.Prefix,
+		Scopes:     k.Scopes,
+		ExpiresAt:  k.ExpiresAt,
+		CreatedAt:  k.CreatedAt,
+		LastUsedAt: k.LastUsedAt,
+	}
+}
+
+type CreateAPIKeyUseCase struct {
+	Repo   domain.APIKeyRepository
+	Opaque domain.OpaqueTokenService
+	Now    func() time.Time
+}
+
+func (uc CreateAPIKeyUseCase) Execute(ctx context.Context, userID int64, in CreateAPIKeyInput) (CreatedAPIKeyOutput, error) {
+	name := strings.TrimSpace(in.Name)
+	if name == "" || len(name) > 100 {
+		return CreatedAPIKeyOutput{}, ErrInvalidAPIKeyName
+	}
+
+	scopes, err := normalizeScopes(in.Scopes)
+	if err != nil {
+		return CreatedAPIKeyOutput{}, err
+	}
+
+	now := uc.Now
+	if now == nil {
+		now = time.Now
+	}
+	t := now().UTC()
+
+	var expiresAt *time.Time
+	if in.ExpiresAt != nil {
+		exp := in.ExpiresAt.UTC()
+		if !exp.After(t) {
+			return CreatedAPIKeyOutput{}, ErrInvalidExpiry
+		}
+		expiresAt = &exp
+	}
+
+	secret, err := uc.Opaque.Generate()
+	if err != nil {
+		return CreatedAPIKeyOutput{}, err
+	}
+	raw := apiKeyPrefix + secret
+
+	prefix := raw
+	if len(prefix) > 12 {
+		prefix = prefix[:12]
+	}
+
+	k, err := uc.Repo.Create(ctx, domain.APIKey{
+		UserID:    userID,
+		Name:      name,
+		Prefix:    prefix,
+		KeyHash:   uc.Opaque.Hash(raw),
+		Scopes:    scopes,
+		ExpiresAt: expiresAt,
+		CreatedAt: t,
+	})
+	if err != nil {
+		return CreatedAPIKeyOutput{}, err
+	}
+
+	return CreatedAPIKeyOutput{APIKeyOutput: toAPIKeyOutput(k), Key: raw}, nil
+}
+
+// normalizeScopes valida, deduplica y ordena.
+func normalizeScopes(in []string) ([]string, error) {
+	seen := make(map[string]bool, len(in))
+	out := make([]string, 0, len(in))
+	for _, s := range in {
+		s = strings.ToLower(strings.TrimSpace(s))
+		if !domain.IsValidScope(s) {
+			return nil, ErrInvalidScopes
+		}
+		if !seen[s] {
+			seen[s] = true
+			out = append(out, s)
+		}
+	}
+	if len(out) == 0 {
+		return nil, ErrInvalidScopes
+	}
+	sort.Strings(out)
+	return out, nil
+}
+
+type ListAPIKeysUseCase struct {
+	Repo domain.APIKeyRepository
+}
+
+func (uc ListAPIKeysUseCase) Execute(ctx context.Context, userID int64) ([]APIKeyOutput, error) {
+	keys, err := uc.Repo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]APIKeyOutput, 0, len(keys))
+	for _, k := range keys {
+		out = append(out, toAPIKeyOutput(k))
+	}
+	return out, nil
+}
+
+type RevokeAPIKeyUseCase struct {
+	Repo domain.APIKeyRepository
+	Now  func() time.Time
+}
+
+func (uc RevokeAPIKeyUseCase) Execute(ctx context.Context, userID, keyID int64) error {
+	if keyID <= 0 {
+		return ErrBadRequest
+	}
+
+	now := uc.Now
+	if now == nil {
+		now = time.Now
+	}
+
+	ok, err := uc.Repo.Revoke(ctx, userID, keyID, now().UTC())
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrAPIKeyNotFound
+	}
+	return nil
+}
+
+// AuthenticatedAPIKey es lo que el middleware necesita para armar el contexto de auth.
+type AuthenticatedAPIKey struct {
+	Key  domain.APIKey
+	User domain.User
+}
+
+// AuthenticateAPIKeyUseCase valida el header X-API-Key y registra el último uso.
+type AuthenticateAPIKeyUseCase struct {
+	Repo     domain.APIKeyRepository
+	UserRepo domain.UserRepository
+	Opaque   domain.OpaqueTokenService
+	Now      func() time.Time
+
+	// last_used_at se escribe como mucho una vez por este intervalo (evita un UPDATE por request)
+	TouchEvery time.Duration
+}
+
+func (uc AuthenticateAPIKeyUseCase) Execute(ctx context.Context, raw string) (AuthenticatedAPIKey, error) {
+	raw = strings.TrimSpace(raw)
+	if !strings.HasPrefix(raw, apiKeyPrefix) {
+		return AuthenticatedAPIKey{}, ErrInvalidAPIKey
+	}
+
+	k, err := uc.Repo.FindByHash(ctx, uc.Opaque.Hash(raw))
+	if err != nil {
+		return AuthenticatedAPIKey{}, err
+	}
+
+	now := uc.Now
+	if now == nil {
+		now = time.Now
+	}
+	t := now().UTC()
+
+	if k == nil || k.RevokedAt != nil || k.IsExpired(t) {
+		return AuthenticatedAPIKey{}, ErrInvalidAPIKey
+	}
+
+	u, err := uc.UserRepo.FindByID(ctx, k.UserID)
+	if err != nil {
+		return AuthenticatedAPIKey{}, err
+	}
+	if u == nil {
+		return AuthenticatedAPIKey{}, ErrInvalidAPIKey
+	}
+
+	every := uc.TouchEvery
+	if every <= 0 {
+		every = time.Minute
+	}
+	if k.LastUsedAt == nil || t.Sub(*k.LastUsedAt) >= every {
+		if err := uc.Repo.TouchLastUsed(ctx, k.ID, t); err != nil {
+			return AuthenticatedAPIKey{}, err
+		}
+		k.LastUsedAt = &t
+	}
+
+	return AuthenticatedAPIKey{Key: *k, User: *u}, nil
+}
@@ -0,0 +1,347 @@
+package app_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/moondolphin/crypto-api/app"
+	"github.com/moondolphin/crypto-api/domain"
+	"github.com/moondolphin/crypto-api/test/mocks"
+)
+
+func TestUC17CreateAPIKey_InvalidName_WhenEmpty(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	uc := app.CreateAPIKeyUseCase{
+		Repo:   mocks.NewMockAPIKeyRepository(ctrl),
+		Opaque: mocks.NewMockOpaqueTokenService(ctrl),
+	}
+
+	// Act
+	_, err := uc.Execute(context.Background(), 1, app.CreateAPIKeyInput{Name: " ", Scopes: []string{domain.ScopeQuotesRead}})
+
+	// Assert
+	require.ErrorIs(t, err, app.ErrInvalidAPIKeyName)
+}
+
+func TestUC17CreateAPIKey_InvalidScopes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	uc := app.CreateAPIKeyUseCase{
+		Repo:   mocks.NewMockAPIKeyRepository(ctrl),
+		Opaque: mocks.NewMockOpaqueTokenService(ctrl),
+	}
+
+	for _, scopes := range [][]string{nil, {}, {"admin"}, {domain.ScopeQuotesRead, "coins:write"}} {
+		// Act
+		_, err := uc.Execute(context.Background(), 1, app.CreateAPIKeyInput{Name: "ci", Scopes: scopes})
+
+		// Assert
+		require.ErrorIs(t, err, app.ErrInvalidScopes, "%v", scopes)
+	}
+}
+
+func TestUC17CreateAPIKey_InvalidExpiry_WhenInThePast(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fixedNow := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+	past := fixedNow.Add(-time.Hour)
+
+	uc := app.CreateAPIKeyUseCase{
+		Repo:   mocks.NewMockAPIKeyRepository(ctrl),
+		Opaque: mocks.NewMockOpaqueTokenService(ctrl),
+		Now:    func() time.Time { return fixedNow },
+	}
+
+	// Act
+	_, err := uc.Execute(context.Background(), 1, app.CreateAPIKeyInput{
+		Name:      "ci",
+		Scopes:    []string{domain.ScopeQuotesRead},
+		ExpiresAt: &past,
+	})
+
+	// Assert
+	require.ErrorIs(t, err, app.ErrInvalidExpiry)
+}
+
+func TestUC17CreateAPIKey_Success_StoresHashAndReturnsKeyOnce(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fixedNow := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+	exp := fixedNow.Add(24 * time.Hour)
+
+	repo := mocks.NewMockAPIKeyRepository(ctrl)
+	opaque := mocks.NewMockOpaqueTokenService(ctrl)
+
+	opaque.EXPECT().Generate().Return("abcdefghijklmnop", nil)
+	opaque.EXPECT().Hash("cak_abcdefghijklmnop").Return("hashed")
+	repo.EXPECT().
+		Create(gomock.Any(), domain.APIKey{
+			UserID:    3,
+			Name:      "ci",
+			Prefix:    "cak_abcdefgh",
+			KeyHash:   "hashed",
+			Scopes:    []string{domain.ScopeFavoritesRead, domain.ScopeQuotesRead},
+			ExpiresAt: &exp,
+			CreatedAt: fixedNow,
+		}).
+		DoAndReturn(func(ctx context.Context, k domain.APIKey) (domain.APIKey, error) {
+			k.ID = 11
+			return k, nil
+		})
+
+	uc := app.CreateAPIKeyUseCase{
+		Repo:   repo,
+		Opaque: opaque,
+		Now:    func() time.Time { return fixedNow },
+	}
+
+	// Act
+	out, err := uc.Execute(context.Background(), 3, app.CreateAPIKeyInput{
+		Name:      " ci ",
+		Scopes:    []string{"quotes:read", "FAVORITES:READ", "quotes:read"},
+		ExpiresAt: &exp,
+	})
+
+	// Assert
+	require.NoError(t, err)
+	require.Equal(t, int64(11), out.ID)
+	require.Equal(t, "cak_abcdefghijklmnop", out.Key)
+	require.Equal(t, "cak_abcdefgh", out.Prefix)
+	require.Equal(t, []string{domain.ScopeFavoritesRead, domain.ScopeQuotesRead}, out.Scopes)
+}
+
+func TestUC17ListAPIKeys_Success_MapsWithoutSecrets(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	created := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	repo := mocks.NewMockAPIKeyRepository(ctrl)
+	repo.EXPECT().ListByUser(gomock.Any(), int64(3)).Return([]domain.APIKey{
+		{ID: 1, UserID: 3, Name: "ci", Prefix: "cak_aaaa", KeyHash: "h", Scopes: []string{domain.ScopeQuotesRead}, CreatedAt: created},
+	}, nil)
+
+	uc := app.ListAPIKeysUseCase{Repo: repo}
+
+	// Act
+	out, err := uc.Execute(context.Background(), 3)
+
+	// Assert
+	require.NoError(t, err)
+	require.Equal(t, []app.APIKeyOutput{
+		{ID: 1, Name: "ci", Prefix: "cak_aaaa", Scopes: []string{domain.ScopeQuotesRead}, CreatedAt: created},
+	}, out)
+}
+
+func TestUC17RevokeAPIKey_NotFound_WhenNotOwnedOrAlreadyRevoked(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockAPIKeyRepository(ctrl)
+	repo.EXPECT().Revoke(gomock.Any(), int64(3), int64(5), gomock.Any()).Return(false, nil)
+
+	uc := app.RevokeAPIKeyUseCase{Repo: repo}
+
+	// Act
+	err := uc.Execute(context.Background(), 3, 5)
+
+	// Assert
+	require.ErrorIs(t, err, app.ErrAPIKeyNotFound)
+}
+
+func TestUC17RevokeAPIKey_Success(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockAPIKeyRepository(ctrl)
+	repo.EXPECT().Revoke(gomock.Any(), int64(3), int64(5), gomock.Any()).Return(true, nil)
+
+	uc := app.RevokeAPIKeyUseCase{Repo: repo}
+
+	// Act
+	err := uc.Execute(context.Background(), 3, 5)
+
+	// Assert
+	require.NoError(t, err)
+}
+
+func TestUC17AuthenticateAPIKey_Invalid_WhenWrongFormat(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fixedNow := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	repo := mocks.NewMockAPIKeyRepository(ctrl)
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	opaque := mocks.NewMockOpaqueTokenService(ctrl)
+
+	uc := app.AuthenticateAPIKeyUseCase{
+		Repo:       repo,
+		UserRepo:   userRepo,
+		Opaque:     opaque,
+		Now:        func() time.Time { return fixedNow },
+		TouchEvery: time.Minute,
+	}
+
+	// Act
+	_, err := uc.Execute(context.Background(), "not-a-key")
+
+	// Assert
+	require.ErrorIs(t, err, app.ErrInvalidAPIKey)
+}
+
+func TestUC17AuthenticateAPIKey_Invalid_WhenRevokedOrExpired(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fixedNow := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	repo := mocks.NewMockAPIKeyRepository(ctrl)
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	opaque := mocks.NewMockOpaqueTokenService(ctrl)
+
+	uc := app.AuthenticateAPIKeyUseCase{
+		Repo:       repo,
+		UserRepo:   userRepo,
+		Opaque:     opaque,
+		Now:        func() time.Time { return fixedNow },
+		TouchEvery: time.Minute,
+	}
+
+	revoked := fixedNow.Add(-time.Hour)
+	expired := fixedNow
+
+	for _, k := range []domain.APIKey{
+		{ID: 1, UserID: 3, RevokedAt: &revoked},
+		{ID: 2, UserID: 3, ExpiresAt: &expired},
+	} {
+		// Arrange
+		opaque.EXPECT().Hash("cak_x").Return("h")
+		repo.EXPECT().FindByHash(gomock.Any(), "h").Return(&k, nil)
+
+		// Act
+		_, err := uc.Execute(context.Background(), "cak_x")
+
+		// Assert
+		require.ErrorIs(t, err, app.ErrInvalidAPIKey)
+	}
+}
+
+func TestUC17AuthenticateAPIKey_Success_TouchesLastUsedWhenStale(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fixedNow := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	repo := mocks.NewMockAPIKeyRepository(ctrl)
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	opaque := mocks.NewMockOpaqueTokenService(ctrl)
+
+	stale := fixedNow.Add(-2 * time.Minute)
+
+	opaque.EXPECT().Hash("cak_x").Return("h")
+	repo.EXPECT().
+		FindByHash(gomock.Any(), "h").
+		Return(&domain.APIKey{ID: 1, UserID: 3, Scopes: []string{domain.ScopeQuotesRead}, LastUsedAt: &stale}, nil)
+	userRepo.EXPECT().FindByID(gomock.Any(), int64(3)).Return(&domain.User{ID: 3, Email: "bot@example.com", Role: domain.RoleUser}, nil)
+	repo.EXPECT().TouchLastUsed(gomock.Any(), int64(1), fixedNow).Return(nil)
+
+	uc := app.AuthenticateAPIKeyUseCase{
+		Repo:       repo,
+		UserRepo:   userRepo,
+		Opaque:     opaque,
+		Now:        func() time.Time { return fixedNow },
+		TouchEvery: time.Minute,
+	}
+
+	// Act
+	out, err := uc.Execute(context.Background(), "cak_x")
+
+	// Assert
+	require.NoError(t, err)
+	require.Equal(t, int64(3), out.User.ID)
+	require.Equal(t, fixedNow, *out.Key.LastUsedAt)
+}
+
+func TestUC17AuthenticateAPIKey_Success_SkipsTouchWhenRecent(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fixedNow := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	repo := mocks.NewMockAPIKeyRepository(ctrl)
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	opaque := mocks.NewMockOpaqueTokenService(ctrl)
+
+	recent := fixedNow.Add(-10 * time.Second)
+
+	opaque.EXPECT().Hash("cak_x").Return("h")
+	repo.EXPECT().
+		FindByHash(gomock.Any(), "h").
+		Return(&domain.APIKey{ID: 1, UserID: 3, LastUsedAt: &recent}, nil)
+	userRepo.EXPECT().FindByID(gomock.Any(), int64(3)).Return(&domain.User{ID: 3, Email: "bot@example.com"}, nil)
+
+	uc := app.AuthenticateAPIKeyUseCase{
+		Repo:       repo,
+		UserRepo:   userRepo,
+		Opaque:     opaque,
+		Now:        func() time.Time { return fixedNow },
+		TouchEvery: time.Minute,
+	}
+
+	// Act
+	_, err := uc.Execute(context.Background(), "cak_x")
+
+	// Assert
+	require.NoError(t, err)
+}
+
+func TestUC17AuthenticateAPIKey_RepoError_WhenTouchFails(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fixedNow := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	repo := mocks.NewMockAPIKeyRepository(ctrl)
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	opaque := mocks.NewMockOpaqueTokenService(ctrl)
+
+	opaque.EXPECT().Hash("cak_x").Return("h")
+	repo.EXPECT().FindByHash(gomock.Any(), "h").Return(&domain.APIKey{ID: 1, UserID: 3}, nil)
+	userRepo.EXPECT().FindByID(gomock.Any(), int64(3)).Return(&domain.User{ID: 3, Email: "bot@example.com"}, nil)
+	repo.EXPECT().TouchLastUsed(gomock.Any(), int64(1), fixedNow).Return(errors.New("db_error"))
+
+	uc := app.AuthenticateAPIKeyUseCase{
+		Repo:       repo,
+		UserRepo:   userRepo,
+		Opaque:     opaque,
+		Now:        func() time.Time { return fixedNow },
+		TouchEvery: time.Minute,
+	}
+
+	// Act
+	_, err := uc.Execute(context.Background(), "cak_x")
+
+	// Assert
+	require.EqualError(t, err, "db_error")
+}
@@ -0,0 +1,292 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/moondolphin/crypto-api/domain"
+)
+
+var (
+	ErrInvalidResetToken    = errors.New("invalid_reset_token")
+	ErrTooManyResetRequests = errors.New("too_many_reset_requests")
+)
+
+const (
+	defaultPasswordResetTTL = 30 * time.Minute
+
+	defaultMaxResetsPerEmail = 3
+	defaultMaxResetsPerIP    = 10
+	defaultResetWindow       = 15 * time.Minute
+)
+
+type RequestPasswordResetInput struct {
+	Email    string `json:"email"`
+	ClientIP string `json:"-"`
+}
+
+// RequestPasswordResetUseCase manda por mail un link de reset de un solo uso.
+// Para no revelar qué emails están registrados, un email desconocido no es error
+// y el token se guarda y el mail se manda en segundo plano: ni el status ni la
+// demora de la base o del SMTP distinguen una cuenta existente.
+type RequestPasswordResetUseCase struct {
+	UserRepo domain.UserRepository
+	Resets   domain.PasswordResetRepository
+	Opaque   domain.OpaqueTokenService
+	Mailer   domain.Mailer
+	Now      func() time.Time
+	TTL      time.Duration
+
+	// URL del front que recibe ?token=...
+	ResetURL string
+
+	// opcional: sin Throttle no hay límite de pedidos
+	Throttle *PasswordResetThrottle
+}
+
+// Execute devuelve cuánto falta si el email o la IP superaron su límite de pedidos.
+func (uc RequestPasswordResetUseCase) Execute(ctx context.Context, in RequestPasswordResetInput) (retryAfter time.Duration, err error) {
+	email := strings.ToLower(strings.TrimSpace(in.Email))
+	if !strings.Contains(email, "@") {
+		return 0, ErrInvalidEmail
+	}
+
+	now := uc.Now
+	if now == nil {
+		now = time.Now
+	}
+	t := now().UTC()
+
+	// se cuenta antes de buscar al usuario: los emails inexistentes también consumen el límite
+	if uc.Throttle != nil {
+		if retryAfter, err := uc.Throttle.Allow(ctx, email, in.ClientIP, t); err != nil {
+			return retryAfter, err
+		}
+	}
+
+	u, err := uc.UserRepo.FindByEmail(ctx, email)
+	if err != nil {
+		return 0, err
+	}
+	if u == nil {
+		return 0, nil
+	}
+
+	// todo el trabajo de una cuenta existente va en segundo plano: ni las
+	// escrituras del token ni el SMTP alargan la respuesta
+	go uc.issue(context.WithoutCancel(ctx), *u, t)
+
+	return 0, nil
+}
+
+// issue invalida los links anteriores, guarda el token nuevo y manda el mail.
+// Los errores sólo se loguean: devolverlos diferenciaría las cuentas existentes.
+func (uc RequestPasswordResetUseCase) issue(ctx context.Context, u domain.User, t time.Time) {
+	ttl := uc.TTL
+	if ttl <= 0 {
+		ttl = defaultPasswordResetTTL
+	}
+
+	// sólo vale el último link pedido
+	if err := uc.Resets.InvalidateByUser(ctx, u.ID, t); err != nil {
+		log.Printf("Warning: failed to invalidate password reset tokens: %v", err)
+		return
+	}
+
+	raw, err := uc.Opaque.Generate()
+	if err != nil {
+		log.Printf("Warning: failed to generate password reset token: %v", err)
+		return
+	}
+
+	_, err = uc.Resets.Create(ctx, domain.PasswordResetToken{
+		UserID:    u.ID,
+		TokenHash: uc.Opaque.Hash(raw),
+		ExpiresAt: t.Add(ttl),
+		CreatedAt: t,
+	})
+	if err != nil {
+		log.Printf("Warning: failed to store password reset token: %v", err)
+		return
+	}
+
+	link, err := withTokenParam(uc.ResetURL, raw)
+	if err != nil {
+		log.Printf("Warning: failed to build password reset link: %v", err)
+		return
+	}
+
+	msg := domain.EmailMessage{
+		To:      u.Email,
+		Subject: "Restablecer contraseña",
+		Body: fmt.Sprintf(
+			"Hola %s,\n\nPara elegir una contraseña nueva entrá a:\n\n%s\n\nEl link vence en %d minutos y sirve una sola vez. Si no lo pediste, ignorá este mail.\n",
+			u.Name, link, int(ttl.Minutes()),
+		),
+	}
+
+	if err := uc.Mailer.Send(ctx, msg); err != nil {
+		log.Printf("Warning: failed to send password reset email: %v", err)
+	}
+}
+
+// PasswordResetThrottle limita los pedidos de reset por email y por IP. Al
+// llegar al máximo dentro de Window la clave queda bloqueada por Window.
+// Comparte el store con LoginThrottle usando claves propias.
+type PasswordResetThrottle struct {
+	Store domain.LoginAttemptStore
+
+	MaxPerEmail int
+	MaxPerIP    int
+	Window      time.Duration
+}
+
+// Allow registra el pedido, o devuelve ErrTooManyResetRequests y cuánto falta
+// si el email o la IP están bloqueados.
+func (t PasswordResetThrottle) Allow(ctx context.Context, email, ip string, now time.Time) (time.Duration, error) {
+	window := t.Window
+	if window <= 0 {
+		window = defaultResetWindow
+	}
+
+	keys := []string{"reset:" + accountAttemptKey(email)}
+	if ip != "" {
+		keys = append(keys, "reset:"+ipAttemptKey(ip))
+	}
+
+	var retryAfter time.Duration
+	for _, key := range keys {
+		a, err := t.Store.Get(ctx, key)
+		if err != nil {
+			return 0, err
+		}
+		if a != nil && a.IsLocked(now) {
+			if d := a.LockedUntil.Sub(now); d > retryAfter {
+				retryAfter = d
+			}
+		}
+	}
+	if retryAfter > 0 {
+		return retryAfter, ErrTooManyResetRequests
+	}
+
+	for _, key := range keys {
+		requests, err := t.Store.RecordFailure(ctx, key, now, now.Add(-window))
+		if err != nil {
+			return 0, err
+		}
+		if requests < t.max(key) {
+			continue
+		}
+		if err := t.Store.Lock(ctx, key, now.Add(window)); err != nil {
+			return 0, err
+		}
+	}
+	return 0, nil
+}
+
+func (t PasswordResetThrottle) max(key string) int {
+	if strings.HasPrefix(key, "reset:ip:") {
+		if t.MaxPerIP > 0 {
+			return t.MaxPerIP
+		}
+		return defaultMaxResetsPerIP
+	}
+	if t.MaxPerEmail > 0 {
+		return t.MaxPerEmail
+	}
+	return defaultMaxResetsPerEmail
+}
+
+// withTokenParam agrega token=<raw> a la query de base respetando la que ya tenga.
+func withTokenParam(base, raw string) (string, error) {
+	u, err := url.Parse(base)
+	if err != nil {
+		return "", err
+	}
+	q := u.Query()
+	q.Set("token", raw)
+	u.RawQuery = q.Encode()
+	return u.String(), nil
+}
+
+type ConfirmPasswordResetInput struct {
+	Token       string `json:"token"`
+	NewPassword string `json:"new_password"`
+}
+
+// ConfirmPasswordResetUseCase consume el token, cambia la contraseña y cierra
+// todas las sesiones abiertas del usuario, API keys incluidas.
+type ConfirmPasswordResetUseCase struct {
+	UserRepo      domain.UserRepository
+	Resets        domain.PasswordResetRepository
+	Opaque        domain.OpaqueTokenService
+	Hasher        domain.PasswordHasher
+	RefreshTokens domain.RefreshTokenRepository // opcional
+	APIKeys       domain.APIKeyRepository       // opcional
+	Revocations   domain.TokenRevocationStore   // opcional
+	Now           func() time.Time
+
+	// vida máxima de un access token, para el corte en la denylist
+	TTL time.Duration
+}
+
+func (uc ConfirmPasswordResetUseCase) Execute(ctx context.Context, in ConfirmPasswordResetInput) error {
+	raw := strings.TrimSpace(in.Token)
+	if raw == "" {
+		return ErrBadRequest
+	}
+	if len(in.NewPassword) < 8 {
+		return ErrInvalidPassword
+	}
+
+	now := uc.Now
+	if now == nil {
+		now = time.Now
+	}
+	t := now().UTC()
+
+	rt, err := uc.Resets.FindByHash(ctx, uc.Opaque.Hash(raw))
+	if err != nil {
+		return err
+	}
+	if rt == nil || rt.IsUsed() || rt.IsExpired(t) {
+		return ErrInvalidResetToken
+	}
+
+	used, err := uc.Resets.MarkUsed(ctx, rt.ID, t)
+	if err != nil {
+		return err
+	}
+	if !used {
+		return ErrInvalidResetToken
+	}
+
+	u, err := uc.UserRepo.FindByID(ctx, rt.UserID)
+	if err != nil {
+		return err
+	}
+	if u == nil {
+		return ErrInvalidResetToken
+	}
+
+	hash, err := uc.Hasher.Hash(in.NewPassword)
+	if err != nil {
+		return err
+	}
+	if err := uc.UserRepo.UpdatePassword(ctx, u.ID, hash); err != nil {
+		return err
+	}
+
+	// cualquier otro link pendiente deja de servir
+	if err := uc.Resets.InvalidateByUser(ctx, u.ID, t); err != nil {
+		return err
+	}
+
+	return revokeSessions(ctx, uc.RefreshTokens, uc.APIKeys, uc.Revocations, u.ID, t, uc.TTL)
+}
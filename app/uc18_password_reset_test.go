@@ -0,0 +1,350 @@
+package app_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/moondolphin/crypto-api/app"
+	"github.com/moondolphin/crypto-api/domain"
+	"github.com/moondolphin/crypto-api/test/mocks"
+)
+
+func TestUC18RequestPasswordReset_InvalidEmail(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	uc := app.RequestPasswordResetUseCase{UserRepo: mocks.NewMockUserRepository(ctrl)}
+
+	// Act
+	_, err := uc.Execute(context.Background(), app.RequestPasswordResetInput{Email: "nope"})
+
+	// Assert
+	require.ErrorIs(t, err, app.ErrInvalidEmail)
+}
+
+func TestUC18RequestPasswordReset_UnknownEmail_IsSilent(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	userRepo.EXPECT().FindByEmail(gomock.Any(), "ghost@example.com").Return(nil, nil)
+
+	// sin expectativas: no se crea token ni se manda mail
+	uc := app.RequestPasswordResetUseCase{
+		UserRepo: userRepo,
+		Resets:   mocks.NewMockPasswordResetRepository(ctrl),
+		Opaque:   mocks.NewMockOpaqueTokenService(ctrl),
+		Mailer:   mocks.NewMockMailer(ctrl),
+	}
+
+	// Act
+	_, err := uc.Execute(context.Background(), app.RequestPasswordResetInput{Email: " Ghost@Example.com "})
+
+	// Assert
+	require.NoError(t, err)
+}
+
+func TestUC18RequestPasswordReset_Success_StoresHashAndMailsLink(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fixedNow := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	resets := mocks.NewMockPasswordResetRepository(ctrl)
+	opaque := mocks.NewMockOpaqueTokenService(ctrl)
+	mailer := mocks.NewMockMailer(ctrl)
+	sent := make(chan struct{})
+
+	userRepo.EXPECT().FindByEmail(gomock.Any(), "a@b.com").
+		Return(&domain.User{ID: 7, Email: "a@b.com", Name: "Ana"}, nil)
+
+	gomock.InOrder(
+		resets.EXPECT().InvalidateByUser(gomock.Any(), int64(7), fixedNow).Return(nil),
+		opaque.EXPECT().Generate().Return("raw-token", nil),
+		opaque.EXPECT().Hash("raw-token").Return("hashed"),
+		resets.EXPECT().Create(gomock.Any(), domain.PasswordResetToken{
+			UserID:    7,
+			TokenHash: "hashed",
+			ExpiresAt: fixedNow.Add(15 * time.Minute),
+			CreatedAt: fixedNow,
+		}).Return(domain.PasswordResetToken{ID: 1}, nil),
+		mailer.EXPECT().Send(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, msg domain.EmailMessage) error {
+				require.Equal(t, "a@b.com", msg.To)
+				require.Contains(t, msg.Body, "https://app.example.com/reset?lang=es&token=raw-token")
+				require.Contains(t, msg.Body, "15 minutos")
+				close(sent)
+				return nil
+			}),
+	)
+
+	uc := app.RequestPasswordResetUseCase{
+		UserRepo: userRepo,
+		Resets:   resets,
+		Opaque:   opaque,
+		Mailer:   mailer,
+		Now:      func() time.Time { return fixedNow },
+		TTL:      15 * time.Minute,
+		ResetURL: "https://app.example.com/reset?lang=es",
+	}
+
+	// Act
+	_, err := uc.Execute(context.Background(), app.RequestPasswordResetInput{Email: "A@B.com"})
+
+	// Assert
+	require.NoError(t, err)
+	<-sent
+}
+
+func TestUC18RequestPasswordReset_MailerError_IsNotReturned(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	resets := mocks.NewMockPasswordResetRepository(ctrl)
+	opaque := mocks.NewMockOpaqueTokenService(ctrl)
+	mailer := mocks.NewMockMailer(ctrl)
+
+	userRepo.EXPECT().FindByEmail(gomock.Any(), "a@b.com").Return(&domain.User{ID: 7, Email: "a@b.com"}, nil)
+	resets.EXPECT().InvalidateByUser(gomock.Any(), int64(7), gomock.Any()).Return(nil)
+	opaque.EXPECT().Generate().Return("raw", nil)
+	opaque.EXPECT().Hash("raw").Return("h")
+	resets.EXPECT().Create(gomock.Any(), gomock.Any()).Return(domain.PasswordResetToken{ID: 1}, nil)
+	sent := make(chan struct{})
+	mailer.EXPECT().Send(gomock.Any(), gomock.Any()).DoAndReturn(func(context.Context, domain.EmailMessage) error {
+		close(sent)
+		return errors.New("smtp down")
+	})
+
+	uc := app.RequestPasswordResetUseCase{UserRepo: userRepo, Resets: resets, Opaque: opaque, Mailer: mailer}
+
+	// Act
+	_, err := uc.Execute(context.Background(), app.RequestPasswordResetInput{Email: "a@b.com"})
+
+	// Assert: misma respuesta que un email desconocido
+	require.NoError(t, err)
+	<-sent
+}
+
+func TestUC18RequestPasswordReset_StoreError_IsNotReturnedAndSendsNoMail(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	resets := mocks.NewMockPasswordResetRepository(ctrl)
+	opaque := mocks.NewMockOpaqueTokenService(ctrl)
+	mailer := mocks.NewMockMailer(ctrl)
+
+	userRepo.EXPECT().FindByEmail(gomock.Any(), "a@b.com").Return(&domain.User{ID: 7, Email: "a@b.com"}, nil)
+	resets.EXPECT().InvalidateByUser(gomock.Any(), int64(7), gomock.Any()).Return(nil)
+	opaque.EXPECT().Generate().Return("raw", nil)
+	opaque.EXPECT().Hash("raw").Return("h")
+	stored := make(chan struct{})
+	resets.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(context.Context, domain.PasswordResetToken) (domain.PasswordResetToken, error) {
+			close(stored)
+			return domain.PasswordResetToken{}, errors.New("db down")
+		})
+
+	uc := app.RequestPasswordResetUseCase{UserRepo: userRepo, Resets: resets, Opaque: opaque, Mailer: mailer}
+
+	// Act
+	_, err := uc.Execute(context.Background(), app.RequestPasswordResetInput{Email: "a@b.com"})
+
+	// Assert: la falla de la base tampoco llega a la respuesta
+	require.NoError(t, err)
+	<-stored
+}
+
+func TestUC18RequestPasswordReset_Throttled_ReturnsRetryAfter(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+	until := now.Add(5 * time.Minute)
+
+	store := mocks.NewMockLoginAttemptStore(ctrl)
+	store.EXPECT().Get(gomock.Any(), "reset:account:a@b.com").Return(&domain.LoginAttempt{LockedUntil: &until}, nil)
+	store.EXPECT().Get(gomock.Any(), "reset:ip:10.0.0.1").Return(nil, nil)
+
+	// sin expectativas en UserRepo: ni se busca al usuario
+	uc := app.RequestPasswordResetUseCase{
+		UserRepo: mocks.NewMockUserRepository(ctrl),
+		Now:      func() time.Time { return now },
+		Throttle: &app.PasswordResetThrottle{Store: store},
+	}
+
+	// Act
+	retryAfter, err := uc.Execute(context.Background(), app.RequestPasswordResetInput{Email: "A@b.com", ClientIP: "10.0.0.1"})
+
+	// Assert
+	require.ErrorIs(t, err, app.ErrTooManyResetRequests)
+	require.Equal(t, 5*time.Minute, retryAfter)
+}
+
+func TestUC18RequestPasswordReset_UnknownEmail_CountsAndLocksAtLimit(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+	window := 10 * time.Minute
+
+	store := mocks.NewMockLoginAttemptStore(ctrl)
+	store.EXPECT().Get(gomock.Any(), gomock.Any()).Return(nil, nil).Times(2)
+	store.EXPECT().RecordFailure(gomock.Any(), "reset:account:ghost@example.com", now, now.Add(-window)).Return(2, nil)
+	store.EXPECT().Lock(gomock.Any(), "reset:account:ghost@example.com", now.Add(window)).Return(nil)
+	store.EXPECT().RecordFailure(gomock.Any(), "reset:ip:10.0.0.1", now, now.Add(-window)).Return(2, nil)
+
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	userRepo.EXPECT().FindByEmail(gomock.Any(), "ghost@example.com").Return(nil, nil)
+
+	uc := app.RequestPasswordResetUseCase{
+		UserRepo: userRepo,
+		Now:      func() time.Time { return now },
+		Throttle: &app.PasswordResetThrottle{Store: store, MaxPerEmail: 2, MaxPerIP: 5, Window: window},
+	}
+
+	// Act
+	retryAfter, err := uc.Execute(context.Background(), app.RequestPasswordResetInput{Email: "ghost@example.com", ClientIP: "10.0.0.1"})
+
+	// Assert: el pedido que llega al límite pasa; el bloqueo aplica al siguiente
+	require.NoError(t, err)
+	require.Zero(t, retryAfter)
+}
+
+func TestUC18ConfirmPasswordReset_Validation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	uc := app.ConfirmPasswordResetUseCase{}
+
+	err := uc.Execute(context.Background(), app.ConfirmPasswordResetInput{Token: " ", NewPassword: "password123"})
+	require.ErrorIs(t, err, app.ErrBadRequest)
+
+	err = uc.Execute(context.Background(), app.ConfirmPasswordResetInput{Token: "raw", NewPassword: "short"})
+	require.ErrorIs(t, err, app.ErrInvalidPassword)
+}
+
+func TestUC18ConfirmPasswordReset_InvalidToken(t *testing.T) {
+	fixedNow := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+	usedAt := fixedNow.Add(-time.Minute)
+
+	cases := map[string]*domain.PasswordResetToken{
+		"missing": nil,
+		"used":    {ID: 1, UserID: 7, ExpiresAt: fixedNow.Add(time.Hour), UsedAt: &usedAt},
+		"expired": {ID: 1, UserID: 7, ExpiresAt: fixedNow},
+	}
+
+	for name, stored := range cases {
+		t.Run(name, func(t *testing.T) {
+			// Arrange
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			resets := mocks.NewMockPasswordResetRepository(ctrl)
+			opaque := mocks.NewMockOpaqueTokenService(ctrl)
+
+			opaque.EXPECT().Hash("raw").Return("h")
+			resets.EXPECT().FindByHash(gomock.Any(), "h").Return(stored, nil)
+
+			uc := app.ConfirmPasswordResetUseCase{
+				Resets: resets,
+				Opaque: opaque,
+				Now:    func() time.Time { return fixedNow },
+			}
+
+			// Act
+			err := uc.Execute(context.Background(), app.ConfirmPasswordResetInput{Token: "raw", NewPassword: "password123"})
+
+			// Assert
+			require.ErrorIs(t, err, app.ErrInvalidResetToken)
+		})
+	}
+}
+
+func TestUC18ConfirmPasswordReset_LostRace_IsInvalid(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fixedNow := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	resets := mocks.NewMockPasswordResetRepository(ctrl)
+	opaque := mocks.NewMockOpaqueTokenService(ctrl)
+
+	opaque.EXPECT().Hash("raw").Return("h")
+	resets.EXPECT().FindByHash(gomock.Any(), "h").
+		Return(&domain.PasswordResetToken{ID: 3, UserID: 7, ExpiresAt: fixedNow.Add(time.Hour)}, nil)
+	resets.EXPECT().MarkUsed(gomock.Any(), int64(3), fixedNow).Return(false, nil)
+
+	uc := app.ConfirmPasswordResetUseCase{
+		Resets: resets,
+		Opaque: opaque,
+		Now:    func() time.Time { return fixedNow },
+	}
+
+	// Act
+	err := uc.Execute(context.Background(), app.ConfirmPasswordResetInput{Token: "raw", NewPassword: "password123"})
+
+	// Assert
+	require.ErrorIs(t, err, app.ErrInvalidResetToken)
+}
+
+func TestUC18ConfirmPasswordReset_Success_UpdatesPasswordAndEndsSessions(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fixedNow := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	resets := mocks.NewMockPasswordResetRepository(ctrl)
+	opaque := mocks.NewMockOpaqueTokenService(ctrl)
+	hasher := mocks.NewMockPasswordHasher(ctrl)
+	refreshRepo := mocks.NewMockRefreshTokenRepository(ctrl)
+	revocations := mocks.NewMockTokenRevocationStore(ctrl)
+	apiKeys := mocks.NewMockAPIKeyRepository(ctrl)
+
+	opaque.EXPECT().Hash("raw").Return("h")
+	resets.EXPECT().FindByHash(gomock.Any(), "h").
+		Return(&domain.PasswordResetToken{ID: 3, UserID: 7, ExpiresAt: fixedNow.Add(time.Hour)}, nil)
+	resets.EXPECT().MarkUsed(gomock.Any(), int64(3), fixedNow).Return(true, nil)
+	userRepo.EXPECT().FindByID(gomock.Any(), int64(7)).Return(&domain.User{ID: 7}, nil)
+	hasher.EXPECT().Hash("password123").Return("bcrypt", nil)
+	userRepo.EXPECT().UpdatePassword(gomock.Any(), int64(7), "bcrypt").Return(nil)
+	resets.EXPECT().InvalidateByUser(gomock.Any(), int64(7), fixedNow).Return(nil)
+	refreshRepo.EXPECT().RevokeByUser(gomock.Any(), int64(7), fixedNow).Return(nil)
+	apiKeys.EXPECT().RevokeByUser(gomock.Any(), int64(7), fixedNow).Return(nil)
+	revocations.EXPECT().RevokeUser(gomock.Any(), int64(7), fixedNow, fixedNow.Add(30*time.Minute)).Return(nil)
+
+	uc := app.ConfirmPasswordResetUseCase{
+		UserRepo:      userRepo,
+		Resets:        resets,
+		Opaque:        opaque,
+		Hasher:        hasher,
+		RefreshTokens: refreshRepo,
+		APIKeys:       apiKeys,
+		Revocations:   revocations,
+		Now:           func() time.Time { return fixedNow },
+		TTL:           30 * time.Minute,
+	}
+
+	// Act
+	err := uc.Execute(context.Background(), app.ConfirmPasswordResetInput{Token: " raw ", NewPassword: "password123"})
+
+	// Assert
+	require.NoError(t, err)
+}
@@ -0,0 +1,237 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/moondolphin/crypto-api/domain"
+)
+
+var (
+	ErrInvalidVerificationToken  = errors.New("invalid_verification_token")
+	ErrEmailAlreadyVerified      = errors.New("email_already_verified")
+	ErrVerificationResendTooSoon = errors.New("verification_resend_too_soon")
+	ErrEmailNotVerified          = errors.New("email_not_verified")
+)
+
+const (
+	defaultEmailVerificationTTL = 48 * time.Hour
+	defaultResendCooldown       = time.Minute
+)
+
+// Funcionalidades que se pueden restringir a cuentas sin verificar (ver EmailVerificationPolicy).
+const (
+	FeatureFavorites  = "favorites"
+	FeatureAPIKeys    = "api_keys"
+	FeatureAlerts     = "alerts"
+	FeatureWebhooks   = "webhooks"
+	FeaturePortfolios = "portfolios"
+)
+
+// SendEmailVerificationUseCase emite un token de verificación nuevo (invalidando
+// los anteriores) y manda el link por mail.
+type SendEmailVerificationUseCase struct {
+	Tokens domain.EmailVerificationRepository
+	Opaque domain.OpaqueTokenService
+	Mailer domain.Mailer
+	Now    func() time.Time
+	TTL    time.Duration
+
+	// URL que recibe ?token=... (GET /api/v1/auth/verify-email o una página del front)
+	VerifyURL string
+}
+
+func (uc SendEmailVerificationUseCase) Send(ctx context.Context, u domain.User) error {
+	now := uc.Now
+	if now == nil {
+		now = time.Now
+	}
+	t := now().UTC()
+
+	ttl := uc.TTL
+	if ttl <= 0 {
+		ttl = defaultEmailVerificationTTL
+	}
+
+	if err := uc.Tokens.InvalidateByUser(ctx, u.ID, t); err != nil {
+		return err
+	}
+
+	raw, err := uc.Opaque.Generate()
+	if err != nil {
+		return err
+	}
+
+	_, err = uc.Tokens.Create(ctx, domain.EmailVerificationToken{
+		UserID:    u.ID,
+		TokenHash: uc.Opaque.Hash(raw),
+		ExpiresAt: t.Add(ttl),
+		CreatedAt: t,
+	})
+	if err != nil {
+		return err
+	}
+
+	link, err := withTokenParam(uc.VerifyURL, raw)
+	if err != nil {
+		return err
+	}
+
+	return uc.Mailer.Send(ctx, domain.EmailMessage{
+		To:      u.Email,
+		Subject: "Confirmá tu email",
+		Body: fmt.Sprintf(
+			"Hola %s,\n\nPara confirmar tu dirección de email entrá a:\n\n%s\n\nEl link vence en %d horas.\n",
+			u.Name, link, int(ttl.Hours()),
+		),
+	})
+}
+
+type VerifyEmailInput struct {
+	Token string `json:"token" form:"token"`
+}
+
+// VerifyEmailUseCase consume el token y marca la cuenta como verificada.
+type VerifyEmailUseCase struct {
+	UserRepo domain.UserRepository
+	Tokens   domain.EmailVerificationRepository
+	Opaque   domain.OpaqueTokenService
+	Now      func() time.Time
+
+	// opcional: la cuenta con este email pasa a admin al verificarlo
+	BootstrapAdminEmail string
+}
+
+func (uc VerifyEmailUseCase) Execute(ctx context.Context, in VerifyEmailInput) error {
+	raw := strings.TrimSpace(in.Token)
+	if raw == "" {
+		return ErrBadRequest
+	}
+
+	now := uc.Now
+	if now == nil {
+		now = time.Now
+	}
+	t := now().UTC()
+
+	vt, err := uc.Tokens.FindByHash(ctx, uc.Opaque.Hash(raw))
+	if err != nil {
+		return err
+	}
+	if vt == nil || vt.IsUsed() || vt.IsExpired(t) {
+		return ErrInvalidVerificationToken
+	}
+
+	used, err := uc.Tokens.MarkUsed(ctx, vt.ID, t)
+	if err != nil {
+		return err
+	}
+	if !used {
+		return ErrInvalidVerificationToken
+	}
+
+	if err := uc.UserRepo.MarkEmailVerified(ctx, vt.UserID, t); err != nil {
+		return err
+	}
+	return uc.promoteBootstrapAdmin(ctx, vt.UserID)
+}
+
+// promoteBootstrapAdmin hace admin al usuario recién verificado si su email es
+// el de BOOTSTRAP_ADMIN_EMAIL: verificar prueba que es dueño del buzón.
+func (uc VerifyEmailUseCase) promoteBootstrapAdmin(ctx context.Context, userID int64) error {
+	admin := strings.ToLower(strings.TrimSpace(uc.BootstrapAdminEmail))
+	if admin == "" {
+		return nil
+	}
+	u, err := uc.UserRepo.FindByID(ctx, userID)
+	if err != nil || u == nil {
+		return err
+	}
+	if strings.ToLower(u.Email) != admin || u.Role == domain.RoleAdmin {
+		return nil
+	}
+	return uc.UserRepo.UpdateRole(ctx, u.ID, domain.RoleAdmin)
+}
+
+// ResendEmailVerificationUseCase reenvía el mail de verificación, como mucho una
+// vez por Cooldown. Si es muy pronto devuelve cuánto falta.
+type ResendEmailVerificationUseCase struct {
+	UserRepo domain.UserRepository
+	Tokens   domain.EmailVerificationRepository
+	Sender   SendEmailVerificationUseCase
+	Now      func() time.Time
+	Cooldown time.Duration
+}
+
+func (uc ResendEmailVerificationUseCase) Execute(ctx context.Context, userID int64) (retryAfter time.Duration, err error) {
+	u, err := uc.UserRepo.FindByID(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+	if u == nil {
+		return 0, ErrUserNotFound
+	}
+	if u.IsEmailVerified() {
+		return 0, ErrEmailAlreadyVerified
+	}
+
+	now := uc.Now
+	if now == nil {
+		now = time.Now
+	}
+	t := now().UTC()
+
+	cooldown := uc.Cooldown
+	if cooldown <= 0 {
+		cooldown = defaultResendCooldown
+	}
+
+	last, err := uc.Tokens.LatestByUser(ctx, u.ID)
+	if err != nil {
+		return 0, err
+	}
+	if last != nil {
+		if next := last.CreatedAt.Add(cooldown); t.Before(next) {
+			return next.Sub(t), ErrVerificationResendTooSoon
+		}
+	}
+
+	return 0, uc.Sender.Send(ctx, *u)
+}
+
+// EmailVerificationPolicy decide qué funcionalidades quedan bloqueadas para
+// cuentas sin verificar. Restricted vacío = sin restricciones.
+type EmailVerificationPolicy struct {
+	UserRepo   domain.UserRepository
+	Restricted map[string]bool
+}
+
+func NewEmailVerificationPolicy(userRepo domain.UserRepository, restricted []string) *EmailVerificationPolicy {
+	m := make(map[string]bool, len(restricted))
+	for _, f := range restricted {
+		if f = strings.TrimSpace(f); f != "" {
+			m[f] = true
+		}
+	}
+	return &EmailVerificationPolicy{UserRepo: userRepo, Restricted: m}
+}
+
+// Check devuelve ErrEmailNotVerified si feature está restringida y la cuenta no
+// verificó su email.
+func (p *EmailVerificationPolicy) Check(ctx context.Context, userID int64, feature string) error {
+	if !p.Restricted[feature] {
+		return nil
+	}
+
+	u, err := p.UserRepo.FindByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if u == nil || !u.IsEmailVerified() {
+		return ErrEmailNotVerified
+	}
+	return nil
+}
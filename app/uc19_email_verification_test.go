@@ -0,0 +1,345 @@
+package app_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/moondolphin/crypto-api/app"
+	"github.com/moondolphin/crypto-api/domain"
+	"github.com/moondolphin/crypto-api/test/mocks"
+)
+
+func TestUC19SendEmailVerification_InvalidatesPreviousAndMailsLink(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fixedNow := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	tokens := mocks.NewMockEmailVerificationRepository(ctrl)
+	opaque := mocks.NewMockOpaqueTokenService(ctrl)
+	mailer := mocks.NewMockMailer(ctrl)
+
+	gomock.InOrder(
+		tokens.EXPECT().InvalidateByUser(gomock.Any(), int64(7), fixedNow).Return(nil),
+		opaque.EXPECT().Generate().Return("raw", nil),
+		opaque.EXPECT().Hash("raw").Return("hashed"),
+		tokens.EXPECT().Create(gomock.Any(), domain.EmailVerificationToken{
+			UserID:    7,
+			TokenHash: "hashed",
+			ExpiresAt: fixedNow.Add(24 * time.Hour),
+			CreatedAt: fixedNow,
+		}).Return(domain.EmailVerificationToken{ID: 1}, nil),
+		mailer.EXPECT().Send(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, msg domain.EmailMessage) error {
+				require.Equal(t, "a@b.com", msg.To)
+				require.Contains(t, msg.Body, "http://localhost:8080/api/v1/auth/verify-email?token=raw")
+				return nil
+			}),
+	)
+
+	uc := app.SendEmailVerificationUseCase{
+		Tokens:    tokens,
+		Opaque:    opaque,
+		Mailer:    mailer,
+		Now:       func() time.Time { return fixedNow },
+		TTL:       24 * time.Hour,
+		VerifyURL: "http://localhost:8080/api/v1/auth/verify-email",
+	}
+
+	// Act
+	err := uc.Send(context.Background(), domain.User{ID: 7, Email: "a@b.com"})
+
+	// Assert
+	require.NoError(t, err)
+}
+
+func TestUC19VerifyEmail_BadRequest_WhenEmptyToken(t *testing.T) {
+	uc := app.VerifyEmailUseCase{}
+
+	err := uc.Execute(context.Background(), app.VerifyEmailInput{Token: "  "})
+
+	require.ErrorIs(t, err, app.ErrBadRequest)
+}
+
+func TestUC19VerifyEmail_InvalidToken(t *testing.T) {
+	fixedNow := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+	usedAt := fixedNow.Add(-time.Minute)
+
+	cases := map[string]*domain.EmailVerificationToken{
+		"missing": nil,
+		"used":    {ID: 1, UserID: 7, ExpiresAt: fixedNow.Add(time.Hour), UsedAt: &usedAt},
+		"expired": {ID: 1, UserID: 7, ExpiresAt: fixedNow},
+	}
+
+	for name, stored := range cases {
+		t.Run(name, func(t *testing.T) {
+			// Arrange
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			tokens := mocks.NewMockEmailVerificationRepository(ctrl)
+			opaque := mocks.NewMockOpaqueTokenService(ctrl)
+
+			opaque.EXPECT().Hash("raw").Return("h")
+			tokens.EXPECT().FindByHash(gomock.Any(), "h").Return(stored, nil)
+
+			uc := app.VerifyEmailUseCase{
+				Tokens: tokens,
+				Opaque: opaque,
+				Now:    func() time.Time { return fixedNow },
+			}
+
+			// Act
+			err := uc.Execute(context.Background(), app.VerifyEmailInput{Token: "raw"})
+
+			// Assert
+			require.ErrorIs(t, err, app.ErrInvalidVerificationToken)
+		})
+	}
+}
+
+func TestUC19VerifyEmail_Success_MarksUserVerified(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fixedNow := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	tokens := mocks.NewMockEmailVerificationRepository(ctrl)
+	opaque := mocks.NewMockOpaqueTokenService(ctrl)
+
+	opaque.EXPECT().Hash("raw").Return("h")
+	tokens.EXPECT().FindByHash(gomock.Any(), "h").
+		Return(&domain.EmailVerificationToken{ID: 4, UserID: 7, ExpiresAt: fixedNow.Add(time.Hour)}, nil)
+	tokens.EXPECT().MarkUsed(gomock.Any(), int64(4), fixedNow).Return(true, nil)
+	userRepo.EXPECT().MarkEmailVerified(gomock.Any(), int64(7), fixedNow).Return(nil)
+
+	uc := app.VerifyEmailUseCase{
+		UserRepo: userRepo,
+		Tokens:   tokens,
+		Opaque:   opaque,
+		Now:      func() time.Time { return fixedNow },
+	}
+
+	// Act
+	err := uc.Execute(context.Background(), app.VerifyEmailInput{Token: " raw "})
+
+	// Assert
+	require.NoError(t, err)
+}
+
+func TestUC19VerifyEmail_BootstrapAdminEmail_PromotesOnVerify(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		email   string
+		role    string
+		promote bool
+	}{
+		{"admin email", "boss@example.com", domain.RoleUser, true},
+		{"already admin", "boss@example.com", domain.RoleAdmin, false},
+		{"other email", "john@example.com", domain.RoleUser, false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			fixedNow := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+
+			userRepo := mocks.NewMockUserRepository(ctrl)
+			tokens := mocks.NewMockEmailVerificationRepository(ctrl)
+			opaque := mocks.NewMockOpaqueTokenService(ctrl)
+
+			opaque.EXPECT().Hash("raw").Return("h")
+			tokens.EXPECT().FindByHash(gomock.Any(), "h").
+				Return(&domain.EmailVerificationToken{ID: 4, UserID: 7, ExpiresAt: fixedNow.Add(time.Hour)}, nil)
+			tokens.EXPECT().MarkUsed(gomock.Any(), int64(4), fixedNow).Return(true, nil)
+			userRepo.EXPECT().MarkEmailVerified(gomock.Any(), int64(7), fixedNow).Return(nil)
+			userRepo.EXPECT().FindByID(gomock.Any(), int64(7)).
+				Return(&domain.User{ID: 7, Email: tc.email, Role: tc.role, EmailVerifiedAt: &fixedNow}, nil)
+			if tc.promote {
+				userRepo.EXPECT().UpdateRole(gomock.Any(), int64(7), domain.RoleAdmin).Return(nil)
+			}
+
+			uc := app.VerifyEmailUseCase{
+				UserRepo:            userRepo,
+				Tokens:              tokens,
+				Opaque:              opaque,
+				Now:                 func() time.Time { return fixedNow },
+				BootstrapAdminEmail: "Boss@Example.com",
+			}
+
+			// Act
+			err := uc.Execute(context.Background(), app.VerifyEmailInput{Token: "raw"})
+
+			// Assert
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestUC19VerifyEmail_LostRace_IsInvalid(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fixedNow := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	tokens := mocks.NewMockEmailVerificationRepository(ctrl)
+	opaque := mocks.NewMockOpaqueTokenService(ctrl)
+
+	opaque.EXPECT().Hash("raw").Return("h")
+	tokens.EXPECT().FindByHash(gomock.Any(), "h").
+		Return(&domain.EmailVerificationToken{ID: 4, UserID: 7, ExpiresAt: fixedNow.Add(time.Hour)}, nil)
+	tokens.EXPECT().MarkUsed(gomock.Any(), int64(4), fixedNow).Return(false, nil)
+
+	uc := app.VerifyEmailUseCase{Tokens: tokens, Opaque: opaque, Now: func() time.Time { return fixedNow }}
+
+	// Act
+	err := uc.Execute(context.Background(), app.VerifyEmailInput{Token: "raw"})
+
+	// Assert
+	require.ErrorIs(t, err, app.ErrInvalidVerificationToken)
+}
+
+func TestUC19ResendVerification_AlreadyVerified(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	verifiedAt := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	userRepo.EXPECT().FindByID(gomock.Any(), int64(7)).Return(&domain.User{ID: 7, EmailVerifiedAt: &verifiedAt}, nil)
+
+	uc := app.ResendEmailVerificationUseCase{UserRepo: userRepo}
+
+	// Act
+	_, err := uc.Execute(context.Background(), 7)
+
+	// Assert
+	require.ErrorIs(t, err, app.ErrEmailAlreadyVerified)
+}
+
+func TestUC19ResendVerification_TooSoon_ReturnsRetryAfter(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fixedNow := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	tokens := mocks.NewMockEmailVerificationRepository(ctrl)
+
+	userRepo.EXPECT().FindByID(gomock.Any(), int64(7)).Return(&domain.User{ID: 7}, nil)
+	tokens.EXPECT().LatestByUser(gomock.Any(), int64(7)).
+		Return(&domain.EmailVerificationToken{ID: 1, CreatedAt: fixedNow.Add(-20 * time.Second)}, nil)
+
+	uc := app.ResendEmailVerificationUseCase{
+		UserRepo: userRepo,
+		Tokens:   tokens,
+		Now:      func() time.Time { return fixedNow },
+		Cooldown: time.Minute,
+	}
+
+	// Act
+	retryAfter, err := uc.Execute(context.Background(), 7)
+
+	// Assert
+	require.ErrorIs(t, err, app.ErrVerificationResendTooSoon)
+	require.Equal(t, 40*time.Second, retryAfter)
+}
+
+func TestUC19ResendVerification_Success_AfterCooldown(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fixedNow := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	tokens := mocks.NewMockEmailVerificationRepository(ctrl)
+	opaque := mocks.NewMockOpaqueTokenService(ctrl)
+	mailer := mocks.NewMockMailer(ctrl)
+
+	userRepo.EXPECT().FindByID(gomock.Any(), int64(7)).Return(&domain.User{ID: 7, Email: "a@b.com"}, nil)
+	tokens.EXPECT().LatestByUser(gomock.Any(), int64(7)).
+		Return(&domain.EmailVerificationToken{ID: 1, CreatedAt: fixedNow.Add(-time.Minute)}, nil)
+	tokens.EXPECT().InvalidateByUser(gomock.Any(), int64(7), fixedNow).Return(nil)
+	opaque.EXPECT().Generate().Return("raw", nil)
+	opaque.EXPECT().Hash("raw").Return("h")
+	tokens.EXPECT().Create(gomock.Any(), gomock.Any()).Return(domain.EmailVerificationToken{ID: 2}, nil)
+	mailer.EXPECT().Send(gomock.Any(), gomock.Any()).Return(nil)
+
+	now := func() time.Time { return fixedNow }
+	uc := app.ResendEmailVerificationUseCase{
+		UserRepo: userRepo,
+		Tokens:   tokens,
+		Sender:   app.SendEmailVerificationUseCase{Tokens: tokens, Opaque: opaque, Mailer: mailer, Now: now},
+		Now:      now,
+		Cooldown: time.Minute,
+	}
+
+	// Act
+	retryAfter, err := uc.Execute(context.Background(), 7)
+
+	// Assert
+	require.NoError(t, err)
+	require.Zero(t, retryAfter)
+}
+
+func TestUC19EmailVerificationPolicy_Check(t *testing.T) {
+	verifiedAt := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("UnrestrictedFeature_SkipsLookup", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		p := app.NewEmailVerificationPolicy(mocks.NewMockUserRepository(ctrl), []string{app.FeatureFavorites})
+
+		require.NoError(t, p.Check(context.Background(), 7, app.FeatureAPIKeys))
+	})
+
+	t.Run("Unverified_IsRejected", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		userRepo := mocks.NewMockUserRepository(ctrl)
+		userRepo.EXPECT().FindByID(gomock.Any(), int64(7)).Return(&domain.User{ID: 7}, nil)
+
+		p := app.NewEmailVerificationPolicy(userRepo, []string{" favorites ", ""})
+
+		require.ErrorIs(t, p.Check(context.Background(), 7, app.FeatureFavorites), app.ErrEmailNotVerified)
+	})
+
+	t.Run("Verified_Passes", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		userRepo := mocks.NewMockUserRepository(ctrl)
+		userRepo.EXPECT().FindByID(gomock.Any(), int64(7)).Return(&domain.User{ID: 7, EmailVerifiedAt: &verifiedAt}, nil)
+
+		p := app.NewEmailVerificationPolicy(userRepo, []string{app.FeatureFavorites})
+
+		require.NoError(t, p.Check(context.Background(), 7, app.FeatureFavorites))
+	})
+
+	t.Run("RepoError", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		userRepo := mocks.NewMockUserRepository(ctrl)
+		userRepo.EXPECT().FindByID(gomock.Any(), int64(7)).Return(nil, errors.New("db down"))
+
+		p := app.NewEmailVerificationPolicy(userRepo, []string{app.FeatureFavorites})
+
+		require.EqualError(t, p.Check(context.Background(), 7, app.FeatureFavorites), "db down")
+	})
+}
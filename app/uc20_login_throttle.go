@@ -0,0 +1,157 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/moondolphin/crypto-api/domain"
+)
+
+var ErrTooManyLoginAttempts = errors.New("too_many_login_attempts")
+
+const (
+	defaultMaxAccountFailures = 5
+	defaultMaxIPFailures      = 20
+	defaultLockoutBase        = 30 * time.Second
+	defaultLockoutMax         = time.Hour
+	defaultFailureWindow      = 15 * time.Minute
+)
+
+// LoginThrottle cuenta los logins fallidos por cuenta y por IP. Al llegar al
+// máximo la clave queda bloqueada BaseLockout, y cada fallo posterior duplica
+// el bloqueo hasta MaxLockout. Los fallos se olvidan tras Window sin fallar.
+type LoginThrottle struct {
+	Store domain.LoginAttemptStore
+
+	MaxAccountFailures int
+	MaxIPFailures      int
+	BaseLockout        time.Duration
+	MaxLockout         time.Duration
+	Window             time.Duration
+}
+
+func accountAttemptKey(email string) string {
+	return "account:" + strings.ToLower(strings.TrimSpace(email))
+}
+
+func ipAttemptKey(ip string) string {
+	return "ip:" + ip
+}
+
+// Check devuelve ErrTooManyLoginAttempts y cuánto falta si la cuenta o la IP están bloqueadas.
+func (t LoginThrottle) Check(ctx context.Context, email, ip string, now time.Time) (time.Duration, error) {
+	var retryAfter time.Duration
+	for _, key := range t.keys(email, ip) {
+		a, err := t.Store.Get(ctx, key)
+		if err != nil {
+			return 0, err
+		}
+		if a != nil && a.IsLocked(now) {
+			if d := a.LockedUntil.Sub(now); d > retryAfter {
+				retryAfter = d
+			}
+		}
+	}
+
+	if retryAfter > 0 {
+		return retryAfter, ErrTooManyLoginAttempts
+	}
+	return 0, nil
+}
+
+// Failure registra un login fallido y bloquea las claves que superaron su máximo.
+func (t LoginThrottle) Failure(ctx context.Context, email, ip string, now time.Time) error {
+	window := t.Window
+	if window <= 0 {
+		window = defaultFailureWindow
+	}
+
+	for _, key := range t.keys(email, ip) {
+		failures, err := t.Store.RecordFailure(ctx, key, now, now.Add(-window))
+		if err != nil {
+			return err
+		}
+
+		limit := t.maxFailures(key)
+		if failures < limit {
+			continue
+		}
+		if err := t.Store.Lock(ctx, key, now.Add(t.lockout(failures-limit))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Success olvida los fallos de la cuenta. Los de la IP se mantienen: un login
+// correcto no debe habilitar a seguir probando contraseñas de otras cuentas.
+func (t LoginThrottle) Success(ctx context.Context, email string) error {
+	return t.Store.Reset(ctx, accountAttemptKey(email))
+}
+
+func (t LoginThrottle) keys(email, ip string) []string {
+	keys := []string{accountAttemptKey(email)}
+	if ip != "" {
+		keys = append(keys, ipAttemptKey(ip))
+	}
+	return keys
+}
+
+func (t LoginThrottle) maxFailures(key string) int {
+	if strings.HasPrefix(key, "ip:") {
+		if t.MaxIPFailures > 0 {
+			return t.MaxIPFailures
+		}
+		return defaultMaxIPFailures
+	}
+	if t.MaxAccountFailures > 0 {
+		return t.MaxAccountFailures
+	}
+	return defaultMaxAccountFailures
+}
+
+// lockout devuelve BaseLockout * 2^extra, acotado a MaxLockout.
+func (t LoginThrottle) lockout(extra int) time.Duration {
+	base := t.BaseLockout
+	if base <= 0 {
+		base = defaultLockoutBase
+	}
+	ceiling := t.MaxLockout
+	if ceiling <= 0 {
+		ceiling = defaultLockoutMax
+	}
+
+	d := base
+	for i := 0; i < extra && d < ceiling; i++ {
+		d *= 2
+	}
+	if d > ceiling {
+		d = ceiling
+	}
+	return d
+}
+
+// UnlockUserLoginUseCase levanta el bloqueo de login de una cuenta (uso admin).
+// No toca los bloqueos por IP.
+type UnlockUserLoginUseCase struct {
+	UserRepo domain.UserRepository
+	Store    domain.LoginAttemptStore
+}
+
+func (uc UnlockUserLoginUseCase) Execute(ctx context.Context, userID int64) error {
+	if userID <= 0 {
+		return ErrBadRequest
+	}
+
+	u, err := uc.UserRepo.FindByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if u == nil {
+		return ErrUserNotFound
+	}
+
+	return uc.Store.Reset(ctx, accountAttemptKey(u.Email))
+}
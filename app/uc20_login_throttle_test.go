@@ -0,0 +1,250 @@
+package app_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/moondolphin/crypto-api/app"
+	"github.com/moondolphin/crypto-api/domain"
+	"github.com/moondolphin/crypto-api/test/mocks"
+)
+
+func TestUC20LoginThrottle_Check_AllowsWhenNoAttempts(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mocks.NewMockLoginAttemptStore(ctrl)
+	now := time.Date(2026, 1, 22, 10, 0, 0, 0, time.UTC)
+
+	store.EXPECT().Get(gomock.Any(), "account:john@example.com").Return(nil, nil)
+	store.EXPECT().Get(gomock.Any(), "ip:10.0.0.1").Return(&domain.LoginAttempt{Key: "ip:10.0.0.1", Failures: 3}, nil)
+
+	th := app.LoginThrottle{Store: store}
+
+	// Act
+	retryAfter, err := th.Check(context.Background(), "john@example.com", "10.0.0.1", now)
+
+	// Assert
+	require.NoError(t, err)
+	require.Zero(t, retryAfter)
+}
+
+func TestUC20LoginThrottle_Check_ReturnsLongestLock(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mocks.NewMockLoginAttemptStore(ctrl)
+	now := time.Date(2026, 1, 22, 10, 0, 0, 0, time.UTC)
+	accountUntil := now.Add(30 * time.Second)
+	ipUntil := now.Add(2 * time.Minute)
+
+	store.EXPECT().Get(gomock.Any(), "account:john@example.com").Return(&domain.LoginAttempt{LockedUntil: &accountUntil}, nil)
+	store.EXPECT().Get(gomock.Any(), "ip:10.0.0.1").Return(&domain.LoginAttempt{LockedUntil: &ipUntil}, nil)
+
+	th := app.LoginThrottle{Store: store}
+
+	// Act
+	retryAfter, err := th.Check(context.Background(), "John@Example.com", "10.0.0.1", now)
+
+	// Assert
+	require.ErrorIs(t, err, app.ErrTooManyLoginAttempts)
+	require.Equal(t, 2*time.Minute, retryAfter)
+}
+
+func TestUC20LoginThrottle_Check_IgnoresExpiredLock(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mocks.NewMockLoginAttemptStore(ctrl)
+	now := time.Date(2026, 1, 22, 10, 0, 0, 0, time.UTC)
+	until := now
+
+	store.EXPECT().Get(gomock.Any(), "account:john@example.com").Return(&domain.LoginAttempt{LockedUntil: &until}, nil)
+
+	th := app.LoginThrottle{Store: store}
+
+	// Act (sin IP sólo se mira la cuenta)
+	_, err := th.Check(context.Background(), "john@example.com", "", now)
+
+	// Assert
+	require.NoError(t, err)
+}
+
+func TestUC20LoginThrottle_Failure_DoesNotLockBelowLimit(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mocks.NewMockLoginAttemptStore(ctrl)
+	now := time.Date(2026, 1, 22, 10, 0, 0, 0, time.UTC)
+
+	store.EXPECT().RecordFailure(gomock.Any(), "account:john@example.com", now, now.Add(-10*time.Minute)).Return(4, nil)
+	store.EXPECT().RecordFailure(gomock.Any(), "ip:10.0.0.1", now, now.Add(-10*time.Minute)).Return(19, nil)
+
+	th := app.LoginThrottle{Store: store, MaxAccountFailures: 5, MaxIPFailures: 20, Window: 10 * time.Minute}
+
+	// Act
+	err := th.Failure(context.Background(), "john@example.com", "10.0.0.1", now)
+
+	// Assert
+	require.NoError(t, err)
+}
+
+func TestUC20LoginThrottle_Failure_LockoutGrowsExponentiallyAndIsCapped(t *testing.T) {
+	now := time.Date(2026, 1, 22, 10, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		name     string
+		failures int
+		want     time.Duration
+	}{
+		{"at limit", 5, 30 * time.Second},
+		{"one over", 6, time.Minute},
+		{"three over", 8, 4 * time.Minute},
+		{"capped", 30, 10 * time.Minute},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mocks.NewMockLoginAttemptStore(ctrl)
+			store.EXPECT().RecordFailure(gomock.Any(), "account:john@example.com", now, gomock.Any()).Return(tc.failures, nil)
+			store.EXPECT().Lock(gomock.Any(), "account:john@example.com", now.Add(tc.want)).Return(nil)
+
+			th := app.LoginThrottle{
+				Store:              store,
+				MaxAccountFailures: 5,
+				BaseLockout:        30 * time.Second,
+				MaxLockout:         10 * time.Minute,
+			}
+
+			// Act
+			err := th.Failure(context.Background(), "john@example.com", "", now)
+
+			// Assert
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestUC20LoginThrottle_Failure_LocksIPWithItsOwnLimit(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mocks.NewMockLoginAttemptStore(ctrl)
+	now := time.Date(2026, 1, 22, 10, 0, 0, 0, time.UTC)
+
+	store.EXPECT().RecordFailure(gomock.Any(), "account:other@example.com", now, gomock.Any()).Return(1, nil)
+	store.EXPECT().RecordFailure(gomock.Any(), "ip:10.0.0.1", now, gomock.Any()).Return(20, nil)
+	store.EXPECT().Lock(gomock.Any(), "ip:10.0.0.1", now.Add(30*time.Second)).Return(nil)
+
+	th := app.LoginThrottle{Store: store}
+
+	// Act (defaults: 5 por cuenta, 20 por IP, 30s de base)
+	err := th.Failure(context.Background(), "other@example.com", "10.0.0.1", now)
+
+	// Assert
+	require.NoError(t, err)
+}
+
+func TestUC20LoginThrottle_Failure_StoreError(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mocks.NewMockLoginAttemptStore(ctrl)
+	now := time.Date(2026, 1, 22, 10, 0, 0, 0, time.UTC)
+
+	store.EXPECT().RecordFailure(gomock.Any(), gomock.Any(), now, gomock.Any()).Return(0, errors.New("db down"))
+
+	th := app.LoginThrottle{Store: store}
+
+	// Act
+	err := th.Failure(context.Background(), "john@example.com", "10.0.0.1", now)
+
+	// Assert
+	require.EqualError(t, err, "db down")
+}
+
+func TestUC20LoginThrottle_Success_ResetsOnlyAccount(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mocks.NewMockLoginAttemptStore(ctrl)
+	store.EXPECT().Reset(gomock.Any(), "account:john@example.com").Return(nil)
+
+	th := app.LoginThrottle{Store: store}
+
+	// Act
+	err := th.Success(context.Background(), "john@example.com")
+
+	// Assert
+	require.NoError(t, err)
+}
+
+func TestUC20UnlockUserLogin_BadRequest_WhenInvalidID(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	uc := app.UnlockUserLoginUseCase{
+		UserRepo: mocks.NewMockUserRepository(ctrl),
+		Store:    mocks.NewMockLoginAttemptStore(ctrl),
+	}
+
+	// Act
+	err := uc.Execute(context.Background(), 0)
+
+	// Assert
+	require.ErrorIs(t, err, app.ErrBadRequest)
+}
+
+func TestUC20UnlockUserLogin_UserNotFound(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	userRepo.EXPECT().FindByID(gomock.Any(), int64(7)).Return(nil, nil)
+
+	uc := app.UnlockUserLoginUseCase{UserRepo: userRepo, Store: mocks.NewMockLoginAttemptStore(ctrl)}
+
+	// Act
+	err := uc.Execute(context.Background(), 7)
+
+	// Assert
+	require.ErrorIs(t, err, app.ErrUserNotFound)
+}
+
+func TestUC20UnlockUserLogin_Success_ResetsAccountKey(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	store := mocks.NewMockLoginAttemptStore(ctrl)
+
+	userRepo.EXPECT().FindByID(gomock.Any(), int64(7)).Return(&domain.User{ID: 7, Email: "john@example.com"}, nil)
+	store.EXPECT().Reset(gomock.Any(), "account:john@example.com").Return(nil)
+
+	uc := app.UnlockUserLoginUseCase{UserRepo: userRepo, Store: store}
+
+	// Act
+	err := uc.Execute(context.Background(), 7)
+
+	// Assert
+	require.NoError(t, err)
+}
@@ -0,0 +1,485 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/moondolphin/crypto-api/domain"
+)
+
+var (
+	ErrTwoFactorAlreadyEnabled = errors.New("two_factor_already_enabled")
+	ErrTwoFactorNotEnabled     = errors.New("two_factor_not_enabled")
+	ErrInvalidTwoFactorCode    = errors.New("invalid_two_factor_code")
+	ErrInvalidLoginChallenge   = errors.New("invalid_login_challenge")
+)
+
+const (
+	defaultLoginChallengeTTL = 5 * time.Minute
+	recoveryCodeCount        = 10
+)
+
+// TwoFactorLogin agrupa lo que LoginUseCase necesita para el login en dos pasos.
+type TwoFactorLogin struct {
+	TOTP          domain.TOTPRepository
+	RecoveryCodes domain.RecoveryCodeRepository
+	Challenges    domain.LoginChallengeRepository
+	Service       domain.TOTPService
+	ChallengeTTL  time.Duration
+}
+
+type TwoFactorChallengeOutput struct {
+	TwoFactorRequired bool      `json:"two_factor_required"`
+	ChallengeToken    string    `json:"challenge_token"`
+	ExpiresAt         time.Time `json:"expires_at"`
+}
+
+func (tf TwoFactorLogin) challenge(ctx context.Context, opaque domain.OpaqueTokenService, userID int64, now time.Time) (TwoFactorChallengeOutput, error) {
+	ttl := tf.ChallengeTTL
+	if ttl <= 0 {
+		ttl = defaultLoginChallengeTTL
+	}
+
+	raw, err := opaque.Generate()
+	if err != nil {
+		return TwoFactorChallengeOutput{}, err
+	}
+
+	c, err := tf.Challenges.Create(ctx, domain.LoginChallenge{
+		UserID:    userID,
+		TokenHash: opaque.Hash(raw),
+		ExpiresAt: now.Add(ttl),
+		CreatedAt: now,
+	})
+	if err != nil {
+		return TwoFactorChallengeOutput{}, err
+	}
+
+	return TwoFactorChallengeOutput{
+		TwoFactorRequired: true,
+		ChallengeToken:    raw,
+		ExpiresAt:         c.ExpiresAt,
+	}, nil
+}
+
+// verifyTOTP valida un código de la app autenticadora y lo marca como usado.
+func verifyTOTP(ctx context.Context, repo domain.TOTPRepository, svc domain.TOTPService, e domain.TOTPEnrollment, code string, now time.Time) (bool, error) {
+	step, ok := svc.Validate(e.Secret, code, now)
+	if !ok {
+		return false, nil
+	}
+	return repo.MarkStepUsed(ctx, e.UserID, step)
+}
+
+// verifySecondFactor acepta un código TOTP o, si no es numérico, uno de recuperación.
+func verifySecondFactor(ctx context.Context, repo domain.TOTPRepository, recovery domain.RecoveryCodeRepository, svc domain.TOTPService, opaque domain.OpaqueTokenService, e domain.TOTPEnrollment, code string, now time.Time) (bool, error) {
+	if isNumericCode(code) {
+		return verifyTOTP(ctx, repo, svc, e, code, now)
+	}
+
+	rc := normalizeRecoveryCode(code)
+	if rc == "" {
+		return false, nil
+	}
+	return recovery.Use(ctx, e.UserID, opaque.Hash(rc), now)
+}
+
+func isNumericCode(code string) bool {
+	code = strings.ReplaceAll(code, " ", "")
+	if code == "" {
+		return false
+	}
+	for _, r := range code {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// normalizeRecoveryCode lleva "ABCDE FGHIJ" o "abcdefghij" al formato emitido (abcde-fghij).
+func normalizeRecoveryCode(code string) string {
+	s := strings.ToLower(code)
+	s = strings.NewReplacer("-", "", " ", "").Replace(s)
+	if len(s) != 10 {
+		return ""
+	}
+	return s[:5] + "-" + s[5:]
+}
+
+// issueRecoveryCodes genera un juego nuevo de códigos (reemplaza los anteriores)
+// y los devuelve en claro: es la única vez que se muestran.
+func issueRecoveryCodes(ctx context.Context, recovery domain.RecoveryCodeRepository, svc domain.TOTPService, opaque domain.OpaqueTokenService, userID int64, now time.Time) ([]string, error) {
+	codes := make([]string, 0, recoveryCodeCount)
+	hashes := make([]string, 0, recoveryCodeCount)
+	for len(codes) < recoveryCodeCount {
+		c, err := svc.GenerateRecoveryCode()
+		if err != nil {
+			return nil, err
+		}
+		codes = append(codes, c)
+		hashes = append(hashes, opaque.Hash(c))
+	}
+
+	if err := recovery.Replace(ctx, userID, hashes, now); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+type TwoFactorStatusOutput struct {
+	Enabled                bool `json:"enabled"`
+	Pending                bool `json:"pending"`
+	RecoveryCodesRemaining int  `json:"recovery_codes_remaining"`
+}
+
+type GetTwoFactorStatusUseCase struct {
+	TOTP          domain.TOTPRepository
+	RecoveryCodes domain.RecoveryCodeRepository
+}
+
+func (uc GetTwoFactorStatusUseCase) Execute(ctx context.Context, userID int64) (TwoFactorStatusOutput, error) {
+	e, err := uc.TOTP.Get(ctx, userID)
+	if err != nil {
+		return TwoFactorStatusOutput{}, err
+	}
+	if e == nil {
+		return TwoFactorStatusOutput{}, nil
+	}
+	if !e.IsConfirmed() {
+		return TwoFactorStatusOutput{Pending: true}, nil
+	}
+
+	n, err := uc.RecoveryCodes.CountUnused(ctx, userID)
+	if err != nil {
+		return TwoFactorStatusOutput{}, err
+	}
+	return TwoFactorStatusOutput{Enabled: true, RecoveryCodesRemaining: n}, nil
+}
+
+type TOTPEnrollmentOutput struct {
+	Secret     string `json:"secret"`
+	OtpauthURI string `json:"otpauth_uri"`
+}
+
+// EnrollTOTPUseCase genera un secreto nuevo. El 2FA no se activa hasta que
+// ConfirmTOTPUseCase recibe un código válido; volver a enrolar descarta el pendiente.
+type EnrollTOTPUseCase struct {
+	UserRepo domain.UserRepository
+	TOTP     domain.TOTPRepository
+	Service  domain.TOTPService
+	Issuer   string
+	Now      func() time.Time
+}
+
+func (uc EnrollTOTPUseCase) Execute(ctx context.Context, userID int64) (TOTPEnrollmentOutput, error) {
+	u, err := uc.UserRepo.FindByID(ctx, userID)
+	if err != nil {
+		return TOTPEnrollmentOutput{}, err
+	}
+	if u == nil {
+		return TOTPEnrollmentOutput{}, ErrUserNotFound
+	}
+
+	e, err := uc.TOTP.Get(ctx, userID)
+	if err != nil {
+		return TOTPEnrollmentOutput{}, err
+	}
+	if e != nil && e.IsConfirmed() {
+		return TOTPEnrollmentOutput{}, ErrTwoFactorAlreadyEnabled
+	}
+
+	now := uc.Now
+	if now == nil {
+		now = time.Now
+	}
+
+	secret, err := uc.Service.GenerateSecret()
+	if err != nil {
+		return TOTPEnrollmentOutput{}, err
+	}
+
+	if err := uc.TOTP.Save(ctx, domain.TOTPEnrollment{UserID: userID, Secret: secret, CreatedAt: now().UTC()}); err != nil {
+		return TOTPEnrollmentOutput{}, err
+	}
+
+	issuer := uc.Issuer
+	if issuer == "" {
+		issuer = "Crypto API"
+	}
+
+	return TOTPEnrollmentOutput{
+		Secret:     secret,
+		OtpauthURI: uc.Service.URI(issuer, u.Email, secret),
+	}, nil
+}
+
+type TwoFactorCodeInput struct {
+	Code string `json:"code"`
+}
+
+type RecoveryCodesOutput struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// ConfirmTOTPUseCase activa el 2FA pendiente con un primer código y entrega los códigos de recuperación.
+type ConfirmTOTPUseCase struct {
+	TOTP          domain.TOTPRepository
+	RecoveryCodes domain.RecoveryCodeRepository
+	Service       domain.TOTPService
+	Opaque        domain.OpaqueTokenService
+	Now           func() time.Time
+}
+
+func (uc ConfirmTOTPUseCase) Execute(ctx context.Context, userID int64, in TwoFactorCodeInput) (RecoveryCodesOutput, error) {
+	code := strings.TrimSpace(in.Code)
+	if code == "" {
+		return RecoveryCodesOutput{}, ErrBadRequest
+	}
+
+	e, err := uc.TOTP.Get(ctx, userID)
+	if err != nil {
+		return RecoveryCodesOutput{}, err
+	}
+	if e == nil {
+		return RecoveryCodesOutput{}, ErrTwoFactorNotEnabled
+	}
+	if e.IsConfirmed() {
+		return RecoveryCodesOutput{}, ErrTwoFactorAlreadyEnabled
+	}
+
+	now := uc.Now
+	if now == nil {
+		now = time.Now
+	}
+	t := now().UTC()
+
+	ok, err := verifyTOTP(ctx, uc.TOTP, uc.Service, *e, code, t)
+	if err != nil {
+		return RecoveryCodesOutput{}, err
+	}
+	if !ok {
+		return RecoveryCodesOutput{}, ErrInvalidTwoFactorCode
+	}
+
+	codes, err := issueRecoveryCodes(ctx, uc.RecoveryCodes, uc.Service, uc.Opaque, userID, t)
+	if err != nil {
+		return RecoveryCodesOutput{}, err
+	}
+
+	if err := uc.TOTP.Confirm(ctx, userID, t); err != nil {
+		return RecoveryCodesOutput{}, err
+	}
+	return RecoveryCodesOutput{RecoveryCodes: codes}, nil
+}
+
+// RegenerateRecoveryCodesUseCase invalida los códigos de recuperación y emite
+// otros; pide un código TOTP vigente.
+type RegenerateRecoveryCodesUseCase struct {
+	TOTP          domain.TOTPRepository
+	RecoveryCodes domain.RecoveryCodeRepository
+	Service       domain.TOTPService
+	Opaque        domain.OpaqueTokenService
+	Now           func() time.Time
+}
+
+func (uc RegenerateRecoveryCodesUseCase) Execute(ctx context.Context, userID int64, in TwoFactorCodeInput) (RecoveryCodesOutput, error) {
+	code := strings.TrimSpace(in.Code)
+	if code == "" {
+		return RecoveryCodesOutput{}, ErrBadRequest
+	}
+
+	e, err := uc.TOTP.Get(ctx, userID)
+	if err != nil {
+		return RecoveryCodesOutput{}, err
+	}
+	if e == nil || !e.IsConfirmed() {
+		return RecoveryCodesOutput{}, ErrTwoFactorNotEnabled
+	}
+
+	now := uc.Now
+	if now == nil {
+		now = time.Now
+	}
+	t := now().UTC()
+
+	ok, err := verifyTOTP(ctx, uc.TOTP, uc.Service, *e, code, t)
+	if err != nil {
+		return RecoveryCodesOutput{}, err
+	}
+	if !ok {
+		return RecoveryCodesOutput{}, ErrInvalidTwoFactorCode
+	}
+
+	codes, err := issueRecoveryCodes(ctx, uc.RecoveryCodes, uc.Service, uc.Opaque, userID, t)
+	if err != nil {
+		return RecoveryCodesOutput{}, err
+	}
+	return RecoveryCodesOutput{RecoveryCodes: codes}, nil
+}
+
+type DisableTwoFactorInput struct {
+	Password string `json:"password"`
+	Code     string `json:"code"`
+}
+
+// DisableTOTPUseCase apaga el 2FA. Pide contraseña y un código (TOTP o de
+// recuperación) para que un access token robado no alcance.
+type DisableTOTPUseCase struct {
+	UserRepo      domain.UserRepository
+	Hasher        domain.PasswordHasher
+	TOTP          domain.TOTPRepository
+	RecoveryCodes domain.RecoveryCodeRepository
+	Service       domain.TOTPService
+	Opaque        domain.OpaqueTokenService
+	Now           func() time.Time
+}
+
+func (uc DisableTOTPUseCase) Execute(ctx context.Context, userID int64, in DisableTwoFactorInput) error {
+	code := strings.TrimSpace(in.Code)
+	if in.Password == "" || code == "" {
+		return ErrBadRequest
+	}
+
+	u, err := uc.UserRepo.FindByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if u == nil {
+		return ErrUserNotFound
+	}
+
+	ok, err := uc.Hasher.Compare(u.PasswordHash, in.Password)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrInvalidCredentials
+	}
+
+	e, err := uc.TOTP.Get(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if e == nil || !e.IsConfirmed() {
+		return ErrTwoFactorNotEnabled
+	}
+
+	now := uc.Now
+	if now == nil {
+		now = time.Now
+	}
+
+	ok, err = verifySecondFactor(ctx, uc.TOTP, uc.RecoveryCodes, uc.Service, uc.Opaque, *e, code, now().UTC())
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrInvalidTwoFactorCode
+	}
+
+	if err := uc.RecoveryCodes.Replace(ctx, userID, nil, now().UTC()); err != nil {
+		return err
+	}
+	return uc.TOTP.Delete(ctx, userID)
+}
+
+type TwoFactorLoginInput struct {
+	ChallengeToken string `json:"challenge_token"`
+	Code           string `json:"code"` // TOTP o código de recuperación
+
+	ClientIP string `json:"-"`
+}
+
+// TwoFactorLoginUseCase es el segundo paso del login: canjea el challenge más un
+// código válido por la sesión. El challenge sirve para un solo intento: con un
+// código incorrecto hay que volver a hacer login. Los códigos incorrectos además
+// cuentan como logins fallidos de la cuenta (LoginThrottle).
+type TwoFactorLoginUseCase struct {
+	Login LoginUseCase
+}
+
+func (uc TwoFactorLoginUseCase) Execute(ctx context.Context, in TwoFactorLoginInput) (LoginOutput, error) {
+	token := strings.TrimSpace(in.ChallengeToken)
+	code := strings.TrimSpace(in.Code)
+	if token == "" || code == "" {
+		return LoginOutput{}, ErrBadRequest
+	}
+
+	tf := uc.Login.TwoFactor
+	if tf == nil {
+		return LoginOutput{}, ErrInvalidLoginChallenge
+	}
+
+	now := uc.Login.Now
+	if now == nil {
+		now = time.Now
+	}
+	t := now().UTC()
+
+	c, err := tf.Challenges.FindByHash(ctx, uc.Login.Opaque.Hash(token))
+	if err != nil {
+		return LoginOutput{}, err
+	}
+	if c == nil || c.IsUsed() || c.IsExpired(t) {
+		return LoginOutput{}, ErrInvalidLoginChallenge
+	}
+
+	u, err := uc.Login.UserRepo.FindByID(ctx, c.UserID)
+	if err != nil {
+		return LoginOutput{}, err
+	}
+	if u == nil {
+		return LoginOutput{}, ErrInvalidLoginChallenge
+	}
+
+	throttle := uc.Login.Throttle
+	if throttle != nil {
+		retryAfter, err := throttle.Check(ctx, u.Email, in.ClientIP, t)
+		if err != nil {
+			return LoginOutput{RetryAfter: retryAfter}, err
+		}
+	}
+
+	e, err := tf.TOTP.Get(ctx, u.ID)
+	if err != nil {
+		return LoginOutput{}, err
+	}
+	if e == nil || !e.IsConfirmed() {
+		// el 2FA se desactivó después de emitir el challenge
+		return LoginOutput{}, ErrInvalidLoginChallenge
+	}
+
+	// el challenge se reclama antes de verificar: de dos requests concurrentes
+	// sólo uno llega a consumir el código de recuperación
+	used, err := tf.Challenges.MarkUsed(ctx, c.ID, t)
+	if err != nil {
+		return LoginOutput{}, err
+	}
+	if !used {
+		return LoginOutput{}, ErrInvalidLoginChallenge
+	}
+
+	ok, err := verifySecondFactor(ctx, tf.TOTP, tf.RecoveryCodes, tf.Service, uc.Login.Opaque, *e, code, t)
+	if err != nil {
+		return LoginOutput{}, err
+	}
+	if !ok {
+		if throttle != nil {
+			if err := throttle.Failure(ctx, u.Email, in.ClientIP, t); err != nil {
+				return LoginOutput{}, err
+			}
+		}
+		return LoginOutput{}, ErrInvalidTwoFactorCode
+	}
+
+	if throttle != nil {
+		if err := throttle.Success(ctx, u.Email); err != nil {
+			return LoginOutput{}, err
+		}
+	}
+
+	return uc.Login.issueSession(ctx, *u, t)
+}
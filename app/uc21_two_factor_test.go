@@ -0,0 +1,509 @@
+package app_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/moondolphin/crypto-api/app"
+	"github.com/moondolphin/crypto-api/domain"
+	"github.com/moondolphin/crypto-api/test/mocks"
+)
+
+func confirmedTOTP(userID int64, at time.Time) *domain.TOTPEnrollment {
+	return &domain.TOTPEnrollment{UserID: userID, Secret: "SECRET", CreatedAt: at, ConfirmedAt: &at}
+}
+
+func TestUC21GetTwoFactorStatus_EnabledWithRemainingCodes(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	totp := mocks.NewMockTOTPRepository(ctrl)
+	recovery := mocks.NewMockRecoveryCodeRepository(ctrl)
+	now := time.Date(2026, 1, 22, 10, 0, 0, 0, time.UTC)
+
+	totp.EXPECT().Get(gomock.Any(), int64(1)).Return(confirmedTOTP(1, now), nil)
+	recovery.EXPECT().CountUnused(gomock.Any(), int64(1)).Return(7, nil)
+
+	uc := app.GetTwoFactorStatusUseCase{TOTP: totp, RecoveryCodes: recovery}
+
+	// Act
+	out, err := uc.Execute(context.Background(), 1)
+
+	// Assert
+	require.NoError(t, err)
+	require.Equal(t, app.TwoFactorStatusOutput{Enabled: true, RecoveryCodesRemaining: 7}, out)
+}
+
+func TestUC21EnrollTOTP_AlreadyEnabled(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	totp := mocks.NewMockTOTPRepository(ctrl)
+	now := time.Date(2026, 1, 22, 10, 0, 0, 0, time.UTC)
+
+	userRepo.EXPECT().FindByID(gomock.Any(), int64(1)).Return(&domain.User{ID: 1, Email: "john@example.com"}, nil)
+	totp.EXPECT().Get(gomock.Any(), int64(1)).Return(confirmedTOTP(1, now), nil)
+
+	uc := app.EnrollTOTPUseCase{UserRepo: userRepo, TOTP: totp, Service: mocks.NewMockTOTPService(ctrl)}
+
+	// Act
+	_, err := uc.Execute(context.Background(), 1)
+
+	// Assert
+	require.ErrorIs(t, err, app.ErrTwoFactorAlreadyEnabled)
+}
+
+func TestUC21EnrollTOTP_Success_SavesPendingSecret(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	totp := mocks.NewMockTOTPRepository(ctrl)
+	svc := mocks.NewMockTOTPService(ctrl)
+	now := time.Date(2026, 1, 22, 10, 0, 0, 0, time.UTC)
+
+	userRepo.EXPECT().FindByID(gomock.Any(), int64(1)).Return(&domain.User{ID: 1, Email: "john@example.com"}, nil)
+	totp.EXPECT().Get(gomock.Any(), int64(1)).Return(nil, nil)
+	svc.EXPECT().GenerateSecret().Return("JBSWY3DPEHPK3PXP", nil)
+	totp.EXPECT().Save(gomock.Any(), domain.TOTPEnrollment{UserID: 1, Secret: "JBSWY3DPEHPK3PXP", CreatedAt: now}).Return(nil)
+	svc.EXPECT().URI("Crypto API", "john@example.com", "JBSWY3DPEHPK3PXP").Return("otpauth://totp/x")
+
+	uc := app.EnrollTOTPUseCase{
+		UserRepo: userRepo,
+		TOTP:     totp,
+		Service:  svc,
+		Now:      func() time.Time { return now },
+	}
+
+	// Act
+	out, err := uc.Execute(context.Background(), 1)
+
+	// Assert
+	require.NoError(t, err)
+	require.Equal(t, "JBSWY3DPEHPK3PXP", out.Secret)
+	require.Equal(t, "otpauth://totp/x", out.OtpauthURI)
+}
+
+func TestUC21ConfirmTOTP_InvalidCode(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	totp := mocks.NewMockTOTPRepository(ctrl)
+	svc := mocks.NewMockTOTPService(ctrl)
+	now := time.Date(2026, 1, 22, 10, 0, 0, 0, time.UTC)
+
+	totp.EXPECT().Get(gomock.Any(), int64(1)).Return(&domain.TOTPEnrollment{UserID: 1, Secret: "SECRET"}, nil)
+	svc.EXPECT().Validate("SECRET", "123456", now).Return(int64(0), false)
+
+	uc := app.ConfirmTOTPUseCase{TOTP: totp, Service: svc, Now: func() time.Time { return now }}
+
+	// Act
+	_, err := uc.Execute(context.Background(), 1, app.TwoFactorCodeInput{Code: "123456"})
+
+	// Assert
+	require.ErrorIs(t, err, app.ErrInvalidTwoFactorCode)
+}
+
+func TestUC21ConfirmTOTP_NotEnrolled(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	totp := mocks.NewMockTOTPRepository(ctrl)
+	totp.EXPECT().Get(gomock.Any(), int64(1)).Return(nil, nil)
+
+	uc := app.ConfirmTOTPUseCase{TOTP: totp}
+
+	// Act
+	_, err := uc.Execute(context.Background(), 1, app.TwoFactorCodeInput{Code: "123456"})
+
+	// Assert
+	require.ErrorIs(t, err, app.ErrTwoFactorNotEnabled)
+}
+
+func TestUC21ConfirmTOTP_Success_ActivatesAndReturnsRecoveryCodes(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	totp := mocks.NewMockTOTPRepository(ctrl)
+	recovery := mocks.NewMockRecoveryCodeRepository(ctrl)
+	svc := mocks.NewMockTOTPService(ctrl)
+	opaque := mocks.NewMockOpaqueTokenService(ctrl)
+	now := time.Date(2026, 1, 22, 10, 0, 0, 0, time.UTC)
+
+	totp.EXPECT().Get(gomock.Any(), int64(1)).Return(&domain.TOTPEnrollment{UserID: 1, Secret: "SECRET"}, nil)
+	svc.EXPECT().Validate("SECRET", "123456", now).Return(int64(42), true)
+	totp.EXPECT().MarkStepUsed(gomock.Any(), int64(1), int64(42)).Return(true, nil)
+	svc.EXPECT().GenerateRecoveryCode().Return("aaaaa-bbbbb", nil).Times(10)
+	opaque.EXPECT().Hash("aaaaa-bbbbb").Return("h").Times(10)
+	recovery.EXPECT().Replace(gomock.Any(), int64(1), gomock.Len(10), now).Return(nil)
+	totp.EXPECT().Confirm(gomock.Any(), int64(1), now).Return(nil)
+
+	uc := app.ConfirmTOTPUseCase{
+		TOTP:          totp,
+		RecoveryCodes: recovery,
+		Service:       svc,
+		Opaque:        opaque,
+		Now:           func() time.Time { return now },
+	}
+
+	// Act
+	out, err := uc.Execute(context.Background(), 1, app.TwoFactorCodeInput{Code: "123456"})
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, out.RecoveryCodes, 10)
+}
+
+func TestUC21RegenerateRecoveryCodes_RejectsReplayedCode(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	totp := mocks.NewMockTOTPRepository(ctrl)
+	svc := mocks.NewMockTOTPService(ctrl)
+	now := time.Date(2026, 1, 22, 10, 0, 0, 0, time.UTC)
+
+	totp.EXPECT().Get(gomock.Any(), int64(1)).Return(confirmedTOTP(1, now), nil)
+	svc.EXPECT().Validate("SECRET", "123456", now).Return(int64(42), true)
+	totp.EXPECT().MarkStepUsed(gomock.Any(), int64(1), int64(42)).Return(false, nil)
+
+	uc := app.RegenerateRecoveryCodesUseCase{TOTP: totp, Service: svc, Now: func() time.Time { return now }}
+
+	// Act
+	_, err := uc.Execute(context.Background(), 1, app.TwoFactorCodeInput{Code: "123456"})
+
+	// Assert
+	require.ErrorIs(t, err, app.ErrInvalidTwoFactorCode)
+}
+
+func TestUC21DisableTOTP_InvalidPassword(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	hasher := mocks.NewMockPasswordHasher(ctrl)
+
+	userRepo.EXPECT().FindByID(gomock.Any(), int64(1)).Return(&domain.User{ID: 1, PasswordHash: "hash"}, nil)
+	hasher.EXPECT().Compare("hash", "wrong").Return(false, nil)
+
+	uc := app.DisableTOTPUseCase{UserRepo: userRepo, Hasher: hasher}
+
+	// Act
+	err := uc.Execute(context.Background(), 1, app.DisableTwoFactorInput{Password: "wrong", Code: "123456"})
+
+	// Assert
+	require.ErrorIs(t, err, app.ErrInvalidCredentials)
+}
+
+func TestUC21DisableTOTP_Success_WithRecoveryCode(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	hasher := mocks.NewMockPasswordHasher(ctrl)
+	totp := mocks.NewMockTOTPRepository(ctrl)
+	recovery := mocks.NewMockRecoveryCodeRepository(ctrl)
+	opaque := mocks.NewMockOpaqueTokenService(ctrl)
+	now := time.Date(2026, 1, 22, 10, 0, 0, 0, time.UTC)
+
+	userRepo.EXPECT().FindByID(gomock.Any(), int64(1)).Return(&domain.User{ID: 1, PasswordHash: "hash"}, nil)
+	hasher.EXPECT().Compare("hash", "SecurePassword123").Return(true, nil)
+	totp.EXPECT().Get(gomock.Any(), int64(1)).Return(confirmedTOTP(1, now), nil)
+	// se normaliza a minúsculas con guion, como se emitió
+	opaque.EXPECT().Hash("abcde-fghjk").Return("rc-hash")
+	recovery.EXPECT().Use(gomock.Any(), int64(1), "rc-hash", now).Return(true, nil)
+	recovery.EXPECT().Replace(gomock.Any(), int64(1), nil, now).Return(nil)
+	totp.EXPECT().Delete(gomock.Any(), int64(1)).Return(nil)
+
+	uc := app.DisableTOTPUseCase{
+		UserRepo:      userRepo,
+		Hasher:        hasher,
+		TOTP:          totp,
+		RecoveryCodes: recovery,
+		Opaque:        opaque,
+		Now:           func() time.Time { return now },
+	}
+
+	// Act
+	err := uc.Execute(context.Background(), 1, app.DisableTwoFactorInput{Password: "SecurePassword123", Code: "ABCDE FGHJK"})
+
+	// Assert
+	require.NoError(t, err)
+}
+
+func TestUC03Login_TwoFactor_ReturnsChallengeInsteadOfTokens(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	hasher := mocks.NewMockPasswordHasher(ctrl)
+	totp := mocks.NewMockTOTPRepository(ctrl)
+	challenges := mocks.NewMockLoginChallengeRepository(ctrl)
+	opaque := mocks.NewMockOpaqueTokenService(ctrl)
+	store := mocks.NewMockLoginAttemptStore(ctrl)
+	now := time.Date(2026, 1, 22, 10, 0, 0, 0, time.UTC)
+
+	store.EXPECT().Get(gomock.Any(), gomock.Any()).Return(nil, nil).Times(2)
+	userRepo.EXPECT().FindByEmail(gomock.Any(), "john@example.com").Return(&domain.User{ID: 1, Email: "john@example.com", PasswordHash: "hash"}, nil)
+	hasher.EXPECT().Compare("hash", "SecurePassword123").Return(true, nil)
+	totp.EXPECT().Get(gomock.Any(), int64(1)).Return(confirmedTOTP(1, now), nil)
+	opaque.EXPECT().Generate().Return("raw-challenge", nil)
+	opaque.EXPECT().Hash("raw-challenge").Return("challenge-hash")
+	challenges.EXPECT().
+		Create(gomock.Any(), domain.LoginChallenge{UserID: 1, TokenHash: "challenge-hash", ExpiresAt: now.Add(2 * time.Minute), CreatedAt: now}).
+		DoAndReturn(func(_ context.Context, c domain.LoginChallenge) (domain.LoginChallenge, error) {
+			c.ID = 9
+			return c, nil
+		})
+	// sin Reset del contador ni tokens emitidos: falta el segundo factor
+
+	uc := app.LoginUseCase{
+		UserRepo: userRepo,
+		Hasher:   hasher,
+		Tokens:   mocks.NewMockTokenService(ctrl),
+		Opaque:   opaque,
+		Now:      func() time.Time { return now },
+		Throttle: &app.LoginThrottle{Store: store},
+		TwoFactor: &app.TwoFactorLogin{
+			TOTP:         totp,
+			Challenges:   challenges,
+			ChallengeTTL: 2 * time.Minute,
+		},
+	}
+
+	// Act
+	out, err := uc.Execute(context.Background(), app.LoginInput{
+		Email:    "john@example.com",
+		Password: "SecurePassword123",
+		ClientIP: "10.0.0.1",
+	})
+
+	// Assert
+	require.NoError(t, err)
+	require.Empty(t, out.AccessToken)
+	require.NotNil(t, out.Challenge)
+	require.True(t, out.Challenge.TwoFactorRequired)
+	require.Equal(t, "raw-challenge", out.Challenge.ChallengeToken)
+	require.Equal(t, now.Add(2*time.Minute), out.Challenge.ExpiresAt)
+}
+
+func TestUC03Login_TwoFactor_PendingEnrollmentIsIgnored(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	hasher := mocks.NewMockPasswordHasher(ctrl)
+	tokens := mocks.NewMockTokenService(ctrl)
+	totp := mocks.NewMockTOTPRepository(ctrl)
+
+	userRepo.EXPECT().FindByEmail(gomock.Any(), "john@example.com").Return(&domain.User{ID: 1, Email: "john@example.com", PasswordHash: "hash"}, nil)
+	hasher.EXPECT().Compare("hash", "SecurePassword123").Return(true, nil)
+	totp.EXPECT().Get(gomock.Any(), int64(1)).Return(&domain.TOTPEnrollment{UserID: 1, Secret: "SECRET"}, nil)
+	tokens.EXPECT().Generate(int64(1), "john@example.com", domain.RoleUser).Return("tok", nil)
+
+	uc := app.LoginUseCase{
+		UserRepo:  userRepo,
+		Hasher:    hasher,
+		Tokens:    tokens,
+		TwoFactor: &app.TwoFactorLogin{TOTP: totp},
+	}
+
+	// Act
+	out, err := uc.Execute(context.Background(), app.LoginInput{Email: "john@example.com", Password: "SecurePassword123"})
+
+	// Assert
+	require.NoError(t, err)
+	require.Nil(t, out.Challenge)
+	require.Equal(t, "tok", out.AccessToken)
+}
+
+type twoFactorLoginMocks struct {
+	userRepo   *mocks.MockUserRepository
+	tokens     *mocks.MockTokenService
+	totp       *mocks.MockTOTPRepository
+	recovery   *mocks.MockRecoveryCodeRepository
+	challenges *mocks.MockLoginChallengeRepository
+	svc        *mocks.MockTOTPService
+	opaque     *mocks.MockOpaqueTokenService
+	store      *mocks.MockLoginAttemptStore
+}
+
+func newTwoFactorLoginUC(ctrl *gomock.Controller, now time.Time) (app.TwoFactorLoginUseCase, twoFactorLoginMocks) {
+	m := twoFactorLoginMocks{
+		userRepo:   mocks.NewMockUserRepository(ctrl),
+		tokens:     mocks.NewMockTokenService(ctrl),
+		totp:       mocks.NewMockTOTPRepository(ctrl),
+		recovery:   mocks.NewMockRecoveryCodeRepository(ctrl),
+		challenges: mocks.NewMockLoginChallengeRepository(ctrl),
+		svc:        mocks.NewMockTOTPService(ctrl),
+		opaque:     mocks.NewMockOpaqueTokenService(ctrl),
+		store:      mocks.NewMockLoginAttemptStore(ctrl),
+	}
+	uc := app.TwoFactorLoginUseCase{Login: app.LoginUseCase{
+		UserRepo: m.userRepo,
+		Tokens:   m.tokens,
+		Opaque:   m.opaque,
+		Now:      func() time.Time { return now },
+		Throttle: &app.LoginThrottle{Store: m.store},
+		TwoFactor: &app.TwoFactorLogin{
+			TOTP:          m.totp,
+			RecoveryCodes: m.recovery,
+			Challenges:    m.challenges,
+			Service:       m.svc,
+		},
+	}}
+	return uc, m
+}
+
+func TestUC21TwoFactorLogin_BadRequest_WhenMissingCode(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	uc, _ := newTwoFactorLoginUC(ctrl, time.Now())
+
+	// Act
+	_, err := uc.Execute(context.Background(), app.TwoFactorLoginInput{ChallengeToken: "raw"})
+
+	// Assert
+	require.ErrorIs(t, err, app.ErrBadRequest)
+}
+
+func TestUC21TwoFactorLogin_InvalidChallenge_WhenExpired(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Date(2026, 1, 22, 10, 0, 0, 0, time.UTC)
+	uc, m := newTwoFactorLoginUC(ctrl, now)
+
+	m.opaque.EXPECT().Hash("raw").Return("challenge-hash")
+	m.challenges.EXPECT().FindByHash(gomock.Any(), "challenge-hash").
+		Return(&domain.LoginChallenge{ID: 9, UserID: 1, ExpiresAt: now}, nil)
+
+	// Act
+	_, err := uc.Execute(context.Background(), app.TwoFactorLoginInput{ChallengeToken: "raw", Code: "123456"})
+
+	// Assert
+	require.ErrorIs(t, err, app.ErrInvalidLoginChallenge)
+}
+
+func TestUC21TwoFactorLogin_WrongCode_CountsAsFailedLogin(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Date(2026, 1, 22, 10, 0, 0, 0, time.UTC)
+	uc, m := newTwoFactorLoginUC(ctrl, now)
+
+	m.opaque.EXPECT().Hash("raw").Return("challenge-hash")
+	m.challenges.EXPECT().FindByHash(gomock.Any(), "challenge-hash").
+		Return(&domain.LoginChallenge{ID: 9, UserID: 1, ExpiresAt: now.Add(time.Minute)}, nil)
+	m.userRepo.EXPECT().FindByID(gomock.Any(), int64(1)).Return(&domain.User{ID: 1, Email: "john@example.com"}, nil)
+	m.store.EXPECT().Get(gomock.Any(), gomock.Any()).Return(nil, nil).Times(2)
+	m.totp.EXPECT().Get(gomock.Any(), int64(1)).Return(confirmedTOTP(1, now), nil)
+	m.challenges.EXPECT().MarkUsed(gomock.Any(), int64(9), now).Return(true, nil)
+	m.svc.EXPECT().Validate("SECRET", "000000", now).Return(int64(0), false)
+	m.store.EXPECT().RecordFailure(gomock.Any(), "account:john@example.com", now, gomock.Any()).Return(1, nil)
+	m.store.EXPECT().RecordFailure(gomock.Any(), "ip:10.0.0.1", now, gomock.Any()).Return(1, nil)
+
+	// Act
+	_, err := uc.Execute(context.Background(), app.TwoFactorLoginInput{ChallengeToken: "raw", Code: "000000", ClientIP: "10.0.0.1"})
+
+	// Assert
+	require.ErrorIs(t, err, app.ErrInvalidTwoFactorCode)
+}
+
+func TestUC21TwoFactorLogin_TooManyAttempts_WhenAccountLocked(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Date(2026, 1, 22, 10, 0, 0, 0, time.UTC)
+	until := now.Add(time.Minute)
+	uc, m := newTwoFactorLoginUC(ctrl, now)
+
+	m.opaque.EXPECT().Hash("raw").Return("challenge-hash")
+	m.challenges.EXPECT().FindByHash(gomock.Any(), "challenge-hash").
+		Return(&domain.LoginChallenge{ID: 9, UserID: 1, ExpiresAt: now.Add(time.Minute)}, nil)
+	m.userRepo.EXPECT().FindByID(gomock.Any(), int64(1)).Return(&domain.User{ID: 1, Email: "john@example.com"}, nil)
+	m.store.EXPECT().Get(gomock.Any(), "account:john@example.com").Return(&domain.LoginAttempt{LockedUntil: &until}, nil)
+
+	// Act
+	out, err := uc.Execute(context.Background(), app.TwoFactorLoginInput{ChallengeToken: "raw", Code: "123456"})
+
+	// Assert
+	require.ErrorIs(t, err, app.ErrTooManyLoginAttempts)
+	require.Equal(t, time.Minute, out.RetryAfter)
+}
+
+func TestUC21TwoFactorLogin_Success_ConsumesChallengeAndIssuesTokens(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Date(2026, 1, 22, 10, 0, 0, 0, time.UTC)
+	uc, m := newTwoFactorLoginUC(ctrl, now)
+
+	m.opaque.EXPECT().Hash("raw").Return("challenge-hash")
+	m.challenges.EXPECT().FindByHash(gomock.Any(), "challenge-hash").
+		Return(&domain.LoginChallenge{ID: 9, UserID: 1, ExpiresAt: now.Add(time.Minute)}, nil)
+	m.userRepo.EXPECT().FindByID(gomock.Any(), int64(1)).Return(&domain.User{ID: 1, Email: "john@example.com", Role: domain.RoleAdmin}, nil)
+	m.store.EXPECT().Get(gomock.Any(), gomock.Any()).Return(nil, nil).Times(2)
+	m.totp.EXPECT().Get(gomock.Any(), int64(1)).Return(confirmedTOTP(1, now), nil)
+	gomock.InOrder(
+		m.challenges.EXPECT().MarkUsed(gomock.Any(), int64(9), now).Return(true, nil),
+		m.svc.EXPECT().Validate("SECRET", "123456", now).Return(int64(42), true),
+		m.totp.EXPECT().MarkStepUsed(gomock.Any(), int64(1), int64(42)).Return(true, nil),
+	)
+	m.store.EXPECT().Reset(gomock.Any(), "account:john@example.com").Return(nil)
+	m.tokens.EXPECT().Generate(int64(1), "john@example.com", domain.RoleAdmin).Return("tok", nil)
+
+	// Act
+	out, err := uc.Execute(context.Background(), app.TwoFactorLoginInput{ChallengeToken: "raw", Code: "123456", ClientIP: "10.0.0.1"})
+
+	// Assert
+	require.NoError(t, err)
+	require.Equal(t, "tok", out.AccessToken)
+	require.Equal(t, now.Add(60*time.Minute), out.ExpiresAt)
+}
+
+func TestUC21TwoFactorLogin_AlreadyConsumed_DoesNotBurnRecoveryCode(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Date(2026, 1, 22, 10, 0, 0, 0, time.UTC)
+	uc, m := newTwoFactorLoginUC(ctrl, now)
+
+	m.opaque.EXPECT().Hash("raw").Return("challenge-hash")
+	m.challenges.EXPECT().FindByHash(gomock.Any(), "challenge-hash").
+		Return(&domain.LoginChallenge{ID: 9, UserID: 1, ExpiresAt: now.Add(time.Minute)}, nil)
+	m.userRepo.EXPECT().FindByID(gomock.Any(), int64(1)).Return(&domain.User{ID: 1, Email: "john@example.com"}, nil)
+	m.store.EXPECT().Get(gomock.Any(), gomock.Any()).Return(nil, nil).Times(2)
+	m.totp.EXPECT().Get(gomock.Any(), int64(1)).Return(confirmedTOTP(1, now), nil)
+	// otro request canjeó el mismo challenge en paralelo: el código de
+	// recuperación no se verifica ni se gasta (sin expectativas en m.recovery)
+	m.challenges.EXPECT().MarkUsed(gomock.Any(), int64(9), now).Return(false, nil)
+
+	// Act
+	_, err := uc.Execute(context.Background(), app.TwoFactorLoginInput{ChallengeToken: "raw", Code: "abcde-fghjk", ClientIP: "10.0.0.1"})
+
+	// Assert
+	require.ErrorIs(t, err, app.ErrInvalidLoginChallenge)
+}
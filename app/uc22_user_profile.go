@@ -0,0 +1,507 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/moondolphin/crypto-api/domain"
+)
+
+var (
+	ErrCurrentPasswordRequired = errors.New("current_password_required")
+	ErrRecentLoginRequired     = errors.New("recent_login_required")
+)
+
+const defaultDeleteAccountFreshSession = 5 * time.Minute
+
+func toUserOutput(u domain.User) UserOutput {
+	return UserOutput{
+		ID:            u.ID,
+		Email:         u.Email,
+		Name:          u.Name,
+		Role:          u.Role,
+		CreatedAt:     u.CreatedAt,
+		EmailVerified: u.IsEmailVerified(),
+	}
+}
+
+// revokeSessions cierra todas las sesiones del usuario: refresh tokens, API keys
+// y, con un corte en la denylist, los access tokens emitidos hasta t. Los tres
+// repos son opcionales.
+func revokeSessions(ctx context.Context, refresh domain.RefreshTokenRepository, apiKeys domain.APIKeyRepository, revocations domain.TokenRevocationStore, userID int64, t time.Time, ttl time.Duration) error {
+	if refresh != nil {
+		if err := refresh.RevokeByUser(ctx, userID, t); err != nil {
+			return err
+		}
+	}
+	if apiKeys != nil {
+		if err := apiKeys.RevokeByUser(ctx, userID, t); err != nil {
+			return err
+		}
+	}
+	if revocations != nil {
+		if ttl <= 0 {
+			ttl = 60 * time.Minute
+		}
+		if err := revocations.RevokeUser(ctx, userID, t, t.Add(ttl)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkPassword compara password con el hash del usuario: ErrBadRequest si
+// viene vacía, ErrInvalidCredentials si no coincide.
+func checkPassword(hasher domain.PasswordHasher, u domain.User, password string) error {
+	if password == "" {
+		return ErrBadRequest
+	}
+	ok, err := hasher.Compare(u.PasswordHash, password)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrInvalidCredentials
+	}
+	return nil
+}
+
+func findUser(ctx context.Context, repo domain.UserRepository, userID int64) (*domain.User, error) {
+	u, err := repo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if u == nil {
+		return nil, ErrUserNotFound
+	}
+	return u, nil
+}
+
+type GetProfileUseCase struct {
+	UserRepo domain.UserRepository
+}
+
+func (uc GetProfileUseCase) Execute(ctx context.Context, userID int64) (UserOutput, error) {
+	u, err := findUser(ctx, uc.UserRepo, userID)
+	if err != nil {
+		return UserOutput{}, err
+	}
+	return toUserOutput(*u), nil
+}
+
+// UpdateProfileInput: los campos nil no se tocan.
+type UpdateProfileInput struct {
+	Name  *string `json:"name"`
+	Email *string `json:"email"`
+
+	// obligatoria sólo para cambiar el email
+	CurrentPassword string `json:"current_password"`
+}
+
+type UpdateProfileUseCase struct {
+	UserRepo domain.UserRepository
+	Hasher   domain.PasswordHasher
+	Now      func() time.Time
+
+	// opcional: con Verification el email nuevo queda sin verificar y se manda el mail
+	Verification *SendEmailVerificationUseCase
+}
+
+func (uc UpdateProfileUseCase) Execute(ctx context.Context, userID int64, in UpdateProfileInput) (UserOutput, error) {
+	if in.Name == nil && in.Email == nil {
+		return UserOutput{}, ErrBadRequest
+	}
+
+	u, err := findUser(ctx, uc.UserRepo, userID)
+	if err != nil {
+		return UserOutput{}, err
+	}
+	updated := *u
+
+	if in.Name != nil {
+		updated.Name = strings.TrimSpace(*in.Name)
+		if updated.Name == "" {
+			return UserOutput{}, ErrInvalidName
+		}
+	}
+
+	emailChanged := false
+	if in.Email != nil {
+		email := strings.ToLower(strings.TrimSpace(*in.Email))
+		if !strings.Contains(email, "@") || len(email) < 5 {
+			return UserOutput{}, ErrInvalidEmail
+		}
+		emailChanged = email != u.Email
+		updated.Email = email
+	}
+
+	if emailChanged {
+		// con un access token robado no alcanza para quedarse con la cuenta
+		if in.CurrentPassword == "" {
+			return UserOutput{}, ErrCurrentPasswordRequired
+		}
+		if err := checkPassword(uc.Hasher, *u, in.CurrentPassword); err != nil {
+			return UserOutput{}, err
+		}
+
+		exists, err := uc.UserRepo.ExistsByEmail(ctx, updated.Email)
+		if err != nil {
+			return UserOutput{}, err
+		}
+		if exists {
+			return UserOutput{}, ErrEmailAlreadyRegistered
+		}
+
+		now := uc.Now
+		if now == nil {
+			now = time.Now
+		}
+		if uc.Verification != nil {
+			updated.EmailVerifiedAt = nil
+		} else {
+			verifiedAt := now().UTC().Truncate(time.Second)
+			updated.EmailVerifiedAt = &verifiedAt
+		}
+	}
+
+	if err := uc.UserRepo.UpdateProfile(ctx, updated); err != nil {
+		return UserOutput{}, err
+	}
+
+	if emailChanged && uc.Verification != nil {
+		if err := uc.Verification.Send(ctx, updated); err != nil {
+			log.Printf("Warning: failed to send verification email: %v", err)
+		}
+	}
+
+	return toUserOutput(updated), nil
+}
+
+type ChangePasswordInput struct {
+	CurrentPassword string `json:"current_password"`
+	NewPassword     string `json:"new_password"`
+}
+
+// ChangePasswordUseCase cambia la contraseña verificando la actual y cierra
+// todas las sesiones (incluida la que hizo el cambio) y las API keys.
+type ChangePasswordUseCase struct {
+	UserRepo      domain.UserRepository
+	Hasher        domain.PasswordHasher
+	RefreshTokens domain.RefreshTokenRepository // opcional
+	APIKeys       domain.APIKeyRepository       // opcional
+	Revocations   domain.TokenRevocationStore   // opcional
+	Now           func() time.Time
+	TTL           time.Duration
+}
+
+func (uc ChangePasswordUseCase) Execute(ctx context.Context, userID int64, in ChangePasswordInput) error {
+	if in.CurrentPassword == "" || in.NewPassword == "" {
+		return ErrBadRequest
+	}
+	if len(in.NewPassword) < 8 {
+		return ErrInvalidPassword
+	}
+
+	u, err := findUser(ctx, uc.UserRepo, userID)
+	if err != nil {
+		return err
+	}
+	if err := checkPassword(uc.Hasher, *u, in.CurrentPassword); err != nil {
+		return err
+	}
+
+	hash, err := uc.Hasher.Hash(in.NewPassword)
+	if err != nil {
+		return err
+	}
+	if err := uc.UserRepo.UpdatePassword(ctx, u.ID, hash); err != nil {
+		return err
+	}
+
+	now := uc.Now
+	if now == nil {
+		now = time.Now
+	}
+	return revokeSessions(ctx, uc.RefreshTokens, uc.APIKeys, uc.Revocations, u.ID, now().UTC(), uc.TTL)
+}
+
+type DeleteAccountInput struct {
+	// obligatoria salvo en cuentas con login OIDC y sesión reciente
+	Password string `json:"password,omitempty"`
+
+	// emisión del access token de la request; lo completa el handler
+	SessionIssuedAt time.Time `json:"-"`
+}
+
+// DeleteAccountUseCase borra la cuenta del usuario. Los favoritos se quitan
+// explícitamente y las sesiones y API keys se revocan; el resto de sus datos
+// (tokens, 2FA) cae por la cascada de users.
+//
+// Pide la contraseña. Una cuenta creada por OIDC tiene una aleatoria que el
+// usuario nunca vio: si tiene identidad OIDC alcanza con un access token
+// emitido hace menos de FreshSession (un login reciente).
+type DeleteAccountUseCase struct {
+	UserRepo      domain.UserRepository
+	Hasher        domain.PasswordHasher
+	Favorites     domain.FavoritesRepository
+	RefreshTokens domain.RefreshTokenRepository // opcional
+	APIKeys       domain.APIKeyRepository       // opcional
+	Revocations   domain.TokenRevocationStore   // opcional
+	Identities    domain.UserIdentityRepository // opcional: sin él siempre pide contraseña
+	Now           func() time.Time
+	TTL           time.Duration
+	FreshSession  time.Duration // default: 5 minutos
+}
+
+func (uc DeleteAccountUseCase) Execute(ctx context.Context, userID int64, in DeleteAccountInput) error {
+	now := uc.Now
+	if now == nil {
+		now = time.Now
+	}
+
+	u, err := findUser(ctx, uc.UserRepo, userID)
+	if err != nil {
+		return err
+	}
+	if err := uc.authorize(ctx, *u, in, now().UTC()); err != nil {
+		return err
+	}
+
+	coins, err := uc.Favorites.ListFavoriteCoinIDsByUser(ctx, u.ID)
+	if err != nil {
+		return err
+	}
+	for _, c := range coins {
+		if err := uc.Favorites.RemoveFavoriteCoinFromUser(ctx, u.ID, c.ID); err != nil {
+			return err
+		}
+	}
+
+	// los access tokens y API keys vigentes no deben sobrevivir a la cuenta
+	if err := revokeSessions(ctx, uc.RefreshTokens, uc.APIKeys, uc.Revocations, u.ID, now().UTC(), uc.TTL); err != nil {
+		return err
+	}
+
+	return uc.UserRepo.Delete(ctx, u.ID)
+}
+
+// authorize confirma que quien borra es el dueño: con la contraseña o, sin
+// ella y sólo en cuentas con identidad OIDC, con una sesión reciente.
+func (uc DeleteAccountUseCase) authorize(ctx context.Context, u domain.User, in DeleteAccountInput, t time.Time) error {
+	if in.Password != "" || uc.Identities == nil {
+		return checkPassword(uc.Hasher, u, in.Password)
+	}
+
+	identities, err := uc.Identities.ListByUser(ctx, u.ID)
+	if err != nil {
+		return err
+	}
+	if len(identities) == 0 {
+		return ErrBadRequest
+	}
+
+	fresh := uc.FreshSession
+	if fresh <= 0 {
+		fresh = defaultDeleteAccountFreshSession
+	}
+	if in.SessionIssuedAt.IsZero() || t.Sub(in.SessionIssuedAt) > fresh {
+		return ErrRecentLoginRequired
+	}
+	return nil
+}
+
+type ExportedUser struct {
+	UserOutput
+	EmailVerifiedAt *time.Time `json:"email_verified_at,omitempty"`
+}
+
+type ExportedIdentity struct {
+	Issuer    string    `json:"issuer"`
+	Subject   string    `json:"subject"`
+	Email     string    `json:"email"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type ExportedTransaction struct {
+	PortfolioID int64 `json:"portfolio_id"`
+	TransactionOutput
+}
+
+type ExportedSession struct {
+	ID        int64     `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// UserDataExport es todo lo que se guarda del usuario. No incluye secretos
+// (hash de la contraseña, hashes de tokens ni el secreto TOTP).
+type UserDataExport struct {
+	ExportedAt   time.Time               `json:"exported_at"`
+	User         ExportedUser            `json:"user"`
+	Favorites    []FavoriteCoinOutput    `json:"favorites"`
+	APIKeys      []APIKeyOutput          `json:"api_keys"`
+	Sessions     []ExportedSession       `json:"sessions"`
+	TwoFactor    TwoFactorStatusOutput   `json:"two_factor"`
+	Identities   []ExportedIdentity      `json:"identities"`
+	Alerts       []AlertOutput           `json:"alerts"`
+	Webhooks     []WebhookOutput         `json:"webhooks"`
+	Portfolios   []PortfolioDetailOutput `json:"portfolios"`
+	Transactions []ExportedTransaction   `json:"transactions"`
+	Watchlists   []WatchlistDetailOutput `json:"watchlists"`
+}
+
+type ExportUserDataUseCase struct {
+	UserRepo         domain.UserRepository
+	Favorites        domain.FavoritesRepository
+	APIKeys          domain.APIKeyRepository
+	RefreshTokens    domain.RefreshTokenRepository
+	TOTP             domain.TOTPRepository                // opcional
+	RecoveryCodes    domain.RecoveryCodeRepository        // opcional
+	Identities       domain.UserIdentityRepository        // opcional
+	Alerts           domain.AlertRuleRepository           // opcional
+	Webhooks         domain.WebhookSubscriptionRepository // opcional
+	Portfolios       domain.PortfolioRepository           // opcional; requiere Holdings
+	Holdings         domain.HoldingRepository
+	Transactions     domain.TransactionRepository // opcional
+	Watchlists       domain.WatchlistRepository   // opcional; requiere WatchlistEntries
+	WatchlistEntries domain.WatchlistEntryRepository
+	Now              func() time.Time
+}
+
+func (uc ExportUserDataUseCase) Execute(ctx context.Context, userID int64) (UserDataExport, error) {
+	u, err := findUser(ctx, uc.UserRepo, userID)
+	if err != nil {
+		return UserDataExport{}, err
+	}
+
+	now := uc.Now
+	if now == nil {
+		now = time.Now
+	}
+	t := now().UTC()
+
+	out := UserDataExport{
+		ExportedAt:   t,
+		User:         ExportedUser{UserOutput: toUserOutput(*u), EmailVerifiedAt: u.EmailVerifiedAt},
+		Favorites:    []FavoriteCoinOutput{},
+		APIKeys:      []APIKeyOutput{},
+		Sessions:     []ExportedSession{},
+		Identities:   []ExportedIdentity{},
+		Alerts:       []AlertOutput{},
+		Webhooks:     []WebhookOutput{},
+		Portfolios:   []PortfolioDetailOutput{},
+		Transactions: []ExportedTransaction{},
+		Watchlists:   []WatchlistDetailOutput{},
+	}
+
+	coins, err := uc.Favorites.ListFavoriteCoinIDsByUser(ctx, u.ID)
+	if err != nil {
+		return UserDataExport{}, err
+	}
+	for _, c := range coins {
+		out.Favorites = append(out.Favorites, FavoriteCoinOutput{
+			ID:            c.ID,
+			Symbol:        c.Symbol,
+			Enabled:       c.Enabled,
+			CoinGeckoID:   c.CoinGeckoID,
+			BinanceSymbol: c.BinanceSymbol,
+		})
+	}
+
+	keys, err := uc.APIKeys.ListByUser(ctx, u.ID)
+	if err != nil {
+		return UserDataExport{}, err
+	}
+	for _, k := range keys {
+		out.APIKeys = append(out.APIKeys, toAPIKeyOutput(k))
+	}
+
+	sessions, err := uc.RefreshTokens.ListActiveByUser(ctx, u.ID, t)
+	if err != nil {
+		return UserDataExport{}, err
+	}
+	for _, s := range sessions {
+		out.Sessions = append(out.Sessions, ExportedSession{ID: s.ID, CreatedAt: s.CreatedAt, ExpiresAt: s.ExpiresAt})
+	}
+
+	if uc.TOTP != nil {
+		status, err := GetTwoFactorStatusUseCase{TOTP: uc.TOTP, RecoveryCodes: uc.RecoveryCodes}.Execute(ctx, u.ID)
+		if err != nil {
+			return UserDataExport{}, err
+		}
+		out.TwoFactor = status
+	}
+
+	if uc.Identities != nil {
+		identities, err := uc.Identities.ListByUser(ctx, u.ID)
+		if err != nil {
+			return UserDataExport{}, err
+		}
+		for _, i := range identities {
+			out.Identities = append(out.Identities, ExportedIdentity{Issuer: i.Issuer, Subject: i.Subject, Email: i.Email, CreatedAt: i.CreatedAt})
+		}
+	}
+
+	if uc.Alerts != nil {
+		alerts, err := ListAlertsUseCase{Rules: uc.Alerts}.Execute(ctx, u.ID)
+		if err != nil {
+			return UserDataExport{}, err
+		}
+		out.Alerts = alerts
+	}
+
+	if uc.Webhooks != nil {
+		// sin secretos: sólo URL y eventos
+		webhooks, err := ListWebhooksUseCase{Subscriptions: uc.Webhooks}.Execute(ctx, u.ID)
+		if err != nil {
+			return UserDataExport{}, err
+		}
+		out.Webhooks = webhooks
+	}
+
+	if uc.Portfolios != nil {
+		portfolios, err := uc.Portfolios.ListByUser(ctx, u.ID)
+		if err != nil {
+			return UserDataExport{}, err
+		}
+		get := GetPortfolioUseCase{Portfolios: uc.Portfolios, Holdings: uc.Holdings}
+		for _, p := range portfolios {
+			detail, err := get.Execute(ctx, u.ID, p.ID)
+			if err != nil {
+				return UserDataExport{}, err
+			}
+			out.Portfolios = append(out.Portfolios, detail)
+
+			if uc.Transactions == nil {
+				continue
+			}
+			txs, err := uc.Transactions.ListByPortfolio(ctx, p.ID, domain.TransactionFilter{})
+			if err != nil {
+				return UserDataExport{}, err
+			}
+			for _, tx := range txs {
+				out.Transactions = append(out.Transactions, ExportedTransaction{PortfolioID: p.ID, TransactionOutput: toTransactionOutput(tx)})
+			}
+		}
+	}
+
+	if uc.Watchlists != nil {
+		watchlists, err := uc.Watchlists.ListByUser(ctx, u.ID)
+		if err != nil {
+			return UserDataExport{}, err
+		}
+		get := GetWatchlistUseCase{Watchlists: uc.Watchlists, Entries: uc.WatchlistEntries}
+		for _, w := range watchlists {
+			detail, err := get.Execute(ctx, u.ID, w.ID)
+			if err != nil {
+				return UserDataExport{}, err
+			}
+			out.Watchlists = append(out.Watchlists, detail)
+		}
+	}
+
+	return out, nil
+}
@@ -0,0 +1,459 @@
+package app_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/moondolphin/crypto-api/app"
+	"github.com/moondolphin/crypto-api/domain"
+	"github.com/moondolphin/crypto-api/test/mocks"
+)
+
+func strPtr(s string) *string { return &s }
+
+func profileUser(now time.Time) *domain.User {
+	return &domain.User{
+		ID:              7,
+		Email:           "john@example.com",
+		Name:            "John",
+		PasswordHash:    "hash",
+		Role:            domain.RoleUser,
+		CreatedAt:       now,
+		EmailVerifiedAt: &now,
+	}
+}
+
+func TestUC22GetProfile_NotFound(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	userRepo.EXPECT().FindByID(gomock.Any(), int64(7)).Return(nil, nil)
+
+	uc := app.GetProfileUseCase{UserRepo: userRepo}
+
+	// Act
+	_, err := uc.Execute(context.Background(), 7)
+
+	// Assert
+	require.ErrorIs(t, err, app.ErrUserNotFound)
+}
+
+func TestUC22UpdateProfile_NameOnly_DoesNotAskPassword(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	hasher := mocks.NewMockPasswordHasher(ctrl)
+	now := time.Date(2026, 1, 23, 10, 0, 0, 0, time.UTC)
+
+	u := profileUser(now)
+	userRepo.EXPECT().FindByID(gomock.Any(), int64(7)).Return(u, nil)
+	expected := *u
+	expected.Name = "Johnny"
+	userRepo.EXPECT().UpdateProfile(gomock.Any(), expected).Return(nil)
+
+	uc := app.UpdateProfileUseCase{UserRepo: userRepo, Hasher: hasher, Now: func() time.Time { return now }}
+
+	// Act
+	out, err := uc.Execute(context.Background(), 7, app.UpdateProfileInput{Name: strPtr("  Johnny ")})
+
+	// Assert
+	require.NoError(t, err)
+	require.Equal(t, "Johnny", out.Name)
+	require.Equal(t, "john@example.com", out.Email)
+	require.True(t, out.EmailVerified)
+}
+
+func TestUC22UpdateProfile_EmptyBody(t *testing.T) {
+	// Arrange
+	uc := app.UpdateProfileUseCase{}
+
+	// Act
+	_, err := uc.Execute(context.Background(), 7, app.UpdateProfileInput{})
+
+	// Assert
+	require.ErrorIs(t, err, app.ErrBadRequest)
+}
+
+func TestUC22UpdateProfile_EmailChangeRequiresPassword(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	now := time.Date(2026, 1, 23, 10, 0, 0, 0, time.UTC)
+	userRepo.EXPECT().FindByID(gomock.Any(), int64(7)).Return(profileUser(now), nil)
+
+	uc := app.UpdateProfileUseCase{UserRepo: userRepo, Hasher: mocks.NewMockPasswordHasher(ctrl)}
+
+	// Act
+	_, err := uc.Execute(context.Background(), 7, app.UpdateProfileInput{Email: strPtr("new@example.com")})
+
+	// Assert
+	require.ErrorIs(t, err, app.ErrCurrentPasswordRequired)
+}
+
+func TestUC22UpdateProfile_EmailChangeWrongPassword(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	hasher := mocks.NewMockPasswordHasher(ctrl)
+	now := time.Date(2026, 1, 23, 10, 0, 0, 0, time.UTC)
+
+	userRepo.EXPECT().FindByID(gomock.Any(), int64(7)).Return(profileUser(now), nil)
+	hasher.EXPECT().Compare("hash", "wrong").Return(false, nil)
+
+	uc := app.UpdateProfileUseCase{UserRepo: userRepo, Hasher: hasher}
+
+	// Act
+	_, err := uc.Execute(context.Background(), 7, app.UpdateProfileInput{
+		Email:           strPtr("new@example.com"),
+		CurrentPassword: "wrong",
+	})
+
+	// Assert
+	require.ErrorIs(t, err, app.ErrInvalidCredentials)
+}
+
+func TestUC22UpdateProfile_EmailTaken(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	hasher := mocks.NewMockPasswordHasher(ctrl)
+	now := time.Date(2026, 1, 23, 10, 0, 0, 0, time.UTC)
+
+	userRepo.EXPECT().FindByID(gomock.Any(), int64(7)).Return(profileUser(now), nil)
+	hasher.EXPECT().Compare("hash", "password123").Return(true, nil)
+	userRepo.EXPECT().ExistsByEmail(gomock.Any(), "taken@example.com").Return(true, nil)
+
+	uc := app.UpdateProfileUseCase{UserRepo: userRepo, Hasher: hasher}
+
+	// Act
+	_, err := uc.Execute(context.Background(), 7, app.UpdateProfileInput{
+		Email:           strPtr("Taken@Example.com"),
+		CurrentPassword: "password123",
+	})
+
+	// Assert
+	require.ErrorIs(t, err, app.ErrEmailAlreadyRegistered)
+}
+
+func TestUC22UpdateProfile_EmailChangeWithVerification_ClearsVerifiedAndSendsMail(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	hasher := mocks.NewMockPasswordHasher(ctrl)
+	tokens := mocks.NewMockEmailVerificationRepository(ctrl)
+	opaque := mocks.NewMockOpaqueTokenService(ctrl)
+	mailer := mocks.NewMockMailer(ctrl)
+	now := time.Date(2026, 1, 23, 10, 0, 0, 0, time.UTC)
+
+	userRepo.EXPECT().FindByID(gomock.Any(), int64(7)).Return(profileUser(now), nil)
+	hasher.EXPECT().Compare("hash", "password123").Return(true, nil)
+	userRepo.EXPECT().ExistsByEmail(gomock.Any(), "new@example.com").Return(false, nil)
+	userRepo.EXPECT().UpdateProfile(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, u domain.User) error {
+		require.Equal(t, "new@example.com", u.Email)
+		require.Nil(t, u.EmailVerifiedAt)
+		return nil
+	})
+	tokens.EXPECT().InvalidateByUser(gomock.Any(), int64(7), now).Return(nil)
+	opaque.EXPECT().Generate().Return("raw", nil)
+	opaque.EXPECT().Hash("raw").Return("h")
+	tokens.EXPECT().Create(gomock.Any(), gomock.Any()).Return(domain.EmailVerificationToken{ID: 1}, nil)
+	mailer.EXPECT().Send(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, msg domain.EmailMessage) error {
+		require.Equal(t, "new@example.com", msg.To)
+		return nil
+	})
+
+	uc := app.UpdateProfileUseCase{
+		UserRepo: userRepo,
+		Hasher:   hasher,
+		Now:      func() time.Time { return now },
+		Verification: &app.SendEmailVerificationUseCase{
+			Tokens:    tokens,
+			Opaque:    opaque,
+			Mailer:    mailer,
+			Now:       func() time.Time { return now },
+			VerifyURL: "http://localhost/verify",
+		},
+	}
+
+	// Act
+	out, err := uc.Execute(context.Background(), 7, app.UpdateProfileInput{
+		Email:           strPtr("new@example.com"),
+		CurrentPassword: "password123",
+	})
+
+	// Assert
+	require.NoError(t, err)
+	require.False(t, out.EmailVerified)
+}
+
+func TestUC22ChangePassword_WrongCurrent(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	hasher := mocks.NewMockPasswordHasher(ctrl)
+	now := time.Date(2026, 1, 23, 10, 0, 0, 0, time.UTC)
+
+	userRepo.EXPECT().FindByID(gomock.Any(), int64(7)).Return(profileUser(now), nil)
+	hasher.EXPECT().Compare("hash", "wrong").Return(false, nil)
+
+	uc := app.ChangePasswordUseCase{UserRepo: userRepo, Hasher: hasher}
+
+	// Act
+	err := uc.Execute(context.Background(), 7, app.ChangePasswordInput{CurrentPassword: "wrong", NewPassword: "password456"})
+
+	// Assert
+	require.ErrorIs(t, err, app.ErrInvalidCredentials)
+}
+
+func TestUC22ChangePassword_TooShort(t *testing.T) {
+	// Arrange
+	uc := app.ChangePasswordUseCase{}
+
+	// Act
+	err := uc.Execute(context.Background(), 7, app.ChangePasswordInput{CurrentPassword: "password123", NewPassword: "short"})
+
+	// Assert
+	require.ErrorIs(t, err, app.ErrInvalidPassword)
+}
+
+func TestUC22ChangePassword_Success_RevokesSessions(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	hasher := mocks.NewMockPasswordHasher(ctrl)
+	refreshRepo := mocks.NewMockRefreshTokenRepository(ctrl)
+	revocations := mocks.NewMockTokenRevocationStore(ctrl)
+	apiKeys := mocks.NewMockAPIKeyRepository(ctrl)
+	now := time.Date(2026, 1, 23, 10, 0, 0, 0, time.UTC)
+
+	userRepo.EXPECT().FindByID(gomock.Any(), int64(7)).Return(profileUser(now), nil)
+	hasher.EXPECT().Compare("hash", "password123").Return(true, nil)
+	hasher.EXPECT().Hash("password456").Return("newhash", nil)
+	userRepo.EXPECT().UpdatePassword(gomock.Any(), int64(7), "newhash").Return(nil)
+	refreshRepo.EXPECT().RevokeByUser(gomock.Any(), int64(7), now).Return(nil)
+	apiKeys.EXPECT().RevokeByUser(gomock.Any(), int64(7), now).Return(nil)
+	revocations.EXPECT().RevokeUser(gomock.Any(), int64(7), now, now.Add(15*time.Minute)).Return(nil)
+
+	uc := app.ChangePasswordUseCase{
+		UserRepo:      userRepo,
+		Hasher:        hasher,
+		RefreshTokens: refreshRepo,
+		APIKeys:       apiKeys,
+		Revocations:   revocations,
+		Now:           func() time.Time { return now },
+		TTL:           15 * time.Minute,
+	}
+
+	// Act
+	err := uc.Execute(context.Background(), 7, app.ChangePasswordInput{CurrentPassword: "password123", NewPassword: "password456"})
+
+	// Assert
+	require.NoError(t, err)
+}
+
+func TestUC22DeleteAccount_WrongPassword_KeepsEverything(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	hasher := mocks.NewMockPasswordHasher(ctrl)
+	favRepo := mocks.NewMockFavoritesRepository(ctrl)
+	now := time.Date(2026, 1, 23, 10, 0, 0, 0, time.UTC)
+
+	userRepo.EXPECT().FindByID(gomock.Any(), int64(7)).Return(profileUser(now), nil)
+	hasher.EXPECT().Compare("hash", "wrong").Return(false, nil)
+
+	uc := app.DeleteAccountUseCase{UserRepo: userRepo, Hasher: hasher, Favorites: favRepo}
+
+	// Act
+	err := uc.Execute(context.Background(), 7, app.DeleteAccountInput{Password: "wrong"})
+
+	// Assert
+	require.ErrorIs(t, err, app.ErrInvalidCredentials)
+}
+
+func TestUC22DeleteAccount_Success_RemovesFavoritesSessionsAndUser(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	hasher := mocks.NewMockPasswordHasher(ctrl)
+	favRepo := mocks.NewMockFavoritesRepository(ctrl)
+	refreshRepo := mocks.NewMockRefreshTokenRepository(ctrl)
+	apiKeys := mocks.NewMockAPIKeyRepository(ctrl)
+	revocations := mocks.NewMockTokenRevocationStore(ctrl)
+	now := time.Date(2026, 1, 23, 10, 0, 0, 0, time.UTC)
+
+	userRepo.EXPECT().FindByID(gomock.Any(), int64(7)).Return(profileUser(now), nil)
+	hasher.EXPECT().Compare("hash", "password123").Return(true, nil)
+	favRepo.EXPECT().ListFavoriteCoinIDsByUser(gomock.Any(), int64(7)).Return([]domain.Coin{{ID: 1, Symbol: "BTC"}, {ID: 2, Symbol: "ETH"}}, nil)
+	gomock.InOrder(
+		favRepo.EXPECT().RemoveFavoriteCoinFromUser(gomock.Any(), int64(7), int64(1)).Return(nil),
+		favRepo.EXPECT().RemoveFavoriteCoinFromUser(gomock.Any(), int64(7), int64(2)).Return(nil),
+		refreshRepo.EXPECT().RevokeByUser(gomock.Any(), int64(7), now).Return(nil),
+		apiKeys.EXPECT().RevokeByUser(gomock.Any(), int64(7), now).Return(nil),
+		revocations.EXPECT().RevokeUser(gomock.Any(), int64(7), now, now.Add(time.Hour)).Return(nil),
+		userRepo.EXPECT().Delete(gomock.Any(), int64(7)).Return(nil),
+	)
+
+	uc := app.DeleteAccountUseCase{
+		UserRepo:      userRepo,
+		Hasher:        hasher,
+		Favorites:     favRepo,
+		RefreshTokens: refreshRepo,
+		APIKeys:       apiKeys,
+		Revocations:   revocations,
+		Now:           func() time.Time { return now },
+		TTL:           time.Hour,
+	}
+
+	// Act
+	err := uc.Execute(context.Background(), 7, app.DeleteAccountInput{Password: "password123"})
+
+	// Assert
+	require.NoError(t, err)
+}
+
+func TestUC22DeleteAccount_OIDCAccountWithoutPassword_NeedsRecentLogin(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	favRepo := mocks.NewMockFavoritesRepository(ctrl)
+	identities := mocks.NewMockUserIdentityRepository(ctrl)
+	now := time.Date(2026, 1, 23, 10, 0, 0, 0, time.UTC)
+
+	userRepo.EXPECT().FindByID(gomock.Any(), int64(7)).Return(profileUser(now), nil).Times(2)
+	identities.EXPECT().ListByUser(gomock.Any(), int64(7)).Return([]domain.UserIdentity{{UserID: 7, Issuer: "https://idp.example.com", Subject: "sub-1"}}, nil).Times(2)
+	favRepo.EXPECT().ListFavoriteCoinIDsByUser(gomock.Any(), int64(7)).Return(nil, nil)
+	userRepo.EXPECT().Delete(gomock.Any(), int64(7)).Return(nil)
+
+	uc := app.DeleteAccountUseCase{
+		UserRepo:   userRepo,
+		Hasher:     mocks.NewMockPasswordHasher(ctrl),
+		Favorites:  favRepo,
+		Identities: identities,
+		Now:        func() time.Time { return now },
+	}
+
+	// Act
+	errStale := uc.Execute(context.Background(), 7, app.DeleteAccountInput{SessionIssuedAt: now.Add(-10 * time.Minute)})
+	errFresh := uc.Execute(context.Background(), 7, app.DeleteAccountInput{SessionIssuedAt: now.Add(-time.Minute)})
+
+	// Assert
+	require.ErrorIs(t, errStale, app.ErrRecentLoginRequired)
+	require.NoError(t, errFresh)
+}
+
+func TestUC22DeleteAccount_LocalAccountWithoutPassword_IsRejected(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	identities := mocks.NewMockUserIdentityRepository(ctrl)
+	now := time.Date(2026, 1, 23, 10, 0, 0, 0, time.UTC)
+
+	userRepo.EXPECT().FindByID(gomock.Any(), int64(7)).Return(profileUser(now), nil)
+	identities.EXPECT().ListByUser(gomock.Any(), int64(7)).Return(nil, nil)
+
+	uc := app.DeleteAccountUseCase{
+		UserRepo:   userRepo,
+		Hasher:     mocks.NewMockPasswordHasher(ctrl),
+		Favorites:  mocks.NewMockFavoritesRepository(ctrl),
+		Identities: identities,
+		Now:        func() time.Time { return now },
+	}
+
+	// Act
+	err := uc.Execute(context.Background(), 7, app.DeleteAccountInput{SessionIssuedAt: now})
+
+	// Assert
+	require.ErrorIs(t, err, app.ErrBadRequest)
+}
+
+func TestUC22ExportUserData_IncludesEverythingWithoutSecrets(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	favRepo := mocks.NewMockFavoritesRepository(ctrl)
+	apiKeys := mocks.NewMockAPIKeyRepository(ctrl)
+	refreshRepo := mocks.NewMockRefreshTokenRepository(ctrl)
+	totp := mocks.NewMockTOTPRepository(ctrl)
+	recovery := mocks.NewMockRecoveryCodeRepository(ctrl)
+	alerts := mocks.NewMockAlertRuleRepository(ctrl)
+	webhooks := mocks.NewMockWebhookSubscriptionRepository(ctrl)
+	now := time.Date(2026, 1, 23, 10, 0, 0, 0, time.UTC)
+
+	userRepo.EXPECT().FindByID(gomock.Any(), int64(7)).Return(profileUser(now), nil)
+	favRepo.EXPECT().ListFavoriteCoinIDsByUser(gomock.Any(), int64(7)).Return([]domain.Coin{{ID: 1, Symbol: "BTC", Enabled: true}}, nil)
+	apiKeys.EXPECT().ListByUser(gomock.Any(), int64(7)).Return([]domain.APIKey{
+		{ID: 3, UserID: 7, Name: "bot", Prefix: "ck_abc", KeyHash: "secret-hash", Scopes: []string{domain.ScopeQuotesRead}, CreatedAt: now},
+	}, nil)
+	refreshRepo.EXPECT().ListActiveByUser(gomock.Any(), int64(7), now).Return([]domain.RefreshToken{
+		{ID: 9, UserID: 7, FamilyID: "fam", TokenHash: "refresh-hash", CreatedAt: now, ExpiresAt: now.Add(24 * time.Hour)},
+	}, nil)
+	totp.EXPECT().Get(gomock.Any(), int64(7)).Return(nil, nil)
+	alerts.EXPECT().ListByUser(gomock.Any(), int64(7)).Return([]domain.AlertRule{
+		{ID: 4, UserID: 7, CoinID: 1, Symbol: "BTC", Kind: domain.AlertAbove, Threshold: 100000, Active: true, Armed: true, CreatedAt: now, UpdatedAt: now},
+	}, nil)
+	webhooks.EXPECT().ListByUser(gomock.Any(), int64(7)).Return([]domain.WebhookSubscription{
+		{ID: 5, UserID: 7, URL: "https://example.com/hook", Events: []string{domain.WebhookEventAlertTriggered}, Secret: "whsec_secret", CreatedAt: now},
+	}, nil)
+
+	uc := app.ExportUserDataUseCase{
+		UserRepo:      userRepo,
+		Favorites:     favRepo,
+		APIKeys:       apiKeys,
+		RefreshTokens: refreshRepo,
+		TOTP:          totp,
+		RecoveryCodes: recovery,
+		Alerts:        alerts,
+		Webhooks:      webhooks,
+		Now:           func() time.Time { return now },
+	}
+
+	// Act
+	out, err := uc.Execute(context.Background(), 7)
+
+	// Assert
+	require.NoError(t, err)
+	require.Equal(t, now, out.ExportedAt)
+	require.Equal(t, "john@example.com", out.User.Email)
+	require.Equal(t, &now, out.User.EmailVerifiedAt)
+	require.Len(t, out.Favorites, 1)
+	require.Equal(t, "BTC", out.Favorites[0].Symbol)
+	require.Len(t, out.APIKeys, 1)
+	require.Equal(t, "ck_abc", out.APIKeys[0].Prefix)
+	require.Equal(t, []app.ExportedSession{{ID: 9, CreatedAt: now, ExpiresAt: now.Add(24 * time.Hour)}}, out.Sessions)
+	require.False(t, out.TwoFactor.Enabled)
+	require.Len(t, out.Alerts, 1)
+	require.Equal(t, domain.AlertAbove, out.Alerts[0].Kind)
+	require.Equal(t, 100000.0, out.Alerts[0].Threshold)
+	require.Equal(t, []app.WebhookOutput{{ID: 5, URL: "https://example.com/hook", Events: []string{domain.WebhookEventAlertTriggered}, CreatedAt: now}}, out.Webhooks)
+}
@@ -0,0 +1,234 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/moondolphin/crypto-api/domain"
+)
+
+var (
+	ErrInvalidOIDCState      = errors.New("invalid_oidc_state")
+	ErrOIDCLoginFailed       = errors.New("oidc_login_failed")
+	ErrOIDCEmailNotVerified  = errors.New("oidc_email_not_verified")
+	ErrOIDCAccountNotFound   = errors.New("oidc_account_not_found")
+	ErrOIDCAccountUnverified = errors.New("oidc_account_unverified")
+)
+
+const defaultOIDCStateTTL = 10 * time.Minute
+
+type StartOIDCLoginOutput struct {
+	AuthorizationURL string `json:"authorization_url"`
+}
+
+// StartOIDCLoginUseCase arranca el login con el proveedor OIDC: genera state,
+// nonce y code_verifier (PKCE), los guarda y devuelve la URL de autorización.
+type StartOIDCLoginUseCase struct {
+	Provider domain.OIDCProvider
+	States   domain.OIDCStateRepository
+	Opaque   domain.OpaqueTokenService
+	Now      func() time.Time
+	TTL      time.Duration
+}
+
+func (uc StartOIDCLoginUseCase) Execute(ctx context.Context) (StartOIDCLoginOutput, error) {
+	now := uc.Now
+	if now == nil {
+		now = time.Now
+	}
+	t := now().UTC()
+
+	ttl := uc.TTL
+	if ttl <= 0 {
+		ttl = defaultOIDCStateTTL
+	}
+
+	// 32 bytes en base64url: 43 caracteres, válido como code_verifier (RFC 7636)
+	var values [3]string
+	for i := range values {
+		v, err := uc.Opaque.Generate()
+		if err != nil {
+			return StartOIDCLoginOutput{}, err
+		}
+		values[i] = v
+	}
+	state, nonce, verifier := values[0], values[1], values[2]
+
+	_, err := uc.States.Create(ctx, domain.OIDCLoginState{
+		StateHash:    uc.Opaque.Hash(state),
+		Nonce:        nonce,
+		CodeVerifier: verifier,
+		ExpiresAt:    t.Add(ttl),
+		CreatedAt:    t,
+	})
+	if err != nil {
+		return StartOIDCLoginOutput{}, err
+	}
+
+	authURL, err := uc.Provider.AuthCodeURL(ctx, state, nonce, verifier)
+	if err != nil {
+		return StartOIDCLoginOutput{}, err
+	}
+	return StartOIDCLoginOutput{AuthorizationURL: authURL}, nil
+}
+
+// OIDCCallbackInput son los parámetros con los que el proveedor vuelve al redirect_uri.
+type OIDCCallbackInput struct {
+	Code  string `form:"code"`
+	State string `form:"state"`
+
+	// el proveedor informa acá si el usuario canceló o hubo un error
+	Error string `form:"error"`
+}
+
+// OIDCCallbackUseCase completa el login: consume el state, canjea el code,
+// resuelve el usuario (vinculado, vinculable por email o nuevo) y emite la
+// sesión como el login con contraseña, incluido el challenge si tiene 2FA.
+type OIDCCallbackUseCase struct {
+	Login      LoginUseCase
+	Provider   domain.OIDCProvider
+	States     domain.OIDCStateRepository
+	Identities domain.UserIdentityRepository
+
+	// sin AutoProvision sólo pueden entrar usuarios que ya existen
+	AutoProvision bool
+}
+
+func (uc OIDCCallbackUseCase) Execute(ctx context.Context, in OIDCCallbackInput) (LoginOutput, error) {
+	if in.Error != "" {
+		return LoginOutput{}, ErrOIDCLoginFailed
+	}
+	if in.Code == "" || in.State == "" {
+		return LoginOutput{}, ErrBadRequest
+	}
+
+	now := uc.Login.Now
+	if now == nil {
+		now = time.Now
+	}
+	t := now().UTC()
+
+	s, err := uc.States.FindByHash(ctx, uc.Login.Opaque.Hash(in.State))
+	if err != nil {
+		return LoginOutput{}, err
+	}
+	if s == nil || s.IsUsed() || s.IsExpired(t) {
+		return LoginOutput{}, ErrInvalidOIDCState
+	}
+
+	used, err := uc.States.MarkUsed(ctx, s.ID, t)
+	if err != nil {
+		return LoginOutput{}, err
+	}
+	if !used {
+		return LoginOutput{}, ErrInvalidOIDCState
+	}
+
+	claims, err := uc.Provider.Exchange(ctx, in.Code, s.CodeVerifier, s.Nonce)
+	if err != nil {
+		log.Printf("Warning: oidc code exchange failed: %v", err)
+		return LoginOutput{}, ErrOIDCLoginFailed
+	}
+
+	u, err := uc.resolveUser(ctx, claims, t)
+	if err != nil {
+		return LoginOutput{}, err
+	}
+
+	ch, err := uc.Login.twoFactorChallenge(ctx, u.ID, t)
+	if err != nil {
+		return LoginOutput{}, err
+	}
+	if ch != nil {
+		return LoginOutput{Challenge: ch}, nil
+	}
+
+	return uc.Login.issueSession(ctx, u, t)
+}
+
+// resolveUser busca la identidad (iss, sub). Si no está vinculada, la vincula
+// al usuario con el mismo email o crea uno nuevo; en ambos casos el proveedor
+// tiene que haber verificado el email, si no cualquiera podría tomar una cuenta
+// registrando su email en el proveedor. Sólo se vinculan cuentas locales con el
+// email ya verificado.
+func (uc OIDCCallbackUseCase) resolveUser(ctx context.Context, claims domain.OIDCClaims, now time.Time) (domain.User, error) {
+	ident, err := uc.Identities.FindBySubject(ctx, claims.Issuer, claims.Subject)
+	if err != nil {
+		return domain.User{}, err
+	}
+	if ident != nil {
+		u, err := findUser(ctx, uc.Login.UserRepo, ident.UserID)
+		if err != nil {
+			return domain.User{}, err
+		}
+		return *u, nil
+	}
+
+	if claims.Email == "" || !claims.EmailVerified {
+		return domain.User{}, ErrOIDCEmailNotVerified
+	}
+
+	u, err := uc.Login.UserRepo.FindByEmail(ctx, claims.Email)
+	if err != nil {
+		return domain.User{}, err
+	}
+
+	switch {
+	case u != nil && !u.IsEmailVerified():
+		// la cuenta local pudo crearla otro con este email: vincularla le
+		// dejaría su contraseña y sesiones. El dueño tiene que verificarla antes.
+		return domain.User{}, ErrOIDCAccountUnverified
+
+	case u == nil && !uc.AutoProvision:
+		return domain.User{}, ErrOIDCAccountNotFound
+
+	case u == nil:
+		created, err := uc.provision(ctx, claims, now)
+		if err != nil {
+			return domain.User{}, err
+		}
+		u = &created
+	}
+
+	_, err = uc.Identities.Create(ctx, domain.UserIdentity{
+		UserID:    u.ID,
+		Issuer:    claims.Issuer,
+		Subject:   claims.Subject,
+		Email:     claims.Email,
+		CreatedAt: now,
+	})
+	if err != nil {
+		return domain.User{}, err
+	}
+	return *u, nil
+}
+
+// provision crea el usuario con una contraseña aleatoria que nadie conoce: si
+// alguna vez la necesita, la define con el reset de contraseña.
+func (uc OIDCCallbackUseCase) provision(ctx context.Context, claims domain.OIDCClaims, now time.Time) (domain.User, error) {
+	password, err := uc.Login.Opaque.Generate()
+	if err != nil {
+		return domain.User{}, err
+	}
+	hash, err := uc.Login.Hasher.Hash(password)
+	if err != nil {
+		return domain.User{}, err
+	}
+
+	name := claims.Name
+	if name == "" {
+		name, _, _ = strings.Cut(claims.Email, "@")
+	}
+
+	return uc.Login.UserRepo.Create(ctx, domain.User{
+		Email:           claims.Email,
+		Name:            name,
+		PasswordHash:    hash,
+		Role:            domain.RoleUser,
+		CreatedAt:       now,
+		EmailVerifiedAt: &now,
+	})
+}
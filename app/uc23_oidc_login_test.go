@@ -0,0 +1,607 @@
+package app_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/moondolphin/crypto-api/app"
+	"github.com/moondolphin/crypto-api/domain"
+	"github.com/moondolphin/crypto-api/test/mocks"
+)
+
+const testIssuer = "https://idp.example.com"
+
+// expectValidOIDCState deja el state "st" listo para consumir y el canje devolviendo claims.
+func expectValidOIDCState(opaque *mocks.MockOpaqueTokenService, states *mocks.MockOIDCStateRepository, provider *mocks.MockOIDCProvider, now time.Time, claims domain.OIDCClaims) {
+	opaque.EXPECT().Hash("st").Return("st-hash")
+	states.EXPECT().FindByHash(gomock.Any(), "st-hash").Return(&domain.OIDCLoginState{
+		ID: 3, StateHash: "st-hash", Nonce: "nonce", CodeVerifier: "verifier", ExpiresAt: now.Add(time.Minute),
+	}, nil)
+	states.EXPECT().MarkUsed(gomock.Any(), int64(3), now).Return(true, nil)
+	provider.EXPECT().Exchange(gomock.Any(), "code", "verifier", "nonce").Return(claims, nil)
+}
+
+func TestUC23StartOIDCLogin_StoresStateAndReturnsURL(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fixedNow := time.Date(2026, 1, 24, 10, 0, 0, 0, time.UTC)
+
+	opaque := mocks.NewMockOpaqueTokenService(ctrl)
+	provider := mocks.NewMockOIDCProvider(ctrl)
+	states := mocks.NewMockOIDCStateRepository(ctrl)
+
+	gomock.InOrder(
+		opaque.EXPECT().Generate().Return("state", nil),
+		opaque.EXPECT().Generate().Return("nonce", nil),
+		opaque.EXPECT().Generate().Return("verifier", nil),
+	)
+	opaque.EXPECT().Hash("state").Return("state-hash")
+	states.EXPECT().Create(gomock.Any(), domain.OIDCLoginState{
+		StateHash:    "state-hash",
+		Nonce:        "nonce",
+		CodeVerifier: "verifier",
+		ExpiresAt:    fixedNow.Add(5 * time.Minute),
+		CreatedAt:    fixedNow,
+	}).Return(domain.OIDCLoginState{ID: 1}, nil)
+	provider.EXPECT().AuthCodeURL(gomock.Any(), "state", "nonce", "verifier").Return("https://idp.example.com/authorize?x=1", nil)
+
+	uc := app.StartOIDCLoginUseCase{
+		Provider: provider,
+		States:   states,
+		Opaque:   opaque,
+		Now:      func() time.Time { return fixedNow },
+		TTL:      5 * time.Minute,
+	}
+
+	// Act
+	out, err := uc.Execute(context.Background())
+
+	// Assert
+	require.NoError(t, err)
+	require.Equal(t, "https://idp.example.com/authorize?x=1", out.AuthorizationURL)
+}
+
+func TestUC23OIDCCallback_ProviderError(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fixedNow := time.Date(2026, 1, 24, 10, 0, 0, 0, time.UTC)
+
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	hasher := mocks.NewMockPasswordHasher(ctrl)
+	tokens := mocks.NewMockTokenService(ctrl)
+	opaque := mocks.NewMockOpaqueTokenService(ctrl)
+	provider := mocks.NewMockOIDCProvider(ctrl)
+	states := mocks.NewMockOIDCStateRepository(ctrl)
+	identities := mocks.NewMockUserIdentityRepository(ctrl)
+
+	uc := app.OIDCCallbackUseCase{
+		Login: app.LoginUseCase{
+			UserRepo: userRepo,
+			Hasher:   hasher,
+			Tokens:   tokens,
+			Opaque:   opaque,
+			Now:      func() time.Time { return fixedNow },
+			TTL:      15 * time.Minute,
+		},
+		Provider:      provider,
+		States:        states,
+		Identities:    identities,
+		AutoProvision: true,
+	}
+
+	// Act
+	_, err := uc.Execute(context.Background(), app.OIDCCallbackInput{Error: "access_denied", State: "st"})
+
+	// Assert
+	require.ErrorIs(t, err, app.ErrOIDCLoginFailed)
+}
+
+func TestUC23OIDCCallback_MissingParams(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fixedNow := time.Date(2026, 1, 24, 10, 0, 0, 0, time.UTC)
+
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	hasher := mocks.NewMockPasswordHasher(ctrl)
+	tokens := mocks.NewMockTokenService(ctrl)
+	opaque := mocks.NewMockOpaqueTokenService(ctrl)
+	provider := mocks.NewMockOIDCProvider(ctrl)
+	states := mocks.NewMockOIDCStateRepository(ctrl)
+	identities := mocks.NewMockUserIdentityRepository(ctrl)
+
+	uc := app.OIDCCallbackUseCase{
+		Login: app.LoginUseCase{
+			UserRepo: userRepo,
+			Hasher:   hasher,
+			Tokens:   tokens,
+			Opaque:   opaque,
+			Now:      func() time.Time { return fixedNow },
+			TTL:      15 * time.Minute,
+		},
+		Provider:      provider,
+		States:        states,
+		Identities:    identities,
+		AutoProvision: true,
+	}
+
+	// Act
+	_, err := uc.Execute(context.Background(), app.OIDCCallbackInput{Code: "code"})
+
+	// Assert
+	require.ErrorIs(t, err, app.ErrBadRequest)
+}
+
+func TestUC23OIDCCallback_InvalidStates(t *testing.T) {
+	now := time.Date(2026, 1, 24, 10, 0, 0, 0, time.UTC)
+	used := now.Add(-time.Minute)
+
+	cases := map[string]*domain.OIDCLoginState{
+		"unknown": nil,
+		"used":    {ID: 3, ExpiresAt: now.Add(time.Minute), UsedAt: &used},
+		"expired": {ID: 3, ExpiresAt: now},
+	}
+
+	for name, state := range cases {
+		t.Run(name, func(t *testing.T) {
+			// Arrange
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			userRepo := mocks.NewMockUserRepository(ctrl)
+			hasher := mocks.NewMockPasswordHasher(ctrl)
+			tokens := mocks.NewMockTokenService(ctrl)
+			opaque := mocks.NewMockOpaqueTokenService(ctrl)
+			provider := mocks.NewMockOIDCProvider(ctrl)
+			states := mocks.NewMockOIDCStateRepository(ctrl)
+			identities := mocks.NewMockUserIdentityRepository(ctrl)
+
+			opaque.EXPECT().Hash("st").Return("st-hash")
+			states.EXPECT().FindByHash(gomock.Any(), "st-hash").Return(state, nil)
+
+			uc := app.OIDCCallbackUseCase{
+				Login: app.LoginUseCase{
+					UserRepo: userRepo,
+					Hasher:   hasher,
+					Tokens:   tokens,
+					Opaque:   opaque,
+					Now:      func() time.Time { return now },
+					TTL:      15 * time.Minute,
+				},
+				Provider:      provider,
+				States:        states,
+				Identities:    identities,
+				AutoProvision: true,
+			}
+
+			// Act
+			_, err := uc.Execute(context.Background(), app.OIDCCallbackInput{Code: "code", State: "st"})
+
+			// Assert
+			require.ErrorIs(t, err, app.ErrInvalidOIDCState)
+		})
+	}
+}
+
+func TestUC23OIDCCallback_StateConsumedConcurrently(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fixedNow := time.Date(2026, 1, 24, 10, 0, 0, 0, time.UTC)
+
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	hasher := mocks.NewMockPasswordHasher(ctrl)
+	tokens := mocks.NewMockTokenService(ctrl)
+	opaque := mocks.NewMockOpaqueTokenService(ctrl)
+	provider := mocks.NewMockOIDCProvider(ctrl)
+	states := mocks.NewMockOIDCStateRepository(ctrl)
+	identities := mocks.NewMockUserIdentityRepository(ctrl)
+
+	opaque.EXPECT().Hash("st").Return("st-hash")
+	states.EXPECT().FindByHash(gomock.Any(), "st-hash").Return(&domain.OIDCLoginState{ID: 3, ExpiresAt: fixedNow.Add(time.Minute)}, nil)
+	states.EXPECT().MarkUsed(gomock.Any(), int64(3), fixedNow).Return(false, nil)
+
+	uc := app.OIDCCallbackUseCase{
+		Login: app.LoginUseCase{
+			UserRepo: userRepo,
+			Hasher:   hasher,
+			Tokens:   tokens,
+			Opaque:   opaque,
+			Now:      func() time.Time { return fixedNow },
+			TTL:      15 * time.Minute,
+		},
+		Provider:      provider,
+		States:        states,
+		Identities:    identities,
+		AutoProvision: true,
+	}
+
+	// Act
+	_, err := uc.Execute(context.Background(), app.OIDCCallbackInput{Code: "code", State: "st"})
+
+	// Assert
+	require.ErrorIs(t, err, app.ErrInvalidOIDCState)
+}
+
+func TestUC23OIDCCallback_ExchangeFails(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fixedNow := time.Date(2026, 1, 24, 10, 0, 0, 0, time.UTC)
+
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	hasher := mocks.NewMockPasswordHasher(ctrl)
+	tokens := mocks.NewMockTokenService(ctrl)
+	opaque := mocks.NewMockOpaqueTokenService(ctrl)
+	provider := mocks.NewMockOIDCProvider(ctrl)
+	states := mocks.NewMockOIDCStateRepository(ctrl)
+	identities := mocks.NewMockUserIdentityRepository(ctrl)
+
+	opaque.EXPECT().Hash("st").Return("st-hash")
+	states.EXPECT().FindByHash(gomock.Any(), "st-hash").Return(&domain.OIDCLoginState{
+		ID: 3, Nonce: "nonce", CodeVerifier: "verifier", ExpiresAt: fixedNow.Add(time.Minute),
+	}, nil)
+	states.EXPECT().MarkUsed(gomock.Any(), int64(3), fixedNow).Return(true, nil)
+	provider.EXPECT().Exchange(gomock.Any(), "code", "verifier", "nonce").Return(domain.OIDCClaims{}, errors.New("nonce mismatch"))
+
+	uc := app.OIDCCallbackUseCase{
+		Login: app.LoginUseCase{
+			UserRepo: userRepo,
+			Hasher:   hasher,
+			Tokens:   tokens,
+			Opaque:   opaque,
+			Now:      func() time.Time { return fixedNow },
+			TTL:      15 * time.Minute,
+		},
+		Provider:      provider,
+		States:        states,
+		Identities:    identities,
+		AutoProvision: true,
+	}
+
+	// Act
+	_, err := uc.Execute(context.Background(), app.OIDCCallbackInput{Code: "code", State: "st"})
+
+	// Assert
+	require.ErrorIs(t, err, app.ErrOIDCLoginFailed)
+}
+
+func TestUC23OIDCCallback_LinkedIdentity_IssuesSession(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fixedNow := time.Date(2026, 1, 24, 10, 0, 0, 0, time.UTC)
+
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	hasher := mocks.NewMockPasswordHasher(ctrl)
+	tokens := mocks.NewMockTokenService(ctrl)
+	opaque := mocks.NewMockOpaqueTokenService(ctrl)
+	provider := mocks.NewMockOIDCProvider(ctrl)
+	states := mocks.NewMockOIDCStateRepository(ctrl)
+	identities := mocks.NewMockUserIdentityRepository(ctrl)
+
+	// el email del proveedor cambió: manda la identidad (iss, sub), no el email
+	expectValidOIDCState(opaque, states, provider, fixedNow, domain.OIDCClaims{Issuer: testIssuer, Subject: "sub-1", Email: "renamed@example.com", EmailVerified: true})
+	identities.EXPECT().FindBySubject(gomock.Any(), testIssuer, "sub-1").Return(&domain.UserIdentity{UserID: 7}, nil)
+	userRepo.EXPECT().FindByID(gomock.Any(), int64(7)).Return(&domain.User{ID: 7, Email: "john@example.com", Role: domain.RoleUser}, nil)
+	tokens.EXPECT().Generate(int64(7), "john@example.com", domain.RoleUser).Return("jwt", nil)
+
+	uc := app.OIDCCallbackUseCase{
+		Login: app.LoginUseCase{
+			UserRepo: userRepo,
+			Hasher:   hasher,
+			Tokens:   tokens,
+			Opaque:   opaque,
+			Now:      func() time.Time { return fixedNow },
+			TTL:      15 * time.Minute,
+		},
+		Provider:      provider,
+		States:        states,
+		Identities:    identities,
+		AutoProvision: true,
+	}
+
+	// Act
+	out, err := uc.Execute(context.Background(), app.OIDCCallbackInput{Code: "code", State: "st"})
+
+	// Assert
+	require.NoError(t, err)
+	require.Equal(t, "jwt", out.AccessToken)
+	require.Equal(t, fixedNow.Add(15*time.Minute), out.ExpiresAt)
+}
+
+func TestUC23OIDCCallback_UnverifiedEmail_IsNotLinked(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fixedNow := time.Date(2026, 1, 24, 10, 0, 0, 0, time.UTC)
+
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	hasher := mocks.NewMockPasswordHasher(ctrl)
+	tokens := mocks.NewMockTokenService(ctrl)
+	opaque := mocks.NewMockOpaqueTokenService(ctrl)
+	provider := mocks.NewMockOIDCProvider(ctrl)
+	states := mocks.NewMockOIDCStateRepository(ctrl)
+	identities := mocks.NewMockUserIdentityRepository(ctrl)
+
+	expectValidOIDCState(opaque, states, provider, fixedNow, domain.OIDCClaims{Issuer: testIssuer, Subject: "sub-1", Email: "john@example.com"})
+	identities.EXPECT().FindBySubject(gomock.Any(), testIssuer, "sub-1").Return(nil, nil)
+
+	uc := app.OIDCCallbackUseCase{
+		Login: app.LoginUseCase{
+			UserRepo: userRepo,
+			Hasher:   hasher,
+			Tokens:   tokens,
+			Opaque:   opaque,
+			Now:      func() time.Time { return fixedNow },
+			TTL:      15 * time.Minute,
+		},
+		Provider:      provider,
+		States:        states,
+		Identities:    identities,
+		AutoProvision: true,
+	}
+
+	// Act
+	_, err := uc.Execute(context.Background(), app.OIDCCallbackInput{Code: "code", State: "st"})
+
+	// Assert
+	require.ErrorIs(t, err, app.ErrOIDCEmailNotVerified)
+}
+
+func TestUC23OIDCCallback_ExistingVerifiedUser_Links(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fixedNow := time.Date(2026, 1, 24, 10, 0, 0, 0, time.UTC)
+
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	hasher := mocks.NewMockPasswordHasher(ctrl)
+	tokens := mocks.NewMockTokenService(ctrl)
+	opaque := mocks.NewMockOpaqueTokenService(ctrl)
+	provider := mocks.NewMockOIDCProvider(ctrl)
+	states := mocks.NewMockOIDCStateRepository(ctrl)
+	identities := mocks.NewMockUserIdentityRepository(ctrl)
+
+	verifiedAt := fixedNow.Add(-time.Hour)
+	expectValidOIDCState(opaque, states, provider, fixedNow, domain.OIDCClaims{Issuer: testIssuer, Subject: "sub-1", Email: "john@example.com", EmailVerified: true})
+	identities.EXPECT().FindBySubject(gomock.Any(), testIssuer, "sub-1").Return(nil, nil)
+	userRepo.EXPECT().FindByEmail(gomock.Any(), "john@example.com").
+		Return(&domain.User{ID: 7, Email: "john@example.com", Role: domain.RoleUser, EmailVerifiedAt: &verifiedAt}, nil)
+	identities.EXPECT().Create(gomock.Any(), domain.UserIdentity{
+		UserID: 7, Issuer: testIssuer, Subject: "sub-1", Email: "john@example.com", CreatedAt: fixedNow,
+	}).Return(domain.UserIdentity{ID: 1}, nil)
+	tokens.EXPECT().Generate(int64(7), "john@example.com", domain.RoleUser).Return("jwt", nil)
+
+	uc := app.OIDCCallbackUseCase{
+		Login: app.LoginUseCase{
+			UserRepo: userRepo,
+			Hasher:   hasher,
+			Tokens:   tokens,
+			Opaque:   opaque,
+			Now:      func() time.Time { return fixedNow },
+			TTL:      15 * time.Minute,
+		},
+		Provider:      provider,
+		States:        states,
+		Identities:    identities,
+		AutoProvision: true,
+	}
+
+	// Act
+	out, err := uc.Execute(context.Background(), app.OIDCCallbackInput{Code: "code", State: "st"})
+
+	// Assert
+	require.NoError(t, err)
+	require.Equal(t, "jwt", out.AccessToken)
+}
+
+func TestUC23OIDCCallback_ExistingUnverifiedUser_IsNotLinked(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fixedNow := time.Date(2026, 1, 24, 10, 0, 0, 0, time.UTC)
+
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	hasher := mocks.NewMockPasswordHasher(ctrl)
+	tokens := mocks.NewMockTokenService(ctrl)
+	opaque := mocks.NewMockOpaqueTokenService(ctrl)
+	provider := mocks.NewMockOIDCProvider(ctrl)
+	states := mocks.NewMockOIDCStateRepository(ctrl)
+	identities := mocks.NewMockUserIdentityRepository(ctrl)
+
+	// alguien registró el email de la víctima con su propia contraseña: ni se
+	// vincula ni se marca verificada (sin Create ni MarkEmailVerified)
+	expectValidOIDCState(opaque, states, provider, fixedNow, domain.OIDCClaims{Issuer: testIssuer, Subject: "sub-1", Email: "john@example.com", EmailVerified: true})
+	identities.EXPECT().FindBySubject(gomock.Any(), testIssuer, "sub-1").Return(nil, nil)
+	userRepo.EXPECT().FindByEmail(gomock.Any(), "john@example.com").Return(&domain.User{ID: 7, Email: "john@example.com", Role: domain.RoleUser}, nil)
+
+	uc := app.OIDCCallbackUseCase{
+		Login: app.LoginUseCase{
+			UserRepo: userRepo,
+			Hasher:   hasher,
+			Tokens:   tokens,
+			Opaque:   opaque,
+			Now:      func() time.Time { return fixedNow },
+			TTL:      15 * time.Minute,
+		},
+		Provider:      provider,
+		States:        states,
+		Identities:    identities,
+		AutoProvision: true,
+	}
+
+	// Act
+	_, err := uc.Execute(context.Background(), app.OIDCCallbackInput{Code: "code", State: "st"})
+
+	// Assert
+	require.ErrorIs(t, err, app.ErrOIDCAccountUnverified)
+}
+
+func TestUC23OIDCCallback_UnknownUser_WithoutAutoProvision(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fixedNow := time.Date(2026, 1, 24, 10, 0, 0, 0, time.UTC)
+
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	hasher := mocks.NewMockPasswordHasher(ctrl)
+	tokens := mocks.NewMockTokenService(ctrl)
+	opaque := mocks.NewMockOpaqueTokenService(ctrl)
+	provider := mocks.NewMockOIDCProvider(ctrl)
+	states := mocks.NewMockOIDCStateRepository(ctrl)
+	identities := mocks.NewMockUserIdentityRepository(ctrl)
+
+	expectValidOIDCState(opaque, states, provider, fixedNow, domain.OIDCClaims{Issuer: testIssuer, Subject: "sub-1", Email: "new@example.com", EmailVerified: true})
+	identities.EXPECT().FindBySubject(gomock.Any(), testIssuer, "sub-1").Return(nil, nil)
+	userRepo.EXPECT().FindByEmail(gomock.Any(), "new@example.com").Return(nil, nil)
+
+	uc := app.OIDCCallbackUseCase{
+		Login: app.LoginUseCase{
+			UserRepo: userRepo,
+			Hasher:   hasher,
+			Tokens:   tokens,
+			Opaque:   opaque,
+			Now:      func() time.Time { return fixedNow },
+			TTL:      15 * time.Minute,
+		},
+		Provider:      provider,
+		States:        states,
+		Identities:    identities,
+		AutoProvision: false,
+	}
+
+	// Act
+	_, err := uc.Execute(context.Background(), app.OIDCCallbackInput{Code: "code", State: "st"})
+
+	// Assert
+	require.ErrorIs(t, err, app.ErrOIDCAccountNotFound)
+}
+
+func TestUC23OIDCCallback_UnknownUser_ProvisionsVerifiedAccount(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fixedNow := time.Date(2026, 1, 24, 10, 0, 0, 0, time.UTC)
+
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	hasher := mocks.NewMockPasswordHasher(ctrl)
+	tokens := mocks.NewMockTokenService(ctrl)
+	opaque := mocks.NewMockOpaqueTokenService(ctrl)
+	provider := mocks.NewMockOIDCProvider(ctrl)
+	states := mocks.NewMockOIDCStateRepository(ctrl)
+	identities := mocks.NewMockUserIdentityRepository(ctrl)
+
+	expectValidOIDCState(opaque, states, provider, fixedNow, domain.OIDCClaims{Issuer: testIssuer, Subject: "sub-1", Email: "new@example.com", EmailVerified: true})
+	identities.EXPECT().FindBySubject(gomock.Any(), testIssuer, "sub-1").Return(nil, nil)
+	userRepo.EXPECT().FindByEmail(gomock.Any(), "new@example.com").Return(nil, nil)
+	opaque.EXPECT().Generate().Return("random-password", nil)
+	hasher.EXPECT().Hash("random-password").Return("bcrypt", nil)
+	userRepo.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, u domain.User) (domain.User, error) {
+		require.Equal(t, "new@example.com", u.Email)
+		require.Equal(t, "new", u.Name)
+		require.Equal(t, "bcrypt", u.PasswordHash)
+		require.Equal(t, domain.RoleUser, u.Role)
+		require.True(t, u.IsEmailVerified())
+		u.ID = 9
+		return u, nil
+	})
+	identities.EXPECT().Create(gomock.Any(), domain.UserIdentity{
+		UserID: 9, Issuer: testIssuer, Subject: "sub-1", Email: "new@example.com", CreatedAt: fixedNow,
+	}).Return(domain.UserIdentity{ID: 1}, nil)
+	tokens.EXPECT().Generate(int64(9), "new@example.com", domain.RoleUser).Return("jwt", nil)
+
+	uc := app.OIDCCallbackUseCase{
+		Login: app.LoginUseCase{
+			UserRepo: userRepo,
+			Hasher:   hasher,
+			Tokens:   tokens,
+			Opaque:   opaque,
+			Now:      func() time.Time { return fixedNow },
+			TTL:      15 * time.Minute,
+		},
+		Provider:      provider,
+		States:        states,
+		Identities:    identities,
+		AutoProvision: true,
+	}
+
+	// Act
+	out, err := uc.Execute(context.Background(), app.OIDCCallbackInput{Code: "code", State: "st"})
+
+	// Assert
+	require.NoError(t, err)
+	require.Equal(t, "jwt", out.AccessToken)
+}
+
+func TestUC23OIDCCallback_UserWithTwoFactor_GetsChallenge(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fixedNow := time.Date(2026, 1, 24, 10, 0, 0, 0, time.UTC)
+
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	hasher := mocks.NewMockPasswordHasher(ctrl)
+	tokens := mocks.NewMockTokenService(ctrl)
+	opaque := mocks.NewMockOpaqueTokenService(ctrl)
+	provider := mocks.NewMockOIDCProvider(ctrl)
+	states := mocks.NewMockOIDCStateRepository(ctrl)
+	identities := mocks.NewMockUserIdentityRepository(ctrl)
+
+	totp := mocks.NewMockTOTPRepository(ctrl)
+	challenges := mocks.NewMockLoginChallengeRepository(ctrl)
+
+	expectValidOIDCState(opaque, states, provider, fixedNow, domain.OIDCClaims{Issuer: testIssuer, Subject: "sub-1", Email: "john@example.com", EmailVerified: true})
+	identities.EXPECT().FindBySubject(gomock.Any(), testIssuer, "sub-1").Return(&domain.UserIdentity{UserID: 7}, nil)
+	userRepo.EXPECT().FindByID(gomock.Any(), int64(7)).Return(&domain.User{ID: 7, Email: "john@example.com"}, nil)
+	totp.EXPECT().Get(gomock.Any(), int64(7)).Return(confirmedTOTP(7, fixedNow), nil)
+	opaque.EXPECT().Generate().Return("challenge", nil)
+	opaque.EXPECT().Hash("challenge").Return("challenge-hash")
+	challenges.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, c domain.LoginChallenge) (domain.LoginChallenge, error) {
+		return c, nil
+	})
+
+	uc := app.OIDCCallbackUseCase{
+		Login: app.LoginUseCase{
+			UserRepo: userRepo,
+			Hasher:   hasher,
+			Tokens:   tokens,
+			Opaque:   opaque,
+			Now:      func() time.Time { return fixedNow },
+			TTL:      15 * time.Minute,
+		},
+		Provider:      provider,
+		States:        states,
+		Identities:    identities,
+		AutoProvision: true,
+	}
+	uc.Login.TwoFactor = &app.TwoFactorLogin{TOTP: totp, Challenges: challenges, ChallengeTTL: time.Minute}
+
+	// Act
+	out, err := uc.Execute(context.Background(), app.OIDCCallbackInput{Code: "code", State: "st"})
+
+	// Assert
+	require.NoError(t, err)
+	require.Empty(t, out.AccessToken)
+	require.NotNil(t, out.Challenge)
+	require.Equal(t, "challenge", out.Challenge.ChallengeToken)
+}
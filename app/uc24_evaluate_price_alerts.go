@@ -0,0 +1,219 @@
+package app
+
+import (
+	"context"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/moondolphin/crypto-api/domain"
+)
+
+type EvaluatePriceAlertsOutput struct {
+	RulesEvaluated int `json:"rules_evaluated"`
+	Triggered      int `json:"triggered"`
+	Rearmed        int `json:"rearmed"`
+	Failed         int `json:"failed"`
+}
+
+// EvaluatePriceAlertsUseCase compara las reglas activas con la última
+// cotización guardada. Corre después de cada RefreshQuotesUseCase.
+type EvaluatePriceAlertsUseCase struct {
+	Rules  domain.AlertRuleRepository
+	Events domain.AlertEventRepository
+	Quotes domain.QuoteRepository
+	Now    func() time.Time
+
+	// opcional: se publica alert.triggered al dueño de cada regla disparada
+	Webhooks *PublishWebhookEventUseCase
+}
+
+// alertQuote es la última cotización de (symbol, provider, currency) ya parseada.
+type alertQuote struct {
+	quote    domain.PriceQuote
+	price    float64
+	quotedAt time.Time
+}
+
+func (uc EvaluatePriceAlertsUseCase) Execute(ctx context.Context) (EvaluatePriceAlertsOutput, error) {
+	now := uc.Now
+	if now == nil {
+		now = time.Now
+	}
+	t := now().UTC()
+
+	rules, err := uc.Rules.ListActive(ctx)
+	if err != nil {
+		return EvaluatePriceAlertsOutput{}, err
+	}
+
+	out := EvaluatePriceAlertsOutput{RulesEvaluated: len(rules)}
+
+	// muchas reglas miran la misma coin: una sola consulta por clave
+	latest := make(map[string]*alertQuote)
+
+	for _, rule := range rules {
+		key := rule.Symbol + "|" + rule.Provider + "|" + rule.Currency
+		q, ok := latest[key]
+		if !ok {
+			q, err = uc.latestQuote(ctx, rule, t)
+			if err != nil {
+				log.Printf("Warning: alert %d: latest quote: %v", rule.ID, err)
+				out.Failed++
+				continue
+			}
+			latest[key] = q
+		}
+		if q == nil {
+			// todavía no hay cotizaciones para la regla
+			continue
+		}
+
+		ev, met, known, err := uc.check(ctx, rule, *q)
+		if err != nil {
+			log.Printf("Warning: alert %d: %v", rule.ID, err)
+			out.Failed++
+			continue
+		}
+		if !known {
+			// sin precio de referencia no se puede decidir: ni dispara ni re-arma
+			continue
+		}
+
+		switch {
+		case met && rule.Armed:
+			triggered, err := uc.Rules.MarkTriggered(ctx, rule.ID, t, !rule.Rearm)
+			if err != nil {
+				log.Printf("Warning: alert %d: mark triggered: %v", rule.ID, err)
+				out.Failed++
+				continue
+			}
+			if !triggered {
+				continue
+			}
+
+			ev.TriggeredAt = t
+			created, err := uc.Events.Create(ctx, ev)
+			if err != nil {
+				log.Printf("Warning: alert %d: record event: %v", rule.ID, err)
+				out.Failed++
+				continue
+			}
+			out.Triggered++
+
+			if uc.Webhooks != nil {
+				if _, err := uc.Webhooks.Execute(ctx, domain.WebhookEventAlertTriggered, rule.UserID, toAlertEventOutput(created)); err != nil {
+					log.Printf("Warning: alert %d: alert.triggered webhooks: %v", rule.ID, err)
+				}
+			}
+
+		case !met && !rule.Armed && rule.Rearm:
+			// el precio volvió del otro lado del umbral: la próxima vez puede dispararse
+			if err := uc.Rules.Rearm(ctx, rule.ID, t); err != nil {
+				log.Printf("Warning: alert %d: rearm: %v", rule.ID, err)
+				out.Failed++
+				continue
+			}
+			out.Rearmed++
+		}
+	}
+
+	return out, nil
+}
+
+func (uc EvaluatePriceAlertsUseCase) latestQuote(ctx context.Context, rule domain.AlertRule, now time.Time) (*alertQuote, error) {
+	pq, err := uc.Quotes.GetLatest(ctx, rule.Symbol, rule.Provider, rule.Currency)
+	if err != nil || pq == nil {
+		return nil, err
+	}
+
+	price, err := strconv.ParseFloat(pq.Price, 64)
+	if err != nil {
+		return nil, err
+	}
+
+	quotedAt := now
+	if ts, err := time.Parse(time.RFC3339, pq.Timestamp); err == nil {
+		quotedAt = ts.UTC()
+	}
+	return &alertQuote{quote: *pq, price: price, quotedAt: quotedAt}, nil
+}
+
+// check arma el evento que se registraría y dice si la condición se cumple
+// (met). known=false si faltan datos para decidirlo.
+func (uc EvaluatePriceAlertsUseCase) check(ctx context.Context, rule domain.AlertRule, q alertQuote) (ev domain.AlertEvent, met, known bool, err error) {
+	ev = domain.AlertEvent{
+		RuleID:    rule.ID,
+		UserID:    rule.UserID,
+		Symbol:    rule.Symbol,
+		Provider:  q.quote.Provider,
+		Currency:  q.quote.Currency,
+		Kind:      rule.Kind,
+		Threshold: rule.Threshold,
+		Price:     q.quote.Price,
+		QuotedAt:  q.quotedAt,
+	}
+
+	switch rule.Kind {
+	case domain.AlertAbove:
+		return ev, q.price >= rule.Threshold, true, nil
+	case domain.AlertBelow:
+		return ev, q.price <= rule.Threshold, true, nil
+	case domain.AlertPercentChange:
+		return uc.checkPercentChange(ctx, rule, q, ev)
+	}
+	return ev, false, false, nil
+}
+
+func (uc EvaluatePriceAlertsUseCase) checkPercentChange(ctx context.Context, rule domain.AlertRule, q alertQuote, ev domain.AlertEvent) (domain.AlertEvent, bool, bool, error) {
+	base, ok, err := uc.referenceQuote(ctx, rule, q)
+	if err != nil || !ok {
+		return ev, false, false, err
+	}
+
+	change := (q.price - base.price) / base.price * 100
+	ev.ReferencePrice = base.quote.Price
+	ev.ChangePercent = &change
+
+	if rule.Threshold > 0 {
+		return ev, change >= rule.Threshold, true, nil
+	}
+	return ev, change <= rule.Threshold, true, nil
+}
+
+// referenceQuote busca el precio al inicio de la ventana: la última cotización
+// de la misma fuente anterior a quotedAt-Window. Si el hueco es más largo que
+// otra ventana no hay referencia (comparar contra un precio de hace días daría
+// cualquier cosa).
+func (uc EvaluatePriceAlertsUseCase) referenceQuote(ctx context.Context, rule domain.AlertRule, q alertQuote) (alertQuote, bool, error) {
+	to := q.quotedAt.Add(-rule.Window)
+	from := to.Add(-rule.Window)
+
+	quotes, _, err := uc.Quotes.ListFilter(ctx, domain.QuoteFilter{
+		Symbol:   rule.Symbol,
+		Provider: q.quote.Provider,
+		Currency: q.quote.Currency,
+		From:     &from,
+		To:       &to,
+		Page:     1,
+		PageSize: 1,
+	})
+	if err != nil || len(quotes) == 0 {
+		return alertQuote{}, false, err
+	}
+
+	base := quotes[0]
+	price, err := strconv.ParseFloat(base.Price, 64)
+	if err != nil {
+		return alertQuote{}, false, err
+	}
+	if price <= 0 {
+		return alertQuote{}, false, nil
+	}
+
+	return alertQuote{
+		quote:    domain.PriceQuote{Symbol: base.Symbol, Currency: base.Currency, Price: base.Price, Provider: base.Provider},
+		price:    price,
+		quotedAt: base.QuotedAt,
+	}, true, nil
+}
@@ -0,0 +1,371 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/moondolphin/crypto-api/domain"
+)
+
+var (
+	ErrInvalidAlertKind      = errors.New("invalid_alert_kind")
+	ErrInvalidAlertThreshold = errors.New("invalid_alert_threshold")
+	ErrInvalidAlertWindow    = errors.New("invalid_alert_window")
+	ErrAlertNotFound         = errors.New("alert_not_found")
+	ErrAlertLimitReached     = errors.New("alert_limit_reached")
+)
+
+const (
+	defaultMaxAlertsPerUser = 50
+
+	minAlertWindow = time.Minute
+	maxAlertWindow = 30 * 24 * time.Hour
+
+	defaultAlertEventsLimit = 50
+	maxAlertEventsLimit     = 200
+)
+
+type CreateAlertInput struct {
+	Symbol   string `json:"symbol"`
+	Currency string `json:"currency,omitempty"`
+	Provider string `json:"provider,omitempty"`
+
+	// above | below | pct_change
+	Kind string `json:"kind"`
+
+	// precio para above/below; porcentaje para pct_change (negativo = caída)
+	Threshold float64 `json:"threshold"`
+
+	// ventana de pct_change, en segundos
+	WindowSeconds int64 `json:"window_seconds,omitempty"`
+
+	// false = one-shot: la regla se desactiva al dispararse
+	Rearm bool `json:"rearm"`
+}
+
+// UpdateAlertInput sólo cambia los campos presentes. Para cambiar coin o tipo
+// se crea otra regla.
+type UpdateAlertInput struct {
+	Threshold     *float64 `json:"threshold,omitempty"`
+	WindowSeconds *int64   `json:"window_seconds,omitempty"`
+	Rearm         *bool    `json:"rearm,omitempty"`
+	Active        *bool    `json:"active,omitempty"`
+}
+
+type AlertOutput struct {
+	ID              int64      `json:"id"`
+	Symbol          string     `json:"symbol"`
+	Currency        string     `json:"currency,omitempty"`
+	Provider        string     `json:"provider,omitempty"`
+	Kind            string     `json:"kind"`
+	Threshold       float64    `json:"threshold"`
+	WindowSeconds   int64      `json:"window_seconds,omitempty"`
+	Rearm           bool       `json:"rearm"`
+	Active          bool       `json:"active"`
+	Armed           bool       `json:"armed"`
+	LastTriggeredAt *time.Time `json:"last_triggered_at,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+type AlertEventOutput struct {
+	ID             int64     `json:"id"`
+	AlertID        int64     `json:"alert_id"`
+	Symbol         string    `json:"symbol"`
+	Provider       string    `json:"provider"`
+	Currency       string    `json:"currency"`
+	Kind           string    `json:"kind"`
+	Threshold      float64   `json:"threshold"`
+	Price          string    `json:"price"`
+	QuotedAt       time.Time `json:"quoted_at"`
+	ReferencePrice string    `json:"reference_price,omitempty"`
+	ChangePercent  *float64  `json:"change_percent,omitempty"`
+	TriggeredAt    time.Time `json:"triggered_at"`
+}
+
+func toAlertOutput(r domain.AlertRule) AlertOutput {
+	return AlertOutput{
+		ID:              r.ID,
+		Symbol:          r.Symbol,
+		Currency:        r.Currency,
+		Provider:        r.Provider,
+		Kind:            r.Kind,
+		Threshold:       r.Threshold,
+		WindowSeconds:   int64(r.Window / time.Second),
+		Rearm:           r.Rearm,
+		Active:          r.Active,
+		Armed:           r.Armed,
+		LastTriggeredAt: r.LastTriggeredAt,
+		CreatedAt:       r.CreatedAt,
+		UpdatedAt:       r.UpdatedAt,
+	}
+}
+
+func toAlertEventOutput(e domain.AlertEvent) AlertEventOutput {
+	return AlertEventOutput{
+		ID:             e.ID,
+		AlertID:        e.RuleID,
+		Symbol:         e.Symbol,
+		Provider:       e.Provider,
+		Currency:       e.Currency,
+		Kind:           e.Kind,
+		Threshold:      e.Threshold,
+		Price:          e.Price,
+		QuotedAt:       e.QuotedAt,
+		ReferencePrice: e.ReferencePrice,
+		ChangePercent:  e.ChangePercent,
+		TriggeredAt:    e.TriggeredAt,
+	}
+}
+
+// validateAlertRule valida umbral y ventana según el tipo de regla.
+func validateAlertRule(r domain.AlertRule) error {
+	switch r.Kind {
+	case domain.AlertAbove, domain.AlertBelow:
+		if r.Threshold <= 0 {
+			return ErrInvalidAlertThreshold
+		}
+		if r.Window != 0 {
+			return ErrInvalidAlertWindow
+		}
+	case domain.AlertPercentChange:
+		// una caída de 100% o más no puede pasar
+		if r.Threshold == 0 || r.Threshold <= -100 {
+			return ErrInvalidAlertThreshold
+		}
+		if r.Window < minAlertWindow || r.Window > maxAlertWindow {
+			return ErrInvalidAlertWindow
+		}
+	default:
+		return ErrInvalidAlertKind
+	}
+	return nil
+}
+
+type CreateAlertUseCase struct {
+	Rules     domain.AlertRuleRepository
+	CoinRepo  domain.CoinRepository
+	Providers domain.PriceProviderRegistry
+	Now       func() time.Time
+
+	// máximo de reglas por usuario (activas o no)
+	MaxPerUser int
+}
+
+func (uc CreateAlertUseCase) Execute(ctx context.Context, userID int64, in CreateAlertInput) (AlertOutput, error) {
+	symbol := strings.ToUpper(strings.TrimSpace(in.Symbol))
+	currency := strings.ToUpper(strings.TrimSpace(in.Currency))
+	provider := strings.ToLower(strings.TrimSpace(in.Provider))
+
+	if symbol == "" || len(currency) > 10 {
+		return AlertOutput{}, ErrBadRequest
+	}
+
+	now := uc.Now
+	if now == nil {
+		now = time.Now
+	}
+	t := now().UTC()
+
+	rule := domain.AlertRule{
+		UserID:    userID,
+		Symbol:    symbol,
+		Currency:  currency,
+		Provider:  provider,
+		Kind:      strings.ToLower(strings.TrimSpace(in.Kind)),
+		Threshold: in.Threshold,
+		Window:    time.Duration(in.WindowSeconds) * time.Second,
+		Rearm:     in.Rearm,
+		Active:    true,
+		Armed:     true,
+		CreatedAt: t,
+		UpdatedAt: t,
+	}
+	if err := validateAlertRule(rule); err != nil {
+		return AlertOutput{}, err
+	}
+
+	if provider != "" {
+		if _, ok := uc.Providers.Get(provider); !ok {
+			return AlertOutput{}, ErrProviderNotSupported
+		}
+	}
+
+	coin, err := uc.CoinRepo.GetEnabledBySymbol(ctx, symbol)
+	if err != nil {
+		return AlertOutput{}, err
+	}
+	if coin == nil {
+		return AlertOutput{}, ErrCoinNotEnabled
+	}
+	rule.CoinID = coin.ID
+
+	limit := uc.MaxPerUser
+	if limit <= 0 {
+		limit = defaultMaxAlertsPerUser
+	}
+	n, err := uc.Rules.CountByUser(ctx, userID)
+	if err != nil {
+		return AlertOutput{}, err
+	}
+	if n >= limit {
+		return AlertOutput{}, ErrAlertLimitReached
+	}
+
+	created, err := uc.Rules.Create(ctx, rule)
+	if err != nil {
+		return AlertOutput{}, err
+	}
+	return toAlertOutput(created), nil
+}
+
+type ListAlertsUseCase struct {
+	Rules domain.AlertRuleRepository
+}
+
+func (uc ListAlertsUseCase) Execute(ctx context.Context, userID int64) ([]AlertOutput, error) {
+	rules, err := uc.Rules.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]AlertOutput, 0, len(rules))
+	for _, r := range rules {
+		out = append(out, toAlertOutput(r))
+	}
+	return out, nil
+}
+
+// findAlert busca la regla del usuario: nil -> ErrAlertNotFound.
+func findAlert(ctx context.Context, rules domain.AlertRuleRepository, userID, id int64) (*domain.AlertRule, error) {
+	if id <= 0 {
+		return nil, ErrBadRequest
+	}
+	r, err := rules.FindByUser(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if r == nil {
+		return nil, ErrAlertNotFound
+	}
+	return r, nil
+}
+
+type GetAlertUseCase struct {
+	Rules domain.AlertRuleRepository
+}
+
+func (uc GetAlertUseCase) Execute(ctx context.Context, userID, id int64) (AlertOutput, error) {
+	r, err := findAlert(ctx, uc.Rules, userID, id)
+	if err != nil {
+		return AlertOutput{}, err
+	}
+	return toAlertOutput(*r), nil
+}
+
+type UpdateAlertUseCase struct {
+	Rules domain.AlertRuleRepository
+	Now   func() time.Time
+}
+
+func (uc UpdateAlertUseCase) Execute(ctx context.Context, userID, id int64, in UpdateAlertInput) (AlertOutput, error) {
+	r, err := findAlert(ctx, uc.Rules, userID, id)
+	if err != nil {
+		return AlertOutput{}, err
+	}
+
+	now := uc.Now
+	if now == nil {
+		now = time.Now
+	}
+
+	// otra condición, o una regla que se vuelve a activar, arranca armada
+	rearm := false
+	if in.Threshold != nil && *in.Threshold != r.Threshold {
+		r.Threshold = *in.Threshold
+		rearm = true
+	}
+	if in.WindowSeconds != nil && time.Duration(*in.WindowSeconds)*time.Second != r.Window {
+		r.Window = time.Duration(*in.WindowSeconds) * time.Second
+		rearm = true
+	}
+	if in.Rearm != nil {
+		r.Rearm = *in.Rearm
+	}
+	if in.Active != nil {
+		if *in.Active && !r.Active {
+			rearm = true
+		}
+		r.Active = *in.Active
+	}
+	if rearm {
+		r.Armed = true
+	}
+
+	if err := validateAlertRule(*r); err != nil {
+		return AlertOutput{}, err
+	}
+
+	r.UpdatedAt = now().UTC()
+	ok, err := uc.Rules.Update(ctx, *r)
+	if err != nil {
+		return AlertOutput{}, err
+	}
+	if !ok {
+		return AlertOutput{}, ErrAlertNotFound
+	}
+	return toAlertOutput(*r), nil
+}
+
+type DeleteAlertUseCase struct {
+	Rules domain.AlertRuleRepository
+}
+
+func (uc DeleteAlertUseCase) Execute(ctx context.Context, userID, id int64) error {
+	if id <= 0 {
+		return ErrBadRequest
+	}
+
+	ok, err := uc.Rules.Delete(ctx, userID, id)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrAlertNotFound
+	}
+	return nil
+}
+
+type ListAlertEventsUseCase struct {
+	Rules  domain.AlertRuleRepository
+	Events domain.AlertEventRepository
+}
+
+// Execute lista los disparos del usuario, de una regla si alertID > 0.
+func (uc ListAlertEventsUseCase) Execute(ctx context.Context, userID, alertID int64, limit int) ([]AlertEventOutput, error) {
+	if alertID > 0 {
+		if _, err := findAlert(ctx, uc.Rules, userID, alertID); err != nil {
+			return nil, err
+		}
+	}
+
+	if limit <= 0 {
+		limit = defaultAlertEventsLimit
+	}
+	if limit > maxAlertEventsLimit {
+		limit = maxAlertEventsLimit
+	}
+
+	events, err := uc.Events.ListByUser(ctx, userID, alertID, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]AlertEventOutput, 0, len(events))
+	for _, e := range events {
+		out = append(out, toAlertEventOutput(e))
+	}
+	return out, nil
+}
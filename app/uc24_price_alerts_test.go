@@ -0,0 +1,725 @@
+package app_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/moondolphin/crypto-api/app"
+	"github.com/moondolphin/crypto-api/domain"
+	"github.com/moondolphin/crypto-api/test/mocks"
+)
+
+func floatPtr(v float64) *float64 { return &v }
+func boolPtr(v bool) *bool        { return &v }
+
+func TestUC24CreateAlert_RejectsInvalidRules(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	uc := app.CreateAlertUseCase{
+		Rules:     mocks.NewMockAlertRuleRepository(ctrl),
+		CoinRepo:  mocks.NewMockCoinRepository(ctrl),
+		Providers: mocks.NewMockPriceProviderRegistry(ctrl),
+	}
+
+	cases := map[string]struct {
+		in   app.CreateAlertInput
+		want error
+	}{
+		"missing symbol":          {in: app.CreateAlertInput{Kind: "above", Threshold: 1}, want: app.ErrBadRequest},
+		"unknown kind":            {in: app.CreateAlertInput{Symbol: "BTC", Kind: "crosses", Threshold: 1}, want: app.ErrInvalidAlertKind},
+		"above without price":     {in: app.CreateAlertInput{Symbol: "BTC", Kind: "above"}, want: app.ErrInvalidAlertThreshold},
+		"below negative price":    {in: app.CreateAlertInput{Symbol: "BTC", Kind: "below", Threshold: -3}, want: app.ErrInvalidAlertThreshold},
+		"above with window":       {in: app.CreateAlertInput{Symbol: "BTC", Kind: "above", Threshold: 1, WindowSeconds: 60}, want: app.ErrInvalidAlertWindow},
+		"pct without window":      {in: app.CreateAlertInput{Symbol: "BTC", Kind: "pct_change", Threshold: 5}, want: app.ErrInvalidAlertWindow},
+		"pct window too long":     {in: app.CreateAlertInput{Symbol: "BTC", Kind: "pct_change", Threshold: 5, WindowSeconds: 31 * 24 * 3600}, want: app.ErrInvalidAlertWindow},
+		"pct zero":                {in: app.CreateAlertInput{Symbol: "BTC", Kind: "pct_change", WindowSeconds: 3600}, want: app.ErrInvalidAlertThreshold},
+		"pct drop of 100 percent": {in: app.CreateAlertInput{Symbol: "BTC", Kind: "pct_change", Threshold: -100, WindowSeconds: 3600}, want: app.ErrInvalidAlertThreshold},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			// Act
+			_, err := uc.Execute(context.Background(), 7, tc.in)
+
+			// Assert
+			require.ErrorIs(t, err, tc.want)
+		})
+	}
+}
+
+func TestUC24CreateAlert_UnknownProvider(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	providers := mocks.NewMockPriceProviderRegistry(ctrl)
+	providers.EXPECT().Get("kraken").Return(nil, false)
+
+	uc := app.CreateAlertUseCase{
+		Rules:     mocks.NewMockAlertRuleRepository(ctrl),
+		CoinRepo:  mocks.NewMockCoinRepository(ctrl),
+		Providers: providers,
+	}
+
+	// Act
+	_, err := uc.Execute(context.Background(), 7, app.CreateAlertInput{Symbol: "BTC", Provider: "Kraken", Kind: "above", Threshold: 1})
+
+	// Assert
+	require.ErrorIs(t, err, app.ErrProviderNotSupported)
+}
+
+func TestUC24CreateAlert_CoinNotEnabled(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	coinRepo := mocks.NewMockCoinRepository(ctrl)
+	coinRepo.EXPECT().GetEnabledBySymbol(gomock.Any(), "DOGE").Return(nil, nil)
+
+	uc := app.CreateAlertUseCase{
+		Rules:     mocks.NewMockAlertRuleRepository(ctrl),
+		CoinRepo:  coinRepo,
+		Providers: mocks.NewMockPriceProviderRegistry(ctrl),
+	}
+
+	// Act
+	_, err := uc.Execute(context.Background(), 7, app.CreateAlertInput{Symbol: "doge", Kind: "below", Threshold: 0.1})
+
+	// Assert
+	require.ErrorIs(t, err, app.ErrCoinNotEnabled)
+}
+
+func TestUC24CreateAlert_LimitReached(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	coinRepo := mocks.NewMockCoinRepository(ctrl)
+	rules := mocks.NewMockAlertRuleRepository(ctrl)
+	coinRepo.EXPECT().GetEnabledBySymbol(gomock.Any(), "BTC").Return(&domain.Coin{ID: 1, Symbol: "BTC", Enabled: true}, nil)
+	rules.EXPECT().CountByUser(gomock.Any(), int64(7)).Return(3, nil)
+
+	uc := app.CreateAlertUseCase{
+		Rules:      rules,
+		CoinRepo:   coinRepo,
+		Providers:  mocks.NewMockPriceProviderRegistry(ctrl),
+		MaxPerUser: 3,
+	}
+
+	// Act
+	_, err := uc.Execute(context.Background(), 7, app.CreateAlertInput{Symbol: "BTC", Kind: "above", Threshold: 100000})
+
+	// Assert
+	require.ErrorIs(t, err, app.ErrAlertLimitReached)
+}
+
+func TestUC24CreateAlert_Success_PercentChange(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Date(2026, 1, 24, 10, 0, 0, 0, time.UTC)
+	coinRepo := mocks.NewMockCoinRepository(ctrl)
+	rules := mocks.NewMockAlertRuleRepository(ctrl)
+	providers := mocks.NewMockPriceProviderRegistry(ctrl)
+
+	providers.EXPECT().Get("coingecko").Return(mocks.NewMockPriceProvider(ctrl), true)
+	coinRepo.EXPECT().GetEnabledBySymbol(gomock.Any(), "BTC").Return(&domain.Coin{ID: 1, Symbol: "BTC", Enabled: true}, nil)
+	rules.EXPECT().CountByUser(gomock.Any(), int64(7)).Return(0, nil)
+	rules.EXPECT().Create(gomock.Any(), domain.AlertRule{
+		UserID:    7,
+		CoinID:    1,
+		Symbol:    "BTC",
+		Currency:  "USD",
+		Provider:  "coingecko",
+		Kind:      domain.AlertPercentChange,
+		Threshold: -5,
+		Window:    time.Hour,
+		Rearm:     true,
+		Active:    true,
+		Armed:     true,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}).DoAndReturn(func(_ context.Context, r domain.AlertRule) (domain.AlertRule, error) {
+		r.ID = 11
+		return r, nil
+	})
+
+	uc := app.CreateAlertUseCase{
+		Rules:     rules,
+		CoinRepo:  coinRepo,
+		Providers: providers,
+		Now:       func() time.Time { return now },
+	}
+
+	// Act
+	out, err := uc.Execute(context.Background(), 7, app.CreateAlertInput{
+		Symbol:        " btc ",
+		Currency:      "usd",
+		Provider:      "coingecko",
+		Kind:          "PCT_CHANGE",
+		Threshold:     -5,
+		WindowSeconds: 3600,
+		Rearm:         true,
+	})
+
+	// Assert
+	require.NoError(t, err)
+	require.Equal(t, int64(11), out.ID)
+	require.Equal(t, "BTC", out.Symbol)
+	require.Equal(t, int64(3600), out.WindowSeconds)
+	require.True(t, out.Active)
+	require.True(t, out.Armed)
+}
+
+func TestUC24UpdateAlert_NotFound(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	rules := mocks.NewMockAlertRuleRepository(ctrl)
+	rules.EXPECT().FindByUser(gomock.Any(), int64(7), int64(3)).Return(nil, nil)
+
+	uc := app.UpdateAlertUseCase{Rules: rules}
+
+	// Act
+	_, err := uc.Execute(context.Background(), 7, 3, app.UpdateAlertInput{Threshold: floatPtr(2)})
+
+	// Assert
+	require.ErrorIs(t, err, app.ErrAlertNotFound)
+}
+
+func TestUC24UpdateAlert_ReactivatingOneShotRearmsIt(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	created := time.Date(2026, 1, 20, 10, 0, 0, 0, time.UTC)
+	now := created.Add(48 * time.Hour)
+	fired := created.Add(24 * time.Hour)
+
+	rules := mocks.NewMockAlertRuleRepository(ctrl)
+	rules.EXPECT().FindByUser(gomock.Any(), int64(7), int64(3)).Return(&domain.AlertRule{
+		ID: 3, UserID: 7, Symbol: "BTC", Kind: domain.AlertAbove, Threshold: 100000,
+		Active: false, Armed: false, LastTriggeredAt: &fired, CreatedAt: created, UpdatedAt: fired,
+	}, nil)
+	rules.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, r domain.AlertRule) (bool, error) {
+		require.True(t, r.Active)
+		require.True(t, r.Armed)
+		require.Equal(t, 110000.0, r.Threshold)
+		require.Equal(t, now, r.UpdatedAt)
+		return true, nil
+	})
+
+	uc := app.UpdateAlertUseCase{Rules: rules, Now: func() time.Time { return now }}
+
+	// Act
+	out, err := uc.Execute(context.Background(), 7, 3, app.UpdateAlertInput{Threshold: floatPtr(110000), Active: boolPtr(true)})
+
+	// Assert
+	require.NoError(t, err)
+	require.True(t, out.Active)
+	require.Equal(t, &fired, out.LastTriggeredAt)
+}
+
+func TestUC24UpdateAlert_TogglingRearmKeepsArmedState(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	rules := mocks.NewMockAlertRuleRepository(ctrl)
+	rules.EXPECT().FindByUser(gomock.Any(), int64(7), int64(3)).Return(&domain.AlertRule{
+		ID: 3, UserID: 7, Symbol: "BTC", Kind: domain.AlertBelow, Threshold: 50000, Rearm: true, Active: true, Armed: false,
+	}, nil)
+	rules.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, r domain.AlertRule) (bool, error) {
+		require.False(t, r.Rearm)
+		require.False(t, r.Armed)
+		return true, nil
+	})
+
+	uc := app.UpdateAlertUseCase{Rules: rules}
+
+	// Act
+	_, err := uc.Execute(context.Background(), 7, 3, app.UpdateAlertInput{Rearm: boolPtr(false), Threshold: floatPtr(50000)})
+
+	// Assert
+	require.NoError(t, err)
+}
+
+func TestUC24UpdateAlert_InvalidWindow(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	rules := mocks.NewMockAlertRuleRepository(ctrl)
+	rules.EXPECT().FindByUser(gomock.Any(), int64(7), int64(3)).Return(&domain.AlertRule{
+		ID: 3, UserID: 7, Kind: domain.AlertPercentChange, Threshold: 5, Window: time.Hour, Active: true, Armed: true,
+	}, nil)
+
+	uc := app.UpdateAlertUseCase{Rules: rules}
+
+	// Act
+	var window int64 = 10
+	_, err := uc.Execute(context.Background(), 7, 3, app.UpdateAlertInput{WindowSeconds: &window})
+
+	// Assert
+	require.ErrorIs(t, err, app.ErrInvalidAlertWindow)
+}
+
+func TestUC24DeleteAlert_NotFound(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	rules := mocks.NewMockAlertRuleRepository(ctrl)
+	rules.EXPECT().Delete(gomock.Any(), int64(7), int64(3)).Return(false, nil)
+
+	// Act
+	err := app.DeleteAlertUseCase{Rules: rules}.Execute(context.Background(), 7, 3)
+
+	// Assert
+	require.ErrorIs(t, err, app.ErrAlertNotFound)
+}
+
+func TestUC24ListAlertEvents_OtherUsersAlert(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	rules := mocks.NewMockAlertRuleRepository(ctrl)
+	rules.EXPECT().FindByUser(gomock.Any(), int64(7), int64(3)).Return(nil, nil)
+
+	uc := app.ListAlertEventsUseCase{Rules: rules, Events: mocks.NewMockAlertEventRepository(ctrl)}
+
+	// Act
+	_, err := uc.Execute(context.Background(), 7, 3, 10)
+
+	// Assert
+	require.ErrorIs(t, err, app.ErrAlertNotFound)
+}
+
+func TestUC24ListAlertEvents_ClampsLimit(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	events := mocks.NewMockAlertEventRepository(ctrl)
+	events.EXPECT().ListByUser(gomock.Any(), int64(7), int64(0), 200).Return([]domain.AlertEvent{
+		{ID: 1, RuleID: 3, UserID: 7, Symbol: "BTC", Kind: domain.AlertAbove, Price: "100001"},
+	}, nil)
+
+	uc := app.ListAlertEventsUseCase{Rules: mocks.NewMockAlertRuleRepository(ctrl), Events: events}
+
+	// Act
+	out, err := uc.Execute(context.Background(), 7, 0, 5000)
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, out, 1)
+	require.Equal(t, int64(3), out[0].AlertID)
+}
+
+// expectLatestBTC deja la última cotización de BTC en price, tomada en quotedAt.
+func expectLatestBTC(quotes *mocks.MockQuoteRepository, price string, quotedAt time.Time) {
+	quotes.EXPECT().GetLatest(gomock.Any(), "BTC", "", "").Return(&domain.PriceQuote{
+		Symbol:    "BTC",
+		Provider:  "coingecko",
+		Currency:  "USD",
+		Price:     price,
+		Timestamp: quotedAt.Format(time.RFC3339),
+	}, nil)
+}
+
+func TestUC24EvaluateAlerts_OneShotAboveTriggersAndDeactivates(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fixedNow := time.Date(2026, 1, 24, 10, 0, 0, 0, time.UTC)
+
+	rules := mocks.NewMockAlertRuleRepository(ctrl)
+	events := mocks.NewMockAlertEventRepository(ctrl)
+	quotes := mocks.NewMockQuoteRepository(ctrl)
+
+	quotedAt := fixedNow.Add(-time.Minute)
+
+	rules.EXPECT().ListActive(gomock.Any()).Return([]domain.AlertRule{
+		{ID: 1, UserID: 7, Symbol: "BTC", Kind: domain.AlertAbove, Threshold: 100000, Active: true, Armed: true},
+		{ID: 2, UserID: 8, Symbol: "BTC", Kind: domain.AlertAbove, Threshold: 150000, Active: true, Armed: true},
+	}, nil)
+	// una sola consulta para las dos reglas
+	expectLatestBTC(quotes, "100000.5", quotedAt)
+	rules.EXPECT().MarkTriggered(gomock.Any(), int64(1), fixedNow, true).Return(true, nil)
+	events.EXPECT().Create(gomock.Any(), domain.AlertEvent{
+		RuleID:      1,
+		UserID:      7,
+		Symbol:      "BTC",
+		Provider:    "coingecko",
+		Currency:    "USD",
+		Kind:        domain.AlertAbove,
+		Threshold:   100000,
+		Price:       "100000.5",
+		QuotedAt:    quotedAt,
+		TriggeredAt: fixedNow,
+	}).Return(domain.AlertEvent{ID: 1}, nil)
+
+	uc := app.EvaluatePriceAlertsUseCase{
+		Rules:  rules,
+		Events: events,
+		Quotes: quotes,
+		Now:    func() time.Time { return fixedNow },
+	}
+
+	// Act
+	out, err := uc.Execute(context.Background())
+
+	// Assert
+	require.NoError(t, err)
+	require.Equal(t, app.EvaluatePriceAlertsOutput{RulesEvaluated: 2, Triggered: 1}, out)
+}
+
+func TestUC24EvaluateAlerts_DisarmedRuleDoesNotFireAgain(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fixedNow := time.Date(2026, 1, 24, 10, 0, 0, 0, time.UTC)
+
+	rules := mocks.NewMockAlertRuleRepository(ctrl)
+	events := mocks.NewMockAlertEventRepository(ctrl)
+	quotes := mocks.NewMockQuoteRepository(ctrl)
+
+	rules.EXPECT().ListActive(gomock.Any()).Return([]domain.AlertRule{
+		{ID: 1, UserID: 7, Symbol: "BTC", Kind: domain.AlertBelow, Threshold: 50000, Rearm: true, Active: true, Armed: false},
+	}, nil)
+	expectLatestBTC(quotes, "49000", fixedNow)
+
+	uc := app.EvaluatePriceAlertsUseCase{
+		Rules:  rules,
+		Events: events,
+		Quotes: quotes,
+		Now:    func() time.Time { return fixedNow },
+	}
+
+	// Act
+	out, err := uc.Execute(context.Background())
+
+	// Assert
+	require.NoError(t, err)
+	require.Equal(t, 0, out.Triggered)
+	require.Equal(t, 0, out.Rearmed)
+}
+
+func TestUC24EvaluateAlerts_RearmsWhenConditionClears(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fixedNow := time.Date(2026, 1, 24, 10, 0, 0, 0, time.UTC)
+
+	rules := mocks.NewMockAlertRuleRepository(ctrl)
+	events := mocks.NewMockAlertEventRepository(ctrl)
+	quotes := mocks.NewMockQuoteRepository(ctrl)
+
+	rules.EXPECT().ListActive(gomock.Any()).Return([]domain.AlertRule{
+		{ID: 1, UserID: 7, Symbol: "BTC", Kind: domain.AlertBelow, Threshold: 50000, Rearm: true, Active: true, Armed: false},
+	}, nil)
+	expectLatestBTC(quotes, "51000", fixedNow)
+	rules.EXPECT().Rearm(gomock.Any(), int64(1), fixedNow).Return(nil)
+
+	uc := app.EvaluatePriceAlertsUseCase{
+		Rules:  rules,
+		Events: events,
+		Quotes: quotes,
+		Now:    func() time.Time { return fixedNow },
+	}
+
+	// Act
+	out, err := uc.Execute(context.Background())
+
+	// Assert
+	require.NoError(t, err)
+	require.Equal(t, 1, out.Rearmed)
+}
+
+func TestUC24EvaluateAlerts_RearmingRuleStaysActive(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fixedNow := time.Date(2026, 1, 24, 10, 0, 0, 0, time.UTC)
+
+	rules := mocks.NewMockAlertRuleRepository(ctrl)
+	events := mocks.NewMockAlertEventRepository(ctrl)
+	quotes := mocks.NewMockQuoteRepository(ctrl)
+
+	rules.EXPECT().ListActive(gomock.Any()).Return([]domain.AlertRule{
+		{ID: 1, UserID: 7, Symbol: "BTC", Kind: domain.AlertBelow, Threshold: 50000, Rearm: true, Active: true, Armed: true},
+	}, nil)
+	expectLatestBTC(quotes, "49000", fixedNow)
+	rules.EXPECT().MarkTriggered(gomock.Any(), int64(1), fixedNow, false).Return(true, nil)
+	events.EXPECT().Create(gomock.Any(), gomock.Any()).Return(domain.AlertEvent{ID: 1}, nil)
+
+	uc := app.EvaluatePriceAlertsUseCase{
+		Rules:  rules,
+		Events: events,
+		Quotes: quotes,
+		Now:    func() time.Time { return fixedNow },
+	}
+
+	// Act
+	out, err := uc.Execute(context.Background())
+
+	// Assert
+	require.NoError(t, err)
+	require.Equal(t, 1, out.Triggered)
+}
+
+func TestUC24EvaluateAlerts_NoEventWhenAlreadyTriggered(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fixedNow := time.Date(2026, 1, 24, 10, 0, 0, 0, time.UTC)
+
+	rules := mocks.NewMockAlertRuleRepository(ctrl)
+	events := mocks.NewMockAlertEventRepository(ctrl)
+	quotes := mocks.NewMockQuoteRepository(ctrl)
+
+	rules.EXPECT().ListActive(gomock.Any()).Return([]domain.AlertRule{
+		{ID: 1, UserID: 7, Symbol: "BTC", Kind: domain.AlertAbove, Threshold: 1, Active: true, Armed: true},
+	}, nil)
+	expectLatestBTC(quotes, "2", fixedNow)
+	// otro evaluador la disparó entre ListActive y MarkTriggered
+	rules.EXPECT().MarkTriggered(gomock.Any(), int64(1), fixedNow, true).Return(false, nil)
+
+	uc := app.EvaluatePriceAlertsUseCase{
+		Rules:  rules,
+		Events: events,
+		Quotes: quotes,
+		Now:    func() time.Time { return fixedNow },
+	}
+
+	// Act
+	out, err := uc.Execute(context.Background())
+
+	// Assert
+	require.NoError(t, err)
+	require.Equal(t, 0, out.Triggered)
+}
+
+func TestUC24EvaluateAlerts_PercentDropWithinWindow(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fixedNow := time.Date(2026, 1, 24, 10, 0, 0, 0, time.UTC)
+
+	rules := mocks.NewMockAlertRuleRepository(ctrl)
+	events := mocks.NewMockAlertEventRepository(ctrl)
+	quotes := mocks.NewMockQuoteRepository(ctrl)
+
+	quotedAt := fixedNow.Add(-time.Minute)
+	to := quotedAt.Add(-time.Hour)
+	from := to.Add(-time.Hour)
+
+	rules.EXPECT().ListActive(gomock.Any()).Return([]domain.AlertRule{
+		{ID: 1, UserID: 7, Symbol: "BTC", Kind: domain.AlertPercentChange, Threshold: -5, Window: time.Hour, Active: true, Armed: true},
+	}, nil)
+	expectLatestBTC(quotes, "94000", quotedAt)
+	quotes.EXPECT().ListFilter(gomock.Any(), domain.QuoteFilter{
+		Symbol:   "BTC",
+		Provider: "coingecko",
+		Currency: "USD",
+		From:     &from,
+		To:       &to,
+		Page:     1,
+		PageSize: 1,
+	}).Return([]domain.Quote{{Symbol: "BTC", Provider: "coingecko", Currency: "USD", Price: "100000", QuotedAt: to.Add(-time.Minute)}}, 1, nil)
+	rules.EXPECT().MarkTriggered(gomock.Any(), int64(1), fixedNow, true).Return(true, nil)
+	events.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, e domain.AlertEvent) (domain.AlertEvent, error) {
+		require.Equal(t, "94000", e.Price)
+		require.Equal(t, "100000", e.ReferencePrice)
+		require.NotNil(t, e.ChangePercent)
+		require.InDelta(t, -6.0, *e.ChangePercent, 1e-9)
+		return e, nil
+	})
+
+	uc := app.EvaluatePriceAlertsUseCase{
+		Rules:  rules,
+		Events: events,
+		Quotes: quotes,
+		Now:    func() time.Time { return fixedNow },
+	}
+
+	// Act
+	out, err := uc.Execute(context.Background())
+
+	// Assert
+	require.NoError(t, err)
+	require.Equal(t, 1, out.Triggered)
+}
+
+func TestUC24EvaluateAlerts_PercentRiseBelowThreshold(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fixedNow := time.Date(2026, 1, 24, 10, 0, 0, 0, time.UTC)
+
+	rules := mocks.NewMockAlertRuleRepository(ctrl)
+	events := mocks.NewMockAlertEventRepository(ctrl)
+	quotes := mocks.NewMockQuoteRepository(ctrl)
+
+	rules.EXPECT().ListActive(gomock.Any()).Return([]domain.AlertRule{
+		{ID: 1, UserID: 7, Symbol: "BTC", Kind: domain.AlertPercentChange, Threshold: 5, Window: time.Hour, Active: true, Armed: true},
+	}, nil)
+	expectLatestBTC(quotes, "104000", fixedNow)
+	quotes.EXPECT().ListFilter(gomock.Any(), gomock.Any()).
+		Return([]domain.Quote{{Symbol: "BTC", Provider: "coingecko", Currency: "USD", Price: "100000"}}, 1, nil)
+
+	uc := app.EvaluatePriceAlertsUseCase{
+		Rules:  rules,
+		Events: events,
+		Quotes: quotes,
+		Now:    func() time.Time { return fixedNow },
+	}
+
+	// Act
+	out, err := uc.Execute(context.Background())
+
+	// Assert
+	require.NoError(t, err)
+	require.Equal(t, 0, out.Triggered)
+}
+
+func TestUC24EvaluateAlerts_PercentWithoutReferenceNeitherFiresNorRearms(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fixedNow := time.Date(2026, 1, 24, 10, 0, 0, 0, time.UTC)
+
+	rules := mocks.NewMockAlertRuleRepository(ctrl)
+	events := mocks.NewMockAlertEventRepository(ctrl)
+	quotes := mocks.NewMockQuoteRepository(ctrl)
+
+	rules.EXPECT().ListActive(gomock.Any()).Return([]domain.AlertRule{
+		{ID: 1, UserID: 7, Symbol: "BTC", Kind: domain.AlertPercentChange, Threshold: -5, Window: time.Hour, Rearm: true, Active: true, Armed: false},
+	}, nil)
+	expectLatestBTC(quotes, "94000", fixedNow)
+	quotes.EXPECT().ListFilter(gomock.Any(), gomock.Any()).Return(nil, 0, nil)
+
+	uc := app.EvaluatePriceAlertsUseCase{
+		Rules:  rules,
+		Events: events,
+		Quotes: quotes,
+		Now:    func() time.Time { return fixedNow },
+	}
+
+	// Act
+	out, err := uc.Execute(context.Background())
+
+	// Assert
+	require.NoError(t, err)
+	require.Equal(t, app.EvaluatePriceAlertsOutput{RulesEvaluated: 1}, out)
+}
+
+func TestUC24EvaluateAlerts_SkipsRulesWithoutQuotes(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fixedNow := time.Date(2026, 1, 24, 10, 0, 0, 0, time.UTC)
+
+	rules := mocks.NewMockAlertRuleRepository(ctrl)
+	events := mocks.NewMockAlertEventRepository(ctrl)
+	quotes := mocks.NewMockQuoteRepository(ctrl)
+
+	rules.EXPECT().ListActive(gomock.Any()).Return([]domain.AlertRule{
+		{ID: 1, UserID: 7, Symbol: "BTC", Currency: "EUR", Kind: domain.AlertAbove, Threshold: 1, Active: true, Armed: true},
+	}, nil)
+	quotes.EXPECT().GetLatest(gomock.Any(), "BTC", "", "EUR").Return(nil, nil)
+
+	uc := app.EvaluatePriceAlertsUseCase{
+		Rules:  rules,
+		Events: events,
+		Quotes: quotes,
+		Now:    func() time.Time { return fixedNow },
+	}
+
+	// Act
+	out, err := uc.Execute(context.Background())
+
+	// Assert
+	require.NoError(t, err)
+	require.Equal(t, app.EvaluatePriceAlertsOutput{RulesEvaluated: 1}, out)
+}
+
+func TestUC24EvaluateAlerts_CountsFailuresAndContinues(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fixedNow := time.Date(2026, 1, 24, 10, 0, 0, 0, time.UTC)
+
+	rules := mocks.NewMockAlertRuleRepository(ctrl)
+	events := mocks.NewMockAlertEventRepository(ctrl)
+	quotes := mocks.NewMockQuoteRepository(ctrl)
+
+	rules.EXPECT().ListActive(gomock.Any()).Return([]domain.AlertRule{
+		{ID: 1, UserID: 7, Symbol: "ETH", Kind: domain.AlertAbove, Threshold: 1, Active: true, Armed: true},
+		{ID: 2, UserID: 7, Symbol: "BTC", Kind: domain.AlertAbove, Threshold: 1, Active: true, Armed: true},
+	}, nil)
+	quotes.EXPECT().GetLatest(gomock.Any(), "ETH", "", "").Return(nil, errors.New("db_error"))
+	expectLatestBTC(quotes, "2", fixedNow)
+	rules.EXPECT().MarkTriggered(gomock.Any(), int64(2), fixedNow, true).Return(true, nil)
+	events.EXPECT().Create(gomock.Any(), gomock.Any()).Return(domain.AlertEvent{ID: 1}, nil)
+
+	uc := app.EvaluatePriceAlertsUseCase{
+		Rules:  rules,
+		Events: events,
+		Quotes: quotes,
+		Now:    func() time.Time { return fixedNow },
+	}
+
+	// Act
+	out, err := uc.Execute(context.Background())
+
+	// Assert
+	require.NoError(t, err)
+	require.Equal(t, app.EvaluatePriceAlertsOutput{RulesEvaluated: 2, Triggered: 1, Failed: 1}, out)
+}
+
+func TestUC24EvaluateAlerts_RepoError_WhenListActiveFails(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fixedNow := time.Date(2026, 1, 24, 10, 0, 0, 0, time.UTC)
+
+	rules := mocks.NewMockAlertRuleRepository(ctrl)
+	events := mocks.NewMockAlertEventRepository(ctrl)
+	quotes := mocks.NewMockQuoteRepository(ctrl)
+
+	rules.EXPECT().ListActive(gomock.Any()).Return(nil, errors.New("db_error"))
+
+	uc := app.EvaluatePriceAlertsUseCase{
+		Rules:  rules,
+		Events: events,
+		Quotes: quotes,
+		Now:    func() time.Time { return fixedNow },
+	}
+
+	// Act
+	_, err := uc.Execute(context.Background())
+
+	// Assert
+	require.EqualError(t, err, "db_error")
+}
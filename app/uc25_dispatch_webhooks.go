@@ -0,0 +1,286 @@
+package app
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/moondolphin/crypto-api/domain"
+)
+
+// Headers de cada entrega. La firma es
+// "t=<unix>,v1=<hex(HMAC-SHA256(secret, "<unix>.<body>"))>": el receptor
+// recalcula el HMAC y puede descartar timestamps viejos contra replays.
+const (
+	WebhookSignatureHeader = "X-Webhook-Signature"
+	WebhookEventHeader     = "X-Webhook-Event"
+	WebhookIDHeader        = "X-Webhook-Id"
+	WebhookDeliveryHeader  = "X-Webhook-Delivery"
+)
+
+const (
+	defaultWebhookMaxAttempts = 8
+	defaultWebhookBaseBackoff = 30 * time.Second
+	defaultWebhookMaxBackoff  = 6 * time.Hour
+	defaultWebhookBatchSize   = 50
+	defaultWebhookLease       = 2 * time.Minute
+
+	maxWebhookErrorLength = 500
+)
+
+// SignWebhookPayload devuelve el valor del header X-Webhook-Signature.
+func SignWebhookPayload(secret string, timestamp int64, body []byte) string {
+	ts := strconv.FormatInt(timestamp, 10)
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(ts))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "t=" + ts + ",v1=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// webhookEnvelope es el cuerpo JSON de toda entrega.
+type webhookEnvelope struct {
+	ID        string    `json:"id"`
+	Type      string    `json:"type"`
+	CreatedAt time.Time `json:"created_at"`
+	Data      any       `json:"data"`
+}
+
+// WebhookQuoteData es una cotización del evento quotes.stored.
+type WebhookQuoteData struct {
+	Symbol   string    `json:"symbol"`
+	Provider string    `json:"provider"`
+	Currency string    `json:"currency"`
+	Price    string    `json:"price"`
+	QuotedAt time.Time `json:"quoted_at"`
+}
+
+type WebhookQuotesStoredData struct {
+	Quotes []WebhookQuoteData `json:"quotes"`
+}
+
+// PublishWebhookEventUseCase encola una entrega del evento por cada
+// suscripción interesada. El envío lo hace DispatchWebhooksUseCase.
+type PublishWebhookEventUseCase struct {
+	Subscriptions domain.WebhookSubscriptionRepository
+	Deliveries    domain.WebhookDeliveryRepository
+	Opaque        domain.OpaqueTokenService
+	Now           func() time.Time
+}
+
+// Execute publica el evento a las suscripciones de userID (0 = de todos los
+// usuarios) y devuelve cuántas entregas encoló.
+func (uc PublishWebhookEventUseCase) Execute(ctx context.Context, event string, userID int64, data any) (int, error) {
+	subs, err := uc.Subscriptions.ListByEvent(ctx, event, userID)
+	if err != nil || len(subs) == 0 {
+		return 0, err
+	}
+
+	now := uc.Now
+	if now == nil {
+		now = time.Now
+	}
+	t := now().UTC()
+
+	id, err := uc.Opaque.Generate()
+	if err != nil {
+		return 0, err
+	}
+
+	payload, err := json.Marshal(webhookEnvelope{ID: "evt_" + id, Type: event, CreatedAt: t, Data: data})
+	if err != nil {
+		return 0, err
+	}
+
+	enqueued := 0
+	for _, s := range subs {
+		_, err := uc.Deliveries.Enqueue(ctx, domain.WebhookDelivery{
+			SubscriptionID: s.ID,
+			EventID:        "evt_" + id,
+			EventType:      event,
+			Payload:        string(payload),
+			Status:         domain.WebhookDeliveryPending,
+			NextAttemptAt:  t,
+			CreatedAt:      t,
+		})
+		if err != nil {
+			return enqueued, err
+		}
+		enqueued++
+	}
+	return enqueued, nil
+}
+
+type DispatchWebhooksOutput struct {
+	Attempted int `json:"attempted"`
+	Delivered int `json:"delivered"`
+	Retrying  int `json:"retrying"`
+	Dead      int `json:"dead"`
+	Failed    int `json:"failed"`
+}
+
+// DispatchWebhooksUseCase envía las entregas pendientes que ya vencieron.
+// Cada intento fallido reprograma la entrega con backoff exponencial
+// (BaseBackoff, 2x, 4x, ... hasta MaxBackoff); al llegar a MaxAttempts queda
+// dead hasta que el usuario la reencole.
+type DispatchWebhooksUseCase struct {
+	Subscriptions domain.WebhookSubscriptionRepository
+	Deliveries    domain.WebhookDeliveryRepository
+	Sender        domain.WebhookSender
+	Now           func() time.Time
+
+	MaxAttempts int
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+	BatchSize   int
+
+	// cuánto queda reservada una entrega mientras se envía; tiene que superar
+	// el timeout del sender
+	Lease time.Duration
+}
+
+func (uc DispatchWebhooksUseCase) Execute(ctx context.Context) (DispatchWebhooksOutput, error) {
+	now := uc.Now
+	if now == nil {
+		now = time.Now
+	}
+	batch := uc.BatchSize
+	if batch <= 0 {
+		batch = defaultWebhookBatchSize
+	}
+	lease := uc.Lease
+	if lease <= 0 {
+		lease = defaultWebhookLease
+	}
+
+	due, err := uc.Deliveries.ListDue(ctx, now().UTC(), batch)
+	if err != nil {
+		return DispatchWebhooksOutput{}, err
+	}
+
+	var out DispatchWebhooksOutput
+	for _, d := range due {
+		claimed, err := uc.Deliveries.Claim(ctx, d.ID, d.Attempts, now().UTC().Add(lease))
+		if err != nil {
+			log.Printf("Warning: webhook delivery %d: claim: %v", d.ID, err)
+			out.Failed++
+			continue
+		}
+		if !claimed {
+			// otro dispatcher la tomó
+			continue
+		}
+		d.Attempts++
+
+		sub, err := uc.Subscriptions.FindByID(ctx, d.SubscriptionID)
+		if err != nil {
+			log.Printf("Warning: webhook delivery %d: subscription: %v", d.ID, err)
+			out.Failed++
+			continue
+		}
+		if sub == nil {
+			// la suscripción se borró (y con ella la entrega) mientras tanto
+			continue
+		}
+
+		out.Attempted++
+		uc.attempt(ctx, *sub, &d)
+
+		if err := uc.Deliveries.RecordAttempt(ctx, d); err != nil {
+			log.Printf("Warning: webhook delivery %d: record attempt: %v", d.ID, err)
+			out.Failed++
+			continue
+		}
+
+		switch d.Status {
+		case domain.WebhookDeliveryDelivered:
+			out.Delivered++
+		case domain.WebhookDeliveryDead:
+			out.Dead++
+		default:
+			out.Retrying++
+		}
+	}
+	return out, nil
+}
+
+// attempt envía la entrega y deja en d el resultado del intento.
+func (uc DispatchWebhooksUseCase) attempt(ctx context.Context, sub domain.WebhookSubscription, d *domain.WebhookDelivery) {
+	now := uc.Now
+	if now == nil {
+		now = time.Now
+	}
+	t := now().UTC()
+
+	body := []byte(d.Payload)
+	headers := map[string]string{
+		"Content-Type":         "application/json",
+		WebhookIDHeader:        d.EventID,
+		WebhookEventHeader:     d.EventType,
+		WebhookDeliveryHeader:  strconv.FormatInt(d.ID, 10),
+		WebhookSignatureHeader: SignWebhookPayload(sub.Secret, t.Unix(), body),
+	}
+
+	code, err := uc.Sender.Send(ctx, sub.URL, headers, body)
+
+	d.LastAttemptAt = &t
+	d.LastStatusCode = code
+	d.LastError = ""
+
+	if err == nil && code >= 200 && code < 300 {
+		d.Status = domain.WebhookDeliveryDelivered
+		d.DeliveredAt = &t
+		return
+	}
+
+	if err != nil {
+		d.LastError = err.Error()
+	} else {
+		d.LastError = fmt.Sprintf("unexpected status %d", code)
+	}
+	if len(d.LastError) > maxWebhookErrorLength {
+		d.LastError = d.LastError[:maxWebhookErrorLength]
+	}
+
+	maxAttempts := uc.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = defaultWebhookMaxAttempts
+	}
+	if d.Attempts >= maxAttempts {
+		d.Status = domain.WebhookDeliveryDead
+		return
+	}
+	d.Status = domain.WebhookDeliveryPending
+	d.NextAttemptAt = t.Add(uc.backoff(d.Attempts))
+}
+
+// backoff es la espera después del intento número attempt (1-based).
+func (uc DispatchWebhooksUseCase) backoff(attempt int) time.Duration {
+	base := uc.BaseBackoff
+	if base <= 0 {
+		base = defaultWebhookBaseBackoff
+	}
+	max := uc.MaxBackoff
+	if max <= 0 {
+		max = defaultWebhookMaxBackoff
+	}
+
+	wait := base
+	for i := 1; i < attempt; i++ {
+		wait *= 2
+		if wait >= max {
+			return max
+		}
+	}
+	if wait > max {
+		return max
+	}
+	return wait
+}
@@ -0,0 +1,330 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/moondolphin/crypto-api/domain"
+)
+
+var (
+	ErrInvalidWebhookURL        = errors.New("invalid_webhook_url")
+	ErrInvalidWebhookEvents     = errors.New("invalid_webhook_events")
+	ErrWebhookNotFound          = errors.New("webhook_not_found")
+	ErrWebhookLimitReached      = errors.New("webhook_limit_reached")
+	ErrWebhookDeliveryNotFound  = errors.New("webhook_delivery_not_found")
+	ErrWebhookDeliveryNotFailed = errors.New("webhook_delivery_not_failed")
+)
+
+// webhookSecretPrefix identifica los secretos de firma de esta API.
+const webhookSecretPrefix = "whsec_"
+
+const (
+	defaultMaxWebhooksPerUser = 10
+
+	maxWebhookURLLength = 2048
+
+	defaultWebhookDeliveriesLimit = 50
+	maxWebhookDeliveriesLimit     = 200
+)
+
+type CreateWebhookInput struct {
+	URL    string   `json:"url"`
+	Events []string `json:"events"`
+}
+
+type WebhookOutput struct {
+	ID        int64     `json:"id"`
+	URL       string    `json:"url"`
+	Events    []string  `json:"events"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// CreatedWebhookOutput es la única respuesta que incluye el secreto de firma.
+type CreatedWebhookOutput struct {
+	WebhookOutput
+	Secret string `json:"secret"`
+}
+
+type WebhookDeliveryOutput struct {
+	ID             int64           `json:"id"`
+	EventID        string          `json:"event_id"`
+	Event          string          `json:"event"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	NextAttemptAt  *time.Time      `json:"next_attempt_at,omitempty"`
+	LastAttemptAt  *time.Time      `json:"last_attempt_at,omitempty"`
+	LastStatusCode int             `json:"last_status_code,omitempty"`
+	LastError      string          `json:"last_error,omitempty"`
+	DeliveredAt    *time.Time      `json:"delivered_at,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+	Payload        json.RawMessage `json:"payload"`
+}
+
+type ReplayWebhookDeliveriesOutput struct {
+	Requeued int64 `json:"requeued"`
+}
+
+func toWebhookOutput(s domain.WebhookSubscription) WebhookOutput {
+	return WebhookOutput{
+		ID:        s.ID,
+		URL:       s.URL,
+		Events:    s.Events,
+		CreatedAt: s.CreatedAt,
+	}
+}
+
+func toWebhookDeliveryOutput(d domain.WebhookDelivery) WebhookDeliveryOutput {
+	out := WebhookDeliveryOutput{
+		ID:             d.ID,
+		EventID:        d.EventID,
+		Event:          d.EventType,
+		Status:         d.Status,
+		Attempts:       d.Attempts,
+		LastAttemptAt:  d.LastAttemptAt,
+		LastStatusCode: d.LastStatusCode,
+		LastError:      d.LastError,
+		DeliveredAt:    d.DeliveredAt,
+		CreatedAt:      d.CreatedAt,
+		Payload:        json.RawMessage(d.Payload),
+	}
+	if d.Status == domain.WebhookDeliveryPending {
+		next := d.NextAttemptAt
+		out.NextAttemptAt = &next
+	}
+	return out
+}
+
+// validateWebhookURL exige https salvo allowInsecure (desarrollo local).
+// Los destinos en redes privadas se rechazan al conectar, en el sender.
+func validateWebhookURL(raw string, allowInsecure bool) (string, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" || len(raw) > maxWebhookURLLength {
+		return "", ErrInvalidWebhookURL
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" || u.Hostname() == "" || u.User != nil || u.Fragment != "" {
+		return "", ErrInvalidWebhookURL
+	}
+	switch u.Scheme {
+	case "https":
+	case "http":
+		if !allowInsecure {
+			return "", ErrInvalidWebhookURL
+		}
+	default:
+		return "", ErrInvalidWebhookURL
+	}
+	return u.String(), nil
+}
+
+// normalizeWebhookEvents valida, deduplica y ordena.
+func normalizeWebhookEvents(in []string) ([]string, error) {
+	seen := make(map[string]bool, len(in))
+	out := make([]string, 0, len(in))
+	for _, e := range in {
+		e = strings.ToLower(strings.TrimSpace(e))
+		if !domain.IsValidWebhookEvent(e) {
+			return nil, ErrInvalidWebhookEvents
+		}
+		if !seen[e] {
+			seen[e] = true
+			out = append(out, e)
+		}
+	}
+	if len(out) == 0 {
+		return nil, ErrInvalidWebhookEvents
+	}
+	sort.Strings(out)
+	return out, nil
+}
+
+type CreateWebhookUseCase struct {
+	Subscriptions domain.WebhookSubscriptionRepository
+	Opaque        domain.OpaqueTokenService
+	Now           func() time.Time
+
+	MaxPerUser int
+
+	// acepta URLs http:// (sólo para desarrollo)
+	AllowInsecure bool
+}
+
+func (uc CreateWebhookUseCase) Execute(ctx context.Context, userID int64, in CreateWebhookInput) (CreatedWebhookOutput, error) {
+	target, err := validateWebhookURL(in.URL, uc.AllowInsecure)
+	if err != nil {
+		return CreatedWebhookOutput{}, err
+	}
+	events, err := normalizeWebhookEvents(in.Events)
+	if err != nil {
+		return CreatedWebhookOutput{}, err
+	}
+
+	limit := uc.MaxPerUser
+	if limit <= 0 {
+		limit = defaultMaxWebhooksPerUser
+	}
+	n, err := uc.Subscriptions.CountByUser(ctx, userID)
+	if err != nil {
+		return CreatedWebhookOutput{}, err
+	}
+	if n >= limit {
+		return CreatedWebhookOutput{}, ErrWebhookLimitReached
+	}
+
+	secret, err := uc.Opaque.Generate()
+	if err != nil {
+		return CreatedWebhookOutput{}, err
+	}
+
+	now := uc.Now
+	if now == nil {
+		now = time.Now
+	}
+
+	s, err := uc.Subscriptions.Create(ctx, domain.WebhookSubscription{
+		UserID:    userID,
+		URL:       target,
+		Events:    events,
+		Secret:    webhookSecretPrefix + secret,
+		CreatedAt: now().UTC(),
+	})
+	if err != nil {
+		return CreatedWebhookOutput{}, err
+	}
+
+	return CreatedWebhookOutput{WebhookOutput: toWebhookOutput(s), Secret: s.Secret}, nil
+}
+
+type ListWebhooksUseCase struct {
+	Subscriptions domain.WebhookSubscriptionRepository
+}
+
+func (uc ListWebhooksUseCase) Execute(ctx context.Context, userID int64) ([]WebhookOutput, error) {
+	subs, err := uc.Subscriptions.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]WebhookOutput, 0, len(subs))
+	for _, s := range subs {
+		out = append(out, toWebhookOutput(s))
+	}
+	return out, nil
+}
+
+// findWebhook busca la suscripción del usuario: nil -> ErrWebhookNotFound.
+func findWebhook(ctx context.Context, subs domain.WebhookSubscriptionRepository, userID, id int64) (*domain.WebhookSubscription, error) {
+	if id <= 0 {
+		return nil, ErrBadRequest
+	}
+	s, err := subs.FindByUser(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if s == nil {
+		return nil, ErrWebhookNotFound
+	}
+	return s, nil
+}
+
+type DeleteWebhookUseCase struct {
+	Subscriptions domain.WebhookSubscriptionRepository
+}
+
+func (uc DeleteWebhookUseCase) Execute(ctx context.Context, userID, id int64) error {
+	if id <= 0 {
+		return ErrBadRequest
+	}
+
+	ok, err := uc.Subscriptions.Delete(ctx, userID, id)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrWebhookNotFound
+	}
+	return nil
+}
+
+type ListWebhookDeliveriesUseCase struct {
+	Subscriptions domain.WebhookSubscriptionRepository
+	Deliveries    domain.WebhookDeliveryRepository
+}
+
+// Execute lista las entregas de una suscripción del usuario; status="" = todas.
+func (uc ListWebhookDeliveriesUseCase) Execute(ctx context.Context, userID, webhookID int64, status string, limit int) ([]WebhookDeliveryOutput, error) {
+	status = strings.ToLower(strings.TrimSpace(status))
+	if status != "" && !domain.IsValidWebhookDeliveryStatus(status) {
+		return nil, ErrBadRequest
+	}
+
+	if _, err := findWebhook(ctx, uc.Subscriptions, userID, webhookID); err != nil {
+		return nil, err
+	}
+
+	if limit <= 0 {
+		limit = defaultWebhookDeliveriesLimit
+	}
+	if limit > maxWebhookDeliveriesLimit {
+		limit = maxWebhookDeliveriesLimit
+	}
+
+	deliveries, err := uc.Deliveries.ListBySubscription(ctx, webhookID, status, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]WebhookDeliveryOutput, 0, len(deliveries))
+	for _, d := range deliveries {
+		out = append(out, toWebhookDeliveryOutput(d))
+	}
+	return out, nil
+}
+
+type ReplayWebhookDeliveriesUseCase struct {
+	Subscriptions domain.WebhookSubscriptionRepository
+	Deliveries    domain.WebhookDeliveryRepository
+	Now           func() time.Time
+}
+
+// Execute vuelve a encolar las entregas fallidas (dead) de la suscripción:
+// una sola si deliveryID > 0, todas si no. El payload no cambia.
+func (uc ReplayWebhookDeliveriesUseCase) Execute(ctx context.Context, userID, webhookID, deliveryID int64) (ReplayWebhookDeliveriesOutput, error) {
+	if deliveryID < 0 {
+		return ReplayWebhookDeliveriesOutput{}, ErrBadRequest
+	}
+	if _, err := findWebhook(ctx, uc.Subscriptions, userID, webhookID); err != nil {
+		return ReplayWebhookDeliveriesOutput{}, err
+	}
+
+	if deliveryID > 0 {
+		d, err := uc.Deliveries.FindBySubscription(ctx, webhookID, deliveryID)
+		if err != nil {
+			return ReplayWebhookDeliveriesOutput{}, err
+		}
+		if d == nil {
+			return ReplayWebhookDeliveriesOutput{}, ErrWebhookDeliveryNotFound
+		}
+		if d.Status != domain.WebhookDeliveryDead {
+			return ReplayWebhookDeliveriesOutput{}, ErrWebhookDeliveryNotFailed
+		}
+	}
+
+	now := uc.Now
+	if now == nil {
+		now = time.Now
+	}
+
+	n, err := uc.Deliveries.Replay(ctx, webhookID, deliveryID, now().UTC())
+	if err != nil {
+		return ReplayWebhookDeliveriesOutput{}, err
+	}
+	return ReplayWebhookDeliveriesOutput{Requeued: n}, nil
+}
@@ -0,0 +1,668 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/moondolphin/crypto-api/domain"
+)
+
+var (
+	ErrInvalidPortfolioName     = errors.New("invalid_portfolio_name")
+	ErrInvalidPortfolioCurrency = errors.New("invalid_portfolio_currency")
+	ErrInvalidQuantity          = errors.New("invalid_quantity")
+	ErrPortfolioNotFound        = errors.New("portfolio_not_found")
+	ErrPortfolioNameTaken       = errors.New("portfolio_name_taken")
+	ErrPortfolioLimitReached    = errors.New("portfolio_limit_reached")
+	ErrHoldingNotFound          = errors.New("holding_not_found")
+)
+
+const (
+	defaultMaxPortfoliosPerUser = 20
+	defaultPortfolioCurrency    = "USD"
+	defaultStalePriceAfter      = 2 * time.Hour
+
+	maxPortfolioNameLength = 100
+	maxCurrencyLength      = 10
+
+	// escala de las columnas de cantidad: DECIMAL(38,18)
+	quantityScale = 18
+
+	// decimales con que se informan valores y precios calculados
+	valueScale = 8
+)
+
+// quantityPattern: decimal plano, sin signo ni exponente, que entra en
+// DECIMAL(38,18).
+var quantityPattern = regexp.MustCompile(`^[0-9]{1,20}(\.[0-9]{1,18})?$`)
+
+// parseQuantity valida una cantidad positiva y la devuelve normalizada.
+func parseQuantity(raw string) (*big.Rat, string, error) {
+	raw = strings.TrimSpace(raw)
+	if !quantityPattern.MatchString(raw) {
+		return nil, "", ErrInvalidQuantity
+	}
+	q, ok := new(big.Rat).SetString(raw)
+	if !ok || q.Sign() <= 0 {
+		return nil, "", ErrInvalidQuantity
+	}
+	return q, formatDecimal(q, quantityScale), nil
+}
+
+// parseDecimal lee un decimal guardado (cantidades, precios); acepta los
+// ceros de relleno de DECIMAL/NUMERIC.
+func parseDecimal(raw string) (*big.Rat, bool) {
+	return new(big.Rat).SetString(strings.TrimSpace(raw))
+}
+
+// formatDecimal redondea a scale decimales y saca los ceros sobrantes.
+func formatDecimal(r *big.Rat, scale int) string {
+	s := r.FloatString(scale)
+	if strings.Contains(s, ".") {
+		s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	}
+	if s == "-0" {
+		return "0"
+	}
+	return s
+}
+
+// normalizeQuantity limpia el texto que viene del repo; si no se puede leer
+// lo devuelve como está.
+func normalizeQuantity(raw string) string {
+	q, ok := parseDecimal(raw)
+	if !ok {
+		return raw
+	}
+	return formatDecimal(q, quantityScale)
+}
+
+// parseQuoteTimestamp interpreta el Timestamp de domain.PriceQuote: RFC3339
+// en la mayoría de los adapters, "2006-01-02 15:04:05" (UTC) en MySQL.
+func parseQuoteTimestamp(s string) (time.Time, bool) {
+	if t, err := time.Parse(time.RFC3339Nano, s); err == nil {
+		return t.UTC(), true
+	}
+	if t, err := time.ParseInLocation("2006-01-02 15:04:05", s, time.UTC); err == nil {
+		return t, true
+	}
+	return time.Time{}, false
+}
+
+func normalizePortfolioName(raw string) (string, error) {
+	name := strings.TrimSpace(raw)
+	if name == "" || utf8.RuneCountInString(name) > maxPortfolioNameLength {
+		return "", ErrInvalidPortfolioName
+	}
+	return name, nil
+}
+
+// normalizeCurrency pasa a mayúsculas; vacío -> def.
+func normalizeCurrency(raw, def string) (string, error) {
+	c := strings.ToUpper(strings.TrimSpace(raw))
+	if c == "" {
+		c = def
+	}
+	if c == "" || len(c) > maxCurrencyLength {
+		return "", ErrInvalidPortfolioCurrency
+	}
+	for _, r := range c {
+		if (r < 'A' || r > 'Z') && (r < '0' || r > '9') {
+			return "", ErrInvalidPortfolioCurrency
+		}
+	}
+	return c, nil
+}
+
+type CreatePortfolioInput struct {
+	Name         string `json:"name"`
+	BaseCurrency string `json:"base_currency,omitempty"`
+}
+
+// UpdatePortfolioInput sólo cambia los campos presentes.
+type UpdatePortfolioInput struct {
+	Name         *string `json:"name,omitempty"`
+	BaseCurrency *string `json:"base_currency,omitempty"`
+}
+
+// SetHoldingInput acepta la cantidad como número o como string JSON; conviene
+// string para no perder precisión en el cliente.
+type SetHoldingInput struct {
+	Quantity json.Number `json:"quantity"`
+}
+
+type PortfolioOutput struct {
+	ID           int64     `json:"id"`
+	Name         string    `json:"name"`
+	BaseCurrency string    `json:"base_currency"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+type HoldingOutput struct {
+	Symbol    string    `json:"symbol"`
+	Quantity  string    `json:"quantity"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type PortfolioDetailOutput struct {
+	PortfolioOutput
+	Holdings []HoldingOutput `json:"holdings"`
+}
+
+func toPortfolioOutput(p domain.Portfolio) PortfolioOutput {
+	return PortfolioOutput{
+		ID:           p.ID,
+		Name:         p.Name,
+		BaseCurrency: p.BaseCurrency,
+		CreatedAt:    p.CreatedAt,
+		UpdatedAt:    p.UpdatedAt,
+	}
+}
+
+func toHoldingOutput(h domain.Holding) HoldingOutput {
+	return HoldingOutput{
+		Symbol:    h.Symbol,
+		Quantity:  normalizeQuantity(h.Quantity),
+		UpdatedAt: h.UpdatedAt,
+	}
+}
+
+// findPortfolio busca el portfolio del usuario: nil -> ErrPortfolioNotFound.
+func findPortfolio(ctx context.Context, portfolios domain.PortfolioRepository, userID, id int64) (*domain.Portfolio, error) {
+	if id <= 0 {
+		return nil, ErrBadRequest
+	}
+	p, err := portfolios.FindByUser(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if p == nil {
+		return nil, ErrPortfolioNotFound
+	}
+	return p, nil
+}
+
+// portfolioNameTaken compara sin distinguir mayúsculas; exceptID excluye al
+// propio portfolio en un rename.
+func portfolioNameTaken(ctx context.Context, portfolios domain.PortfolioRepository, userID, exceptID int64, name string) (bool, error) {
+	list, err := portfolios.ListByUser(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	for _, p := range list {
+		if p.ID != exceptID && strings.EqualFold(p.Name, name) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+type CreatePortfolioUseCase struct {
+	Portfolios domain.PortfolioRepository
+	Now        func() time.Time
+
+	MaxPerUser int
+}
+
+func (uc CreatePortfolioUseCase) Execute(ctx context.Context, userID int64, in CreatePortfolioInput) (PortfolioOutput, error) {
+	name, err := normalizePortfolioName(in.Name)
+	if err != nil {
+		return PortfolioOutput{}, err
+	}
+	currency, err := normalizeCurrency(in.BaseCurrency, defaultPortfolioCurrency)
+	if err != nil {
+		return PortfolioOutput{}, err
+	}
+
+	limit := uc.MaxPerUser
+	if limit <= 0 {
+		limit = defaultMaxPortfoliosPerUser
+	}
+	n, err := uc.Portfolios.CountByUser(ctx, userID)
+	if err != nil {
+		return PortfolioOutput{}, err
+	}
+	if n >= limit {
+		return PortfolioOutput{}, ErrPortfolioLimitReached
+	}
+
+	taken, err := portfolioNameTaken(ctx, uc.Portfolios, userID, 0, name)
+	if err != nil {
+		return PortfolioOutput{}, err
+	}
+	if taken {
+		return PortfolioOutput{}, ErrPortfolioNameTaken
+	}
+
+	now := uc.Now
+	if now == nil {
+		now = time.Now
+	}
+	t := now().UTC()
+
+	p, err := uc.Portfolios.Create(ctx, domain.Portfolio{
+		UserID:       userID,
+		Name:         name,
+		BaseCurrency: currency,
+		CreatedAt:    t,
+		UpdatedAt:    t,
+	})
+	if err != nil {
+		return PortfolioOutput{}, err
+	}
+	return toPortfolioOutput(p), nil
+}
+
+type ListPortfoliosUseCase struct {
+	Portfolios domain.PortfolioRepository
+}
+
+func (uc ListPortfoliosUseCase) Execute(ctx context.Context, userID int64) ([]PortfolioOutput, error) {
+	list, err := uc.Portfolios.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]PortfolioOutput, 0, len(list))
+	for _, p := range list {
+		out = append(out, toPortfolioOutput(p))
+	}
+	return out, nil
+}
+
+type GetPortfolioUseCase struct {
+	Portfolios domain.PortfolioRepository
+	Holdings   domain.HoldingRepository
+}
+
+func (uc GetPortfolioUseCase) Execute(ctx context.Context, userID, id int64) (PortfolioDetailOutput, error) {
+	p, err := findPortfolio(ctx, uc.Portfolios, userID, id)
+	if err != nil {
+		return PortfolioDetailOutput{}, err
+	}
+
+	holdings, err := uc.Holdings.ListByPortfolio(ctx, p.ID)
+	if err != nil {
+		return PortfolioDetailOutput{}, err
+	}
+
+	out := PortfolioDetailOutput{
+		PortfolioOutput: toPortfolioOutput(*p),
+		Holdings:        make([]HoldingOutput, 0, len(holdings)),
+	}
+	for _, h := range holdings {
+		out.Holdings = append(out.Holdings, toHoldingOutput(h))
+	}
+	return out, nil
+}
+
+type UpdatePortfolioUseCase struct {
+	Portfolios domain.PortfolioRepository
+	Now        func() time.Time
+}
+
+func (uc UpdatePortfolioUseCase) Execute(ctx context.Context, userID, id int64, in UpdatePortfolioInput) (PortfolioOutput, error) {
+	if in.Name == nil && in.BaseCurrency == nil {
+		return PortfolioOutput{}, ErrBadRequest
+	}
+
+	p, err := findPortfolio(ctx, uc.Portfolios, userID, id)
+	if err != nil {
+		return PortfolioOutput{}, err
+	}
+
+	if in.Name != nil {
+		name, err := normalizePortfolioName(*in.Name)
+		if err != nil {
+			return PortfolioOutput{}, err
+		}
+		taken, err := portfolioNameTaken(ctx, uc.Portfolios, userID, p.ID, name)
+		if err != nil {
+			return PortfolioOutput{}, err
+		}
+		if taken {
+			return PortfolioOutput{}, ErrPortfolioNameTaken
+		}
+		p.Name = name
+	}
+	if in.BaseCurrency != nil {
+		// acá vacío no vale: no hay a qué volver por defecto
+		currency, err := normalizeCurrency(*in.BaseCurrency, "")
+		if err != nil {
+			return PortfolioOutput{}, err
+		}
+		p.BaseCurrency = currency
+	}
+
+	now := uc.Now
+	if now == nil {
+		now = time.Now
+	}
+	p.UpdatedAt = now().UTC()
+
+	ok, err := uc.Portfolios.Update(ctx, *p)
+	if err != nil {
+		return PortfolioOutput{}, err
+	}
+	if !ok {
+		return PortfolioOutput{}, ErrPortfolioNotFound
+	}
+	return toPortfolioOutput(*p), nil
+}
+
+type DeletePortfolioUseCase struct {
+	Portfolios domain.PortfolioRepository
+}
+
+// Execute borra el portfolio; las tenencias se van en cascada.
+func (uc DeletePortfolioUseCase) Execute(ctx context.Context, userID, id int64) error {
+	if id <= 0 {
+		return ErrBadRequest
+	}
+
+	ok, err := uc.Portfolios.Delete(ctx, userID, id)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrPortfolioNotFound
+	}
+	return nil
+}
+
+type SetHoldingUseCase struct {
+	Portfolios domain.PortfolioRepository
+	Holdings   domain.HoldingRepository
+	CoinRepo   domain.CoinRepository
+	Snapshots  domain.PortfolioSnapshotRepository // opcional
+	Now        func() time.Time
+}
+
+// Execute fija la cantidad que el portfolio tiene de symbol (crea la tenencia
+// o la pisa). La coin tiene que estar habilitada.
+func (uc SetHoldingUseCase) Execute(ctx context.Context, userID, portfolioID int64, symbol string, in SetHoldingInput) (HoldingOutput, error) {
+	symbol = strings.ToUpper(strings.TrimSpace(symbol))
+	if symbol == "" {
+		return HoldingOutput{}, ErrBadRequest
+	}
+	_, quantity, err := parseQuantity(in.Quantity.String())
+	if err != nil {
+		return HoldingOutput{}, err
+	}
+
+	p, err := findPortfolio(ctx, uc.Portfolios, userID, portfolioID)
+	if err != nil {
+		return HoldingOutput{}, err
+	}
+
+	coin, err := uc.CoinRepo.GetEnabledBySymbol(ctx, symbol)
+	if err != nil {
+		return HoldingOutput{}, err
+	}
+	if coin == nil {
+		return HoldingOutput{}, ErrCoinNotEnabled
+	}
+
+	now := uc.Now
+	if now == nil {
+		now = time.Now
+	}
+	t := now().UTC()
+
+	// las tenencias no tienen fecha: valen para todo el historial
+	if err := invalidateSnapshots(ctx, uc.Snapshots, p.ID, time.Time{}); err != nil {
+		return HoldingOutput{}, err
+	}
+
+	h, err := uc.Holdings.Upsert(ctx, domain.Holding{
+		PortfolioID: p.ID,
+		CoinID:      coin.ID,
+		Symbol:      coin.Symbol,
+		Quantity:    quantity,
+		CreatedAt:   t,
+		UpdatedAt:   t,
+	})
+	if err != nil {
+		return HoldingOutput{}, err
+	}
+	return toHoldingOutput(h), nil
+}
+
+type DeleteHoldingUseCase struct {
+	Portfolios domain.PortfolioRepository
+	Holdings   domain.HoldingRepository
+	CoinRepo   domain.CoinRepository
+	Snapshots  domain.PortfolioSnapshotRepository // opcional
+}
+
+func (uc DeleteHoldingUseCase) Execute(ctx context.Context, userID, portfolioID int64, symbol string) error {
+	symbol = strings.ToUpper(strings.TrimSpace(symbol))
+	if symbol == "" {
+		return ErrBadRequest
+	}
+
+	p, err := findPortfolio(ctx, uc.Portfolios, userID, portfolioID)
+	if err != nil {
+		return err
+	}
+
+	// GetBySymbol (no sólo habilitadas): se puede sacar una coin que se
+	// deshabilitó después de cargarla
+	coin, err := uc.CoinRepo.GetBySymbol(ctx, symbol)
+	if err != nil {
+		return err
+	}
+	if coin == nil {
+		return ErrHoldingNotFound
+	}
+
+	if err := invalidateSnapshots(ctx, uc.Snapshots, p.ID, time.Time{}); err != nil {
+		return err
+	}
+
+	ok, err := uc.Holdings.Delete(ctx, p.ID, coin.ID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrHoldingNotFound
+	}
+	return nil
+}
+
+type AssetValuationOutput struct {
+	Symbol   string `json:"symbol"`
+	Quantity string `json:"quantity"`
+
+	// vacíos si no hay cotización guardada en la moneda pedida
+	Price             string     `json:"price,omitempty"`
+	Value             string     `json:"value,omitempty"`
+	AllocationPercent *float64   `json:"allocation_percent,omitempty"`
+	Provider          string     `json:"provider,omitempty"`
+	QuotedAt          *time.Time `json:"quoted_at,omitempty"`
+	AgeSeconds        *int64     `json:"age_seconds,omitempty"`
+
+	// el precio usado es más viejo que el umbral de frescura
+	Stale bool `json:"stale"`
+
+	PriceMissing bool `json:"price_missing,omitempty"`
+}
+
+type PortfolioValuationOutput struct {
+	PortfolioID int64     `json:"portfolio_id"`
+	Currency    string    `json:"currency"`
+	TotalValue  string    `json:"total_value"`
+	ValuedAt    time.Time `json:"valued_at"`
+
+	// umbral usado para marcar precios viejos
+	StaleAfterSeconds int64 `json:"stale_after_seconds"`
+
+	// activos con precio viejo y sin precio: si alguno es > 0 el total es
+	// aproximado o parcial
+	StaleCount   int `json:"stale_count"`
+	MissingCount int `json:"missing_count"`
+
+	Assets []AssetValuationOutput `json:"assets"`
+}
+
+// ValuePortfolioUseCase valúa las tenencias con la última cotización guardada
+// de cada coin (no consulta a los providers). Los activos sin cotización en la
+// moneda pedida se listan pero no suman al total ni a la distribución.
+type ValuePortfolioUseCase struct {
+	Portfolios domain.PortfolioRepository
+	Holdings   domain.HoldingRepository
+	QuoteRepo  domain.QuoteRepository
+	Now        func() time.Time
+
+	// antigüedad a partir de la cual un precio se marca stale
+	StaleAfter time.Duration
+}
+
+// Execute valúa en currency; vacío = moneda base del portfolio.
+func (uc ValuePortfolioUseCase) Execute(ctx context.Context, userID, portfolioID int64, currency string) (PortfolioValuationOutput, error) {
+	p, err := findPortfolio(ctx, uc.Portfolios, userID, portfolioID)
+	if err != nil {
+		return PortfolioValuationOutput{}, err
+	}
+	currency, err = normalizeCurrency(currency, p.BaseCurrency)
+	if err != nil {
+		return PortfolioValuationOutput{}, err
+	}
+
+	holdings, err := uc.Holdings.ListByPortfolio(ctx, p.ID)
+	if err != nil {
+		return PortfolioValuationOutput{}, err
+	}
+
+	now := uc.Now
+	if now == nil {
+		now = time.Now
+	}
+	t := now().UTC()
+
+	staleAfter := uc.StaleAfter
+	if staleAfter <= 0 {
+		staleAfter = defaultStalePriceAfter
+	}
+
+	out := PortfolioValuationOutput{
+		PortfolioID:       p.ID,
+		Currency:          currency,
+		ValuedAt:          t,
+		StaleAfterSeconds: int64(staleAfter / time.Second),
+		Assets:            make([]AssetValuationOutput, 0, len(holdings)),
+	}
+
+	total := new(big.Rat)
+	values := make([]*big.Rat, len(holdings))
+
+	for i, h := range holdings {
+		asset := AssetValuationOutput{Symbol: h.Symbol, Quantity: normalizeQuantity(h.Quantity)}
+
+		qty, okQty := parseDecimal(h.Quantity)
+		lp, err := latestPrice(ctx, uc.QuoteRepo, h.Symbol, currency, t, staleAfter)
+		if err != nil {
+			return PortfolioValuationOutput{}, err
+		}
+		if lp == nil || !okQty {
+			asset.PriceMissing = true
+			out.MissingCount++
+			out.Assets = append(out.Assets, asset)
+			continue
+		}
+
+		value := new(big.Rat).Mul(qty, lp.price)
+		values[i] = value
+		total.Add(total, value)
+
+		asset.Price = formatDecimal(lp.price, valueScale)
+		asset.Value = formatDecimal(value, valueScale)
+		asset.Provider = lp.provider
+		asset.QuotedAt = lp.quotedAt
+		asset.AgeSeconds = lp.ageSeconds
+		asset.Stale = lp.stale
+		if asset.Stale {
+			out.StaleCount++
+		}
+		out.Assets = append(out.Assets, asset)
+	}
+
+	out.TotalValue = formatDecimal(total, valueScale)
+
+	if total.Sign() > 0 {
+		for i := range out.Assets {
+			if values[i] == nil {
+				continue
+			}
+			pct := allocationPercent(values[i], total)
+			out.Assets[i].AllocationPercent = &pct
+		}
+	}
+
+	// primero los de mayor valor; los sin precio al final, por símbolo
+	sort.SliceStable(out.Assets, func(i, j int) bool {
+		a, b := out.Assets[i], out.Assets[j]
+		if a.PriceMissing != b.PriceMissing {
+			return !a.PriceMissing
+		}
+		if a.AllocationPercent != nil && b.AllocationPercent != nil && *a.AllocationPercent != *b.AllocationPercent {
+			return *a.AllocationPercent > *b.AllocationPercent
+		}
+		return a.Symbol < b.Symbol
+	})
+	return out, nil
+}
+
+// quotedPrice es la última cotización guardada de una coin, ya interpretada.
+type quotedPrice struct {
+	price      *big.Rat
+	provider   string
+	quotedAt   *time.Time
+	ageSeconds *int64
+
+	// más vieja que el umbral, o sin fecha legible (no se puede garantizar
+	// que sea reciente)
+	stale bool
+}
+
+// latestPrice busca la última cotización de symbol en currency (de cualquier
+// provider); nil si no hay o el precio no se puede leer.
+func latestPrice(ctx context.Context, quotes domain.QuoteRepository, symbol, currency string, now time.Time, staleAfter time.Duration) (*quotedPrice, error) {
+	pq, err := quotes.GetLatest(ctx, symbol, "", currency)
+	if err != nil || pq == nil {
+		return nil, err
+	}
+	price, ok := parseDecimal(pq.Price)
+	if !ok {
+		return nil, nil
+	}
+
+	out := &quotedPrice{price: price, provider: pq.Provider, stale: true}
+	if quotedAt, ok := parseQuoteTimestamp(pq.Timestamp); ok {
+		age := int64(now.Sub(quotedAt) / time.Second)
+		if age < 0 {
+			age = 0
+		}
+		out.quotedAt = &quotedAt
+		out.ageSeconds = &age
+		out.stale = now.Sub(quotedAt) > staleAfter
+	}
+	return out, nil
+}
+
+// allocationPercent es value/total*100 con 4 decimales.
+func allocationPercent(value, total *big.Rat) float64 {
+	pct := new(big.Rat).Quo(value, total)
+	pct.Mul(pct, big.NewRat(100, 1))
+	f, _ := new(big.Rat).SetString(pct.FloatString(4))
+	v, _ := f.Float64()
+	return v
+}
@@ -0,0 +1,360 @@
+package app_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/moondolphin/crypto-api/app"
+	"github.com/moondolphin/crypto-api/domain"
+	"github.com/moondolphin/crypto-api/test/mocks"
+)
+
+func TestUC26CreatePortfolio_DefaultsCurrencyAndCreates(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	repo := mocks.NewMockPortfolioRepository(ctrl)
+	repo.EXPECT().CountByUser(gomock.Any(), int64(7)).Return(1, nil)
+	repo.EXPECT().ListByUser(gomock.Any(), int64(7)).Return([]domain.Portfolio{{ID: 1, UserID: 7, Name: "Trading"}}, nil)
+	repo.EXPECT().Create(gomock.Any(), domain.Portfolio{
+		UserID:       7,
+		Name:         "Largo plazo",
+		BaseCurrency: "USD",
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}).DoAndReturn(func(_ context.Context, p domain.Portfolio) (domain.Portfolio, error) {
+		p.ID = 2
+		return p, nil
+	})
+
+	uc := app.CreatePortfolioUseCase{Portfolios: repo, Now: func() time.Time { return now }}
+
+	// Act
+	out, err := uc.Execute(context.Background(), 7, app.CreatePortfolioInput{Name: "  Largo plazo "})
+
+	// Assert
+	require.NoError(t, err)
+	require.Equal(t, int64(2), out.ID)
+	require.Equal(t, "USD", out.BaseCurrency)
+}
+
+func TestUC26CreatePortfolio_Rejections(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cases := map[string]struct {
+		in    app.CreatePortfolioInput
+		count int
+		list  []domain.Portfolio
+		want  error
+	}{
+		"empty name":    {in: app.CreatePortfolioInput{Name: "  "}, want: app.ErrInvalidPortfolioName},
+		"bad currency":  {in: app.CreatePortfolioInput{Name: "P", BaseCurrency: "us-d"}, want: app.ErrInvalidPortfolioCurrency},
+		"limit reached": {in: app.CreatePortfolioInput{Name: "P"}, count: 3, want: app.ErrPortfolioLimitReached},
+		"name taken (any case)": {
+			in:   app.CreatePortfolioInput{Name: "trading"},
+			list: []domain.Portfolio{{ID: 1, Name: "Trading"}},
+			want: app.ErrPortfolioNameTaken,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			// Arrange
+			repo := mocks.NewMockPortfolioRepository(ctrl)
+			repo.EXPECT().CountByUser(gomock.Any(), gomock.Any()).Return(tc.count, nil).AnyTimes()
+			repo.EXPECT().ListByUser(gomock.Any(), gomock.Any()).Return(tc.list, nil).AnyTimes()
+
+			uc := app.CreatePortfolioUseCase{Portfolios: repo, MaxPerUser: 3}
+
+			// Act
+			_, err := uc.Execute(context.Background(), 7, tc.in)
+
+			// Assert
+			require.ErrorIs(t, err, tc.want)
+		})
+	}
+}
+
+func TestUC26UpdatePortfolio_RenamesAndChangesCurrency(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	created := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := created.Add(48 * time.Hour)
+	current := domain.Portfolio{ID: 4, UserID: 7, Name: "Viejo", BaseCurrency: "USD", CreatedAt: created, UpdatedAt: created}
+
+	repo := mocks.NewMockPortfolioRepository(ctrl)
+	repo.EXPECT().FindByUser(gomock.Any(), int64(7), int64(4)).Return(&current, nil)
+	// el propio portfolio no cuenta como nombre tomado
+	repo.EXPECT().ListByUser(gomock.Any(), int64(7)).Return([]domain.Portfolio{current}, nil)
+	repo.EXPECT().Update(gomock.Any(), domain.Portfolio{
+		ID: 4, UserID: 7, Name: "viejo", BaseCurrency: "EUR", CreatedAt: created, UpdatedAt: now,
+	}).Return(true, nil)
+
+	uc := app.UpdatePortfolioUseCase{Portfolios: repo, Now: func() time.Time { return now }}
+
+	// Act
+	out, err := uc.Execute(context.Background(), 7, 4, app.UpdatePortfolioInput{Name: strPtr("viejo"), BaseCurrency: strPtr("eur")})
+
+	// Assert
+	require.NoError(t, err)
+	require.Equal(t, "EUR", out.BaseCurrency)
+	require.Equal(t, now, out.UpdatedAt)
+}
+
+func TestUC26UpdatePortfolio_EmptyPatchAndMissing(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockPortfolioRepository(ctrl)
+	repo.EXPECT().FindByUser(gomock.Any(), int64(7), int64(9)).Return(nil, nil)
+	uc := app.UpdatePortfolioUseCase{Portfolios: repo}
+
+	// Act
+	_, errEmpty := uc.Execute(context.Background(), 7, 9, app.UpdatePortfolioInput{})
+	_, errMissing := uc.Execute(context.Background(), 7, 9, app.UpdatePortfolioInput{Name: strPtr("x")})
+
+	// Assert
+	require.ErrorIs(t, errEmpty, app.ErrBadRequest)
+	require.ErrorIs(t, errMissing, app.ErrPortfolioNotFound)
+}
+
+func TestUC26DeletePortfolio_NotFound(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockPortfolioRepository(ctrl)
+	repo.EXPECT().Delete(gomock.Any(), int64(7), int64(3)).Return(false, nil)
+
+	// Act
+	err := app.DeletePortfolioUseCase{Portfolios: repo}.Execute(context.Background(), 7, 3)
+
+	// Assert
+	require.ErrorIs(t, err, app.ErrPortfolioNotFound)
+}
+
+func TestUC26SetHolding_NormalizesQuantityAndUpserts(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	portfolios := mocks.NewMockPortfolioRepository(ctrl)
+	holdings := mocks.NewMockHoldingRepository(ctrl)
+	coins := mocks.NewMockCoinRepository(ctrl)
+
+	portfolios.EXPECT().FindByUser(gomock.Any(), int64(7), int64(4)).Return(&domain.Portfolio{ID: 4, UserID: 7}, nil)
+	coins.EXPECT().GetEnabledBySymbol(gomock.Any(), "BTC").Return(&domain.Coin{ID: 1, Symbol: "BTC", Enabled: true}, nil)
+	holdings.EXPECT().Upsert(gomock.Any(), domain.Holding{
+		PortfolioID: 4, CoinID: 1, Symbol: "BTC", Quantity: "0.5", CreatedAt: now, UpdatedAt: now,
+	}).DoAndReturn(func(_ context.Context, h domain.Holding) (domain.Holding, error) {
+		// MySQL/Postgres devuelven la cantidad con ceros de relleno
+		h.ID = 10
+		h.Quantity = "0.500000000000000000"
+		return h, nil
+	})
+
+	uc := app.SetHoldingUseCase{Portfolios: portfolios, Holdings: holdings, CoinRepo: coins, Now: func() time.Time { return now }}
+
+	// Act
+	out, err := uc.Execute(context.Background(), 7, 4, " btc ", app.SetHoldingInput{Quantity: json.Number("0.50")})
+
+	// Assert
+	require.NoError(t, err)
+	require.Equal(t, app.HoldingOutput{Symbol: "BTC", Quantity: "0.5", UpdatedAt: now}, out)
+}
+
+func TestUC26SetHolding_RejectsInvalidQuantities(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	uc := app.SetHoldingUseCase{
+		Portfolios: mocks.NewMockPortfolioRepository(ctrl),
+		Holdings:   mocks.NewMockHoldingRepository(ctrl),
+		CoinRepo:   mocks.NewMockCoinRepository(ctrl),
+	}
+
+	for _, q := range []string{"", "0", "0.000", "-1", "1e3", "1.", ".5", "abc", "1.0000000000000000001", "123456789012345678901"} {
+		t.Run(q, func(t *testing.T) {
+			// Act
+			_, err := uc.Execute(context.Background(), 7, 4, "BTC", app.SetHoldingInput{Quantity: json.Number(q)})
+
+			// Assert
+			require.ErrorIs(t, err, app.ErrInvalidQuantity)
+		})
+	}
+}
+
+func TestUC26SetHolding_CoinNotEnabled(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	portfolios := mocks.NewMockPortfolioRepository(ctrl)
+	coins := mocks.NewMockCoinRepository(ctrl)
+	portfolios.EXPECT().FindByUser(gomock.Any(), int64(7), int64(4)).Return(&domain.Portfolio{ID: 4, UserID: 7}, nil)
+	coins.EXPECT().GetEnabledBySymbol(gomock.Any(), "ZZZ").Return(nil, nil)
+
+	uc := app.SetHoldingUseCase{Portfolios: portfolios, Holdings: mocks.NewMockHoldingRepository(ctrl), CoinRepo: coins}
+
+	// Act
+	_, err := uc.Execute(context.Background(), 7, 4, "zzz", app.SetHoldingInput{Quantity: "1"})
+
+	// Assert
+	require.ErrorIs(t, err, app.ErrCoinNotEnabled)
+}
+
+func TestUC26DeleteHolding_UnknownCoinOrHolding(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	portfolios := mocks.NewMockPortfolioRepository(ctrl)
+	holdings := mocks.NewMockHoldingRepository(ctrl)
+	coins := mocks.NewMockCoinRepository(ctrl)
+	portfolios.EXPECT().FindByUser(gomock.Any(), int64(7), int64(4)).Return(&domain.Portfolio{ID: 4, UserID: 7}, nil).Times(2)
+	coins.EXPECT().GetBySymbol(gomock.Any(), "NOPE").Return(nil, nil)
+	coins.EXPECT().GetBySymbol(gomock.Any(), "ETH").Return(&domain.Coin{ID: 2, Symbol: "ETH"}, nil)
+	holdings.EXPECT().Delete(gomock.Any(), int64(4), int64(2)).Return(false, nil)
+
+	uc := app.DeleteHoldingUseCase{Portfolios: portfolios, Holdings: holdings, CoinRepo: coins}
+
+	// Act
+	errCoin := uc.Execute(context.Background(), 7, 4, "nope")
+	errHolding := uc.Execute(context.Background(), 7, 4, "eth")
+
+	// Assert
+	require.ErrorIs(t, errCoin, app.ErrHoldingNotFound)
+	require.ErrorIs(t, errHolding, app.ErrHoldingNotFound)
+}
+
+func TestUC26ValuePortfolio_TotalsAllocationAndStaleness(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	portfolios := mocks.NewMockPortfolioRepository(ctrl)
+	holdings := mocks.NewMockHoldingRepository(ctrl)
+	quotes := mocks.NewMockQuoteRepository(ctrl)
+
+	portfolios.EXPECT().FindByUser(gomock.Any(), int64(7), int64(4)).Return(&domain.Portfolio{ID: 4, UserID: 7, BaseCurrency: "USD"}, nil)
+	holdings.EXPECT().ListByPortfolio(gomock.Any(), int64(4)).Return([]domain.Holding{
+		{Symbol: "ADA", Quantity: "100.000000000000000000"},
+		{Symbol: "BTC", Quantity: "0.1"},
+		{Symbol: "ETH", Quantity: "2"},
+	}, nil)
+	quotes.EXPECT().GetLatest(gomock.Any(), "ADA", "", "USD").Return(nil, nil)
+	quotes.EXPECT().GetLatest(gomock.Any(), "BTC", "", "USD").Return(&domain.PriceQuote{
+		Symbol: "BTC", Currency: "USD", Price: "60000.00", Provider: "binance",
+		Timestamp: now.Add(-5 * time.Minute).Format(time.RFC3339),
+	}, nil)
+	// formato de MySQL y precio viejo
+	quotes.EXPECT().GetLatest(gomock.Any(), "ETH", "", "USD").Return(&domain.PriceQuote{
+		Symbol: "ETH", Currency: "USD", Price: "1000", Provider: "coingecko",
+		Timestamp: now.Add(-3 * time.Hour).Format("2006-01-02 15:04:05"),
+	}, nil)
+
+	uc := app.ValuePortfolioUseCase{
+		Portfolios: portfolios,
+		Holdings:   holdings,
+		QuoteRepo:  quotes,
+		Now:        func() time.Time { return now },
+		StaleAfter: time.Hour,
+	}
+
+	// Act
+	out, err := uc.Execute(context.Background(), 7, 4, "")
+
+	// Assert
+	require.NoError(t, err)
+	require.Equal(t, "USD", out.Currency)
+	require.Equal(t, "8000", out.TotalValue)
+	require.Equal(t, int64(3600), out.StaleAfterSeconds)
+	require.Equal(t, 1, out.StaleCount)
+	require.Equal(t, 1, out.MissingCount)
+	require.Len(t, out.Assets, 3)
+
+	btc := out.Assets[0]
+	require.Equal(t, "BTC", btc.Symbol)
+	require.Equal(t, "6000", btc.Value)
+	require.Equal(t, "60000", btc.Price)
+	require.Equal(t, 75.0, *btc.AllocationPercent)
+	require.Equal(t, int64(300), *btc.AgeSeconds)
+	require.False(t, btc.Stale)
+
+	eth := out.Assets[1]
+	require.Equal(t, "ETH", eth.Symbol)
+	require.Equal(t, "2000", eth.Value)
+	require.Equal(t, 25.0, *eth.AllocationPercent)
+	require.Equal(t, now.Add(-3*time.Hour), *eth.QuotedAt)
+	require.True(t, eth.Stale)
+
+	ada := out.Assets[2]
+	require.Equal(t, "ADA", ada.Symbol)
+	require.Equal(t, "100", ada.Quantity)
+	require.True(t, ada.PriceMissing)
+	require.Nil(t, ada.AllocationPercent)
+	require.Empty(t, ada.Value)
+}
+
+func TestUC26ValuePortfolio_ExplicitCurrencyAndPrecision(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	portfolios := mocks.NewMockPortfolioRepository(ctrl)
+	holdings := mocks.NewMockHoldingRepository(ctrl)
+	quotes := mocks.NewMockQuoteRepository(ctrl)
+
+	portfolios.EXPECT().FindByUser(gomock.Any(), int64(7), int64(4)).Return(&domain.Portfolio{ID: 4, UserID: 7, BaseCurrency: "USD"}, nil)
+	holdings.EXPECT().ListByPortfolio(gomock.Any(), int64(4)).Return([]domain.Holding{
+		{Symbol: "A", Quantity: "0.1"},
+		{Symbol: "B", Quantity: "0.2"},
+	}, nil)
+	quotes.EXPECT().GetLatest(gomock.Any(), "A", "", "EUR").Return(&domain.PriceQuote{Price: "0.1", Timestamp: now.Format(time.RFC3339)}, nil)
+	quotes.EXPECT().GetLatest(gomock.Any(), "B", "", "EUR").Return(&domain.PriceQuote{Price: "0.1", Timestamp: now.Format(time.RFC3339)}, nil)
+
+	uc := app.ValuePortfolioUseCase{Portfolios: portfolios, Holdings: holdings, QuoteRepo: quotes, Now: func() time.Time { return now }}
+
+	// Act
+	out, err := uc.Execute(context.Background(), 7, 4, "eur")
+
+	// Assert: sin errores de float (0.1*0.1 + 0.2*0.1 = 0.03 exacto)
+	require.NoError(t, err)
+	require.Equal(t, "EUR", out.Currency)
+	require.Equal(t, "0.03", out.TotalValue)
+	require.Equal(t, "B", out.Assets[0].Symbol)
+	require.Equal(t, 66.6667, *out.Assets[0].AllocationPercent)
+	require.Equal(t, 33.3333, *out.Assets[1].AllocationPercent)
+	require.Equal(t, int64(2*3600), out.StaleAfterSeconds)
+}
+
+func TestUC26ValuePortfolio_InvalidCurrency(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	portfolios := mocks.NewMockPortfolioRepository(ctrl)
+	portfolios.EXPECT().FindByUser(gomock.Any(), int64(7), int64(4)).Return(&domain.Portfolio{ID: 4, UserID: 7, BaseCurrency: "USD"}, nil)
+
+	uc := app.ValuePortfolioUseCase{Portfolios: portfolios, Holdings: mocks.NewMockHoldingRepository(ctrl), QuoteRepo: mocks.NewMockQuoteRepository(ctrl)}
+
+	// Act
+	_, err := uc.Execute(context.Background(), 7, 4, "U$D")
+
+	// Assert
+	require.ErrorIs(t, err, app.ErrInvalidPortfolioCurrency)
+}
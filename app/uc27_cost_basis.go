@@ -0,0 +1,302 @@
+package app
+
+import (
+	"errors"
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/moondolphin/crypto-api/domain"
+)
+
+var (
+	ErrInvalidCostBasisMethod = errors.New("invalid_cost_basis_method")
+	ErrInsufficientQuantity   = errors.New("insufficient_quantity")
+	ErrInvalidTransaction     = errors.New("invalid_transaction")
+)
+
+// Métodos para asignar costo a lo que se vende.
+const (
+	// las ventas consumen primero los lotes más viejos
+	CostBasisFIFO = "fifo"
+
+	// un único pool por coin con costo promedio ponderado
+	CostBasisAverage = "average"
+)
+
+func IsValidCostBasisMethod(m string) bool {
+	return m == CostBasisFIFO || m == CostBasisAverage
+}
+
+// Lot es lo que queda abierto de una entrada (buy o transfer_in). Cost es el
+// costo total que le queda asignado, comisión de compra incluida.
+type Lot struct {
+	TransactionID int64
+	AcquiredAt    time.Time
+	Quantity      *big.Rat
+	Cost          *big.Rat
+}
+
+// LotMatch es la parte de un lote que consumió una salida (sell o
+// transfer_out). Con average no hay lotes: LotTransactionID es 0 y AcquiredAt
+// queda en cero.
+type LotMatch struct {
+	TransactionID    int64
+	Type             string
+	DisposedAt       time.Time
+	LotTransactionID int64
+	AcquiredAt       time.Time
+	Quantity         *big.Rat
+	CostBasis        *big.Rat
+
+	// lo cobrado por esta parte, neto de la comisión prorrateada; cero en
+	// transfer_out
+	Proceeds *big.Rat
+}
+
+// Gain es Proceeds - CostBasis; en transfer_out no hay ganancia.
+func (m LotMatch) Gain() *big.Rat {
+	if m.Type != domain.TransactionSell {
+		return new(big.Rat)
+	}
+	return new(big.Rat).Sub(m.Proceeds, m.CostBasis)
+}
+
+// AssetLedger es el estado de una coin después de aplicar todos sus
+// movimientos.
+type AssetLedger struct {
+	CoinID int64
+	Symbol string
+
+	// cantidad abierta y costo asignado a esa cantidad
+	Quantity  *big.Rat
+	CostBasis *big.Rat
+
+	// lotes abiertos, del más viejo al más nuevo (sólo FIFO)
+	Lots []Lot
+
+	// salidas en orden cronológico
+	Matches []LotMatch
+
+	// ventas: proceeds - costo; menos las comisiones de transferencias
+	RealizedPnL *big.Rat
+
+	// todas las comisiones pagadas
+	Fees *big.Rat
+}
+
+// ledgerTx es un movimiento con sus decimales ya leídos.
+type ledgerTx struct {
+	tx       domain.Transaction
+	quantity *big.Rat
+	price    *big.Rat
+	fee      *big.Rat
+}
+
+func parseLedgerTx(t domain.Transaction) (ledgerTx, error) {
+	if !domain.IsValidTransactionType(t.Type) {
+		return ledgerTx{}, ErrInvalidTransaction
+	}
+	q, ok := parseDecimal(t.Quantity)
+	if !ok || q.Sign() <= 0 {
+		return ledgerTx{}, ErrInvalidTransaction
+	}
+
+	price := new(big.Rat)
+	if t.Price != "" {
+		if price, ok = parseDecimal(t.Price); !ok || price.Sign() < 0 {
+			return ledgerTx{}, ErrInvalidTransaction
+		}
+	}
+	fee := new(big.Rat)
+	if t.Fee != "" {
+		if fee, ok = parseDecimal(t.Fee); !ok || fee.Sign() < 0 {
+			return ledgerTx{}, ErrInvalidTransaction
+		}
+	}
+	return ledgerTx{tx: t, quantity: q, price: price, fee: fee}, nil
+}
+
+// BuildLedger aplica los movimientos en orden cronológico (executed_at y, a
+// igual fecha, id) y devuelve una entrada por coin, ordenadas por símbolo.
+//
+// Reglas:
+//   - buy y transfer_in abren un lote con costo quantity*price (+ comisión en buy).
+//   - sell consume lotes (FIFO o promedio) y realiza proceeds - costo, con
+//     proceeds = quantity*price - comisión.
+//   - transfer_out consume lotes sin realizar ganancia: el costo sale del
+//     portfolio junto con las unidades.
+//   - la comisión de una transferencia se cuenta como pérdida realizada.
+//
+// Vender o transferir más de lo que hay abierto en ese momento devuelve
+// ErrInsufficientQuantity.
+func BuildLedger(txs []domain.Transaction, method string) ([]AssetLedger, error) {
+	if !IsValidCostBasisMethod(method) {
+		return nil, ErrInvalidCostBasisMethod
+	}
+
+	parsed := make([]ledgerTx, 0, len(txs))
+	for _, t := range txs {
+		lt, err := parseLedgerTx(t)
+		if err != nil {
+			return nil, err
+		}
+		parsed = append(parsed, lt)
+	}
+	sort.SliceStable(parsed, func(i, j int) bool {
+		a, b := parsed[i].tx, parsed[j].tx
+		if !a.ExecutedAt.Equal(b.ExecutedAt) {
+			return a.ExecutedAt.Before(b.ExecutedAt)
+		}
+		return a.ID < b.ID
+	})
+
+	byCoin := make(map[int64]*AssetLedger)
+	for _, lt := range parsed {
+		a, ok := byCoin[lt.tx.CoinID]
+		if !ok {
+			a = &AssetLedger{
+				CoinID:      lt.tx.CoinID,
+				Symbol:      lt.tx.Symbol,
+				Quantity:    new(big.Rat),
+				CostBasis:   new(big.Rat),
+				RealizedPnL: new(big.Rat),
+				Fees:        new(big.Rat),
+			}
+			byCoin[lt.tx.CoinID] = a
+		}
+		if err := a.apply(lt, method); err != nil {
+			return nil, err
+		}
+	}
+
+	out := make([]AssetLedger, 0, len(byCoin))
+	for _, a := range byCoin {
+		out = append(out, *a)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Symbol != out[j].Symbol {
+			return out[i].Symbol < out[j].Symbol
+		}
+		return out[i].CoinID < out[j].CoinID
+	})
+	return out, nil
+}
+
+func (a *AssetLedger) apply(lt ledgerTx, method string) error {
+	t := lt.tx
+	a.Fees.Add(a.Fees, lt.fee)
+
+	switch t.Type {
+	case domain.TransactionBuy, domain.TransactionTransferIn:
+		cost := new(big.Rat).Mul(lt.quantity, lt.price)
+		if t.Type == domain.TransactionBuy {
+			cost.Add(cost, lt.fee)
+		} else {
+			a.RealizedPnL.Sub(a.RealizedPnL, lt.fee)
+		}
+
+		a.Quantity.Add(a.Quantity, lt.quantity)
+		a.CostBasis.Add(a.CostBasis, cost)
+		if method == CostBasisFIFO {
+			a.Lots = append(a.Lots, Lot{
+				TransactionID: t.ID,
+				AcquiredAt:    t.ExecutedAt,
+				Quantity:      new(big.Rat).Set(lt.quantity),
+				Cost:          cost,
+			})
+		}
+		return nil
+
+	case domain.TransactionSell, domain.TransactionTransferOut:
+		if lt.quantity.Cmp(a.Quantity) > 0 {
+			return ErrInsufficientQuantity
+		}
+
+		proceeds := new(big.Rat)
+		if t.Type == domain.TransactionSell {
+			proceeds.Mul(lt.quantity, lt.price)
+			proceeds.Sub(proceeds, lt.fee)
+		} else {
+			a.RealizedPnL.Sub(a.RealizedPnL, lt.fee)
+		}
+
+		var matches []LotMatch
+		if method == CostBasisFIFO {
+			matches = a.consumeFIFO(t, lt.quantity, proceeds)
+		} else {
+			matches = a.consumeAverage(t, lt.quantity, proceeds)
+		}
+
+		for _, m := range matches {
+			a.CostBasis.Sub(a.CostBasis, m.CostBasis)
+			a.RealizedPnL.Add(a.RealizedPnL, m.Gain())
+		}
+		a.Quantity.Sub(a.Quantity, lt.quantity)
+		if a.Quantity.Sign() == 0 {
+			// sin unidades no puede quedar costo (evita restos de redondeo)
+			a.CostBasis.SetInt64(0)
+		}
+		a.Matches = append(a.Matches, matches...)
+		return nil
+	}
+	return ErrInvalidTransaction
+}
+
+// consumeFIFO saca quantity de los lotes más viejos. Cada parte lleva el costo
+// proporcional de su lote y los proceeds proporcionales a su cantidad.
+func (a *AssetLedger) consumeFIFO(t domain.Transaction, quantity, proceeds *big.Rat) []LotMatch {
+	var matches []LotMatch
+	remaining := new(big.Rat).Set(quantity)
+
+	for remaining.Sign() > 0 && len(a.Lots) > 0 {
+		lot := &a.Lots[0]
+
+		take := new(big.Rat).Set(remaining)
+		if lot.Quantity.Cmp(take) < 0 {
+			take.Set(lot.Quantity)
+		}
+
+		cost := new(big.Rat).Mul(lot.Cost, new(big.Rat).Quo(take, lot.Quantity))
+		if take.Cmp(lot.Quantity) == 0 {
+			cost.Set(lot.Cost)
+		}
+
+		matches = append(matches, LotMatch{
+			TransactionID:    t.ID,
+			Type:             t.Type,
+			DisposedAt:       t.ExecutedAt,
+			LotTransactionID: lot.TransactionID,
+			AcquiredAt:       lot.AcquiredAt,
+			Quantity:         take,
+			CostBasis:        cost,
+			Proceeds:         new(big.Rat).Mul(proceeds, new(big.Rat).Quo(take, quantity)),
+		})
+
+		lot.Quantity.Sub(lot.Quantity, take)
+		lot.Cost.Sub(lot.Cost, cost)
+		remaining.Sub(remaining, take)
+
+		if lot.Quantity.Sign() == 0 {
+			a.Lots = a.Lots[1:]
+		}
+	}
+	return matches
+}
+
+// consumeAverage saca quantity del pool al costo promedio vigente.
+func (a *AssetLedger) consumeAverage(t domain.Transaction, quantity, proceeds *big.Rat) []LotMatch {
+	cost := new(big.Rat).Set(a.CostBasis)
+	if quantity.Cmp(a.Quantity) != 0 {
+		cost.Mul(cost, new(big.Rat).Quo(quantity, a.Quantity))
+	}
+
+	return []LotMatch{{
+		TransactionID: t.ID,
+		Type:          t.Type,
+		DisposedAt:    t.ExecutedAt,
+		Quantity:      new(big.Rat).Set(quantity),
+		CostBasis:     cost,
+		Proceeds:      new(big.Rat).Set(proceeds),
+	}}
+}
@@ -0,0 +1,416 @@
+package app_test
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/moondolphin/crypto-api/app"
+	"github.com/moondolphin/crypto-api/domain"
+)
+
+var ledgerT0 = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// ltx arma un movimiento de BTC (coin 1) el día day.
+func ltx(id int64, kind, qty, price, fee string, day int) domain.Transaction {
+	return domain.Transaction{
+		ID:         id,
+		CoinID:     1,
+		Symbol:     "BTC",
+		Type:       kind,
+		Quantity:   qty,
+		Price:      price,
+		Fee:        fee,
+		Currency:   "USD",
+		ExecutedAt: ledgerT0.AddDate(0, 0, day),
+	}
+}
+
+// rat formatea con 8 decimales sin ceros sobrantes, para comparar.
+func rat(r *big.Rat) string {
+	s := r.FloatString(8)
+	for len(s) > 0 && s[len(s)-1] == '0' {
+		s = s[:len(s)-1]
+	}
+	if len(s) > 0 && s[len(s)-1] == '.' {
+		s = s[:len(s)-1]
+	}
+	if s == "-0" {
+		return "0"
+	}
+	return s
+}
+
+type wantMatch struct {
+	tx, lot  int64
+	qty      string
+	cost     string
+	proceeds string
+}
+
+func requireMatches(t *testing.T, want []wantMatch, got []app.LotMatch) {
+	t.Helper()
+	require.Len(t, got, len(want))
+	for i, w := range want {
+		require.Equal(t, w.tx, got[i].TransactionID, "match %d tx", i)
+		require.Equal(t, w.lot, got[i].LotTransactionID, "match %d lot", i)
+		require.Equal(t, w.qty, rat(got[i].Quantity), "match %d qty", i)
+		require.Equal(t, w.cost, rat(got[i].CostBasis), "match %d cost", i)
+		require.Equal(t, w.proceeds, rat(got[i].Proceeds), "match %d proceeds", i)
+	}
+}
+
+func buildOne(t *testing.T, method string, txs ...domain.Transaction) app.AssetLedger {
+	t.Helper()
+	ledgers, err := app.BuildLedger(txs, method)
+	require.NoError(t, err)
+	require.Len(t, ledgers, 1)
+	return ledgers[0]
+}
+
+func TestUC27BuildLedger_SingleBuyOpensLotWithFeeInCost(t *testing.T) {
+	// Act
+	l := buildOne(t, app.CostBasisFIFO, ltx(1, domain.TransactionBuy, "2", "100", "4", 0))
+
+	// Assert
+	require.Equal(t, "2", rat(l.Quantity))
+	require.Equal(t, "204", rat(l.CostBasis))
+	require.Equal(t, "0", rat(l.RealizedPnL))
+	require.Equal(t, "4", rat(l.Fees))
+	require.Len(t, l.Lots, 1)
+	require.Equal(t, int64(1), l.Lots[0].TransactionID)
+	require.Equal(t, ledgerT0, l.Lots[0].AcquiredAt)
+	require.Equal(t, "204", rat(l.Lots[0].Cost))
+	require.Empty(t, l.Matches)
+}
+
+func TestUC27BuildLedger_FIFOSellConsumesOldestLotFirst(t *testing.T) {
+	// Act
+	l := buildOne(t, app.CostBasisFIFO,
+		ltx(1, domain.TransactionBuy, "1", "100", "0", 0),
+		ltx(2, domain.TransactionBuy, "1", "200", "0", 1),
+		ltx(3, domain.TransactionSell, "1", "300", "0", 2),
+	)
+
+	// Assert
+	requireMatches(t, []wantMatch{{tx: 3, lot: 1, qty: "1", cost: "100", proceeds: "300"}}, l.Matches)
+	require.Equal(t, "200", rat(l.RealizedPnL))
+	require.Equal(t, "1", rat(l.Quantity))
+	require.Equal(t, "200", rat(l.CostBasis))
+	require.Len(t, l.Lots, 1)
+	require.Equal(t, int64(2), l.Lots[0].TransactionID)
+}
+
+func TestUC27BuildLedger_FIFOSellSpansSeveralLotsAndLeavesPartialLot(t *testing.T) {
+	// Act: 1@100 + 2@130 + 3@160; vende 4@150 con comisión 8
+	l := buildOne(t, app.CostBasisFIFO,
+		ltx(1, domain.TransactionBuy, "1", "100", "0", 0),
+		ltx(2, domain.TransactionBuy, "2", "130", "0", 1),
+		ltx(3, domain.TransactionBuy, "3", "160", "0", 2),
+		ltx(4, domain.TransactionSell, "4", "150", "8", 3),
+	)
+
+	// Assert: proceeds netos 592 repartidos 1/4, 2/4, 1/4
+	requireMatches(t, []wantMatch{
+		{tx: 4, lot: 1, qty: "1", cost: "100", proceeds: "148"},
+		{tx: 4, lot: 2, qty: "2", cost: "260", proceeds: "296"},
+		{tx: 4, lot: 3, qty: "1", cost: "160", proceeds: "148"},
+	}, l.Matches)
+	require.Equal(t, "72", rat(l.RealizedPnL)) // 592 - 520
+	require.Equal(t, "2", rat(l.Quantity))
+	require.Equal(t, "320", rat(l.CostBasis))
+	require.Len(t, l.Lots, 1)
+	require.Equal(t, int64(3), l.Lots[0].TransactionID)
+	require.Equal(t, "2", rat(l.Lots[0].Quantity))
+	require.Equal(t, "320", rat(l.Lots[0].Cost))
+	require.Equal(t, "8", rat(l.Fees))
+}
+
+func TestUC27BuildLedger_FIFOPartialLotKeepsProportionalFeeCost(t *testing.T) {
+	// Act: la comisión de compra (3) se reparte con el lote
+	l := buildOne(t, app.CostBasisFIFO,
+		ltx(1, domain.TransactionBuy, "3", "10", "3", 0),
+		ltx(2, domain.TransactionSell, "1", "20", "0", 1),
+		ltx(3, domain.TransactionSell, "1", "5", "0", 2),
+	)
+
+	// Assert
+	requireMatches(t, []wantMatch{
+		{tx: 2, lot: 1, qty: "1", cost: "11", proceeds: "20"},
+		{tx: 3, lot: 1, qty: "1", cost: "11", proceeds: "5"},
+	}, l.Matches)
+	require.Equal(t, "3", rat(l.RealizedPnL)) // 9 - 6
+	require.Equal(t, "11", rat(l.Lots[0].Cost))
+}
+
+func TestUC27BuildLedger_SellExactlyWholeLotRemovesIt(t *testing.T) {
+	// Act
+	l := buildOne(t, app.CostBasisFIFO,
+		ltx(1, domain.TransactionBuy, "0.5", "100", "0", 0),
+		ltx(2, domain.TransactionBuy, "0.5", "300", "0", 1),
+		ltx(3, domain.TransactionSell, "0.5", "400", "0", 2),
+	)
+
+	// Assert
+	require.Len(t, l.Lots, 1)
+	require.Equal(t, int64(2), l.Lots[0].TransactionID)
+	require.Equal(t, "150", rat(l.RealizedPnL))
+}
+
+func TestUC27BuildLedger_SellEverythingClosesPositionAndRebuysStartFresh(t *testing.T) {
+	for _, method := range []string{app.CostBasisFIFO, app.CostBasisAverage} {
+		t.Run(method, func(t *testing.T) {
+			// Act
+			l := buildOne(t, method,
+				ltx(1, domain.TransactionBuy, "1", "100", "0", 0),
+				ltx(2, domain.TransactionBuy, "2", "100.5", "0", 1),
+				ltx(3, domain.TransactionSell, "3", "90", "0", 2),
+				ltx(4, domain.TransactionBuy, "1", "50", "0", 3),
+			)
+
+			// Assert
+			require.Equal(t, "-31", rat(l.RealizedPnL)) // 270 - 301
+			require.Equal(t, "1", rat(l.Quantity))
+			require.Equal(t, "50", rat(l.CostBasis))
+			if method == app.CostBasisFIFO {
+				require.Len(t, l.Lots, 1)
+				require.Equal(t, int64(4), l.Lots[0].TransactionID)
+			} else {
+				require.Empty(t, l.Lots)
+			}
+		})
+	}
+}
+
+func TestUC27BuildLedger_AverageCostUsesWeightedPool(t *testing.T) {
+	// Act: 1@100 + 1@200 -> promedio 150; vende 1@300
+	l := buildOne(t, app.CostBasisAverage,
+		ltx(1, domain.TransactionBuy, "1", "100", "0", 0),
+		ltx(2, domain.TransactionBuy, "1", "200", "0", 1),
+		ltx(3, domain.TransactionSell, "1", "300", "0", 2),
+	)
+
+	// Assert
+	requireMatches(t, []wantMatch{{tx: 3, lot: 0, qty: "1", cost: "150", proceeds: "300"}}, l.Matches)
+	require.True(t, l.Matches[0].AcquiredAt.IsZero())
+	require.Equal(t, "150", rat(l.RealizedPnL))
+	require.Equal(t, "1", rat(l.Quantity))
+	require.Equal(t, "150", rat(l.CostBasis))
+	require.Empty(t, l.Lots)
+}
+
+func TestUC27BuildLedger_AverageCostAveragesAgainAfterNewBuys(t *testing.T) {
+	// Act: 2@10 (pool 20) -> vende 1 (queda 1 a 10) -> compra 1@40 (pool 50, prom 25) -> vende 1@30
+	l := buildOne(t, app.CostBasisAverage,
+		ltx(1, domain.TransactionBuy, "2", "10", "0", 0),
+		ltx(2, domain.TransactionSell, "1", "12", "0", 1),
+		ltx(3, domain.TransactionBuy, "1", "40", "0", 2),
+		ltx(4, domain.TransactionSell, "1", "30", "1", 3),
+	)
+
+	// Assert
+	requireMatches(t, []wantMatch{
+		{tx: 2, qty: "1", cost: "10", proceeds: "12"},
+		{tx: 4, qty: "1", cost: "25", proceeds: "29"},
+	}, l.Matches)
+	require.Equal(t, "6", rat(l.RealizedPnL)) // 2 + 4
+	require.Equal(t, "25", rat(l.CostBasis))
+}
+
+func TestUC27BuildLedger_FIFOAndAverageDifferButAgreeOnQuantity(t *testing.T) {
+	txs := []domain.Transaction{
+		ltx(1, domain.TransactionBuy, "1", "100", "0", 0),
+		ltx(2, domain.TransactionBuy, "1", "300", "0", 1),
+		ltx(3, domain.TransactionSell, "1", "250", "0", 2),
+	}
+
+	// Act
+	fifo := buildOne(t, app.CostBasisFIFO, txs...)
+	avg := buildOne(t, app.CostBasisAverage, txs...)
+
+	// Assert
+	require.Equal(t, "150", rat(fifo.RealizedPnL))
+	require.Equal(t, "50", rat(avg.RealizedPnL))
+	require.Equal(t, rat(fifo.Quantity), rat(avg.Quantity))
+	// costo abierto - realizado = invertido - cobrado, con cualquier método
+	require.Equal(t, "150", rat(new(big.Rat).Sub(fifo.CostBasis, fifo.RealizedPnL)))
+	require.Equal(t, "150", rat(new(big.Rat).Sub(avg.CostBasis, avg.RealizedPnL)))
+}
+
+func TestUC27BuildLedger_TransferInUsesAssignedCostAndFeeIsRealizedLoss(t *testing.T) {
+	// Act
+	l := buildOne(t, app.CostBasisFIFO,
+		ltx(1, domain.TransactionTransferIn, "2", "", "0.5", 0),
+		ltx(2, domain.TransactionTransferIn, "1", "30", "0", 1),
+		ltx(3, domain.TransactionSell, "2", "10", "0", 2),
+	)
+
+	// Assert: el primer lote entra a costo 0
+	requireMatches(t, []wantMatch{{tx: 3, lot: 1, qty: "2", cost: "0", proceeds: "20"}}, l.Matches)
+	require.Equal(t, "19.5", rat(l.RealizedPnL))
+	require.Equal(t, "30", rat(l.CostBasis))
+	require.Equal(t, "0.5", rat(l.Fees))
+}
+
+func TestUC27BuildLedger_TransferOutRemovesLotsWithoutRealizing(t *testing.T) {
+	for _, method := range []string{app.CostBasisFIFO, app.CostBasisAverage} {
+		t.Run(method, func(t *testing.T) {
+			// Act
+			l := buildOne(t, method,
+				ltx(1, domain.TransactionBuy, "1", "100", "0", 0),
+				ltx(2, domain.TransactionBuy, "1", "300", "0", 1),
+				ltx(3, domain.TransactionTransferOut, "1", "", "2", 2),
+			)
+
+			// Assert: sólo la comisión de la transferencia es pérdida
+			require.Equal(t, "-2", rat(l.RealizedPnL))
+			require.Equal(t, "1", rat(l.Quantity))
+			require.Len(t, l.Matches, 1)
+			require.Equal(t, domain.TransactionTransferOut, l.Matches[0].Type)
+			require.Equal(t, "0", rat(l.Matches[0].Proceeds))
+			require.Equal(t, "0", rat(l.Matches[0].Gain()))
+			if method == app.CostBasisFIFO {
+				require.Equal(t, "300", rat(l.CostBasis))
+				require.Equal(t, int64(1), l.Matches[0].LotTransactionID)
+			} else {
+				require.Equal(t, "200", rat(l.CostBasis))
+			}
+		})
+	}
+}
+
+func TestUC27BuildLedger_OrdersByExecutedAtThenID(t *testing.T) {
+	// Arrange: llegan desordenados; la venta y la compra 2 son del mismo día
+	txs := []domain.Transaction{
+		ltx(5, domain.TransactionSell, "1", "500", "0", 1),
+		ltx(2, domain.TransactionBuy, "1", "200", "0", 1),
+		ltx(9, domain.TransactionBuy, "1", "100", "0", 0),
+	}
+
+	// Act
+	l := buildOne(t, app.CostBasisFIFO, txs...)
+
+	// Assert: la compra 9 (día 0) es el lote más viejo aunque tenga id mayor
+	requireMatches(t, []wantMatch{{tx: 5, lot: 9, qty: "1", cost: "100", proceeds: "500"}}, l.Matches)
+	require.Equal(t, int64(2), l.Lots[0].TransactionID)
+}
+
+func TestUC27BuildLedger_SameInstantSellBeforeBuyByIDIsInsufficient(t *testing.T) {
+	// Act: la venta tiene id menor que la compra del mismo instante
+	_, err := app.BuildLedger([]domain.Transaction{
+		ltx(2, domain.TransactionBuy, "1", "100", "0", 0),
+		ltx(1, domain.TransactionSell, "1", "100", "0", 0),
+	}, app.CostBasisFIFO)
+
+	// Assert
+	require.ErrorIs(t, err, app.ErrInsufficientQuantity)
+}
+
+func TestUC27BuildLedger_OversellIsRejected(t *testing.T) {
+	cases := map[string][]domain.Transaction{
+		"sell without buys": {ltx(1, domain.TransactionSell, "1", "1", "0", 0)},
+		"sell more than held": {
+			ltx(1, domain.TransactionBuy, "1", "1", "0", 0),
+			ltx(2, domain.TransactionSell, "1.000000000000000001", "1", "0", 1),
+		},
+		"sell before the buy": {
+			ltx(1, domain.TransactionSell, "1", "1", "0", 0),
+			ltx(2, domain.TransactionBuy, "1", "1", "0", 1),
+		},
+		"transfer out after selling everything": {
+			ltx(1, domain.TransactionBuy, "1", "1", "0", 0),
+			ltx(2, domain.TransactionSell, "1", "1", "0", 1),
+			ltx(3, domain.TransactionTransferOut, "0.1", "", "0", 2),
+		},
+	}
+
+	for name, txs := range cases {
+		for _, method := range []string{app.CostBasisFIFO, app.CostBasisAverage} {
+			t.Run(name+"/"+method, func(t *testing.T) {
+				// Act
+				_, err := app.BuildLedger(txs, method)
+
+				// Assert
+				require.ErrorIs(t, err, app.ErrInsufficientQuantity)
+			})
+		}
+	}
+}
+
+func TestUC27BuildLedger_SeparatesCoinsAndSortsBySymbol(t *testing.T) {
+	eth := func(id int64, kind, qty, price string, day int) domain.Transaction {
+		tx := ltx(id, kind, qty, price, "0", day)
+		tx.CoinID = 2
+		tx.Symbol = "ETH"
+		return tx
+	}
+
+	// Act
+	ledgers, err := app.BuildLedger([]domain.Transaction{
+		eth(1, domain.TransactionBuy, "10", "3", 0),
+		ltx(2, domain.TransactionBuy, "1", "100", "0", 0),
+		eth(3, domain.TransactionSell, "4", "5", 1),
+	}, app.CostBasisFIFO)
+
+	// Assert: la venta de ETH no toca los lotes de BTC
+	require.NoError(t, err)
+	require.Len(t, ledgers, 2)
+	require.Equal(t, "BTC", ledgers[0].Symbol)
+	require.Equal(t, "0", rat(ledgers[0].RealizedPnL))
+	require.Equal(t, "1", rat(ledgers[0].Quantity))
+	require.Equal(t, "ETH", ledgers[1].Symbol)
+	require.Equal(t, "8", rat(ledgers[1].RealizedPnL))
+	require.Equal(t, "6", rat(ledgers[1].Quantity))
+}
+
+func TestUC27BuildLedger_ExactWithTinyQuantities(t *testing.T) {
+	// Act: 3 compras de 0.1 y una venta de 0.3 (en float no da exacto)
+	l := buildOne(t, app.CostBasisFIFO,
+		ltx(1, domain.TransactionBuy, "0.1", "0.1", "0", 0),
+		ltx(2, domain.TransactionBuy, "0.1", "0.2", "0", 1),
+		ltx(3, domain.TransactionBuy, "0.1", "0.3", "0", 2),
+		ltx(4, domain.TransactionSell, "0.3", "1", "0", 3),
+	)
+
+	// Assert
+	require.Equal(t, "0", rat(l.Quantity))
+	require.Equal(t, "0", rat(l.CostBasis))
+	require.Empty(t, l.Lots)
+	require.Equal(t, "0.24", rat(l.RealizedPnL))
+}
+
+func TestUC27BuildLedger_RejectsInvalidInput(t *testing.T) {
+	cases := map[string]struct {
+		txs    []domain.Transaction
+		method string
+		want   error
+	}{
+		"unknown method":   {txs: nil, method: "lifo", want: app.ErrInvalidCostBasisMethod},
+		"unknown type":     {txs: []domain.Transaction{ltx(1, "gift", "1", "1", "0", 0)}, method: app.CostBasisFIFO, want: app.ErrInvalidTransaction},
+		"zero quantity":    {txs: []domain.Transaction{ltx(1, domain.TransactionBuy, "0", "1", "0", 0)}, method: app.CostBasisFIFO, want: app.ErrInvalidTransaction},
+		"negative price":   {txs: []domain.Transaction{ltx(1, domain.TransactionBuy, "1", "-1", "0", 0)}, method: app.CostBasisFIFO, want: app.ErrInvalidTransaction},
+		"unreadable fee":   {txs: []domain.Transaction{ltx(1, domain.TransactionBuy, "1", "1", "x", 0)}, method: app.CostBasisFIFO, want: app.ErrInvalidTransaction},
+		"unreadable price": {txs: []domain.Transaction{ltx(1, domain.TransactionBuy, "1", "abc", "0", 0)}, method: app.CostBasisAverage, want: app.ErrInvalidTransaction},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			// Act
+			_, err := app.BuildLedger(tc.txs, tc.method)
+
+			// Assert
+			require.ErrorIs(t, err, tc.want)
+		})
+	}
+}
+
+func TestUC27BuildLedger_EmptyLedger(t *testing.T) {
+	// Act
+	ledgers, err := app.BuildLedger(nil, app.CostBasisAverage)
+
+	// Assert
+	require.NoError(t, err)
+	require.Empty(t, ledgers)
+}
@@ -0,0 +1,554 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/moondolphin/crypto-api/domain"
+)
+
+var (
+	ErrInvalidTransactionType   = errors.New("invalid_transaction_type")
+	ErrInvalidPrice             = errors.New("invalid_price")
+	ErrInvalidFee               = errors.New("invalid_fee")
+	ErrInvalidExecutedAt        = errors.New("invalid_executed_at")
+	ErrInvalidTransactionNote   = errors.New("invalid_transaction_note")
+	ErrTransactionNotFound      = errors.New("transaction_not_found")
+	ErrTransactionInUse         = errors.New("transaction_in_use")
+	ErrTransactionCurrencyMixed = errors.New("transaction_currency_mismatch")
+)
+
+const (
+	maxTransactionNoteLength = 255
+
+	// tolerancia para relojes de cliente adelantados
+	maxExecutedAtSkew = 5 * time.Minute
+)
+
+// parseAmount valida un decimal no negativo (precio, comisión); vacío -> "0".
+func parseAmount(raw string, invalid error) (string, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return "0", nil
+	}
+	if !quantityPattern.MatchString(raw) {
+		return "", invalid
+	}
+	v, ok := new(big.Rat).SetString(raw)
+	if !ok {
+		return "", invalid
+	}
+	return formatDecimal(v, quantityScale), nil
+}
+
+// RecordTransactionInput: los decimales se aceptan como número o string JSON.
+type RecordTransactionInput struct {
+	Symbol string `json:"symbol"`
+
+	// buy | sell | transfer_in | transfer_out
+	Type     string      `json:"type"`
+	Quantity json.Number `json:"quantity"`
+
+	// precio por unidad: obligatorio en buy/sell; en transfer_in es el costo
+	// asignado (sin precio cuenta como 0 y el reporte impositivo lo valúa al
+	// cierre del día); en transfer_out no se usa
+	Price json.Number `json:"price,omitempty"`
+	Fee   json.Number `json:"fee,omitempty"`
+
+	// default y único valor aceptado: moneda base del portfolio
+	Currency string `json:"currency,omitempty"`
+
+	// default: ahora
+	ExecutedAt *time.Time `json:"executed_at,omitempty"`
+	Note       string     `json:"note,omitempty"`
+}
+
+type TransactionOutput struct {
+	ID         int64     `json:"id"`
+	Symbol     string    `json:"symbol"`
+	Type       string    `json:"type"`
+	Quantity   string    `json:"quantity"`
+	Price      string    `json:"price,omitempty"`
+	Fee        string    `json:"fee"`
+	Currency   string    `json:"currency"`
+	ExecutedAt time.Time `json:"executed_at"`
+	Note       string    `json:"note,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+func toTransactionOutput(t domain.Transaction) TransactionOutput {
+	out := TransactionOutput{
+		ID:         t.ID,
+		Symbol:     t.Symbol,
+		Type:       t.Type,
+		Quantity:   normalizeQuantity(t.Quantity),
+		Fee:        normalizeQuantity(t.Fee),
+		Currency:   t.Currency,
+		ExecutedAt: t.ExecutedAt,
+		Note:       t.Note,
+		CreatedAt:  t.CreatedAt,
+	}
+	if t.Type != domain.TransactionTransferOut {
+		out.Price = normalizeQuantity(t.Price)
+	}
+	return out
+}
+
+// checkLedger rearma el libro de la coin con los movimientos dados: falla con
+// ErrInsufficientQuantity si alguna salida queda sin cubrir.
+func checkLedger(txs []domain.Transaction) error {
+	// FIFO y promedio mueven las mismas cantidades: alcanza con uno
+	_, err := BuildLedger(txs, CostBasisFIFO)
+	return err
+}
+
+type RecordTransactionUseCase struct {
+	Portfolios   domain.PortfolioRepository
+	Transactions domain.TransactionRepository
+	CoinRepo     domain.CoinRepository
+	Snapshots    domain.PortfolioSnapshotRepository // opcional
+	Now          func() time.Time
+}
+
+// Execute valida y guarda el movimiento. La moneda tiene que ser la base del
+// portfolio. Una salida (sell, transfer_out) no puede dejar negativa la
+// cantidad de la coin en ningún momento del libro.
+func (uc RecordTransactionUseCase) Execute(ctx context.Context, userID, portfolioID int64, in RecordTransactionInput) (TransactionOutput, error) {
+	symbol := strings.ToUpper(strings.TrimSpace(in.Symbol))
+	kind := strings.ToLower(strings.TrimSpace(in.Type))
+	if symbol == "" {
+		return TransactionOutput{}, ErrBadRequest
+	}
+	if !domain.IsValidTransactionType(kind) {
+		return TransactionOutput{}, ErrInvalidTransactionType
+	}
+
+	_, quantity, err := parseQuantity(in.Quantity.String())
+	if err != nil {
+		return TransactionOutput{}, err
+	}
+
+	price := "0"
+	switch kind {
+	case domain.TransactionBuy, domain.TransactionSell:
+		if strings.TrimSpace(in.Price.String()) == "" {
+			return TransactionOutput{}, ErrInvalidPrice
+		}
+		fallthrough
+	case domain.TransactionTransferIn:
+		if strings.TrimSpace(in.Price.String()) == "" {
+			// se guarda vacío para distinguirlo de un costo 0 explícito
+			price = ""
+			break
+		}
+		if price, err = parseAmount(in.Price.String(), ErrInvalidPrice); err != nil {
+			return TransactionOutput{}, err
+		}
+	}
+
+	fee, err := parseAmount(in.Fee.String(), ErrInvalidFee)
+	if err != nil {
+		return TransactionOutput{}, err
+	}
+
+	note := strings.TrimSpace(in.Note)
+	if utf8.RuneCountInString(note) > maxTransactionNoteLength {
+		return TransactionOutput{}, ErrInvalidTransactionNote
+	}
+
+	now := uc.Now
+	if now == nil {
+		now = time.Now
+	}
+	t := now().UTC()
+
+	executedAt := t
+	if in.ExecutedAt != nil {
+		executedAt = in.ExecutedAt.UTC()
+		if executedAt.IsZero() || executedAt.After(t.Add(maxExecutedAtSkew)) {
+			return TransactionOutput{}, ErrInvalidExecutedAt
+		}
+	}
+
+	p, err := findPortfolio(ctx, uc.Portfolios, userID, portfolioID)
+	if err != nil {
+		return TransactionOutput{}, err
+	}
+	currency, err := normalizeCurrency(in.Currency, p.BaseCurrency)
+	if err != nil {
+		return TransactionOutput{}, err
+	}
+	// P&L, historial y reporte impositivo suman todo en la moneda base
+	if currency != p.BaseCurrency {
+		return TransactionOutput{}, ErrTransactionCurrencyMixed
+	}
+
+	// se puede vender o sacar una coin que se deshabilitó después de comprarla
+	var coin *domain.Coin
+	if kind == domain.TransactionBuy || kind == domain.TransactionTransferIn {
+		coin, err = uc.CoinRepo.GetEnabledBySymbol(ctx, symbol)
+	} else {
+		coin, err = uc.CoinRepo.GetBySymbol(ctx, symbol)
+	}
+	if err != nil {
+		return TransactionOutput{}, err
+	}
+	if coin == nil {
+		return TransactionOutput{}, ErrCoinNotEnabled
+	}
+
+	tx := domain.Transaction{
+		PortfolioID: p.ID,
+		CoinID:      coin.ID,
+		Symbol:      coin.Symbol,
+		Type:        kind,
+		Quantity:    quantity,
+		Price:       price,
+		Fee:         fee,
+		Currency:    currency,
+		ExecutedAt:  executedAt,
+		Note:        note,
+		CreatedAt:   t,
+	}
+
+	if err := invalidateSnapshots(ctx, uc.Snapshots, p.ID, executedAt); err != nil {
+		return TransactionOutput{}, err
+	}
+
+	var created domain.Transaction
+	if kind == domain.TransactionSell || kind == domain.TransactionTransferOut {
+		created, err = uc.Transactions.CreateChecked(ctx, tx, func(existing []domain.Transaction) error {
+			// el nuevo movimiento todavía no tiene id: va después de los de su mismo instante
+			probe := tx
+			probe.ID = 1
+			for _, e := range existing {
+				if e.ID >= probe.ID {
+					probe.ID = e.ID + 1
+				}
+			}
+			return checkLedger(append(existing, probe))
+		})
+	} else {
+		created, err = uc.Transactions.Create(ctx, tx)
+	}
+	if err != nil {
+		return TransactionOutput{}, err
+	}
+	return toTransactionOutput(created), nil
+}
+
+type ListTransactionsInput struct {
+	Symbol string
+	From   *time.Time
+	To     *time.Time
+}
+
+type ListTransactionsUseCase struct {
+	Portfolios   domain.PortfolioRepository
+	Transactions domain.TransactionRepository
+	CoinRepo     domain.CoinRepository
+}
+
+// Execute lista los movimientos del portfolio en orden cronológico.
+func (uc ListTransactionsUseCase) Execute(ctx context.Context, userID, portfolioID int64, in ListTransactionsInput) ([]TransactionOutput, error) {
+	if in.From != nil && in.To != nil && !in.From.Before(*in.To) {
+		return nil, ErrBadRequest
+	}
+
+	p, err := findPortfolio(ctx, uc.Portfolios, userID, portfolioID)
+	if err != nil {
+		return nil, err
+	}
+
+	f := domain.TransactionFilter{From: in.From, To: in.To}
+	if symbol := strings.ToUpper(strings.TrimSpace(in.Symbol)); symbol != "" {
+		coin, err := uc.CoinRepo.GetBySymbol(ctx, symbol)
+		if err != nil {
+			return nil, err
+		}
+		if coin == nil {
+			return []TransactionOutput{}, nil
+		}
+		f.CoinID = coin.ID
+	}
+
+	txs, err := uc.Transactions.ListByPortfolio(ctx, p.ID, f)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]TransactionOutput, 0, len(txs))
+	for _, t := range txs {
+		out = append(out, toTransactionOutput(t))
+	}
+	return out, nil
+}
+
+type DeleteTransactionUseCase struct {
+	Portfolios   domain.PortfolioRepository
+	Transactions domain.TransactionRepository
+	Snapshots    domain.PortfolioSnapshotRepository // opcional
+}
+
+// Execute borra un movimiento. No se puede borrar una entrada que cubre una
+// salida posterior (ErrTransactionInUse): primero hay que borrar la salida.
+func (uc DeleteTransactionUseCase) Execute(ctx context.Context, userID, portfolioID, id int64) error {
+	if id <= 0 {
+		return ErrBadRequest
+	}
+
+	p, err := findPortfolio(ctx, uc.Portfolios, userID, portfolioID)
+	if err != nil {
+		return err
+	}
+
+	tx, err := uc.Transactions.FindByPortfolio(ctx, p.ID, id)
+	if err != nil {
+		return err
+	}
+	if tx == nil {
+		return ErrTransactionNotFound
+	}
+
+	if tx.Type == domain.TransactionBuy || tx.Type == domain.TransactionTransferIn {
+		txs, err := uc.Transactions.ListByPortfolio(ctx, p.ID, domain.TransactionFilter{CoinID: tx.CoinID})
+		if err != nil {
+			return err
+		}
+		rest := make([]domain.Transaction, 0, len(txs))
+		for _, t := range txs {
+			if t.ID != tx.ID {
+				rest = append(rest, t)
+			}
+		}
+		if err := checkLedger(rest); err != nil {
+			if errors.Is(err, ErrInsufficientQuantity) {
+				return ErrTransactionInUse
+			}
+			return err
+		}
+	}
+
+	if err := invalidateSnapshots(ctx, uc.Snapshots, p.ID, tx.ExecutedAt); err != nil {
+		return err
+	}
+
+	ok, err := uc.Transactions.Delete(ctx, p.ID, id)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrTransactionNotFound
+	}
+	return nil
+}
+
+type OpenLotOutput struct {
+	TransactionID int64     `json:"transaction_id"`
+	AcquiredAt    time.Time `json:"acquired_at"`
+	Quantity      string    `json:"quantity"`
+	UnitCost      string    `json:"unit_cost"`
+	CostBasis     string    `json:"cost_basis"`
+
+	// vacío si no hay precio
+	UnrealizedPnL string `json:"unrealized_pnl,omitempty"`
+}
+
+type AssetPnLOutput struct {
+	Symbol string `json:"symbol"`
+
+	// cantidad abierta y su costo
+	Quantity    string `json:"quantity"`
+	CostBasis   string `json:"cost_basis"`
+	AverageCost string `json:"average_cost,omitempty"`
+
+	RealizedPnL string `json:"realized_pnl"`
+	Fees        string `json:"fees"`
+
+	// sólo si queda cantidad abierta y hay cotización
+	Price             string     `json:"price,omitempty"`
+	MarketValue       string     `json:"market_value,omitempty"`
+	UnrealizedPnL     string     `json:"unrealized_pnl,omitempty"`
+	UnrealizedPercent *float64   `json:"unrealized_percent,omitempty"`
+	Provider          string     `json:"provider,omitempty"`
+	QuotedAt          *time.Time `json:"quoted_at,omitempty"`
+	AgeSeconds        *int64     `json:"age_seconds,omitempty"`
+	Stale             bool       `json:"stale"`
+	PriceMissing      bool       `json:"price_missing,omitempty"`
+
+	// lotes abiertos (sólo con method=fifo)
+	OpenLots []OpenLotOutput `json:"open_lots,omitempty"`
+}
+
+type PortfolioPnLOutput struct {
+	PortfolioID       int64     `json:"portfolio_id"`
+	Currency          string    `json:"currency"`
+	Method            string    `json:"method"`
+	ValuedAt          time.Time `json:"valued_at"`
+	StaleAfterSeconds int64     `json:"stale_after_seconds"`
+
+	CostBasis     string `json:"cost_basis"`
+	RealizedPnL   string `json:"realized_pnl"`
+	Fees          string `json:"fees"`
+	MarketValue   string `json:"market_value"`
+	UnrealizedPnL string `json:"unrealized_pnl"`
+
+	// si MissingCount > 0, market_value y unrealized_pnl no incluyen esos activos
+	StaleCount   int `json:"stale_count"`
+	MissingCount int `json:"missing_count"`
+
+	Assets []AssetPnLOutput `json:"assets"`
+}
+
+// PortfolioPnLUseCase calcula ganancia realizada y no realizada del libro de
+// movimientos. Lo abierto se valúa con la última cotización guardada (como la
+// valuación de tenencias). Todos los movimientos tienen que estar en la moneda
+// pedida: no se convierten montos entre monedas.
+type PortfolioPnLUseCase struct {
+	Portfolios   domain.PortfolioRepository
+	Transactions domain.TransactionRepository
+	QuoteRepo    domain.QuoteRepository
+	Now          func() time.Time
+
+	StaleAfter time.Duration
+}
+
+// Execute usa method (default fifo) y currency (default moneda base).
+func (uc PortfolioPnLUseCase) Execute(ctx context.Context, userID, portfolioID int64, method, currency string) (PortfolioPnLOutput, error) {
+	method = strings.ToLower(strings.TrimSpace(method))
+	if method == "" {
+		method = CostBasisFIFO
+	}
+	if !IsValidCostBasisMethod(method) {
+		return PortfolioPnLOutput{}, ErrInvalidCostBasisMethod
+	}
+
+	p, err := findPortfolio(ctx, uc.Portfolios, userID, portfolioID)
+	if err != nil {
+		return PortfolioPnLOutput{}, err
+	}
+	currency, err = normalizeCurrency(currency, p.BaseCurrency)
+	if err != nil {
+		return PortfolioPnLOutput{}, err
+	}
+
+	txs, err := uc.Transactions.ListByPortfolio(ctx, p.ID, domain.TransactionFilter{})
+	if err != nil {
+		return PortfolioPnLOutput{}, err
+	}
+	for _, t := range txs {
+		if t.Currency != currency {
+			return PortfolioPnLOutput{}, ErrTransactionCurrencyMixed
+		}
+	}
+
+	ledgers, err := BuildLedger(txs, method)
+	if err != nil {
+		return PortfolioPnLOutput{}, err
+	}
+
+	now := uc.Now
+	if now == nil {
+		now = time.Now
+	}
+	t := now().UTC()
+
+	staleAfter := uc.StaleAfter
+	if staleAfter <= 0 {
+		staleAfter = defaultStalePriceAfter
+	}
+
+	out := PortfolioPnLOutput{
+		PortfolioID:       p.ID,
+		Currency:          currency,
+		Method:            method,
+		ValuedAt:          t,
+		StaleAfterSeconds: int64(staleAfter / time.Second),
+		Assets:            make([]AssetPnLOutput, 0, len(ledgers)),
+	}
+
+	var (
+		costBasis  = new(big.Rat)
+		realized   = new(big.Rat)
+		fees       = new(big.Rat)
+		market     = new(big.Rat)
+		unrealized = new(big.Rat)
+	)
+
+	for _, l := range ledgers {
+		asset := AssetPnLOutput{
+			Symbol:      l.Symbol,
+			Quantity:    formatDecimal(l.Quantity, quantityScale),
+			CostBasis:   formatDecimal(l.CostBasis, valueScale),
+			RealizedPnL: formatDecimal(l.RealizedPnL, valueScale),
+			Fees:        formatDecimal(l.Fees, valueScale),
+		}
+		costBasis.Add(costBasis, l.CostBasis)
+		realized.Add(realized, l.RealizedPnL)
+		fees.Add(fees, l.Fees)
+
+		var lp *quotedPrice
+		if l.Quantity.Sign() > 0 {
+			asset.AverageCost = formatDecimal(new(big.Rat).Quo(l.CostBasis, l.Quantity), valueScale)
+
+			lp, err = latestPrice(ctx, uc.QuoteRepo, l.Symbol, currency, t, staleAfter)
+			if err != nil {
+				return PortfolioPnLOutput{}, err
+			}
+			if lp == nil {
+				asset.PriceMissing = true
+				out.MissingCount++
+			} else {
+				value := new(big.Rat).Mul(l.Quantity, lp.price)
+				gain := new(big.Rat).Sub(value, l.CostBasis)
+				market.Add(market, value)
+				unrealized.Add(unrealized, gain)
+
+				asset.Price = formatDecimal(lp.price, valueScale)
+				asset.MarketValue = formatDecimal(value, valueScale)
+				asset.UnrealizedPnL = formatDecimal(gain, valueScale)
+				if l.CostBasis.Sign() > 0 {
+					pct := allocationPercent(gain, l.CostBasis)
+					asset.UnrealizedPercent = &pct
+				}
+				asset.Provider = lp.provider
+				asset.QuotedAt = lp.quotedAt
+				asset.AgeSeconds = lp.ageSeconds
+				asset.Stale = lp.stale
+				if lp.stale {
+					out.StaleCount++
+				}
+			}
+		}
+
+		for _, lot := range l.Lots {
+			lo := OpenLotOutput{
+				TransactionID: lot.TransactionID,
+				AcquiredAt:    lot.AcquiredAt,
+				Quantity:      formatDecimal(lot.Quantity, quantityScale),
+				UnitCost:      formatDecimal(new(big.Rat).Quo(lot.Cost, lot.Quantity), valueScale),
+				CostBasis:     formatDecimal(lot.Cost, valueScale),
+			}
+			if lp != nil {
+				gain := new(big.Rat).Mul(lot.Quantity, lp.price)
+				gain.Sub(gain, lot.Cost)
+				lo.UnrealizedPnL = formatDecimal(gain, valueScale)
+			}
+			asset.OpenLots = append(asset.OpenLots, lo)
+		}
+
+		out.Assets = append(out.Assets, asset)
+	}
+
+	out.CostBasis = formatDecimal(costBasis, valueScale)
+	out.RealizedPnL = formatDecimal(realized, valueScale)
+	out.Fees = formatDecimal(fees, valueScale)
+	out.MarketValue = formatDecimal(market, valueScale)
+	out.UnrealizedPnL = formatDecimal(unrealized, valueScale)
+	return out, nil
+}
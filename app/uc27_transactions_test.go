@@ -0,0 +1,496 @@
+package app_test
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/moondolphin/crypto-api/app"
+	"github.com/moondolphin/crypto-api/domain"
+	"github.com/moondolphin/crypto-api/test/mocks"
+)
+
+func TestUC27RecordTransaction_BuyDefaultsCurrencyAndTime(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	portfolios := mocks.NewMockPortfolioRepository(ctrl)
+	txs := mocks.NewMockTransactionRepository(ctrl)
+	coins := mocks.NewMockCoinRepository(ctrl)
+
+	portfolios.EXPECT().FindByUser(gomock.Any(), int64(7), int64(4)).Return(&domain.Portfolio{ID: 4, UserID: 7, BaseCurrency: "EUR"}, nil)
+	coins.EXPECT().GetEnabledBySymbol(gomock.Any(), "BTC").Return(&domain.Coin{ID: 1, Symbol: "BTC"}, nil)
+	txs.EXPECT().Create(gomock.Any(), domain.Transaction{
+		PortfolioID: 4,
+		CoinID:      1,
+		Symbol:      "BTC",
+		Type:        domain.TransactionBuy,
+		Quantity:    "0.5",
+		Price:       "60000",
+		Fee:         "0",
+		Currency:    "EUR",
+		ExecutedAt:  now,
+		Note:        "DCA",
+		CreatedAt:   now,
+	}).DoAndReturn(func(_ context.Context, tx domain.Transaction) (domain.Transaction, error) {
+		tx.ID = 11
+		return tx, nil
+	})
+
+	uc := app.RecordTransactionUseCase{Portfolios: portfolios, Transactions: txs, CoinRepo: coins, Now: func() time.Time { return now }}
+
+	// Act
+	out, err := uc.Execute(context.Background(), 7, 4, app.RecordTransactionInput{
+		Symbol:   " btc ",
+		Type:     "BUY",
+		Quantity: json.Number("0.50"),
+		Price:    json.Number("60000.00"),
+		Note:     " DCA ",
+	})
+
+	// Assert
+	require.NoError(t, err)
+	require.Equal(t, int64(11), out.ID)
+	require.Equal(t, "0.5", out.Quantity)
+	require.Equal(t, "60000", out.Price)
+	require.Equal(t, "EUR", out.Currency)
+}
+
+func TestUC27RecordTransaction_Rejections(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	future := now.Add(time.Hour)
+
+	cases := map[string]struct {
+		in   app.RecordTransactionInput
+		want error
+	}{
+		"missing symbol":    {in: app.RecordTransactionInput{Type: "buy", Quantity: "1", Price: "1"}, want: app.ErrBadRequest},
+		"unknown type":      {in: app.RecordTransactionInput{Symbol: "BTC", Type: "gift", Quantity: "1"}, want: app.ErrInvalidTransactionType},
+		"zero quantity":     {in: app.RecordTransactionInput{Symbol: "BTC", Type: "buy", Quantity: "0", Price: "1"}, want: app.ErrInvalidQuantity},
+		"buy without price": {in: app.RecordTransactionInput{Symbol: "BTC", Type: "buy", Quantity: "1"}, want: app.ErrInvalidPrice},
+		"sell bad price":    {in: app.RecordTransactionInput{Symbol: "BTC", Type: "sell", Quantity: "1", Price: "-3"}, want: app.ErrInvalidPrice},
+		"bad fee":           {in: app.RecordTransactionInput{Symbol: "BTC", Type: "buy", Quantity: "1", Price: "1", Fee: "1e3"}, want: app.ErrInvalidFee},
+		"long note": {
+			in:   app.RecordTransactionInput{Symbol: "BTC", Type: "buy", Quantity: "1", Price: "1", Note: strings.Repeat("x", 256)},
+			want: app.ErrInvalidTransactionNote,
+		},
+		"executed in the future": {
+			in:   app.RecordTransactionInput{Symbol: "BTC", Type: "buy", Quantity: "1", Price: "1", ExecutedAt: &future},
+			want: app.ErrInvalidExecutedAt,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			// Arrange: ninguna validación llega al repo
+			uc := app.RecordTransactionUseCase{
+				Portfolios:   mocks.NewMockPortfolioRepository(ctrl),
+				Transactions: mocks.NewMockTransactionRepository(ctrl),
+				CoinRepo:     mocks.NewMockCoinRepository(ctrl),
+				Now:          func() time.Time { return now },
+			}
+
+			// Act
+			_, err := uc.Execute(context.Background(), 7, 4, tc.in)
+
+			// Assert
+			require.ErrorIs(t, err, tc.want)
+		})
+	}
+}
+
+func TestUC27RecordTransaction_UnknownCoinOrPortfolio(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	portfolios := mocks.NewMockPortfolioRepository(ctrl)
+	coins := mocks.NewMockCoinRepository(ctrl)
+	portfolios.EXPECT().FindByUser(gomock.Any(), int64(7), int64(4)).Return(&domain.Portfolio{ID: 4, BaseCurrency: "USD"}, nil)
+	portfolios.EXPECT().FindByUser(gomock.Any(), int64(7), int64(5)).Return(nil, nil)
+	coins.EXPECT().GetEnabledBySymbol(gomock.Any(), "DOGE").Return(nil, nil)
+
+	uc := app.RecordTransactionUseCase{Portfolios: portfolios, Transactions: mocks.NewMockTransactionRepository(ctrl), CoinRepo: coins}
+	in := app.RecordTransactionInput{Symbol: "DOGE", Type: "buy", Quantity: "1", Price: "0.1"}
+
+	// Act
+	_, errCoin := uc.Execute(context.Background(), 7, 4, in)
+	_, errPortfolio := uc.Execute(context.Background(), 7, 5, in)
+
+	// Assert
+	require.ErrorIs(t, errCoin, app.ErrCoinNotEnabled)
+	require.ErrorIs(t, errPortfolio, app.ErrPortfolioNotFound)
+}
+
+func TestUC27RecordTransaction_CurrencyOtherThanBase_IsRejected(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	portfolios := mocks.NewMockPortfolioRepository(ctrl)
+	portfolios.EXPECT().FindByUser(gomock.Any(), int64(7), int64(4)).Return(&domain.Portfolio{ID: 4, BaseCurrency: "USD"}, nil)
+
+	// sin expectativas: no se busca la coin ni se guarda nada
+	uc := app.RecordTransactionUseCase{
+		Portfolios:   portfolios,
+		Transactions: mocks.NewMockTransactionRepository(ctrl),
+		CoinRepo:     mocks.NewMockCoinRepository(ctrl),
+	}
+
+	// Act
+	_, err := uc.Execute(context.Background(), 7, 4, app.RecordTransactionInput{
+		Symbol: "BTC", Type: "buy", Quantity: "1", Price: "100", Currency: "eur",
+	})
+
+	// Assert
+	require.ErrorIs(t, err, app.ErrTransactionCurrencyMixed)
+}
+
+func TestUC27RecordTransaction_SellChecksOpenQuantityAtExecutionTime(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	day := func(d int) time.Time { return time.Date(2026, 3, d, 0, 0, 0, 0, time.UTC) }
+	existing := []domain.Transaction{
+		{ID: 1, CoinID: 1, Symbol: "BTC", Type: domain.TransactionBuy, Quantity: "1", Price: "100", Fee: "0", Currency: "USD", ExecutedAt: day(2)},
+		{ID: 2, CoinID: 1, Symbol: "BTC", Type: domain.TransactionSell, Quantity: "1", Price: "150", Fee: "0", Currency: "USD", ExecutedAt: day(5)},
+		{ID: 3, CoinID: 1, Symbol: "BTC", Type: domain.TransactionBuy, Quantity: "2", Price: "120", Fee: "0", Currency: "USD", ExecutedAt: day(8)},
+	}
+
+	cases := map[string]struct {
+		executedAt time.Time
+		quantity   string
+		kind       string
+		want       error
+	}{
+		// el 3 hay 1 abierto, pero dejaría sin cubrir la venta del 5
+		"backdated sell uncovers a later one": {executedAt: day(3), quantity: "1", kind: "sell", want: app.ErrInsufficientQuantity},
+		"more than held now":                  {executedAt: day(9), quantity: "2.1", kind: "transfer_out", want: app.ErrInsufficientQuantity},
+		"all that is held now":                {executedAt: day(9), quantity: "2", kind: "sell"},
+		// mismo instante que la compra 3: el nuevo va después
+		"same instant as the covering buy": {executedAt: day(8), quantity: "2", kind: "sell"},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			// Arrange
+			portfolios := mocks.NewMockPortfolioRepository(ctrl)
+			txs := mocks.NewMockTransactionRepository(ctrl)
+			coins := mocks.NewMockCoinRepository(ctrl)
+
+			portfolios.EXPECT().FindByUser(gomock.Any(), int64(7), int64(4)).Return(&domain.Portfolio{ID: 4, BaseCurrency: "USD"}, nil)
+			coins.EXPECT().GetBySymbol(gomock.Any(), "BTC").Return(&domain.Coin{ID: 1, Symbol: "BTC"}, nil)
+			txs.EXPECT().CreateChecked(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+				func(_ context.Context, tx domain.Transaction, check func([]domain.Transaction) error) (domain.Transaction, error) {
+					if err := check(existing); err != nil {
+						return domain.Transaction{}, err
+					}
+					tx.ID = 4
+					return tx, nil
+				})
+
+			uc := app.RecordTransactionUseCase{Portfolios: portfolios, Transactions: txs, CoinRepo: coins, Now: func() time.Time { return now }}
+			executedAt := tc.executedAt
+
+			// Act
+			_, err := uc.Execute(context.Background(), 7, 4, app.RecordTransactionInput{
+				Symbol:     "BTC",
+				Type:       tc.kind,
+				Quantity:   json.Number(tc.quantity),
+				Price:      "130",
+				ExecutedAt: &executedAt,
+			})
+
+			// Assert
+			if tc.want == nil {
+				require.NoError(t, err)
+			} else {
+				require.ErrorIs(t, err, tc.want)
+			}
+		})
+	}
+}
+
+func TestUC27RecordTransaction_TransferOutIgnoresPrice(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	portfolios := mocks.NewMockPortfolioRepository(ctrl)
+	txs := mocks.NewMockTransactionRepository(ctrl)
+	coins := mocks.NewMockCoinRepository(ctrl)
+
+	portfolios.EXPECT().FindByUser(gomock.Any(), int64(7), int64(4)).Return(&domain.Portfolio{ID: 4, BaseCurrency: "USD"}, nil)
+	coins.EXPECT().GetBySymbol(gomock.Any(), "ETH").Return(&domain.Coin{ID: 2, Symbol: "ETH"}, nil)
+	txs.EXPECT().CreateChecked(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, tx domain.Transaction, check func([]domain.Transaction) error) (domain.Transaction, error) {
+			require.NoError(t, check([]domain.Transaction{
+				{ID: 1, CoinID: 2, Symbol: "ETH", Type: domain.TransactionTransferIn, Quantity: "3", Price: "0", Fee: "0", Currency: "USD", ExecutedAt: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)},
+			}))
+			require.Equal(t, "0", tx.Price)
+			require.Equal(t, "0.01", tx.Fee)
+			tx.ID = 2
+			return tx, nil
+		})
+
+	uc := app.RecordTransactionUseCase{Portfolios: portfolios, Transactions: txs, CoinRepo: coins}
+
+	// Act
+	out, err := uc.Execute(context.Background(), 7, 4, app.RecordTransactionInput{
+		Symbol: "ETH", Type: "transfer_out", Quantity: "1", Price: "not-used", Fee: "0.01",
+	})
+
+	// Assert
+	require.NoError(t, err)
+	require.Empty(t, out.Price)
+}
+
+func TestUC27RecordTransaction_TransferInKeepsMissingPriceApartFromZero(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	portfolios := mocks.NewMockPortfolioRepository(ctrl)
+	txs := mocks.NewMockTransactionRepository(ctrl)
+	coins := mocks.NewMockCoinRepository(ctrl)
+
+	portfolios.EXPECT().FindByUser(gomock.Any(), int64(7), int64(4)).Return(&domain.Portfolio{ID: 4, BaseCurrency: "USD"}, nil).Times(2)
+	coins.EXPECT().GetEnabledBySymbol(gomock.Any(), "ETH").Return(&domain.Coin{ID: 2, Symbol: "ETH"}, nil).Times(2)
+	var prices []string
+	txs.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, tx domain.Transaction) (domain.Transaction, error) {
+		prices = append(prices, tx.Price)
+		return tx, nil
+	}).Times(2)
+
+	uc := app.RecordTransactionUseCase{Portfolios: portfolios, Transactions: txs, CoinRepo: coins}
+
+	// Act
+	missing, errMissing := uc.Execute(context.Background(), 7, 4, app.RecordTransactionInput{Symbol: "ETH", Type: "transfer_in", Quantity: "1"})
+	zero, errZero := uc.Execute(context.Background(), 7, 4, app.RecordTransactionInput{Symbol: "ETH", Type: "transfer_in", Quantity: "1", Price: "0"})
+
+	// Assert
+	require.NoError(t, errMissing)
+	require.NoError(t, errZero)
+	require.Equal(t, []string{"", "0"}, prices)
+	require.Empty(t, missing.Price)
+	require.Equal(t, "0", zero.Price)
+}
+
+func TestUC27ListTransactions_FiltersBySymbolAndRange(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, 0)
+	portfolios := mocks.NewMockPortfolioRepository(ctrl)
+	txs := mocks.NewMockTransactionRepository(ctrl)
+	coins := mocks.NewMockCoinRepository(ctrl)
+
+	portfolios.EXPECT().FindByUser(gomock.Any(), int64(7), int64(4)).Return(&domain.Portfolio{ID: 4}, nil).Times(2)
+	coins.EXPECT().GetBySymbol(gomock.Any(), "BTC").Return(&domain.Coin{ID: 1, Symbol: "BTC"}, nil)
+	coins.EXPECT().GetBySymbol(gomock.Any(), "NOPE").Return(nil, nil)
+	txs.EXPECT().ListByPortfolio(gomock.Any(), int64(4), domain.TransactionFilter{CoinID: 1, From: &from, To: &to}).Return([]domain.Transaction{
+		{ID: 3, Symbol: "BTC", Type: domain.TransactionBuy, Quantity: "1.500000000000000000", Price: "10.000000000000000000", Fee: "0.000000000000000000"},
+	}, nil)
+
+	uc := app.ListTransactionsUseCase{Portfolios: portfolios, Transactions: txs, CoinRepo: coins}
+
+	// Act
+	out, err := uc.Execute(context.Background(), 7, 4, app.ListTransactionsInput{Symbol: "btc", From: &from, To: &to})
+	none, errNone := uc.Execute(context.Background(), 7, 4, app.ListTransactionsInput{Symbol: "nope"})
+	_, errRange := uc.Execute(context.Background(), 7, 4, app.ListTransactionsInput{From: &to, To: &from})
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, out, 1)
+	require.Equal(t, "1.5", out[0].Quantity)
+	require.Equal(t, "10", out[0].Price)
+	require.Equal(t, "0", out[0].Fee)
+	require.NoError(t, errNone)
+	require.NotNil(t, none)
+	require.Empty(t, none)
+	require.ErrorIs(t, errRange, app.ErrBadRequest)
+}
+
+func TestUC27DeleteTransaction_BuyCoveringLaterSellIsInUse(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	day := func(d int) time.Time { return time.Date(2026, 3, d, 0, 0, 0, 0, time.UTC) }
+	buy1 := domain.Transaction{ID: 1, CoinID: 1, Type: domain.TransactionBuy, Quantity: "1", Price: "100", ExecutedAt: day(1)}
+	buy2 := domain.Transaction{ID: 2, CoinID: 1, Type: domain.TransactionBuy, Quantity: "1", Price: "100", ExecutedAt: day(2)}
+	sell := domain.Transaction{ID: 3, CoinID: 1, Type: domain.TransactionSell, Quantity: "1.5", Price: "120", ExecutedAt: day(3)}
+	all := []domain.Transaction{buy1, buy2, sell}
+
+	portfolios := mocks.NewMockPortfolioRepository(ctrl)
+	txs := mocks.NewMockTransactionRepository(ctrl)
+	portfolios.EXPECT().FindByUser(gomock.Any(), int64(7), int64(4)).Return(&domain.Portfolio{ID: 4}, nil).Times(3)
+	txs.EXPECT().FindByPortfolio(gomock.Any(), int64(4), int64(2)).Return(&buy2, nil)
+	txs.EXPECT().FindByPortfolio(gomock.Any(), int64(4), int64(3)).Return(&sell, nil)
+	txs.EXPECT().FindByPortfolio(gomock.Any(), int64(4), int64(9)).Return(nil, nil)
+	txs.EXPECT().ListByPortfolio(gomock.Any(), int64(4), domain.TransactionFilter{CoinID: 1}).Return(all, nil)
+	// borrar una salida nunca deja el libro en negativo: no hace falta revisarlo
+	txs.EXPECT().Delete(gomock.Any(), int64(4), int64(3)).Return(true, nil)
+
+	uc := app.DeleteTransactionUseCase{Portfolios: portfolios, Transactions: txs}
+
+	// Act
+	errInUse := uc.Execute(context.Background(), 7, 4, 2)
+	errSell := uc.Execute(context.Background(), 7, 4, 3)
+	errMissing := uc.Execute(context.Background(), 7, 4, 9)
+
+	// Assert
+	require.ErrorIs(t, errInUse, app.ErrTransactionInUse)
+	require.NoError(t, errSell)
+	require.ErrorIs(t, errMissing, app.ErrTransactionNotFound)
+}
+
+func TestUC27DeleteTransaction_UncoveredBuyIsDeleted(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	buy := domain.Transaction{ID: 1, CoinID: 1, Type: domain.TransactionBuy, Quantity: "1", Price: "100"}
+	portfolios := mocks.NewMockPortfolioRepository(ctrl)
+	txs := mocks.NewMockTransactionRepository(ctrl)
+	portfolios.EXPECT().FindByUser(gomock.Any(), int64(7), int64(4)).Return(&domain.Portfolio{ID: 4}, nil)
+	txs.EXPECT().FindByPortfolio(gomock.Any(), int64(4), int64(1)).Return(&buy, nil)
+	txs.EXPECT().ListByPortfolio(gomock.Any(), int64(4), domain.TransactionFilter{CoinID: 1}).Return([]domain.Transaction{buy}, nil)
+	txs.EXPECT().Delete(gomock.Any(), int64(4), int64(1)).Return(true, nil)
+
+	uc := app.DeleteTransactionUseCase{Portfolios: portfolios, Transactions: txs}
+
+	// Act
+	err := uc.Execute(context.Background(), 7, 4, 1)
+
+	// Assert
+	require.NoError(t, err)
+}
+
+func pnlTransactions() []domain.Transaction {
+	day := func(d int) time.Time { return time.Date(2026, 2, d, 0, 0, 0, 0, time.UTC) }
+	return []domain.Transaction{
+		{ID: 1, CoinID: 1, Symbol: "BTC", Type: domain.TransactionBuy, Quantity: "1", Price: "100", Fee: "1", Currency: "USD", ExecutedAt: day(1)},
+		{ID: 2, CoinID: 1, Symbol: "BTC", Type: domain.TransactionBuy, Quantity: "1", Price: "200", Fee: "1", Currency: "USD", ExecutedAt: day(2)},
+		{ID: 3, CoinID: 1, Symbol: "BTC", Type: domain.TransactionSell, Quantity: "1", Price: "250", Fee: "0", Currency: "USD", ExecutedAt: day(3)},
+		{ID: 4, CoinID: 2, Symbol: "ETH", Type: domain.TransactionBuy, Quantity: "2", Price: "10", Fee: "0", Currency: "USD", ExecutedAt: day(4)},
+	}
+}
+
+func TestUC27PortfolioPnL_FIFOValuesOpenLotsAgainstLatestQuote(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	portfolios := mocks.NewMockPortfolioRepository(ctrl)
+	txs := mocks.NewMockTransactionRepository(ctrl)
+	quotes := mocks.NewMockQuoteRepository(ctrl)
+
+	portfolios.EXPECT().FindByUser(gomock.Any(), int64(7), int64(4)).Return(&domain.Portfolio{ID: 4, BaseCurrency: "USD"}, nil)
+	txs.EXPECT().ListByPortfolio(gomock.Any(), int64(4), domain.TransactionFilter{}).Return(pnlTransactions(), nil)
+	quotes.EXPECT().GetLatest(gomock.Any(), "BTC", "", "USD").Return(&domain.PriceQuote{
+		Price: "300", Provider: "binance", Timestamp: now.Add(-time.Minute).Format(time.RFC3339),
+	}, nil)
+	quotes.EXPECT().GetLatest(gomock.Any(), "ETH", "", "USD").Return(nil, nil)
+
+	uc := app.PortfolioPnLUseCase{Portfolios: portfolios, Transactions: txs, QuoteRepo: quotes, Now: func() time.Time { return now }}
+
+	// Act
+	out, err := uc.Execute(context.Background(), 7, 4, "", "")
+
+	// Assert
+	require.NoError(t, err)
+	require.Equal(t, app.CostBasisFIFO, out.Method)
+	require.Equal(t, "USD", out.Currency)
+	require.Equal(t, "149", out.RealizedPnL) // 250 - 101
+	require.Equal(t, "221", out.CostBasis)   // 201 (BTC) + 20 (ETH)
+	require.Equal(t, "2", out.Fees)
+	require.Equal(t, "300", out.MarketValue) // ETH sin precio no suma
+	require.Equal(t, "99", out.UnrealizedPnL)
+	require.Equal(t, 1, out.MissingCount)
+	require.Equal(t, 0, out.StaleCount)
+	require.Len(t, out.Assets, 2)
+
+	btc := out.Assets[0]
+	require.Equal(t, "BTC", btc.Symbol)
+	require.Equal(t, "1", btc.Quantity)
+	require.Equal(t, "201", btc.AverageCost)
+	require.Equal(t, "300", btc.MarketValue)
+	require.Equal(t, "99", btc.UnrealizedPnL)
+	require.Equal(t, 49.2537, *btc.UnrealizedPercent)
+	require.Len(t, btc.OpenLots, 1)
+	require.Equal(t, int64(2), btc.OpenLots[0].TransactionID)
+	require.Equal(t, "201", btc.OpenLots[0].UnitCost)
+	require.Equal(t, "99", btc.OpenLots[0].UnrealizedPnL)
+
+	eth := out.Assets[1]
+	require.True(t, eth.PriceMissing)
+	require.Empty(t, eth.MarketValue)
+	require.Len(t, eth.OpenLots, 1)
+	require.Empty(t, eth.OpenLots[0].UnrealizedPnL)
+}
+
+func TestUC27PortfolioPnL_AverageMethodHasNoLots(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	portfolios := mocks.NewMockPortfolioRepository(ctrl)
+	txs := mocks.NewMockTransactionRepository(ctrl)
+	quotes := mocks.NewMockQuoteRepository(ctrl)
+
+	portfolios.EXPECT().FindByUser(gomock.Any(), int64(7), int64(4)).Return(&domain.Portfolio{ID: 4, BaseCurrency: "USD"}, nil)
+	txs.EXPECT().ListByPortfolio(gomock.Any(), int64(4), domain.TransactionFilter{}).Return(pnlTransactions(), nil)
+	quotes.EXPECT().GetLatest(gomock.Any(), gomock.Any(), "", "USD").Return(&domain.PriceQuote{
+		Price: "300", Timestamp: now.Add(-5 * time.Hour).Format(time.RFC3339),
+	}, nil).Times(2)
+
+	uc := app.PortfolioPnLUseCase{Portfolios: portfolios, Transactions: txs, QuoteRepo: quotes, Now: func() time.Time { return now }}
+
+	// Act
+	out, err := uc.Execute(context.Background(), 7, 4, "Average", "usd")
+
+	// Assert
+	require.NoError(t, err)
+	require.Equal(t, app.CostBasisAverage, out.Method)
+	require.Equal(t, "99", out.RealizedPnL) // 250 - 151
+	require.Equal(t, "151", out.Assets[0].CostBasis)
+	require.Empty(t, out.Assets[0].OpenLots)
+	require.Equal(t, 2, out.StaleCount)
+}
+
+func TestUC27PortfolioPnL_Rejections(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	portfolios := mocks.NewMockPortfolioRepository(ctrl)
+	txs := mocks.NewMockTransactionRepository(ctrl)
+	portfolios.EXPECT().FindByUser(gomock.Any(), int64(7), int64(4)).Return(&domain.Portfolio{ID: 4, BaseCurrency: "USD"}, nil)
+	txs.EXPECT().ListByPortfolio(gomock.Any(), int64(4), domain.TransactionFilter{}).Return(pnlTransactions(), nil)
+
+	uc := app.PortfolioPnLUseCase{Portfolios: portfolios, Transactions: txs, QuoteRepo: mocks.NewMockQuoteRepository(ctrl)}
+
+	// Act
+	_, errMethod := uc.Execute(context.Background(), 7, 4, "lifo", "")
+	_, errCurrency := uc.Execute(context.Background(), 7, 4, "", "EUR")
+
+	// Assert
+	require.ErrorIs(t, errMethod, app.ErrInvalidCostBasisMethod)
+	require.ErrorIs(t, errCurrency, app.ErrTransactionCurrencyMixed)
+}
@@ -0,0 +1,312 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/moondolphin/crypto-api/domain"
+)
+
+var (
+	ErrInvalidHistoryRange  = errors.New("invalid_history_range")
+	ErrHistoryRangeTooLarge = errors.New("history_range_too_large")
+)
+
+const (
+	defaultHistoryDays    = 30
+	defaultHistoryMaxDays = 366
+
+	// cuánto se arrastra el último cierre conocido a días sin cotización
+	defaultHistoryPriceLookback = 7 * 24 * time.Hour
+
+	dayLayout = "2006-01-02"
+)
+
+// utcDay es las 00:00 UTC del día de t.
+func utcDay(t time.Time) time.Time {
+	return t.UTC().Truncate(24 * time.Hour)
+}
+
+// invalidateSnapshots descarta los valores diarios guardados desde el día de
+// from (time.Time{} = todos). Se llama antes de cambiar tenencias o
+// movimientos: si la escritura después falla sólo se pierde el cache.
+func invalidateSnapshots(ctx context.Context, snapshots domain.PortfolioSnapshotRepository, portfolioID int64, from time.Time) error {
+	if snapshots == nil {
+		return nil
+	}
+	if !from.IsZero() {
+		from = utcDay(from)
+	}
+	return snapshots.DeleteFrom(ctx, portfolioID, from)
+}
+
+type PortfolioHistoryInput struct {
+	// días UTC, ambos incluidos. Default: los últimos 30 días hasta hoy
+	From *time.Time
+	To   *time.Time
+
+	// default: moneda base del portfolio
+	Currency string
+}
+
+type PortfolioHistoryPoint struct {
+	Date  string `json:"date"`
+	Value string `json:"value"`
+
+	// coins con cantidad ese día pero sin cotización cercana: no suman a value
+	Missing []string `json:"missing,omitempty"`
+}
+
+type PortfolioHistoryOutput struct {
+	PortfolioID int64                   `json:"portfolio_id"`
+	Currency    string                  `json:"currency"`
+	From        string                  `json:"from"`
+	To          string                  `json:"to"`
+	Points      []PortfolioHistoryPoint `json:"points"`
+}
+
+// PortfolioHistoryUseCase reconstruye el valor del portfolio al cierre de
+// cada día con las cotizaciones guardadas.
+//
+// La cantidad de cada coin sale de sus movimientos hasta el fin del día. Una
+// coin sin movimientos usa su tenencia actual en todo el rango (las tenencias
+// no tienen historia). El precio es el último del día en la moneda pedida; si
+// ese día no hubo, el último de hasta PriceLookback antes.
+//
+// Con Snapshots, los días pasados completos se guardan y no se recalculan
+// hasta que cambian las tenencias o un movimiento de esa fecha o anterior.
+// Cotizaciones cargadas después para un día ya guardado no se ven.
+type PortfolioHistoryUseCase struct {
+	Portfolios   domain.PortfolioRepository
+	Holdings     domain.HoldingRepository
+	Transactions domain.TransactionRepository
+	QuoteRepo    domain.QuoteRepository
+	Snapshots    domain.PortfolioSnapshotRepository // opcional
+	Now          func() time.Time
+
+	MaxDays       int
+	PriceLookback time.Duration
+}
+
+func (uc PortfolioHistoryUseCase) Execute(ctx context.Context, userID, portfolioID int64, in PortfolioHistoryInput) (PortfolioHistoryOutput, error) {
+	now := uc.Now
+	if now == nil {
+		now = time.Now
+	}
+	t := now().UTC()
+	today := utcDay(t)
+
+	maxDays := uc.MaxDays
+	if maxDays <= 0 {
+		maxDays = defaultHistoryMaxDays
+	}
+	lookback := uc.PriceLookback
+	if lookback <= 0 {
+		lookback = defaultHistoryPriceLookback
+	}
+
+	to := today
+	if in.To != nil {
+		to = utcDay(*in.To)
+	}
+	// no hay valores futuros: el rango se corta en hoy
+	if to.After(today) {
+		to = today
+	}
+	from := to.AddDate(0, 0, -(defaultHistoryDays - 1))
+	if in.From != nil {
+		from = utcDay(*in.From)
+	}
+	if from.After(to) {
+		return PortfolioHistoryOutput{}, ErrInvalidHistoryRange
+	}
+	days := int(to.Sub(from)/(24*time.Hour)) + 1
+	if days > maxDays {
+		return PortfolioHistoryOutput{}, ErrHistoryRangeTooLarge
+	}
+
+	p, err := findPortfolio(ctx, uc.Portfolios, userID, portfolioID)
+	if err != nil {
+		return PortfolioHistoryOutput{}, err
+	}
+	currency, err := normalizeCurrency(in.Currency, p.BaseCurrency)
+	if err != nil {
+		return PortfolioHistoryOutput{}, err
+	}
+
+	out := PortfolioHistoryOutput{
+		PortfolioID: p.ID,
+		Currency:    currency,
+		From:        from.Format(dayLayout),
+		To:          to.Format(dayLayout),
+		Points:      make([]PortfolioHistoryPoint, days),
+	}
+	done := make([]bool, days)
+
+	if uc.Snapshots != nil {
+		snaps, err := uc.Snapshots.ListRange(ctx, p.ID, currency, from, to)
+		if err != nil {
+			return PortfolioHistoryOutput{}, err
+		}
+		for _, s := range snaps {
+			day := utcDay(s.Day)
+			i := int(day.Sub(from) / (24 * time.Hour))
+			v, ok := parseDecimal(s.Value)
+			// el día de hoy todavía puede cambiar: siempre se recalcula
+			if i < 0 || i >= days || !day.Before(today) || !ok {
+				continue
+			}
+			out.Points[i] = PortfolioHistoryPoint{Date: day.Format(dayLayout), Value: formatDecimal(v, valueScale)}
+			done[i] = true
+		}
+	}
+
+	first, last := -1, -1
+	for i := range done {
+		if !done[i] {
+			if first < 0 {
+				first = i
+			}
+			last = i
+		}
+	}
+	if first < 0 {
+		return out, nil
+	}
+
+	firstDay := from.AddDate(0, 0, first)
+	end := from.AddDate(0, 0, last+1)
+
+	// todos los movimientos, también los posteriores al rango: una coin que
+	// tiene libro no usa su tenencia aunque compre después
+	txs, err := uc.Transactions.ListByPortfolio(ctx, p.ID, domain.TransactionFilter{})
+	if err != nil {
+		return PortfolioHistoryOutput{}, err
+	}
+	holdings, err := uc.Holdings.ListByPortfolio(ctx, p.ID)
+	if err != nil {
+		return PortfolioHistoryOutput{}, err
+	}
+
+	// coins con movimientos: la cantidad sale del libro; el resto, de la tenencia
+	withTxs := make(map[int64]bool)
+	for _, tx := range txs {
+		withTxs[tx.CoinID] = true
+	}
+	quantities := make(map[string]*big.Rat)
+	symbolSet := make(map[string]bool)
+	for _, tx := range txs {
+		symbolSet[tx.Symbol] = true
+		if quantities[tx.Symbol] == nil {
+			quantities[tx.Symbol] = new(big.Rat)
+		}
+	}
+	for _, h := range holdings {
+		if withTxs[h.CoinID] {
+			continue
+		}
+		q, ok := parseDecimal(h.Quantity)
+		if !ok {
+			continue
+		}
+		quantities[h.Symbol] = q
+		symbolSet[h.Symbol] = true
+	}
+
+	symbols := make([]string, 0, len(symbolSet))
+	for s := range symbolSet {
+		symbols = append(symbols, s)
+	}
+	sort.Strings(symbols)
+
+	closes, err := uc.QuoteRepo.ListDailyCloses(ctx, domain.DailyCloseFilter{
+		Symbols:  symbols,
+		Currency: currency,
+		From:     firstDay.Add(-lookback),
+		To:       end,
+	})
+	if err != nil {
+		return PortfolioHistoryOutput{}, err
+	}
+	bySymbol := make(map[string][]domain.DailyClose, len(symbols))
+	for _, c := range closes {
+		bySymbol[c.Symbol] = append(bySymbol[c.Symbol], c)
+	}
+	prices := make(map[string]*big.Rat, len(symbols))
+	priceDay := make(map[string]time.Time, len(symbols))
+	next := make(map[string]int, len(symbols))
+
+	applied := 0
+	for i := first; i <= last; i++ {
+		day := from.AddDate(0, 0, i)
+		dayEnd := day.AddDate(0, 0, 1)
+
+		for ; applied < len(txs) && txs[applied].ExecutedAt.Before(dayEnd); applied++ {
+			tx := txs[applied]
+			q, ok := parseDecimal(tx.Quantity)
+			if !ok {
+				continue
+			}
+			switch tx.Type {
+			case domain.TransactionBuy, domain.TransactionTransferIn:
+				quantities[tx.Symbol].Add(quantities[tx.Symbol], q)
+			case domain.TransactionSell, domain.TransactionTransferOut:
+				quantities[tx.Symbol].Sub(quantities[tx.Symbol], q)
+			}
+		}
+
+		// avanza el último cierre conocido de cada coin hasta este día
+		for _, s := range symbols {
+			list := bySymbol[s]
+			for j := next[s]; j < len(list) && !list[j].Day.After(day); j++ {
+				if price, ok := parseDecimal(list[j].Price); ok {
+					prices[s] = price
+					priceDay[s] = list[j].Day
+				}
+				next[s] = j + 1
+			}
+		}
+
+		if done[i] {
+			continue
+		}
+
+		value := new(big.Rat)
+		var missing []string
+		for _, s := range symbols {
+			q := quantities[s]
+			if q.Sign() <= 0 {
+				continue
+			}
+			price := prices[s]
+			if price == nil || day.Sub(priceDay[s]) > lookback {
+				missing = append(missing, s)
+				continue
+			}
+			value.Add(value, new(big.Rat).Mul(q, price))
+		}
+
+		point := PortfolioHistoryPoint{Date: day.Format(dayLayout), Value: formatDecimal(value, valueScale), Missing: missing}
+		out.Points[i] = point
+
+		if uc.Snapshots != nil && day.Before(today) && len(missing) == 0 {
+			err := uc.Snapshots.Upsert(ctx, domain.PortfolioSnapshot{
+				PortfolioID: p.ID,
+				Day:         day,
+				Currency:    currency,
+				Value:       value.FloatString(quantityScale),
+				ComputedAt:  t,
+			})
+			if err != nil {
+				// el valor ya está calculado: sin cache sólo se recalcula la próxima vez
+				log.Printf("Warning: portfolio %d snapshot %s: %v", p.ID, point.Date, err)
+			}
+		}
+	}
+
+	return out, nil
+}
@@ -0,0 +1,287 @@
+package app_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/moondolphin/crypto-api/app"
+	"github.com/moondolphin/crypto-api/domain"
+	"github.com/moondolphin/crypto-api/test/mocks"
+)
+
+func march(d int) time.Time { return time.Date(2026, 3, d, 0, 0, 0, 0, time.UTC) }
+
+func dailyClose(symbol string, day time.Time, price string) domain.DailyClose {
+	return domain.DailyClose{Symbol: symbol, Day: day, Provider: "binance", Price: price, QuotedAt: day.Add(23 * time.Hour)}
+}
+
+func TestUC28PortfolioHistory_ReconstructsFromTransactionsHoldingsAndCloses(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := march(5).Add(10 * time.Hour)
+	portfolios := mocks.NewMockPortfolioRepository(ctrl)
+	holdings := mocks.NewMockHoldingRepository(ctrl)
+	txs := mocks.NewMockTransactionRepository(ctrl)
+	quotes := mocks.NewMockQuoteRepository(ctrl)
+	snapshots := mocks.NewMockPortfolioSnapshotRepository(ctrl)
+
+	portfolios.EXPECT().FindByUser(gomock.Any(), int64(7), int64(4)).Return(&domain.Portfolio{ID: 4, BaseCurrency: "USD"}, nil)
+	snapshots.EXPECT().ListRange(gomock.Any(), int64(4), "USD", march(1), march(5)).Return(nil, nil)
+	txs.EXPECT().ListByPortfolio(gomock.Any(), int64(4), domain.TransactionFilter{}).Return([]domain.Transaction{
+		{ID: 1, CoinID: 1, Symbol: "BTC", Type: domain.TransactionBuy, Quantity: "1", ExecutedAt: march(1).AddDate(0, 0, -2)},
+		{ID: 2, CoinID: 1, Symbol: "BTC", Type: domain.TransactionBuy, Quantity: "1", ExecutedAt: march(2).Add(12 * time.Hour)},
+		{ID: 3, CoinID: 1, Symbol: "BTC", Type: domain.TransactionSell, Quantity: "0.5", ExecutedAt: march(4)},
+	}, nil)
+	holdings.EXPECT().ListByPortfolio(gomock.Any(), int64(4)).Return([]domain.Holding{
+		{CoinID: 1, Symbol: "BTC", Quantity: "99"}, // tiene libro: se ignora
+		{CoinID: 2, Symbol: "ETH", Quantity: "2"},
+	}, nil)
+	quotes.EXPECT().ListDailyCloses(gomock.Any(), domain.DailyCloseFilter{
+		Symbols:  []string{"BTC", "ETH"},
+		Currency: "USD",
+		From:     march(1).Add(-48 * time.Hour),
+		To:       march(6),
+	}).Return([]domain.DailyClose{
+		dailyClose("BTC", march(1).AddDate(0, 0, -1), "100"),
+		dailyClose("BTC", march(2), "200"),
+		dailyClose("BTC", march(4), "300"),
+		dailyClose("BTC", march(5), "310"),
+		dailyClose("ETH", march(1), "10"),
+	}, nil)
+
+	var saved []domain.PortfolioSnapshot
+	snapshots.EXPECT().Upsert(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, s domain.PortfolioSnapshot) error {
+		saved = append(saved, s)
+		return nil
+	}).AnyTimes()
+
+	uc := app.PortfolioHistoryUseCase{
+		Portfolios:    portfolios,
+		Holdings:      holdings,
+		Transactions:  txs,
+		QuoteRepo:     quotes,
+		Snapshots:     snapshots,
+		Now:           func() time.Time { return now },
+		PriceLookback: 48 * time.Hour,
+	}
+	from := march(1).Add(15 * time.Hour) // se toma el día
+	to := march(20)                      // futuro: se corta en hoy
+
+	// Act
+	out, err := uc.Execute(context.Background(), 7, 4, app.PortfolioHistoryInput{From: &from, To: &to})
+
+	// Assert
+	require.NoError(t, err)
+	require.Equal(t, "USD", out.Currency)
+	require.Equal(t, "2026-03-01", out.From)
+	require.Equal(t, "2026-03-05", out.To)
+	require.Equal(t, []app.PortfolioHistoryPoint{
+		{Date: "2026-03-01", Value: "120"}, // 1 BTC a 100 (cierre del día anterior) + 2 ETH a 10
+		{Date: "2026-03-02", Value: "420"}, // 2 BTC a 200 + 20
+		{Date: "2026-03-03", Value: "420"}, // sin cierres: se arrastran los anteriores
+		{Date: "2026-03-04", Value: "450", Missing: []string{"ETH"}},
+		{Date: "2026-03-05", Value: "465", Missing: []string{"ETH"}},
+	}, out.Points)
+
+	// sólo se guardan los días pasados completos
+	require.Len(t, saved, 3)
+	for i, want := range []string{"120", "420", "420"} {
+		require.Equal(t, march(i+1), saved[i].Day)
+		require.Equal(t, int64(4), saved[i].PortfolioID)
+		require.Equal(t, "USD", saved[i].Currency)
+		require.Equal(t, now, saved[i].ComputedAt)
+		require.Equal(t, want+".000000000000000000", saved[i].Value)
+	}
+}
+
+func TestUC28PortfolioHistory_UsesSnapshotsAndAlwaysRecomputesToday(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := march(3).Add(10 * time.Hour)
+	portfolios := mocks.NewMockPortfolioRepository(ctrl)
+	holdings := mocks.NewMockHoldingRepository(ctrl)
+	txs := mocks.NewMockTransactionRepository(ctrl)
+	quotes := mocks.NewMockQuoteRepository(ctrl)
+	snapshots := mocks.NewMockPortfolioSnapshotRepository(ctrl)
+
+	portfolios.EXPECT().FindByUser(gomock.Any(), int64(7), int64(4)).Return(&domain.Portfolio{ID: 4, BaseCurrency: "USD"}, nil)
+	snapshots.EXPECT().ListRange(gomock.Any(), int64(4), "EUR", march(1), march(3)).Return([]domain.PortfolioSnapshot{
+		{PortfolioID: 4, Day: march(1), Currency: "EUR", Value: "10.500000000000000000"},
+		{PortfolioID: 4, Day: march(2), Currency: "EUR", Value: "11"},
+		{PortfolioID: 4, Day: march(3), Currency: "EUR", Value: "999"}, // hoy: no se usa
+	}, nil)
+	txs.EXPECT().ListByPortfolio(gomock.Any(), int64(4), domain.TransactionFilter{}).Return(nil, nil)
+	holdings.EXPECT().ListByPortfolio(gomock.Any(), int64(4)).Return([]domain.Holding{{CoinID: 2, Symbol: "ETH", Quantity: "2"}}, nil)
+	quotes.EXPECT().ListDailyCloses(gomock.Any(), domain.DailyCloseFilter{
+		Symbols:  []string{"ETH"},
+		Currency: "EUR",
+		From:     march(3).Add(-7 * 24 * time.Hour),
+		To:       march(4),
+	}).Return([]domain.DailyClose{dailyClose("ETH", march(3), "6")}, nil)
+
+	uc := app.PortfolioHistoryUseCase{
+		Portfolios:   portfolios,
+		Holdings:     holdings,
+		Transactions: txs,
+		QuoteRepo:    quotes,
+		Snapshots:    snapshots,
+		Now:          func() time.Time { return now },
+	}
+	from := march(1)
+
+	// Act
+	out, err := uc.Execute(context.Background(), 7, 4, app.PortfolioHistoryInput{From: &from, Currency: "eur"})
+
+	// Assert: hoy no se guarda
+	require.NoError(t, err)
+	require.Equal(t, []app.PortfolioHistoryPoint{
+		{Date: "2026-03-01", Value: "10.5"},
+		{Date: "2026-03-02", Value: "11"},
+		{Date: "2026-03-03", Value: "12"},
+	}, out.Points)
+}
+
+func TestUC28PortfolioHistory_FullyCachedRangeSkipsRecomputation(t *testing.T) {
+	// Arrange: sin expectativas en movimientos, tenencias ni cotizaciones
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	portfolios := mocks.NewMockPortfolioRepository(ctrl)
+	snapshots := mocks.NewMockPortfolioSnapshotRepository(ctrl)
+	portfolios.EXPECT().FindByUser(gomock.Any(), int64(7), int64(4)).Return(&domain.Portfolio{ID: 4, BaseCurrency: "USD"}, nil)
+	snapshots.EXPECT().ListRange(gomock.Any(), int64(4), "USD", march(1), march(2)).Return([]domain.PortfolioSnapshot{
+		{Day: march(1), Value: "1"},
+		{Day: march(2), Value: "2"},
+	}, nil)
+
+	uc := app.PortfolioHistoryUseCase{
+		Portfolios:   portfolios,
+		Holdings:     mocks.NewMockHoldingRepository(ctrl),
+		Transactions: mocks.NewMockTransactionRepository(ctrl),
+		QuoteRepo:    mocks.NewMockQuoteRepository(ctrl),
+		Snapshots:    snapshots,
+		Now:          func() time.Time { return march(10) },
+	}
+	from, to := march(1), march(2)
+
+	// Act
+	out, err := uc.Execute(context.Background(), 7, 4, app.PortfolioHistoryInput{From: &from, To: &to})
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, out.Points, 2)
+	require.Equal(t, "2", out.Points[1].Value)
+}
+
+func TestUC28PortfolioHistory_RangeRules(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := march(31).Add(time.Hour)
+	ptr := func(t time.Time) *time.Time { return &t }
+
+	cases := map[string]struct {
+		in   app.PortfolioHistoryInput
+		want error
+	}{
+		"from after to":   {in: app.PortfolioHistoryInput{From: ptr(march(5)), To: ptr(march(4))}, want: app.ErrInvalidHistoryRange},
+		"too many days":   {in: app.PortfolioHistoryInput{From: ptr(march(1)), To: ptr(march(11))}, want: app.ErrHistoryRangeTooLarge},
+		"from in future":  {in: app.PortfolioHistoryInput{From: ptr(march(31).AddDate(0, 0, 1))}, want: app.ErrInvalidHistoryRange},
+		"default 30 days": {in: app.PortfolioHistoryInput{}, want: app.ErrHistoryRangeTooLarge},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			// Arrange: ninguna validación llega al repo
+			uc := app.PortfolioHistoryUseCase{
+				Portfolios: mocks.NewMockPortfolioRepository(ctrl),
+				Now:        func() time.Time { return now },
+				MaxDays:    10,
+			}
+
+			// Act
+			_, err := uc.Execute(context.Background(), 7, 4, tc.in)
+
+			// Assert
+			require.ErrorIs(t, err, tc.want)
+		})
+	}
+}
+
+func TestUC28PortfolioHistory_EmptyPortfolioIsZero(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	portfolios := mocks.NewMockPortfolioRepository(ctrl)
+	holdings := mocks.NewMockHoldingRepository(ctrl)
+	txs := mocks.NewMockTransactionRepository(ctrl)
+	quotes := mocks.NewMockQuoteRepository(ctrl)
+	portfolios.EXPECT().FindByUser(gomock.Any(), int64(7), int64(4)).Return(&domain.Portfolio{ID: 4, BaseCurrency: "USD"}, nil)
+	txs.EXPECT().ListByPortfolio(gomock.Any(), int64(4), gomock.Any()).Return(nil, nil)
+	holdings.EXPECT().ListByPortfolio(gomock.Any(), int64(4)).Return(nil, nil)
+	quotes.EXPECT().ListDailyCloses(gomock.Any(), gomock.Any()).Return(nil, nil)
+
+	uc := app.PortfolioHistoryUseCase{Portfolios: portfolios, Holdings: holdings, Transactions: txs, QuoteRepo: quotes, Now: func() time.Time { return march(31) }}
+
+	// Act
+	out, err := uc.Execute(context.Background(), 7, 4, app.PortfolioHistoryInput{})
+
+	// Assert: sin Snapshots no se guarda nada
+	require.NoError(t, err)
+	require.Len(t, out.Points, 30)
+	require.Equal(t, "2026-03-02", out.From)
+	require.Equal(t, app.PortfolioHistoryPoint{Date: "2026-03-31", Value: "0"}, out.Points[29])
+}
+
+func TestUC28Snapshots_InvalidatedByTransactionAndHoldingChanges(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	portfolios := mocks.NewMockPortfolioRepository(ctrl)
+	txs := mocks.NewMockTransactionRepository(ctrl)
+	holdings := mocks.NewMockHoldingRepository(ctrl)
+	coins := mocks.NewMockCoinRepository(ctrl)
+	snapshots := mocks.NewMockPortfolioSnapshotRepository(ctrl)
+
+	executedAt := march(2).Add(15 * time.Hour)
+	portfolios.EXPECT().FindByUser(gomock.Any(), int64(7), int64(4)).Return(&domain.Portfolio{ID: 4, BaseCurrency: "USD"}, nil).Times(3)
+	coins.EXPECT().GetEnabledBySymbol(gomock.Any(), "BTC").Return(&domain.Coin{ID: 1, Symbol: "BTC"}, nil).Times(2)
+	coins.EXPECT().GetBySymbol(gomock.Any(), "BTC").Return(&domain.Coin{ID: 1, Symbol: "BTC"}, nil)
+
+	gomock.InOrder(
+		snapshots.EXPECT().DeleteFrom(gomock.Any(), int64(4), march(2)).Return(nil),
+		txs.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, tx domain.Transaction) (domain.Transaction, error) { return tx, nil }),
+	)
+	gomock.InOrder(
+		snapshots.EXPECT().DeleteFrom(gomock.Any(), int64(4), time.Time{}).Return(nil),
+		holdings.EXPECT().Upsert(gomock.Any(), gomock.Any()).Return(domain.Holding{}, nil),
+	)
+	gomock.InOrder(
+		snapshots.EXPECT().DeleteFrom(gomock.Any(), int64(4), time.Time{}).Return(nil),
+		holdings.EXPECT().Delete(gomock.Any(), int64(4), int64(1)).Return(true, nil),
+	)
+
+	record := app.RecordTransactionUseCase{Portfolios: portfolios, Transactions: txs, CoinRepo: coins, Snapshots: snapshots, Now: func() time.Time { return march(3) }}
+	set := app.SetHoldingUseCase{Portfolios: portfolios, Holdings: holdings, CoinRepo: coins, Snapshots: snapshots}
+	del := app.DeleteHoldingUseCase{Portfolios: portfolios, Holdings: holdings, CoinRepo: coins, Snapshots: snapshots}
+
+	// Act
+	_, errRecord := record.Execute(context.Background(), 7, 4, app.RecordTransactionInput{Symbol: "BTC", Type: "buy", Quantity: "1", Price: "1", ExecutedAt: &executedAt})
+	_, errSet := set.Execute(context.Background(), 7, 4, "BTC", app.SetHoldingInput{Quantity: "1"})
+	errDel := del.Execute(context.Background(), 7, 4, "BTC")
+
+	// Assert
+	require.NoError(t, errRecord)
+	require.NoError(t, errSet)
+	require.NoError(t, errDel)
+}
@@ -0,0 +1,478 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/moondolphin/crypto-api/domain"
+)
+
+var (
+	ErrInvalidWatchlistName   = errors.New("invalid_watchlist_name")
+	ErrInvalidWatchlistNote   = errors.New("invalid_watchlist_note")
+	ErrInvalidWatchlistOrder  = errors.New("invalid_watchlist_order")
+	ErrWatchlistNotFound      = errors.New("watchlist_not_found")
+	ErrWatchlistNameTaken     = errors.New("watchlist_name_taken")
+	ErrWatchlistLimitReached  = errors.New("watchlist_limit_reached")
+	ErrDefaultWatchlist       = errors.New("default_watchlist_not_deletable")
+	ErrWatchlistEntryNotFound = errors.New("watchlist_entry_not_found")
+)
+
+const (
+	defaultMaxWatchlistsPerUser = 20
+
+	maxWatchlistNameLength = 100
+	maxWatchlistNoteLength = 500
+)
+
+type CreateWatchlistInput struct {
+	Name string `json:"name"`
+}
+
+type UpdateWatchlistInput struct {
+	Name string `json:"name"`
+}
+
+// SetWatchlistEntryInput: sin note, una coin que ya estaba conserva la suya;
+// "" la borra.
+type SetWatchlistEntryInput struct {
+	Note *string `json:"note,omitempty"`
+}
+
+// ReorderWatchlistInput trae todos los símbolos de la lista en el orden nuevo.
+type ReorderWatchlistInput struct {
+	Symbols []string `json:"symbols"`
+}
+
+type WatchlistOutput struct {
+	ID        int64     `json:"id"`
+	Name      string    `json:"name"`
+	IsDefault bool      `json:"is_default"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type WatchlistEntryOutput struct {
+	Symbol    string    `json:"symbol"`
+	Position  int       `json:"position"`
+	Note      string    `json:"note"`
+	AddedAt   time.Time `json:"added_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type WatchlistDetailOutput struct {
+	WatchlistOutput
+	Entries []WatchlistEntryOutput `json:"entries"`
+}
+
+func toWatchlistOutput(w domain.Watchlist) WatchlistOutput {
+	return WatchlistOutput{
+		ID:        w.ID,
+		Name:      w.Name,
+		IsDefault: w.IsDefault,
+		CreatedAt: w.CreatedAt,
+		UpdatedAt: w.UpdatedAt,
+	}
+}
+
+// toWatchlistEntryOutputs numera las entradas 0..n-1: las posiciones
+// guardadas pueden tener huecos.
+func toWatchlistEntryOutputs(entries []domain.WatchlistEntry) []WatchlistEntryOutput {
+	out := make([]WatchlistEntryOutput, 0, len(entries))
+	for i, e := range entries {
+		out = append(out, WatchlistEntryOutput{
+			Symbol:    e.Symbol,
+			Position:  i,
+			Note:      e.Note,
+			AddedAt:   e.CreatedAt,
+			UpdatedAt: e.UpdatedAt,
+		})
+	}
+	return out
+}
+
+func normalizeWatchlistName(raw string) (string, error) {
+	name := strings.TrimSpace(raw)
+	if name == "" || utf8.RuneCountInString(name) > maxWatchlistNameLength {
+		return "", ErrInvalidWatchlistName
+	}
+	return name, nil
+}
+
+func normalizeWatchlistNote(raw string) (string, error) {
+	note := strings.TrimSpace(raw)
+	if utf8.RuneCountInString(note) > maxWatchlistNoteLength {
+		return "", ErrInvalidWatchlistNote
+	}
+	return note, nil
+}
+
+// ensureDefaultWatchlist crea la lista por defecto si el usuario todavía no
+// tiene: así siempre existe antes que las demás y ocupa su nombre.
+func ensureDefaultWatchlist(ctx context.Context, watchlists domain.WatchlistRepository, userID int64, t time.Time) (domain.Watchlist, error) {
+	return watchlists.EnsureDefault(ctx, domain.Watchlist{
+		UserID:    userID,
+		Name:      domain.DefaultWatchlistName,
+		CreatedAt: t,
+		UpdatedAt: t,
+	})
+}
+
+// findWatchlist busca la lista del usuario: nil -> ErrWatchlistNotFound.
+func findWatchlist(ctx context.Context, watchlists domain.WatchlistRepository, userID, id int64) (*domain.Watchlist, error) {
+	if id <= 0 {
+		return nil, ErrBadRequest
+	}
+	w, err := watchlists.FindByUser(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if w == nil {
+		return nil, ErrWatchlistNotFound
+	}
+	return w, nil
+}
+
+// watchlistNameTaken compara sin distinguir mayúsculas; exceptID excluye a la
+// propia lista en un rename.
+func watchlistNameTaken(ctx context.Context, watchlists domain.WatchlistRepository, userID, exceptID int64, name string) (bool, error) {
+	list, err := watchlists.ListByUser(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	for _, w := range list {
+		if w.ID != exceptID && strings.EqualFold(w.Name, name) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// findWatchlistCoin resuelve el símbolo como las favoritas: cualquier coin
+// conocida, habilitada o no.
+func findWatchlistCoin(ctx context.Context, coins domain.CoinRepository, symbol string) (*domain.Coin, error) {
+	symbol = strings.ToUpper(strings.TrimSpace(symbol))
+	if symbol == "" {
+		return nil, ErrBadRequest
+	}
+	coin, err := coins.GetBySymbol(ctx, symbol)
+	if err != nil {
+		return nil, err
+	}
+	if coin == nil {
+		return nil, ErrCoinNotFound
+	}
+	return coin, nil
+}
+
+type ListWatchlistsUseCase struct {
+	Watchlists domain.WatchlistRepository
+	Now        func() time.Time
+}
+
+// Execute devuelve las listas del usuario, la por defecto primero.
+func (uc ListWatchlistsUseCase) Execute(ctx context.Context, userID int64) ([]WatchlistOutput, error) {
+	now := uc.Now
+	if now == nil {
+		now = time.Now
+	}
+	if _, err := ensureDefaultWatchlist(ctx, uc.Watchlists, userID, now().UTC()); err != nil {
+		return nil, err
+	}
+
+	list, err := uc.Watchlists.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]WatchlistOutput, 0, len(list))
+	for _, w := range list {
+		out = append(out, toWatchlistOutput(w))
+	}
+	return out, nil
+}
+
+type CreateWatchlistUseCase struct {
+	Watchlists domain.WatchlistRepository
+	Now        func() time.Time
+
+	MaxPerUser int
+}
+
+func (uc CreateWatchlistUseCase) Execute(ctx context.Context, userID int64, in CreateWatchlistInput) (WatchlistOutput, error) {
+	name, err := normalizeWatchlistName(in.Name)
+	if err != nil {
+		return WatchlistOutput{}, err
+	}
+
+	now := uc.Now
+	if now == nil {
+		now = time.Now
+	}
+	t := now().UTC()
+
+	if _, err := ensureDefaultWatchlist(ctx, uc.Watchlists, userID, t); err != nil {
+		return WatchlistOutput{}, err
+	}
+
+	limit := uc.MaxPerUser
+	if limit <= 0 {
+		limit = defaultMaxWatchlistsPerUser
+	}
+	n, err := uc.Watchlists.CountByUser(ctx, userID)
+	if err != nil {
+		return WatchlistOutput{}, err
+	}
+	if n >= limit {
+		return WatchlistOutput{}, ErrWatchlistLimitReached
+	}
+
+	taken, err := watchlistNameTaken(ctx, uc.Watchlists, userID, 0, name)
+	if err != nil {
+		return WatchlistOutput{}, err
+	}
+	if taken {
+		return WatchlistOutput{}, ErrWatchlistNameTaken
+	}
+
+	w, err := uc.Watchlists.Create(ctx, domain.Watchlist{
+		UserID:    userID,
+		Name:      name,
+		CreatedAt: t,
+		UpdatedAt: t,
+	})
+	if err != nil {
+		return WatchlistOutput{}, err
+	}
+	return toWatchlistOutput(w), nil
+}
+
+type GetWatchlistUseCase struct {
+	Watchlists domain.WatchlistRepository
+	Entries    domain.WatchlistEntryRepository
+}
+
+func (uc GetWatchlistUseCase) Execute(ctx context.Context, userID, id int64) (WatchlistDetailOutput, error) {
+	w, err := findWatchlist(ctx, uc.Watchlists, userID, id)
+	if err != nil {
+		return WatchlistDetailOutput{}, err
+	}
+
+	entries, err := uc.Entries.ListByWatchlist(ctx, w.ID)
+	if err != nil {
+		return WatchlistDetailOutput{}, err
+	}
+	return WatchlistDetailOutput{
+		WatchlistOutput: toWatchlistOutput(*w),
+		Entries:         toWatchlistEntryOutputs(entries),
+	}, nil
+}
+
+type UpdateWatchlistUseCase struct {
+	Watchlists domain.WatchlistRepository
+	Now        func() time.Time
+}
+
+// Execute renombra la lista; la por defecto también se puede renombrar.
+func (uc UpdateWatchlistUseCase) Execute(ctx context.Context, userID, id int64, in UpdateWatchlistInput) (WatchlistOutput, error) {
+	name, err := normalizeWatchlistName(in.Name)
+	if err != nil {
+		return WatchlistOutput{}, err
+	}
+
+	w, err := findWatchlist(ctx, uc.Watchlists, userID, id)
+	if err != nil {
+		return WatchlistOutput{}, err
+	}
+
+	taken, err := watchlistNameTaken(ctx, uc.Watchlists, userID, w.ID, name)
+	if err != nil {
+		return WatchlistOutput{}, err
+	}
+	if taken {
+		return WatchlistOutput{}, ErrWatchlistNameTaken
+	}
+
+	now := uc.Now
+	if now == nil {
+		now = time.Now
+	}
+	w.Name = name
+	w.UpdatedAt = now().UTC()
+
+	ok, err := uc.Watchlists.Update(ctx, *w)
+	if err != nil {
+		return WatchlistOutput{}, err
+	}
+	if !ok {
+		return WatchlistOutput{}, ErrWatchlistNotFound
+	}
+	return toWatchlistOutput(*w), nil
+}
+
+type DeleteWatchlistUseCase struct {
+	Watchlists domain.WatchlistRepository
+}
+
+// Execute borra la lista y sus entradas. La por defecto no se borra: es la
+// que respalda /users/me/favorites.
+func (uc DeleteWatchlistUseCase) Execute(ctx context.Context, userID, id int64) error {
+	w, err := findWatchlist(ctx, uc.Watchlists, userID, id)
+	if err != nil {
+		return err
+	}
+	if w.IsDefault {
+		return ErrDefaultWatchlist
+	}
+
+	ok, err := uc.Watchlists.Delete(ctx, userID, w.ID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrWatchlistNotFound
+	}
+	return nil
+}
+
+type SetWatchlistEntryUseCase struct {
+	Watchlists domain.WatchlistRepository
+	Entries    domain.WatchlistEntryRepository
+	CoinRepo   domain.CoinRepository
+	Now        func() time.Time
+}
+
+// Execute agrega symbol al final de la lista o, si ya estaba, le cambia la
+// nota (si vino). Es idempotente.
+func (uc SetWatchlistEntryUseCase) Execute(ctx context.Context, userID, watchlistID int64, symbol string, in SetWatchlistEntryInput) (WatchlistEntryOutput, error) {
+	var note string
+	if in.Note != nil {
+		n, err := normalizeWatchlistNote(*in.Note)
+		if err != nil {
+			return WatchlistEntryOutput{}, err
+		}
+		note = n
+	}
+
+	w, err := findWatchlist(ctx, uc.Watchlists, userID, watchlistID)
+	if err != nil {
+		return WatchlistEntryOutput{}, err
+	}
+	coin, err := findWatchlistCoin(ctx, uc.CoinRepo, symbol)
+	if err != nil {
+		return WatchlistEntryOutput{}, err
+	}
+
+	now := uc.Now
+	if now == nil {
+		now = time.Now
+	}
+	t := now().UTC()
+
+	added, err := uc.Entries.Add(ctx, domain.WatchlistEntry{
+		WatchlistID: w.ID,
+		CoinID:      coin.ID,
+		Note:        note,
+		CreatedAt:   t,
+		UpdatedAt:   t,
+	})
+	if err != nil {
+		return WatchlistEntryOutput{}, err
+	}
+	if !added && in.Note != nil {
+		if _, err := uc.Entries.UpdateNote(ctx, w.ID, coin.ID, note, t); err != nil {
+			return WatchlistEntryOutput{}, err
+		}
+	}
+
+	// se relee la lista para devolver la posición que quedó
+	entries, err := uc.Entries.ListByWatchlist(ctx, w.ID)
+	if err != nil {
+		return WatchlistEntryOutput{}, err
+	}
+	outs := toWatchlistEntryOutputs(entries)
+	for i, e := range entries {
+		if e.CoinID == coin.ID {
+			return outs[i], nil
+		}
+	}
+	// la borraron entre medio
+	return WatchlistEntryOutput{}, ErrWatchlistEntryNotFound
+}
+
+type RemoveWatchlistEntryUseCase struct {
+	Watchlists domain.WatchlistRepository
+	Entries    domain.WatchlistEntryRepository
+	CoinRepo   domain.CoinRepository
+}
+
+func (uc RemoveWatchlistEntryUseCase) Execute(ctx context.Context, userID, watchlistID int64, symbol string) error {
+	w, err := findWatchlist(ctx, uc.Watchlists, userID, watchlistID)
+	if err != nil {
+		return err
+	}
+	coin, err := findWatchlistCoin(ctx, uc.CoinRepo, symbol)
+	if err != nil {
+		return err
+	}
+
+	ok, err := uc.Entries.Remove(ctx, w.ID, coin.ID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrWatchlistEntryNotFound
+	}
+	return nil
+}
+
+type ReorderWatchlistUseCase struct {
+	Watchlists domain.WatchlistRepository
+	Entries    domain.WatchlistEntryRepository
+}
+
+// Execute ordena la lista según in.Symbols, que tiene que nombrar cada coin
+// de la lista exactamente una vez.
+func (uc ReorderWatchlistUseCase) Execute(ctx context.Context, userID, watchlistID int64, in ReorderWatchlistInput) (WatchlistDetailOutput, error) {
+	w, err := findWatchlist(ctx, uc.Watchlists, userID, watchlistID)
+	if err != nil {
+		return WatchlistDetailOutput{}, err
+	}
+
+	entries, err := uc.Entries.ListByWatchlist(ctx, w.ID)
+	if err != nil {
+		return WatchlistDetailOutput{}, err
+	}
+	if len(in.Symbols) != len(entries) {
+		return WatchlistDetailOutput{}, ErrInvalidWatchlistOrder
+	}
+
+	bySymbol := make(map[string]domain.WatchlistEntry, len(entries))
+	for _, e := range entries {
+		bySymbol[e.Symbol] = e
+	}
+	ordered := make([]domain.WatchlistEntry, 0, len(entries))
+	coinIDs := make([]int64, 0, len(entries))
+	for _, raw := range in.Symbols {
+		s := strings.ToUpper(strings.TrimSpace(raw))
+		e, ok := bySymbol[s]
+		if !ok {
+			// desconocido o repetido
+			return WatchlistDetailOutput{}, ErrInvalidWatchlistOrder
+		}
+		delete(bySymbol, s)
+		ordered = append(ordered, e)
+		coinIDs = append(coinIDs, e.CoinID)
+	}
+
+	if err := uc.Entries.Reorder(ctx, w.ID, coinIDs); err != nil {
+		return WatchlistDetailOutput{}, err
+	}
+	return WatchlistDetailOutput{
+		WatchlistOutput: toWatchlistOutput(*w),
+		Entries:         toWatchlistEntryOutputs(ordered),
+	}, nil
+}
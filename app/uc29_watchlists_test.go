@@ -0,0 +1,376 @@
+package app_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/moondolphin/crypto-api/app"
+	"github.com/moondolphin/crypto-api/domain"
+	"github.com/moondolphin/crypto-api/test/mocks"
+)
+
+func TestUC29ListWatchlists_EnsuresDefaultFirst(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	repo := mocks.NewMockWatchlistRepository(ctrl)
+	gomock.InOrder(
+		repo.EXPECT().EnsureDefault(gomock.Any(), domain.Watchlist{
+			UserID:    7,
+			Name:      domain.DefaultWatchlistName,
+			CreatedAt: now,
+			UpdatedAt: now,
+		}).Return(domain.Watchlist{ID: 1, UserID: 7, Name: "Favorites", IsDefault: true}, nil),
+		repo.EXPECT().ListByUser(gomock.Any(), int64(7)).Return([]domain.Watchlist{
+			{ID: 1, UserID: 7, Name: "Favorites", IsDefault: true},
+			{ID: 4, UserID: 7, Name: "Memes"},
+		}, nil),
+	)
+
+	uc := app.ListWatchlistsUseCase{Watchlists: repo, Now: func() time.Time { return now }}
+
+	// Act
+	out, err := uc.Execute(context.Background(), 7)
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, out, 2)
+	require.True(t, out[0].IsDefault)
+	require.Equal(t, "Memes", out[1].Name)
+}
+
+func TestUC29CreateWatchlist_Creates(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	repo := mocks.NewMockWatchlistRepository(ctrl)
+	repo.EXPECT().EnsureDefault(gomock.Any(), gomock.Any()).Return(domain.Watchlist{ID: 1, IsDefault: true}, nil)
+	repo.EXPECT().CountByUser(gomock.Any(), int64(7)).Return(1, nil)
+	repo.EXPECT().ListByUser(gomock.Any(), int64(7)).Return([]domain.Watchlist{{ID: 1, UserID: 7, Name: "Favorites", IsDefault: true}}, nil)
+	repo.EXPECT().Create(gomock.Any(), domain.Watchlist{
+		UserID:    7,
+		Name:      "Memes",
+		CreatedAt: now,
+		UpdatedAt: now,
+	}).DoAndReturn(func(_ context.Context, w domain.Watchlist) (domain.Watchlist, error) {
+		w.ID = 2
+		return w, nil
+	})
+
+	uc := app.CreateWatchlistUseCase{Watchlists: repo, Now: func() time.Time { return now }}
+
+	// Act
+	out, err := uc.Execute(context.Background(), 7, app.CreateWatchlistInput{Name: " Memes  "})
+
+	// Assert
+	require.NoError(t, err)
+	require.Equal(t, int64(2), out.ID)
+	require.False(t, out.IsDefault)
+}
+
+func TestUC29CreateWatchlist_Rejections(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cases := map[string]struct {
+		in    app.CreateWatchlistInput
+		count int
+		list  []domain.Watchlist
+		want  error
+	}{
+		"empty name":    {in: app.CreateWatchlistInput{Name: " "}, want: app.ErrInvalidWatchlistName},
+		"limit reached": {in: app.CreateWatchlistInput{Name: "W"}, count: 3, want: app.ErrWatchlistLimitReached},
+		"default name taken": {
+			in:   app.CreateWatchlistInput{Name: "favorites"},
+			list: []domain.Watchlist{{ID: 1, Name: "Favorites", IsDefault: true}},
+			want: app.ErrWatchlistNameTaken,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			// Arrange
+			repo := mocks.NewMockWatchlistRepository(ctrl)
+			repo.EXPECT().EnsureDefault(gomock.Any(), gomock.Any()).Return(domain.Watchlist{ID: 1, IsDefault: true}, nil).AnyTimes()
+			repo.EXPECT().CountByUser(gomock.Any(), gomock.Any()).Return(tc.count, nil).AnyTimes()
+			repo.EXPECT().ListByUser(gomock.Any(), gomock.Any()).Return(tc.list, nil).AnyTimes()
+
+			uc := app.CreateWatchlistUseCase{Watchlists: repo, MaxPerUser: 3}
+
+			// Act
+			_, err := uc.Execute(context.Background(), 7, tc.in)
+
+			// Assert
+			require.ErrorIs(t, err, tc.want)
+		})
+	}
+}
+
+func TestUC29UpdateWatchlist_RenamesDefault(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	def := domain.Watchlist{ID: 1, UserID: 7, Name: "Favorites", IsDefault: true}
+	repo := mocks.NewMockWatchlistRepository(ctrl)
+	repo.EXPECT().FindByUser(gomock.Any(), int64(7), int64(1)).Return(&def, nil)
+	repo.EXPECT().ListByUser(gomock.Any(), int64(7)).Return([]domain.Watchlist{def, {ID: 2, UserID: 7, Name: "Memes"}}, nil)
+	repo.EXPECT().Update(gomock.Any(), domain.Watchlist{ID: 1, UserID: 7, Name: "Principal", IsDefault: true, UpdatedAt: now}).Return(true, nil)
+
+	uc := app.UpdateWatchlistUseCase{Watchlists: repo, Now: func() time.Time { return now }}
+
+	// Act
+	out, err := uc.Execute(context.Background(), 7, 1, app.UpdateWatchlistInput{Name: "Principal"})
+
+	// Assert
+	require.NoError(t, err)
+	require.Equal(t, "Principal", out.Name)
+	require.True(t, out.IsDefault)
+}
+
+func TestUC29DeleteWatchlist_RejectsDefault(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockWatchlistRepository(ctrl)
+	repo.EXPECT().FindByUser(gomock.Any(), int64(7), int64(1)).Return(&domain.Watchlist{ID: 1, UserID: 7, IsDefault: true}, nil)
+	repo.EXPECT().Delete(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	uc := app.DeleteWatchlistUseCase{Watchlists: repo}
+
+	// Act
+	err := uc.Execute(context.Background(), 7, 1)
+
+	// Assert
+	require.ErrorIs(t, err, app.ErrDefaultWatchlist)
+}
+
+func TestUC29DeleteWatchlist_NotFound(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockWatchlistRepository(ctrl)
+	repo.EXPECT().FindByUser(gomock.Any(), int64(7), int64(9)).Return(nil, nil)
+
+	uc := app.DeleteWatchlistUseCase{Watchlists: repo}
+
+	// Act
+	err := uc.Execute(context.Background(), 7, 9)
+
+	// Assert
+	require.ErrorIs(t, err, app.ErrWatchlistNotFound)
+}
+
+func TestUC29SetWatchlistEntry_AddsAtEnd(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	watchlists := mocks.NewMockWatchlistRepository(ctrl)
+	entries := mocks.NewMockWatchlistEntryRepository(ctrl)
+	coins := mocks.NewMockCoinRepository(ctrl)
+
+	watchlists.EXPECT().FindByUser(gomock.Any(), int64(7), int64(2)).Return(&domain.Watchlist{ID: 2, UserID: 7}, nil)
+	coins.EXPECT().GetBySymbol(gomock.Any(), "ETH").Return(&domain.Coin{ID: 20, Symbol: "ETH"}, nil)
+	entries.EXPECT().Add(gomock.Any(), domain.WatchlistEntry{
+		WatchlistID: 2,
+		CoinID:      20,
+		Note:        "staking",
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}).Return(true, nil)
+	entries.EXPECT().UpdateNote(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+	entries.EXPECT().ListByWatchlist(gomock.Any(), int64(2)).Return([]domain.WatchlistEntry{
+		{WatchlistID: 2, CoinID: 10, Symbol: "BTC", Position: 0},
+		{WatchlistID: 2, CoinID: 20, Symbol: "ETH", Position: 3, Note: "staking", CreatedAt: now, UpdatedAt: now},
+	}, nil)
+
+	uc := app.SetWatchlistEntryUseCase{Watchlists: watchlists, Entries: entries, CoinRepo: coins, Now: func() time.Time { return now }}
+
+	// Act
+	out, err := uc.Execute(context.Background(), 7, 2, " eth", app.SetWatchlistEntryInput{Note: strPtr(" staking ")})
+
+	// Assert
+	require.NoError(t, err)
+	require.Equal(t, "ETH", out.Symbol)
+	require.Equal(t, 1, out.Position) // posiciones renumeradas sin huecos
+	require.Equal(t, "staking", out.Note)
+	require.Equal(t, now, out.AddedAt)
+}
+
+func TestUC29SetWatchlistEntry_ExistingEntry(t *testing.T) {
+	now := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	cases := map[string]struct {
+		note       *string
+		updateNote bool
+	}{
+		"without note keeps it": {note: nil, updateNote: false},
+		"empty note clears it":  {note: strPtr(""), updateNote: true},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			// Arrange
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			watchlists := mocks.NewMockWatchlistRepository(ctrl)
+			entries := mocks.NewMockWatchlistEntryRepository(ctrl)
+			coins := mocks.NewMockCoinRepository(ctrl)
+
+			watchlists.EXPECT().FindByUser(gomock.Any(), int64(7), int64(2)).Return(&domain.Watchlist{ID: 2, UserID: 7}, nil)
+			coins.EXPECT().GetBySymbol(gomock.Any(), "BTC").Return(&domain.Coin{ID: 10, Symbol: "BTC"}, nil)
+			entries.EXPECT().Add(gomock.Any(), gomock.Any()).Return(false, nil)
+			if tc.updateNote {
+				entries.EXPECT().UpdateNote(gomock.Any(), int64(2), int64(10), "", now).Return(true, nil)
+			}
+			entries.EXPECT().ListByWatchlist(gomock.Any(), int64(2)).Return([]domain.WatchlistEntry{{WatchlistID: 2, CoinID: 10, Symbol: "BTC"}}, nil)
+
+			uc := app.SetWatchlistEntryUseCase{Watchlists: watchlists, Entries: entries, CoinRepo: coins, Now: func() time.Time { return now }}
+
+			// Act
+			out, err := uc.Execute(context.Background(), 7, 2, "BTC", app.SetWatchlistEntryInput{Note: tc.note})
+
+			// Assert
+			require.NoError(t, err)
+			require.Equal(t, "BTC", out.Symbol)
+		})
+	}
+}
+
+func TestUC29SetWatchlistEntry_Rejections(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	long := make([]rune, 501)
+	for i := range long {
+		long[i] = 'x'
+	}
+
+	cases := map[string]struct {
+		symbol string
+		note   *string
+		list   *domain.Watchlist
+		coin   *domain.Coin
+		want   error
+	}{
+		"note too long":  {symbol: "BTC", note: strPtr(string(long)), want: app.ErrInvalidWatchlistNote},
+		"other's list":   {symbol: "BTC", want: app.ErrWatchlistNotFound},
+		"unknown coin":   {symbol: "NOPE", list: &domain.Watchlist{ID: 2}, want: app.ErrCoinNotFound},
+		"missing symbol": {symbol: " ", list: &domain.Watchlist{ID: 2}, want: app.ErrBadRequest},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			// Arrange
+			watchlists := mocks.NewMockWatchlistRepository(ctrl)
+			coins := mocks.NewMockCoinRepository(ctrl)
+			watchlists.EXPECT().FindByUser(gomock.Any(), gomock.Any(), gomock.Any()).Return(tc.list, nil).AnyTimes()
+			coins.EXPECT().GetBySymbol(gomock.Any(), gomock.Any()).Return(tc.coin, nil).AnyTimes()
+
+			uc := app.SetWatchlistEntryUseCase{Watchlists: watchlists, Entries: mocks.NewMockWatchlistEntryRepository(ctrl), CoinRepo: coins}
+
+			// Act
+			_, err := uc.Execute(context.Background(), 7, 2, tc.symbol, app.SetWatchlistEntryInput{Note: tc.note})
+
+			// Assert
+			require.ErrorIs(t, err, tc.want)
+		})
+	}
+}
+
+func TestUC29RemoveWatchlistEntry_NotInList(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	watchlists := mocks.NewMockWatchlistRepository(ctrl)
+	entries := mocks.NewMockWatchlistEntryRepository(ctrl)
+	coins := mocks.NewMockCoinRepository(ctrl)
+	watchlists.EXPECT().FindByUser(gomock.Any(), int64(7), int64(2)).Return(&domain.Watchlist{ID: 2, UserID: 7}, nil)
+	coins.EXPECT().GetBySymbol(gomock.Any(), "BTC").Return(&domain.Coin{ID: 10, Symbol: "BTC"}, nil)
+	entries.EXPECT().Remove(gomock.Any(), int64(2), int64(10)).Return(false, nil)
+
+	uc := app.RemoveWatchlistEntryUseCase{Watchlists: watchlists, Entries: entries, CoinRepo: coins}
+
+	// Act
+	err := uc.Execute(context.Background(), 7, 2, "btc")
+
+	// Assert
+	require.ErrorIs(t, err, app.ErrWatchlistEntryNotFound)
+}
+
+func TestUC29ReorderWatchlist_AppliesNewOrder(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	watchlists := mocks.NewMockWatchlistRepository(ctrl)
+	entries := mocks.NewMockWatchlistEntryRepository(ctrl)
+	watchlists.EXPECT().FindByUser(gomock.Any(), int64(7), int64(2)).Return(&domain.Watchlist{ID: 2, UserID: 7, Name: "Memes"}, nil)
+	entries.EXPECT().ListByWatchlist(gomock.Any(), int64(2)).Return([]domain.WatchlistEntry{
+		{WatchlistID: 2, CoinID: 10, Symbol: "BTC", Position: 0},
+		{WatchlistID: 2, CoinID: 20, Symbol: "ETH", Position: 1, Note: "staking"},
+		{WatchlistID: 2, CoinID: 30, Symbol: "SOL", Position: 5},
+	}, nil)
+	entries.EXPECT().Reorder(gomock.Any(), int64(2), []int64{30, 10, 20}).Return(nil)
+
+	uc := app.ReorderWatchlistUseCase{Watchlists: watchlists, Entries: entries}
+
+	// Act
+	out, err := uc.Execute(context.Background(), 7, 2, app.ReorderWatchlistInput{Symbols: []string{"sol", "BTC", " ETH"}})
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, out.Entries, 3)
+	require.Equal(t, "SOL", out.Entries[0].Symbol)
+	require.Equal(t, "ETH", out.Entries[2].Symbol)
+	require.Equal(t, 2, out.Entries[2].Position)
+	require.Equal(t, "staking", out.Entries[2].Note)
+}
+
+func TestUC29ReorderWatchlist_RejectsIncompleteOrder(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cases := map[string][]string{
+		"missing coin":   {"BTC"},
+		"duplicate coin": {"BTC", "BTC"},
+		"unknown coin":   {"BTC", "DOGE"},
+	}
+
+	for name, symbols := range cases {
+		t.Run(name, func(t *testing.T) {
+			// Arrange
+			watchlists := mocks.NewMockWatchlistRepository(ctrl)
+			entries := mocks.NewMockWatchlistEntryRepository(ctrl)
+			watchlists.EXPECT().FindByUser(gomock.Any(), gomock.Any(), gomock.Any()).Return(&domain.Watchlist{ID: 2}, nil)
+			entries.EXPECT().ListByWatchlist(gomock.Any(), int64(2)).Return([]domain.WatchlistEntry{
+				{CoinID: 10, Symbol: "BTC"},
+				{CoinID: 20, Symbol: "ETH"},
+			}, nil)
+			entries.EXPECT().Reorder(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+			uc := app.ReorderWatchlistUseCase{Watchlists: watchlists, Entries: entries}
+
+			// Act
+			_, err := uc.Execute(context.Background(), 7, 2, app.ReorderWatchlistInput{Symbols: symbols})
+
+			// Assert
+			require.ErrorIs(t, err, app.ErrInvalidWatchlistOrder)
+		})
+	}
+}
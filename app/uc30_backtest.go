@@ -0,0 +1,301 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/moondolphin/crypto-api/domain"
+)
+
+var (
+	ErrInvalidBacktestStrategy = errors.New("invalid_backtest_strategy")
+	ErrInvalidBacktestInterval = errors.New("invalid_backtest_interval")
+	ErrInvalidBacktestAmount   = errors.New("invalid_backtest_amount")
+	ErrInvalidBacktestRange    = errors.New("invalid_backtest_range")
+	ErrBacktestRangeTooLarge   = errors.New("backtest_range_too_large")
+)
+
+// Estrategias de backtest.
+const (
+	// Amount en cada fecha del calendario (Interval)
+	BacktestDCA = "dca"
+
+	// todo Amount el primer día
+	BacktestLumpSum = "lump_sum"
+)
+
+// Frecuencias de compra de DCA.
+const (
+	BacktestDaily   = "daily"
+	BacktestWeekly  = "weekly"
+	BacktestMonthly = "monthly"
+)
+
+const (
+	defaultBacktestDays     = 365
+	defaultBacktestMaxDays  = 1830
+	defaultBacktestCurrency = "USD"
+)
+
+type BacktestInput struct {
+	Symbol   string
+	Provider string // vacío = cualquiera (gana el primero por nombre en cada día)
+	Currency string // default USD
+
+	Strategy string
+	Interval string // sólo DCA; default weekly
+
+	// por compra en DCA, total en lump_sum
+	Amount string
+
+	// días UTC, ambos incluidos. Default: los últimos 365 días hasta hoy
+	From *time.Time
+	To   *time.Time
+}
+
+type BacktestPurchase struct {
+	// fecha del calendario y día en que se pudo comprar (el primero con
+	// cotización desde esa fecha)
+	Date       string `json:"date"`
+	ExecutedOn string `json:"executed_on"`
+
+	Price  string `json:"price"`
+	Amount string `json:"amount"`
+	Units  string `json:"units"`
+}
+
+type BacktestOutput struct {
+	Symbol   string `json:"symbol"`
+	Provider string `json:"provider,omitempty"`
+	Currency string `json:"currency"`
+	Strategy string `json:"strategy"`
+	Interval string `json:"interval,omitempty"`
+	From     string `json:"from"`
+	To       string `json:"to"`
+
+	Invested   string `json:"invested"`
+	Units      string `json:"units"`
+	FinalPrice string `json:"final_price"`
+	FinalValue string `json:"final_value"`
+	Return     string `json:"return"`
+
+	ReturnPercent      float64 `json:"return_percent"`
+	MaxDrawdownPercent float64 `json:"max_drawdown_percent"`
+
+	Purchases []BacktestPurchase `json:"purchases"`
+
+	// fechas del calendario sin cotización hasta la siguiente compra
+	Skipped []string `json:"skipped,omitempty"`
+}
+
+// BacktestUseCase simula compras sobre los cierres diarios guardados de un
+// símbolo en una moneda.
+//
+// Cada compra del calendario se hace al cierre del primer día con cotización
+// desde su fecha y antes de la compra siguiente; si no hay, se saltea. El
+// valor final usa el último cierre del rango.
+//
+// El drawdown se mide sobre valor/invertido día a día, así los aportes de DCA
+// no tapan las caídas: con lump_sum coincide con el drawdown del precio.
+type BacktestUseCase struct {
+	QuoteRepo domain.QuoteRepository
+	Now       func() time.Time
+
+	MaxDays int
+}
+
+func (uc BacktestUseCase) Execute(ctx context.Context, in BacktestInput) (BacktestOutput, error) {
+	now := uc.Now
+	if now == nil {
+		now = time.Now
+	}
+	today := utcDay(now())
+
+	maxDays := uc.MaxDays
+	if maxDays <= 0 {
+		maxDays = defaultBacktestMaxDays
+	}
+
+	symbol := strings.ToUpper(strings.TrimSpace(in.Symbol))
+	if symbol == "" {
+		return BacktestOutput{}, ErrBadRequest
+	}
+	provider := strings.ToLower(strings.TrimSpace(in.Provider))
+	currency, err := normalizeCurrency(in.Currency, defaultBacktestCurrency)
+	if err != nil {
+		return BacktestOutput{}, ErrBadRequest
+	}
+
+	strategy := strings.ToLower(strings.TrimSpace(in.Strategy))
+	interval := strings.ToLower(strings.TrimSpace(in.Interval))
+	switch strategy {
+	case BacktestDCA:
+		if interval == "" {
+			interval = BacktestWeekly
+		}
+		if interval != BacktestDaily && interval != BacktestWeekly && interval != BacktestMonthly {
+			return BacktestOutput{}, ErrInvalidBacktestInterval
+		}
+	case BacktestLumpSum:
+		interval = ""
+	default:
+		return BacktestOutput{}, ErrInvalidBacktestStrategy
+	}
+
+	amount, _, err := parseQuantity(in.Amount)
+	if err != nil {
+		return BacktestOutput{}, ErrInvalidBacktestAmount
+	}
+
+	to := today
+	if in.To != nil {
+		to = utcDay(*in.To)
+	}
+	if to.After(today) {
+		to = today
+	}
+	from := to.AddDate(0, 0, -(defaultBacktestDays - 1))
+	if in.From != nil {
+		from = utcDay(*in.From)
+	}
+	if from.After(to) {
+		return BacktestOutput{}, ErrInvalidBacktestRange
+	}
+	if days := int(to.Sub(from)/(24*time.Hour)) + 1; days > maxDays {
+		return BacktestOutput{}, ErrBacktestRangeTooLarge
+	}
+	end := to.AddDate(0, 0, 1)
+
+	closes, err := uc.QuoteRepo.ListDailyCloses(ctx, domain.DailyCloseFilter{
+		Symbols:  []string{symbol},
+		Provider: provider,
+		Currency: currency,
+		From:     from,
+		To:       end,
+	})
+	if err != nil {
+		return BacktestOutput{}, err
+	}
+	prices := make([]*big.Rat, 0, len(closes))
+	days := make([]time.Time, 0, len(closes))
+	for _, c := range closes {
+		if p, ok := parseDecimal(c.Price); ok && p.Sign() > 0 {
+			prices = append(prices, p)
+			days = append(days, utcDay(c.Day))
+		}
+	}
+	if len(prices) == 0 {
+		return BacktestOutput{}, ErrQuoteNotFound
+	}
+
+	schedule := backtestSchedule(strategy, interval, from, end)
+
+	out := BacktestOutput{
+		Symbol:    symbol,
+		Provider:  provider,
+		Currency:  currency,
+		Strategy:  strategy,
+		Interval:  interval,
+		From:      from.Format(dayLayout),
+		To:        to.Format(dayLayout),
+		Purchases: make([]BacktestPurchase, 0, len(schedule)),
+	}
+
+	// compras: índice del cierre en que se ejecuta cada una
+	type purchase struct {
+		close int
+		units *big.Rat
+	}
+	var buys []purchase
+	next := 0
+	for i, day := range schedule {
+		limit := end
+		if i+1 < len(schedule) {
+			limit = schedule[i+1]
+		}
+		for next < len(days) && days[next].Before(day) {
+			next++
+		}
+		if next >= len(days) || !days[next].Before(limit) {
+			out.Skipped = append(out.Skipped, day.Format(dayLayout))
+			continue
+		}
+		units := new(big.Rat).Quo(amount, prices[next])
+		buys = append(buys, purchase{close: next, units: units})
+		out.Purchases = append(out.Purchases, BacktestPurchase{
+			Date:       day.Format(dayLayout),
+			ExecutedOn: days[next].Format(dayLayout),
+			Price:      formatDecimal(prices[next], valueScale),
+			Amount:     formatDecimal(amount, valueScale),
+			Units:      formatDecimal(units, quantityScale),
+		})
+	}
+
+	invested := new(big.Rat)
+	units := new(big.Rat)
+	var peak *big.Rat
+	maxDrawdown := new(big.Rat)
+	b := 0
+	for i, price := range prices {
+		for ; b < len(buys) && buys[b].close == i; b++ {
+			invested.Add(invested, amount)
+			units.Add(units, buys[b].units)
+		}
+		if invested.Sign() == 0 {
+			continue
+		}
+		ratio := new(big.Rat).Quo(new(big.Rat).Mul(units, price), invested)
+		if peak == nil || ratio.Cmp(peak) > 0 {
+			peak = ratio
+			continue
+		}
+		if dd := new(big.Rat).Quo(new(big.Rat).Sub(peak, ratio), peak); dd.Cmp(maxDrawdown) > 0 {
+			maxDrawdown = dd
+		}
+	}
+
+	finalPrice := prices[len(prices)-1]
+	value := new(big.Rat).Mul(units, finalPrice)
+	ret := new(big.Rat).Sub(value, invested)
+
+	out.Invested = formatDecimal(invested, valueScale)
+	out.Units = formatDecimal(units, quantityScale)
+	out.FinalPrice = formatDecimal(finalPrice, valueScale)
+	out.FinalValue = formatDecimal(value, valueScale)
+	out.Return = formatDecimal(ret, valueScale)
+	out.ReturnPercent = allocationPercent(ret, invested)
+	out.MaxDrawdownPercent = allocationPercent(maxDrawdown, big.NewRat(1, 1))
+	return out, nil
+}
+
+// backtestSchedule arma las fechas de compra en [from, end). Mensual cae el
+// mismo día del mes que from, o el último si el mes es más corto.
+func backtestSchedule(strategy, interval string, from, end time.Time) []time.Time {
+	if strategy == BacktestLumpSum {
+		return []time.Time{from}
+	}
+
+	var out []time.Time
+	for k := 0; ; k++ {
+		var day time.Time
+		switch interval {
+		case BacktestDaily:
+			day = from.AddDate(0, 0, k)
+		case BacktestWeekly:
+			day = from.AddDate(0, 0, 7*k)
+		default:
+			day = from.AddDate(0, k, 0)
+			if day.Day() != from.Day() {
+				// se pasó al mes siguiente: último día del mes buscado
+				day = day.AddDate(0, 0, -day.Day())
+			}
+		}
+		if !day.Before(end) {
+			return out
+		}
+		out = append(out, day)
+	}
+}
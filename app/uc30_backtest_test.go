@@ -0,0 +1,200 @@
+package app_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/moondolphin/crypto-api/app"
+	"github.com/moondolphin/crypto-api/domain"
+	"github.com/moondolphin/crypto-api/test/mocks"
+)
+
+func TestUC30Backtest_DCA_WeeklyWithMissingDays(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	quotes := mocks.NewMockQuoteRepository(ctrl)
+	quotes.EXPECT().ListDailyCloses(gomock.Any(), domain.DailyCloseFilter{
+		Symbols:  []string{"ETH"},
+		Provider: "binance",
+		Currency: "USDT",
+		From:     march(1),
+		To:       march(16),
+	}).Return([]domain.DailyClose{
+		dailyClose("ETH", march(1), "100"),
+		dailyClose("ETH", march(2), "50"),
+		dailyClose("ETH", march(9), "200"), // el 8 no hay: compra el 9
+		dailyClose("ETH", march(14), "100"),
+	}, nil)
+
+	uc := app.BacktestUseCase{QuoteRepo: quotes, Now: func() time.Time { return march(20) }}
+	from, to := march(1), march(15)
+
+	// Act
+	out, err := uc.Execute(context.Background(), app.BacktestInput{
+		Symbol:   " eth ",
+		Provider: "Binance",
+		Currency: "usdt",
+		Strategy: app.BacktestDCA,
+		Amount:   "100",
+		From:     &from,
+		To:       &to,
+	})
+
+	// Assert
+	require.NoError(t, err)
+	require.Equal(t, "ETH", out.Symbol)
+	require.Equal(t, app.BacktestWeekly, out.Interval)
+	require.Equal(t, []app.BacktestPurchase{
+		{Date: "2026-03-01", ExecutedOn: "2026-03-01", Price: "100", Amount: "100", Units: "1"},
+		{Date: "2026-03-08", ExecutedOn: "2026-03-09", Price: "200", Amount: "100", Units: "0.5"},
+	}, out.Purchases)
+	require.Equal(t, []string{"2026-03-15"}, out.Skipped)
+
+	require.Equal(t, "200", out.Invested)
+	require.Equal(t, "1.5", out.Units)
+	require.Equal(t, "100", out.FinalPrice)
+	require.Equal(t, "150", out.FinalValue)
+	require.Equal(t, "-50", out.Return)
+	require.InDelta(t, -25, out.ReturnPercent, 1e-9)
+	require.InDelta(t, 50, out.MaxDrawdownPercent, 1e-9)
+}
+
+func TestUC30Backtest_LumpSum_DefaultRangeAndCurrency(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := march(20).Add(15 * time.Hour)
+	from := march(20).AddDate(0, 0, -364)
+
+	quotes := mocks.NewMockQuoteRepository(ctrl)
+	quotes.EXPECT().ListDailyCloses(gomock.Any(), domain.DailyCloseFilter{
+		Symbols:  []string{"BTC"},
+		Currency: "USD",
+		From:     from,
+		To:       march(21),
+	}).Return([]domain.DailyClose{
+		dailyClose("BTC", march(1), "100"),
+		dailyClose("BTC", march(5), "80"),
+		dailyClose("BTC", march(10), "120"),
+	}, nil)
+
+	uc := app.BacktestUseCase{QuoteRepo: quotes, Now: func() time.Time { return now }}
+
+	// Act
+	out, err := uc.Execute(context.Background(), app.BacktestInput{Symbol: "BTC", Strategy: app.BacktestLumpSum, Amount: "1000"})
+
+	// Assert
+	require.NoError(t, err)
+	require.Equal(t, from.Format("2006-01-02"), out.From)
+	require.Equal(t, "2026-03-20", out.To)
+	require.Empty(t, out.Interval)
+	require.Len(t, out.Purchases, 1)
+	require.Equal(t, "2026-03-01", out.Purchases[0].ExecutedOn)
+	require.Equal(t, "10", out.Units)
+	require.Equal(t, "1200", out.FinalValue)
+	require.Equal(t, "200", out.Return)
+	require.InDelta(t, 20, out.ReturnPercent, 1e-9)
+	require.InDelta(t, 20, out.MaxDrawdownPercent, 1e-9)
+}
+
+func TestUC30Backtest_DCA_MonthlyClampsToMonthEnd(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	jan31 := time.Date(2026, 1, 31, 0, 0, 0, 0, time.UTC)
+	feb28 := time.Date(2026, 2, 28, 0, 0, 0, 0, time.UTC)
+	mar31 := time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC)
+
+	quotes := mocks.NewMockQuoteRepository(ctrl)
+	quotes.EXPECT().ListDailyCloses(gomock.Any(), gomock.Any()).Return([]domain.DailyClose{
+		dailyClose("BTC", jan31, "10"),
+		dailyClose("BTC", feb28, "20"),
+		dailyClose("BTC", mar31, "40"),
+	}, nil)
+
+	uc := app.BacktestUseCase{QuoteRepo: quotes, Now: func() time.Time { return mar31 }}
+
+	// Act
+	out, err := uc.Execute(context.Background(), app.BacktestInput{
+		Symbol:   "BTC",
+		Strategy: app.BacktestDCA,
+		Interval: app.BacktestMonthly,
+		Amount:   "40",
+		From:     &jan31,
+	})
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, out.Purchases, 3)
+	require.Equal(t, "2026-02-28", out.Purchases[1].Date)
+	require.Equal(t, "2026-03-31", out.Purchases[2].Date)
+	require.Equal(t, "120", out.Invested)
+	require.Equal(t, "7", out.Units)
+	require.Equal(t, "280", out.FinalValue)
+	require.Zero(t, out.MaxDrawdownPercent)
+}
+
+func TestUC30Backtest_NoQuotes_ReturnsQuoteNotFound(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	quotes := mocks.NewMockQuoteRepository(ctrl)
+	quotes.EXPECT().ListDailyCloses(gomock.Any(), gomock.Any()).Return(nil, nil)
+
+	uc := app.BacktestUseCase{QuoteRepo: quotes, Now: func() time.Time { return march(20) }}
+
+	// Act
+	_, err := uc.Execute(context.Background(), app.BacktestInput{Symbol: "BTC", Strategy: app.BacktestLumpSum, Amount: "1"})
+
+	// Assert
+	require.ErrorIs(t, err, app.ErrQuoteNotFound)
+}
+
+func TestUC30Backtest_InvalidInput(t *testing.T) {
+	from, to := march(1), march(10)
+	longFrom := march(10).AddDate(-1, 0, 0)
+
+	cases := []struct {
+		name string
+		in   app.BacktestInput
+		want error
+	}{
+		{"missing symbol", app.BacktestInput{Strategy: app.BacktestLumpSum, Amount: "1"}, app.ErrBadRequest},
+		{"bad currency", app.BacktestInput{Symbol: "BTC", Currency: "U$D", Strategy: app.BacktestLumpSum, Amount: "1"}, app.ErrBadRequest},
+		{"bad strategy", app.BacktestInput{Symbol: "BTC", Strategy: "yolo", Amount: "1"}, app.ErrInvalidBacktestStrategy},
+		{"bad interval", app.BacktestInput{Symbol: "BTC", Strategy: app.BacktestDCA, Interval: "hourly", Amount: "1"}, app.ErrInvalidBacktestInterval},
+		{"zero amount", app.BacktestInput{Symbol: "BTC", Strategy: app.BacktestLumpSum, Amount: "0"}, app.ErrInvalidBacktestAmount},
+		{"bad amount", app.BacktestInput{Symbol: "BTC", Strategy: app.BacktestLumpSum, Amount: "-5"}, app.ErrInvalidBacktestAmount},
+		{"inverted range", app.BacktestInput{Symbol: "BTC", Strategy: app.BacktestLumpSum, Amount: "1", From: &to, To: &from}, app.ErrInvalidBacktestRange},
+		{"range too large", app.BacktestInput{Symbol: "BTC", Strategy: app.BacktestLumpSum, Amount: "1", From: &longFrom, To: &to}, app.ErrBacktestRangeTooLarge},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			uc := app.BacktestUseCase{
+				QuoteRepo: mocks.NewMockQuoteRepository(ctrl),
+				Now:       func() time.Time { return march(20) },
+				MaxDays:   100,
+			}
+
+			// Act
+			_, err := uc.Execute(context.Background(), tc.in)
+
+			// Assert
+			require.ErrorIs(t, err, tc.want)
+		})
+	}
+}
@@ -0,0 +1,384 @@
+package app
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"io"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/moondolphin/crypto-api/domain"
+)
+
+var ErrInvalidTaxYear = errors.New("invalid_tax_year")
+
+// Plazo de tenencia de una disposición (sólo FIFO, que conoce la fecha de
+// compra de cada lote).
+const (
+	TaxTermShort = "short"
+	TaxTermLong  = "long" // más de un año
+)
+
+// De dónde sale el costo del lote de una disposición.
+const (
+	// precio cargado en el movimiento
+	CostSourceTransaction = "transaction"
+
+	// transfer_in sin precio: cierre del día de la entrada
+	CostSourceFairMarketValue = "fair_market_value"
+
+	// transfer_in sin precio ni cotización cercana: costo 0
+	CostSourceMissing = "missing"
+)
+
+type TaxReportInput struct {
+	Year     int    // default: año en curso (UTC)
+	Method   string // fifo (default) | average
+	Currency string // default: moneda base del portfolio
+}
+
+// TaxDisposalOutput es la parte de una venta que consumió un lote (con
+// average, la venta entera contra el pool).
+type TaxDisposalOutput struct {
+	Symbol        string    `json:"symbol"`
+	TransactionID int64     `json:"transaction_id"`
+	DisposedAt    time.Time `json:"disposed_at"`
+
+	LotTransactionID int64      `json:"lot_transaction_id,omitempty"`
+	AcquiredAt       *time.Time `json:"acquired_at,omitempty"`
+	HoldingDays      *int       `json:"holding_days,omitempty"`
+	Term             string     `json:"term,omitempty"`
+
+	Quantity  string `json:"quantity"`
+	Proceeds  string `json:"proceeds"`
+	CostBasis string `json:"cost_basis"`
+	Gain      string `json:"gain"`
+
+	// el lote no se consumió entero en esta venta; LotRemaining es lo que le
+	// quedó abierto después
+	Partial         bool   `json:"partial,omitempty"`
+	LotRemaining    string `json:"lot_remaining,omitempty"`
+	CostBasisSource string `json:"cost_basis_source,omitempty"`
+}
+
+type TaxReportOutput struct {
+	PortfolioID int64  `json:"portfolio_id"`
+	Year        int    `json:"year"`
+	Currency    string `json:"currency"`
+	Method      string `json:"method"`
+
+	Proceeds  string `json:"proceeds"`
+	CostBasis string `json:"cost_basis"`
+	Gain      string `json:"gain"`
+
+	// sólo FIFO
+	ShortTermGain string `json:"short_term_gain,omitempty"`
+	LongTermGain  string `json:"long_term_gain,omitempty"`
+
+	Disposals []TaxDisposalOutput `json:"disposals"`
+}
+
+// TaxReportUseCase lista las ventas del año (UTC) con el costo de lo vendido.
+//
+// El libro se arma con todos los movimientos del portfolio, como el P&L, así
+// los lotes consumidos en años anteriores no vuelven a contar. Un transfer_in
+// cargado sin precio toma como costo el cierre guardado del día de la entrada
+// (o el último de hasta PriceLookback antes); sin cotización queda en 0 y la
+// disposición lo marca como missing. Las transferencias de salida no son
+// ventas y no se listan.
+type TaxReportUseCase struct {
+	Portfolios   domain.PortfolioRepository
+	Transactions domain.TransactionRepository
+	QuoteRepo    domain.QuoteRepository
+	Now          func() time.Time
+
+	PriceLookback time.Duration
+}
+
+func (uc TaxReportUseCase) Execute(ctx context.Context, userID, portfolioID int64, in TaxReportInput) (TaxReportOutput, error) {
+	now := uc.Now
+	if now == nil {
+		now = time.Now
+	}
+	current := now().UTC().Year()
+
+	year := in.Year
+	if year == 0 {
+		year = current
+	}
+	if year < 1970 || year > current {
+		return TaxReportOutput{}, ErrInvalidTaxYear
+	}
+
+	method := strings.ToLower(strings.TrimSpace(in.Method))
+	if method == "" {
+		method = CostBasisFIFO
+	}
+	if !IsValidCostBasisMethod(method) {
+		return TaxReportOutput{}, ErrInvalidCostBasisMethod
+	}
+
+	p, err := findPortfolio(ctx, uc.Portfolios, userID, portfolioID)
+	if err != nil {
+		return TaxReportOutput{}, err
+	}
+	currency, err := normalizeCurrency(in.Currency, p.BaseCurrency)
+	if err != nil {
+		return TaxReportOutput{}, err
+	}
+
+	txs, err := uc.Transactions.ListByPortfolio(ctx, p.ID, domain.TransactionFilter{})
+	if err != nil {
+		return TaxReportOutput{}, err
+	}
+	for _, t := range txs {
+		if t.Currency != currency {
+			return TaxReportOutput{}, ErrTransactionCurrencyMixed
+		}
+	}
+
+	txs, sources, err := uc.fillFairMarketValues(ctx, txs, currency)
+	if err != nil {
+		return TaxReportOutput{}, err
+	}
+
+	ledgers, err := BuildLedger(txs, method)
+	if err != nil {
+		return TaxReportOutput{}, err
+	}
+
+	// cantidad original de cada lote, para saber cuánto le queda tras cada venta
+	lotQuantity := make(map[int64]*big.Rat, len(txs))
+	for _, t := range txs {
+		if q, ok := parseDecimal(t.Quantity); ok {
+			lotQuantity[t.ID] = q
+		}
+	}
+
+	start := time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(1, 0, 0)
+
+	out := TaxReportOutput{
+		PortfolioID: p.ID,
+		Year:        year,
+		Currency:    currency,
+		Method:      method,
+		Disposals:   make([]TaxDisposalOutput, 0),
+	}
+	var (
+		proceeds  = new(big.Rat)
+		costBasis = new(big.Rat)
+		gain      = new(big.Rat)
+		shortTerm = new(big.Rat)
+		longTerm  = new(big.Rat)
+	)
+
+	for _, l := range ledgers {
+		consumed := make(map[int64]*big.Rat)
+		for _, m := range l.Matches {
+			var remaining *big.Rat
+			if m.LotTransactionID != 0 && lotQuantity[m.LotTransactionID] != nil {
+				if consumed[m.LotTransactionID] == nil {
+					consumed[m.LotTransactionID] = new(big.Rat)
+				}
+				consumed[m.LotTransactionID].Add(consumed[m.LotTransactionID], m.Quantity)
+				remaining = new(big.Rat).Sub(lotQuantity[m.LotTransactionID], consumed[m.LotTransactionID])
+			}
+
+			disposedAt := m.DisposedAt.UTC()
+			if m.Type != domain.TransactionSell || disposedAt.Before(start) || !disposedAt.Before(end) {
+				continue
+			}
+
+			g := m.Gain()
+			d := TaxDisposalOutput{
+				Symbol:        l.Symbol,
+				TransactionID: m.TransactionID,
+				DisposedAt:    disposedAt,
+				Quantity:      formatDecimal(m.Quantity, quantityScale),
+				Proceeds:      formatDecimal(m.Proceeds, valueScale),
+				CostBasis:     formatDecimal(m.CostBasis, valueScale),
+				Gain:          formatDecimal(g, valueScale),
+			}
+			proceeds.Add(proceeds, m.Proceeds)
+			costBasis.Add(costBasis, m.CostBasis)
+			gain.Add(gain, g)
+
+			if m.LotTransactionID != 0 {
+				acquiredAt := m.AcquiredAt.UTC()
+				days := int(disposedAt.Sub(acquiredAt) / (24 * time.Hour))
+				d.LotTransactionID = m.LotTransactionID
+				d.AcquiredAt = &acquiredAt
+				d.HoldingDays = &days
+				d.Term = TaxTermShort
+				if disposedAt.After(acquiredAt.AddDate(1, 0, 0)) {
+					d.Term = TaxTermLong
+					longTerm.Add(longTerm, g)
+				} else {
+					shortTerm.Add(shortTerm, g)
+				}
+				d.CostBasisSource = sources[m.LotTransactionID]
+				if remaining != nil && m.Quantity.Cmp(lotQuantity[m.LotTransactionID]) < 0 {
+					d.Partial = true
+					d.LotRemaining = formatDecimal(remaining, quantityScale)
+				}
+			}
+			out.Disposals = append(out.Disposals, d)
+		}
+	}
+
+	sort.SliceStable(out.Disposals, func(i, j int) bool {
+		a, b := out.Disposals[i], out.Disposals[j]
+		if !a.DisposedAt.Equal(b.DisposedAt) {
+			return a.DisposedAt.Before(b.DisposedAt)
+		}
+		return a.TransactionID < b.TransactionID
+	})
+
+	out.Proceeds = formatDecimal(proceeds, valueScale)
+	out.CostBasis = formatDecimal(costBasis, valueScale)
+	out.Gain = formatDecimal(gain, valueScale)
+	if method == CostBasisFIFO {
+		out.ShortTermGain = formatDecimal(shortTerm, valueScale)
+		out.LongTermGain = formatDecimal(longTerm, valueScale)
+	}
+	return out, nil
+}
+
+// fillFairMarketValues pone precio a los transfer_in cargados sin precio con
+// una sola consulta de cierres diarios, y devuelve de dónde salió el costo de
+// cada entrada.
+func (uc TaxReportUseCase) fillFairMarketValues(ctx context.Context, txs []domain.Transaction, currency string) ([]domain.Transaction, map[int64]string, error) {
+	lookback := uc.PriceLookback
+	if lookback <= 0 {
+		lookback = defaultHistoryPriceLookback
+	}
+
+	sources := make(map[int64]string, len(txs))
+	var pending []int
+	var first, last time.Time
+	symbolSet := make(map[string]bool)
+	for i, t := range txs {
+		if t.Type != domain.TransactionBuy && t.Type != domain.TransactionTransferIn {
+			continue
+		}
+		sources[t.ID] = CostSourceTransaction
+		if t.Type != domain.TransactionTransferIn {
+			continue
+		}
+		// un "0" explícito es un costo cero buscado: sólo se valúa lo que vino
+		// sin precio
+		if strings.TrimSpace(t.Price) != "" {
+			continue
+		}
+		day := utcDay(t.ExecutedAt)
+		if len(pending) == 0 || day.Before(first) {
+			first = day
+		}
+		if len(pending) == 0 || day.After(last) {
+			last = day
+		}
+		pending = append(pending, i)
+		symbolSet[t.Symbol] = true
+	}
+	if len(pending) == 0 {
+		return txs, sources, nil
+	}
+
+	symbols := make([]string, 0, len(symbolSet))
+	for s := range symbolSet {
+		symbols = append(symbols, s)
+	}
+	sort.Strings(symbols)
+
+	closes, err := uc.QuoteRepo.ListDailyCloses(ctx, domain.DailyCloseFilter{
+		Symbols:  symbols,
+		Currency: currency,
+		From:     first.Add(-lookback),
+		To:       last.AddDate(0, 0, 1),
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	bySymbol := make(map[string][]domain.DailyClose, len(symbols))
+	for _, c := range closes {
+		bySymbol[c.Symbol] = append(bySymbol[c.Symbol], c)
+	}
+
+	filled := make([]domain.Transaction, len(txs))
+	copy(filled, txs)
+	for _, i := range pending {
+		t := &filled[i]
+		day := utcDay(t.ExecutedAt)
+		sources[t.ID] = CostSourceMissing
+
+		// cierres por día ascendente: el último que no pasa del día de la entrada
+		list := bySymbol[t.Symbol]
+		for j := len(list) - 1; j >= 0; j-- {
+			c := list[j]
+			if c.Day.After(day) {
+				continue
+			}
+			if day.Sub(c.Day) > lookback {
+				break
+			}
+			if _, ok := parseDecimal(c.Price); ok {
+				t.Price = c.Price
+				sources[t.ID] = CostSourceFairMarketValue
+			}
+			break
+		}
+	}
+	return filled, sources, nil
+}
+
+var taxReportCSVHeader = []string{
+	"symbol", "transaction_id", "disposed_at", "lot_transaction_id", "acquired_at",
+	"holding_days", "term", "quantity", "proceeds", "cost_basis", "gain",
+	"partial", "lot_remaining", "cost_basis_source",
+}
+
+// WriteCSV escribe una fila por disposición, con los mismos campos que el JSON.
+func (o TaxReportOutput) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(taxReportCSVHeader); err != nil {
+		return err
+	}
+	for _, d := range o.Disposals {
+		var lotID, acquiredAt, holdingDays string
+		if d.LotTransactionID != 0 {
+			lotID = strconv.FormatInt(d.LotTransactionID, 10)
+		}
+		if d.AcquiredAt != nil {
+			acquiredAt = d.AcquiredAt.Format(time.RFC3339)
+		}
+		if d.HoldingDays != nil {
+			holdingDays = strconv.Itoa(*d.HoldingDays)
+		}
+		err := cw.Write([]string{
+			d.Symbol,
+			strconv.FormatInt(d.TransactionID, 10),
+			d.DisposedAt.Format(time.RFC3339),
+			lotID,
+			acquiredAt,
+			holdingDays,
+			d.Term,
+			d.Quantity,
+			d.Proceeds,
+			d.CostBasis,
+			d.Gain,
+			strconv.FormatBool(d.Partial),
+			d.LotRemaining,
+			d.CostBasisSource,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,256 @@
+package app_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/moondolphin/crypto-api/app"
+	"github.com/moondolphin/crypto-api/domain"
+	"github.com/moondolphin/crypto-api/test/mocks"
+)
+
+func taxTx(id int64, symbol, kind, qty, price, fee string, at time.Time) domain.Transaction {
+	return domain.Transaction{
+		ID:          id,
+		PortfolioID: 4,
+		CoinID:      map[string]int64{"BTC": 1, "ETH": 2}[symbol],
+		Symbol:      symbol,
+		Type:        kind,
+		Quantity:    qty,
+		Price:       price,
+		Fee:         fee,
+		Currency:    "USD",
+		ExecutedAt:  at,
+	}
+}
+
+func day2026(month time.Month, d int) time.Time {
+	return time.Date(2026, month, d, 12, 0, 0, 0, time.UTC)
+}
+
+// BTC: 2 comprados en 2025, 1 vendido en 2025, 1 recibido sin precio en 2026
+// y 1.5 vendidos en 2026 (el resto del lote viejo y medio lote nuevo). ETH:
+// recibido sin precio ni cotización y vendido.
+func taxTransactions() []domain.Transaction {
+	return []domain.Transaction{
+		taxTx(1, "BTC", domain.TransactionBuy, "2", "100", "0", time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC)),
+		taxTx(3, "BTC", domain.TransactionSell, "1", "400", "0", time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)),
+		taxTx(5, "ETH", domain.TransactionTransferIn, "1", "", "0", day2026(time.January, 5)),
+		taxTx(2, "BTC", domain.TransactionTransferIn, "1", "", "0", day2026(time.February, 1)),
+		taxTx(4, "BTC", domain.TransactionSell, "1.5", "500", "10", day2026(time.March, 1)),
+		taxTx(6, "ETH", domain.TransactionSell, "1", "50", "0", day2026(time.April, 1)),
+		taxTx(7, "BTC", domain.TransactionTransferOut, "0.1", "", "0", day2026(time.May, 1)),
+	}
+}
+
+func TestUC31TaxReport_FIFOListsYearDisposalsWithPartialLotsAndFairValues(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	portfolios := mocks.NewMockPortfolioRepository(ctrl)
+	txs := mocks.NewMockTransactionRepository(ctrl)
+	quotes := mocks.NewMockQuoteRepository(ctrl)
+
+	portfolios.EXPECT().FindByUser(gomock.Any(), int64(7), int64(4)).Return(&domain.Portfolio{ID: 4, BaseCurrency: "USD"}, nil)
+	txs.EXPECT().ListByPortfolio(gomock.Any(), int64(4), domain.TransactionFilter{}).Return(taxTransactions(), nil)
+	quotes.EXPECT().ListDailyCloses(gomock.Any(), domain.DailyCloseFilter{
+		Symbols:  []string{"BTC", "ETH"},
+		Currency: "USD",
+		From:     time.Date(2025, 12, 29, 0, 0, 0, 0, time.UTC),
+		To:       time.Date(2026, 2, 2, 0, 0, 0, 0, time.UTC),
+	}).Return([]domain.DailyClose{
+		dailyClose("BTC", time.Date(2026, 1, 20, 0, 0, 0, 0, time.UTC), "250"),
+		dailyClose("BTC", time.Date(2026, 1, 31, 0, 0, 0, 0, time.UTC), "300"),
+	}, nil)
+
+	uc := app.TaxReportUseCase{
+		Portfolios:   portfolios,
+		Transactions: txs,
+		QuoteRepo:    quotes,
+		Now:          func() time.Time { return day2026(time.October, 1) },
+	}
+
+	// Act
+	out, err := uc.Execute(context.Background(), 7, 4, app.TaxReportInput{})
+
+	// Assert
+	require.NoError(t, err)
+	require.Equal(t, 2026, out.Year)
+	require.Equal(t, app.CostBasisFIFO, out.Method)
+	require.Len(t, out.Disposals, 3)
+
+	// resto del lote de 2025: más de un año
+	old := out.Disposals[0]
+	require.Equal(t, int64(4), old.TransactionID)
+	require.Equal(t, int64(1), old.LotTransactionID)
+	require.Equal(t, "1", old.Quantity)
+	require.Equal(t, "493.33333333", old.Proceeds)
+	require.Equal(t, "100", old.CostBasis)
+	require.Equal(t, "393.33333333", old.Gain)
+	require.Equal(t, app.TaxTermLong, old.Term)
+	require.Equal(t, 415, *old.HoldingDays)
+	require.True(t, old.Partial)
+	require.Equal(t, "0", old.LotRemaining)
+	require.Equal(t, app.CostSourceTransaction, old.CostBasisSource)
+
+	// medio lote recibido, valuado al cierre del día anterior
+	received := out.Disposals[1]
+	require.Equal(t, int64(2), received.LotTransactionID)
+	require.Equal(t, "0.5", received.Quantity)
+	require.Equal(t, "150", received.CostBasis)
+	require.Equal(t, "96.66666667", received.Gain)
+	require.Equal(t, app.TaxTermShort, received.Term)
+	require.True(t, received.Partial)
+	require.Equal(t, "0.5", received.LotRemaining)
+	require.Equal(t, app.CostSourceFairMarketValue, received.CostBasisSource)
+
+	eth := out.Disposals[2]
+	require.Equal(t, "ETH", eth.Symbol)
+	require.Equal(t, "0", eth.CostBasis)
+	require.Equal(t, "50", eth.Gain)
+	require.False(t, eth.Partial)
+	require.Empty(t, eth.LotRemaining)
+	require.Equal(t, app.CostSourceMissing, eth.CostBasisSource)
+
+	require.Equal(t, "790", out.Proceeds)
+	require.Equal(t, "250", out.CostBasis)
+	require.Equal(t, "540", out.Gain)
+	require.Equal(t, "393.33333333", out.LongTermGain)
+	require.Equal(t, "146.66666667", out.ShortTermGain)
+}
+
+func TestUC31TaxReport_AverageAndPreviousYear(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	portfolios := mocks.NewMockPortfolioRepository(ctrl)
+	txs := mocks.NewMockTransactionRepository(ctrl)
+	quotes := mocks.NewMockQuoteRepository(ctrl)
+
+	portfolios.EXPECT().FindByUser(gomock.Any(), int64(7), int64(4)).Return(&domain.Portfolio{ID: 4, BaseCurrency: "USD"}, nil)
+	txs.EXPECT().ListByPortfolio(gomock.Any(), int64(4), domain.TransactionFilter{}).Return(taxTransactions(), nil)
+	quotes.EXPECT().ListDailyCloses(gomock.Any(), gomock.Any()).Return(nil, nil)
+
+	uc := app.TaxReportUseCase{
+		Portfolios:   portfolios,
+		Transactions: txs,
+		QuoteRepo:    quotes,
+		Now:          func() time.Time { return day2026(time.October, 1) },
+	}
+
+	// Act
+	out, err := uc.Execute(context.Background(), 7, 4, app.TaxReportInput{Year: 2025, Method: "AVERAGE"})
+
+	// Assert
+	require.NoError(t, err)
+	require.Equal(t, []app.TaxDisposalOutput{{
+		Symbol:        "BTC",
+		TransactionID: 3,
+		DisposedAt:    time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC),
+		Quantity:      "1",
+		Proceeds:      "400",
+		CostBasis:     "100",
+		Gain:          "300",
+	}}, out.Disposals)
+	require.Equal(t, "300", out.Gain)
+	require.Empty(t, out.ShortTermGain)
+	require.Empty(t, out.LongTermGain)
+}
+
+func TestUC31TaxReport_ExplicitZeroPriceIsKept(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	portfolios := mocks.NewMockPortfolioRepository(ctrl)
+	txs := mocks.NewMockTransactionRepository(ctrl)
+	portfolios.EXPECT().FindByUser(gomock.Any(), int64(7), int64(4)).Return(&domain.Portfolio{ID: 4, BaseCurrency: "USD"}, nil)
+	txs.EXPECT().ListByPortfolio(gomock.Any(), int64(4), domain.TransactionFilter{}).Return([]domain.Transaction{
+		taxTx(1, "BTC", domain.TransactionTransferIn, "1", "0", "0", day2026(time.February, 1)),
+		taxTx(2, "BTC", domain.TransactionSell, "1", "500", "0", day2026(time.March, 1)),
+	}, nil)
+
+	// sin transfer_in sin precio no se consultan cierres
+	uc := app.TaxReportUseCase{
+		Portfolios:   portfolios,
+		Transactions: txs,
+		QuoteRepo:    mocks.NewMockQuoteRepository(ctrl),
+		Now:          func() time.Time { return day2026(time.October, 1) },
+	}
+
+	// Act
+	out, err := uc.Execute(context.Background(), 7, 4, app.TaxReportInput{})
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, out.Disposals, 1)
+	require.Equal(t, "0", out.Disposals[0].CostBasis)
+	require.Equal(t, "500", out.Disposals[0].Gain)
+	require.Equal(t, app.CostSourceTransaction, out.Disposals[0].CostBasisSource)
+}
+
+func TestUC31TaxReport_Rejections(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	portfolios := mocks.NewMockPortfolioRepository(ctrl)
+	txs := mocks.NewMockTransactionRepository(ctrl)
+	portfolios.EXPECT().FindByUser(gomock.Any(), int64(7), int64(4)).Return(&domain.Portfolio{ID: 4, BaseCurrency: "USD"}, nil)
+	txs.EXPECT().ListByPortfolio(gomock.Any(), int64(4), domain.TransactionFilter{}).Return(taxTransactions(), nil)
+
+	uc := app.TaxReportUseCase{
+		Portfolios:   portfolios,
+		Transactions: txs,
+		QuoteRepo:    mocks.NewMockQuoteRepository(ctrl),
+		Now:          func() time.Time { return day2026(time.October, 1) },
+	}
+
+	// Act
+	_, errFuture := uc.Execute(context.Background(), 7, 4, app.TaxReportInput{Year: 2027})
+	_, errOld := uc.Execute(context.Background(), 7, 4, app.TaxReportInput{Year: 1900})
+	_, errMethod := uc.Execute(context.Background(), 7, 4, app.TaxReportInput{Method: "lifo"})
+	_, errCurrency := uc.Execute(context.Background(), 7, 4, app.TaxReportInput{Currency: "EUR"})
+
+	// Assert
+	require.ErrorIs(t, errFuture, app.ErrInvalidTaxYear)
+	require.ErrorIs(t, errOld, app.ErrInvalidTaxYear)
+	require.ErrorIs(t, errMethod, app.ErrInvalidCostBasisMethod)
+	require.ErrorIs(t, errCurrency, app.ErrTransactionCurrencyMixed)
+}
+
+func TestUC31TaxReport_WriteCSV(t *testing.T) {
+	// Arrange
+	acquired := time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC)
+	days := 415
+	out := app.TaxReportOutput{Disposals: []app.TaxDisposalOutput{
+		{
+			Symbol: "BTC", TransactionID: 4, DisposedAt: day2026(time.March, 1),
+			LotTransactionID: 1, AcquiredAt: &acquired, HoldingDays: &days, Term: app.TaxTermLong,
+			Quantity: "1", Proceeds: "493.33333333", CostBasis: "100", Gain: "393.33333333",
+			Partial: true, LotRemaining: "0", CostBasisSource: app.CostSourceTransaction,
+		},
+		{Symbol: "BTC", TransactionID: 3, DisposedAt: acquired, Quantity: "1", Proceeds: "400", CostBasis: "100", Gain: "300"},
+	}}
+	var buf bytes.Buffer
+
+	// Act
+	err := out.WriteCSV(&buf)
+
+	// Assert
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Equal(t, []string{
+		"symbol,transaction_id,disposed_at,lot_transaction_id,acquired_at,holding_days,term,quantity,proceeds,cost_basis,gain,partial,lot_remaining,cost_basis_source",
+		"BTC,4,2026-03-01T12:00:00Z,1,2025-01-10T12:00:00Z,415,long,1,493.33333333,100,393.33333333,true,0,transaction",
+		"BTC,3,2025-01-10T12:00:00Z,,,,,1,400,100,300,false,,",
+	}, lines)
+}
@@ -2,6 +2,7 @@ package app
 
 import (
 	"context"
+	"log"
 	"time"
 
 	"github.com/moondolphin/crypto-api/domain"
@@ -10,7 +11,11 @@ import (
 type RefreshQuotesOutput struct {
 	CoinsProcessed int `json:"coins_processed"`
 	QuotesSaved    int `json:"quotes_saved"`
+	Duplicates     int `json:"duplicates_skipped"`
 	Failed         int `json:"failed"`
+
+	AlertsTriggered int `json:"alerts_triggered"`
+	WebhooksQueued  int `json:"webhooks_queued"`
 }
 
 type RefreshQuotesUseCase struct {
@@ -19,6 +24,15 @@ type RefreshQuotesUseCase struct {
 	Providers  domain.PriceProviderRegistry
 	Now        func() time.Time
 	ProviderFX map[string]string // provider -> currency (ej: binance->USDT, coingecko->USD)
+
+	// opcional: se invalida el cache de últimas cotizaciones de cada coin con quotes nuevas
+	Invalidator domain.QuoteCacheInvalidator
+
+	// opcional: se evalúan las alertas de precio si hubo quotes nuevas
+	Alerts *EvaluatePriceAlertsUseCase
+
+	// opcional: se publica quotes.stored con las quotes nuevas
+	Webhooks *PublishWebhookEventUseCase
 }
 
 func (uc RefreshQuotesUseCase) Execute(ctx context.Context) (RefreshQuotesOutput, error) {
@@ -33,8 +47,10 @@ func (uc RefreshQuotesUseCase) Execute(ctx context.Context) (RefreshQuotesOutput
 	}
 
 	out := RefreshQuotesOutput{CoinsProcessed: len(coins)}
+	var stored []WebhookQuoteData
 
 	for _, coin := range coins {
+		saved := 0
 		for providerName, currency := range uc.ProviderFX {
 			if providerName == "binance" && coin.BinanceSymbol == "" {
 				continue
@@ -62,21 +78,60 @@ func (uc RefreshQuotesUseCase) Execute(ctx context.Context) (RefreshQuotesOutput
 				}
 			}
 
-			err = uc.QuoteRepo.Insert(ctx, domain.Quote{
+			quote := domain.Quote{
 				CoinID:   coin.ID,
 				Symbol:   coin.Symbol,
 				Provider: p.Name(),
 				Currency: currency,
 				Price:    q.Price,
 				QuotedAt: quotedAt,
-			})
+			}
+			inserted, err := uc.QuoteRepo.Insert(ctx, quote)
 			if err != nil {
 				out.Failed++
 				continue
 			}
+			if !inserted {
+				// ya existía (cron y refresh manual solapados, o mismo timestamp del provider)
+				out.Duplicates++
+				continue
+			}
 
 			out.QuotesSaved++
+			saved++
+			if uc.Webhooks != nil {
+				stored = append(stored, WebhookQuoteData{
+					Symbol:   quote.Symbol,
+					Provider: quote.Provider,
+					Currency: quote.Currency,
+					Price:    quote.Price,
+					QuotedAt: quote.QuotedAt,
+				})
+			}
+		}
+
+		if saved > 0 && uc.Invalidator != nil {
+			uc.Invalidator.InvalidateSymbol(coin.Symbol)
+		}
+	}
+
+	if out.QuotesSaved > 0 && uc.Alerts != nil {
+		// una falla de las alertas no invalida las quotes ya guardadas
+		res, err := uc.Alerts.Execute(ctx)
+		if err != nil {
+			log.Printf("Warning: price alerts evaluation failed: %v", err)
+		} else {
+			out.AlertsTriggered = res.Triggered
+		}
+	}
+
+	if len(stored) > 0 {
+		// un evento por corrida, con todas las quotes nuevas
+		n, err := uc.Webhooks.Execute(ctx, domain.WebhookEventQuotesStored, 0, WebhookQuotesStoredData{Quotes: stored})
+		if err != nil {
+			log.Printf("Warning: quotes.stored webhooks: %v", err)
 		}
+		out.WebhooksQueued = n
 	}
 
 	return out, nil
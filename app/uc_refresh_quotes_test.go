@@ -141,7 +141,7 @@ func TestUCRefreshQuotes_Success_ProcessesMultipleCoinsAndProviders(t *testing.T
 	// Quote repo inserts: 4 quotes (2 coins * 2 providers)
 	quoteRepo.EXPECT().
 		Insert(gomock.Any(), gomock.Any()).
-		Return(nil).
+		Return(true, nil).
 		Times(4)
 
 	uc := app.RefreshQuotesUseCase{
@@ -198,7 +198,7 @@ func TestUCRefreshQuotes_Success_SkipsMissingProviderIDs(t *testing.T) {
 
 	quoteRepo.EXPECT().
 		Insert(gomock.Any(), gomock.Any()).
-		Return(nil).
+		Return(true, nil).
 		Times(1)
 
 	uc := app.RefreshQuotesUseCase{
@@ -349,7 +349,7 @@ func TestUCRefreshQuotes_Success_CountsFailures_WhenQuoteInsertFails(t *testing.
 
 	quoteRepo.EXPECT().
 		Insert(gomock.Any(), gomock.Any()).
-		Return(errors.New("insert_error"))
+		Return(false, errors.New("insert_error"))
 
 	uc := app.RefreshQuotesUseCase{
 		CoinRepo:   coinRepo,
@@ -411,7 +411,7 @@ func TestUCRefreshQuotes_Success_UsesProviderTimestamp_WhenAvailable(t *testing.
 			// Verify provider's timestamp was used
 			require.Equal(t, providerTime, q.QuotedAt)
 		}).
-		Return(nil)
+		Return(true, nil)
 
 	uc := app.RefreshQuotesUseCase{
 		CoinRepo:   coinRepo,
@@ -470,7 +470,7 @@ func TestUCRefreshQuotes_Success_UsesCurrentTime_WhenProviderTimestampInvalid(t
 			// Verify current time was used when timestamp is invalid
 			require.Equal(t, fixedTime, q.QuotedAt)
 		}).
-		Return(nil)
+		Return(true, nil)
 
 	uc := app.RefreshQuotesUseCase{
 		CoinRepo:   coinRepo,
@@ -487,3 +487,270 @@ func TestUCRefreshQuotes_Success_UsesCurrentTime_WhenProviderTimestampInvalid(t
 	require.NoError(t, err)
 	require.Equal(t, 1, result.QuotesSaved)
 }
+
+func TestUCRefreshQuotes_Success_InvalidatesCacheOnlyForCoinsWithNewQuotes(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	coinRepo := mocks.NewMockCoinRepository(ctrl)
+	quoteRepo := mocks.NewMockQuoteRepository(ctrl)
+	providers := mocks.NewMockPriceProviderRegistry(ctrl)
+	binanceProvider := mocks.NewMockPriceProvider(ctrl)
+	invalidator := mocks.NewMockQuoteCacheInvalidator(ctrl)
+
+	coins := []domain.Coin{
+		{ID: 1, Symbol: "BTC", Enabled: true, BinanceSymbol: "BTCUSDT"},
+		{ID: 2, Symbol: "ETH", Enabled: true, BinanceSymbol: "ETHUSDT"},
+	}
+
+	coinRepo.EXPECT().
+		ListEnabled(gomock.Any()).
+		Return(coins, nil)
+
+	providers.EXPECT().
+		Get("binance").
+		Return(binanceProvider, true).
+		Times(2)
+
+	binanceProvider.EXPECT().
+		Name().
+		Return("binance").
+		AnyTimes()
+
+	binanceProvider.EXPECT().
+		GetCurrentPrice(gomock.Any(), coins[0], "USDT").
+		Return(domain.PriceQuote{Price: "45000"}, nil)
+
+	binanceProvider.EXPECT().
+		GetCurrentPrice(gomock.Any(), coins[1], "USDT").
+		Return(domain.PriceQuote{}, errors.New("provider_down"))
+
+	quoteRepo.EXPECT().
+		Insert(gomock.Any(), gomock.Any()).
+		Return(true, nil)
+
+	// solo BTC tuvo cotizaciones nuevas
+	invalidator.EXPECT().
+		InvalidateSymbol("BTC")
+
+	uc := app.RefreshQuotesUseCase{
+		CoinRepo:    coinRepo,
+		QuoteRepo:   quoteRepo,
+		Providers:   providers,
+		Now:         func() time.Time { return time.Date(2026, 1, 22, 10, 0, 0, 0, time.UTC) },
+		ProviderFX:  map[string]string{"binance": "USDT"},
+		Invalidator: invalidator,
+	}
+
+	// Act
+	result, err := uc.Execute(context.Background())
+
+	// Assert
+	require.NoError(t, err)
+	require.Equal(t, 1, result.QuotesSaved)
+	require.Equal(t, 1, result.Failed)
+}
+
+func TestUCRefreshQuotes_Success_CountsDuplicates_WhenQuoteAlreadyStored(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	coinRepo := mocks.NewMockCoinRepository(ctrl)
+	quoteRepo := mocks.NewMockQuoteRepository(ctrl)
+	providers := mocks.NewMockPriceProviderRegistry(ctrl)
+	binanceProvider := mocks.NewMockPriceProvider(ctrl)
+	invalidator := mocks.NewMockQuoteCacheInvalidator(ctrl)
+
+	coin := domain.Coin{ID: 1, Symbol: "BTC", Enabled: true, BinanceSymbol: "BTCUSDT"}
+	fixedTime := time.Date(2026, 1, 22, 10, 0, 0, 0, time.UTC)
+
+	coinRepo.EXPECT().
+		ListEnabled(gomock.Any()).
+		Return([]domain.Coin{coin}, nil)
+
+	providers.EXPECT().
+		Get("binance").
+		Return(binanceProvider, true)
+
+	binanceProvider.EXPECT().
+		Name().
+		Return("binance")
+
+	binanceProvider.EXPECT().
+		GetCurrentPrice(gomock.Any(), coin, "USDT").
+		Return(domain.PriceQuote{Price: "45000", Timestamp: fixedTime.Format(time.RFC3339)}, nil)
+
+	// el repo ya tenía esa cotización (mismo coin/provider/currency/quoted_at)
+	quoteRepo.EXPECT().
+		Insert(gomock.Any(), gomock.Any()).
+		Return(false, nil)
+
+	// sin cotizaciones nuevas no se invalida el cache
+	invalidator.EXPECT().
+		InvalidateSymbol(gomock.Any()).
+		Times(0)
+
+	uc := app.RefreshQuotesUseCase{
+		CoinRepo:    coinRepo,
+		QuoteRepo:   quoteRepo,
+		Providers:   providers,
+		Now:         func() time.Time { return fixedTime },
+		ProviderFX:  map[string]string{"binance": "USDT"},
+		Invalidator: invalidator,
+	}
+
+	// Act
+	result, err := uc.Execute(context.Background())
+
+	// Assert
+	require.NoError(t, err)
+	require.Equal(t, 1, result.CoinsProcessed)
+	require.Equal(t, 0, result.QuotesSaved)
+	require.Equal(t, 1, result.Duplicates)
+	require.Equal(t, 0, result.Failed)
+}
+
+func TestUCRefreshQuotes_EvaluatesAlertsAfterSavingQuotes(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	coinRepo := mocks.NewMockCoinRepository(ctrl)
+	quoteRepo := mocks.NewMockQuoteRepository(ctrl)
+	providers := mocks.NewMockPriceProviderRegistry(ctrl)
+	provider := mocks.NewMockPriceProvider(ctrl)
+	rules := mocks.NewMockAlertRuleRepository(ctrl)
+	events := mocks.NewMockAlertEventRepository(ctrl)
+	now := time.Date(2026, 1, 24, 10, 0, 0, 0, time.UTC)
+
+	coinRepo.EXPECT().ListEnabled(gomock.Any()).Return([]domain.Coin{{ID: 1, Symbol: "BTC", Enabled: true, CoinGeckoID: "bitcoin"}}, nil)
+	providers.EXPECT().Get("coingecko").Return(provider, true)
+	provider.EXPECT().GetCurrentPrice(gomock.Any(), gomock.Any(), "USD").Return(domain.PriceQuote{Price: "100500"}, nil)
+	provider.EXPECT().Name().Return("coingecko")
+	quoteRepo.EXPECT().Insert(gomock.Any(), gomock.Any()).Return(true, nil)
+
+	rules.EXPECT().ListActive(gomock.Any()).Return([]domain.AlertRule{
+		{ID: 1, UserID: 7, Symbol: "BTC", Kind: domain.AlertAbove, Threshold: 100000, Active: true, Armed: true},
+	}, nil)
+	quoteRepo.EXPECT().GetLatest(gomock.Any(), "BTC", "", "").Return(&domain.PriceQuote{
+		Symbol: "BTC", Provider: "coingecko", Currency: "USD", Price: "100500", Timestamp: now.Format(time.RFC3339),
+	}, nil)
+	rules.EXPECT().MarkTriggered(gomock.Any(), int64(1), now, true).Return(true, nil)
+	events.EXPECT().Create(gomock.Any(), gomock.Any()).Return(domain.AlertEvent{ID: 1}, nil)
+
+	uc := app.RefreshQuotesUseCase{
+		CoinRepo:   coinRepo,
+		QuoteRepo:  quoteRepo,
+		Providers:  providers,
+		Now:        func() time.Time { return now },
+		ProviderFX: map[string]string{"coingecko": "USD"},
+		Alerts: &app.EvaluatePriceAlertsUseCase{
+			Rules:  rules,
+			Events: events,
+			Quotes: quoteRepo,
+			Now:    func() time.Time { return now },
+		},
+	}
+
+	// Act
+	out, err := uc.Execute(context.Background())
+
+	// Assert
+	require.NoError(t, err)
+	require.Equal(t, 1, out.QuotesSaved)
+	require.Equal(t, 1, out.AlertsTriggered)
+}
+
+func TestUCRefreshQuotes_SkipsAlertsWithoutNewQuotes(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	coinRepo := mocks.NewMockCoinRepository(ctrl)
+	coinRepo.EXPECT().ListEnabled(gomock.Any()).Return([]domain.Coin{}, nil)
+
+	// sin expectativas: el evaluador no debe consultar nada
+	uc := app.RefreshQuotesUseCase{
+		CoinRepo:   coinRepo,
+		QuoteRepo:  mocks.NewMockQuoteRepository(ctrl),
+		Providers:  mocks.NewMockPriceProviderRegistry(ctrl),
+		ProviderFX: map[string]string{"coingecko": "USD"},
+		Alerts: &app.EvaluatePriceAlertsUseCase{
+			Rules:  mocks.NewMockAlertRuleRepository(ctrl),
+			Events: mocks.NewMockAlertEventRepository(ctrl),
+		},
+	}
+
+	// Act
+	out, err := uc.Execute(context.Background())
+
+	// Assert
+	require.NoError(t, err)
+	require.Equal(t, 0, out.AlertsTriggered)
+}
+
+func TestUCRefreshQuotes_PublishesQuotesStoredOncePerRun(t *testing.T) {
+	// Arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	coinRepo := mocks.NewMockCoinRepository(ctrl)
+	quoteRepo := mocks.NewMockQuoteRepository(ctrl)
+	providers := mocks.NewMockPriceProviderRegistry(ctrl)
+	provider := mocks.NewMockPriceProvider(ctrl)
+	subs := mocks.NewMockWebhookSubscriptionRepository(ctrl)
+	deliveries := mocks.NewMockWebhookDeliveryRepository(ctrl)
+	opaque := mocks.NewMockOpaqueTokenService(ctrl)
+	now := time.Date(2026, 1, 25, 10, 0, 0, 0, time.UTC)
+
+	coinRepo.EXPECT().ListEnabled(gomock.Any()).Return([]domain.Coin{
+		{ID: 1, Symbol: "BTC", Enabled: true, CoinGeckoID: "bitcoin"},
+		{ID: 2, Symbol: "ETH", Enabled: true, CoinGeckoID: "ethereum"},
+	}, nil)
+	providers.EXPECT().Get("coingecko").Return(provider, true).Times(2)
+	provider.EXPECT().GetCurrentPrice(gomock.Any(), gomock.Any(), "USD").Return(domain.PriceQuote{Price: "100500"}, nil)
+	provider.EXPECT().GetCurrentPrice(gomock.Any(), gomock.Any(), "USD").Return(domain.PriceQuote{Price: "3500"}, nil)
+	provider.EXPECT().Name().Return("coingecko").Times(2)
+	quoteRepo.EXPECT().Insert(gomock.Any(), gomock.Any()).Return(true, nil)
+	// ETH ya estaba guardada: no va en el evento
+	quoteRepo.EXPECT().Insert(gomock.Any(), gomock.Any()).Return(false, nil)
+
+	subs.EXPECT().ListByEvent(gomock.Any(), domain.WebhookEventQuotesStored, int64(0)).Return([]domain.WebhookSubscription{{ID: 9}}, nil)
+	opaque.EXPECT().Generate().Return("abc", nil)
+
+	var payload string
+	deliveries.EXPECT().Enqueue(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, d domain.WebhookDelivery) (domain.WebhookDelivery, error) {
+		payload = d.Payload
+		return d, nil
+	})
+
+	uc := app.RefreshQuotesUseCase{
+		CoinRepo:   coinRepo,
+		QuoteRepo:  quoteRepo,
+		Providers:  providers,
+		Now:        func() time.Time { return now },
+		ProviderFX: map[string]string{"coingecko": "USD"},
+		Webhooks: &app.PublishWebhookEventUseCase{
+			Subscriptions: subs,
+			Deliveries:    deliveries,
+			Opaque:        opaque,
+			Now:           func() time.Time { return now },
+		},
+	}
+
+	// Act
+	out, err := uc.Execute(context.Background())
+
+	// Assert
+	require.NoError(t, err)
+	require.Equal(t, 1, out.QuotesSaved)
+	require.Equal(t, 1, out.WebhooksQueued)
+	require.JSONEq(t, `{
+		"id": "evt_abc",
+		"type": "quotes.stored",
+		"created_at": "2026-01-25T10:00:00Z",
+		"data": {"quotes": [{"symbol": "BTC", "provider": "coingecko", "currency": "USD", "price": "100500", "quoted_at": "2026-01-25T10:00:00Z"}]}
+	}`, payload)
+}
@@ -0,0 +1,31 @@
+package bootstrap
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// purge es una limpieza periódica; run devuelve cuántas filas borró.
+type purge struct {
+	name string
+	run  func(ctx context.Context) (int64, error)
+}
+
+// runPurges corre cada purga con su propio timeout. Un error se loguea y no
+// frena a las siguientes: una tabla caída no deja crecer a las demás.
+func runPurges(purges []purge) {
+	for _, p := range purges {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		n, err := p.run(ctx)
+		cancel()
+
+		if err != nil {
+			fmt.Println(p.name, "cleanup error:", err)
+			continue
+		}
+		if n > 0 {
+			fmt.Println(p.name, "cleanup ok, deleted:", n)
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package bootstrap
+
+import (
+	"github.com/moondolphin/crypto-api/adapters/secondary/security"
+	"github.com/moondolphin/crypto-api/config"
+)
+
+// loadJWTKeys arma el KeySet según la config:
+//   - sin JWT_KEYS_DIR: HS256 con JWT_SECRET (obligatorio).
+//   - con JWT_KEYS_DIR: RS256/EdDSA desde los PEM; si además hay JWT_SECRET se
+//     mantiene como clave sólo de verificación para no cortar las sesiones
+//     emitidas antes de migrar.
+func loadJWTKeys() (*security.KeySet, error) {
+	dir := config.JWTKeysDir()
+	if dir == "" {
+		secret, err := config.JWTSecret()
+		if err != nil {
+			return nil, err
+		}
+		return security.NewHS256KeySet(secret), nil
+	}
+
+	keys, err := security.LoadKeySet(dir, config.JWTActiveKID())
+	if err != nil {
+		return nil, err
+	}
+
+	if secret, err := config.JWTSecret(); err == nil {
+		keys.AddVerifyOnly(security.HS256Key("", secret))
+	}
+	return keys, nil
+}
@@ -0,0 +1,30 @@
+package bootstrap
+
+import (
+	"github.com/moondolphin/crypto-api/adapters/secondary/mail"
+	"github.com/moondolphin/crypto-api/config"
+	"github.com/moondolphin/crypto-api/domain"
+)
+
+// openMailer elige el adapter de mail según MAIL_DRIVER (log por defecto, para desarrollo).
+func openMailer() (domain.Mailer, error) {
+	driver, err := config.MailDriver()
+	if err != nil {
+		return nil, err
+	}
+
+	switch driver {
+	case config.MailDriverSMTP:
+		host, err := config.SMTPHost()
+		if err != nil {
+			return nil, err
+		}
+		return mail.NewSMTPMailer(host, config.SMTPPort(), config.SMTPUsername(), config.SMTPPassword(), config.MailFrom()), nil
+
+	case config.MailDriverFile:
+		return mail.NewFileMailer(config.MailOutboxDir(), config.MailFrom()), nil
+
+	default:
+		return mail.NewLogMailer(nil), nil
+	}
+}
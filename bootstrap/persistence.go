@@ -0,0 +1,58 @@
+package bootstrap
+
+import (
+	"context"
+	"database/sql"
+
+	_ "github.com/go-sql-driver/mysql"
+
+	mysqlrepo "github.com/moondolphin/crypto-api/adapters/secondary/persistence/mysql"
+	pgrepo "github.com/moondolphin/crypto-api/adapters/secondary/persistence/postgres"
+	sqliterepo "github.com/moondolphin/crypto-api/adapters/secondary/persistence/sqlite"
+	"github.com/moondolphin/crypto-api/config"
+	"github.com/moondolphin/crypto-api/domain"
+)
+
+// openRepositories arma los puertos de persistencia del driver elegido (DB_DRIVER).
+func openRepositories(ctx context.Context) (domain.Repositories, error) {
+	driver, err := config.DBDriver()
+	if err != nil {
+		return domain.Repositories{}, err
+	}
+
+	switch driver {
+	case config.DriverSQLite:
+		db, err := sqliterepo.Open(ctx, config.SQLitePath())
+		if err != nil {
+			return domain.Repositories{}, err
+		}
+		return sqliterepo.NewRepositories(db), nil
+
+	case config.DriverPostgres:
+		dsn, err := config.PostgresDSN()
+		if err != nil {
+			return domain.Repositories{}, err
+		}
+		db, err := pgrepo.Open(ctx, dsn)
+		if err != nil {
+			return domain.Repositories{}, err
+		}
+		return pgrepo.NewRepositories(db), nil
+
+	default:
+		dsn, err := config.MySQLDSN()
+		if err != nil {
+			return domain.Repositories{}, err
+		}
+
+		db, err := sql.Open("mysql", dsn)
+		if err != nil {
+			return domain.Repositories{}, err
+		}
+		if err := db.PingContext(ctx); err != nil {
+			_ = db.Close()
+			return domain.Repositories{}, err
+		}
+		return mysqlrepo.NewRepositories(db), nil
+	}
+}
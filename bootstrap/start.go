@@ -2,7 +2,6 @@ package bootstrap
 
 import (
 	"context"
-	"database/sql"
 	"fmt"
 	"net/http"
 	"os"
@@ -11,14 +10,16 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
-	_ "github.com/go-sql-driver/mysql"
 
 	httpapi "github.com/moondolphin/crypto-api/adapters/primary/httpapi"
-	mysqlrepo "github.com/moondolphin/crypto-api/adapters/secondary/persistence/mysql"
+	"github.com/moondolphin/crypto-api/adapters/secondary/cache"
+	"github.com/moondolphin/crypto-api/adapters/secondary/oidc"
 	"github.com/moondolphin/crypto-api/adapters/secondary/providers"
 	"github.com/moondolphin/crypto-api/adapters/secondary/security"
+	"github.com/moondolphin/crypto-api/adapters/secondary/webhook"
 	"github.com/moondolphin/crypto-api/app"
 	"github.com/moondolphin/crypto-api/config"
+	"github.com/moondolphin/crypto-api/domain"
 	"github.com/moondolphin/crypto-api/service"
 
 	swaggerFiles "github.com/swaggo/files"
@@ -26,47 +27,200 @@ import (
 )
 
 func Start() (*gin.Engine, error) {
-	dsn, err := config.MySQLDSN()
+	repos, err := openRepositories(context.Background())
 	if err != nil {
 		return nil, err
 	}
 
-	db, err := sql.Open("mysql", dsn)
+	// deps
+	userRepo := repos.Users
+	hasher := security.NewBcryptHasher(0)
+
+	jwtKeys, err := loadJWTKeys()
 	if err != nil {
 		return nil, err
 	}
-	if err := db.Ping(); err != nil {
-		_ = db.Close()
-		return nil, err
+	jwtTTL := config.JWTTTL()
+	jwtSvc := security.NewJWTServiceWithKeys(jwtKeys, jwtTTL, "crypto-api")
+	opaqueSvc := security.NewOpaqueTokenService()
+	totpSvc := security.NewTOTPService()
+	refreshTTL := config.RefreshTTL()
+
+	// use cases
+	adminEmail := config.BootstrapAdminEmail()
+
+	if adminEmail != "" {
+		err := app.BootstrapAdminUseCase{
+			UserRepo:                 userRepo,
+			EmailVerificationEnabled: config.EmailVerificationEnabled(),
+		}.Execute(context.Background(), adminEmail)
+		switch err {
+		case nil:
+			fmt.Println("bootstrap admin ok:", adminEmail)
+		case app.ErrUserNotFound, app.ErrEmailNotVerified:
+			fmt.Println("bootstrap admin pending: will be admin once the email is verified:", adminEmail)
+		case app.ErrBootstrapAdminNeedsVerification:
+			// cualquiera podría registrar ese email sin ser dueño del buzón
+			fmt.Println("WARNING: BOOTSTRAP_ADMIN_EMAIL ignored: it needs EMAIL_VERIFICATION_ENABLED=true, nobody was promoted:", adminEmail)
+		default:
+			return nil, err
+		}
 	}
 
-	// deps
-	userRepo := mysqlrepo.NewMySQLUserRepository(db)
-	hasher := security.NewBcryptHasher(0)
+	loginUC := app.LoginUseCase{
+		UserRepo:      userRepo,
+		Hasher:        hasher,
+		Tokens:        jwtSvc,
+		Now:           time.Now,
+		TTL:           jwtTTL,
+		RefreshTokens: repos.RefreshTokens,
+		Opaque:        opaqueSvc,
+		RefreshTTL:    refreshTTL,
+		Throttle: &app.LoginThrottle{
+			Store:              repos.LoginAttempts,
+			MaxAccountFailures: config.LoginMaxAccountFailures(),
+			MaxIPFailures:      config.LoginMaxIPFailures(),
+			BaseLockout:        config.LoginLockoutBase(),
+			MaxLockout:         config.LoginLockoutMax(),
+			Window:             config.LoginFailureWindow(),
+		},
+		TwoFactor: &app.TwoFactorLogin{
+			TOTP:          repos.TOTP,
+			RecoveryCodes: repos.RecoveryCodes,
+			Challenges:    repos.Challenges,
+			Service:       totpSvc,
+			ChallengeTTL:  config.LoginChallengeTTL(),
+		},
+	}
+
+	refreshTokenUC := app.RefreshTokenUseCase{
+		UserRepo:      userRepo,
+		Tokens:        jwtSvc,
+		RefreshTokens: repos.RefreshTokens,
+		Opaque:        opaqueSvc,
+		Now:           time.Now,
+		TTL:           jwtTTL,
+		RefreshTTL:    refreshTTL,
+	}
 
-	jwtSecret, err := config.JWTSecret()
+	logoutUC := app.LogoutUseCase{
+		RefreshTokens: repos.RefreshTokens,
+		Opaque:        opaqueSvc,
+		Revocations:   repos.Revocations,
+		Now:           time.Now,
+	}
+
+	revokeUserTokensUC := app.RevokeUserTokensUseCase{
+		UserRepo:      userRepo,
+		Revocations:   repos.Revocations,
+		RefreshTokens: repos.RefreshTokens,
+		APIKeys:       repos.APIKeys,
+		Now:           time.Now,
+		TTL:           jwtTTL,
+	}
+
+	mailer, err := openMailer()
 	if err != nil {
 		return nil, err
 	}
-	jwtTTL := config.JWTTTL()
-	jwtSvc := security.NewJWTService(jwtSecret, jwtTTL, "crypto-api")
 
-	// use cases
+	sendVerificationUC := app.SendEmailVerificationUseCase{
+		Tokens:    repos.Verifications,
+		Opaque:    opaqueSvc,
+		Mailer:    mailer,
+		Now:       time.Now,
+		TTL:       config.EmailVerificationTTL(),
+		VerifyURL: config.EmailVerificationURL(),
+	}
+
 	registerUC := app.RegisterUserUseCase{
 		UserRepo: userRepo,
 		Hasher:   hasher,
 		Now:      time.Now,
 	}
 
-	loginUC := app.LoginUseCase{
+	// con verificación apagada las cuentas nacen verificadas y no hay restricciones
+	var verifiedPolicy *app.EmailVerificationPolicy
+	if config.EmailVerificationEnabled() {
+		registerUC.Verification = &sendVerificationUC
+		verifiedPolicy = app.NewEmailVerificationPolicy(userRepo, config.UnverifiedRestrictedFeatures())
+	}
+
+	verifyEmailUC := app.VerifyEmailUseCase{
+		UserRepo:            userRepo,
+		Tokens:              repos.Verifications,
+		Opaque:              opaqueSvc,
+		Now:                 time.Now,
+		BootstrapAdminEmail: adminEmail,
+	}
+
+	resendVerificationUC := app.ResendEmailVerificationUseCase{
 		UserRepo: userRepo,
-		Hasher:   hasher,
-		Tokens:   jwtSvc,
+		Tokens:   repos.Verifications,
+		Sender:   sendVerificationUC,
 		Now:      time.Now,
-		TTL:      jwtTTL,
+		Cooldown: config.EmailVerificationResendCooldown(),
 	}
 
-	coinRepo := mysqlrepo.NewMySQLCoinRepository(db)
+	requestPasswordResetUC := app.RequestPasswordResetUseCase{
+		UserRepo: userRepo,
+		Resets:   repos.PasswordResets,
+		Opaque:   opaqueSvc,
+		Mailer:   mailer,
+		Now:      time.Now,
+		TTL:      config.PasswordResetTTL(),
+		ResetURL: config.PasswordResetURL(),
+		Throttle: &app.PasswordResetThrottle{
+			Store:       repos.LoginAttempts,
+			MaxPerEmail: config.PasswordResetMaxPerEmail(),
+			MaxPerIP:    config.PasswordResetMaxPerIP(),
+			Window:      config.PasswordResetWindow(),
+		},
+	}
+
+	confirmPasswordResetUC := app.ConfirmPasswordResetUseCase{
+		UserRepo:      userRepo,
+		Resets:        repos.PasswordResets,
+		Opaque:        opaqueSvc,
+		Hasher:        hasher,
+		RefreshTokens: repos.RefreshTokens,
+		APIKeys:       repos.APIKeys,
+		Revocations:   repos.Revocations,
+		Now:           time.Now,
+		TTL:           jwtTTL,
+	}
+
+	// purgas horarias; cada una corre aunque falle otra
+	purges := []purge{
+		// revocaciones vencidas (el JWT ya expiró solo)
+		{name: "revocations", run: func(ctx context.Context) (int64, error) {
+			return repos.Revocations.DeleteExpired(ctx, time.Now())
+		}},
+		// contadores de login fallido sin actividad ni bloqueo vigente
+		{name: "login attempts", run: func(ctx context.Context) (int64, error) {
+			return repos.LoginAttempts.DeleteStale(ctx, time.Now().Add(-config.LoginFailureWindow()))
+		}},
+		{name: "login challenges", run: func(ctx context.Context) (int64, error) {
+			return repos.Challenges.DeleteExpired(ctx, time.Now())
+		}},
+		{name: "oidc states", run: func(ctx context.Context) (int64, error) {
+			return repos.OIDCStates.DeleteExpired(ctx, time.Now())
+		}},
+		// el historial de entregas exitosas no hace falta para siempre
+		{name: "webhook deliveries", run: func(ctx context.Context) (int64, error) {
+			return repos.Deliveries.DeleteDeliveredBefore(ctx, time.Now().Add(-config.WebhookDeliveredRetention()))
+		}},
+	}
+	go func() {
+		ticker := time.NewTicker(1 * time.Hour)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			runPurges(purges)
+		}
+	}()
+
+	coinRepo := repos.Coins
 	reg := service.NewProviderRegistry(
 		providers.NewBinanceProvider(),
 		providers.NewCoinGeckoProvider(),
@@ -74,16 +228,39 @@ func Start() (*gin.Engine, error) {
 
 	// router
 	r := gin.Default()
+	// sin proxies de confianza c.ClientIP() ignora X-Forwarded-For (throttling de login por IP)
+	if err := r.SetTrustedProxies(config.TrustedProxies()); err != nil {
+		return nil, err
+	}
 
-	quoteRepo := mysqlrepo.NewMySQLQuoteRepository(db)
+	quoteRepo := repos.Quotes
+
+	// cache read-through de últimas cotizaciones y coins (CACHE_TTL_SECONDS=0 lo apaga)
+	var quoteCache *cache.CachedQuoteRepository
+	var coinCache *cache.CachedCoinRepository
+	cacheStats := httpapi.CacheStatsHandler{}
+	if ttl := config.CacheTTL(); ttl > 0 {
+		quoteCache = cache.NewCachedQuoteRepository(quoteRepo, ttl, config.CacheMaxEntries())
+		coinCache = cache.NewCachedCoinRepository(coinRepo, ttl, config.CacheMaxEntries())
+		quoteRepo = quoteCache
+		coinRepo = coinCache
+		cacheStats = httpapi.CacheStatsHandler{Quotes: quoteCache, Coins: coinCache}
+	}
 
-	ctrlRepo := mysqlrepo.NewMySQLRefreshControlRepository(db)
+	ctrlRepo := repos.RefreshControl
 
 	lastPriceUC := app.GetLastPriceUseCase{
 		CoinRepo:  coinRepo,
 		QuoteRepo: quoteRepo,
 	}
 
+	publishWebhookUC := &app.PublishWebhookEventUseCase{
+		Subscriptions: repos.Webhooks,
+		Deliveries:    repos.Deliveries,
+		Opaque:        opaqueSvc,
+		Now:           time.Now,
+	}
+
 	var refreshMu sync.Mutex
 
 	refreshUC := app.RefreshQuotesUseCase{
@@ -96,6 +273,17 @@ func Start() (*gin.Engine, error) {
 			"coingecko": "USD",
 		},
 	}
+	if quoteCache != nil {
+		refreshUC.Invalidator = quoteCache
+	}
+	refreshUC.Alerts = &app.EvaluatePriceAlertsUseCase{
+		Rules:    repos.AlertRules,
+		Events:   repos.AlertEvents,
+		Quotes:   quoteRepo,
+		Now:      time.Now,
+		Webhooks: publishWebhookUC,
+	}
+	refreshUC.Webhooks = publishWebhookUC
 
 	go func() {
 		run := func() {
@@ -123,6 +311,37 @@ func Start() (*gin.Engine, error) {
 		}
 	}()
 
+	// entregas de webhooks: el outbox se drena en tandas hasta vaciar lo vencido
+	dispatchWebhooksUC := app.DispatchWebhooksUseCase{
+		Subscriptions: repos.Webhooks,
+		Deliveries:    repos.Deliveries,
+		Sender:        webhook.NewHTTPSender(config.WebhookTimeout(), config.WebhookAllowInsecure()),
+		Now:           time.Now,
+		MaxAttempts:   config.WebhookMaxAttempts(),
+		Lease:         config.WebhookTimeout() + time.Minute,
+	}
+	go func() {
+		ticker := time.NewTicker(config.WebhookDispatchInterval())
+		defer ticker.Stop()
+
+		for range ticker.C {
+			for {
+				ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+				out, err := dispatchWebhooksUC.Execute(ctx)
+				cancel()
+
+				if err != nil {
+					fmt.Println("webhook dispatch error:", err)
+					break
+				}
+				if out.Attempted == 0 {
+					break
+				}
+				fmt.Println("webhook dispatch ok", out)
+			}
+		}
+	}()
+
 	manualUC := app.ManualRefreshWithCooldownUseCase{
 		RefreshUC:   refreshUC,
 		ControlRepo: ctrlRepo,
@@ -151,44 +370,500 @@ func Start() (*gin.Engine, error) {
 		Now:      time.Now,
 	}
 
-	favRepo := mysqlrepo.NewMySQLFavoritesRepository(db)
+	favRepo := repos.Favorites
+	// las cotizaciones de favoritas informan los mismos pares que refresca el job
+	favoritePairs := make([]domain.ProviderCurrency, 0, len(refreshUC.ProviderFX))
+	for provider, currency := range refreshUC.ProviderFX {
+		favoritePairs = append(favoritePairs, domain.ProviderCurrency{Provider: provider, Currency: currency})
+	}
+	listFavoritesUC := app.ListFavoritesUseCase{
+		Favorites: favRepo,
+		QuoteRepo: quoteRepo,
+		Pairs:     favoritePairs,
+		Now:       time.Now,
+	}
+
+	apiKeyRepo := repos.APIKeys
+
+	createAPIKeyUC := app.CreateAPIKeyUseCase{Repo: apiKeyRepo, Opaque: opaqueSvc, Now: time.Now}
+	listAPIKeysUC := app.ListAPIKeysUseCase{Repo: apiKeyRepo}
+	revokeAPIKeyUC := app.RevokeAPIKeyUseCase{Repo: apiKeyRepo, Now: time.Now}
+	authenticateAPIKeyUC := app.AuthenticateAPIKeyUseCase{
+		Repo:       apiKeyRepo,
+		UserRepo:   userRepo,
+		Opaque:     opaqueSvc,
+		Now:        time.Now,
+		TouchEvery: time.Minute,
+	}
+
+	// sesión de usuario (JWT) y, donde se permite, también X-API-Key
+	jwtAuth := httpapi.AuthConfig{KeyFunc: jwtKeys.Keyfunc, Issuer: jwtSvc.Issuer, Revocations: repos.Revocations}
+	anyAuth := jwtAuth
+	anyAuth.APIKeys = &authenticateAPIKeyUC
 
 	// swagger
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
 	// públicos
+	r.GET("/.well-known/jwks.json", httpapi.JWKSHandler{Keys: jwtKeys}.Handle)
 	r.POST("/api/v1/auth/register", httpapi.RegisterUserHandler{UC: registerUC}.Handle)
 	r.POST("/api/v1/auth/login", httpapi.LoginHandler{UC: loginUC}.Handle)
+	r.POST("/api/v1/auth/login/2fa", httpapi.TwoFactorLoginHandler{UC: app.TwoFactorLoginUseCase{Login: loginUC}}.Handle)
+
+	// login con proveedor OIDC (sólo si está configurado)
+	if config.OIDCEnabled() {
+		oidcProvider := oidc.NewProvider(oidc.Config{
+			IssuerURL:    config.OIDCIssuerURL(),
+			ClientID:     config.OIDCClientID(),
+			ClientSecret: config.OIDCClientSecret(),
+			RedirectURL:  config.OIDCRedirectURL(),
+			Scopes:       config.OIDCScopes(),
+			Leeway:       time.Minute,
+		})
+
+		r.GET("/api/v1/auth/oidc/login", httpapi.StartOIDCLoginHandler{UC: app.StartOIDCLoginUseCase{
+			Provider: oidcProvider,
+			States:   repos.OIDCStates,
+			Opaque:   opaqueSvc,
+			Now:      time.Now,
+			TTL:      config.OIDCStateTTL(),
+		}}.Handle)
+		r.GET("/api/v1/auth/oidc/callback", httpapi.OIDCCallbackHandler{UC: app.OIDCCallbackUseCase{
+			Login:         loginUC,
+			Provider:      oidcProvider,
+			States:        repos.OIDCStates,
+			Identities:    repos.Identities,
+			AutoProvision: config.OIDCAutoProvision(),
+		}}.Handle)
+	}
+	r.POST("/api/v1/auth/password/forgot", httpapi.RequestPasswordResetHandler{UC: requestPasswordResetUC}.Handle)
+	r.POST("/api/v1/auth/password/reset", httpapi.ConfirmPasswordResetHandler{UC: confirmPasswordResetUC}.Handle)
+	r.GET("/api/v1/auth/verify-email", httpapi.VerifyEmailHandler{UC: verifyEmailUC}.Handle)
+	r.POST("/api/v1/auth/verify-email", httpapi.VerifyEmailHandler{UC: verifyEmailUC}.Handle)
+	r.POST("/api/v1/auth/verify-email/resend",
+		httpapi.AuthRequired(jwtAuth),
+		httpapi.ResendVerificationHandler{UC: resendVerificationUC}.Handle,
+	)
+	r.POST("/api/v1/auth/refresh", httpapi.RefreshTokenHandler{UC: refreshTokenUC}.Handle)
+	r.POST("/api/v1/auth/logout",
+		httpapi.AuthOptional(jwtAuth),
+		httpapi.LogoutHandler{UC: logoutUC}.Handle,
+	)
 
 	r.GET("/api/v1/crypto/price",
-		httpapi.AuthOptional(jwtSecret),
+		httpapi.AuthOptional(anyAuth),
+		httpapi.RequireScope(domain.ScopeQuotesRead),
 		httpapi.GetCurrentPriceHandler{UC: lastPriceUC}.Handle,
 	)
 
 	r.GET("/api/v1/quotes/filters",
-		httpapi.AuthOptional(jwtSecret),
+		httpapi.AuthOptional(anyAuth),
+		httpapi.RequireScope(domain.ScopeQuotesRead),
 		httpapi.GetQuoteFiltersHandler{UC: getQuoteFiltersUC}.Handle,
 	)
 	r.GET("/api/v1/quotes",
-		httpapi.AuthOptional(jwtSecret),
+		httpapi.AuthOptional(anyAuth),
+		httpapi.RequireScope(domain.ScopeQuotesRead),
 		httpapi.SearchQuotesHandler{UC: searchQuotesUC}.Handle,
 	)
+	r.GET("/api/v1/quotes/backtest",
+		httpapi.AuthOptional(anyAuth),
+		httpapi.RequireScope(domain.ScopeQuotesRead),
+		httpapi.BacktestHandler{UC: app.BacktestUseCase{
+			QuoteRepo: quoteRepo,
+			Now:       time.Now,
+			MaxDays:   config.BacktestMaxDays(),
+		}}.Handle,
+	)
 
 	// privados
 	auth := r.Group("/api/v1")
-	auth.Use(httpapi.AuthRequired(jwtSecret))
+	auth.Use(httpapi.AuthRequired(anyAuth))
 
-	auth.POST("/job/refresh", func(c *gin.Context) {
+	auth.GET("/users/me/favorites",
+		httpapi.RequireScope(domain.ScopeFavoritesRead),
+		httpapi.RequireVerifiedEmail(verifiedPolicy, app.FeatureFavorites),
+		httpapi.ListFavoritesHandler{UC: listFavoritesUC}.Handle,
+	)
+	auth.POST("/users/me/favorites/:symbol",
+		httpapi.RequireScope(domain.ScopeFavoritesWrite),
+		httpapi.RequireVerifiedEmail(verifiedPolicy, app.FeatureFavorites),
+		httpapi.AddFavoriteHandler{CoinRepo: coinRepo, FavRepo: favRepo}.Handle,
+	)
+	auth.DELETE("/users/me/favorites/:symbol",
+		httpapi.RequireScope(domain.ScopeFavoritesWrite),
+		httpapi.RequireVerifiedEmail(verifiedPolicy, app.FeatureFavorites),
+		httpapi.RemoveFavoriteHandler{CoinRepo: coinRepo, FavRepo: favRepo}.Handle,
+	)
+
+	// watchlists: /users/me/favorites es la lista por defecto
+	auth.GET("/users/me/watchlists",
+		httpapi.RequireScope(domain.ScopeFavoritesRead),
+		httpapi.RequireVerifiedEmail(verifiedPolicy, app.FeatureFavorites),
+		httpapi.ListWatchlistsHandler{UC: app.ListWatchlistsUseCase{Watchlists: repos.Watchlists, Now: time.Now}}.Handle,
+	)
+	auth.POST("/users/me/watchlists",
+		httpapi.RequireScope(domain.ScopeFavoritesWrite),
+		httpapi.RequireVerifiedEmail(verifiedPolicy, app.FeatureFavorites),
+		httpapi.CreateWatchlistHandler{UC: app.CreateWatchlistUseCase{
+			Watchlists: repos.Watchlists,
+			Now:        time.Now,
+			MaxPerUser: config.WatchlistsMaxPerUser(),
+		}}.Handle,
+	)
+	auth.GET("/users/me/watchlists/:id",
+		httpapi.RequireScope(domain.ScopeFavoritesRead),
+		httpapi.RequireVerifiedEmail(verifiedPolicy, app.FeatureFavorites),
+		httpapi.GetWatchlistHandler{UC: app.GetWatchlistUseCase{Watchlists: repos.Watchlists, Entries: repos.WatchlistEntries}}.Handle,
+	)
+	auth.PATCH("/users/me/watchlists/:id",
+		httpapi.RequireScope(domain.ScopeFavoritesWrite),
+		httpapi.RequireVerifiedEmail(verifiedPolicy, app.FeatureFavorites),
+		httpapi.UpdateWatchlistHandler{UC: app.UpdateWatchlistUseCase{Watchlists: repos.Watchlists, Now: time.Now}}.Handle,
+	)
+	auth.DELETE("/users/me/watchlists/:id",
+		httpapi.RequireScope(domain.ScopeFavoritesWrite),
+		httpapi.RequireVerifiedEmail(verifiedPolicy, app.FeatureFavorites),
+		httpapi.DeleteWatchlistHandler{UC: app.DeleteWatchlistUseCase{Watchlists: repos.Watchlists}}.Handle,
+	)
+	auth.PUT("/users/me/watchlists/:id/entries/:symbol",
+		httpapi.RequireScope(domain.ScopeFavoritesWrite),
+		httpapi.RequireVerifiedEmail(verifiedPolicy, app.FeatureFavorites),
+		httpapi.SetWatchlistEntryHandler{UC: app.SetWatchlistEntryUseCase{
+			Watchlists: repos.Watchlists,
+			Entries:    repos.WatchlistEntries,
+			CoinRepo:   coinRepo,
+			Now:        time.Now,
+		}}.Handle,
+	)
+	auth.DELETE("/users/me/watchlists/:id/entries/:symbol",
+		httpapi.RequireScope(domain.ScopeFavoritesWrite),
+		httpapi.RequireVerifiedEmail(verifiedPolicy, app.FeatureFavorites),
+		httpapi.RemoveWatchlistEntryHandler{UC: app.RemoveWatchlistEntryUseCase{
+			Watchlists: repos.Watchlists,
+			Entries:    repos.WatchlistEntries,
+			CoinRepo:   coinRepo,
+		}}.Handle,
+	)
+	auth.PUT("/users/me/watchlists/:id/order",
+		httpapi.RequireScope(domain.ScopeFavoritesWrite),
+		httpapi.RequireVerifiedEmail(verifiedPolicy, app.FeatureFavorites),
+		httpapi.ReorderWatchlistHandler{UC: app.ReorderWatchlistUseCase{Watchlists: repos.Watchlists, Entries: repos.WatchlistEntries}}.Handle,
+	)
+
+	alertEventsHandler := httpapi.ListAlertEventsHandler{UC: app.ListAlertEventsUseCase{
+		Rules:  repos.AlertRules,
+		Events: repos.AlertEvents,
+	}}
+
+	auth.GET("/users/me/alerts",
+		httpapi.RequireScope(domain.ScopeAlertsRead),
+		httpapi.RequireVerifiedEmail(verifiedPolicy, app.FeatureAlerts),
+		httpapi.ListAlertsHandler{UC: app.ListAlertsUseCase{Rules: repos.AlertRules}}.Handle,
+	)
+	auth.POST("/users/me/alerts",
+		httpapi.RequireScope(domain.ScopeAlertsWrite),
+		httpapi.RequireVerifiedEmail(verifiedPolicy, app.FeatureAlerts),
+		httpapi.CreateAlertHandler{UC: app.CreateAlertUseCase{
+			Rules:      repos.AlertRules,
+			CoinRepo:   coinRepo,
+			Providers:  reg,
+			Now:        time.Now,
+			MaxPerUser: config.AlertsMaxPerUser(),
+		}}.Handle,
+	)
+	auth.GET("/users/me/alerts/events",
+		httpapi.RequireScope(domain.ScopeAlertsRead),
+		httpapi.RequireVerifiedEmail(verifiedPolicy, app.FeatureAlerts),
+		alertEventsHandler.Handle,
+	)
+	auth.GET("/users/me/alerts/:id",
+		httpapi.RequireScope(domain.ScopeAlertsRead),
+		httpapi.RequireVerifiedEmail(verifiedPolicy, app.FeatureAlerts),
+		httpapi.GetAlertHandler{UC: app.GetAlertUseCase{Rules: repos.AlertRules}}.Handle,
+	)
+	auth.GET("/users/me/alerts/:id/events",
+		httpapi.RequireScope(domain.ScopeAlertsRead),
+		httpapi.RequireVerifiedEmail(verifiedPolicy, app.FeatureAlerts),
+		alertEventsHandler.Handle,
+	)
+	auth.PATCH("/users/me/alerts/:id",
+		httpapi.RequireScope(domain.ScopeAlertsWrite),
+		httpapi.RequireVerifiedEmail(verifiedPolicy, app.FeatureAlerts),
+		httpapi.UpdateAlertHandler{UC: app.UpdateAlertUseCase{Rules: repos.AlertRules, Now: time.Now}}.Handle,
+	)
+	auth.DELETE("/users/me/alerts/:id",
+		httpapi.RequireScope(domain.ScopeAlertsWrite),
+		httpapi.RequireVerifiedEmail(verifiedPolicy, app.FeatureAlerts),
+		httpapi.DeleteAlertHandler{UC: app.DeleteAlertUseCase{Rules: repos.AlertRules}}.Handle,
+	)
+
+	// valores diarios del historial ya calculados; sin ellos se recalcula todo
+	var portfolioSnapshots domain.PortfolioSnapshotRepository
+	if config.PortfolioSnapshotsEnabled() {
+		portfolioSnapshots = repos.Snapshots
+	}
+
+	auth.GET("/users/me/portfolios",
+		httpapi.RequireScope(domain.ScopePortfoliosRead),
+		httpapi.RequireVerifiedEmail(verifiedPolicy, app.FeaturePortfolios),
+		httpapi.ListPortfoliosHandler{UC: app.ListPortfoliosUseCase{Portfolios: repos.Portfolios}}.Handle,
+	)
+	auth.POST("/users/me/portfolios",
+		httpapi.RequireScope(domain.ScopePortfoliosWrite),
+		httpapi.RequireVerifiedEmail(verifiedPolicy, app.FeaturePortfolios),
+		httpapi.CreatePortfolioHandler{UC: app.CreatePortfolioUseCase{
+			Portfolios: repos.Portfolios,
+			Now:        time.Now,
+			MaxPerUser: config.PortfoliosMaxPerUser(),
+		}}.Handle,
+	)
+	auth.GET("/users/me/portfolios/:id",
+		httpapi.RequireScope(domain.ScopePortfoliosRead),
+		httpapi.RequireVerifiedEmail(verifiedPolicy, app.FeaturePortfolios),
+		httpapi.GetPortfolioHandler{UC: app.GetPortfolioUseCase{Portfolios: repos.Portfolios, Holdings: repos.Holdings}}.Handle,
+	)
+	auth.PATCH("/users/me/portfolios/:id",
+		httpapi.RequireScope(domain.ScopePortfoliosWrite),
+		httpapi.RequireVerifiedEmail(verifiedPolicy, app.FeaturePortfolios),
+		httpapi.UpdatePortfolioHandler{UC: app.UpdatePortfolioUseCase{Portfolios: repos.Portfolios, Now: time.Now}}.Handle,
+	)
+	auth.DELETE("/users/me/portfolios/:id",
+		httpapi.RequireScope(domain.ScopePortfoliosWrite),
+		httpapi.RequireVerifiedEmail(verifiedPolicy, app.FeaturePortfolios),
+		httpapi.DeletePortfolioHandler{UC: app.DeletePortfolioUseCase{Portfolios: repos.Portfolios}}.Handle,
+	)
+	auth.PUT("/users/me/portfolios/:id/holdings/:symbol",
+		httpapi.RequireScope(domain.ScopePortfoliosWrite),
+		httpapi.RequireVerifiedEmail(verifiedPolicy, app.FeaturePortfolios),
+		httpapi.SetHoldingHandler{UC: app.SetHoldingUseCase{
+			Portfolios: repos.Portfolios,
+			Holdings:   repos.Holdings,
+			CoinRepo:   coinRepo,
+			Snapshots:  portfolioSnapshots,
+			Now:        time.Now,
+		}}.Handle,
+	)
+	auth.DELETE("/users/me/portfolios/:id/holdings/:symbol",
+		httpapi.RequireScope(domain.ScopePortfoliosWrite),
+		httpapi.RequireVerifiedEmail(verifiedPolicy, app.FeaturePortfolios),
+		httpapi.DeleteHoldingHandler{UC: app.DeleteHoldingUseCase{
+			Portfolios: repos.Portfolios,
+			Holdings:   repos.Holdings,
+			CoinRepo:   coinRepo,
+			Snapshots:  portfolioSnapshots,
+		}}.Handle,
+	)
+	auth.GET("/users/me/portfolios/:id/valuation",
+		httpapi.RequireScope(domain.ScopePortfoliosRead),
+		httpapi.RequireVerifiedEmail(verifiedPolicy, app.FeaturePortfolios),
+		httpapi.PortfolioValuationHandler{UC: app.ValuePortfolioUseCase{
+			Portfolios: repos.Portfolios,
+			Holdings:   repos.Holdings,
+			QuoteRepo:  quoteRepo,
+			Now:        time.Now,
+			StaleAfter: config.PortfolioStalePriceAfter(),
+		}}.Handle,
+	)
+	auth.POST("/users/me/portfolios/:id/transactions",
+		httpapi.RequireScope(domain.ScopePortfoliosWrite),
+		httpapi.RequireVerifiedEmail(verifiedPolicy, app.FeaturePortfolios),
+		httpapi.RecordTransactionHandler{UC: app.RecordTransactionUseCase{
+			Portfolios:   repos.Portfolios,
+			Transactions: repos.Transactions,
+			CoinRepo:     coinRepo,
+			Snapshots:    portfolioSnapshots,
+			Now:          time.Now,
+		}}.Handle,
+	)
+	auth.GET("/users/me/portfolios/:id/transactions",
+		httpapi.RequireScope(domain.ScopePortfoliosRead),
+		httpapi.RequireVerifiedEmail(verifiedPolicy, app.FeaturePortfolios),
+		httpapi.ListTransactionsHandler{UC: app.ListTransactionsUseCase{
+			Portfolios:   repos.Portfolios,
+			Transactions: repos.Transactions,
+			CoinRepo:     coinRepo,
+		}}.Handle,
+	)
+	auth.DELETE("/users/me/portfolios/:id/transactions/:txid",
+		httpapi.RequireScope(domain.ScopePortfoliosWrite),
+		httpapi.RequireVerifiedEmail(verifiedPolicy, app.FeaturePortfolios),
+		httpapi.DeleteTransactionHandler{UC: app.DeleteTransactionUseCase{
+			Portfolios:   repos.Portfolios,
+			Transactions: repos.Transactions,
+			Snapshots:    portfolioSnapshots,
+		}}.Handle,
+	)
+	auth.GET("/users/me/portfolios/:id/pnl",
+		httpapi.RequireScope(domain.ScopePortfoliosRead),
+		httpapi.RequireVerifiedEmail(verifiedPolicy, app.FeaturePortfolios),
+		httpapi.PortfolioPnLHandler{UC: app.PortfolioPnLUseCase{
+			Portfolios:   repos.Portfolios,
+			Transactions: repos.Transactions,
+			QuoteRepo:    quoteRepo,
+			Now:          time.Now,
+			StaleAfter:   config.PortfolioStalePriceAfter(),
+		}}.Handle,
+	)
+	auth.GET("/users/me/portfolios/:id/history",
+		httpapi.RequireScope(domain.ScopePortfoliosRead),
+		httpapi.RequireVerifiedEmail(verifiedPolicy, app.FeaturePortfolios),
+		httpapi.PortfolioHistoryHandler{UC: app.PortfolioHistoryUseCase{
+			Portfolios:    repos.Portfolios,
+			Holdings:      repos.Holdings,
+			Transactions:  repos.Transactions,
+			QuoteRepo:     quoteRepo,
+			Snapshots:     portfolioSnapshots,
+			Now:           time.Now,
+			MaxDays:       config.PortfolioHistoryMaxDays(),
+			PriceLookback: config.PortfolioHistoryPriceLookback(),
+		}}.Handle,
+	)
+	auth.GET("/users/me/portfolios/:id/tax-report",
+		httpapi.RequireScope(domain.ScopePortfoliosRead),
+		httpapi.RequireVerifiedEmail(verifiedPolicy, app.FeaturePortfolios),
+		httpapi.TaxReportHandler{UC: app.TaxReportUseCase{
+			Portfolios:    repos.Portfolios,
+			Transactions:  repos.Transactions,
+			QuoteRepo:     quoteRepo,
+			Now:           time.Now,
+			PriceLookback: config.PortfolioHistoryPriceLookback(),
+		}}.Handle,
+	)
+
+	// solo con sesión de usuario: una API key no puede crear otras keys
+	session := auth.Group("")
+	session.Use(httpapi.RejectAPIKeys())
+
+	updateProfileUC := app.UpdateProfileUseCase{UserRepo: userRepo, Hasher: hasher, Now: time.Now}
+	if config.EmailVerificationEnabled() {
+		updateProfileUC.Verification = &sendVerificationUC
+	}
+
+	session.GET("/users/me", httpapi.GetProfileHandler{UC: app.GetProfileUseCase{UserRepo: userRepo}}.Handle)
+	session.PATCH("/users/me", httpapi.UpdateProfileHandler{UC: updateProfileUC}.Handle)
+	session.POST("/users/me/password", httpapi.ChangePasswordHandler{UC: app.ChangePasswordUseCase{
+		UserRepo:      userRepo,
+		Hasher:        hasher,
+		RefreshTokens: repos.RefreshTokens,
+		APIKeys:       repos.APIKeys,
+		Revocations:   repos.Revocations,
+		Now:           time.Now,
+		TTL:           jwtTTL,
+	}}.Handle)
+	session.DELETE("/users/me", httpapi.DeleteAccountHandler{UC: app.DeleteAccountUseCase{
+		UserRepo:      userRepo,
+		Hasher:        hasher,
+		Favorites:     favRepo,
+		RefreshTokens: repos.RefreshTokens,
+		APIKeys:       apiKeyRepo,
+		Revocations:   repos.Revocations,
+		Identities:    repos.Identities,
+		Now:           time.Now,
+		TTL:           jwtTTL,
+	}}.Handle)
+	session.GET("/users/me/export", httpapi.ExportUserDataHandler{UC: app.ExportUserDataUseCase{
+		UserRepo:         userRepo,
+		Favorites:        favRepo,
+		APIKeys:          apiKeyRepo,
+		RefreshTokens:    repos.RefreshTokens,
+		TOTP:             repos.TOTP,
+		RecoveryCodes:    repos.RecoveryCodes,
+		Identities:       repos.Identities,
+		Alerts:           repos.AlertRules,
+		Webhooks:         repos.Webhooks,
+		Portfolios:       repos.Portfolios,
+		Holdings:         repos.Holdings,
+		Transactions:     repos.Transactions,
+		Watchlists:       repos.Watchlists,
+		WatchlistEntries: repos.WatchlistEntries,
+		Now:              time.Now,
+	}}.Handle)
+
+	session.GET("/users/me/api-keys", httpapi.ListAPIKeysHandler{UC: listAPIKeysUC}.Handle)
+	session.POST("/users/me/api-keys",
+		httpapi.RequireVerifiedEmail(verifiedPolicy, app.FeatureAPIKeys),
+		httpapi.CreateAPIKeyHandler{UC: createAPIKeyUC}.Handle,
+	)
+	session.DELETE("/users/me/api-keys/:id", httpapi.RevokeAPIKeyHandler{UC: revokeAPIKeyUC}.Handle)
+
+	session.GET("/users/me/webhooks", httpapi.ListWebhooksHandler{UC: app.ListWebhooksUseCase{Subscriptions: repos.Webhooks}}.Handle)
+	session.POST("/users/me/webhooks",
+		httpapi.RequireVerifiedEmail(verifiedPolicy, app.FeatureWebhooks),
+		httpapi.CreateWebhookHandler{UC: app.CreateWebhookUseCase{
+			Subscriptions: repos.Webhooks,
+			Opaque:        opaqueSvc,
+			Now:           time.Now,
+			MaxPerUser:    config.WebhooksMaxPerUser(),
+			AllowInsecure: config.WebhookAllowInsecure(),
+		}}.Handle,
+	)
+	session.DELETE("/users/me/webhooks/:id", httpapi.DeleteWebhookHandler{UC: app.DeleteWebhookUseCase{Subscriptions: repos.Webhooks}}.Handle)
+	session.GET("/users/me/webhooks/:id/deliveries", httpapi.ListWebhookDeliveriesHandler{UC: app.ListWebhookDeliveriesUseCase{
+		Subscriptions: repos.Webhooks,
+		Deliveries:    repos.Deliveries,
+	}}.Handle)
+	replayWebhookHandler := httpapi.ReplayWebhookDeliveriesHandler{UC: app.ReplayWebhookDeliveriesUseCase{
+		Subscriptions: repos.Webhooks,
+		Deliveries:    repos.Deliveries,
+		Now:           time.Now,
+	}}
+	session.POST("/users/me/webhooks/:id/deliveries/replay", replayWebhookHandler.Handle)
+	session.POST("/users/me/webhooks/:id/deliveries/:deliveryId/replay", replayWebhookHandler.Handle)
+
+	session.GET("/users/me/2fa", httpapi.TwoFactorStatusHandler{UC: app.GetTwoFactorStatusUseCase{
+		TOTP:          repos.TOTP,
+		RecoveryCodes: repos.RecoveryCodes,
+	}}.Handle)
+	session.POST("/users/me/2fa/enroll", httpapi.EnrollTOTPHandler{UC: app.EnrollTOTPUseCase{
+		UserRepo: userRepo,
+		TOTP:     repos.TOTP,
+		Service:  totpSvc,
+		Issuer:   config.TOTPIssuer(),
+		Now:      time.Now,
+	}}.Handle)
+	session.POST("/users/me/2fa/confirm", httpapi.ConfirmTOTPHandler{UC: app.ConfirmTOTPUseCase{
+		TOTP:          repos.TOTP,
+		RecoveryCodes: repos.RecoveryCodes,
+		Service:       totpSvc,
+		Opaque:        opaqueSvc,
+		Now:           time.Now,
+	}}.Handle)
+	session.POST("/users/me/2fa/recovery-codes", httpapi.RegenerateRecoveryCodesHandler{UC: app.RegenerateRecoveryCodesUseCase{
+		TOTP:          repos.TOTP,
+		RecoveryCodes: repos.RecoveryCodes,
+		Service:       totpSvc,
+		Opaque:        opaqueSvc,
+		Now:           time.Now,
+	}}.Handle)
+	session.POST("/users/me/2fa/disable", httpapi.DisableTOTPHandler{UC: app.DisableTOTPUseCase{
+		UserRepo:      userRepo,
+		Hasher:        hasher,
+		TOTP:          repos.TOTP,
+		RecoveryCodes: repos.RecoveryCodes,
+		Service:       totpSvc,
+		Opaque:        opaqueSvc,
+		Now:           time.Now,
+	}}.Handle)
+
+	// solo admin: gestión de coins, refresh manual y observabilidad
+	admin := session.Group("")
+	admin.Use(httpapi.RequireRole(domain.RoleAdmin))
+
+	admin.POST("/job/refresh", func(c *gin.Context) {
 		refreshMu.Lock()
 		defer refreshMu.Unlock()
 		refreshHandler.Handle(c)
 	})
 
-	auth.POST("/coins", httpapi.CreateCoinHandler{UC: createCoinUC}.Handle)
-	auth.GET("/users/me/favorites", httpapi.ListFavoritesHandler{FavRepo: favRepo}.Handle)
-	auth.PUT("/coins/:symbol", httpapi.UpdateCoinHandler{UC: updateCoinUC}.Handle)
-	auth.POST("/users/me/favorites/:symbol", httpapi.AddFavoriteHandler{CoinRepo: coinRepo, FavRepo: favRepo}.Handle)
-	auth.DELETE("/users/me/favorites/:symbol", httpapi.RemoveFavoriteHandler{CoinRepo: coinRepo, FavRepo: favRepo}.Handle)
+	admin.POST("/coins", httpapi.CreateCoinHandler{UC: createCoinUC}.Handle)
+	admin.PUT("/coins/:symbol", httpapi.UpdateCoinHandler{UC: updateCoinUC}.Handle)
+	admin.GET("/cache/stats", cacheStats.Handle)
+	admin.POST("/admin/users/:id/revoke-tokens", httpapi.RevokeUserTokensHandler{UC: revokeUserTokensUC}.Handle)
+	admin.POST("/admin/users/:id/unlock", httpapi.UnlockUserLoginHandler{UC: app.UnlockUserLoginUseCase{
+		UserRepo: userRepo,
+		Store:    repos.LoginAttempts,
+	}}.Handle)
 
 	auth.GET("/me", func(c *gin.Context) {
 		v, _ := c.Get("auth")
@@ -7,6 +7,10 @@
 // @in header
 // @name Authorization
 // @description Type "Bearer " followed by a space and the JWT token.
+// @securityDefinitions.apikey ApiKeyAuth
+// @in header
+// @name X-API-Key
+// @description API key de usuario (cak_...), limitada por sus scopes.
 package main
 
 import (
@@ -0,0 +1,9 @@
+package config
+
+import "strings"
+
+// BootstrapAdminEmail es el email del primer admin (BOOTSTRAP_ADMIN_EMAIL). Vacío = deshabilitado.
+// Sólo se usa con EMAIL_VERIFICATION_ENABLED=true.
+func BootstrapAdminEmail() string {
+	return strings.ToLower(strings.TrimSpace(Getenv("BOOTSTRAP_ADMIN_EMAIL", "")))
+}
@@ -0,0 +1,6 @@
+package config
+
+// AlertsMaxPerUser: reglas de alerta de precio por usuario, activas o no (ALERTS_MAX_PER_USER).
+func AlertsMaxPerUser() int {
+	return positiveInt("ALERTS_MAX_PER_USER", 50)
+}
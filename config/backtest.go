@@ -0,0 +1,6 @@
+package config
+
+// BacktestMaxDays: días que puede abarcar un backtest (BACKTEST_MAX_DAYS).
+func BacktestMaxDays() int {
+	return positiveInt("BACKTEST_MAX_DAYS", 1830)
+}
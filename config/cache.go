@@ -0,0 +1,25 @@
+package config
+
+import (
+	"strconv"
+	"time"
+)
+
+// CacheTTL es el TTL del cache de lecturas (segundos). 0 lo deshabilita.
+func CacheTTL() time.Duration {
+	raw := Getenv("CACHE_TTL_SECONDS", "300")
+	n, err := strconv.Atoi(raw)
+	if err != nil || n < 0 {
+		return 300 * time.Second
+	}
+	return time.Duration(n) * time.Second
+}
+
+func CacheMaxEntries() int {
+	raw := Getenv("CACHE_MAX_ENTRIES", "1000")
+	n, err := strconv.Atoi(raw)
+	if err != nil || n <= 0 {
+		return 1000
+	}
+	return n
+}
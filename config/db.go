@@ -0,0 +1,23 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	DriverMySQL    = "mysql"
+	DriverSQLite   = "sqlite"
+	DriverPostgres = "postgres"
+)
+
+// DBDriver devuelve el motor de persistencia (DB_DRIVER). Default: mysql.
+func DBDriver() (string, error) {
+	d := strings.ToLower(strings.TrimSpace(Getenv("DB_DRIVER", DriverMySQL)))
+	switch d {
+	case DriverMySQL, DriverSQLite, DriverPostgres:
+		return d, nil
+	default:
+		return "", fmt.Errorf("DB_DRIVER not supported: %s", d)
+	}
+}
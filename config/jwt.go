@@ -23,3 +23,24 @@ func JWTTTL() time.Duration {
 	}
 	return time.Duration(n) * time.Minute
 }
+
+func RefreshTTL() time.Duration {
+	// horas
+	raw := Getenv("REFRESH_TTL_HOURS", "720")
+	n, err := strconv.Atoi(raw)
+	if err != nil || n <= 0 {
+		return 720 * time.Hour
+	}
+	return time.Duration(n) * time.Hour
+}
+
+// JWTKeysDir es el directorio con las claves PEM de firma asimétrica.
+// Vacío = modo HS256 con JWT_SECRET.
+func JWTKeysDir() string {
+	return Getenv("JWT_KEYS_DIR", "")
+}
+
+// JWTActiveKID fuerza la clave de firma; vacío = la de kid mayor.
+func JWTActiveKID() string {
+	return Getenv("JWT_ACTIVE_KID", "")
+}
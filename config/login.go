@@ -0,0 +1,53 @@
+package config
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+func positiveInt(key string, def int) int {
+	n, err := strconv.Atoi(Getenv(key, strconv.Itoa(def)))
+	if err != nil || n <= 0 {
+		return def
+	}
+	return n
+}
+
+// LoginMaxAccountFailures: fallos seguidos de una cuenta antes de bloquearla (LOGIN_MAX_FAILURES_ACCOUNT).
+func LoginMaxAccountFailures() int {
+	return positiveInt("LOGIN_MAX_FAILURES_ACCOUNT", 5)
+}
+
+// LoginMaxIPFailures: fallos desde una misma IP (cualquier cuenta) antes de bloquearla (LOGIN_MAX_FAILURES_IP).
+func LoginMaxIPFailures() int {
+	return positiveInt("LOGIN_MAX_FAILURES_IP", 20)
+}
+
+// LoginLockoutBase es el primer bloqueo; cada fallo posterior lo duplica (segundos).
+func LoginLockoutBase() time.Duration {
+	return time.Duration(positiveInt("LOGIN_LOCKOUT_BASE_SECONDS", 30)) * time.Second
+}
+
+// LoginLockoutMax acota el bloqueo exponencial (segundos).
+func LoginLockoutMax() time.Duration {
+	return time.Duration(positiveInt("LOGIN_LOCKOUT_MAX_SECONDS", 3600)) * time.Second
+}
+
+// LoginFailureWindow: pasado este tiempo sin fallos el contador vuelve a cero (minutos).
+func LoginFailureWindow() time.Duration {
+	return time.Duration(positiveInt("LOGIN_FAILURE_WINDOW_MINUTES", 15)) * time.Minute
+}
+
+// TrustedProxies lista las IPs/CIDR de proxies cuyo X-Forwarded-For se acepta
+// (TRUSTED_PROXIES, separado por comas). Vacío = ninguno: la IP del cliente es
+// la de la conexión y no se puede falsear el throttling por IP con headers.
+func TrustedProxies() []string {
+	var out []string
+	for _, p := range strings.Split(Getenv("TRUSTED_PROXIES", ""), ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			out = append(out, p)
+		}
+	}
+	return out
+}
@@ -0,0 +1,91 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	MailDriverLog  = "log"
+	MailDriverFile = "file"
+	MailDriverSMTP = "smtp"
+)
+
+// MailDriver elige cómo salen los mails (MAIL_DRIVER). Default: log.
+func MailDriver() (string, error) {
+	d := strings.ToLower(strings.TrimSpace(Getenv("MAIL_DRIVER", MailDriverLog)))
+	switch d {
+	case MailDriverLog, MailDriverFile, MailDriverSMTP:
+		return d, nil
+	default:
+		return "", fmt.Errorf("MAIL_DRIVER not supported: %s", d)
+	}
+}
+
+func MailFrom() string {
+	return Getenv("MAIL_FROM", "no-reply@crypto-api.local")
+}
+
+// MailOutboxDir es donde el driver file deja los .eml.
+func MailOutboxDir() string {
+	return Getenv("MAIL_OUTBOX_DIR", "mail-outbox")
+}
+
+func SMTPHost() (string, error) {
+	h := Getenv("SMTP_HOST", "")
+	if h == "" {
+		return "", fmt.Errorf("SMTP_HOST required")
+	}
+	return h, nil
+}
+
+func SMTPPort() int {
+	n, err := strconv.Atoi(Getenv("SMTP_PORT", "587"))
+	if err != nil || n <= 0 {
+		return 587
+	}
+	return n
+}
+
+func SMTPUsername() string {
+	return Getenv("SMTP_USERNAME", "")
+}
+
+func SMTPPassword() string {
+	return Getenv("SMTP_PASSWORD", "")
+}
+
+// AppBaseURL es la URL pública del front, para armar los links de los mails.
+func AppBaseURL() string {
+	return strings.TrimRight(Getenv("APP_BASE_URL", "http://localhost:8080"), "/")
+}
+
+func PasswordResetURL() string {
+	return Getenv("PASSWORD_RESET_URL", AppBaseURL()+"/reset-password")
+}
+
+func PasswordResetTTL() time.Duration {
+	// minutos
+	n, err := strconv.Atoi(Getenv("PASSWORD_RESET_TTL_MINUTES", "30"))
+	if err != nil || n <= 0 {
+		return 30 * time.Minute
+	}
+	return time.Duration(n) * time.Minute
+}
+
+// PasswordResetMaxPerEmail: pedidos de reset de un mismo email dentro de la ventana (PASSWORD_RESET_MAX_PER_EMAIL).
+func PasswordResetMaxPerEmail() int {
+	return positiveInt("PASSWORD_RESET_MAX_PER_EMAIL", 3)
+}
+
+// PasswordResetMaxPerIP: pedidos de reset desde una misma IP dentro de la ventana (PASSWORD_RESET_MAX_PER_IP).
+func PasswordResetMaxPerIP() int {
+	return positiveInt("PASSWORD_RESET_MAX_PER_IP", 10)
+}
+
+// PasswordResetWindow es la ventana del límite de pedidos y lo que dura el bloqueo (minutos).
+func PasswordResetWindow() time.Duration {
+	return time.Duration(positiveInt("PASSWORD_RESET_WINDOW_MINUTES", 15)) * time.Minute
+}
@@ -0,0 +1,52 @@
+package config
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// OIDCEnabled: el login con OIDC se habilita al configurar OIDC_ISSUER_URL y OIDC_CLIENT_ID.
+func OIDCEnabled() bool {
+	return OIDCIssuerURL() != "" && OIDCClientID() != ""
+}
+
+// OIDCIssuerURL es el issuer del proveedor; de ahí se lee /.well-known/openid-configuration.
+func OIDCIssuerURL() string {
+	return Getenv("OIDC_ISSUER_URL", "")
+}
+
+func OIDCClientID() string {
+	return Getenv("OIDC_CLIENT_ID", "")
+}
+
+// OIDCClientSecret vacío = cliente público (sólo PKCE).
+func OIDCClientSecret() string {
+	return Getenv("OIDC_CLIENT_SECRET", "")
+}
+
+// OIDCRedirectURL tiene que coincidir con la registrada en el proveedor.
+func OIDCRedirectURL() string {
+	return Getenv("OIDC_REDIRECT_URL", AppBaseURL()+"/api/v1/auth/oidc/callback")
+}
+
+// OIDCScopes: scopes separados por coma o espacio (OIDC_SCOPES).
+func OIDCScopes() []string {
+	return strings.FieldsFunc(Getenv("OIDC_SCOPES", "openid,email,profile"), func(r rune) bool {
+		return r == ',' || r == ' '
+	})
+}
+
+// OIDCStateTTL: tiempo para volver del proveedor antes de que el state venza (segundos).
+func OIDCStateTTL() time.Duration {
+	return time.Duration(positiveInt("OIDC_STATE_TTL_SECONDS", 600)) * time.Second
+}
+
+// OIDCAutoProvision: con false sólo entran por OIDC usuarios ya registrados (OIDC_AUTO_PROVISION).
+func OIDCAutoProvision() bool {
+	v, err := strconv.ParseBool(Getenv("OIDC_AUTO_PROVISION", "true"))
+	if err != nil {
+		return true
+	}
+	return v
+}
@@ -0,0 +1,39 @@
+package config
+
+import (
+	"strconv"
+	"time"
+)
+
+// PortfoliosMaxPerUser: portfolios por usuario (PORTFOLIOS_MAX_PER_USER).
+func PortfoliosMaxPerUser() int {
+	return positiveInt("PORTFOLIOS_MAX_PER_USER", 20)
+}
+
+// PortfolioStalePriceAfter: antigüedad a partir de la cual la valuación marca
+// un precio como stale (PORTFOLIO_STALE_PRICE_MINUTES).
+func PortfolioStalePriceAfter() time.Duration {
+	return time.Duration(positiveInt("PORTFOLIO_STALE_PRICE_MINUTES", 120)) * time.Minute
+}
+
+// PortfolioHistoryMaxDays: días que puede abarcar un pedido de historial
+// (PORTFOLIO_HISTORY_MAX_DAYS).
+func PortfolioHistoryMaxDays() int {
+	return positiveInt("PORTFOLIO_HISTORY_MAX_DAYS", 366)
+}
+
+// PortfolioHistoryPriceLookback: cuántos días se arrastra el último cierre a
+// días sin cotización (PORTFOLIO_HISTORY_PRICE_LOOKBACK_DAYS).
+func PortfolioHistoryPriceLookback() time.Duration {
+	return time.Duration(positiveInt("PORTFOLIO_HISTORY_PRICE_LOOKBACK_DAYS", 7)) * 24 * time.Hour
+}
+
+// PortfolioSnapshotsEnabled guarda los valores diarios ya calculados del
+// historial (PORTFOLIO_SNAPSHOTS_ENABLED, default true).
+func PortfolioSnapshotsEnabled() bool {
+	v, err := strconv.ParseBool(Getenv("PORTFOLIO_SNAPSHOTS_ENABLED", "true"))
+	if err != nil {
+		return true
+	}
+	return v
+}
@@ -0,0 +1,26 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+)
+
+func PostgresDSN() (string, error) {
+	pass := os.Getenv("POSTGRES_PASSWORD")
+	if pass == "" {
+		return "", fmt.Errorf("POSTGRES_PASSWORD required")
+	}
+
+	u := url.URL{
+		Scheme: "postgres",
+		User:   url.UserPassword(Getenv("POSTGRES_USER", "postgres"), pass),
+		Host:   Getenv("POSTGRES_HOST", "127.0.0.1") + ":" + Getenv("POSTGRES_PORT", "5432"),
+		Path:   Getenv("POSTGRES_DB", "crypto"),
+	}
+	q := u.Query()
+	q.Set("sslmode", Getenv("POSTGRES_SSLMODE", "disable"))
+	u.RawQuery = q.Encode()
+
+	return u.String(), nil
+}
@@ -0,0 +1,5 @@
+package config
+
+func SQLitePath() string {
+	return Getenv("SQLITE_PATH", "crypto.db")
+}
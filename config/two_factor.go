@@ -0,0 +1,13 @@
+package config
+
+import "time"
+
+// TOTPIssuer es el nombre que muestra la app autenticadora (TOTP_ISSUER).
+func TOTPIssuer() string {
+	return Getenv("TOTP_ISSUER", "Crypto API")
+}
+
+// LoginChallengeTTL: tiempo para ingresar el código 2FA tras la contraseña (segundos).
+func LoginChallengeTTL() time.Duration {
+	return time.Duration(positiveInt("LOGIN_CHALLENGE_TTL_SECONDS", 300)) * time.Second
+}
@@ -0,0 +1,56 @@
+package config
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// EmailVerificationEnabled: con false las cuentas nuevas nacen verificadas (EMAIL_VERIFICATION_ENABLED).
+func EmailVerificationEnabled() bool {
+	v, err := strconv.ParseBool(Getenv("EMAIL_VERIFICATION_ENABLED", "true"))
+	if err != nil {
+		return true
+	}
+	return v
+}
+
+// EmailVerificationURL es el destino del link del mail. Default: el GET de la API.
+func EmailVerificationURL() string {
+	return Getenv("EMAIL_VERIFICATION_URL", AppBaseURL()+"/api/v1/auth/verify-email")
+}
+
+func EmailVerificationTTL() time.Duration {
+	// horas
+	n, err := strconv.Atoi(Getenv("EMAIL_VERIFICATION_TTL_HOURS", "48"))
+	if err != nil || n <= 0 {
+		return 48 * time.Hour
+	}
+	return time.Duration(n) * time.Hour
+}
+
+// EmailVerificationResendCooldown es el mínimo entre dos reenvíos (segundos).
+func EmailVerificationResendCooldown() time.Duration {
+	n, err := strconv.Atoi(Getenv("EMAIL_VERIFICATION_RESEND_SECONDS", "60"))
+	if err != nil || n <= 0 {
+		return 60 * time.Second
+	}
+	return time.Duration(n) * time.Second
+}
+
+// UnverifiedRestrictedFeatures lista lo que una cuenta sin verificar no puede usar
+// (UNVERIFIED_RESTRICTED_FEATURES, separado por comas). "none" = sin restricciones.
+func UnverifiedRestrictedFeatures() []string {
+	raw := strings.ToLower(strings.TrimSpace(Getenv("UNVERIFIED_RESTRICTED_FEATURES", "favorites,api_keys,alerts,webhooks,portfolios")))
+	if raw == "none" {
+		return nil
+	}
+
+	var out []string
+	for _, f := range strings.Split(raw, ",") {
+		if f = strings.TrimSpace(f); f != "" {
+			out = append(out, f)
+		}
+	}
+	return out
+}
@@ -0,0 +1,7 @@
+package config
+
+// WatchlistsMaxPerUser: watchlists por usuario, contando la por defecto
+// (WATCHLISTS_MAX_PER_USER).
+func WatchlistsMaxPerUser() int {
+	return positiveInt("WATCHLISTS_MAX_PER_USER", 20)
+}
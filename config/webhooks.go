@@ -0,0 +1,41 @@
+package config
+
+import (
+	"strconv"
+	"time"
+)
+
+// WebhooksMaxPerUser: suscripciones de webhook por usuario (WEBHOOK_MAX_PER_USER).
+func WebhooksMaxPerUser() int {
+	return positiveInt("WEBHOOK_MAX_PER_USER", 10)
+}
+
+// WebhookAllowInsecure acepta URLs http:// y destinos en redes privadas o
+// localhost (WEBHOOK_ALLOW_INSECURE). Sólo para desarrollo.
+func WebhookAllowInsecure() bool {
+	v, err := strconv.ParseBool(Getenv("WEBHOOK_ALLOW_INSECURE", "false"))
+	if err != nil {
+		return false
+	}
+	return v
+}
+
+// WebhookDispatchInterval: cada cuánto se buscan entregas pendientes (WEBHOOK_DISPATCH_INTERVAL_SECONDS).
+func WebhookDispatchInterval() time.Duration {
+	return time.Duration(positiveInt("WEBHOOK_DISPATCH_INTERVAL_SECONDS", 10)) * time.Second
+}
+
+// WebhookTimeout: timeout de cada POST al receptor (WEBHOOK_TIMEOUT_SECONDS).
+func WebhookTimeout() time.Duration {
+	return time.Duration(positiveInt("WEBHOOK_TIMEOUT_SECONDS", 10)) * time.Second
+}
+
+// WebhookMaxAttempts: intentos antes de dar una entrega por muerta (WEBHOOK_MAX_ATTEMPTS).
+func WebhookMaxAttempts() int {
+	return positiveInt("WEBHOOK_MAX_ATTEMPTS", 8)
+}
+
+// WebhookDeliveredRetention: cuánto se guardan las entregas exitosas (WEBHOOK_DELIVERED_RETENTION_HOURS).
+func WebhookDeliveredRetention() time.Duration {
+	return time.Duration(positiveInt("WEBHOOK_DELIVERED_RETENTION_HOURS", 168)) * time.Hour
+}
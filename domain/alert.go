@@ -0,0 +1,70 @@
+package domain
+
+import "time"
+
+// Tipos de regla de alerta de precio.
+const (
+	AlertAbove         = "above"      // precio >= Threshold
+	AlertBelow         = "below"      // precio <= Threshold
+	AlertPercentChange = "pct_change" // variación % en Window: Threshold > 0 suba, < 0 caída
+)
+
+// AlertRule es una regla de alerta de un usuario sobre el precio de una coin.
+//
+// Una regla one-shot (Rearm=false) se desactiva al dispararse. Una que se
+// re-arma queda desarmada (Armed=false) hasta que la condición deja de
+// cumplirse, así no se dispara en cada refresh mientras el precio siga del
+// otro lado del umbral.
+type AlertRule struct {
+	ID     int64
+	UserID int64
+	CoinID int64
+	Symbol string
+
+	// opcionales: vacío = la última cotización de cualquier provider/moneda
+	Currency string
+	Provider string
+
+	Kind      string
+	Threshold float64
+	Window    time.Duration // sólo AlertPercentChange
+
+	Rearm  bool
+	Active bool
+	Armed  bool
+
+	LastTriggeredAt *time.Time
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+// IsValidAlertKind indica si k es uno de los tipos de regla conocidos.
+func IsValidAlertKind(k string) bool {
+	switch k {
+	case AlertAbove, AlertBelow, AlertPercentChange:
+		return true
+	}
+	return false
+}
+
+// AlertEvent es el registro de una regla disparada. Guarda una copia de la
+// regla al momento del disparo: el usuario puede editarla después.
+type AlertEvent struct {
+	ID        int64
+	RuleID    int64
+	UserID    int64
+	Symbol    string
+	Provider  string
+	Currency  string
+	Kind      string
+	Threshold float64
+
+	Price    string
+	QuotedAt time.Time
+
+	// sólo AlertPercentChange: precio al inicio de la ventana y variación %
+	ReferencePrice string
+	ChangePercent  *float64
+
+	TriggeredAt time.Time
+}
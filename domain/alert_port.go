@@ -0,0 +1,45 @@
+package domain
+
+//go:generate echo Generating mocks for alert_port.go
+//go:generate go run go.uber.org/mock/mockgen@v0.5.0 -source=alert_port.go -destination=../test/mocks/alert_port_mock.go -package=mocks
+
+import (
+	"context"
+	"time"
+)
+
+type AlertRuleRepository interface {
+	Create(ctx context.Context, r AlertRule) (AlertRule, error)
+
+	// devuelve nil, nil si no existe o no pertenece al usuario
+	FindByUser(ctx context.Context, userID, id int64) (*AlertRule, error)
+
+	// reglas del usuario (activas o no), por id ascendente
+	ListByUser(ctx context.Context, userID int64) ([]AlertRule, error)
+
+	CountByUser(ctx context.Context, userID int64) (int, error)
+
+	// reglas activas de todos los usuarios, por id ascendente (para el evaluador)
+	ListActive(ctx context.Context) ([]AlertRule, error)
+
+	// guarda los campos editables (threshold, window, rearm, active, armed) si
+	// la regla pertenece al usuario. updated=false si no.
+	Update(ctx context.Context, r AlertRule) (updated bool, err error)
+
+	// borra la regla (y sus eventos) si pertenece al usuario
+	Delete(ctx context.Context, userID, id int64) (deleted bool, err error)
+
+	// desarma la regla si sigue activa y armada, y la desactiva si deactivate.
+	// triggered=false si otro evaluador ya la disparó o el usuario la cambió.
+	MarkTriggered(ctx context.Context, id int64, at time.Time, deactivate bool) (triggered bool, err error)
+
+	// vuelve a armar una regla activa desarmada
+	Rearm(ctx context.Context, id int64, at time.Time) error
+}
+
+type AlertEventRepository interface {
+	Create(ctx context.Context, e AlertEvent) (AlertEvent, error)
+
+	// eventos del usuario, del más reciente al más viejo. ruleID=0 = todas las reglas.
+	ListByUser(ctx context.Context, userID, ruleID int64, limit int) ([]AlertEvent, error)
+}
@@ -0,0 +1,53 @@
+package domain
+
+import "time"
+
+// Scopes que se pueden otorgar a una API key. Un JWT de usuario no tiene
+// restricción de scopes.
+const (
+	ScopeQuotesRead      = "quotes:read"
+	ScopeFavoritesRead   = "favorites:read"
+	ScopeFavoritesWrite  = "favorites:write"
+	ScopeAlertsRead      = "alerts:read"
+	ScopeAlertsWrite     = "alerts:write"
+	ScopePortfoliosRead  = "portfolios:read"
+	ScopePortfoliosWrite = "portfolios:write"
+)
+
+// APIKey es una credencial de larga duración para clientes máquina a máquina.
+// Sólo se persiste el hash; Prefix queda en claro para que el usuario la reconozca.
+type APIKey struct {
+	ID         int64
+	UserID     int64
+	Name       string
+	Prefix     string
+	KeyHash    string
+	Scopes     []string
+	ExpiresAt  *time.Time // nil = no vence
+	CreatedAt  time.Time
+	LastUsedAt *time.Time
+	RevokedAt  *time.Time
+}
+
+// IsValidScope indica si s es uno de los scopes conocidos.
+func IsValidScope(s string) bool {
+	switch s {
+	case ScopeQuotesRead, ScopeFavoritesRead, ScopeFavoritesWrite, ScopeAlertsRead, ScopeAlertsWrite,
+		ScopePortfoliosRead, ScopePortfoliosWrite:
+		return true
+	}
+	return false
+}
+
+func (k APIKey) HasScope(scope string) bool {
+	for _, s := range k.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+func (k APIKey) IsExpired(now time.Time) bool {
+	return k.ExpiresAt != nil && !now.Before(*k.ExpiresAt)
+}
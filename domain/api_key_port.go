@@ -0,0 +1,27 @@
+package domain
+
+//go:generate echo Generating mocks for api_key_port.go
+//go:generate go run go.uber.org/mock/mockgen@v0.5.0 -source=api_key_port.go -destination=../test/mocks/api_key_port_mock.go -package=mocks
+
+import (
+	"context"
+	"time"
+)
+
+type APIKeyRepository interface {
+	Create(ctx context.Context, k APIKey) (APIKey, error)
+
+	// keys no revocadas del usuario, por id ascendente
+	ListByUser(ctx context.Context, userID int64) ([]APIKey, error)
+
+	// devuelve nil, nil si no existe (incluye revocadas)
+	FindByHash(ctx context.Context, keyHash string) (*APIKey, error)
+
+	// revoca la key si pertenece al usuario y sigue activa. revoked=false si no.
+	Revoke(ctx context.Context, userID, id int64, at time.Time) (revoked bool, err error)
+
+	// revoca todas las keys activas del usuario (cambio o reset de contraseña, cierre de sesiones)
+	RevokeByUser(ctx context.Context, userID int64, at time.Time) error
+
+	TouchLastUsed(ctx context.Context, id int64, at time.Time) error
+}
@@ -0,0 +1,20 @@
+package domain
+
+//go:generate echo Generating mocks for cache_port.go
+//go:generate go run go.uber.org/mock/mockgen@v0.5.0 -source=cache_port.go -destination=../test/mocks/cache_port_mock.go -package=mocks
+
+type CacheStats struct {
+	Hits      uint64 `json:"hits"`
+	Misses    uint64 `json:"misses"`
+	Evictions uint64 `json:"evictions"`
+	Size      int    `json:"size"`
+}
+
+// QuoteCacheInvalidator descarta las últimas cotizaciones cacheadas de un símbolo.
+type QuoteCacheInvalidator interface {
+	InvalidateSymbol(symbol string)
+}
+
+type CacheStatsReporter interface {
+	Stats() CacheStats
+}
@@ -0,0 +1,22 @@
+package domain
+
+import "time"
+
+// EmailVerificationToken es el token opaco que se manda por mail para confirmar
+// la dirección; sólo se persiste su hash.
+type EmailVerificationToken struct {
+	ID        int64
+	UserID    int64
+	TokenHash string
+	ExpiresAt time.Time
+	CreatedAt time.Time
+	UsedAt    *time.Time // consumido o reemplazado por un reenvío
+}
+
+func (t EmailVerificationToken) IsUsed() bool {
+	return t.UsedAt != nil
+}
+
+func (t EmailVerificationToken) IsExpired(now time.Time) bool {
+	return !now.Before(t.ExpiresAt)
+}
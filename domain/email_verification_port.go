@@ -0,0 +1,25 @@
+package domain
+
+//go:generate echo Generating mocks for email_verification_port.go
+//go:generate go run go.uber.org/mock/mockgen@v0.5.0 -source=email_verification_port.go -destination=../test/mocks/email_verification_port_mock.go -package=mocks
+
+import (
+	"context"
+	"time"
+)
+
+type EmailVerificationRepository interface {
+	Create(ctx context.Context, t EmailVerificationToken) (EmailVerificationToken, error)
+
+	// devuelve nil, nil si no existe
+	FindByHash(ctx context.Context, tokenHash string) (*EmailVerificationToken, error)
+
+	// último token emitido al usuario (usado o no), para limitar reenvíos. nil, nil si no hay.
+	LatestByUser(ctx context.Context, userID int64) (*EmailVerificationToken, error)
+
+	// consume el token. used=false si ya estaba usado.
+	MarkUsed(ctx context.Context, id int64, at time.Time) (used bool, err error)
+
+	// invalida todos los tokens todavía sin usar del usuario
+	InvalidateByUser(ctx context.Context, userID int64, at time.Time) error
+}
@@ -0,0 +1,26 @@
+package domain
+
+//go:generate echo Generating mocks for jwks_port.go
+//go:generate go run go.uber.org/mock/mockgen@v0.5.0 -source=jwks_port.go -destination=../test/mocks/jwks_port_mock.go -package=mocks
+
+// PublicJWK es una clave pública de verificación en formato JWK (RFC 7517).
+type PublicJWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+
+	// RSA
+	N string `json:"n,omitempty"`
+	E string `json:"e,omitempty"`
+
+	// OKP (Ed25519)
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+// JWKSProvider expone las claves públicas con las que otros servicios pueden
+// verificar nuestros access tokens. Las claves simétricas nunca se publican.
+type JWKSProvider interface {
+	PublicJWKs() []PublicJWK
+}
@@ -0,0 +1,16 @@
+package domain
+
+import "time"
+
+// LoginAttempt acumula los logins fallidos de una clave (una cuenta o una IP).
+type LoginAttempt struct {
+	Key           string
+	Failures      int
+	LastFailureAt time.Time
+	LockedUntil   *time.Time
+}
+
+// IsLocked indica si la clave sigue bloqueada en now.
+func (a LoginAttempt) IsLocked(now time.Time) bool {
+	return a.LockedUntil != nil && now.Before(*a.LockedUntil)
+}
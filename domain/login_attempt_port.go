@@ -0,0 +1,27 @@
+package domain
+
+//go:generate echo Generating mocks for login_attempt_port.go
+//go:generate go run go.uber.org/mock/mockgen@v0.5.0 -source=login_attempt_port.go -destination=../test/mocks/login_attempt_port_mock.go -package=mocks
+
+import (
+	"context"
+	"time"
+)
+
+// LoginAttemptStore lleva la cuenta de logins fallidos para el bloqueo temporal.
+type LoginAttemptStore interface {
+	// devuelve nil, nil si la clave no tiene fallos registrados
+	Get(ctx context.Context, key string) (*LoginAttempt, error)
+
+	// suma un fallo y devuelve el total. Si el último fallo (o el fin del último
+	// bloqueo, si es posterior) es anterior a resetBefore, el contador vuelve a empezar.
+	RecordFailure(ctx context.Context, key string, at, resetBefore time.Time) (failures int, err error)
+
+	Lock(ctx context.Context, key string, until time.Time) error
+
+	// olvida los fallos de la clave (login correcto o desbloqueo manual)
+	Reset(ctx context.Context, key string) error
+
+	// borra las claves sin fallos desde before y sin bloqueo vigente; devuelve cuántas eliminó
+	DeleteStale(ctx context.Context, before time.Time) (int64, error)
+}
@@ -0,0 +1,18 @@
+package domain
+
+//go:generate echo Generating mocks for mailer_port.go
+//go:generate go run go.uber.org/mock/mockgen@v0.5.0 -source=mailer_port.go -destination=../test/mocks/mailer_port_mock.go -package=mocks
+
+import "context"
+
+// EmailMessage es un mail de texto plano.
+type EmailMessage struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer envía mails transaccionales (reset de contraseña, verificación, ...).
+type Mailer interface {
+	Send(ctx context.Context, msg EmailMessage) error
+}
@@ -0,0 +1,43 @@
+package domain
+
+import "time"
+
+// UserIdentity vincula un usuario con su cuenta en un proveedor OIDC externo.
+// La clave es (Issuer, Subject): el email del proveedor puede cambiar.
+type UserIdentity struct {
+	ID        int64
+	UserID    int64
+	Issuer    string
+	Subject   string
+	Email     string // email informado por el proveedor al vincular
+	CreatedAt time.Time
+}
+
+// OIDCLoginState es un login OIDC en curso: se crea al redirigir al proveedor y
+// se consume en el callback. Guarda el nonce y el code_verifier de PKCE.
+type OIDCLoginState struct {
+	ID           int64
+	StateHash    string
+	Nonce        string
+	CodeVerifier string
+	ExpiresAt    time.Time
+	CreatedAt    time.Time
+	UsedAt       *time.Time
+}
+
+func (s OIDCLoginState) IsUsed() bool {
+	return s.UsedAt != nil
+}
+
+func (s OIDCLoginState) IsExpired(now time.Time) bool {
+	return !now.Before(s.ExpiresAt)
+}
+
+// OIDCClaims son los datos de un ID token ya validado (firma, iss, aud, exp y nonce).
+type OIDCClaims struct {
+	Issuer        string
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+}
@@ -0,0 +1,40 @@
+package domain
+
+//go:generate echo Generating mocks for oidc_port.go
+//go:generate go run go.uber.org/mock/mockgen@v0.5.0 -source=oidc_port.go -destination=../test/mocks/oidc_port_mock.go -package=mocks
+
+import (
+	"context"
+	"time"
+)
+
+type UserIdentityRepository interface {
+	// devuelve nil, nil si la identidad no está vinculada
+	FindBySubject(ctx context.Context, issuer, subject string) (*UserIdentity, error)
+
+	Create(ctx context.Context, i UserIdentity) (UserIdentity, error)
+
+	ListByUser(ctx context.Context, userID int64) ([]UserIdentity, error)
+}
+
+type OIDCStateRepository interface {
+	Create(ctx context.Context, s OIDCLoginState) (OIDCLoginState, error)
+
+	// devuelve nil, nil si no existe
+	FindByHash(ctx context.Context, stateHash string) (*OIDCLoginState, error)
+
+	// consume el state. used=false si ya estaba usado.
+	MarkUsed(ctx context.Context, id int64, at time.Time) (used bool, err error)
+
+	// borra los states vencidos antes de now y devuelve cuántos eliminó
+	DeleteExpired(ctx context.Context, now time.Time) (int64, error)
+}
+
+// OIDCProvider habla con el proveedor de identidad (authorization code + PKCE S256).
+type OIDCProvider interface {
+	// URL del proveedor a la que se redirige al usuario
+	AuthCodeURL(ctx context.Context, state, nonce, codeVerifier string) (string, error)
+
+	// canjea code por tokens y valida el ID token contra nonce
+	Exchange(ctx context.Context, code, codeVerifier, nonce string) (OIDCClaims, error)
+}
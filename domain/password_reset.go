@@ -0,0 +1,22 @@
+package domain
+
+import "time"
+
+// PasswordResetToken es un token opaco de un solo uso para restablecer la
+// contraseña; sólo se persiste su hash.
+type PasswordResetToken struct {
+	ID        int64
+	UserID    int64
+	TokenHash string
+	ExpiresAt time.Time
+	CreatedAt time.Time
+	UsedAt    *time.Time // consumido o invalidado por un pedido posterior
+}
+
+func (t PasswordResetToken) IsUsed() bool {
+	return t.UsedAt != nil
+}
+
+func (t PasswordResetToken) IsExpired(now time.Time) bool {
+	return !now.Before(t.ExpiresAt)
+}
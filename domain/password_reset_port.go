@@ -0,0 +1,22 @@
+package domain
+
+//go:generate echo Generating mocks for password_reset_port.go
+//go:generate go run go.uber.org/mock/mockgen@v0.5.0 -source=password_reset_port.go -destination=../test/mocks/password_reset_port_mock.go -package=mocks
+
+import (
+	"context"
+	"time"
+)
+
+type PasswordResetRepository interface {
+	Create(ctx context.Context, t PasswordResetToken) (PasswordResetToken, error)
+
+	// devuelve nil, nil si no existe
+	FindByHash(ctx context.Context, tokenHash string) (*PasswordResetToken, error)
+
+	// consume el token. used=false si ya estaba usado (otro request ganó la carrera).
+	MarkUsed(ctx context.Context, id int64, at time.Time) (used bool, err error)
+
+	// invalida todos los tokens todavía sin usar del usuario
+	InvalidateByUser(ctx context.Context, userID int64, at time.Time) error
+}
@@ -0,0 +1,37 @@
+package domain
+
+import "time"
+
+// Portfolio agrupa las tenencias de un usuario. BaseCurrency es la moneda en
+// la que se valúa si no se pide otra.
+type Portfolio struct {
+	ID           int64
+	UserID       int64
+	Name         string
+	BaseCurrency string
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+// Holding es la cantidad que el usuario tiene de una coin dentro de un portfolio.
+// Quantity es un decimal en texto (como los precios) para no perder precisión.
+type Holding struct {
+	ID          int64
+	PortfolioID int64
+	CoinID      int64
+	Symbol      string
+	Quantity    string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// PortfolioSnapshot es el valor ya calculado de un portfolio al cierre de un
+// día (UTC) en una moneda. Sólo se guardan días completos: todas las coins con
+// cantidad tenían precio.
+type PortfolioSnapshot struct {
+	PortfolioID int64
+	Day         time.Time // 00:00 UTC
+	Currency    string
+	Value       string
+	ComputedAt  time.Time
+}
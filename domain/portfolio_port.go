@@ -0,0 +1,48 @@
+package domain
+
+//go:generate echo Generating mocks for portfolio_port.go
+//go:generate go run go.uber.org/mock/mockgen@v0.5.0 -source=portfolio_port.go -destination=../test/mocks/portfolio_port_mock.go -package=mocks
+
+import (
+	"context"
+	"time"
+)
+
+type PortfolioRepository interface {
+	Create(ctx context.Context, p Portfolio) (Portfolio, error)
+
+	// devuelve nil, nil si no existe o no pertenece al usuario
+	FindByUser(ctx context.Context, userID, id int64) (*Portfolio, error)
+
+	// portfolios del usuario, por id ascendente
+	ListByUser(ctx context.Context, userID int64) ([]Portfolio, error)
+
+	CountByUser(ctx context.Context, userID int64) (int, error)
+
+	// guarda nombre y moneda base si el portfolio pertenece al usuario
+	Update(ctx context.Context, p Portfolio) (updated bool, err error)
+
+	// borra el portfolio (y sus tenencias) si pertenece al usuario
+	Delete(ctx context.Context, userID, id int64) (deleted bool, err error)
+}
+
+type HoldingRepository interface {
+	// crea la tenencia de (PortfolioID, CoinID) o le pisa la cantidad si ya existe
+	Upsert(ctx context.Context, h Holding) (Holding, error)
+
+	// tenencias del portfolio, por símbolo
+	ListByPortfolio(ctx context.Context, portfolioID int64) ([]Holding, error)
+
+	Delete(ctx context.Context, portfolioID, coinID int64) (deleted bool, err error)
+}
+
+type PortfolioSnapshotRepository interface {
+	// crea o reemplaza el snapshot de (PortfolioID, Day, Currency)
+	Upsert(ctx context.Context, s PortfolioSnapshot) error
+
+	// snapshots con día en [from, to], por día ascendente
+	ListRange(ctx context.Context, portfolioID int64, currency string, from, to time.Time) ([]PortfolioSnapshot, error)
+
+	// borra los snapshots desde ese día en adelante, en todas las monedas
+	DeleteFrom(ctx context.Context, portfolioID int64, from time.Time) error
+}
@@ -12,3 +12,25 @@ type Quote struct {
 	QuotedAt  time.Time
 	CreatedAt time.Time
 }
+
+// DailyClose es la última cotización de un símbolo en un día (UTC).
+type DailyClose struct {
+	Symbol   string
+	Day      time.Time // 00:00 UTC
+	Provider string
+	Price    string
+	QuotedAt time.Time
+}
+
+// QuoteTicker es la última cotización de un símbolo en una fuente junto con la
+// última hasta un instante de referencia. PrevPrice vacío = no hay referencia.
+type QuoteTicker struct {
+	Symbol   string
+	Provider string
+	Currency string
+	Price    string
+	QuotedAt time.Time
+
+	PrevPrice    string
+	PrevQuotedAt time.Time
+}
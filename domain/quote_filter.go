@@ -19,3 +19,29 @@ type QuoteFilter struct {
 	Page     int
 	PageSize int
 }
+
+// DailyCloseFilter toma quoted_at en [From, To). Provider vacío = cualquiera.
+type DailyCloseFilter struct {
+	Symbols  []string
+	Provider string
+	Currency string
+
+	From time.Time
+	To   time.Time
+}
+
+// ProviderCurrency es una fuente de cotizaciones: provider y la moneda en la
+// que cotiza.
+type ProviderCurrency struct {
+	Provider string
+	Currency string
+}
+
+// QuoteTickerFilter pide la última cotización de cada símbolo en cada par de
+// Pairs (vacío = cualquier provider/moneda) y la última hasta PrevAt como
+// referencia.
+type QuoteTickerFilter struct {
+	Symbols []string
+	Pairs   []ProviderCurrency
+	PrevAt  time.Time
+}
@@ -6,12 +6,24 @@ package domain
 import "context"
 
 type QuoteRepository interface {
-	Insert(ctx context.Context, q Quote) error
+	// Insert es idempotente sobre (coin_id, provider, currency, quoted_at):
+	// si la cotización ya existe no falla y devuelve inserted=false.
+	Insert(ctx context.Context, q Quote) (inserted bool, err error)
 
 	GetLatest(ctx context.Context, symbol, provider, currency string) (*PriceQuote, error)
 
 	ListFilter(ctx context.Context, f QuoteFilter) ([]Quote, int, error)
 
+	// ListDailyCloses devuelve la última cotización de cada día por símbolo,
+	// ordenadas por símbolo y día. Si varios providers cotizan en el mismo
+	// instante gana el primero por nombre.
+	ListDailyCloses(ctx context.Context, f DailyCloseFilter) ([]DailyClose, error)
+
+	// ListTickers resuelve en una sola consulta la última cotización y la de
+	// referencia de cada (símbolo, provider, moneda), ordenadas por símbolo,
+	// provider y moneda. Sin símbolos devuelve vacío.
+	ListTickers(ctx context.Context, f QuoteTickerFilter) ([]QuoteTicker, error)
+
 	// NEW: faceted filters ("tamiz")
 	ListAvailableFilters(ctx context.Context, f QuoteFilter) (QuoteFilters, error)
 }
@@ -0,0 +1,23 @@
+package domain
+
+import "time"
+
+// RefreshToken es un token opaco de larga duración; sólo se persiste su hash.
+// Todos los tokens emitidos por rotación a partir de un mismo login comparten FamilyID.
+type RefreshToken struct {
+	ID        int64
+	UserID    int64
+	FamilyID  string
+	TokenHash string
+	ExpiresAt time.Time
+	CreatedAt time.Time
+	RevokedAt *time.Time // usado (rotado) o revocado por logout/reuso
+}
+
+func (t RefreshToken) IsRevoked() bool {
+	return t.RevokedAt != nil
+}
+
+func (t RefreshToken) IsExpired(now time.Time) bool {
+	return !now.Before(t.ExpiresAt)
+}
@@ -0,0 +1,35 @@
+package domain
+
+//go:generate echo Generating mocks for refresh_token_port.go
+//go:generate go run go.uber.org/mock/mockgen@v0.5.0 -source=refresh_token_port.go -destination=../test/mocks/refresh_token_port_mock.go -package=mocks
+
+import (
+	"context"
+	"time"
+)
+
+type RefreshTokenRepository interface {
+	Create(ctx context.Context, t RefreshToken) (RefreshToken, error)
+
+	// devuelve nil, nil si no existe
+	FindByHash(ctx context.Context, tokenHash string) (*RefreshToken, error)
+
+	// marca el token como revocado. revoked=false si ya lo estaba
+	// (p. ej. otra rotación concurrente ganó la carrera).
+	Revoke(ctx context.Context, id int64, at time.Time) (revoked bool, err error)
+
+	// revoca todos los tokens todavía activos de la familia
+	RevokeFamily(ctx context.Context, familyID string, at time.Time) error
+
+	// revoca todos los tokens todavía activos del usuario (todas sus familias)
+	RevokeByUser(ctx context.Context, userID int64, at time.Time) error
+
+	// tokens del usuario sin revocar ni vencer en now, por id ascendente
+	ListActiveByUser(ctx context.Context, userID int64, now time.Time) ([]RefreshToken, error)
+}
+
+// OpaqueTokenService genera secretos aleatorios y su hash determinístico para persistir.
+type OpaqueTokenService interface {
+	Generate() (string, error)
+	Hash(token string) string
+}
@@ -0,0 +1,33 @@
+package domain
+
+// Repositories agrupa los puertos de persistencia de un mismo adapter sobre un
+// storage compartido (favoritos y quotes referencian coins/users). Cada adapter
+// lo arma con su NewRepositories.
+type Repositories struct {
+	Coins            CoinRepository
+	Quotes           QuoteRepository
+	Users            UserRepository
+	Favorites        FavoritesRepository
+	RefreshControl   RefreshControlRepository
+	RefreshTokens    RefreshTokenRepository
+	Revocations      TokenRevocationStore
+	APIKeys          APIKeyRepository
+	PasswordResets   PasswordResetRepository
+	Verifications    EmailVerificationRepository
+	LoginAttempts    LoginAttemptStore
+	TOTP             TOTPRepository
+	RecoveryCodes    RecoveryCodeRepository
+	Challenges       LoginChallengeRepository
+	Identities       UserIdentityRepository
+	OIDCStates       OIDCStateRepository
+	AlertRules       AlertRuleRepository
+	AlertEvents      AlertEventRepository
+	Webhooks         WebhookSubscriptionRepository
+	Deliveries       WebhookDeliveryRepository
+	Portfolios       PortfolioRepository
+	Holdings         HoldingRepository
+	Transactions     TransactionRepository
+	Snapshots        PortfolioSnapshotRepository
+	Watchlists       WatchlistRepository
+	WatchlistEntries WatchlistEntryRepository
+}
@@ -0,0 +1,24 @@
+package domain
+
+//go:generate echo Generating mocks for token_revocation_port.go
+//go:generate go run go.uber.org/mock/mockgen@v0.5.0 -source=token_revocation_port.go -destination=../test/mocks/token_revocation_port_mock.go -package=mocks
+
+import (
+	"context"
+	"time"
+)
+
+// TokenRevocationStore guarda qué access tokens (JWT) dejaron de valer antes de su exp.
+// Las entradas sólo tienen sentido hasta expiresAt: después el token ya vence solo.
+type TokenRevocationStore interface {
+	// agrega jti a la denylist
+	RevokeJTI(ctx context.Context, jti string, expiresAt time.Time) error
+
+	// invalida todos los tokens del usuario emitidos hasta issuedBefore (inclusive, en segundos)
+	RevokeUser(ctx context.Context, userID int64, issuedBefore, expiresAt time.Time) error
+
+	IsRevoked(ctx context.Context, jti string, userID int64, issuedAt time.Time) (bool, error)
+
+	// borra las entradas ya vencidas y devuelve cuántas eliminó
+	DeleteExpired(ctx context.Context, now time.Time) (int64, error)
+}
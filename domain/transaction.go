@@ -0,0 +1,48 @@
+package domain
+
+import "time"
+
+// Tipos de movimiento del libro de un portfolio.
+const (
+	TransactionBuy  = "buy"
+	TransactionSell = "sell"
+
+	// entrada/salida de coins sin compra ni venta (p. ej. entre wallets propias):
+	// no realizan ganancia
+	TransactionTransferIn  = "transfer_in"
+	TransactionTransferOut = "transfer_out"
+)
+
+func IsValidTransactionType(t string) bool {
+	switch t {
+	case TransactionBuy, TransactionSell, TransactionTransferIn, TransactionTransferOut:
+		return true
+	}
+	return false
+}
+
+// Transaction es un movimiento del libro de un portfolio. Quantity, Price
+// (por unidad) y Fee son decimales en texto, en Currency. En transfer_in Price
+// es el costo que se le asigna a lo que entra ("" si no se cargó, distinto de
+// un "0" explícito); en transfer_out no se usa.
+type Transaction struct {
+	ID          int64
+	PortfolioID int64
+	CoinID      int64
+	Symbol      string
+	Type        string
+	Quantity    string
+	Price       string
+	Fee         string
+	Currency    string
+	ExecutedAt  time.Time
+	Note        string
+	CreatedAt   time.Time
+}
+
+// TransactionFilter acota ListByPortfolio; los campos vacíos no filtran.
+type TransactionFilter struct {
+	CoinID int64
+	From   *time.Time // inclusive
+	To     *time.Time // exclusive
+}
@@ -0,0 +1,24 @@
+package domain
+
+//go:generate echo Generating mocks for transaction_port.go
+//go:generate go run go.uber.org/mock/mockgen@v0.5.0 -source=transaction_port.go -destination=../test/mocks/transaction_port_mock.go -package=mocks
+
+import "context"
+
+type TransactionRepository interface {
+	Create(ctx context.Context, t Transaction) (Transaction, error)
+
+	// crea t sólo si check acepta los movimientos de su coin en el portfolio
+	// (orden cronológico). Lectura, check y alta van en una transacción con el
+	// portfolio bloqueado, así dos salidas concurrentes no usan el mismo saldo.
+	// Si check falla devuelve ese error sin crear nada.
+	CreateChecked(ctx context.Context, t Transaction, check func(existing []Transaction) error) (Transaction, error)
+
+	// devuelve nil, nil si no existe en ese portfolio
+	FindByPortfolio(ctx context.Context, portfolioID, id int64) (*Transaction, error)
+
+	// movimientos del portfolio en orden cronológico (executed_at, id)
+	ListByPortfolio(ctx context.Context, portfolioID int64, f TransactionFilter) ([]Transaction, error)
+
+	Delete(ctx context.Context, portfolioID, id int64) (deleted bool, err error)
+}
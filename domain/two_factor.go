@@ -0,0 +1,38 @@
+package domain
+
+import "time"
+
+// TOTPEnrollment es el segundo factor TOTP (RFC 6238) de un usuario. Queda
+// pendiente hasta que el usuario confirma un primer código.
+type TOTPEnrollment struct {
+	UserID      int64
+	Secret      string // base32, sin padding
+	CreatedAt   time.Time
+	ConfirmedAt *time.Time
+
+	// último paso de 30s aceptado: un código no se puede reutilizar
+	LastUsedStep int64
+}
+
+func (e TOTPEnrollment) IsConfirmed() bool {
+	return e.ConfirmedAt != nil
+}
+
+// LoginChallenge es el token opaco de corta vida que recibe un usuario con 2FA
+// tras validar la contraseña; se canjea junto con un código por la sesión.
+type LoginChallenge struct {
+	ID        int64
+	UserID    int64
+	TokenHash string
+	ExpiresAt time.Time
+	CreatedAt time.Time
+	UsedAt    *time.Time
+}
+
+func (c LoginChallenge) IsUsed() bool {
+	return c.UsedAt != nil
+}
+
+func (c LoginChallenge) IsExpired(now time.Time) bool {
+	return !now.Before(c.ExpiresAt)
+}
@@ -0,0 +1,63 @@
+package domain
+
+//go:generate echo Generating mocks for two_factor_port.go
+//go:generate go run go.uber.org/mock/mockgen@v0.5.0 -source=two_factor_port.go -destination=../test/mocks/two_factor_port_mock.go -package=mocks
+
+import (
+	"context"
+	"time"
+)
+
+type TOTPRepository interface {
+	// devuelve nil, nil si el usuario no tiene 2FA (ni pendiente)
+	Get(ctx context.Context, userID int64) (*TOTPEnrollment, error)
+
+	// crea o reemplaza el enrolamiento del usuario (queda sin confirmar)
+	Save(ctx context.Context, e TOTPEnrollment) error
+
+	Confirm(ctx context.Context, userID int64, at time.Time) error
+
+	// registra step como usado. used=false si ya se aceptó ese paso o uno
+	// posterior (replay del mismo código o carrera entre dos logins).
+	MarkStepUsed(ctx context.Context, userID int64, step int64) (used bool, err error)
+
+	Delete(ctx context.Context, userID int64) error
+}
+
+// RecoveryCodeRepository guarda los hashes de los códigos de recuperación (un solo uso).
+type RecoveryCodeRepository interface {
+	// reemplaza todos los códigos del usuario; con hashes vacío los borra
+	Replace(ctx context.Context, userID int64, hashes []string, at time.Time) error
+
+	// consume el código. used=false si no existe o ya se había usado.
+	Use(ctx context.Context, userID int64, hash string, at time.Time) (used bool, err error)
+
+	CountUnused(ctx context.Context, userID int64) (int, error)
+}
+
+type LoginChallengeRepository interface {
+	Create(ctx context.Context, c LoginChallenge) (LoginChallenge, error)
+
+	// devuelve nil, nil si no existe
+	FindByHash(ctx context.Context, tokenHash string) (*LoginChallenge, error)
+
+	// consume el challenge. used=false si ya estaba usado.
+	MarkUsed(ctx context.Context, id int64, at time.Time) (used bool, err error)
+
+	// borra los challenges vencidos antes de now y devuelve cuántos eliminó
+	DeleteExpired(ctx context.Context, now time.Time) (int64, error)
+}
+
+// TOTPService implementa el algoritmo TOTP y genera los secretos del segundo factor.
+type TOTPService interface {
+	GenerateSecret() (string, error)
+
+	// URI otpauth://totp/... para mostrar como QR en la app autenticadora
+	URI(issuer, account, secret string) string
+
+	// valida code contra secret en at (con tolerancia de reloj) y devuelve el
+	// paso de tiempo que coincidió
+	Validate(secret, code string, at time.Time) (step int64, ok bool)
+
+	GenerateRecoveryCode() (string, error)
+}
@@ -2,10 +2,27 @@ package domain
 
 import "time"
 
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+)
+
 type User struct {
 	ID           int64
 	Email        string
 	Name         string
 	PasswordHash string
+	Role         string // RoleUser | RoleAdmin
 	CreatedAt    time.Time
+
+	EmailVerifiedAt *time.Time // nil = cuenta sin verificar
+}
+
+func (u User) IsEmailVerified() bool {
+	return u.EmailVerifiedAt != nil
+}
+
+// IsValidRole indica si r es uno de los roles conocidos.
+func IsValidRole(r string) bool {
+	return r == RoleUser || r == RoleAdmin
 }
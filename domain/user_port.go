@@ -3,13 +3,29 @@ package domain
 //go:generate echo Generating mocks for user_port.go
 //go:generate go run go.uber.org/mock/mockgen@v0.5.0 -source=user_port.go -destination=../test/mocks/user_port_mock.go -package=mocks
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 type UserRepository interface {
 	ExistsByEmail(ctx context.Context, email string) (bool, error)
 	Create(ctx context.Context, u User) (User, error)
 
 	FindByEmail(ctx context.Context, email string) (*User, error)
+	FindByID(ctx context.Context, id int64) (*User, error)
+
+	UpdateRole(ctx context.Context, userID int64, role string) error
+	UpdatePassword(ctx context.Context, userID int64, passwordHash string) error
+
+	// no-op si ya estaba verificado
+	MarkEmailVerified(ctx context.Context, userID int64, at time.Time) error
+
+	// guarda name, email y email_verified_at de u (cambiar el email puede dejar la cuenta sin verificar)
+	UpdateProfile(ctx context.Context, u User) error
+
+	// borra el usuario; lo que referencia a users se borra en cascada
+	Delete(ctx context.Context, userID int64) error
 }
 
 type PasswordHasher interface {
@@ -18,5 +34,5 @@ type PasswordHasher interface {
 }
 
 type TokenService interface {
-	Generate(userID int64, email string) (string, error)
+	Generate(userID int64, email, role string) (string, error)
 }
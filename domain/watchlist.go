@@ -0,0 +1,30 @@
+package domain
+
+import "time"
+
+// DefaultWatchlistName es el nombre con que se crea la lista por defecto, la
+// que muestran los endpoints de favoritas.
+const DefaultWatchlistName = "Favorites"
+
+// Watchlist es una lista de coins con nombre. Cada usuario tiene a lo sumo una
+// lista por defecto (IsDefault), que no se puede borrar.
+type Watchlist struct {
+	ID        int64
+	UserID    int64
+	Name      string
+	IsDefault bool
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// WatchlistEntry es una coin dentro de una watchlist. Position sólo ordena: puede
+// tener huecos después de quitar coins.
+type WatchlistEntry struct {
+	WatchlistID int64
+	CoinID      int64
+	Symbol      string
+	Position    int
+	Note        string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
@@ -0,0 +1,46 @@
+package domain
+
+//go:generate echo Generating mocks for watchlist_port.go
+//go:generate go run go.uber.org/mock/mockgen@v0.5.0 -source=watchlist_port.go -destination=../test/mocks/watchlist_port_mock.go -package=mocks
+
+import (
+	"context"
+	"time"
+)
+
+type WatchlistRepository interface {
+	Create(ctx context.Context, w Watchlist) (Watchlist, error)
+
+	// devuelve la lista por defecto de w.UserID; si no tiene, la crea con el
+	// nombre y las fechas de w. Dos llamadas concurrentes no crean dos listas.
+	EnsureDefault(ctx context.Context, w Watchlist) (Watchlist, error)
+
+	// devuelve nil, nil si no existe o no pertenece al usuario
+	FindByUser(ctx context.Context, userID, id int64) (*Watchlist, error)
+
+	// listas del usuario: la por defecto primero, después por id ascendente
+	ListByUser(ctx context.Context, userID int64) ([]Watchlist, error)
+
+	CountByUser(ctx context.Context, userID int64) (int, error)
+
+	// guarda el nombre si la lista pertenece al usuario
+	Update(ctx context.Context, w Watchlist) (updated bool, err error)
+
+	// borra la lista (y sus entradas) si pertenece al usuario y no es la por defecto
+	Delete(ctx context.Context, userID, id int64) (deleted bool, err error)
+}
+
+type WatchlistEntryRepository interface {
+	// agrega la coin al final de la lista; si ya estaba no cambia nada
+	Add(ctx context.Context, e WatchlistEntry) (added bool, err error)
+
+	UpdateNote(ctx context.Context, watchlistID, coinID int64, note string, at time.Time) (updated bool, err error)
+
+	Remove(ctx context.Context, watchlistID, coinID int64) (removed bool, err error)
+
+	// entradas de la lista por posición
+	ListByWatchlist(ctx context.Context, watchlistID int64) ([]WatchlistEntry, error)
+
+	// asigna las posiciones 0..n-1 en el orden de coinIDs, todo o nada
+	Reorder(ctx context.Context, watchlistID int64, coinIDs []int64) error
+}
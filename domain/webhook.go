@@ -0,0 +1,75 @@
+package domain
+
+import "time"
+
+// Eventos que se pueden suscribir con un webhook.
+const (
+	WebhookEventQuotesStored   = "quotes.stored"   // quotes nuevas de un refresh (a todos los suscriptos)
+	WebhookEventAlertTriggered = "alert.triggered" // se disparó una alerta (sólo al dueño de la regla)
+)
+
+// Estados de una entrega de webhook.
+const (
+	WebhookDeliveryPending   = "pending"   // espera su próximo intento
+	WebhookDeliveryDelivered = "delivered" // el receptor respondió 2xx
+	WebhookDeliveryDead      = "dead"      // se agotaron los intentos; sólo sale con un replay
+)
+
+// IsValidWebhookEvent indica si e es uno de los eventos conocidos.
+func IsValidWebhookEvent(e string) bool {
+	switch e {
+	case WebhookEventQuotesStored, WebhookEventAlertTriggered:
+		return true
+	}
+	return false
+}
+
+// IsValidWebhookDeliveryStatus indica si s es uno de los estados de entrega.
+func IsValidWebhookDeliveryStatus(s string) bool {
+	switch s {
+	case WebhookDeliveryPending, WebhookDeliveryDelivered, WebhookDeliveryDead:
+		return true
+	}
+	return false
+}
+
+// WebhookSubscription es un endpoint de un usuario que recibe eventos por POST.
+// Secret se guarda en claro porque hace falta para firmar cada entrega; la API
+// sólo lo muestra al crear la suscripción.
+type WebhookSubscription struct {
+	ID        int64
+	UserID    int64
+	URL       string
+	Events    []string
+	Secret    string
+	CreatedAt time.Time
+}
+
+func (s WebhookSubscription) HasEvent(event string) bool {
+	for _, e := range s.Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookDelivery es una entrega del outbox: el payload queda fijo al encolarla
+// para que los reintentos y replays manden exactamente lo mismo.
+type WebhookDelivery struct {
+	ID             int64
+	SubscriptionID int64
+	EventID        string
+	EventType      string
+	Payload        string
+
+	Status        string
+	Attempts      int
+	NextAttemptAt time.Time
+
+	LastAttemptAt  *time.Time
+	LastStatusCode int // 0 = sin respuesta HTTP
+	LastError      string
+	DeliveredAt    *time.Time
+	CreatedAt      time.Time
+}
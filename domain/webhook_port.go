@@ -0,0 +1,64 @@
+package domain
+
+//go:generate echo Generating mocks for webhook_port.go
+//go:generate go run go.uber.org/mock/mockgen@v0.5.0 -source=webhook_port.go -destination=../test/mocks/webhook_port_mock.go -package=mocks
+
+import (
+	"context"
+	"time"
+)
+
+type WebhookSubscriptionRepository interface {
+	Create(ctx context.Context, s WebhookSubscription) (WebhookSubscription, error)
+
+	// devuelve nil, nil si no existe o no pertenece al usuario
+	FindByUser(ctx context.Context, userID, id int64) (*WebhookSubscription, error)
+
+	// devuelve nil, nil si no existe (para el dispatcher, que no conoce al usuario)
+	FindByID(ctx context.Context, id int64) (*WebhookSubscription, error)
+
+	// suscripciones del usuario, por id ascendente
+	ListByUser(ctx context.Context, userID int64) ([]WebhookSubscription, error)
+
+	CountByUser(ctx context.Context, userID int64) (int, error)
+
+	// suscripciones al evento, por id ascendente. userID=0 = de cualquier usuario.
+	ListByEvent(ctx context.Context, event string, userID int64) ([]WebhookSubscription, error)
+
+	// borra la suscripción (y sus entregas) si pertenece al usuario
+	Delete(ctx context.Context, userID, id int64) (deleted bool, err error)
+}
+
+type WebhookDeliveryRepository interface {
+	Enqueue(ctx context.Context, d WebhookDelivery) (WebhookDelivery, error)
+
+	// entregas pending con NextAttemptAt <= now, las más atrasadas primero
+	ListDue(ctx context.Context, now time.Time, limit int) ([]WebhookDelivery, error)
+
+	// reserva la entrega para un intento: suma uno a Attempts y corre
+	// NextAttemptAt a leaseUntil (si el proceso muere, se reintenta después).
+	// claimed=false si ya no está pending o otro dispatcher la tomó antes.
+	Claim(ctx context.Context, id int64, attempts int, leaseUntil time.Time) (claimed bool, err error)
+
+	// guarda el resultado de un intento: Status, NextAttemptAt, LastAttemptAt,
+	// LastStatusCode, LastError y DeliveredAt
+	RecordAttempt(ctx context.Context, d WebhookDelivery) error
+
+	// devuelve nil, nil si no existe o es de otra suscripción
+	FindBySubscription(ctx context.Context, subscriptionID, id int64) (*WebhookDelivery, error)
+
+	// entregas de la suscripción, de la más nueva a la más vieja. status="" = todas.
+	ListBySubscription(ctx context.Context, subscriptionID int64, status string, limit int) ([]WebhookDelivery, error)
+
+	// vuelve a pending (con Attempts en cero) las entregas dead de la suscripción;
+	// id=0 = todas. Devuelve cuántas reencoló.
+	Replay(ctx context.Context, subscriptionID, id int64, now time.Time) (int64, error)
+
+	// borra las entregas exitosas anteriores a before y devuelve cuántas eliminó
+	DeleteDeliveredBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
+// WebhookSender hace el POST al receptor. statusCode=0 si no hubo respuesta.
+type WebhookSender interface {
+	Send(ctx context.Context, url string, headers map[string]string, body []byte) (statusCode int, err error)
+}
@@ -367,9 +367,7 @@ async function getFavSymbolsSet() {
   // cache liviano para no pegarle todo el tiempo
   if (cachedFavSymbols) return cachedFavSymbols;
 
-  const res = await fetch("/api/v1/users/me/favorites", {
-    headers: { Authorization: `Bearer ${token}` },
-  });
+  const res = await authFetch("/api/v1/users/me/favorites");
   if (!res.ok) throw new Error(`HTTP ${res.status}`);
   const favs = await res.json();
   //cachedFavSymbols = new Set(favs.map(x => x.symbol));
@@ -483,10 +481,16 @@ async function getFavSymbolsSet() {
   // Auth (JWT)
   // =========================
   const TOKEN_KEY = "crypto_api_token";
+  const REFRESH_KEY = "crypto_api_refresh_token";
 
   const getToken = () => localStorage.getItem(TOKEN_KEY) || "";
   const setToken = (t) => localStorage.setItem(TOKEN_KEY, t);
-  const clearToken = () => localStorage.removeItem(TOKEN_KEY);
+  const getRefreshToken = () => localStorage.getItem(REFRESH_KEY) || "";
+  const setRefreshToken = (t) => (t ? localStorage.setItem(REFRESH_KEY, t) : localStorage.removeItem(REFRESH_KEY));
+  const clearToken = () => {
+    localStorage.removeItem(TOKEN_KEY);
+    localStorage.removeItem(REFRESH_KEY);
+  };
   const isLoggedIn = () => Boolean(getToken());
 
   const btnOpenLogin = $("btn-open-login");
@@ -524,11 +528,50 @@ async function getFavSymbolsSet() {
 }
 
 
+  // Un solo refresh en vuelo: si varios requests reciben 401 a la vez, comparten la rotación
+  let refreshInFlight = null;
+
+  async function refreshSession() {
+    const refreshToken = getRefreshToken();
+    if (!refreshToken) return false;
+
+    if (!refreshInFlight) {
+      refreshInFlight = (async () => {
+        try {
+          const res = await fetch("/api/v1/auth/refresh", {
+            method: "POST",
+            headers: { "Content-Type": "application/json" },
+            body: JSON.stringify({ refresh_token: refreshToken }),
+          });
+          if (!res.ok) {
+            clearToken();
+            return false;
+          }
+          const data = await res.json();
+          setToken(data.access_token);
+          setRefreshToken(data.refresh_token);
+          return true;
+        } catch (_) {
+          return false;
+        } finally {
+          refreshInFlight = null;
+        }
+      })();
+    }
+    return refreshInFlight;
+  }
+
   async function authFetch(url, options = {}) {
-    const token = getToken();
-    const headers = new Headers(options.headers || {});
-    if (token) headers.set("Authorization", `Bearer ${token}`);
-    return fetch(url, { ...options, headers });
+    const doFetch = () => {
+      const token = getToken();
+      const headers = new Headers(options.headers || {});
+      if (token) headers.set("Authorization", `Bearer ${token}`);
+      return fetch(url, { ...options, headers });
+    };
+
+    const res = await doFetch();
+    if (res.status !== 401 || !(await refreshSession())) return res;
+    return doFetch();
   }
 
   let cachedFavSymbols = null;
@@ -580,7 +623,27 @@ async function refreshAllUI() {
   // Abrir modal de login
 btnOpenLogin?.addEventListener("click", async () => {
   if (isLoggedIn()) {
+    const accessToken = getToken();
+    const refreshToken = getRefreshToken();
     clearToken();
+
+    // best-effort: revoca la sesión en el server (access + refresh)
+    const logout = (withBearer) => {
+      const headers = { "Content-Type": "application/json" };
+      if (withBearer) headers.Authorization = `Bearer ${accessToken}`;
+      return fetch("/api/v1/auth/logout", {
+        method: "POST",
+        headers,
+        body: JSON.stringify({ refresh_token: refreshToken }),
+      });
+    };
+    logout(true)
+      .then((res) => {
+        // access token vencido: igual revocamos el refresh
+        if (res.status === 401 && refreshToken) return logout(false);
+      })
+      .catch(() => {});
+
     await refreshAllUI();
     return;
   }
@@ -622,6 +685,11 @@ btnOpenLogin?.addEventListener("click", async () => {
       if (!res.ok) {
         // Intentamos leer mensaje si viene JSON, sino usamos status
         let msg = `Login inválido (HTTP ${res.status})`;
+        if (res.status === 429) {
+          const wait = res.headers.get("Retry-After");
+          showModal(`Demasiados intentos fallidos. Probá de nuevo en ${wait || "unos"} segundos.`);
+          return;
+        }
         try {
           const t = await res.text();
           if (t) msg = t;
@@ -631,7 +699,25 @@ btnOpenLogin?.addEventListener("click", async () => {
         return;
       }
 
-      const data = await res.json();
+      let data = await res.json();
+
+      // usuario con 2FA: se pide el código y se canjea el challenge
+      if (data.two_factor_required) {
+        const code = window.prompt("Código de la app autenticadora (o código de recuperación):");
+        if (!code) return;
+
+        const res2 = await fetch("/api/v1/auth/login/2fa", {
+          method: "POST",
+          headers: { "Content-Type": "application/json" },
+          body: JSON.stringify({ challenge_token: data.challenge_token, code: code.trim() }),
+        });
+        if (!res2.ok) {
+          showModal(res2.status === 429 ? "Demasiados intentos fallidos." : "Código inválido.");
+          return;
+        }
+        data = await res2.json();
+      }
+
       const token = data.access_token || data.accessToken || data.AccessToken;
 
       if (!token) {
@@ -641,6 +727,7 @@ btnOpenLogin?.addEventListener("click", async () => {
       }
 
       setToken(token);
+      setRefreshToken(data.refresh_token || "");
       await refreshAllUI();
      
 
@@ -726,10 +813,11 @@ btnOpenLogin?.addEventListener("click", async () => {
 
     const coinsProcessed = data.coins_processed ?? "-";
     const quotesSaved = data.quotes_saved ?? "-";
+    const duplicates = data.duplicates_skipped ?? "-";
     const failed = data.failed ?? "-";
 
     showToast(
-      `Refresh OK ✅ Coins: ${coinsProcessed} | Quotes: ${quotesSaved} | Failed: ${failed}`,
+      `Refresh OK ✅ Coins: ${coinsProcessed} | Quotes: ${quotesSaved} | Duplicadas: ${duplicates} | Failed: ${failed}`,
       "success"
     );
 
@@ -958,14 +1046,27 @@ async function loadFavorites() {
   list.innerHTML = "";
 
   if (!token) {
-    if (hint) hint.style.display = "block";
+    if (hint) {
+      hint.textContent = "(Logueate para ver tus favoritas)";
+      hint.style.display = "block";
+    }
     return [];
   }
   if (hint) hint.style.display = "none";
 
-  const res = await fetch("/api/v1/users/me/favorites", {
-    headers: { Authorization: `Bearer ${token}` },
-  });
+  const res = await authFetch("/api/v1/users/me/favorites");
+
+  // cuenta sin verificar: favoritas bloqueadas hasta confirmar el email
+  if (res.status === 403) {
+    const body = await res.json().catch(() => ({}));
+    if (body.error === "email_not_verified") {
+      if (hint) {
+        hint.textContent = "Confirmá tu email (revisá tu casilla) para usar favoritas.";
+        hint.style.display = "block";
+      }
+      return [];
+    }
+  }
 
   if (!res.ok) throw new Error(`HTTP ${res.status}`);
   const favs = await res.json();
@@ -999,11 +1100,14 @@ async function addFavorite(symbol) {
     return;
   }
 
-  const res = await fetch(`/api/v1/users/me/favorites/${encodeURIComponent(symbol)}`, {
+  const res = await authFetch(`/api/v1/users/me/favorites/${encodeURIComponent(symbol)}`, {
     method: "POST",
-    headers: { Authorization: `Bearer ${token}` },
   });
 
+  if (res.status === 403) {
+    showConfirmModal("Confirmá tu email para agregar favoritas.");
+    return;
+  }
   if (!res.ok) throw new Error(`HTTP ${res.status}`);
 
   await refreshAllUI();
@@ -1013,9 +1117,8 @@ async function removeFavorite(symbol) {
   const token = getToken();
   if (!token) return;
 
-  const res = await fetch(`/api/v1/users/me/favorites/${encodeURIComponent(symbol)}`, {
+  const res = await authFetch(`/api/v1/users/me/favorites/${encodeURIComponent(symbol)}`, {
     method: "DELETE",
-    headers: { Authorization: `Bearer ${token}` },
   });
 
   if (!res.ok) throw new Error(`HTTP ${res.status}`);
@@ -0,0 +1,13 @@
+-- Unicidad de cotizaciones por (coin_id, provider, currency, quoted_at).
+-- En bases existentes correr una vez a mano: primero elimina los duplicados
+-- (se conserva la fila de menor id) y luego agrega la clave única.
+DELETE q1 FROM quotes q1
+JOIN quotes q2
+  ON q1.coin_id = q2.coin_id
+ AND q1.provider = q2.provider
+ AND q1.currency = q2.currency
+ AND q1.quoted_at = q2.quoted_at
+ AND q1.id > q2.id;
+
+ALTER TABLE quotes
+  ADD UNIQUE KEY uk_quotes_coin_provider_currency_time (coin_id, provider, currency, quoted_at);
@@ -0,0 +1,3 @@
+-- Rol del usuario para control de acceso (user | admin).
+ALTER TABLE users
+  ADD COLUMN role VARCHAR(16) NOT NULL DEFAULT 'user';
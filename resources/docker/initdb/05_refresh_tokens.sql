@@ -0,0 +1,15 @@
+-- Refresh tokens opacos: sólo se guarda el SHA-256. family_id agrupa las rotaciones de un login.
+CREATE TABLE IF NOT EXISTS refresh_tokens (
+  id BIGINT NOT NULL AUTO_INCREMENT,
+  user_id BIGINT NOT NULL,
+  family_id VARCHAR(64) NOT NULL,
+  token_hash CHAR(64) NOT NULL,
+  expires_at DATETIME NOT NULL,
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  revoked_at DATETIME NULL,
+  PRIMARY KEY (id),
+  UNIQUE KEY uk_refresh_tokens_hash (token_hash),
+  INDEX idx_refresh_tokens_family (family_id),
+  INDEX idx_refresh_tokens_user (user_id),
+  CONSTRAINT fk_refresh_tokens_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
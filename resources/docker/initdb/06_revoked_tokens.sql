@@ -0,0 +1,17 @@
+-- Denylist de access tokens (jti) y cortes por usuario ("revocar todo lo emitido hasta X").
+-- Las filas se purgan al pasar expires_at: para entonces el JWT ya venció solo.
+CREATE TABLE IF NOT EXISTS revoked_tokens (
+  jti VARCHAR(64) NOT NULL,
+  expires_at DATETIME NOT NULL,
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (jti),
+  INDEX idx_revoked_tokens_expires (expires_at)
+);
+
+CREATE TABLE IF NOT EXISTS revoked_user_tokens (
+  user_id BIGINT NOT NULL,
+  revoked_before DATETIME NOT NULL,
+  expires_at DATETIME NOT NULL,
+  PRIMARY KEY (user_id),
+  INDEX idx_revoked_user_tokens_expires (expires_at)
+);
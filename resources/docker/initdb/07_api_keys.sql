@@ -0,0 +1,18 @@
+-- API keys de usuario para clientes máquina a máquina. Sólo se guarda el SHA-256;
+-- prefix queda en claro para identificarlas en el listado. scopes: lista separada por comas.
+CREATE TABLE IF NOT EXISTS api_keys (
+  id BIGINT NOT NULL AUTO_INCREMENT,
+  user_id BIGINT NOT NULL,
+  name VARCHAR(100) NOT NULL,
+  prefix VARCHAR(16) NOT NULL,
+  key_hash CHAR(64) NOT NULL,
+  scopes VARCHAR(255) NOT NULL,
+  expires_at DATETIME NULL,
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  last_used_at DATETIME NULL,
+  revoked_at DATETIME NULL,
+  PRIMARY KEY (id),
+  UNIQUE KEY uk_api_keys_hash (key_hash),
+  INDEX idx_api_keys_user (user_id),
+  CONSTRAINT fk_api_keys_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
@@ -0,0 +1,13 @@
+-- Tokens de reset de contraseña: un solo uso, con vencimiento. Sólo se guarda el SHA-256.
+CREATE TABLE IF NOT EXISTS password_reset_tokens (
+  id BIGINT NOT NULL AUTO_INCREMENT,
+  user_id BIGINT NOT NULL,
+  token_hash CHAR(64) NOT NULL,
+  expires_at DATETIME NOT NULL,
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  used_at DATETIME NULL,
+  PRIMARY KEY (id),
+  UNIQUE KEY uk_password_reset_tokens_hash (token_hash),
+  INDEX idx_password_reset_tokens_user (user_id),
+  CONSTRAINT fk_password_reset_tokens_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
@@ -0,0 +1,19 @@
+-- Verificación de email: las cuentas nuevas nacen sin verificar. Las existentes
+-- se dan por verificadas para no restringirlas retroactivamente.
+ALTER TABLE users
+  ADD COLUMN email_verified_at DATETIME NULL;
+
+UPDATE users SET email_verified_at = created_at WHERE email_verified_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS email_verification_tokens (
+  id BIGINT NOT NULL AUTO_INCREMENT,
+  user_id BIGINT NOT NULL,
+  token_hash CHAR(64) NOT NULL,
+  expires_at DATETIME NOT NULL,
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  used_at DATETIME NULL,
+  PRIMARY KEY (id),
+  UNIQUE KEY uk_email_verification_tokens_hash (token_hash),
+  INDEX idx_email_verification_tokens_user (user_id, created_at),
+  CONSTRAINT fk_email_verification_tokens_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
@@ -0,0 +1,9 @@
+-- Logins fallidos por cuenta ("account:<email>") o IP ("ip:<addr>") para el bloqueo temporal.
+CREATE TABLE IF NOT EXISTS login_attempts (
+  attempt_key VARCHAR(320) NOT NULL,
+  failures INT NOT NULL,
+  last_failure_at DATETIME NOT NULL,
+  locked_until DATETIME NULL,
+  PRIMARY KEY (attempt_key),
+  INDEX idx_login_attempts_last_failure (last_failure_at)
+);
@@ -0,0 +1,35 @@
+-- Segundo factor TOTP: secreto por usuario, códigos de recuperación (sólo SHA-256)
+-- y challenges de login entre la contraseña y el código.
+CREATE TABLE IF NOT EXISTS user_totp (
+  user_id BIGINT NOT NULL,
+  secret VARCHAR(64) NOT NULL,
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  confirmed_at DATETIME NULL,
+  last_used_step BIGINT NOT NULL DEFAULT 0,
+  PRIMARY KEY (user_id),
+  CONSTRAINT fk_user_totp_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS totp_recovery_codes (
+  id BIGINT NOT NULL AUTO_INCREMENT,
+  user_id BIGINT NOT NULL,
+  code_hash CHAR(64) NOT NULL,
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  used_at DATETIME NULL,
+  PRIMARY KEY (id),
+  UNIQUE KEY uk_totp_recovery_codes_user_hash (user_id, code_hash),
+  CONSTRAINT fk_totp_recovery_codes_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS login_challenges (
+  id BIGINT NOT NULL AUTO_INCREMENT,
+  user_id BIGINT NOT NULL,
+  token_hash CHAR(64) NOT NULL,
+  expires_at DATETIME NOT NULL,
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  used_at DATETIME NULL,
+  PRIMARY KEY (id),
+  UNIQUE KEY uk_login_challenges_hash (token_hash),
+  INDEX idx_login_challenges_expires (expires_at),
+  CONSTRAINT fk_login_challenges_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
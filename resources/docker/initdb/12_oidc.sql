@@ -0,0 +1,27 @@
+-- Login con un proveedor OIDC: identidades vinculadas (iss + sub) y los
+-- states de los logins en curso (nonce y code_verifier de PKCE).
+CREATE TABLE IF NOT EXISTS user_identities (
+  id BIGINT NOT NULL AUTO_INCREMENT,
+  user_id BIGINT NOT NULL,
+  issuer VARCHAR(255) NOT NULL,
+  subject VARCHAR(255) NOT NULL,
+  email VARCHAR(255) NOT NULL DEFAULT '',
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (id),
+  UNIQUE KEY uk_user_identities_subject (issuer, subject),
+  INDEX idx_user_identities_user (user_id),
+  CONSTRAINT fk_user_identities_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS oidc_login_states (
+  id BIGINT NOT NULL AUTO_INCREMENT,
+  state_hash CHAR(64) NOT NULL,
+  nonce VARCHAR(128) NOT NULL,
+  code_verifier VARCHAR(128) NOT NULL,
+  expires_at DATETIME NOT NULL,
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  used_at DATETIME NULL,
+  PRIMARY KEY (id),
+  UNIQUE KEY uk_oidc_login_states_hash (state_hash),
+  INDEX idx_oidc_login_states_expires (expires_at)
+);
@@ -0,0 +1,43 @@
+-- Alertas de precio: reglas de cada usuario y los eventos de las que se dispararon.
+CREATE TABLE IF NOT EXISTS alert_rules (
+  id BIGINT NOT NULL AUTO_INCREMENT,
+  user_id BIGINT NOT NULL,
+  coin_id BIGINT NOT NULL,
+  symbol VARCHAR(20) NOT NULL,
+  currency VARCHAR(10) NOT NULL DEFAULT '',
+  provider VARCHAR(50) NOT NULL DEFAULT '',
+  kind VARCHAR(20) NOT NULL,
+  threshold DOUBLE NOT NULL,
+  window_seconds INT NOT NULL DEFAULT 0,
+  rearm BOOLEAN NOT NULL DEFAULT FALSE,
+  active BOOLEAN NOT NULL DEFAULT TRUE,
+  armed BOOLEAN NOT NULL DEFAULT TRUE,
+  last_triggered_at DATETIME NULL,
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (id),
+  INDEX idx_alert_rules_user (user_id),
+  INDEX idx_alert_rules_active (active),
+  CONSTRAINT fk_alert_rules_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+  CONSTRAINT fk_alert_rules_coin FOREIGN KEY (coin_id) REFERENCES coins(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS alert_events (
+  id BIGINT NOT NULL AUTO_INCREMENT,
+  rule_id BIGINT NOT NULL,
+  user_id BIGINT NOT NULL,
+  symbol VARCHAR(20) NOT NULL,
+  provider VARCHAR(50) NOT NULL,
+  currency VARCHAR(10) NOT NULL,
+  kind VARCHAR(20) NOT NULL,
+  threshold DOUBLE NOT NULL,
+  price DECIMAL(30,10) NOT NULL,
+  quoted_at DATETIME NOT NULL,
+  reference_price DECIMAL(30,10) NULL,
+  change_percent DOUBLE NULL,
+  triggered_at DATETIME NOT NULL,
+  PRIMARY KEY (id),
+  INDEX idx_alert_events_user (user_id, triggered_at),
+  CONSTRAINT fk_alert_events_rule FOREIGN KEY (rule_id) REFERENCES alert_rules(id) ON DELETE CASCADE,
+  CONSTRAINT fk_alert_events_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
@@ -0,0 +1,32 @@
+-- Webhooks salientes: suscripciones de cada usuario y el outbox de entregas.
+CREATE TABLE IF NOT EXISTS webhook_subscriptions (
+  id BIGINT NOT NULL AUTO_INCREMENT,
+  user_id BIGINT NOT NULL,
+  url VARCHAR(2048) NOT NULL,
+  events VARCHAR(255) NOT NULL,
+  secret VARCHAR(255) NOT NULL,
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (id),
+  INDEX idx_webhook_subscriptions_user (user_id),
+  CONSTRAINT fk_webhook_subscriptions_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+  id BIGINT NOT NULL AUTO_INCREMENT,
+  subscription_id BIGINT NOT NULL,
+  event_id VARCHAR(64) NOT NULL,
+  event_type VARCHAR(50) NOT NULL,
+  payload MEDIUMTEXT NOT NULL,
+  status VARCHAR(20) NOT NULL,
+  attempts INT NOT NULL DEFAULT 0,
+  next_attempt_at DATETIME NOT NULL,
+  last_attempt_at DATETIME NULL,
+  last_status_code INT NOT NULL DEFAULT 0,
+  last_error VARCHAR(500) NOT NULL DEFAULT '',
+  delivered_at DATETIME NULL,
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (id),
+  INDEX idx_webhook_deliveries_due (status, next_attempt_at),
+  INDEX idx_webhook_deliveries_subscription (subscription_id, created_at),
+  CONSTRAINT fk_webhook_deliveries_subscription FOREIGN KEY (subscription_id) REFERENCES webhook_subscriptions(id) ON DELETE CASCADE
+);
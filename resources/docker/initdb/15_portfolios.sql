@@ -0,0 +1,25 @@
+-- Portfolios de cada usuario y sus tenencias (una fila por coin).
+CREATE TABLE IF NOT EXISTS portfolios (
+  id BIGINT NOT NULL AUTO_INCREMENT,
+  user_id BIGINT NOT NULL,
+  name VARCHAR(100) NOT NULL,
+  base_currency VARCHAR(10) NOT NULL,
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (id),
+  INDEX idx_portfolios_user (user_id),
+  CONSTRAINT fk_portfolios_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS portfolio_holdings (
+  id BIGINT NOT NULL AUTO_INCREMENT,
+  portfolio_id BIGINT NOT NULL,
+  coin_id BIGINT NOT NULL,
+  quantity DECIMAL(38,18) NOT NULL,
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (id),
+  UNIQUE KEY uq_portfolio_holdings_coin (portfolio_id, coin_id),
+  CONSTRAINT fk_portfolio_holdings_portfolio FOREIGN KEY (portfolio_id) REFERENCES portfolios(id) ON DELETE CASCADE,
+  CONSTRAINT fk_portfolio_holdings_coin FOREIGN KEY (coin_id) REFERENCES coins(id) ON DELETE CASCADE
+);
@@ -0,0 +1,18 @@
+-- Libro de movimientos de cada portfolio (compras, ventas y transferencias).
+CREATE TABLE IF NOT EXISTS portfolio_transactions (
+  id BIGINT NOT NULL AUTO_INCREMENT,
+  portfolio_id BIGINT NOT NULL,
+  coin_id BIGINT NOT NULL,
+  type VARCHAR(20) NOT NULL,
+  quantity DECIMAL(38,18) NOT NULL,
+  price DECIMAL(38,18) NOT NULL,
+  fee DECIMAL(38,18) NOT NULL,
+  currency VARCHAR(10) NOT NULL,
+  executed_at DATETIME NOT NULL,
+  note VARCHAR(255) NOT NULL DEFAULT '',
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (id),
+  INDEX idx_portfolio_transactions_time (portfolio_id, executed_at),
+  CONSTRAINT fk_portfolio_transactions_portfolio FOREIGN KEY (portfolio_id) REFERENCES portfolios(id) ON DELETE CASCADE,
+  CONSTRAINT fk_portfolio_transactions_coin FOREIGN KEY (coin_id) REFERENCES coins(id) ON DELETE CASCADE
+);
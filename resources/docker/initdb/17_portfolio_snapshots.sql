@@ -0,0 +1,10 @@
+-- Valor diario ya calculado de cada portfolio (cache del historial).
+CREATE TABLE IF NOT EXISTS portfolio_snapshots (
+  portfolio_id BIGINT NOT NULL,
+  day DATE NOT NULL,
+  currency VARCHAR(10) NOT NULL,
+  value DECIMAL(38,18) NOT NULL,
+  computed_at DATETIME NOT NULL,
+  PRIMARY KEY (portfolio_id, day, currency),
+  CONSTRAINT fk_portfolio_snapshots_portfolio FOREIGN KEY (portfolio_id) REFERENCES portfolios(id) ON DELETE CASCADE
+);
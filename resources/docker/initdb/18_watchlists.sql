@@ -0,0 +1,45 @@
+-- Watchlists con nombre; la lista por defecto reemplaza a user_favorites.
+CREATE TABLE IF NOT EXISTS watchlists (
+  id BIGINT NOT NULL AUTO_INCREMENT,
+  user_id BIGINT NOT NULL,
+  name VARCHAR(100) NOT NULL,
+  -- user_id en la lista por defecto, NULL en las demás: una sola por usuario
+  default_user_id BIGINT NULL,
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (id),
+  UNIQUE KEY uq_watchlists_default (default_user_id),
+  INDEX idx_watchlists_user (user_id),
+  CONSTRAINT fk_watchlists_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+  CONSTRAINT fk_watchlists_default_user FOREIGN KEY (default_user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS watchlist_entries (
+  watchlist_id BIGINT NOT NULL,
+  coin_id BIGINT NOT NULL,
+  sort_order INT NOT NULL,
+  note VARCHAR(500) NOT NULL DEFAULT '',
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (watchlist_id, coin_id),
+  INDEX idx_watchlist_entries_coin (coin_id),
+  CONSTRAINT fk_watchlist_entries_watchlist FOREIGN KEY (watchlist_id) REFERENCES watchlists(id) ON DELETE CASCADE,
+  CONSTRAINT fk_watchlist_entries_coin FOREIGN KEY (coin_id) REFERENCES coins(id) ON DELETE CASCADE
+);
+
+-- las favoritas pasan a la lista por defecto, ordenadas por símbolo
+INSERT INTO watchlists (user_id, name, default_user_id, created_at, updated_at)
+SELECT user_id, 'Favorites', user_id, MIN(created_at), MIN(created_at)
+FROM user_favorites
+GROUP BY user_id;
+
+INSERT INTO watchlist_entries (watchlist_id, coin_id, sort_order, note, created_at, updated_at)
+SELECT w.id, uf.coin_id,
+  (SELECT COUNT(*) FROM user_favorites uf2 JOIN coins c2 ON c2.id = uf2.coin_id
+   WHERE uf2.user_id = uf.user_id AND c2.symbol < c.symbol),
+  '', uf.created_at, uf.created_at
+FROM user_favorites uf
+JOIN coins c ON c.id = uf.coin_id
+JOIN watchlists w ON w.default_user_id = uf.user_id;
+
+DROP TABLE user_favorites;
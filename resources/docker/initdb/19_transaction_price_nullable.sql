@@ -0,0 +1,7 @@
+-- Un transfer_in cargado sin precio guarda NULL (distinto de un costo 0).
+ALTER TABLE portfolio_transactions
+  MODIFY price DECIMAL(38,18) NULL;
+
+-- los transfer_in con 0 se cargaron sin precio (antes no había otra forma de
+-- guardarlo): quedan en NULL, como los trataba el reporte impositivo
+UPDATE portfolio_transactions SET price = NULL WHERE type = 'transfer_in' AND price = 0;
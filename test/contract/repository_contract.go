@@ -0,0 +1,2994 @@
+// Package contract contiene la suite de contrato que toda implementación de los
+// puertos de persistencia de domain debe pasar (memory, sqlite, mysql, ...).
+package contract
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/moondolphin/crypto-api/domain"
+)
+
+// Repositories agrupa los puertos de un mismo adapter, compartiendo storage
+// (favoritos y quotes referencian coins/users).
+type Repositories = domain.Repositories
+
+// Factory devuelve repos sobre un storage aislado: sin quotes, users, favoritos
+// ni refresh_control/refresh_tokens/revocaciones/api_keys/password_reset_tokens/email_verification_tokens/login_attempts/2FA/OIDC/alertas/webhooks/portfolios/movimientos/snapshots/watchlists previos. Puede traer coins sembradas (la suite usa símbolos "ZZ*").
+type Factory func(t *testing.T) Repositories
+
+// RunRepositoryContract corre la suite completa contra el adapter que construye newRepos.
+func RunRepositoryContract(t *testing.T, newRepos Factory) {
+	t.Run("CoinRepository", func(t *testing.T) { runCoinContract(t, newRepos) })
+	t.Run("QuoteRepository", func(t *testing.T) { runQuoteContract(t, newRepos) })
+	t.Run("UserRepository", func(t *testing.T) { runUserContract(t, newRepos) })
+	t.Run("FavoritesRepository", func(t *testing.T) { runFavoritesContract(t, newRepos) })
+	t.Run("RefreshControlRepository", func(t *testing.T) { runRefreshControlContract(t, newRepos) })
+	t.Run("RefreshTokenRepository", func(t *testing.T) { runRefreshTokenContract(t, newRepos) })
+	t.Run("TokenRevocationStore", func(t *testing.T) { runTokenRevocationContract(t, newRepos) })
+	t.Run("APIKeyRepository", func(t *testing.T) { runAPIKeyContract(t, newRepos) })
+	t.Run("PasswordResetRepository", func(t *testing.T) { runPasswordResetContract(t, newRepos) })
+	t.Run("EmailVerificationRepository", func(t *testing.T) { runEmailVerificationContract(t, newRepos) })
+	t.Run("LoginAttemptStore", func(t *testing.T) { runLoginAttemptContract(t, newRepos) })
+	t.Run("TOTPRepository", func(t *testing.T) { runTOTPContract(t, newRepos) })
+	t.Run("RecoveryCodeRepository", func(t *testing.T) { runRecoveryCodeContract(t, newRepos) })
+	t.Run("LoginChallengeRepository", func(t *testing.T) { runLoginChallengeContract(t, newRepos) })
+	t.Run("UserIdentityRepository", func(t *testing.T) { runUserIdentityContract(t, newRepos) })
+	t.Run("OIDCStateRepository", func(t *testing.T) { runOIDCStateContract(t, newRepos) })
+	t.Run("AlertRuleRepository", func(t *testing.T) { runAlertRuleContract(t, newRepos) })
+	t.Run("AlertEventRepository", func(t *testing.T) { runAlertEventContract(t, newRepos) })
+	t.Run("WebhookSubscriptionRepository", func(t *testing.T) { runWebhookSubscriptionContract(t, newRepos) })
+	t.Run("WebhookDeliveryRepository", func(t *testing.T) { runWebhookDeliveryContract(t, newRepos) })
+	t.Run("PortfolioRepository", func(t *testing.T) { runPortfolioContract(t, newRepos) })
+	t.Run("HoldingRepository", func(t *testing.T) { runHoldingContract(t, newRepos) })
+	t.Run("TransactionRepository", func(t *testing.T) { runTransactionContract(t, newRepos) })
+	t.Run("PortfolioSnapshotRepository", func(t *testing.T) { runPortfolioSnapshotContract(t, newRepos) })
+	t.Run("WatchlistRepository", func(t *testing.T) { runWatchlistContract(t, newRepos) })
+	t.Run("WatchlistEntryRepository", func(t *testing.T) { runWatchlistEntryContract(t, newRepos) })
+}
+
+func mustUpsertCoin(t *testing.T, r domain.CoinRepository, c domain.Coin) domain.Coin {
+	t.Helper()
+	out, err := r.Upsert(context.Background(), c)
+	require.NoError(t, err)
+	require.NotNil(t, out)
+	return *out
+}
+
+// requirePrice compara numéricamente (MySQL devuelve DECIMAL con ceros a la derecha).
+// seedUser inserta un usuario cualquiera del que cuelgan las filas de cada suite.
+func seedUser(t *testing.T, repos Repositories, now time.Time) domain.User {
+	t.Helper()
+	u, err := repos.Users.Create(context.Background(), domain.User{
+		Email:        fmt.Sprintf("zz-user-%d@example.com", time.Now().UnixNano()),
+		Name:         "User",
+		PasswordHash: "h",
+		CreatedAt:    now,
+	})
+	require.NoError(t, err)
+	return u
+}
+
+func requirePrice(t *testing.T, want, got string) {
+	t.Helper()
+	w, err := strconv.ParseFloat(want, 64)
+	require.NoError(t, err)
+	g, err := strconv.ParseFloat(got, 64)
+	require.NoError(t, err)
+	require.Equal(t, w, g)
+}
+
+func runCoinContract(t *testing.T, newRepos Factory) {
+	ctx := context.Background()
+
+	t.Run("GetEnabledBySymbol_ReturnsNilNil_WhenMissing", func(t *testing.T) {
+		r := newRepos(t).Coins
+
+		c, err := r.GetEnabledBySymbol(ctx, "ZZNOPE")
+		require.NoError(t, err)
+		require.Nil(t, c)
+
+		c, err = r.GetBySymbol(ctx, "ZZNOPE")
+		require.NoError(t, err)
+		require.Nil(t, c)
+	})
+
+	t.Run("Upsert_CreatesCoin", func(t *testing.T) {
+		r := newRepos(t).Coins
+
+		c := mustUpsertCoin(t, r, domain.Coin{Symbol: "ZZA", Enabled: true, CoinGeckoID: "zz-a", BinanceSymbol: "ZZAUSDT"})
+		require.Positive(t, c.ID)
+		require.Equal(t, "ZZA", c.Symbol)
+		require.True(t, c.Enabled)
+		require.Equal(t, "zz-a", c.CoinGeckoID)
+		require.Equal(t, "ZZAUSDT", c.BinanceSymbol)
+
+		got, err := r.GetEnabledBySymbol(ctx, "ZZA")
+		require.NoError(t, err)
+		require.Equal(t, &c, got)
+	})
+
+	t.Run("Upsert_MergesWithoutOverwritingWithEmptyIDs", func(t *testing.T) {
+		r := newRepos(t).Coins
+
+		first := mustUpsertCoin(t, r, domain.Coin{Symbol: "ZZB", Enabled: true, CoinGeckoID: "zz-b", BinanceSymbol: "ZZBUSDT"})
+		second := mustUpsertCoin(t, r, domain.Coin{Symbol: "ZZB", Enabled: false})
+
+		require.Equal(t, first.ID, second.ID)
+		require.False(t, second.Enabled)
+		require.Equal(t, "zz-b", second.CoinGeckoID)
+		require.Equal(t, "ZZBUSDT", second.BinanceSymbol)
+
+		third := mustUpsertCoin(t, r, domain.Coin{Symbol: "ZZB", Enabled: true, BinanceSymbol: "ZZBUSDC"})
+		require.True(t, third.Enabled)
+		require.Equal(t, "zz-b", third.CoinGeckoID)
+		require.Equal(t, "ZZBUSDC", third.BinanceSymbol)
+	})
+
+	t.Run("DisabledCoin_OnlyVisibleThroughGetBySymbol", func(t *testing.T) {
+		r := newRepos(t).Coins
+
+		mustUpsertCoin(t, r, domain.Coin{Symbol: "ZZC", Enabled: false, CoinGeckoID: "zz-c"})
+
+		c, err := r.GetEnabledBySymbol(ctx, "ZZC")
+		require.NoError(t, err)
+		require.Nil(t, c)
+
+		c, err = r.GetBySymbol(ctx, "ZZC")
+		require.NoError(t, err)
+		require.NotNil(t, c)
+		require.False(t, c.Enabled)
+	})
+
+	t.Run("ListEnabled_SortedBySymbol_ExcludesDisabled", func(t *testing.T) {
+		r := newRepos(t).Coins
+
+		mustUpsertCoin(t, r, domain.Coin{Symbol: "ZZF", Enabled: true, CoinGeckoID: "zz-f"})
+		mustUpsertCoin(t, r, domain.Coin{Symbol: "ZZD", Enabled: true, CoinGeckoID: "zz-d"})
+		mustUpsertCoin(t, r, domain.Coin{Symbol: "ZZE", Enabled: false, CoinGeckoID: "zz-e"})
+
+		list, err := r.ListEnabled(ctx)
+		require.NoError(t, err)
+
+		var zz []string
+		for i, c := range list {
+			require.True(t, c.Enabled)
+			if i > 0 {
+				require.LessOrEqual(t, list[i-1].Symbol, c.Symbol)
+			}
+			if len(c.Symbol) >= 2 && c.Symbol[:2] == "ZZ" {
+				zz = append(zz, c.Symbol)
+			}
+		}
+		require.Equal(t, []string{"ZZD", "ZZF"}, zz)
+	})
+}
+
+func runQuoteContract(t *testing.T, newRepos Factory) {
+	ctx := context.Background()
+	base := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	// seed crea una coin y n quotes separadas por 1h, precio 100, 110, 120...
+	seed := func(t *testing.T, repos Repositories, symbol, provider, currency string, n int) {
+		t.Helper()
+		c := mustUpsertCoin(t, repos.Coins, domain.Coin{Symbol: symbol, Enabled: true, CoinGeckoID: "x-" + symbol})
+		for i := 0; i < n; i++ {
+			inserted, err := repos.Quotes.Insert(ctx, domain.Quote{
+				CoinID:   c.ID,
+				Symbol:   symbol,
+				Provider: provider,
+				Currency: currency,
+				Price:    strconv.Itoa(100 + i*10),
+				QuotedAt: base.Add(time.Duration(i) * time.Hour),
+			})
+			require.NoError(t, err)
+			require.True(t, inserted)
+		}
+	}
+
+	t.Run("GetLatest_ReturnsNilNil_WhenNoQuotes", func(t *testing.T) {
+		repos := newRepos(t)
+
+		q, err := repos.Quotes.GetLatest(ctx, "ZZQ0", "", "")
+		require.NoError(t, err)
+		require.Nil(t, q)
+	})
+
+	t.Run("GetLatest_ReturnsMostRecent_FilteringProviderAndCurrency", func(t *testing.T) {
+		repos := newRepos(t)
+		seed(t, repos, "ZZQ1", "binance", "USDT", 3)
+
+		c, err := repos.Coins.GetBySymbol(ctx, "ZZQ1")
+		require.NoError(t, err)
+		_, err = repos.Quotes.Insert(ctx, domain.Quote{
+			CoinID: c.ID, Symbol: "ZZQ1", Provider: "coingecko", Currency: "USD",
+			Price: "999.5", QuotedAt: base.Add(-time.Hour),
+		})
+		require.NoError(t, err)
+
+		q, err := repos.Quotes.GetLatest(ctx, "ZZQ1", "", "")
+		require.NoError(t, err)
+		require.NotNil(t, q)
+		requirePrice(t, "120", q.Price)
+		require.Equal(t, "binance", q.Provider)
+
+		ts, err := time.Parse(time.RFC3339Nano, q.Timestamp)
+		require.NoError(t, err)
+		require.True(t, base.Add(2*time.Hour).Equal(ts), "timestamp %s", q.Timestamp)
+
+		q, err = repos.Quotes.GetLatest(ctx, "ZZQ1", "coingecko", "USD")
+		require.NoError(t, err)
+		require.NotNil(t, q)
+		requirePrice(t, "999.5", q.Price)
+
+		q, err = repos.Quotes.GetLatest(ctx, "ZZQ1", "binance", "USD")
+		require.NoError(t, err)
+		require.Nil(t, q)
+	})
+
+	t.Run("Insert_IsIdempotentOnCoinProviderCurrencyAndTime", func(t *testing.T) {
+		repos := newRepos(t)
+		c := mustUpsertCoin(t, repos.Coins, domain.Coin{Symbol: "ZZQD", Enabled: true, CoinGeckoID: "zz-qd"})
+
+		q := domain.Quote{CoinID: c.ID, Symbol: "ZZQD", Provider: "binance", Currency: "USDT", Price: "100", QuotedAt: base}
+
+		inserted, err := repos.Quotes.Insert(ctx, q)
+		require.NoError(t, err)
+		require.True(t, inserted)
+
+		// mismo timestamp, aunque cambie el precio -> duplicado
+		q.Price = "101"
+		inserted, err = repos.Quotes.Insert(ctx, q)
+		require.NoError(t, err)
+		require.False(t, inserted)
+
+		// otra moneda -> cotización distinta
+		q.Currency = "USD"
+		inserted, err = repos.Quotes.Insert(ctx, q)
+		require.NoError(t, err)
+		require.True(t, inserted)
+
+		items, total, err := repos.Quotes.ListFilter(ctx, domain.QuoteFilter{Symbol: "ZZQD", Currency: "USDT"})
+		require.NoError(t, err)
+		require.Equal(t, 1, total)
+		requirePrice(t, "100", items[0].Price)
+	})
+
+	t.Run("ListFilter_PagesNewestFirst", func(t *testing.T) {
+		repos := newRepos(t)
+		seed(t, repos, "ZZQ2", "binance", "USDT", 5)
+
+		items, total, err := repos.Quotes.ListFilter(ctx, domain.QuoteFilter{Symbol: "ZZQ2", Page: 1, PageSize: 2})
+		require.NoError(t, err)
+		require.Equal(t, 5, total)
+		require.Len(t, items, 2)
+		requirePrice(t, "140", items[0].Price)
+		requirePrice(t, "130", items[1].Price)
+		require.Positive(t, items[0].ID)
+		require.Positive(t, items[0].CoinID)
+		require.True(t, base.Add(4*time.Hour).Equal(items[0].QuotedAt))
+
+		items, total, err = repos.Quotes.ListFilter(ctx, domain.QuoteFilter{Symbol: "ZZQ2", Page: 3, PageSize: 2})
+		require.NoError(t, err)
+		require.Equal(t, 5, total)
+		require.Len(t, items, 1)
+		requirePrice(t, "100", items[0].Price)
+
+		items, total, err = repos.Quotes.ListFilter(ctx, domain.QuoteFilter{Symbol: "ZZQ2", Page: 4, PageSize: 2})
+		require.NoError(t, err)
+		require.Equal(t, 5, total)
+		require.Empty(t, items)
+	})
+
+	t.Run("ListFilter_DefaultsAndCapsPageSize", func(t *testing.T) {
+		repos := newRepos(t)
+		seed(t, repos, "ZZQ3", "binance", "USDT", 3)
+
+		items, total, err := repos.Quotes.ListFilter(ctx, domain.QuoteFilter{Symbol: "ZZQ3", PageSize: 1000})
+		require.NoError(t, err)
+		require.Equal(t, 3, total)
+		require.Len(t, items, 3)
+	})
+
+	t.Run("ListFilter_AppliesPriceAndTimeRanges", func(t *testing.T) {
+		repos := newRepos(t)
+		seed(t, repos, "ZZQ4", "binance", "USDT", 5)
+
+		minP, maxP := 105.0, 130.0
+		items, total, err := repos.Quotes.ListFilter(ctx, domain.QuoteFilter{Symbol: "ZZQ4", MinPrice: &minP, MaxPrice: &maxP})
+		require.NoError(t, err)
+		require.Equal(t, 3, total)
+		require.Len(t, items, 3)
+
+		from := base.Add(time.Hour)
+		to := base.Add(3 * time.Hour)
+		items, total, err = repos.Quotes.ListFilter(ctx, domain.QuoteFilter{Symbol: "ZZQ4", From: &from, To: &to})
+		require.NoError(t, err)
+		require.Equal(t, 3, total)
+		requirePrice(t, "130", items[0].Price)
+		requirePrice(t, "110", items[2].Price)
+	})
+
+	t.Run("ListAvailableFilters_ReturnsFacetsForTheSameSieve", func(t *testing.T) {
+		repos := newRepos(t)
+		seed(t, repos, "ZZQ5", "binance", "USDT", 3)
+		seed(t, repos, "ZZQ6", "coingecko", "USD", 2)
+
+		facets, err := repos.Quotes.ListAvailableFilters(ctx, domain.QuoteFilter{Symbol: "ZZQ5"})
+		require.NoError(t, err)
+		require.Equal(t, []string{"ZZQ5"}, facets.Symbols)
+		require.Equal(t, []string{"binance"}, facets.Providers)
+		require.Equal(t, []string{"USDT"}, facets.Currencies)
+		requirePrice(t, "100", facets.MinPrice)
+		requirePrice(t, "120", facets.MaxPrice)
+		require.NotNil(t, facets.From)
+		require.NotNil(t, facets.To)
+		require.True(t, base.Equal(*facets.From))
+		require.True(t, base.Add(2*time.Hour).Equal(*facets.To))
+
+		facets, err = repos.Quotes.ListAvailableFilters(ctx, domain.QuoteFilter{Symbol: "ZZQ404"})
+		require.NoError(t, err)
+		require.Empty(t, facets.Symbols)
+		require.Empty(t, facets.MinPrice)
+		require.Nil(t, facets.From)
+	})
+
+	t.Run("ListDailyCloses_LastQuotePerSymbolAndUTCDay", func(t *testing.T) {
+		repos := newRepos(t)
+		// 12:00 del 10 a 01:00 del 11: el 10 cierra a 210 (23:00), el 11 a 230 (01:00)
+		seed(t, repos, "ZZQ7", "binance", "USD", 14)
+		seed(t, repos, "ZZQ8", "binance", "USD", 1)
+		seed(t, repos, "ZZQ9", "binance", "EUR", 1)
+
+		c, err := repos.Coins.GetBySymbol(ctx, "ZZQ7")
+		require.NoError(t, err)
+		_, err = repos.Quotes.Insert(ctx, domain.Quote{
+			CoinID: c.ID, Symbol: "ZZQ7", Provider: "aaa", Currency: "USD",
+			Price: "999", QuotedAt: base.Add(13 * time.Hour),
+		})
+		require.NoError(t, err)
+
+		day := base.Truncate(24 * time.Hour)
+		f := domain.DailyCloseFilter{
+			Symbols:  []string{"ZZQ8", "ZZQ7", "ZZQ9"},
+			Currency: "USD",
+			From:     day,
+			To:       day.AddDate(0, 0, 2),
+		}
+		closes, err := repos.Quotes.ListDailyCloses(ctx, f)
+		require.NoError(t, err)
+		require.Len(t, closes, 3)
+
+		require.Equal(t, "ZZQ7", closes[0].Symbol)
+		require.True(t, day.Equal(closes[0].Day), "day %s", closes[0].Day)
+		require.True(t, base.Add(11*time.Hour).Equal(closes[0].QuotedAt))
+		requirePrice(t, "210", closes[0].Price)
+
+		// mismo instante en dos providers: gana el primero por nombre
+		require.True(t, day.AddDate(0, 0, 1).Equal(closes[1].Day))
+		require.Equal(t, "aaa", closes[1].Provider)
+		requirePrice(t, "999", closes[1].Price)
+
+		require.Equal(t, "ZZQ8", closes[2].Symbol)
+		requirePrice(t, "100", closes[2].Price)
+
+		f.Provider = "binance"
+		f.To = base.Add(13 * time.Hour) // excluye 01:00 del 11
+		closes, err = repos.Quotes.ListDailyCloses(ctx, f)
+		require.NoError(t, err)
+		require.Len(t, closes, 3)
+		requirePrice(t, "220", closes[1].Price)
+		require.Equal(t, "binance", closes[1].Provider)
+
+		closes, err = repos.Quotes.ListDailyCloses(ctx, domain.DailyCloseFilter{Currency: "USD", From: day, To: day.AddDate(0, 0, 2)})
+		require.NoError(t, err)
+		require.Empty(t, closes)
+	})
+
+	t.Run("ListTickers_LatestAndReferencePerPair", func(t *testing.T) {
+		repos := newRepos(t)
+		// ZZT1 binance/USDT: 100 (12:00) .. 140 (16:00)
+		seed(t, repos, "ZZT1", "binance", "USDT", 5)
+		seed(t, repos, "ZZT2", "binance", "USDT", 1)
+		seed(t, repos, "ZZT3", "binance", "USDT", 1)
+
+		c, err := repos.Coins.GetBySymbol(ctx, "ZZT1")
+		require.NoError(t, err)
+		for _, q := range []domain.Quote{
+			{CoinID: c.ID, Symbol: "ZZT1", Provider: "coingecko", Currency: "USD", Price: "101.5", QuotedAt: base.Add(4 * time.Hour)},
+			{CoinID: c.ID, Symbol: "ZZT1", Provider: "coingecko", Currency: "EUR", Price: "90", QuotedAt: base.Add(4 * time.Hour)},
+		} {
+			_, err := repos.Quotes.Insert(ctx, q)
+			require.NoError(t, err)
+		}
+
+		f := domain.QuoteTickerFilter{
+			Symbols: []string{"ZZT2", "ZZT1", "ZZT404"},
+			Pairs: []domain.ProviderCurrency{
+				{Provider: "binance", Currency: "USDT"},
+				{Provider: "coingecko", Currency: "USD"},
+			},
+			PrevAt: base.Add(90 * time.Minute),
+		}
+		tickers, err := repos.Quotes.ListTickers(ctx, f)
+		require.NoError(t, err)
+		require.Len(t, tickers, 3)
+
+		require.Equal(t, "ZZT1", tickers[0].Symbol)
+		require.Equal(t, "binance", tickers[0].Provider)
+		require.Equal(t, "USDT", tickers[0].Currency)
+		requirePrice(t, "140", tickers[0].Price)
+		require.True(t, base.Add(4*time.Hour).Equal(tickers[0].QuotedAt), "quoted_at %s", tickers[0].QuotedAt)
+		requirePrice(t, "110", tickers[0].PrevPrice)
+		require.True(t, base.Add(time.Hour).Equal(tickers[0].PrevQuotedAt), "prev %s", tickers[0].PrevQuotedAt)
+
+		// sin cotización hasta PrevAt: no hay referencia
+		require.Equal(t, "coingecko", tickers[1].Provider)
+		requirePrice(t, "101.5", tickers[1].Price)
+		require.Empty(t, tickers[1].PrevPrice)
+		require.True(t, tickers[1].PrevQuotedAt.IsZero())
+
+		require.Equal(t, "ZZT2", tickers[2].Symbol)
+		requirePrice(t, "100", tickers[2].PrevPrice)
+
+		// sin pares: todas las fuentes
+		f.Pairs = nil
+		tickers, err = repos.Quotes.ListTickers(ctx, f)
+		require.NoError(t, err)
+		require.Len(t, tickers, 4)
+		require.Equal(t, "EUR", tickers[1].Currency)
+
+		tickers, err = repos.Quotes.ListTickers(ctx, domain.QuoteTickerFilter{PrevAt: base})
+		require.NoError(t, err)
+		require.Empty(t, tickers)
+	})
+}
+
+func runUserContract(t *testing.T, newRepos Factory) {
+	ctx := context.Background()
+	createdAt := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	t.Run("FindByID_ReturnsNilNil_WhenMissing", func(t *testing.T) {
+		r := newRepos(t).Users
+
+		u, err := r.FindByID(ctx, 987654321)
+		require.NoError(t, err)
+		require.Nil(t, u)
+	})
+
+	t.Run("CreateAndFind", func(t *testing.T) {
+		r := newRepos(t).Users
+		email := fmt.Sprintf("zz-%d@example.com", time.Now().UnixNano())
+
+		exists, err := r.ExistsByEmail(ctx, email)
+		require.NoError(t, err)
+		require.False(t, exists)
+
+		u, err := r.FindByEmail(ctx, email)
+		require.NoError(t, err)
+		require.Nil(t, u)
+
+		created, err := r.Create(ctx, domain.User{Email: email, Name: "Zed", PasswordHash: "hash", CreatedAt: createdAt})
+		require.NoError(t, err)
+		require.Positive(t, created.ID)
+
+		exists, err = r.ExistsByEmail(ctx, email)
+		require.NoError(t, err)
+		require.True(t, exists)
+
+		u, err = r.FindByEmail(ctx, email)
+		require.NoError(t, err)
+		require.NotNil(t, u)
+		require.Equal(t, created.ID, u.ID)
+		require.Equal(t, "Zed", u.Name)
+		require.Equal(t, "hash", u.PasswordHash)
+		require.True(t, createdAt.Equal(u.CreatedAt))
+
+		byID, err := r.FindByID(ctx, created.ID)
+		require.NoError(t, err)
+		require.NotNil(t, byID)
+		require.Equal(t, email, byID.Email)
+		require.Equal(t, domain.RoleUser, byID.Role)
+	})
+
+	t.Run("Create_DefaultsRoleAndUpdateRole", func(t *testing.T) {
+		r := newRepos(t).Users
+		email := fmt.Sprintf("zz-role-%d@example.com", time.Now().UnixNano())
+
+		created, err := r.Create(ctx, domain.User{Email: email, Name: "R", PasswordHash: "h", CreatedAt: createdAt})
+		require.NoError(t, err)
+		require.Equal(t, domain.RoleUser, created.Role)
+
+		u, err := r.FindByEmail(ctx, email)
+		require.NoError(t, err)
+		require.Equal(t, domain.RoleUser, u.Role)
+
+		require.NoError(t, r.UpdateRole(ctx, created.ID, domain.RoleAdmin))
+
+		u, err = r.FindByEmail(ctx, email)
+		require.NoError(t, err)
+		require.Equal(t, domain.RoleAdmin, u.Role)
+	})
+
+	t.Run("UpdatePassword", func(t *testing.T) {
+		r := newRepos(t).Users
+		email := fmt.Sprintf("zz-pwd-%d@example.com", time.Now().UnixNano())
+
+		created, err := r.Create(ctx, domain.User{Email: email, Name: "P", PasswordHash: "old", CreatedAt: createdAt})
+		require.NoError(t, err)
+
+		require.NoError(t, r.UpdatePassword(ctx, created.ID, "new"))
+
+		u, err := r.FindByID(ctx, created.ID)
+		require.NoError(t, err)
+		require.Equal(t, "new", u.PasswordHash)
+	})
+
+	t.Run("EmailVerifiedAt_CreateAndMarkVerified", func(t *testing.T) {
+		r := newRepos(t).Users
+		verifiedAt := createdAt.Add(time.Hour)
+
+		pending, err := r.Create(ctx, domain.User{
+			Email: fmt.Sprintf("zz-ev-%d@example.com", time.Now().UnixNano()), Name: "E", PasswordHash: "h", CreatedAt: createdAt,
+		})
+		require.NoError(t, err)
+
+		verified, err := r.Create(ctx, domain.User{
+			Email: fmt.Sprintf("zz-ev2-%d@example.com", time.Now().UnixNano()), Name: "E", PasswordHash: "h", CreatedAt: createdAt,
+			EmailVerifiedAt: &createdAt,
+		})
+		require.NoError(t, err)
+
+		u, err := r.FindByID(ctx, pending.ID)
+		require.NoError(t, err)
+		require.False(t, u.IsEmailVerified())
+
+		u, err = r.FindByEmail(ctx, verified.Email)
+		require.NoError(t, err)
+		require.True(t, u.IsEmailVerified())
+		require.True(t, createdAt.Equal(*u.EmailVerifiedAt))
+
+		require.NoError(t, r.MarkEmailVerified(ctx, pending.ID, verifiedAt))
+		// segunda vez no pisa la fecha
+		require.NoError(t, r.MarkEmailVerified(ctx, pending.ID, verifiedAt.Add(time.Hour)))
+
+		u, err = r.FindByEmail(ctx, pending.Email)
+		require.NoError(t, err)
+		require.True(t, u.IsEmailVerified())
+		require.True(t, verifiedAt.Equal(*u.EmailVerifiedAt))
+	})
+
+	t.Run("Create_FailsOnDuplicateEmail", func(t *testing.T) {
+		r := newRepos(t).Users
+		email := fmt.Sprintf("zz-dup-%d@example.com", time.Now().UnixNano())
+
+		_, err := r.Create(ctx, domain.User{Email: email, Name: "A", PasswordHash: "h", CreatedAt: createdAt})
+		require.NoError(t, err)
+		_, err = r.Create(ctx, domain.User{Email: email, Name: "B", PasswordHash: "h", CreatedAt: createdAt})
+		require.Error(t, err)
+	})
+
+	t.Run("UpdateProfile_ChangesNameEmailAndVerification", func(t *testing.T) {
+		r := newRepos(t).Users
+		email := fmt.Sprintf("zz-prof-%d@example.com", time.Now().UnixNano())
+
+		created, err := r.Create(ctx, domain.User{Email: email, Name: "Old", PasswordHash: "h", CreatedAt: createdAt, EmailVerifiedAt: &createdAt})
+		require.NoError(t, err)
+
+		created.Name = "New"
+		created.Email = "new-" + email
+		created.EmailVerifiedAt = nil
+		require.NoError(t, r.UpdateProfile(ctx, created))
+
+		u, err := r.FindByEmail(ctx, email)
+		require.NoError(t, err)
+		require.Nil(t, u)
+
+		u, err = r.FindByID(ctx, created.ID)
+		require.NoError(t, err)
+		require.Equal(t, "New", u.Name)
+		require.Equal(t, "new-"+email, u.Email)
+		require.Equal(t, "h", u.PasswordHash)
+		require.False(t, u.IsEmailVerified())
+	})
+
+	t.Run("UpdateProfile_FailsOnDuplicateEmail", func(t *testing.T) {
+		r := newRepos(t).Users
+		suffix := time.Now().UnixNano()
+
+		a, err := r.Create(ctx, domain.User{Email: fmt.Sprintf("zz-a-%d@example.com", suffix), Name: "A", PasswordHash: "h", CreatedAt: createdAt})
+		require.NoError(t, err)
+		b, err := r.Create(ctx, domain.User{Email: fmt.Sprintf("zz-b-%d@example.com", suffix), Name: "B", PasswordHash: "h", CreatedAt: createdAt})
+		require.NoError(t, err)
+
+		b.Email = a.Email
+		require.Error(t, r.UpdateProfile(ctx, b))
+	})
+
+	t.Run("Delete_RemovesUser", func(t *testing.T) {
+		r := newRepos(t).Users
+		email := fmt.Sprintf("zz-del-%d@example.com", time.Now().UnixNano())
+
+		created, err := r.Create(ctx, domain.User{Email: email, Name: "D", PasswordHash: "h", CreatedAt: createdAt})
+		require.NoError(t, err)
+		require.NoError(t, r.Delete(ctx, created.ID))
+
+		u, err := r.FindByID(ctx, created.ID)
+		require.NoError(t, err)
+		require.Nil(t, u)
+
+		exists, err := r.ExistsByEmail(ctx, email)
+		require.NoError(t, err)
+		require.False(t, exists)
+
+		// borrar un id inexistente no es error
+		require.NoError(t, r.Delete(ctx, created.ID))
+	})
+
+	t.Run("Delete_RemovesDependentRows", func(t *testing.T) {
+		repos := newRepos(t)
+		now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+		u := seedUser(t, repos, now)
+		other := seedUser(t, repos, now)
+		coin := mustUpsertCoin(t, repos.Coins, domain.Coin{Symbol: "ZZDEL", Enabled: true, CoinGeckoID: "zz-del"})
+
+		for _, owner := range []domain.User{u, other} {
+			suffix := strconv.FormatInt(owner.ID, 10)
+			_, err := repos.RefreshTokens.Create(ctx, domain.RefreshToken{UserID: owner.ID, FamilyID: "fam-" + suffix, TokenHash: "rt-" + suffix, ExpiresAt: now.Add(time.Hour), CreatedAt: now})
+			require.NoError(t, err)
+		}
+		_, err := repos.APIKeys.Create(ctx, domain.APIKey{UserID: u.ID, Name: "ci", Prefix: "cak_del", KeyHash: "ak-del", CreatedAt: now})
+		require.NoError(t, err)
+		_, err = repos.PasswordResets.Create(ctx, domain.PasswordResetToken{UserID: u.ID, TokenHash: "pr-del", ExpiresAt: now.Add(time.Hour), CreatedAt: now})
+		require.NoError(t, err)
+		_, err = repos.Verifications.Create(ctx, domain.EmailVerificationToken{UserID: u.ID, TokenHash: "ev-del", ExpiresAt: now.Add(time.Hour), CreatedAt: now})
+		require.NoError(t, err)
+		_, err = repos.Challenges.Create(ctx, domain.LoginChallenge{UserID: u.ID, TokenHash: "ch-del", ExpiresAt: now.Add(time.Hour), CreatedAt: now})
+		require.NoError(t, err)
+		require.NoError(t, repos.TOTP.Save(ctx, domain.TOTPEnrollment{UserID: u.ID, Secret: "SECRETA", CreatedAt: now}))
+		require.NoError(t, repos.RecoveryCodes.Replace(ctx, u.ID, []string{"rc-del"}, now))
+		_, err = repos.Identities.Create(ctx, domain.UserIdentity{UserID: u.ID, Issuer: "https://idp.example.com", Subject: "sub-del", Email: u.Email, CreatedAt: now})
+		require.NoError(t, err)
+		rule, err := repos.AlertRules.Create(ctx, newAlertRule(u.ID, coin, domain.AlertAbove, 100, now))
+		require.NoError(t, err)
+		_, err = repos.AlertEvents.Create(ctx, domain.AlertEvent{RuleID: rule.ID, UserID: u.ID, Symbol: coin.Symbol, Provider: "coingecko", Currency: "USD", Kind: rule.Kind,
+			Threshold: rule.Threshold, Price: "101", QuotedAt: now, TriggeredAt: now})
+		require.NoError(t, err)
+		sub := newWebhookSubscription(t, repos, u.ID, now, domain.WebhookEventAlertTriggered)
+		_, err = repos.Deliveries.Enqueue(ctx, newWebhookDelivery(sub.ID, "evt_del", now))
+		require.NoError(t, err)
+		p := newPortfolio(t, repos, u.ID, "Del", now)
+		_, err = repos.Transactions.Create(ctx, newTransaction(p.ID, coin, domain.TransactionBuy, "1", "100", now))
+		require.NoError(t, err)
+		_, err = repos.Holdings.Upsert(ctx, domain.Holding{PortfolioID: p.ID, CoinID: coin.ID, Quantity: "1", CreatedAt: now, UpdatedAt: now})
+		require.NoError(t, err)
+		require.NoError(t, repos.Snapshots.Upsert(ctx, domain.PortfolioSnapshot{PortfolioID: p.ID, Day: now, Currency: "USD", Value: "100", ComputedAt: now}))
+		w := newWatchlist(t, repos, u.ID, "Del", now)
+		_, err = repos.WatchlistEntries.Add(ctx, domain.WatchlistEntry{WatchlistID: w.ID, CoinID: coin.ID, CreatedAt: now, UpdatedAt: now})
+		require.NoError(t, err)
+		require.NoError(t, repos.Favorites.AddFavoriteCoinToUser(ctx, u.ID, coin.ID))
+
+		require.NoError(t, repos.Users.Delete(ctx, u.ID))
+
+		rt, err := repos.RefreshTokens.FindByHash(ctx, "rt-"+strconv.FormatInt(u.ID, 10))
+		require.NoError(t, err)
+		require.Nil(t, rt)
+		ak, err := repos.APIKeys.FindByHash(ctx, "ak-del")
+		require.NoError(t, err)
+		require.Nil(t, ak)
+		pr, err := repos.PasswordResets.FindByHash(ctx, "pr-del")
+		require.NoError(t, err)
+		require.Nil(t, pr)
+		ev, err := repos.Verifications.FindByHash(ctx, "ev-del")
+		require.NoError(t, err)
+		require.Nil(t, ev)
+		ch, err := repos.Challenges.FindByHash(ctx, "ch-del")
+		require.NoError(t, err)
+		require.Nil(t, ch)
+		enrollment, err := repos.TOTP.Get(ctx, u.ID)
+		require.NoError(t, err)
+		require.Nil(t, enrollment)
+		codes, err := repos.RecoveryCodes.CountUnused(ctx, u.ID)
+		require.NoError(t, err)
+		require.Zero(t, codes)
+		identities, err := repos.Identities.ListByUser(ctx, u.ID)
+		require.NoError(t, err)
+		require.Empty(t, identities)
+		rules, err := repos.AlertRules.ListByUser(ctx, u.ID)
+		require.NoError(t, err)
+		require.Empty(t, rules)
+		events, err := repos.AlertEvents.ListByUser(ctx, u.ID, 0, 10)
+		require.NoError(t, err)
+		require.Empty(t, events)
+		subs, err := repos.Webhooks.ListByUser(ctx, u.ID)
+		require.NoError(t, err)
+		require.Empty(t, subs)
+		deliveries, err := repos.Deliveries.ListBySubscription(ctx, sub.ID, "", 10)
+		require.NoError(t, err)
+		require.Empty(t, deliveries)
+		portfolios, err := repos.Portfolios.ListByUser(ctx, u.ID)
+		require.NoError(t, err)
+		require.Empty(t, portfolios)
+		txs, err := repos.Transactions.ListByPortfolio(ctx, p.ID, domain.TransactionFilter{})
+		require.NoError(t, err)
+		require.Empty(t, txs)
+		holdings, err := repos.Holdings.ListByPortfolio(ctx, p.ID)
+		require.NoError(t, err)
+		require.Empty(t, holdings)
+		snapshots, err := repos.Snapshots.ListRange(ctx, p.ID, "USD", now.AddDate(0, 0, -1), now.AddDate(0, 0, 1))
+		require.NoError(t, err)
+		require.Empty(t, snapshots)
+		watchlists, err := repos.Watchlists.ListByUser(ctx, u.ID)
+		require.NoError(t, err)
+		require.Empty(t, watchlists)
+		entries, err := repos.WatchlistEntries.ListByWatchlist(ctx, w.ID)
+		require.NoError(t, err)
+		require.Empty(t, entries)
+		favorites, err := repos.Favorites.ListFavoriteCoinIDsByUser(ctx, u.ID)
+		require.NoError(t, err)
+		require.Empty(t, favorites)
+
+		// lo del otro usuario queda
+		rt, err = repos.RefreshTokens.FindByHash(ctx, "rt-"+strconv.FormatInt(other.ID, 10))
+		require.NoError(t, err)
+		require.NotNil(t, rt)
+	})
+}
+
+func runFavoritesContract(t *testing.T, newRepos Factory) {
+	ctx := context.Background()
+
+	t.Run("AddListRemove_Idempotent", func(t *testing.T) {
+		repos := newRepos(t)
+
+		u, err := repos.Users.Create(ctx, domain.User{
+			Email:        fmt.Sprintf("zz-fav-%d@example.com", time.Now().UnixNano()),
+			Name:         "Fav",
+			PasswordHash: "h",
+			CreatedAt:    time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC),
+		})
+		require.NoError(t, err)
+
+		b := mustUpsertCoin(t, repos.Coins, domain.Coin{Symbol: "ZZFB", Enabled: true, CoinGeckoID: "zz-fb"})
+		a := mustUpsertCoin(t, repos.Coins, domain.Coin{Symbol: "ZZFA", Enabled: false, CoinGeckoID: "zz-fa"})
+
+		list, err := repos.Favorites.ListFavoriteCoinIDsByUser(ctx, u.ID)
+		require.NoError(t, err)
+		require.Empty(t, list)
+
+		require.NoError(t, repos.Favorites.AddFavoriteCoinToUser(ctx, u.ID, b.ID))
+		require.NoError(t, repos.Favorites.AddFavoriteCoinToUser(ctx, u.ID, b.ID))
+		require.NoError(t, repos.Favorites.AddFavoriteCoinToUser(ctx, u.ID, a.ID))
+
+		list, err = repos.Favorites.ListFavoriteCoinIDsByUser(ctx, u.ID)
+		require.NoError(t, err)
+		require.Equal(t, []domain.Coin{a, b}, list)
+
+		require.NoError(t, repos.Favorites.RemoveFavoriteCoinFromUser(ctx, u.ID, b.ID))
+		require.NoError(t, repos.Favorites.RemoveFavoriteCoinFromUser(ctx, u.ID, b.ID))
+
+		list, err = repos.Favorites.ListFavoriteCoinIDsByUser(ctx, u.ID)
+		require.NoError(t, err)
+		require.Equal(t, []domain.Coin{a}, list)
+	})
+}
+
+func runRefreshControlContract(t *testing.T, newRepos Factory) {
+	ctx := context.Background()
+
+	t.Run("GetSet", func(t *testing.T) {
+		r := newRepos(t).RefreshControl
+
+		_, ok, err := r.GetLastManualRefresh(ctx)
+		require.NoError(t, err)
+		require.False(t, ok)
+
+		first := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+		require.NoError(t, r.SetLastManualRefresh(ctx, first))
+
+		got, ok, err := r.GetLastManualRefresh(ctx)
+		require.NoError(t, err)
+		require.True(t, ok)
+		require.True(t, first.Equal(got))
+
+		second := first.Add(25 * time.Minute)
+		require.NoError(t, r.SetLastManualRefresh(ctx, second))
+
+		got, ok, err = r.GetLastManualRefresh(ctx)
+		require.NoError(t, err)
+		require.True(t, ok)
+		require.True(t, second.Equal(got))
+		require.Equal(t, time.UTC, got.Location())
+	})
+}
+
+func runRefreshTokenContract(t *testing.T, newRepos Factory) {
+	ctx := context.Background()
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	newUser := func(t *testing.T, repos Repositories) domain.User {
+		t.Helper()
+		u, err := repos.Users.Create(ctx, domain.User{
+			Email:        fmt.Sprintf("zz-rt-%d@example.com", time.Now().UnixNano()),
+			Name:         "RT",
+			PasswordHash: "h",
+			CreatedAt:    now,
+		})
+		require.NoError(t, err)
+		return u
+	}
+
+	t.Run("FindByHash_ReturnsNilNil_WhenMissing", func(t *testing.T) {
+		r := newRepos(t).RefreshTokens
+
+		got, err := r.FindByHash(ctx, "zz-missing")
+		require.NoError(t, err)
+		require.Nil(t, got)
+	})
+
+	t.Run("CreateFindRevoke", func(t *testing.T) {
+		repos := newRepos(t)
+		u := newUser(t, repos)
+
+		created, err := repos.RefreshTokens.Create(ctx, domain.RefreshToken{
+			UserID:    u.ID,
+			FamilyID:  "fam-1",
+			TokenHash: "hash-1",
+			ExpiresAt: now.Add(24 * time.Hour),
+			CreatedAt: now,
+		})
+		require.NoError(t, err)
+		require.Positive(t, created.ID)
+
+		got, err := repos.RefreshTokens.FindByHash(ctx, "hash-1")
+		require.NoError(t, err)
+		require.NotNil(t, got)
+		require.Equal(t, created.ID, got.ID)
+		require.Equal(t, u.ID, got.UserID)
+		require.Equal(t, "fam-1", got.FamilyID)
+		require.True(t, now.Add(24*time.Hour).Equal(got.ExpiresAt))
+		require.False(t, got.IsRevoked())
+
+		ok, err := repos.RefreshTokens.Revoke(ctx, created.ID, now.Add(time.Minute))
+		require.NoError(t, err)
+		require.True(t, ok)
+
+		// segunda revocación: ya no estaba activo
+		ok, err = repos.RefreshTokens.Revoke(ctx, created.ID, now.Add(2*time.Minute))
+		require.NoError(t, err)
+		require.False(t, ok)
+
+		got, err = repos.RefreshTokens.FindByHash(ctx, "hash-1")
+		require.NoError(t, err)
+		require.True(t, got.IsRevoked())
+		require.True(t, now.Add(time.Minute).Equal(*got.RevokedAt))
+	})
+
+	t.Run("RevokeFamily_OnlyTouchesThatFamily", func(t *testing.T) {
+		repos := newRepos(t)
+		u := newUser(t, repos)
+
+		for _, tk := range []domain.RefreshToken{
+			{UserID: u.ID, FamilyID: "fam-a", TokenHash: "a-1"},
+			{UserID: u.ID, FamilyID: "fam-a", TokenHash: "a-2"},
+			{UserID: u.ID, FamilyID: "fam-b", TokenHash: "b-1"},
+		} {
+			tk.ExpiresAt = now.Add(time.Hour)
+			tk.CreatedAt = now
+			_, err := repos.RefreshTokens.Create(ctx, tk)
+			require.NoError(t, err)
+		}
+
+		require.NoError(t, repos.RefreshTokens.RevokeFamily(ctx, "fam-a", now))
+
+		for hash, revoked := range map[string]bool{"a-1": true, "a-2": true, "b-1": false} {
+			got, err := repos.RefreshTokens.FindByHash(ctx, hash)
+			require.NoError(t, err)
+			require.Equal(t, revoked, got.IsRevoked(), hash)
+		}
+	})
+
+	t.Run("RevokeByUser_RevokesEveryFamilyOfThatUser", func(t *testing.T) {
+		repos := newRepos(t)
+		u := newUser(t, repos)
+		other := newUser(t, repos)
+
+		for _, tk := range []domain.RefreshToken{
+			{UserID: u.ID, FamilyID: "fam-u1", TokenHash: "u-1"},
+			{UserID: u.ID, FamilyID: "fam-u2", TokenHash: "u-2"},
+			{UserID: other.ID, FamilyID: "fam-o", TokenHash: "o-1"},
+		} {
+			tk.ExpiresAt = now.Add(time.Hour)
+			tk.CreatedAt = now
+			_, err := repos.RefreshTokens.Create(ctx, tk)
+			require.NoError(t, err)
+		}
+
+		require.NoError(t, repos.RefreshTokens.RevokeByUser(ctx, u.ID, now))
+
+		for hash, revoked := range map[string]bool{"u-1": true, "u-2": true, "o-1": false} {
+			got, err := repos.RefreshTokens.FindByHash(ctx, hash)
+			require.NoError(t, err)
+			require.Equal(t, revoked, got.IsRevoked(), hash)
+		}
+	})
+
+	t.Run("Create_FailsOnDuplicateHash", func(t *testing.T) {
+		repos := newRepos(t)
+		u := newUser(t, repos)
+
+		tk := domain.RefreshToken{UserID: u.ID, FamilyID: "fam", TokenHash: "dup", ExpiresAt: now, CreatedAt: now}
+		_, err := repos.RefreshTokens.Create(ctx, tk)
+		require.NoError(t, err)
+		_, err = repos.RefreshTokens.Create(ctx, tk)
+		require.Error(t, err)
+	})
+
+	t.Run("ListActiveByUser_SkipsRevokedExpiredAndOthers", func(t *testing.T) {
+		repos := newRepos(t)
+		u := newUser(t, repos)
+		other := newUser(t, repos)
+
+		for _, tk := range []domain.RefreshToken{
+			{UserID: u.ID, FamilyID: "fa", TokenHash: "la-1", ExpiresAt: now.Add(time.Hour)},
+			{UserID: u.ID, FamilyID: "fa", TokenHash: "la-revoked", ExpiresAt: now.Add(time.Hour)},
+			{UserID: u.ID, FamilyID: "fb", TokenHash: "la-expired", ExpiresAt: now.Add(-time.Minute)},
+			{UserID: u.ID, FamilyID: "fc", TokenHash: "la-2", ExpiresAt: now.Add(2 * time.Hour)},
+			{UserID: other.ID, FamilyID: "fo", TokenHash: "la-other", ExpiresAt: now.Add(time.Hour)},
+		} {
+			tk.CreatedAt = now
+			created, err := repos.RefreshTokens.Create(ctx, tk)
+			require.NoError(t, err)
+			if tk.TokenHash == "la-revoked" {
+				_, err = repos.RefreshTokens.Revoke(ctx, created.ID, now)
+				require.NoError(t, err)
+			}
+		}
+
+		got, err := repos.RefreshTokens.ListActiveByUser(ctx, u.ID, now)
+		require.NoError(t, err)
+		require.Len(t, got, 2)
+		require.Equal(t, "la-1", got[0].TokenHash)
+		require.Equal(t, "la-2", got[1].TokenHash)
+		require.Equal(t, "fc", got[1].FamilyID)
+		require.True(t, now.Add(2*time.Hour).Equal(got[1].ExpiresAt))
+	})
+}
+
+func runTokenRevocationContract(t *testing.T, newRepos Factory) {
+	ctx := context.Background()
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	t.Run("RevokeJTI", func(t *testing.T) {
+		s := newRepos(t).Revocations
+
+		revoked, err := s.IsRevoked(ctx, "jti-1", 1, now)
+		require.NoError(t, err)
+		require.False(t, revoked)
+
+		require.NoError(t, s.RevokeJTI(ctx, "jti-1", now.Add(time.Hour)))
+		require.NoError(t, s.RevokeJTI(ctx, "jti-1", now.Add(time.Hour)))
+
+		revoked, err = s.IsRevoked(ctx, "jti-1", 1, now)
+		require.NoError(t, err)
+		require.True(t, revoked)
+
+		revoked, err = s.IsRevoked(ctx, "jti-2", 1, now)
+		require.NoError(t, err)
+		require.False(t, revoked)
+	})
+
+	t.Run("RevokeUser_CutsTokensIssuedUpToThatSecond", func(t *testing.T) {
+		s := newRepos(t).Revocations
+
+		require.NoError(t, s.RevokeUser(ctx, 7, now.Add(500*time.Millisecond), now.Add(time.Hour)))
+
+		for _, tc := range []struct {
+			uid     int64
+			iat     time.Time
+			revoked bool
+		}{
+			{7, now.Add(-time.Hour), true},
+			{7, now, true},
+			{7, now.Add(time.Second), false},
+			{8, now.Add(-time.Hour), false},
+		} {
+			got, err := s.IsRevoked(ctx, "", tc.uid, tc.iat)
+			require.NoError(t, err)
+			require.Equal(t, tc.revoked, got, "uid=%d iat=%s", tc.uid, tc.iat)
+		}
+
+		// un corte más viejo no retrocede el vigente
+		require.NoError(t, s.RevokeUser(ctx, 7, now.Add(-time.Hour), now.Add(time.Hour)))
+		got, err := s.IsRevoked(ctx, "", 7, now)
+		require.NoError(t, err)
+		require.True(t, got)
+	})
+
+	t.Run("DeleteExpired_PurgesOnlyPastEntries", func(t *testing.T) {
+		s := newRepos(t).Revocations
+
+		require.NoError(t, s.RevokeJTI(ctx, "old", now.Add(-time.Minute)))
+		require.NoError(t, s.RevokeJTI(ctx, "live", now.Add(time.Hour)))
+		require.NoError(t, s.RevokeUser(ctx, 1, now.Add(-2*time.Hour), now.Add(-time.Minute)))
+		require.NoError(t, s.RevokeUser(ctx, 2, now.Add(-2*time.Hour), now.Add(time.Hour)))
+
+		n, err := s.DeleteExpired(ctx, now)
+		require.NoError(t, err)
+		require.Equal(t, int64(2), n)
+
+		for _, tc := range []struct {
+			jti     string
+			uid     int64
+			revoked bool
+		}{
+			{"old", 0, false},
+			{"live", 0, true},
+			{"", 1, false},
+			{"", 2, true},
+		} {
+			got, err := s.IsRevoked(ctx, tc.jti, tc.uid, now.Add(-3*time.Hour))
+			require.NoError(t, err)
+			require.Equal(t, tc.revoked, got, "jti=%q uid=%d", tc.jti, tc.uid)
+		}
+	})
+}
+
+func runAPIKeyContract(t *testing.T, newRepos Factory) {
+	ctx := context.Background()
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	newUser := func(t *testing.T, repos Repositories) domain.User {
+		t.Helper()
+		u, err := repos.Users.Create(ctx, domain.User{
+			Email:        fmt.Sprintf("zz-ak-%d@example.com", time.Now().UnixNano()),
+			Name:         "AK",
+			PasswordHash: "h",
+			CreatedAt:    now,
+		})
+		require.NoError(t, err)
+		return u
+	}
+
+	t.Run("FindByHash_ReturnsNilNil_WhenMissing", func(t *testing.T) {
+		r := newRepos(t).APIKeys
+
+		got, err := r.FindByHash(ctx, "zz-missing")
+		require.NoError(t, err)
+		require.Nil(t, got)
+	})
+
+	t.Run("CreateFindListTouch", func(t *testing.T) {
+		repos := newRepos(t)
+		u := newUser(t, repos)
+		exp := now.Add(30 * 24 * time.Hour)
+
+		first, err := repos.APIKeys.Create(ctx, domain.APIKey{
+			UserID:    u.ID,
+			Name:      "ci",
+			Prefix:    "cak_aaaa",
+			KeyHash:   "ak-1",
+			Scopes:    []string{domain.ScopeFavoritesRead, domain.ScopeQuotesRead},
+			ExpiresAt: &exp,
+			CreatedAt: now,
+		})
+		require.NoError(t, err)
+		require.Positive(t, first.ID)
+
+		second, err := repos.APIKeys.Create(ctx, domain.APIKey{
+			UserID:    u.ID,
+			Name:      "bot",
+			Prefix:    "cak_bbbb",
+			KeyHash:   "ak-2",
+			Scopes:    []string{domain.ScopeQuotesRead},
+			CreatedAt: now,
+		})
+		require.NoError(t, err)
+
+		got, err := repos.APIKeys.FindByHash(ctx, "ak-1")
+		require.NoError(t, err)
+		require.NotNil(t, got)
+		require.Equal(t, first.ID, got.ID)
+		require.Equal(t, u.ID, got.UserID)
+		require.Equal(t, "ci", got.Name)
+		require.Equal(t, "cak_aaaa", got.Prefix)
+		require.Equal(t, []string{domain.ScopeFavoritesRead, domain.ScopeQuotesRead}, got.Scopes)
+		require.NotNil(t, got.ExpiresAt)
+		require.True(t, exp.Equal(*got.ExpiresAt))
+		require.True(t, now.Equal(got.CreatedAt))
+		require.Nil(t, got.LastUsedAt)
+		require.Nil(t, got.RevokedAt)
+
+		require.NoError(t, repos.APIKeys.TouchLastUsed(ctx, second.ID, now.Add(time.Minute)))
+
+		list, err := repos.APIKeys.ListByUser(ctx, u.ID)
+		require.NoError(t, err)
+		require.Len(t, list, 2)
+		require.Equal(t, first.ID, list[0].ID)
+		require.Equal(t, second.ID, list[1].ID)
+		require.Nil(t, list[1].ExpiresAt)
+		require.NotNil(t, list[1].LastUsedAt)
+		require.True(t, now.Add(time.Minute).Equal(*list[1].LastUsedAt))
+	})
+
+	t.Run("Revoke_OnlyOwnerAndOnlyOnce", func(t *testing.T) {
+		repos := newRepos(t)
+		u := newUser(t, repos)
+		other := newUser(t, repos)
+
+		k, err := repos.APIKeys.Create(ctx, domain.APIKey{
+			UserID: u.ID, Name: "k", Prefix: "cak_cccc", KeyHash: "ak-3",
+			Scopes: []string{domain.ScopeQuotesRead}, CreatedAt: now,
+		})
+		require.NoError(t, err)
+
+		ok, err := repos.APIKeys.Revoke(ctx, other.ID, k.ID, now)
+		require.NoError(t, err)
+		require.False(t, ok)
+
+		ok, err = repos.APIKeys.Revoke(ctx, u.ID, k.ID, now)
+		require.NoError(t, err)
+		require.True(t, ok)
+
+		ok, err = repos.APIKeys.Revoke(ctx, u.ID, k.ID, now)
+		require.NoError(t, err)
+		require.False(t, ok)
+
+		list, err := repos.APIKeys.ListByUser(ctx, u.ID)
+		require.NoError(t, err)
+		require.Empty(t, list)
+
+		got, err := repos.APIKeys.FindByHash(ctx, "ak-3")
+		require.NoError(t, err)
+		require.NotNil(t, got.RevokedAt)
+	})
+
+	t.Run("RevokeByUser_RevokesOnlyThatUsersKeys", func(t *testing.T) {
+		repos := newRepos(t)
+		u := newUser(t, repos)
+		other := newUser(t, repos)
+
+		for i, owner := range []int64{u.ID, u.ID, other.ID} {
+			_, err := repos.APIKeys.Create(ctx, domain.APIKey{
+				UserID: owner, Name: "k", Prefix: fmt.Sprintf("cak_d%03d", i), KeyHash: fmt.Sprintf("ak-byuser-%d", i),
+				Scopes: []string{domain.ScopeQuotesRead}, CreatedAt: now,
+			})
+			require.NoError(t, err)
+		}
+
+		require.NoError(t, repos.APIKeys.RevokeByUser(ctx, u.ID, now))
+
+		list, err := repos.APIKeys.ListByUser(ctx, u.ID)
+		require.NoError(t, err)
+		require.Empty(t, list)
+
+		got, err := repos.APIKeys.FindByHash(ctx, "ak-byuser-0")
+		require.NoError(t, err)
+		require.NotNil(t, got.RevokedAt)
+		require.True(t, now.Equal(*got.RevokedAt))
+
+		list, err = repos.APIKeys.ListByUser(ctx, other.ID)
+		require.NoError(t, err)
+		require.Len(t, list, 1)
+	})
+}
+
+func runPasswordResetContract(t *testing.T, newRepos Factory) {
+	ctx := context.Background()
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	newUser := func(t *testing.T, repos Repositories) domain.User {
+		t.Helper()
+		u, err := repos.Users.Create(ctx, domain.User{
+			Email:        fmt.Sprintf("zz-pr-%d@example.com", time.Now().UnixNano()),
+			Name:         "PR",
+			PasswordHash: "h",
+			CreatedAt:    now,
+		})
+		require.NoError(t, err)
+		return u
+	}
+
+	t.Run("FindByHash_ReturnsNilNil_WhenMissing", func(t *testing.T) {
+		r := newRepos(t).PasswordResets
+
+		got, err := r.FindByHash(ctx, "zz-missing")
+		require.NoError(t, err)
+		require.Nil(t, got)
+	})
+
+	t.Run("CreateFindMarkUsed_SingleUse", func(t *testing.T) {
+		repos := newRepos(t)
+		u := newUser(t, repos)
+
+		created, err := repos.PasswordResets.Create(ctx, domain.PasswordResetToken{
+			UserID:    u.ID,
+			TokenHash: "pr-1",
+			ExpiresAt: now.Add(time.Hour),
+			CreatedAt: now,
+		})
+		require.NoError(t, err)
+		require.Positive(t, created.ID)
+
+		got, err := repos.PasswordResets.FindByHash(ctx, "pr-1")
+		require.NoError(t, err)
+		require.NotNil(t, got)
+		require.Equal(t, u.ID, got.UserID)
+		require.True(t, now.Add(time.Hour).Equal(got.ExpiresAt))
+		require.False(t, got.IsUsed())
+
+		ok, err := repos.PasswordResets.MarkUsed(ctx, created.ID, now.Add(time.Minute))
+		require.NoError(t, err)
+		require.True(t, ok)
+
+		ok, err = repos.PasswordResets.MarkUsed(ctx, created.ID, now.Add(2*time.Minute))
+		require.NoError(t, err)
+		require.False(t, ok)
+
+		got, err = repos.PasswordResets.FindByHash(ctx, "pr-1")
+		require.NoError(t, err)
+		require.True(t, got.IsUsed())
+		require.True(t, now.Add(time.Minute).Equal(*got.UsedAt))
+	})
+
+	t.Run("InvalidateByUser_OnlyTouchesThatUser", func(t *testing.T) {
+		repos := newRepos(t)
+		u := newUser(t, repos)
+		other := newUser(t, repos)
+
+		for _, tk := range []domain.PasswordResetToken{
+			{UserID: u.ID, TokenHash: "pu-1"},
+			{UserID: u.ID, TokenHash: "pu-2"},
+			{UserID: other.ID, TokenHash: "po-1"},
+		} {
+			tk.ExpiresAt = now.Add(time.Hour)
+			tk.CreatedAt = now
+			_, err := repos.PasswordResets.Create(ctx, tk)
+			require.NoError(t, err)
+		}
+
+		require.NoError(t, repos.PasswordResets.InvalidateByUser(ctx, u.ID, now))
+
+		for hash, used := range map[string]bool{"pu-1": true, "pu-2": true, "po-1": false} {
+			got, err := repos.PasswordResets.FindByHash(ctx, hash)
+			require.NoError(t, err)
+			require.Equal(t, used, got.IsUsed(), hash)
+		}
+	})
+
+	t.Run("Create_FailsOnDuplicateHash", func(t *testing.T) {
+		repos := newRepos(t)
+		u := newUser(t, repos)
+
+		tk := domain.PasswordResetToken{UserID: u.ID, TokenHash: "pr-dup", ExpiresAt: now, CreatedAt: now}
+		_, err := repos.PasswordResets.Create(ctx, tk)
+		require.NoError(t, err)
+		_, err = repos.PasswordResets.Create(ctx, tk)
+		require.Error(t, err)
+	})
+}
+
+func runEmailVerificationContract(t *testing.T, newRepos Factory) {
+	ctx := context.Background()
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	newUser := func(t *testing.T, repos Repositories) domain.User {
+		t.Helper()
+		u, err := repos.Users.Create(ctx, domain.User{
+			Email:        fmt.Sprintf("zz-ev-%d@example.com", time.Now().UnixNano()),
+			Name:         "EV",
+			PasswordHash: "h",
+			CreatedAt:    now,
+		})
+		require.NoError(t, err)
+		return u
+	}
+
+	t.Run("FindAndLatest_ReturnNilNil_WhenMissing", func(t *testing.T) {
+		repos := newRepos(t)
+		u := newUser(t, repos)
+
+		got, err := repos.Verifications.FindByHash(ctx, "zz-missing")
+		require.NoError(t, err)
+		require.Nil(t, got)
+
+		got, err = repos.Verifications.LatestByUser(ctx, u.ID)
+		require.NoError(t, err)
+		require.Nil(t, got)
+	})
+
+	t.Run("CreateFindMarkUsed_SingleUse", func(t *testing.T) {
+		repos := newRepos(t)
+		u := newUser(t, repos)
+
+		created, err := repos.Verifications.Create(ctx, domain.EmailVerificationToken{
+			UserID:    u.ID,
+			TokenHash: "ev-1",
+			ExpiresAt: now.Add(time.Hour),
+			CreatedAt: now,
+		})
+		require.NoError(t, err)
+		require.Positive(t, created.ID)
+
+		got, err := repos.Verifications.FindByHash(ctx, "ev-1")
+		require.NoError(t, err)
+		require.NotNil(t, got)
+		require.Equal(t, u.ID, got.UserID)
+		require.True(t, now.Add(time.Hour).Equal(got.ExpiresAt))
+		require.False(t, got.IsUsed())
+
+		ok, err := repos.Verifications.MarkUsed(ctx, created.ID, now.Add(time.Minute))
+		require.NoError(t, err)
+		require.True(t, ok)
+
+		ok, err = repos.Verifications.MarkUsed(ctx, created.ID, now.Add(2*time.Minute))
+		require.NoError(t, err)
+		require.False(t, ok)
+
+		got, err = repos.Verifications.FindByHash(ctx, "ev-1")
+		require.NoError(t, err)
+		require.True(t, got.IsUsed())
+	})
+
+	t.Run("LatestByUser_ReturnsNewestIncludingUsed", func(t *testing.T) {
+		repos := newRepos(t)
+		u := newUser(t, repos)
+		other := newUser(t, repos)
+
+		for _, tk := range []domain.EmailVerificationToken{
+			{UserID: u.ID, TokenHash: "el-1", CreatedAt: now},
+			{UserID: u.ID, TokenHash: "el-2", CreatedAt: now.Add(time.Minute)},
+			{UserID: other.ID, TokenHash: "el-o", CreatedAt: now.Add(time.Hour)},
+		} {
+			tk.ExpiresAt = now.Add(24 * time.Hour)
+			_, err := repos.Verifications.Create(ctx, tk)
+			require.NoError(t, err)
+		}
+		require.NoError(t, repos.Verifications.InvalidateByUser(ctx, u.ID, now.Add(2*time.Minute)))
+
+		got, err := repos.Verifications.LatestByUser(ctx, u.ID)
+		require.NoError(t, err)
+		require.NotNil(t, got)
+		require.Equal(t, "el-2", got.TokenHash)
+		require.True(t, now.Add(time.Minute).Equal(got.CreatedAt))
+		require.True(t, got.IsUsed())
+
+		got, err = repos.Verifications.FindByHash(ctx, "el-o")
+		require.NoError(t, err)
+		require.False(t, got.IsUsed())
+	})
+
+	t.Run("Create_FailsOnDuplicateHash", func(t *testing.T) {
+		repos := newRepos(t)
+		u := newUser(t, repos)
+
+		tk := domain.EmailVerificationToken{UserID: u.ID, TokenHash: "ev-dup", ExpiresAt: now, CreatedAt: now}
+		_, err := repos.Verifications.Create(ctx, tk)
+		require.NoError(t, err)
+		_, err = repos.Verifications.Create(ctx, tk)
+		require.Error(t, err)
+	})
+}
+
+func runLoginAttemptContract(t *testing.T, newRepos Factory) {
+	ctx := context.Background()
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+	window := 15 * time.Minute
+
+	t.Run("Get_ReturnsNilNil_WhenMissing", func(t *testing.T) {
+		s := newRepos(t).LoginAttempts
+
+		got, err := s.Get(ctx, "account:zz-missing@example.com")
+		require.NoError(t, err)
+		require.Nil(t, got)
+	})
+
+	t.Run("RecordFailure_CountsPerKey", func(t *testing.T) {
+		s := newRepos(t).LoginAttempts
+
+		for i := 1; i <= 3; i++ {
+			n, err := s.RecordFailure(ctx, "account:zz@example.com", now.Add(time.Duration(i)*time.Second), now.Add(-window))
+			require.NoError(t, err)
+			require.Equal(t, i, n)
+		}
+		n, err := s.RecordFailure(ctx, "ip:10.0.0.1", now, now.Add(-window))
+		require.NoError(t, err)
+		require.Equal(t, 1, n)
+
+		got, err := s.Get(ctx, "account:zz@example.com")
+		require.NoError(t, err)
+		require.NotNil(t, got)
+		require.Equal(t, "account:zz@example.com", got.Key)
+		require.Equal(t, 3, got.Failures)
+		require.True(t, now.Add(3*time.Second).Equal(got.LastFailureAt))
+		require.Nil(t, got.LockedUntil)
+	})
+
+	t.Run("RecordFailure_RestartsAfterWindow", func(t *testing.T) {
+		s := newRepos(t).LoginAttempts
+
+		_, err := s.RecordFailure(ctx, "ip:zz", now, now.Add(-window))
+		require.NoError(t, err)
+		_, err = s.RecordFailure(ctx, "ip:zz", now.Add(time.Minute), now.Add(time.Minute-window))
+		require.NoError(t, err)
+
+		later := now.Add(time.Hour)
+		n, err := s.RecordFailure(ctx, "ip:zz", later, later.Add(-window))
+		require.NoError(t, err)
+		require.Equal(t, 1, n)
+	})
+
+	t.Run("RecordFailure_WindowStartsAfterLockEnds", func(t *testing.T) {
+		s := newRepos(t).LoginAttempts
+
+		_, err := s.RecordFailure(ctx, "ip:zz-w", now, now.Add(-window))
+		require.NoError(t, err)
+		require.NoError(t, s.Lock(ctx, "ip:zz-w", now.Add(time.Hour)))
+
+		// pasó más que la ventana desde el fallo, pero no desde el fin del bloqueo
+		later := now.Add(time.Hour + time.Minute)
+		n, err := s.RecordFailure(ctx, "ip:zz-w", later, later.Add(-window))
+		require.NoError(t, err)
+		require.Equal(t, 2, n)
+	})
+
+	t.Run("LockAndReset", func(t *testing.T) {
+		s := newRepos(t).LoginAttempts
+
+		_, err := s.RecordFailure(ctx, "account:zz-lock@example.com", now, now.Add(-window))
+		require.NoError(t, err)
+		require.NoError(t, s.Lock(ctx, "account:zz-lock@example.com", now.Add(time.Minute)))
+
+		got, err := s.Get(ctx, "account:zz-lock@example.com")
+		require.NoError(t, err)
+		require.NotNil(t, got.LockedUntil)
+		require.True(t, now.Add(time.Minute).Equal(*got.LockedUntil))
+		require.True(t, got.IsLocked(now))
+		require.False(t, got.IsLocked(now.Add(time.Minute)))
+
+		require.NoError(t, s.Reset(ctx, "account:zz-lock@example.com"))
+		got, err = s.Get(ctx, "account:zz-lock@example.com")
+		require.NoError(t, err)
+		require.Nil(t, got)
+
+		// resetear una clave inexistente no es error
+		require.NoError(t, s.Reset(ctx, "account:zz-lock@example.com"))
+	})
+
+	t.Run("DeleteStale_KeepsRecentAndLocked", func(t *testing.T) {
+		s := newRepos(t).LoginAttempts
+
+		_, err := s.RecordFailure(ctx, "ip:zz-old", now, now.Add(-window))
+		require.NoError(t, err)
+		_, err = s.RecordFailure(ctx, "ip:zz-locked", now, now.Add(-window))
+		require.NoError(t, err)
+		require.NoError(t, s.Lock(ctx, "ip:zz-locked", now.Add(3*time.Hour)))
+		_, err = s.RecordFailure(ctx, "ip:zz-recent", now.Add(2*time.Hour), now.Add(2*time.Hour-window))
+		require.NoError(t, err)
+
+		n, err := s.DeleteStale(ctx, now.Add(time.Hour))
+		require.NoError(t, err)
+		require.Equal(t, int64(1), n)
+
+		got, err := s.Get(ctx, "ip:zz-old")
+		require.NoError(t, err)
+		require.Nil(t, got)
+		for _, key := range []string{"ip:zz-locked", "ip:zz-recent"} {
+			got, err = s.Get(ctx, key)
+			require.NoError(t, err)
+			require.NotNil(t, got, key)
+		}
+	})
+}
+
+func runTOTPContract(t *testing.T, newRepos Factory) {
+	ctx := context.Background()
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	t.Run("Get_ReturnsNilNil_WhenMissing", func(t *testing.T) {
+		repos := newRepos(t)
+		u := seedUser(t, repos, now)
+
+		got, err := repos.TOTP.Get(ctx, u.ID)
+		require.NoError(t, err)
+		require.Nil(t, got)
+	})
+
+	t.Run("SaveConfirmAndDelete", func(t *testing.T) {
+		repos := newRepos(t)
+		u := seedUser(t, repos, now)
+
+		require.NoError(t, repos.TOTP.Save(ctx, domain.TOTPEnrollment{UserID: u.ID, Secret: "SECRETA", CreatedAt: now}))
+
+		got, err := repos.TOTP.Get(ctx, u.ID)
+		require.NoError(t, err)
+		require.NotNil(t, got)
+		require.Equal(t, "SECRETA", got.Secret)
+		require.True(t, now.Equal(got.CreatedAt))
+		require.False(t, got.IsConfirmed())
+		require.Zero(t, got.LastUsedStep)
+
+		require.NoError(t, repos.TOTP.Confirm(ctx, u.ID, now.Add(time.Minute)))
+		got, err = repos.TOTP.Get(ctx, u.ID)
+		require.NoError(t, err)
+		require.True(t, got.IsConfirmed())
+		require.True(t, now.Add(time.Minute).Equal(*got.ConfirmedAt))
+
+		require.NoError(t, repos.TOTP.Delete(ctx, u.ID))
+		got, err = repos.TOTP.Get(ctx, u.ID)
+		require.NoError(t, err)
+		require.Nil(t, got)
+	})
+
+	t.Run("Save_ReplacesAndResetsEnrollment", func(t *testing.T) {
+		repos := newRepos(t)
+		u := seedUser(t, repos, now)
+
+		require.NoError(t, repos.TOTP.Save(ctx, domain.TOTPEnrollment{UserID: u.ID, Secret: "VIEJA", CreatedAt: now}))
+		require.NoError(t, repos.TOTP.Confirm(ctx, u.ID, now))
+		_, err := repos.TOTP.MarkStepUsed(ctx, u.ID, 100)
+		require.NoError(t, err)
+
+		require.NoError(t, repos.TOTP.Save(ctx, domain.TOTPEnrollment{UserID: u.ID, Secret: "NUEVA", CreatedAt: now.Add(time.Hour)}))
+
+		got, err := repos.TOTP.Get(ctx, u.ID)
+		require.NoError(t, err)
+		require.Equal(t, "NUEVA", got.Secret)
+		require.False(t, got.IsConfirmed())
+		require.Zero(t, got.LastUsedStep)
+	})
+
+	t.Run("MarkStepUsed_RejectsReplayAndOlderSteps", func(t *testing.T) {
+		repos := newRepos(t)
+		u := seedUser(t, repos, now)
+		require.NoError(t, repos.TOTP.Save(ctx, domain.TOTPEnrollment{UserID: u.ID, Secret: "S", CreatedAt: now}))
+
+		ok, err := repos.TOTP.MarkStepUsed(ctx, u.ID, 100)
+		require.NoError(t, err)
+		require.True(t, ok)
+
+		for _, step := range []int64{100, 99} {
+			ok, err = repos.TOTP.MarkStepUsed(ctx, u.ID, step)
+			require.NoError(t, err)
+			require.False(t, ok, step)
+		}
+
+		ok, err = repos.TOTP.MarkStepUsed(ctx, u.ID, 101)
+		require.NoError(t, err)
+		require.True(t, ok)
+
+		got, err := repos.TOTP.Get(ctx, u.ID)
+		require.NoError(t, err)
+		require.Equal(t, int64(101), got.LastUsedStep)
+	})
+}
+
+func runRecoveryCodeContract(t *testing.T, newRepos Factory) {
+	ctx := context.Background()
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	t.Run("ReplaceUseAndCount", func(t *testing.T) {
+		repos := newRepos(t)
+		u := seedUser(t, repos, now)
+		other := seedUser(t, repos, now)
+
+		require.NoError(t, repos.RecoveryCodes.Replace(ctx, u.ID, []string{"rc-1", "rc-2", "rc-3"}, now))
+
+		n, err := repos.RecoveryCodes.CountUnused(ctx, u.ID)
+		require.NoError(t, err)
+		require.Equal(t, 3, n)
+
+		// el código es del usuario: otro no lo puede usar
+		ok, err := repos.RecoveryCodes.Use(ctx, other.ID, "rc-1", now)
+		require.NoError(t, err)
+		require.False(t, ok)
+
+		ok, err = repos.RecoveryCodes.Use(ctx, u.ID, "rc-1", now)
+		require.NoError(t, err)
+		require.True(t, ok)
+
+		ok, err = repos.RecoveryCodes.Use(ctx, u.ID, "rc-1", now)
+		require.NoError(t, err)
+		require.False(t, ok)
+
+		ok, err = repos.RecoveryCodes.Use(ctx, u.ID, "rc-missing", now)
+		require.NoError(t, err)
+		require.False(t, ok)
+
+		n, err = repos.RecoveryCodes.CountUnused(ctx, u.ID)
+		require.NoError(t, err)
+		require.Equal(t, 2, n)
+	})
+
+	t.Run("Replace_DiscardsPreviousCodes", func(t *testing.T) {
+		repos := newRepos(t)
+		u := seedUser(t, repos, now)
+
+		require.NoError(t, repos.RecoveryCodes.Replace(ctx, u.ID, []string{"old-1", "old-2"}, now))
+		require.NoError(t, repos.RecoveryCodes.Replace(ctx, u.ID, []string{"new-1"}, now.Add(time.Hour)))
+
+		ok, err := repos.RecoveryCodes.Use(ctx, u.ID, "old-1", now)
+		require.NoError(t, err)
+		require.False(t, ok)
+
+		n, err := repos.RecoveryCodes.CountUnused(ctx, u.ID)
+		require.NoError(t, err)
+		require.Equal(t, 1, n)
+
+		require.NoError(t, repos.RecoveryCodes.Replace(ctx, u.ID, nil, now))
+		n, err = repos.RecoveryCodes.CountUnused(ctx, u.ID)
+		require.NoError(t, err)
+		require.Zero(t, n)
+	})
+}
+
+func runLoginChallengeContract(t *testing.T, newRepos Factory) {
+	ctx := context.Background()
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	t.Run("FindByHash_ReturnsNilNil_WhenMissing", func(t *testing.T) {
+		got, err := newRepos(t).Challenges.FindByHash(ctx, "zz-missing")
+		require.NoError(t, err)
+		require.Nil(t, got)
+	})
+
+	t.Run("CreateFindMarkUsed_SingleUse", func(t *testing.T) {
+		repos := newRepos(t)
+		u := seedUser(t, repos, now)
+
+		created, err := repos.Challenges.Create(ctx, domain.LoginChallenge{
+			UserID:    u.ID,
+			TokenHash: "lc-1",
+			ExpiresAt: now.Add(5 * time.Minute),
+			CreatedAt: now,
+		})
+		require.NoError(t, err)
+		require.Positive(t, created.ID)
+
+		got, err := repos.Challenges.FindByHash(ctx, "lc-1")
+		require.NoError(t, err)
+		require.NotNil(t, got)
+		require.Equal(t, u.ID, got.UserID)
+		require.True(t, now.Add(5*time.Minute).Equal(got.ExpiresAt))
+		require.False(t, got.IsUsed())
+
+		ok, err := repos.Challenges.MarkUsed(ctx, created.ID, now.Add(time.Minute))
+		require.NoError(t, err)
+		require.True(t, ok)
+
+		ok, err = repos.Challenges.MarkUsed(ctx, created.ID, now.Add(2*time.Minute))
+		require.NoError(t, err)
+		require.False(t, ok)
+
+		got, err = repos.Challenges.FindByHash(ctx, "lc-1")
+		require.NoError(t, err)
+		require.True(t, got.IsUsed())
+	})
+
+	t.Run("DeleteExpired_RemovesOnlyExpired", func(t *testing.T) {
+		repos := newRepos(t)
+		u := seedUser(t, repos, now)
+
+		for _, c := range []domain.LoginChallenge{
+			{UserID: u.ID, TokenHash: "lc-old", ExpiresAt: now.Add(-time.Minute), CreatedAt: now},
+			{UserID: u.ID, TokenHash: "lc-live", ExpiresAt: now.Add(time.Minute), CreatedAt: now},
+		} {
+			_, err := repos.Challenges.Create(ctx, c)
+			require.NoError(t, err)
+		}
+
+		n, err := repos.Challenges.DeleteExpired(ctx, now)
+		require.NoError(t, err)
+		require.Equal(t, int64(1), n)
+
+		got, err := repos.Challenges.FindByHash(ctx, "lc-old")
+		require.NoError(t, err)
+		require.Nil(t, got)
+		got, err = repos.Challenges.FindByHash(ctx, "lc-live")
+		require.NoError(t, err)
+		require.NotNil(t, got)
+	})
+
+	t.Run("Create_FailsOnDuplicateHash", func(t *testing.T) {
+		repos := newRepos(t)
+		u := seedUser(t, repos, now)
+
+		c := domain.LoginChallenge{UserID: u.ID, TokenHash: "lc-dup", ExpiresAt: now, CreatedAt: now}
+		_, err := repos.Challenges.Create(ctx, c)
+		require.NoError(t, err)
+		_, err = repos.Challenges.Create(ctx, c)
+		require.Error(t, err)
+	})
+}
+
+func runUserIdentityContract(t *testing.T, newRepos Factory) {
+	ctx := context.Background()
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	t.Run("FindBySubject_ReturnsNilNil_WhenMissing", func(t *testing.T) {
+		got, err := newRepos(t).Identities.FindBySubject(ctx, "https://idp.example.com", "zz-missing")
+		require.NoError(t, err)
+		require.Nil(t, got)
+	})
+
+	t.Run("CreateFindAndList", func(t *testing.T) {
+		repos := newRepos(t)
+		u := seedUser(t, repos, now)
+
+		created, err := repos.Identities.Create(ctx, domain.UserIdentity{
+			UserID:    u.ID,
+			Issuer:    "https://idp.example.com",
+			Subject:   "sub-1",
+			Email:     u.Email,
+			CreatedAt: now,
+		})
+		require.NoError(t, err)
+		require.Positive(t, created.ID)
+
+		// mismo subject en otro issuer es otra identidad
+		_, err = repos.Identities.Create(ctx, domain.UserIdentity{
+			UserID:    u.ID,
+			Issuer:    "https://other.example.com",
+			Subject:   "sub-1",
+			CreatedAt: now,
+		})
+		require.NoError(t, err)
+
+		got, err := repos.Identities.FindBySubject(ctx, "https://idp.example.com", "sub-1")
+		require.NoError(t, err)
+		require.NotNil(t, got)
+		require.Equal(t, created.ID, got.ID)
+		require.Equal(t, u.ID, got.UserID)
+		require.Equal(t, u.Email, got.Email)
+		require.True(t, now.Equal(got.CreatedAt))
+
+		list, err := repos.Identities.ListByUser(ctx, u.ID)
+		require.NoError(t, err)
+		require.Len(t, list, 2)
+		require.Equal(t, "https://idp.example.com", list[0].Issuer)
+		require.Equal(t, "https://other.example.com", list[1].Issuer)
+	})
+
+	t.Run("Create_FailsOnDuplicateSubject", func(t *testing.T) {
+		repos := newRepos(t)
+		u := seedUser(t, repos, now)
+		other := seedUser(t, repos, now)
+
+		_, err := repos.Identities.Create(ctx, domain.UserIdentity{UserID: u.ID, Issuer: "iss", Subject: "sub-dup", CreatedAt: now})
+		require.NoError(t, err)
+		_, err = repos.Identities.Create(ctx, domain.UserIdentity{UserID: other.ID, Issuer: "iss", Subject: "sub-dup", CreatedAt: now})
+		require.Error(t, err)
+	})
+}
+
+func runOIDCStateContract(t *testing.T, newRepos Factory) {
+	ctx := context.Background()
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	t.Run("FindByHash_ReturnsNilNil_WhenMissing", func(t *testing.T) {
+		got, err := newRepos(t).OIDCStates.FindByHash(ctx, "zz-missing")
+		require.NoError(t, err)
+		require.Nil(t, got)
+	})
+
+	t.Run("CreateFindMarkUsed_SingleUse", func(t *testing.T) {
+		repos := newRepos(t)
+
+		created, err := repos.OIDCStates.Create(ctx, domain.OIDCLoginState{
+			StateHash:    "st-1",
+			Nonce:        "nonce-1",
+			CodeVerifier: "verifier-1",
+			ExpiresAt:    now.Add(10 * time.Minute),
+			CreatedAt:    now,
+		})
+		require.NoError(t, err)
+		require.Positive(t, created.ID)
+
+		got, err := repos.OIDCStates.FindByHash(ctx, "st-1")
+		require.NoError(t, err)
+		require.NotNil(t, got)
+		require.Equal(t, "nonce-1", got.Nonce)
+		require.Equal(t, "verifier-1", got.CodeVerifier)
+		require.True(t, now.Add(10*time.Minute).Equal(got.ExpiresAt))
+		require.False(t, got.IsUsed())
+
+		ok, err := repos.OIDCStates.MarkUsed(ctx, created.ID, now.Add(time.Minute))
+		require.NoError(t, err)
+		require.True(t, ok)
+
+		ok, err = repos.OIDCStates.MarkUsed(ctx, created.ID, now.Add(2*time.Minute))
+		require.NoError(t, err)
+		require.False(t, ok)
+
+		got, err = repos.OIDCStates.FindByHash(ctx, "st-1")
+		require.NoError(t, err)
+		require.True(t, got.IsUsed())
+	})
+
+	t.Run("DeleteExpired_RemovesOnlyExpired", func(t *testing.T) {
+		repos := newRepos(t)
+
+		for _, s := range []domain.OIDCLoginState{
+			{StateHash: "st-old", Nonce: "n", CodeVerifier: "v", ExpiresAt: now.Add(-time.Minute), CreatedAt: now},
+			{StateHash: "st-live", Nonce: "n", CodeVerifier: "v", ExpiresAt: now.Add(time.Minute), CreatedAt: now},
+		} {
+			_, err := repos.OIDCStates.Create(ctx, s)
+			require.NoError(t, err)
+		}
+
+		n, err := repos.OIDCStates.DeleteExpired(ctx, now)
+		require.NoError(t, err)
+		require.Equal(t, int64(1), n)
+
+		got, err := repos.OIDCStates.FindByHash(ctx, "st-old")
+		require.NoError(t, err)
+		require.Nil(t, got)
+		got, err = repos.OIDCStates.FindByHash(ctx, "st-live")
+		require.NoError(t, err)
+		require.NotNil(t, got)
+	})
+
+	t.Run("Create_FailsOnDuplicateHash", func(t *testing.T) {
+		repos := newRepos(t)
+
+		s := domain.OIDCLoginState{StateHash: "st-dup", Nonce: "n", CodeVerifier: "v", ExpiresAt: now, CreatedAt: now}
+		_, err := repos.OIDCStates.Create(ctx, s)
+		require.NoError(t, err)
+		_, err = repos.OIDCStates.Create(ctx, s)
+		require.Error(t, err)
+	})
+}
+
+func newAlertRule(userID int64, coin domain.Coin, kind string, threshold float64, now time.Time) domain.AlertRule {
+	return domain.AlertRule{
+		UserID:    userID,
+		CoinID:    coin.ID,
+		Symbol:    coin.Symbol,
+		Kind:      kind,
+		Threshold: threshold,
+		Active:    true,
+		Armed:     true,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+func runAlertRuleContract(t *testing.T, newRepos Factory) {
+	ctx := context.Background()
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	t.Run("CreateFindListCount", func(t *testing.T) {
+		repos := newRepos(t)
+		u := seedUser(t, repos, now)
+		other := seedUser(t, repos, now)
+		coin := mustUpsertCoin(t, repos.Coins, domain.Coin{Symbol: "ZZAL", Enabled: true, CoinGeckoID: "zz-al"})
+
+		pct := newAlertRule(u.ID, coin, domain.AlertPercentChange, -5, now)
+		pct.Currency = "USD"
+		pct.Provider = "coingecko"
+		pct.Window = time.Hour
+		pct.Rearm = true
+
+		created, err := repos.AlertRules.Create(ctx, pct)
+		require.NoError(t, err)
+		require.Positive(t, created.ID)
+
+		_, err = repos.AlertRules.Create(ctx, newAlertRule(u.ID, coin, domain.AlertAbove, 100000, now))
+		require.NoError(t, err)
+		_, err = repos.AlertRules.Create(ctx, newAlertRule(other.ID, coin, domain.AlertBelow, 1.5, now))
+		require.NoError(t, err)
+
+		got, err := repos.AlertRules.FindByUser(ctx, u.ID, created.ID)
+		require.NoError(t, err)
+		require.NotNil(t, got)
+		require.Equal(t, coin.ID, got.CoinID)
+		require.Equal(t, "ZZAL", got.Symbol)
+		require.Equal(t, "USD", got.Currency)
+		require.Equal(t, "coingecko", got.Provider)
+		require.Equal(t, domain.AlertPercentChange, got.Kind)
+		require.Equal(t, -5.0, got.Threshold)
+		require.Equal(t, time.Hour, got.Window)
+		require.True(t, got.Rearm)
+		require.True(t, got.Active)
+		require.True(t, got.Armed)
+		require.Nil(t, got.LastTriggeredAt)
+		require.True(t, now.Equal(got.CreatedAt))
+
+		// la regla de otro usuario no se ve
+		got, err = repos.AlertRules.FindByUser(ctx, other.ID, created.ID)
+		require.NoError(t, err)
+		require.Nil(t, got)
+
+		list, err := repos.AlertRules.ListByUser(ctx, u.ID)
+		require.NoError(t, err)
+		require.Len(t, list, 2)
+		require.Equal(t, domain.AlertPercentChange, list[0].Kind)
+		require.Equal(t, domain.AlertAbove, list[1].Kind)
+
+		n, err := repos.AlertRules.CountByUser(ctx, u.ID)
+		require.NoError(t, err)
+		require.Equal(t, 2, n)
+	})
+
+	t.Run("UpdateAndDelete_ScopedToUser", func(t *testing.T) {
+		repos := newRepos(t)
+		u := seedUser(t, repos, now)
+		other := seedUser(t, repos, now)
+		coin := mustUpsertCoin(t, repos.Coins, domain.Coin{Symbol: "ZZAL", Enabled: true, CoinGeckoID: "zz-al"})
+
+		created, err := repos.AlertRules.Create(ctx, newAlertRule(u.ID, coin, domain.AlertAbove, 100, now))
+		require.NoError(t, err)
+
+		upd := created
+		upd.UserID = other.ID
+		upd.Threshold = 1
+		ok, err := repos.AlertRules.Update(ctx, upd)
+		require.NoError(t, err)
+		require.False(t, ok)
+
+		upd.UserID = u.ID
+		upd.Threshold = 250.5
+		upd.Rearm = true
+		upd.Active = false
+		upd.UpdatedAt = now.Add(time.Minute)
+		ok, err = repos.AlertRules.Update(ctx, upd)
+		require.NoError(t, err)
+		require.True(t, ok)
+
+		got, err := repos.AlertRules.FindByUser(ctx, u.ID, created.ID)
+		require.NoError(t, err)
+		require.Equal(t, 250.5, got.Threshold)
+		require.True(t, got.Rearm)
+		require.False(t, got.Active)
+		require.True(t, now.Add(time.Minute).Equal(got.UpdatedAt))
+
+		ok, err = repos.AlertRules.Delete(ctx, other.ID, created.ID)
+		require.NoError(t, err)
+		require.False(t, ok)
+
+		ok, err = repos.AlertRules.Delete(ctx, u.ID, created.ID)
+		require.NoError(t, err)
+		require.True(t, ok)
+
+		got, err = repos.AlertRules.FindByUser(ctx, u.ID, created.ID)
+		require.NoError(t, err)
+		require.Nil(t, got)
+	})
+
+	t.Run("ListActive_SkipsInactive", func(t *testing.T) {
+		repos := newRepos(t)
+		u := seedUser(t, repos, now)
+		coin := mustUpsertCoin(t, repos.Coins, domain.Coin{Symbol: "ZZAL", Enabled: true, CoinGeckoID: "zz-al"})
+
+		active, err := repos.AlertRules.Create(ctx, newAlertRule(u.ID, coin, domain.AlertAbove, 1, now))
+		require.NoError(t, err)
+		inactive := newAlertRule(u.ID, coin, domain.AlertBelow, 1, now)
+		inactive.Active = false
+		_, err = repos.AlertRules.Create(ctx, inactive)
+		require.NoError(t, err)
+
+		list, err := repos.AlertRules.ListActive(ctx)
+		require.NoError(t, err)
+		require.Len(t, list, 1)
+		require.Equal(t, active.ID, list[0].ID)
+	})
+
+	t.Run("MarkTriggered_OneShotDeactivates", func(t *testing.T) {
+		repos := newRepos(t)
+		u := seedUser(t, repos, now)
+		coin := mustUpsertCoin(t, repos.Coins, domain.Coin{Symbol: "ZZAL", Enabled: true, CoinGeckoID: "zz-al"})
+
+		created, err := repos.AlertRules.Create(ctx, newAlertRule(u.ID, coin, domain.AlertAbove, 1, now))
+		require.NoError(t, err)
+
+		ok, err := repos.AlertRules.MarkTriggered(ctx, created.ID, now.Add(time.Minute), true)
+		require.NoError(t, err)
+		require.True(t, ok)
+
+		// ya desarmada: un segundo evaluador no la vuelve a disparar
+		ok, err = repos.AlertRules.MarkTriggered(ctx, created.ID, now.Add(2*time.Minute), true)
+		require.NoError(t, err)
+		require.False(t, ok)
+
+		got, err := repos.AlertRules.FindByUser(ctx, u.ID, created.ID)
+		require.NoError(t, err)
+		require.False(t, got.Active)
+		require.False(t, got.Armed)
+		require.NotNil(t, got.LastTriggeredAt)
+		require.True(t, now.Add(time.Minute).Equal(*got.LastTriggeredAt))
+
+		// Rearm no revive una regla inactiva
+		require.NoError(t, repos.AlertRules.Rearm(ctx, created.ID, now.Add(3*time.Minute)))
+		got, err = repos.AlertRules.FindByUser(ctx, u.ID, created.ID)
+		require.NoError(t, err)
+		require.False(t, got.Armed)
+	})
+
+	t.Run("MarkTriggered_RearmingStaysActive", func(t *testing.T) {
+		repos := newRepos(t)
+		u := seedUser(t, repos, now)
+		coin := mustUpsertCoin(t, repos.Coins, domain.Coin{Symbol: "ZZAL", Enabled: true, CoinGeckoID: "zz-al"})
+
+		rule := newAlertRule(u.ID, coin, domain.AlertBelow, 1, now)
+		rule.Rearm = true
+		created, err := repos.AlertRules.Create(ctx, rule)
+		require.NoError(t, err)
+
+		ok, err := repos.AlertRules.MarkTriggered(ctx, created.ID, now.Add(time.Minute), false)
+		require.NoError(t, err)
+		require.True(t, ok)
+
+		got, err := repos.AlertRules.FindByUser(ctx, u.ID, created.ID)
+		require.NoError(t, err)
+		require.True(t, got.Active)
+		require.False(t, got.Armed)
+
+		require.NoError(t, repos.AlertRules.Rearm(ctx, created.ID, now.Add(2*time.Minute)))
+		got, err = repos.AlertRules.FindByUser(ctx, u.ID, created.ID)
+		require.NoError(t, err)
+		require.True(t, got.Armed)
+
+		ok, err = repos.AlertRules.MarkTriggered(ctx, created.ID, now.Add(3*time.Minute), false)
+		require.NoError(t, err)
+		require.True(t, ok)
+	})
+}
+
+func runAlertEventContract(t *testing.T, newRepos Factory) {
+	ctx := context.Background()
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	t.Run("CreateAndList_NewestFirst", func(t *testing.T) {
+		repos := newRepos(t)
+		u := seedUser(t, repos, now)
+		other := seedUser(t, repos, now)
+		coin := mustUpsertCoin(t, repos.Coins, domain.Coin{Symbol: "ZZAL", Enabled: true, CoinGeckoID: "zz-al"})
+
+		above, err := repos.AlertRules.Create(ctx, newAlertRule(u.ID, coin, domain.AlertAbove, 100, now))
+		require.NoError(t, err)
+		pct, err := repos.AlertRules.Create(ctx, newAlertRule(u.ID, coin, domain.AlertPercentChange, -5, now))
+		require.NoError(t, err)
+		foreign, err := repos.AlertRules.Create(ctx, newAlertRule(other.ID, coin, domain.AlertAbove, 100, now))
+		require.NoError(t, err)
+
+		change := -6.25
+		events := []domain.AlertEvent{
+			{RuleID: above.ID, UserID: u.ID, Symbol: "ZZAL", Provider: "coingecko", Currency: "USD", Kind: domain.AlertAbove,
+				Threshold: 100, Price: "101.5", QuotedAt: now, TriggeredAt: now.Add(time.Minute)},
+			{RuleID: pct.ID, UserID: u.ID, Symbol: "ZZAL", Provider: "coingecko", Currency: "USD", Kind: domain.AlertPercentChange,
+				Threshold: -5, Price: "93.75", QuotedAt: now.Add(time.Hour), ReferencePrice: "100", ChangePercent: &change,
+				TriggeredAt: now.Add(time.Hour + time.Minute)},
+			{RuleID: foreign.ID, UserID: other.ID, Symbol: "ZZAL", Provider: "coingecko", Currency: "USD", Kind: domain.AlertAbove,
+				Threshold: 100, Price: "101.5", QuotedAt: now, TriggeredAt: now.Add(time.Minute)},
+		}
+		for _, e := range events {
+			created, err := repos.AlertEvents.Create(ctx, e)
+			require.NoError(t, err)
+			require.Positive(t, created.ID)
+		}
+
+		list, err := repos.AlertEvents.ListByUser(ctx, u.ID, 0, 10)
+		require.NoError(t, err)
+		require.Len(t, list, 2)
+
+		require.Equal(t, pct.ID, list[0].RuleID)
+		require.Equal(t, domain.AlertPercentChange, list[0].Kind)
+		require.Equal(t, -5.0, list[0].Threshold)
+		requirePrice(t, "93.75", list[0].Price)
+		requirePrice(t, "100", list[0].ReferencePrice)
+		require.NotNil(t, list[0].ChangePercent)
+		require.Equal(t, -6.25, *list[0].ChangePercent)
+		require.True(t, now.Add(time.Hour).Equal(list[0].QuotedAt))
+		require.True(t, now.Add(time.Hour+time.Minute).Equal(list[0].TriggeredAt))
+
+		require.Equal(t, above.ID, list[1].RuleID)
+		requirePrice(t, "101.5", list[1].Price)
+		require.Empty(t, list[1].ReferencePrice)
+		require.Nil(t, list[1].ChangePercent)
+
+		list, err = repos.AlertEvents.ListByUser(ctx, u.ID, above.ID, 10)
+		require.NoError(t, err)
+		require.Len(t, list, 1)
+		require.Equal(t, above.ID, list[0].RuleID)
+
+		list, err = repos.AlertEvents.ListByUser(ctx, u.ID, 0, 1)
+		require.NoError(t, err)
+		require.Len(t, list, 1)
+		require.Equal(t, pct.ID, list[0].RuleID)
+	})
+}
+
+func newWebhookSubscription(t *testing.T, repos Repositories, userID int64, now time.Time, events ...string) domain.WebhookSubscription {
+	t.Helper()
+	s, err := repos.Webhooks.Create(context.Background(), domain.WebhookSubscription{
+		UserID:    userID,
+		URL:       "https://example.com/hooks/" + strconv.FormatInt(userID, 10),
+		Events:    events,
+		Secret:    "whsec_test",
+		CreatedAt: now,
+	})
+	require.NoError(t, err)
+	return s
+}
+
+func runWebhookSubscriptionContract(t *testing.T, newRepos Factory) {
+	ctx := context.Background()
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	t.Run("CreateFindListCountDelete", func(t *testing.T) {
+		repos := newRepos(t)
+		u := seedUser(t, repos, now)
+		other := seedUser(t, repos, now)
+
+		created := newWebhookSubscription(t, repos, u.ID, now, domain.WebhookEventQuotesStored, domain.WebhookEventAlertTriggered)
+		require.Positive(t, created.ID)
+		newWebhookSubscription(t, repos, u.ID, now, domain.WebhookEventAlertTriggered)
+
+		got, err := repos.Webhooks.FindByUser(ctx, u.ID, created.ID)
+		require.NoError(t, err)
+		require.NotNil(t, got)
+		require.Equal(t, created.URL, got.URL)
+		require.Equal(t, []string{domain.WebhookEventQuotesStored, domain.WebhookEventAlertTriggered}, got.Events)
+		require.Equal(t, "whsec_test", got.Secret)
+		require.True(t, now.Equal(got.CreatedAt))
+
+		got, err = repos.Webhooks.FindByUser(ctx, other.ID, created.ID)
+		require.NoError(t, err)
+		require.Nil(t, got)
+
+		got, err = repos.Webhooks.FindByID(ctx, created.ID)
+		require.NoError(t, err)
+		require.NotNil(t, got)
+		require.Equal(t, u.ID, got.UserID)
+
+		list, err := repos.Webhooks.ListByUser(ctx, u.ID)
+		require.NoError(t, err)
+		require.Len(t, list, 2)
+		require.Equal(t, created.ID, list[0].ID)
+
+		n, err := repos.Webhooks.CountByUser(ctx, u.ID)
+		require.NoError(t, err)
+		require.Equal(t, 2, n)
+
+		ok, err := repos.Webhooks.Delete(ctx, other.ID, created.ID)
+		require.NoError(t, err)
+		require.False(t, ok)
+
+		ok, err = repos.Webhooks.Delete(ctx, u.ID, created.ID)
+		require.NoError(t, err)
+		require.True(t, ok)
+
+		n, err = repos.Webhooks.CountByUser(ctx, u.ID)
+		require.NoError(t, err)
+		require.Equal(t, 1, n)
+
+		got, err = repos.Webhooks.FindByID(ctx, created.ID)
+		require.NoError(t, err)
+		require.Nil(t, got)
+	})
+
+	t.Run("ListByEvent_MatchesWholeEventsAndUser", func(t *testing.T) {
+		repos := newRepos(t)
+		u := seedUser(t, repos, now)
+		other := seedUser(t, repos, now)
+
+		both := newWebhookSubscription(t, repos, u.ID, now, domain.WebhookEventAlertTriggered, domain.WebhookEventQuotesStored)
+		alerts := newWebhookSubscription(t, repos, u.ID, now, domain.WebhookEventAlertTriggered)
+		quotes := newWebhookSubscription(t, repos, other.ID, now, domain.WebhookEventQuotesStored)
+
+		list, err := repos.Webhooks.ListByEvent(ctx, domain.WebhookEventQuotesStored, 0)
+		require.NoError(t, err)
+		require.Len(t, list, 2)
+		require.Equal(t, both.ID, list[0].ID)
+		require.Equal(t, quotes.ID, list[1].ID)
+
+		list, err = repos.Webhooks.ListByEvent(ctx, domain.WebhookEventAlertTriggered, u.ID)
+		require.NoError(t, err)
+		require.Len(t, list, 2)
+		require.Equal(t, alerts.ID, list[1].ID)
+
+		list, err = repos.Webhooks.ListByEvent(ctx, domain.WebhookEventAlertTriggered, other.ID)
+		require.NoError(t, err)
+		require.Empty(t, list)
+
+		// no matchea por prefijo
+		list, err = repos.Webhooks.ListByEvent(ctx, "alert", 0)
+		require.NoError(t, err)
+		require.Empty(t, list)
+	})
+}
+
+func newWebhookDelivery(subscriptionID int64, eventID string, now time.Time) domain.WebhookDelivery {
+	return domain.WebhookDelivery{
+		SubscriptionID: subscriptionID,
+		EventID:        eventID,
+		EventType:      domain.WebhookEventAlertTriggered,
+		Payload:        `{"id":"` + eventID + `"}`,
+		Status:         domain.WebhookDeliveryPending,
+		NextAttemptAt:  now,
+		CreatedAt:      now,
+	}
+}
+
+func runWebhookDeliveryContract(t *testing.T, newRepos Factory) {
+	ctx := context.Background()
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	t.Run("EnqueueListDueClaimRecord", func(t *testing.T) {
+		repos := newRepos(t)
+		u := seedUser(t, repos, now)
+		sub := newWebhookSubscription(t, repos, u.ID, now, domain.WebhookEventAlertTriggered)
+
+		first, err := repos.Deliveries.Enqueue(ctx, newWebhookDelivery(sub.ID, "evt_1", now))
+		require.NoError(t, err)
+		require.Positive(t, first.ID)
+		_, err = repos.Deliveries.Enqueue(ctx, newWebhookDelivery(sub.ID, "evt_2", now.Add(time.Minute)))
+		require.NoError(t, err)
+
+		due, err := repos.Deliveries.ListDue(ctx, now, 10)
+		require.NoError(t, err)
+		require.Len(t, due, 1)
+		require.Equal(t, first.ID, due[0].ID)
+		require.Equal(t, "evt_1", due[0].EventID)
+		require.Equal(t, `{"id":"evt_1"}`, due[0].Payload)
+		require.Equal(t, 0, due[0].Attempts)
+		require.Nil(t, due[0].LastAttemptAt)
+
+		lease := now.Add(30 * time.Second)
+		ok, err := repos.Deliveries.Claim(ctx, first.ID, 0, lease)
+		require.NoError(t, err)
+		require.True(t, ok)
+
+		// otro dispatcher con la misma foto no la reserva
+		ok, err = repos.Deliveries.Claim(ctx, first.ID, 0, lease)
+		require.NoError(t, err)
+		require.False(t, ok)
+
+		due, err = repos.Deliveries.ListDue(ctx, now, 10)
+		require.NoError(t, err)
+		require.Empty(t, due)
+
+		got, err := repos.Deliveries.FindBySubscription(ctx, sub.ID, first.ID)
+		require.NoError(t, err)
+		require.NotNil(t, got)
+		require.Equal(t, 1, got.Attempts)
+		require.True(t, lease.Equal(got.NextAttemptAt))
+
+		attempted := now.Add(time.Second)
+		got.Status = domain.WebhookDeliveryDelivered
+		got.LastAttemptAt = &attempted
+		got.LastStatusCode = 204
+		got.DeliveredAt = &attempted
+		require.NoError(t, repos.Deliveries.RecordAttempt(ctx, *got))
+
+		got, err = repos.Deliveries.FindBySubscription(ctx, sub.ID, first.ID)
+		require.NoError(t, err)
+		require.Equal(t, domain.WebhookDeliveryDelivered, got.Status)
+		require.Equal(t, 204, got.LastStatusCode)
+		require.NotNil(t, got.DeliveredAt)
+		require.True(t, attempted.Equal(*got.DeliveredAt))
+
+		// de otra suscripción no se ve
+		got, err = repos.Deliveries.FindBySubscription(ctx, sub.ID+1000, first.ID)
+		require.NoError(t, err)
+		require.Nil(t, got)
+
+		n, err := repos.Deliveries.DeleteDeliveredBefore(ctx, attempted)
+		require.NoError(t, err)
+		require.Equal(t, int64(0), n)
+		n, err = repos.Deliveries.DeleteDeliveredBefore(ctx, attempted.Add(time.Second))
+		require.NoError(t, err)
+		require.Equal(t, int64(1), n)
+	})
+
+	t.Run("ListBySubscriptionAndReplay", func(t *testing.T) {
+		repos := newRepos(t)
+		u := seedUser(t, repos, now)
+		sub := newWebhookSubscription(t, repos, u.ID, now, domain.WebhookEventAlertTriggered)
+		otherSub := newWebhookSubscription(t, repos, u.ID, now, domain.WebhookEventAlertTriggered)
+
+		var dead []domain.WebhookDelivery
+		for i := 0; i < 2; i++ {
+			d, err := repos.Deliveries.Enqueue(ctx, newWebhookDelivery(sub.ID, fmt.Sprintf("evt_dead_%d", i), now.Add(time.Duration(i)*time.Minute)))
+			require.NoError(t, err)
+			d.Status = domain.WebhookDeliveryDead
+			d.LastError = "http 500"
+			d.LastStatusCode = 500
+			require.NoError(t, repos.Deliveries.RecordAttempt(ctx, d))
+			dead = append(dead, d)
+		}
+		_, err := repos.Deliveries.Enqueue(ctx, newWebhookDelivery(sub.ID, "evt_pending", now.Add(5*time.Minute)))
+		require.NoError(t, err)
+		_, err = repos.Deliveries.Enqueue(ctx, newWebhookDelivery(otherSub.ID, "evt_other", now))
+		require.NoError(t, err)
+
+		list, err := repos.Deliveries.ListBySubscription(ctx, sub.ID, "", 10)
+		require.NoError(t, err)
+		require.Len(t, list, 3)
+		require.Equal(t, "evt_pending", list[0].EventID)
+
+		list, err = repos.Deliveries.ListBySubscription(ctx, sub.ID, domain.WebhookDeliveryDead, 1)
+		require.NoError(t, err)
+		require.Len(t, list, 1)
+		require.Equal(t, "evt_dead_1", list[0].EventID)
+		require.Equal(t, "http 500", list[0].LastError)
+
+		// replay de una sola entrega
+		replayAt := now.Add(time.Hour)
+		n, err := repos.Deliveries.Replay(ctx, sub.ID, dead[0].ID, replayAt)
+		require.NoError(t, err)
+		require.Equal(t, int64(1), n)
+
+		got, err := repos.Deliveries.FindBySubscription(ctx, sub.ID, dead[0].ID)
+		require.NoError(t, err)
+		require.Equal(t, domain.WebhookDeliveryPending, got.Status)
+		require.Equal(t, 0, got.Attempts)
+		require.True(t, replayAt.Equal(got.NextAttemptAt))
+
+		// de otra suscripción no reencola nada
+		n, err = repos.Deliveries.Replay(ctx, otherSub.ID, dead[1].ID, replayAt)
+		require.NoError(t, err)
+		require.Equal(t, int64(0), n)
+
+		// todas las dead que quedan
+		n, err = repos.Deliveries.Replay(ctx, sub.ID, 0, replayAt)
+		require.NoError(t, err)
+		require.Equal(t, int64(1), n)
+
+		list, err = repos.Deliveries.ListBySubscription(ctx, sub.ID, domain.WebhookDeliveryDead, 10)
+		require.NoError(t, err)
+		require.Empty(t, list)
+	})
+}
+
+func newPortfolio(t *testing.T, repos Repositories, userID int64, name string, now time.Time) domain.Portfolio {
+	t.Helper()
+	p, err := repos.Portfolios.Create(context.Background(), domain.Portfolio{
+		UserID:       userID,
+		Name:         name,
+		BaseCurrency: "USD",
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	})
+	require.NoError(t, err)
+	return p
+}
+
+func runPortfolioContract(t *testing.T, newRepos Factory) {
+	ctx := context.Background()
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	t.Run("CreateFindListCount", func(t *testing.T) {
+		repos := newRepos(t)
+		u := seedUser(t, repos, now)
+		other := seedUser(t, repos, now)
+
+		created := newPortfolio(t, repos, u.ID, "Largo plazo", now)
+		require.Positive(t, created.ID)
+		newPortfolio(t, repos, u.ID, "Trading", now)
+		newPortfolio(t, repos, other.ID, "Ajeno", now)
+
+		got, err := repos.Portfolios.FindByUser(ctx, u.ID, created.ID)
+		require.NoError(t, err)
+		require.NotNil(t, got)
+		require.Equal(t, "Largo plazo", got.Name)
+		require.Equal(t, "USD", got.BaseCurrency)
+		require.True(t, now.Equal(got.CreatedAt))
+		require.True(t, now.Equal(got.UpdatedAt))
+
+		// de otro usuario no se ve
+		got, err = repos.Portfolios.FindByUser(ctx, other.ID, created.ID)
+		require.NoError(t, err)
+		require.Nil(t, got)
+
+		list, err := repos.Portfolios.ListByUser(ctx, u.ID)
+		require.NoError(t, err)
+		require.Len(t, list, 2)
+		require.Equal(t, created.ID, list[0].ID)
+		require.Equal(t, "Trading", list[1].Name)
+
+		n, err := repos.Portfolios.CountByUser(ctx, u.ID)
+		require.NoError(t, err)
+		require.Equal(t, 2, n)
+	})
+
+	t.Run("UpdateAndDelete_OnlyForOwner", func(t *testing.T) {
+		repos := newRepos(t)
+		u := seedUser(t, repos, now)
+		other := seedUser(t, repos, now)
+		p := newPortfolio(t, repos, u.ID, "Principal", now)
+
+		later := now.Add(time.Hour)
+		upd := p
+		upd.Name = "Renombrado"
+		upd.BaseCurrency = "EUR"
+		upd.UpdatedAt = later
+
+		upd.UserID = other.ID
+		ok, err := repos.Portfolios.Update(ctx, upd)
+		require.NoError(t, err)
+		require.False(t, ok)
+
+		upd.UserID = u.ID
+		ok, err = repos.Portfolios.Update(ctx, upd)
+		require.NoError(t, err)
+		require.True(t, ok)
+
+		got, err := repos.Portfolios.FindByUser(ctx, u.ID, p.ID)
+		require.NoError(t, err)
+		require.Equal(t, "Renombrado", got.Name)
+		require.Equal(t, "EUR", got.BaseCurrency)
+		require.True(t, now.Equal(got.CreatedAt))
+		require.True(t, later.Equal(got.UpdatedAt))
+
+		ok, err = repos.Portfolios.Delete(ctx, other.ID, p.ID)
+		require.NoError(t, err)
+		require.False(t, ok)
+
+		ok, err = repos.Portfolios.Delete(ctx, u.ID, p.ID)
+		require.NoError(t, err)
+		require.True(t, ok)
+
+		got, err = repos.Portfolios.FindByUser(ctx, u.ID, p.ID)
+		require.NoError(t, err)
+		require.Nil(t, got)
+	})
+}
+
+func runHoldingContract(t *testing.T, newRepos Factory) {
+	ctx := context.Background()
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	t.Run("UpsertListDelete", func(t *testing.T) {
+		repos := newRepos(t)
+		u := seedUser(t, repos, now)
+		p := newPortfolio(t, repos, u.ID, "Principal", now)
+		other := newPortfolio(t, repos, u.ID, "Otro", now)
+		zzb := mustUpsertCoin(t, repos.Coins, domain.Coin{Symbol: "ZZHB", Enabled: true, CoinGeckoID: "zz-hb"})
+		zza := mustUpsertCoin(t, repos.Coins, domain.Coin{Symbol: "ZZHA", Enabled: true, CoinGeckoID: "zz-ha"})
+
+		created, err := repos.Holdings.Upsert(ctx, domain.Holding{PortfolioID: p.ID, CoinID: zzb.ID, Quantity: "1.5", CreatedAt: now, UpdatedAt: now})
+		require.NoError(t, err)
+		require.Positive(t, created.ID)
+		require.Equal(t, "ZZHB", created.Symbol)
+		requirePrice(t, "1.5", created.Quantity)
+
+		_, err = repos.Holdings.Upsert(ctx, domain.Holding{PortfolioID: p.ID, CoinID: zza.ID, Quantity: "0.000000000000000001", CreatedAt: now, UpdatedAt: now})
+		require.NoError(t, err)
+		_, err = repos.Holdings.Upsert(ctx, domain.Holding{PortfolioID: other.ID, CoinID: zza.ID, Quantity: "7", CreatedAt: now, UpdatedAt: now})
+		require.NoError(t, err)
+
+		// pisar la cantidad conserva id y created_at
+		later := now.Add(time.Hour)
+		updated, err := repos.Holdings.Upsert(ctx, domain.Holding{PortfolioID: p.ID, CoinID: zzb.ID, Quantity: "12345678901234567890.123456789012345678", CreatedAt: later, UpdatedAt: later})
+		require.NoError(t, err)
+		require.Equal(t, created.ID, updated.ID)
+		require.True(t, now.Equal(updated.CreatedAt))
+		require.True(t, later.Equal(updated.UpdatedAt))
+
+		list, err := repos.Holdings.ListByPortfolio(ctx, p.ID)
+		require.NoError(t, err)
+		require.Len(t, list, 2)
+		require.Equal(t, "ZZHA", list[0].Symbol)
+		requirePrice(t, "0.000000000000000001", list[0].Quantity)
+		require.Equal(t, "ZZHB", list[1].Symbol)
+		require.Equal(t, p.ID, list[1].PortfolioID)
+		require.Equal(t, zzb.ID, list[1].CoinID)
+		// sin redondeo: se compara el texto sin ceros de relleno
+		require.Equal(t, "12345678901234567890.123456789012345678", strings.TrimRight(list[1].Quantity, "0"))
+
+		ok, err := repos.Holdings.Delete(ctx, p.ID, zza.ID)
+		require.NoError(t, err)
+		require.True(t, ok)
+		ok, err = repos.Holdings.Delete(ctx, p.ID, zza.ID)
+		require.NoError(t, err)
+		require.False(t, ok)
+
+		list, err = repos.Holdings.ListByPortfolio(ctx, p.ID)
+		require.NoError(t, err)
+		require.Len(t, list, 1)
+
+		list, err = repos.Holdings.ListByPortfolio(ctx, other.ID)
+		require.NoError(t, err)
+		require.Len(t, list, 1)
+		requirePrice(t, "7", list[0].Quantity)
+	})
+}
+
+func newTransaction(portfolioID int64, coin domain.Coin, kind, quantity, price string, at time.Time) domain.Transaction {
+	return domain.Transaction{
+		PortfolioID: portfolioID,
+		CoinID:      coin.ID,
+		Type:        kind,
+		Quantity:    quantity,
+		Price:       price,
+		Fee:         "0",
+		Currency:    "USD",
+		ExecutedAt:  at,
+		CreatedAt:   at,
+	}
+}
+
+func runTransactionContract(t *testing.T, newRepos Factory) {
+	ctx := context.Background()
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	t.Run("CreateFindDelete", func(t *testing.T) {
+		repos := newRepos(t)
+		u := seedUser(t, repos, now)
+		p := newPortfolio(t, repos, u.ID, "Principal", now)
+		other := newPortfolio(t, repos, u.ID, "Otro", now)
+		coin := mustUpsertCoin(t, repos.Coins, domain.Coin{Symbol: "ZZTX", Enabled: true, CoinGeckoID: "zz-tx"})
+
+		tx := newTransaction(p.ID, coin, domain.TransactionBuy, "0.123456789012345678", "65000.5", now)
+		tx.Fee = "1.25"
+		tx.Currency = "EUR"
+		tx.Note = "primera compra"
+		created, err := repos.Transactions.Create(ctx, tx)
+		require.NoError(t, err)
+		require.Positive(t, created.ID)
+
+		got, err := repos.Transactions.FindByPortfolio(ctx, p.ID, created.ID)
+		require.NoError(t, err)
+		require.NotNil(t, got)
+		require.Equal(t, "ZZTX", got.Symbol)
+		require.Equal(t, coin.ID, got.CoinID)
+		require.Equal(t, domain.TransactionBuy, got.Type)
+		require.Equal(t, "0.123456789012345678", strings.TrimRight(got.Quantity, "0"))
+		requirePrice(t, "65000.5", got.Price)
+		requirePrice(t, "1.25", got.Fee)
+		require.Equal(t, "EUR", got.Currency)
+		require.Equal(t, "primera compra", got.Note)
+		require.True(t, now.Equal(got.ExecutedAt))
+
+		// de otro portfolio no se ve ni se borra
+		got, err = repos.Transactions.FindByPortfolio(ctx, other.ID, created.ID)
+		require.NoError(t, err)
+		require.Nil(t, got)
+
+		ok, err := repos.Transactions.Delete(ctx, other.ID, created.ID)
+		require.NoError(t, err)
+		require.False(t, ok)
+
+		ok, err = repos.Transactions.Delete(ctx, p.ID, created.ID)
+		require.NoError(t, err)
+		require.True(t, ok)
+
+		got, err = repos.Transactions.FindByPortfolio(ctx, p.ID, created.ID)
+		require.NoError(t, err)
+		require.Nil(t, got)
+	})
+
+	t.Run("Create_KeepsMissingTransferInPriceApartFromZero", func(t *testing.T) {
+		repos := newRepos(t)
+		u := seedUser(t, repos, now)
+		p := newPortfolio(t, repos, u.ID, "Principal", now)
+		coin := mustUpsertCoin(t, repos.Coins, domain.Coin{Symbol: "ZZTI", Enabled: true, CoinGeckoID: "zz-ti"})
+
+		missing, err := repos.Transactions.Create(ctx, newTransaction(p.ID, coin, domain.TransactionTransferIn, "1", "", now))
+		require.NoError(t, err)
+		zero, err := repos.Transactions.Create(ctx, newTransaction(p.ID, coin, domain.TransactionTransferIn, "1", "0", now))
+		require.NoError(t, err)
+
+		got, err := repos.Transactions.FindByPortfolio(ctx, p.ID, missing.ID)
+		require.NoError(t, err)
+		require.NotNil(t, got)
+		require.Empty(t, got.Price)
+
+		got, err = repos.Transactions.FindByPortfolio(ctx, p.ID, zero.ID)
+		require.NoError(t, err)
+		require.NotNil(t, got)
+		requirePrice(t, "0", got.Price)
+	})
+
+	t.Run("ListByPortfolio_ChronologicalWithFilters", func(t *testing.T) {
+		repos := newRepos(t)
+		u := seedUser(t, repos, now)
+		p := newPortfolio(t, repos, u.ID, "Principal", now)
+		other := newPortfolio(t, repos, u.ID, "Otro", now)
+		a := mustUpsertCoin(t, repos.Coins, domain.Coin{Symbol: "ZZTA", Enabled: true, CoinGeckoID: "zz-ta"})
+		b := mustUpsertCoin(t, repos.Coins, domain.Coin{Symbol: "ZZTB", Enabled: true, CoinGeckoID: "zz-tb"})
+
+		// se cargan desordenados; dos en el mismo instante desempatan por id
+		sell, err := repos.Transactions.Create(ctx, newTransaction(p.ID, a, domain.TransactionSell, "1", "120", now.Add(48*time.Hour)))
+		require.NoError(t, err)
+		buy1, err := repos.Transactions.Create(ctx, newTransaction(p.ID, a, domain.TransactionBuy, "2", "100", now))
+		require.NoError(t, err)
+		buy2, err := repos.Transactions.Create(ctx, newTransaction(p.ID, b, domain.TransactionBuy, "5", "10", now))
+		require.NoError(t, err)
+		in, err := repos.Transactions.Create(ctx, newTransaction(p.ID, b, domain.TransactionTransferIn, "1", "0", now.Add(24*time.Hour)))
+		require.NoError(t, err)
+		_, err = repos.Transactions.Create(ctx, newTransaction(other.ID, a, domain.TransactionBuy, "9", "1", now))
+		require.NoError(t, err)
+
+		list, err := repos.Transactions.ListByPortfolio(ctx, p.ID, domain.TransactionFilter{})
+		require.NoError(t, err)
+		require.Len(t, list, 4)
+		require.Equal(t, []int64{buy1.ID, buy2.ID, in.ID, sell.ID}, []int64{list[0].ID, list[1].ID, list[2].ID, list[3].ID})
+		require.Equal(t, "ZZTB", list[1].Symbol)
+
+		list, err = repos.Transactions.ListByPortfolio(ctx, p.ID, domain.TransactionFilter{CoinID: a.ID})
+		require.NoError(t, err)
+		require.Len(t, list, 2)
+		require.Equal(t, buy1.ID, list[0].ID)
+		require.Equal(t, sell.ID, list[1].ID)
+
+		from := now.Add(time.Hour)
+		to := now.Add(48 * time.Hour)
+		list, err = repos.Transactions.ListByPortfolio(ctx, p.ID, domain.TransactionFilter{From: &from, To: &to})
+		require.NoError(t, err)
+		require.Len(t, list, 1)
+		require.Equal(t, in.ID, list[0].ID)
+	})
+
+	t.Run("CreateChecked_ChecksCoinMovementsAndSkipsOnError", func(t *testing.T) {
+		repos := newRepos(t)
+		u := seedUser(t, repos, now)
+		p := newPortfolio(t, repos, u.ID, "Principal", now)
+		a := mustUpsertCoin(t, repos.Coins, domain.Coin{Symbol: "ZZTC", Enabled: true, CoinGeckoID: "zz-tc"})
+		b := mustUpsertCoin(t, repos.Coins, domain.Coin{Symbol: "ZZTD", Enabled: true, CoinGeckoID: "zz-td"})
+
+		buy, err := repos.Transactions.Create(ctx, newTransaction(p.ID, a, domain.TransactionBuy, "1", "100", now))
+		require.NoError(t, err)
+		_, err = repos.Transactions.Create(ctx, newTransaction(p.ID, b, domain.TransactionBuy, "1", "100", now))
+		require.NoError(t, err)
+
+		rejected := errors.New("rejected")
+		_, err = repos.Transactions.CreateChecked(ctx, newTransaction(p.ID, a, domain.TransactionSell, "1", "120", now.Add(time.Hour)),
+			func(existing []domain.Transaction) error {
+				require.Len(t, existing, 1)
+				require.Equal(t, buy.ID, existing[0].ID)
+				require.Equal(t, "ZZTC", existing[0].Symbol)
+				return rejected
+			})
+		require.ErrorIs(t, err, rejected)
+
+		list, err := repos.Transactions.ListByPortfolio(ctx, p.ID, domain.TransactionFilter{CoinID: a.ID})
+		require.NoError(t, err)
+		require.Len(t, list, 1)
+	})
+
+	t.Run("CreateChecked_ConcurrentSellsSeeEachOther", func(t *testing.T) {
+		repos := newRepos(t)
+		u := seedUser(t, repos, now)
+		p := newPortfolio(t, repos, u.ID, "Principal", now)
+		coin := mustUpsertCoin(t, repos.Coins, domain.Coin{Symbol: "ZZTE", Enabled: true, CoinGeckoID: "zz-te"})
+
+		_, err := repos.Transactions.Create(ctx, newTransaction(p.ID, coin, domain.TransactionBuy, "1", "100", now))
+		require.NoError(t, err)
+
+		// sólo hay saldo para una venta: la que llega segunda tiene que ver a la primera
+		soldOut := errors.New("sold_out")
+		onlyFirstSell := func(existing []domain.Transaction) error {
+			for _, e := range existing {
+				if e.Type == domain.TransactionSell {
+					return soldOut
+				}
+			}
+			return nil
+		}
+
+		const sellers = 5
+		errs := make(chan error, sellers)
+		var wg sync.WaitGroup
+		for i := 0; i < sellers; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err := repos.Transactions.CreateChecked(ctx, newTransaction(p.ID, coin, domain.TransactionSell, "1", "120", now.Add(time.Hour)), onlyFirstSell)
+				errs <- err
+			}()
+		}
+		wg.Wait()
+		close(errs)
+
+		created := 0
+		for err := range errs {
+			if err == nil {
+				created++
+				continue
+			}
+			require.ErrorIs(t, err, soldOut)
+		}
+		require.Equal(t, 1, created)
+
+		list, err := repos.Transactions.ListByPortfolio(ctx, p.ID, domain.TransactionFilter{CoinID: coin.ID})
+		require.NoError(t, err)
+		require.Len(t, list, 2)
+	})
+}
+
+func runPortfolioSnapshotContract(t *testing.T, newRepos Factory) {
+	ctx := context.Background()
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+	day := func(d int) time.Time { return time.Date(2026, 1, d, 0, 0, 0, 0, time.UTC) }
+
+	t.Run("UpsertListRangeDeleteFrom", func(t *testing.T) {
+		repos := newRepos(t)
+		u := seedUser(t, repos, now)
+		p := newPortfolio(t, repos, u.ID, "Principal", now)
+		other := newPortfolio(t, repos, u.ID, "Otro", now)
+
+		for d := 1; d <= 4; d++ {
+			require.NoError(t, repos.Snapshots.Upsert(ctx, domain.PortfolioSnapshot{
+				PortfolioID: p.ID, Day: day(d), Currency: "USD", Value: strconv.Itoa(d * 100), ComputedAt: now,
+			}))
+		}
+		require.NoError(t, repos.Snapshots.Upsert(ctx, domain.PortfolioSnapshot{PortfolioID: p.ID, Day: day(2), Currency: "EUR", Value: "1", ComputedAt: now}))
+		require.NoError(t, repos.Snapshots.Upsert(ctx, domain.PortfolioSnapshot{PortfolioID: other.ID, Day: day(2), Currency: "USD", Value: "7", ComputedAt: now}))
+
+		// reemplaza el valor del día 3
+		require.NoError(t, repos.Snapshots.Upsert(ctx, domain.PortfolioSnapshot{
+			PortfolioID: p.ID, Day: day(3), Currency: "USD", Value: "333.5", ComputedAt: now.Add(time.Hour),
+		}))
+
+		list, err := repos.Snapshots.ListRange(ctx, p.ID, "USD", day(2), day(3))
+		require.NoError(t, err)
+		require.Len(t, list, 2)
+		require.True(t, day(2).Equal(list[0].Day), "day %s", list[0].Day)
+		requirePrice(t, "200", list[0].Value)
+		require.Equal(t, "USD", list[0].Currency)
+		require.True(t, day(3).Equal(list[1].Day))
+		requirePrice(t, "333.5", list[1].Value)
+		require.True(t, now.Add(time.Hour).Equal(list[1].ComputedAt))
+
+		// borra desde el día 2 en todas las monedas, sólo de ese portfolio
+		require.NoError(t, repos.Snapshots.DeleteFrom(ctx, p.ID, day(2)))
+		list, err = repos.Snapshots.ListRange(ctx, p.ID, "USD", day(1), day(31))
+		require.NoError(t, err)
+		require.Len(t, list, 1)
+		require.True(t, day(1).Equal(list[0].Day))
+
+		list, err = repos.Snapshots.ListRange(ctx, p.ID, "EUR", day(1), day(31))
+		require.NoError(t, err)
+		require.Empty(t, list)
+
+		list, err = repos.Snapshots.ListRange(ctx, other.ID, "USD", day(1), day(31))
+		require.NoError(t, err)
+		require.Len(t, list, 1)
+	})
+}
+
+func newWatchlist(t *testing.T, repos Repositories, userID int64, name string, now time.Time) domain.Watchlist {
+	t.Helper()
+	w, err := repos.Watchlists.Create(context.Background(), domain.Watchlist{
+		UserID:    userID,
+		Name:      name,
+		CreatedAt: now,
+		UpdatedAt: now,
+	})
+	require.NoError(t, err)
+	return w
+}
+
+func runWatchlistContract(t *testing.T, newRepos Factory) {
+	ctx := context.Background()
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	t.Run("EnsureDefault_CreatesOnce_ListsFirst", func(t *testing.T) {
+		repos := newRepos(t)
+		u := seedUser(t, repos, now)
+		other := seedUser(t, repos, now)
+
+		custom := newWatchlist(t, repos, u.ID, "Memes", now)
+		require.Positive(t, custom.ID)
+		require.False(t, custom.IsDefault)
+
+		def, err := repos.Watchlists.EnsureDefault(ctx, domain.Watchlist{UserID: u.ID, Name: "Favorites", CreatedAt: now, UpdatedAt: now})
+		require.NoError(t, err)
+		require.True(t, def.IsDefault)
+		require.Equal(t, "Favorites", def.Name)
+
+		// la segunda no crea otra ni cambia el nombre
+		again, err := repos.Watchlists.EnsureDefault(ctx, domain.Watchlist{UserID: u.ID, Name: "Otra", CreatedAt: now, UpdatedAt: now})
+		require.NoError(t, err)
+		require.Equal(t, def.ID, again.ID)
+		require.Equal(t, "Favorites", again.Name)
+
+		otherDef, err := repos.Watchlists.EnsureDefault(ctx, domain.Watchlist{UserID: other.ID, Name: "Favorites", CreatedAt: now, UpdatedAt: now})
+		require.NoError(t, err)
+		require.NotEqual(t, def.ID, otherDef.ID)
+
+		list, err := repos.Watchlists.ListByUser(ctx, u.ID)
+		require.NoError(t, err)
+		require.Len(t, list, 2)
+		require.Equal(t, def.ID, list[0].ID)
+		require.True(t, list[0].IsDefault)
+		require.Equal(t, custom.ID, list[1].ID)
+		require.False(t, list[1].IsDefault)
+
+		n, err := repos.Watchlists.CountByUser(ctx, u.ID)
+		require.NoError(t, err)
+		require.Equal(t, 2, n)
+
+		got, err := repos.Watchlists.FindByUser(ctx, u.ID, def.ID)
+		require.NoError(t, err)
+		require.NotNil(t, got)
+		require.True(t, got.IsDefault)
+		require.True(t, now.Equal(got.CreatedAt))
+
+		// de otro usuario no se ve
+		got, err = repos.Watchlists.FindByUser(ctx, other.ID, custom.ID)
+		require.NoError(t, err)
+		require.Nil(t, got)
+	})
+
+	t.Run("UpdateAndDelete_OnlyForOwner_DefaultStays", func(t *testing.T) {
+		repos := newRepos(t)
+		u := seedUser(t, repos, now)
+		other := seedUser(t, repos, now)
+		w := newWatchlist(t, repos, u.ID, "Memes", now)
+		def, err := repos.Watchlists.EnsureDefault(ctx, domain.Watchlist{UserID: u.ID, Name: "Favorites", CreatedAt: now, UpdatedAt: now})
+		require.NoError(t, err)
+
+		later := now.Add(time.Hour)
+		upd := w
+		upd.Name = "Renombrada"
+		upd.UpdatedAt = later
+
+		upd.UserID = other.ID
+		ok, err := repos.Watchlists.Update(ctx, upd)
+		require.NoError(t, err)
+		require.False(t, ok)
+
+		upd.UserID = u.ID
+		ok, err = repos.Watchlists.Update(ctx, upd)
+		require.NoError(t, err)
+		require.True(t, ok)
+
+		got, err := repos.Watchlists.FindByUser(ctx, u.ID, w.ID)
+		require.NoError(t, err)
+		require.Equal(t, "Renombrada", got.Name)
+		require.True(t, later.Equal(got.UpdatedAt))
+
+		// la por defecto se puede renombrar pero no borrar
+		def.Name = "Principal"
+		ok, err = repos.Watchlists.Update(ctx, def)
+		require.NoError(t, err)
+		require.True(t, ok)
+
+		ok, err = repos.Watchlists.Delete(ctx, u.ID, def.ID)
+		require.NoError(t, err)
+		require.False(t, ok)
+
+		ok, err = repos.Watchlists.Delete(ctx, other.ID, w.ID)
+		require.NoError(t, err)
+		require.False(t, ok)
+
+		ok, err = repos.Watchlists.Delete(ctx, u.ID, w.ID)
+		require.NoError(t, err)
+		require.True(t, ok)
+
+		list, err := repos.Watchlists.ListByUser(ctx, u.ID)
+		require.NoError(t, err)
+		require.Len(t, list, 1)
+		require.Equal(t, "Principal", list[0].Name)
+		require.True(t, list[0].IsDefault)
+	})
+}
+
+func runWatchlistEntryContract(t *testing.T, newRepos Factory) {
+	ctx := context.Background()
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	t.Run("AddNoteReorderRemove", func(t *testing.T) {
+		repos := newRepos(t)
+		u := seedUser(t, repos, now)
+		w := newWatchlist(t, repos, u.ID, "Memes", now)
+		otherList := newWatchlist(t, repos, u.ID, "Otra", now)
+
+		a := mustUpsertCoin(t, repos.Coins, domain.Coin{Symbol: "ZZWA", Enabled: true, CoinGeckoID: "zz-wa"})
+		b := mustUpsertCoin(t, repos.Coins, domain.Coin{Symbol: "ZZWB", Enabled: true, CoinGeckoID: "zz-wb"})
+		c := mustUpsertCoin(t, repos.Coins, domain.Coin{Symbol: "ZZWC", Enabled: false, CoinGeckoID: "zz-wc"})
+
+		// se agregan al final, en el orden de alta
+		for _, coin := range []domain.Coin{c, a, b} {
+			added, err := repos.WatchlistEntries.Add(ctx, domain.WatchlistEntry{WatchlistID: w.ID, CoinID: coin.ID, CreatedAt: now, UpdatedAt: now})
+			require.NoError(t, err)
+			require.True(t, added)
+		}
+		added, err := repos.WatchlistEntries.Add(ctx, domain.WatchlistEntry{WatchlistID: w.ID, CoinID: a.ID, Note: "otra", CreatedAt: now, UpdatedAt: now})
+		require.NoError(t, err)
+		require.False(t, added)
+
+		added, err = repos.WatchlistEntries.Add(ctx, domain.WatchlistEntry{WatchlistID: otherList.ID, CoinID: a.ID, Note: "en otra lista", CreatedAt: now, UpdatedAt: now})
+		require.NoError(t, err)
+		require.True(t, added)
+
+		symbols := func(list []domain.WatchlistEntry) []string {
+			out := make([]string, 0, len(list))
+			for _, e := range list {
+				out = append(out, e.Symbol)
+			}
+			return out
+		}
+
+		list, err := repos.WatchlistEntries.ListByWatchlist(ctx, w.ID)
+		require.NoError(t, err)
+		require.Equal(t, []string{"ZZWC", "ZZWA", "ZZWB"}, symbols(list))
+		require.Equal(t, "", list[1].Note)
+		require.Equal(t, w.ID, list[1].WatchlistID)
+		require.Equal(t, a.ID, list[1].CoinID)
+		require.True(t, now.Equal(list[1].CreatedAt))
+
+		later := now.Add(time.Hour)
+		ok, err := repos.WatchlistEntries.UpdateNote(ctx, w.ID, a.ID, "comprar bajo 1", later)
+		require.NoError(t, err)
+		require.True(t, ok)
+
+		ok, err = repos.WatchlistEntries.UpdateNote(ctx, w.ID, 999999, "x", later)
+		require.NoError(t, err)
+		require.False(t, ok)
+
+		require.NoError(t, repos.WatchlistEntries.Reorder(ctx, w.ID, []int64{b.ID, a.ID, c.ID}))
+
+		list, err = repos.WatchlistEntries.ListByWatchlist(ctx, w.ID)
+		require.NoError(t, err)
+		require.Equal(t, []string{"ZZWB", "ZZWA", "ZZWC"}, symbols(list))
+		require.Equal(t, "comprar bajo 1", list[1].Note)
+		require.True(t, later.Equal(list[1].UpdatedAt))
+
+		// una alta después de reordenar queda última
+		d := mustUpsertCoin(t, repos.Coins, domain.Coin{Symbol: "ZZWD", Enabled: true, CoinGeckoID: "zz-wd"})
+		added, err = repos.WatchlistEntries.Add(ctx, domain.WatchlistEntry{WatchlistID: w.ID, CoinID: d.ID, CreatedAt: now, UpdatedAt: now})
+		require.NoError(t, err)
+		require.True(t, added)
+
+		ok, err = repos.WatchlistEntries.Remove(ctx, w.ID, a.ID)
+		require.NoError(t, err)
+		require.True(t, ok)
+		ok, err = repos.WatchlistEntries.Remove(ctx, w.ID, a.ID)
+		require.NoError(t, err)
+		require.False(t, ok)
+
+		list, err = repos.WatchlistEntries.ListByWatchlist(ctx, w.ID)
+		require.NoError(t, err)
+		require.Equal(t, []string{"ZZWB", "ZZWC", "ZZWD"}, symbols(list))
+
+		// la otra lista no cambió
+		list, err = repos.WatchlistEntries.ListByWatchlist(ctx, otherList.ID)
+		require.NoError(t, err)
+		require.Equal(t, []string{"ZZWA"}, symbols(list))
+		require.Equal(t, "en otra lista", list[0].Note)
+	})
+
+	t.Run("Favorites_UseDefaultWatchlist", func(t *testing.T) {
+		repos := newRepos(t)
+		u := seedUser(t, repos, now)
+		a := mustUpsertCoin(t, repos.Coins, domain.Coin{Symbol: "ZZWE", Enabled: true, CoinGeckoID: "zz-we"})
+		b := mustUpsertCoin(t, repos.Coins, domain.Coin{Symbol: "ZZWF", Enabled: true, CoinGeckoID: "zz-wf"})
+
+		require.NoError(t, repos.Favorites.AddFavoriteCoinToUser(ctx, u.ID, b.ID))
+
+		def, err := repos.Watchlists.EnsureDefault(ctx, domain.Watchlist{UserID: u.ID, Name: "Otra", CreatedAt: now, UpdatedAt: now})
+		require.NoError(t, err)
+		require.Equal(t, domain.DefaultWatchlistName, def.Name)
+
+		list, err := repos.WatchlistEntries.ListByWatchlist(ctx, def.ID)
+		require.NoError(t, err)
+		require.Len(t, list, 1)
+		require.Equal(t, b.ID, list[0].CoinID)
+
+		added, err := repos.WatchlistEntries.Add(ctx, domain.WatchlistEntry{WatchlistID: def.ID, CoinID: a.ID, CreatedAt: now, UpdatedAt: now})
+		require.NoError(t, err)
+		require.True(t, added)
+
+		favs, err := repos.Favorites.ListFavoriteCoinIDsByUser(ctx, u.ID)
+		require.NoError(t, err)
+		require.Equal(t, []domain.Coin{a, b}, favs)
+
+		require.NoError(t, repos.Favorites.RemoveFavoriteCoinFromUser(ctx, u.ID, a.ID))
+		list, err = repos.WatchlistEntries.ListByWatchlist(ctx, def.ID)
+		require.NoError(t, err)
+		require.Len(t, list, 1)
+		require.Equal(t, "ZZWF", list[0].Symbol)
+	})
+}
@@ -0,0 +1,231 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: alert_port.go
+//
+// Generated by this command:
+//
+//	mockgen -source=alert_port.go -destination=../test/mocks/alert_port_mock.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	domain "github.com/moondolphin/crypto-api/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockAlertRuleRepository is a mock of AlertRuleRepository interface.
+type MockAlertRuleRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockAlertRuleRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockAlertRuleRepositoryMockRecorder is the mock recorder for MockAlertRuleRepository.
+type MockAlertRuleRepositoryMockRecorder struct {
+	mock *MockAlertRuleRepository
+}
+
+// NewMockAlertRuleRepository creates a new mock instance.
+func NewMockAlertRuleRepository(ctrl *gomock.Controller) *MockAlertRuleRepository {
+	mock := &MockAlertRuleRepository{ctrl: ctrl}
+	mock.recorder = &MockAlertRuleRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAlertRuleRepository) EXPECT() *MockAlertRuleRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CountByUser mocks base method.
+func (m *MockAlertRuleRepository) CountByUser(ctx context.Context, userID int64) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountByUser", ctx, userID)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountByUser indicates an expected call of CountByUser.
+func (mr *MockAlertRuleRepositoryMockRecorder) CountByUser(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountByUser", reflect.TypeOf((*MockAlertRuleRepository)(nil).CountByUser), ctx, userID)
+}
+
+// Create mocks base method.
+func (m *MockAlertRuleRepository) Create(ctx context.Context, r domain.AlertRule) (domain.AlertRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, r)
+	ret0, _ := ret[0].(domain.AlertRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockAlertRuleRepositoryMockRecorder) Create(ctx, r any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockAlertRuleRepository)(nil).Create), ctx, r)
+}
+
+// Delete mocks base method.
+func (m *MockAlertRuleRepository) Delete(ctx context.Context, userID, id int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, userID, id)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockAlertRuleRepositoryMockRecorder) Delete(ctx, userID, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockAlertRuleRepository)(nil).Delete), ctx, userID, id)
+}
+
+// FindByUser mocks base method.
+func (m *MockAlertRuleRepository) FindByUser(ctx context.Context, userID, id int64) (*domain.AlertRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByUser", ctx, userID, id)
+	ret0, _ := ret[0].(*domain.AlertRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByUser indicates an expected call of FindByUser.
+func (mr *MockAlertRuleRepositoryMockRecorder) FindByUser(ctx, userID, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByUser", reflect.TypeOf((*MockAlertRuleRepository)(nil).FindByUser), ctx, userID, id)
+}
+
+// ListActive mocks base method.
+func (m *MockAlertRuleRepository) ListActive(ctx context.Context) ([]domain.AlertRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListActive", ctx)
+	ret0, _ := ret[0].([]domain.AlertRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListActive indicates an expected call of ListActive.
+func (mr *MockAlertRuleRepositoryMockRecorder) ListActive(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListActive", reflect.TypeOf((*MockAlertRuleRepository)(nil).ListActive), ctx)
+}
+
+// ListByUser mocks base method.
+func (m *MockAlertRuleRepository) ListByUser(ctx context.Context, userID int64) ([]domain.AlertRule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByUser", ctx, userID)
+	ret0, _ := ret[0].([]domain.AlertRule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByUser indicates an expected call of ListByUser.
+func (mr *MockAlertRuleRepositoryMockRecorder) ListByUser(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByUser", reflect.TypeOf((*MockAlertRuleRepository)(nil).ListByUser), ctx, userID)
+}
+
+// MarkTriggered mocks base method.
+func (m *MockAlertRuleRepository) MarkTriggered(ctx context.Context, id int64, at time.Time, deactivate bool) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkTriggered", ctx, id, at, deactivate)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkTriggered indicates an expected call of MarkTriggered.
+func (mr *MockAlertRuleRepositoryMockRecorder) MarkTriggered(ctx, id, at, deactivate any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkTriggered", reflect.TypeOf((*MockAlertRuleRepository)(nil).MarkTriggered), ctx, id, at, deactivate)
+}
+
+// Rearm mocks base method.
+func (m *MockAlertRuleRepository) Rearm(ctx context.Context, id int64, at time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Rearm", ctx, id, at)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Rearm indicates an expected call of Rearm.
+func (mr *MockAlertRuleRepositoryMockRecorder) Rearm(ctx, id, at any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Rearm", reflect.TypeOf((*MockAlertRuleRepository)(nil).Rearm), ctx, id, at)
+}
+
+// Update mocks base method.
+func (m *MockAlertRuleRepository) Update(ctx context.Context, r domain.AlertRule) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, r)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockAlertRuleRepositoryMockRecorder) Update(ctx, r any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockAlertRuleRepository)(nil).Update), ctx, r)
+}
+
+// MockAlertEventRepository is a mock of AlertEventRepository interface.
+type MockAlertEventRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockAlertEventRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockAlertEventRepositoryMockRecorder is the mock recorder for MockAlertEventRepository.
+type MockAlertEventRepositoryMockRecorder struct {
+	mock *MockAlertEventRepository
+}
+
+// NewMockAlertEventRepository creates a new mock instance.
+func NewMockAlertEventRepository(ctrl *gomock.Controller) *MockAlertEventRepository {
+	mock := &MockAlertEventRepository{ctrl: ctrl}
+	mock.recorder = &MockAlertEventRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAlertEventRepository) EXPECT() *MockAlertEventRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockAlertEventRepository) Create(ctx context.Context, e domain.AlertEvent) (domain.AlertEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, e)
+	ret0, _ := ret[0].(domain.AlertEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockAlertEventRepositoryMockRecorder) Create(ctx, e any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockAlertEventRepository)(nil).Create), ctx, e)
+}
+
+// ListByUser mocks base method.
+func (m *MockAlertEventRepository) ListByUser(ctx context.Context, userID, ruleID int64, limit int) ([]domain.AlertEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByUser", ctx, userID, ruleID, limit)
+	ret0, _ := ret[0].([]domain.AlertEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByUser indicates an expected call of ListByUser.
+func (mr *MockAlertEventRepositoryMockRecorder) ListByUser(ctx, userID, ruleID, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByUser", reflect.TypeOf((*MockAlertEventRepository)(nil).ListByUser), ctx, userID, ruleID, limit)
+}
@@ -0,0 +1,91 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: cache_port.go
+//
+// Generated by this command:
+//
+//	mockgen -source=cache_port.go -destination=../test/mocks/cache_port_mock.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	reflect "reflect"
+
+	domain "github.com/moondolphin/crypto-api/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockQuoteCacheInvalidator is a mock of QuoteCacheInvalidator interface.
+type MockQuoteCacheInvalidator struct {
+	ctrl     *gomock.Controller
+	recorder *MockQuoteCacheInvalidatorMockRecorder
+	isgomock struct{}
+}
+
+// MockQuoteCacheInvalidatorMockRecorder is the mock recorder for MockQuoteCacheInvalidator.
+type MockQuoteCacheInvalidatorMockRecorder struct {
+	mock *MockQuoteCacheInvalidator
+}
+
+// NewMockQuoteCacheInvalidator creates a new mock instance.
+func NewMockQuoteCacheInvalidator(ctrl *gomock.Controller) *MockQuoteCacheInvalidator {
+	mock := &MockQuoteCacheInvalidator{ctrl: ctrl}
+	mock.recorder = &MockQuoteCacheInvalidatorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockQuoteCacheInvalidator) EXPECT() *MockQuoteCacheInvalidatorMockRecorder {
+	return m.recorder
+}
+
+// InvalidateSymbol mocks base method.
+func (m *MockQuoteCacheInvalidator) InvalidateSymbol(symbol string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "InvalidateSymbol", symbol)
+}
+
+// InvalidateSymbol indicates an expected call of InvalidateSymbol.
+func (mr *MockQuoteCacheInvalidatorMockRecorder) InvalidateSymbol(symbol any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InvalidateSymbol", reflect.TypeOf((*MockQuoteCacheInvalidator)(nil).InvalidateSymbol), symbol)
+}
+
+// MockCacheStatsReporter is a mock of CacheStatsReporter interface.
+type MockCacheStatsReporter struct {
+	ctrl     *gomock.Controller
+	recorder *MockCacheStatsReporterMockRecorder
+	isgomock struct{}
+}
+
+// MockCacheStatsReporterMockRecorder is the mock recorder for MockCacheStatsReporter.
+type MockCacheStatsReporterMockRecorder struct {
+	mock *MockCacheStatsReporter
+}
+
+// NewMockCacheStatsReporter creates a new mock instance.
+func NewMockCacheStatsReporter(ctrl *gomock.Controller) *MockCacheStatsReporter {
+	mock := &MockCacheStatsReporter{ctrl: ctrl}
+	mock.recorder = &MockCacheStatsReporterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCacheStatsReporter) EXPECT() *MockCacheStatsReporterMockRecorder {
+	return m.recorder
+}
+
+// Stats mocks base method.
+func (m *MockCacheStatsReporter) Stats() domain.CacheStats {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Stats")
+	ret0, _ := ret[0].(domain.CacheStats)
+	return ret0
+}
+
+// Stats indicates an expected call of Stats.
+func (mr *MockCacheStatsReporterMockRecorder) Stats() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stats", reflect.TypeOf((*MockCacheStatsReporter)(nil).Stats))
+}
@@ -0,0 +1,55 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: jwks_port.go
+//
+// Generated by this command:
+//
+//	mockgen -source=jwks_port.go -destination=../test/mocks/jwks_port_mock.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	reflect "reflect"
+
+	domain "github.com/moondolphin/crypto-api/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockJWKSProvider is a mock of JWKSProvider interface.
+type MockJWKSProvider struct {
+	ctrl     *gomock.Controller
+	recorder *MockJWKSProviderMockRecorder
+	isgomock struct{}
+}
+
+// MockJWKSProviderMockRecorder is the mock recorder for MockJWKSProvider.
+type MockJWKSProviderMockRecorder struct {
+	mock *MockJWKSProvider
+}
+
+// NewMockJWKSProvider creates a new mock instance.
+func NewMockJWKSProvider(ctrl *gomock.Controller) *MockJWKSProvider {
+	mock := &MockJWKSProvider{ctrl: ctrl}
+	mock.recorder = &MockJWKSProviderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockJWKSProvider) EXPECT() *MockJWKSProviderMockRecorder {
+	return m.recorder
+}
+
+// PublicJWKs mocks base method.
+func (m *MockJWKSProvider) PublicJWKs() []domain.PublicJWK {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PublicJWKs")
+	ret0, _ := ret[0].([]domain.PublicJWK)
+	return ret0
+}
+
+// PublicJWKs indicates an expected call of PublicJWKs.
+func (mr *MockJWKSProviderMockRecorder) PublicJWKs() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublicJWKs", reflect.TypeOf((*MockJWKSProvider)(nil).PublicJWKs))
+}
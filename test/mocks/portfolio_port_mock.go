@@ -0,0 +1,269 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: portfolio_port.go
+//
+// Generated by this command:
+//
+//	mockgen -source=portfolio_port.go -destination=../test/mocks/portfolio_port_mock.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	domain "github.com/moondolphin/crypto-api/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockPortfolioRepository is a mock of PortfolioRepository interface.
+type MockPortfolioRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockPortfolioRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockPortfolioRepositoryMockRecorder is the mock recorder for MockPortfolioRepository.
+type MockPortfolioRepositoryMockRecorder struct {
+	mock *MockPortfolioRepository
+}
+
+// NewMockPortfolioRepository creates a new mock instance.
+func NewMockPortfolioRepository(ctrl *gomock.Controller) *MockPortfolioRepository {
+	mock := &MockPortfolioRepository{ctrl: ctrl}
+	mock.recorder = &MockPortfolioRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPortfolioRepository) EXPECT() *MockPortfolioRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CountByUser mocks base method.
+func (m *MockPortfolioRepository) CountByUser(ctx context.Context, userID int64) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountByUser", ctx, userID)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountByUser indicates an expected call of CountByUser.
+func (mr *MockPortfolioRepositoryMockRecorder) CountByUser(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountByUser", reflect.TypeOf((*MockPortfolioRepository)(nil).CountByUser), ctx, userID)
+}
+
+// Create mocks base method.
+func (m *MockPortfolioRepository) Create(ctx context.Context, p domain.Portfolio) (domain.Portfolio, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, p)
+	ret0, _ := ret[0].(domain.Portfolio)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockPortfolioRepositoryMockRecorder) Create(ctx, p any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockPortfolioRepository)(nil).Create), ctx, p)
+}
+
+// Delete mocks base method.
+func (m *MockPortfolioRepository) Delete(ctx context.Context, userID, id int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, userID, id)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockPortfolioRepositoryMockRecorder) Delete(ctx, userID, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockPortfolioRepository)(nil).Delete), ctx, userID, id)
+}
+
+// FindByUser mocks base method.
+func (m *MockPortfolioRepository) FindByUser(ctx context.Context, userID, id int64) (*domain.Portfolio, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByUser", ctx, userID, id)
+	ret0, _ := ret[0].(*domain.Portfolio)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByUser indicates an expected call of FindByUser.
+func (mr *MockPortfolioRepositoryMockRecorder) FindByUser(ctx, userID, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByUser", reflect.TypeOf((*MockPortfolioRepository)(nil).FindByUser), ctx, userID, id)
+}
+
+// ListByUser mocks base method.
+func (m *MockPortfolioRepository) ListByUser(ctx context.Context, userID int64) ([]domain.Portfolio, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByUser", ctx, userID)
+	ret0, _ := ret[0].([]domain.Portfolio)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByUser indicates an expected call of ListByUser.
+func (mr *MockPortfolioRepositoryMockRecorder) ListByUser(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByUser", reflect.TypeOf((*MockPortfolioRepository)(nil).ListByUser), ctx, userID)
+}
+
+// Update mocks base method.
+func (m *MockPortfolioRepository) Update(ctx context.Context, p domain.Portfolio) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, p)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockPortfolioRepositoryMockRecorder) Update(ctx, p any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockPortfolioRepository)(nil).Update), ctx, p)
+}
+
+// MockHoldingRepository is a mock of HoldingRepository interface.
+type MockHoldingRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockHoldingRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockHoldingRepositoryMockRecorder is the mock recorder for MockHoldingRepository.
+type MockHoldingRepositoryMockRecorder struct {
+	mock *MockHoldingRepository
+}
+
+// NewMockHoldingRepository creates a new mock instance.
+func NewMockHoldingRepository(ctrl *gomock.Controller) *MockHoldingRepository {
+	mock := &MockHoldingRepository{ctrl: ctrl}
+	mock.recorder = &MockHoldingRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockHoldingRepository) EXPECT() *MockHoldingRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockHoldingRepository) Delete(ctx context.Context, portfolioID, coinID int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, portfolioID, coinID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockHoldingRepositoryMockRecorder) Delete(ctx, portfolioID, coinID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockHoldingRepository)(nil).Delete), ctx, portfolioID, coinID)
+}
+
+// ListByPortfolio mocks base method.
+func (m *MockHoldingRepository) ListByPortfolio(ctx context.Context, portfolioID int64) ([]domain.Holding, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByPortfolio", ctx, portfolioID)
+	ret0, _ := ret[0].([]domain.Holding)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByPortfolio indicates an expected call of ListByPortfolio.
+func (mr *MockHoldingRepositoryMockRecorder) ListByPortfolio(ctx, portfolioID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByPortfolio", reflect.TypeOf((*MockHoldingRepository)(nil).ListByPortfolio), ctx, portfolioID)
+}
+
+// Upsert mocks base method.
+func (m *MockHoldingRepository) Upsert(ctx context.Context, h domain.Holding) (domain.Holding, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Upsert", ctx, h)
+	ret0, _ := ret[0].(domain.Holding)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Upsert indicates an expected call of Upsert.
+func (mr *MockHoldingRepositoryMockRecorder) Upsert(ctx, h any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Upsert", reflect.TypeOf((*MockHoldingRepository)(nil).Upsert), ctx, h)
+}
+
+// MockPortfolioSnapshotRepository is a mock of PortfolioSnapshotRepository interface.
+type MockPortfolioSnapshotRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockPortfolioSnapshotRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockPortfolioSnapshotRepositoryMockRecorder is the mock recorder for MockPortfolioSnapshotRepository.
+type MockPortfolioSnapshotRepositoryMockRecorder struct {
+	mock *MockPortfolioSnapshotRepository
+}
+
+// NewMockPortfolioSnapshotRepository creates a new mock instance.
+func NewMockPortfolioSnapshotRepository(ctrl *gomock.Controller) *MockPortfolioSnapshotRepository {
+	mock := &MockPortfolioSnapshotRepository{ctrl: ctrl}
+	mock.recorder = &MockPortfolioSnapshotRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPortfolioSnapshotRepository) EXPECT() *MockPortfolioSnapshotRepositoryMockRecorder {
+	return m.recorder
+}
+
+// DeleteFrom mocks base method.
+func (m *MockPortfolioSnapshotRepository) DeleteFrom(ctx context.Context, portfolioID int64, from time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteFrom", ctx, portfolioID, from)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteFrom indicates an expected call of DeleteFrom.
+func (mr *MockPortfolioSnapshotRepositoryMockRecorder) DeleteFrom(ctx, portfolioID, from any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteFrom", reflect.TypeOf((*MockPortfolioSnapshotRepository)(nil).DeleteFrom), ctx, portfolioID, from)
+}
+
+// ListRange mocks base method.
+func (m *MockPortfolioSnapshotRepository) ListRange(ctx context.Context, portfolioID int64, currency string, from, to time.Time) ([]domain.PortfolioSnapshot, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRange", ctx, portfolioID, currency, from, to)
+	ret0, _ := ret[0].([]domain.PortfolioSnapshot)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRange indicates an expected call of ListRange.
+func (mr *MockPortfolioSnapshotRepositoryMockRecorder) ListRange(ctx, portfolioID, currency, from, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRange", reflect.TypeOf((*MockPortfolioSnapshotRepository)(nil).ListRange), ctx, portfolioID, currency, from, to)
+}
+
+// Upsert mocks base method.
+func (m *MockPortfolioSnapshotRepository) Upsert(ctx context.Context, s domain.PortfolioSnapshot) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Upsert", ctx, s)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Upsert indicates an expected call of Upsert.
+func (mr *MockPortfolioSnapshotRepositoryMockRecorder) Upsert(ctx, s any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Upsert", reflect.TypeOf((*MockPortfolioSnapshotRepository)(nil).Upsert), ctx, s)
+}
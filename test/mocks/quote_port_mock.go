@@ -57,11 +57,12 @@ func (mr *MockQuoteRepositoryMockRecorder) GetLatest(ctx, symbol, provider, curr
 }
 
 // Insert mocks base method.
-func (m *MockQuoteRepository) Insert(ctx context.Context, q domain.Quote) error {
+func (m *MockQuoteRepository) Insert(ctx context.Context, q domain.Quote) (bool, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Insert", ctx, q)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // Insert indicates an expected call of Insert.
@@ -85,6 +86,21 @@ func (mr *MockQuoteRepositoryMockRecorder) ListAvailableFilters(ctx, f any) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAvailableFilters", reflect.TypeOf((*MockQuoteRepository)(nil).ListAvailableFilters), ctx, f)
 }
 
+// ListDailyCloses mocks base method.
+func (m *MockQuoteRepository) ListDailyCloses(ctx context.Context, f domain.DailyCloseFilter) ([]domain.DailyClose, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDailyCloses", ctx, f)
+	ret0, _ := ret[0].([]domain.DailyClose)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDailyCloses indicates an expected call of ListDailyCloses.
+func (mr *MockQuoteRepositoryMockRecorder) ListDailyCloses(ctx, f any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDailyCloses", reflect.TypeOf((*MockQuoteRepository)(nil).ListDailyCloses), ctx, f)
+}
+
 // ListFilter mocks base method.
 func (m *MockQuoteRepository) ListFilter(ctx context.Context, f domain.QuoteFilter) ([]domain.Quote, int, error) {
 	m.ctrl.T.Helper()
@@ -100,3 +116,18 @@ func (mr *MockQuoteRepositoryMockRecorder) ListFilter(ctx, f any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListFilter", reflect.TypeOf((*MockQuoteRepository)(nil).ListFilter), ctx, f)
 }
+
+// ListTickers mocks base method.
+func (m *MockQuoteRepository) ListTickers(ctx context.Context, f domain.QuoteTickerFilter) ([]domain.QuoteTicker, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTickers", ctx, f)
+	ret0, _ := ret[0].([]domain.QuoteTicker)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTickers indicates an expected call of ListTickers.
+func (mr *MockQuoteRepositoryMockRecorder) ListTickers(ctx, f any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTickers", reflect.TypeOf((*MockQuoteRepository)(nil).ListTickers), ctx, f)
+}
@@ -0,0 +1,100 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: token_revocation_port.go
+//
+// Generated by this command:
+//
+//	mockgen -source=token_revocation_port.go -destination=../test/mocks/token_revocation_port_mock.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockTokenRevocationStore is a mock of TokenRevocationStore interface.
+type MockTokenRevocationStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockTokenRevocationStoreMockRecorder
+	isgomock struct{}
+}
+
+// MockTokenRevocationStoreMockRecorder is the mock recorder for MockTokenRevocationStore.
+type MockTokenRevocationStoreMockRecorder struct {
+	mock *MockTokenRevocationStore
+}
+
+// NewMockTokenRevocationStore creates a new mock instance.
+func NewMockTokenRevocationStore(ctrl *gomock.Controller) *MockTokenRevocationStore {
+	mock := &MockTokenRevocationStore{ctrl: ctrl}
+	mock.recorder = &MockTokenRevocationStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTokenRevocationStore) EXPECT() *MockTokenRevocationStoreMockRecorder {
+	return m.recorder
+}
+
+// DeleteExpired mocks base method.
+func (m *MockTokenRevocationStore) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpired", ctx, now)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteExpired indicates an expected call of DeleteExpired.
+func (mr *MockTokenRevocationStoreMockRecorder) DeleteExpired(ctx, now any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpired", reflect.TypeOf((*MockTokenRevocationStore)(nil).DeleteExpired), ctx, now)
+}
+
+// IsRevoked mocks base method.
+func (m *MockTokenRevocationStore) IsRevoked(ctx context.Context, jti string, userID int64, issuedAt time.Time) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsRevoked", ctx, jti, userID, issuedAt)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsRevoked indicates an expected call of IsRevoked.
+func (mr *MockTokenRevocationStoreMockRecorder) IsRevoked(ctx, jti, userID, issuedAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsRevoked", reflect.TypeOf((*MockTokenRevocationStore)(nil).IsRevoked), ctx, jti, userID, issuedAt)
+}
+
+// RevokeJTI mocks base method.
+func (m *MockTokenRevocationStore) RevokeJTI(ctx context.Context, jti string, expiresAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeJTI", ctx, jti, expiresAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeJTI indicates an expected call of RevokeJTI.
+func (mr *MockTokenRevocationStoreMockRecorder) RevokeJTI(ctx, jti, expiresAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeJTI", reflect.TypeOf((*MockTokenRevocationStore)(nil).RevokeJTI), ctx, jti, expiresAt)
+}
+
+// RevokeUser mocks base method.
+func (m *MockTokenRevocationStore) RevokeUser(ctx context.Context, userID int64, issuedBefore, expiresAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeUser", ctx, userID, issuedBefore, expiresAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeUser indicates an expected call of RevokeUser.
+func (mr *MockTokenRevocationStoreMockRecorder) RevokeUser(ctx, userID, issuedBefore, expiresAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeUser", reflect.TypeOf((*MockTokenRevocationStore)(nil).RevokeUser), ctx, userID, issuedBefore, expiresAt)
+}
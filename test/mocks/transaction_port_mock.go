@@ -0,0 +1,117 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: transaction_port.go
+//
+// Generated by this command:
+//
+//	mockgen -source=transaction_port.go -destination=../test/mocks/transaction_port_mock.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	domain "github.com/moondolphin/crypto-api/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockTransactionRepository is a mock of TransactionRepository interface.
+type MockTransactionRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockTransactionRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockTransactionRepositoryMockRecorder is the mock recorder for MockTransactionRepository.
+type MockTransactionRepositoryMockRecorder struct {
+	mock *MockTransactionRepository
+}
+
+// NewMockTransactionRepository creates a new mock instance.
+func NewMockTransactionRepository(ctrl *gomock.Controller) *MockTransactionRepository {
+	mock := &MockTransactionRepository{ctrl: ctrl}
+	mock.recorder = &MockTransactionRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTransactionRepository) EXPECT() *MockTransactionRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockTransactionRepository) Create(ctx context.Context, t domain.Transaction) (domain.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, t)
+	ret0, _ := ret[0].(domain.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockTransactionRepositoryMockRecorder) Create(ctx, t any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockTransactionRepository)(nil).Create), ctx, t)
+}
+
+// CreateChecked mocks base method.
+func (m *MockTransactionRepository) CreateChecked(ctx context.Context, t domain.Transaction, check func([]domain.Transaction) error) (domain.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateChecked", ctx, t, check)
+	ret0, _ := ret[0].(domain.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateChecked indicates an expected call of CreateChecked.
+func (mr *MockTransactionRepositoryMockRecorder) CreateChecked(ctx, t, check any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateChecked", reflect.TypeOf((*MockTransactionRepository)(nil).CreateChecked), ctx, t, check)
+}
+
+// Delete mocks base method.
+func (m *MockTransactionRepository) Delete(ctx context.Context, portfolioID, id int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, portfolioID, id)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockTransactionRepositoryMockRecorder) Delete(ctx, portfolioID, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockTransactionRepository)(nil).Delete), ctx, portfolioID, id)
+}
+
+// FindByPortfolio mocks base method.
+func (m *MockTransactionRepository) FindByPortfolio(ctx context.Context, portfolioID, id int64) (*domain.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByPortfolio", ctx, portfolioID, id)
+	ret0, _ := ret[0].(*domain.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByPortfolio indicates an expected call of FindByPortfolio.
+func (mr *MockTransactionRepositoryMockRecorder) FindByPortfolio(ctx, portfolioID, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByPortfolio", reflect.TypeOf((*MockTransactionRepository)(nil).FindByPortfolio), ctx, portfolioID, id)
+}
+
+// ListByPortfolio mocks base method.
+func (m *MockTransactionRepository) ListByPortfolio(ctx context.Context, portfolioID int64, f domain.TransactionFilter) ([]domain.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByPortfolio", ctx, portfolioID, f)
+	ret0, _ := ret[0].([]domain.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByPortfolio indicates an expected call of ListByPortfolio.
+func (mr *MockTransactionRepositoryMockRecorder) ListByPortfolio(ctx, portfolioID, f any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByPortfolio", reflect.TypeOf((*MockTransactionRepository)(nil).ListByPortfolio), ctx, portfolioID, f)
+}
@@ -0,0 +1,246 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: watchlist_port.go
+//
+// Generated by this command:
+//
+//	mockgen -source=watchlist_port.go -destination=../test/mocks/watchlist_port_mock.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	domain "github.com/moondolphin/crypto-api/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockWatchlistRepository is a mock of WatchlistRepository interface.
+type MockWatchlistRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockWatchlistRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockWatchlistRepositoryMockRecorder is the mock recorder for MockWatchlistRepository.
+type MockWatchlistRepositoryMockRecorder struct {
+	mock *MockWatchlistRepository
+}
+
+// NewMockWatchlistRepository creates a new mock instance.
+func NewMockWatchlistRepository(ctrl *gomock.Controller) *MockWatchlistRepository {
+	mock := &MockWatchlistRepository{ctrl: ctrl}
+	mock.recorder = &MockWatchlistRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWatchlistRepository) EXPECT() *MockWatchlistRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CountByUser mocks base method.
+func (m *MockWatchlistRepository) CountByUser(ctx context.Context, userID int64) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountByUser", ctx, userID)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountByUser indicates an expected call of CountByUser.
+func (mr *MockWatchlistRepositoryMockRecorder) CountByUser(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountByUser", reflect.TypeOf((*MockWatchlistRepository)(nil).CountByUser), ctx, userID)
+}
+
+// Create mocks base method.
+func (m *MockWatchlistRepository) Create(ctx context.Context, w domain.Watchlist) (domain.Watchlist, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, w)
+	ret0, _ := ret[0].(domain.Watchlist)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockWatchlistRepositoryMockRecorder) Create(ctx, w any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockWatchlistRepository)(nil).Create), ctx, w)
+}
+
+// Delete mocks base method.
+func (m *MockWatchlistRepository) Delete(ctx context.Context, userID, id int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, userID, id)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockWatchlistRepositoryMockRecorder) Delete(ctx, userID, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockWatchlistRepository)(nil).Delete), ctx, userID, id)
+}
+
+// EnsureDefault mocks base method.
+func (m *MockWatchlistRepository) EnsureDefault(ctx context.Context, w domain.Watchlist) (domain.Watchlist, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnsureDefault", ctx, w)
+	ret0, _ := ret[0].(domain.Watchlist)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EnsureDefault indicates an expected call of EnsureDefault.
+func (mr *MockWatchlistRepositoryMockRecorder) EnsureDefault(ctx, w any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnsureDefault", reflect.TypeOf((*MockWatchlistRepository)(nil).EnsureDefault), ctx, w)
+}
+
+// FindByUser mocks base method.
+func (m *MockWatchlistRepository) FindByUser(ctx context.Context, userID, id int64) (*domain.Watchlist, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByUser", ctx, userID, id)
+	ret0, _ := ret[0].(*domain.Watchlist)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByUser indicates an expected call of FindByUser.
+func (mr *MockWatchlistRepositoryMockRecorder) FindByUser(ctx, userID, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByUser", reflect.TypeOf((*MockWatchlistRepository)(nil).FindByUser), ctx, userID, id)
+}
+
+// ListByUser mocks base method.
+func (m *MockWatchlistRepository) ListByUser(ctx context.Context, userID int64) ([]domain.Watchlist, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByUser", ctx, userID)
+	ret0, _ := ret[0].([]domain.Watchlist)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByUser indicates an expected call of ListByUser.
+func (mr *MockWatchlistRepositoryMockRecorder) ListByUser(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByUser", reflect.TypeOf((*MockWatchlistRepository)(nil).ListByUser), ctx, userID)
+}
+
+// Update mocks base method.
+func (m *MockWatchlistRepository) Update(ctx context.Context, w domain.Watchlist) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, w)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockWatchlistRepositoryMockRecorder) Update(ctx, w any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockWatchlistRepository)(nil).Update), ctx, w)
+}
+
+// MockWatchlistEntryRepository is a mock of WatchlistEntryRepository interface.
+type MockWatchlistEntryRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockWatchlistEntryRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockWatchlistEntryRepositoryMockRecorder is the mock recorder for MockWatchlistEntryRepository.
+type MockWatchlistEntryRepositoryMockRecorder struct {
+	mock *MockWatchlistEntryRepository
+}
+
+// NewMockWatchlistEntryRepository creates a new mock instance.
+func NewMockWatchlistEntryRepository(ctrl *gomock.Controller) *MockWatchlistEntryRepository {
+	mock := &MockWatchlistEntryRepository{ctrl: ctrl}
+	mock.recorder = &MockWatchlistEntryRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWatchlistEntryRepository) EXPECT() *MockWatchlistEntryRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Add mocks base method.
+func (m *MockWatchlistEntryRepository) Add(ctx context.Context, e domain.WatchlistEntry) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Add", ctx, e)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Add indicates an expected call of Add.
+func (mr *MockWatchlistEntryRepositoryMockRecorder) Add(ctx, e any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Add", reflect.TypeOf((*MockWatchlistEntryRepository)(nil).Add), ctx, e)
+}
+
+// ListByWatchlist mocks base method.
+func (m *MockWatchlistEntryRepository) ListByWatchlist(ctx context.Context, watchlistID int64) ([]domain.WatchlistEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByWatchlist", ctx, watchlistID)
+	ret0, _ := ret[0].([]domain.WatchlistEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByWatchlist indicates an expected call of ListByWatchlist.
+func (mr *MockWatchlistEntryRepositoryMockRecorder) ListByWatchlist(ctx, watchlistID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByWatchlist", reflect.TypeOf((*MockWatchlistEntryRepository)(nil).ListByWatchlist), ctx, watchlistID)
+}
+
+// Remove mocks base method.
+func (m *MockWatchlistEntryRepository) Remove(ctx context.Context, watchlistID, coinID int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Remove", ctx, watchlistID, coinID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Remove indicates an expected call of Remove.
+func (mr *MockWatchlistEntryRepositoryMockRecorder) Remove(ctx, watchlistID, coinID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Remove", reflect.TypeOf((*MockWatchlistEntryRepository)(nil).Remove), ctx, watchlistID, coinID)
+}
+
+// Reorder mocks base method.
+func (m *MockWatchlistEntryRepository) Reorder(ctx context.Context, watchlistID int64, coinIDs []int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reorder", ctx, watchlistID, coinIDs)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Reorder indicates an expected call of Reorder.
+func (mr *MockWatchlistEntryRepositoryMockRecorder) Reorder(ctx, watchlistID, coinIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reorder", reflect.TypeOf((*MockWatchlistEntryRepository)(nil).Reorder), ctx, watchlistID, coinIDs)
+}
+
+// UpdateNote mocks base method.
+func (m *MockWatchlistEntryRepository) UpdateNote(ctx context.Context, watchlistID, coinID int64, note string, at time.Time) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateNote", ctx, watchlistID, coinID, note, at)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateNote indicates an expected call of UpdateNote.
+func (mr *MockWatchlistEntryRepositoryMockRecorder) UpdateNote(ctx, watchlistID, coinID, note, at any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateNote", reflect.TypeOf((*MockWatchlistEntryRepository)(nil).UpdateNote), ctx, watchlistID, coinID, note, at)
+}